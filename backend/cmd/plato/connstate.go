@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	idleTimeoutEnv    = "PLATO_HTTP_IDLE_TIMEOUT"
+	logConnectionsEnv = "PLATO_LOG_CONNECTIONS"
+
+	defaultIdleTimeout = 60 * time.Second
+)
+
+type serverTuning struct {
+	idleTimeout    time.Duration
+	logConnections bool
+}
+
+func loadServerTuning() (serverTuning, error) {
+	tuning := serverTuning{idleTimeout: defaultIdleTimeout}
+
+	rawIdleTimeout := strings.TrimSpace(os.Getenv(idleTimeoutEnv))
+	if rawIdleTimeout != "" {
+		idleTimeout, err := time.ParseDuration(rawIdleTimeout)
+		if err != nil {
+			return serverTuning{}, fmt.Errorf("%s must be a duration: %w", idleTimeoutEnv, err)
+		}
+		if idleTimeout <= 0 {
+			return serverTuning{}, fmt.Errorf("%s must be positive", idleTimeoutEnv)
+		}
+		tuning.idleTimeout = idleTimeout
+	}
+
+	rawLogConnections := strings.TrimSpace(os.Getenv(logConnectionsEnv))
+	if rawLogConnections != "" {
+		logConnections, err := strconv.ParseBool(rawLogConnections)
+		if err != nil {
+			return serverTuning{}, fmt.Errorf("%s must be a boolean value: %w", logConnectionsEnv, err)
+		}
+		tuning.logConnections = logConnections
+	}
+
+	return tuning, nil
+}
+
+// connStateCounters holds cumulative connection transition counts.
+type connStateCounters struct {
+	New      int64
+	Active   int64
+	Idle     int64
+	Hijacked int64
+	Closed   int64
+}
+
+// connStateTracker counts http.Server connection state transitions to help
+// diagnose keep-alive churn behind load balancers.
+type connStateTracker struct {
+	newCount      atomic.Int64
+	activeCount   atomic.Int64
+	idleCount     atomic.Int64
+	hijackedCount atomic.Int64
+	closedCount   atomic.Int64
+	logger        logFunc
+}
+
+func newConnStateTracker(logger logFunc) *connStateTracker {
+	return &connStateTracker{logger: logger}
+}
+
+func (t *connStateTracker) track(conn net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		t.newCount.Add(1)
+	case http.StateActive:
+		t.activeCount.Add(1)
+	case http.StateIdle:
+		t.idleCount.Add(1)
+	case http.StateHijacked:
+		t.hijackedCount.Add(1)
+	case http.StateClosed:
+		t.closedCount.Add(1)
+	default:
+		return
+	}
+
+	counters := t.snapshot()
+	logWith(
+		t.logger,
+		slog.LevelInfo,
+		"connection %s %s (new=%d active=%d idle=%d hijacked=%d closed=%d open=%d)",
+		remoteAddr(conn),
+		state,
+		counters.New,
+		counters.Active,
+		counters.Idle,
+		counters.Hijacked,
+		counters.Closed,
+		counters.New-counters.Closed-counters.Hijacked,
+	)
+}
+
+func (t *connStateTracker) snapshot() connStateCounters {
+	return connStateCounters{
+		New:      t.newCount.Load(),
+		Active:   t.activeCount.Load(),
+		Idle:     t.idleCount.Load(),
+		Hijacked: t.hijackedCount.Load(),
+		Closed:   t.closedCount.Load(),
+	}
+}
+
+func remoteAddr(conn net.Conn) string {
+	if conn == nil || conn.RemoteAddr() == nil {
+		return "unknown"
+	}
+	return conn.RemoteAddr().String()
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLoadServerTuning verifies the load server tuning scenario.
+func TestLoadServerTuning(t *testing.T) {
+	t.Setenv(idleTimeoutEnv, "")
+	t.Setenv(logConnectionsEnv, "")
+	tuning, err := loadServerTuning()
+	if err != nil {
+		t.Fatalf("load default tuning: %v", err)
+	}
+	if tuning.idleTimeout != defaultIdleTimeout || tuning.logConnections {
+		t.Fatalf("unexpected default tuning %+v", tuning)
+	}
+
+	t.Setenv(idleTimeoutEnv, "3m")
+	t.Setenv(logConnectionsEnv, "true")
+	tuning, err = loadServerTuning()
+	if err != nil {
+		t.Fatalf("load configured tuning: %v", err)
+	}
+	if tuning.idleTimeout != 3*time.Minute || !tuning.logConnections {
+		t.Fatalf("unexpected configured tuning %+v", tuning)
+	}
+
+	for _, testCase := range []struct {
+		key   string
+		value string
+	}{
+		{key: idleTimeoutEnv, value: "soon"},
+		{key: idleTimeoutEnv, value: "-1s"},
+		{key: logConnectionsEnv, value: "sometimes"},
+	} {
+		t.Setenv(idleTimeoutEnv, "")
+		t.Setenv(logConnectionsEnv, "")
+		t.Setenv(testCase.key, testCase.value)
+		if _, err = loadServerTuning(); err == nil || !strings.Contains(err.Error(), testCase.key) {
+			t.Fatalf("expected %s=%q to fail, got %v", testCase.key, testCase.value, err)
+		}
+	}
+}
+
+// TestRunAppliesIdleTimeoutAndConnStateHook verifies the run applies idle timeout and conn state hook scenario.
+func TestRunAppliesIdleTimeoutAndConnStateHook(t *testing.T) {
+	t.Setenv(idleTimeoutEnv, "90s")
+	t.Setenv(logConnectionsEnv, "true")
+
+	if err := run(testEphemeralAddr, http.NewServeMux(), func(server *http.Server, _ net.Listener) error {
+		if server.IdleTimeout != 90*time.Second {
+			t.Fatalf("expected idle timeout 90s, got %v", server.IdleTimeout)
+		}
+		if server.ConnState == nil {
+			t.Fatal("expected connection state hook when connection logging is enabled")
+		}
+		return nil
+	}, nil); err != nil {
+		t.Fatalf("expected run success, got %v", err)
+	}
+
+	t.Setenv(idleTimeoutEnv, "later")
+	if err := run(testEphemeralAddr, http.NewServeMux(), func(_ *http.Server, _ net.Listener) error {
+		return nil
+	}, nil); err == nil {
+		t.Fatal("expected invalid idle timeout to fail run")
+	}
+}
+
+// TestConnStateTrackerCountsRequestLifecycle verifies the conn state tracker counts request lifecycle scenario.
+func TestConnStateTrackerCountsRequestLifecycle(t *testing.T) {
+	var logMutex sync.Mutex
+	var logMessages []string
+	tracker := newConnStateTracker(func(_ slog.Level, format string, args ...any) {
+		logMutex.Lock()
+		defer logMutex.Unlock()
+		logMessages = append(logMessages, fmt.Sprintf(format, args...))
+	})
+
+	closed := make(chan struct{}, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		tracker.track(conn, state)
+		if state == http.StateClosed {
+			closed <- struct{}{}
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 2 * time.Second
+	response, err := doGetRequest(client, server.URL)
+	if err != nil {
+		t.Fatalf("request test server: %v", err)
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+	client.CloseIdleConnections()
+
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for connection close")
+	}
+
+	expected := connStateCounters{New: 1, Active: 1, Idle: 1, Closed: 1}
+	if got := tracker.snapshot(); got != expected {
+		t.Fatalf("expected counters %+v, got %+v", expected, got)
+	}
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	if len(logMessages) != 4 {
+		t.Fatalf("expected one log entry per transition, got %v", logMessages)
+	}
+	if !logsContain(logMessages, "closed (new=1 active=1 idle=1 hijacked=0 closed=1 open=0)") {
+		t.Fatalf("expected closed transition log with counters, got %v", logMessages)
+	}
+}
+
+// TestRemoteAddr verifies the remote addr scenario.
+func TestRemoteAddr(t *testing.T) {
+	if got := remoteAddr(nil); got != "unknown" {
+		t.Fatalf("expected unknown for nil connection, got %q", got)
+	}
+}
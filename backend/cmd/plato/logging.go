@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	logLevelEnv  = "PLATO_LOG_LEVEL"
+	logFormatEnv = "PLATO_LOG_FORMAT"
+
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// logFunc writes one formatted message at the given level. Startup, shutdown,
+// and connection logging take it as a parameter so tests can capture lines.
+type logFunc func(level slog.Level, format string, args ...any)
+
+// newLogger builds the process logger from PLATO_LOG_LEVEL and
+// PLATO_LOG_FORMAT. Unset variables keep INFO level text output.
+func newLogger(output io.Writer) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	if rawLevel := strings.TrimSpace(os.Getenv(logLevelEnv)); rawLevel != "" {
+		if err := level.UnmarshalText([]byte(rawLevel)); err != nil {
+			return nil, fmt.Errorf("%s must be one of debug, info, warn, or error: %w", logLevelEnv, err)
+		}
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	switch format := strings.ToLower(strings.TrimSpace(os.Getenv(logFormatEnv))); format {
+	case "", logFormatText:
+		return slog.New(slog.NewTextHandler(output, options)), nil
+	case logFormatJSON:
+		return slog.New(slog.NewJSONHandler(output, options)), nil
+	default:
+		return nil, fmt.Errorf("%s must be %s or %s, got %q", logFormatEnv, logFormatText, logFormatJSON, format)
+	}
+}
+
+func logFuncFor(logger *slog.Logger) logFunc {
+	return func(level slog.Level, format string, args ...any) {
+		logger.Log(context.Background(), level, fmt.Sprintf(format, args...))
+	}
+}
+
+func logWith(logger logFunc, level slog.Level, format string, args ...any) {
+	if logger != nil {
+		logger(level, format, args...)
+	}
+}
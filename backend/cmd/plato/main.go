@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -18,7 +20,7 @@ var (
 	runServer          = run
 	makeRouter         = httpapi.NewRouter
 	loadRuntimeConfig  = httpapi.LoadRuntimeConfigFromEnv
-	logPrintf          = log.Printf
+	logOutput          = io.Writer(os.Stderr)
 	exitProcess        = os.Exit
 	signalNotify       = signal.Notify
 	signalStop         = signal.Stop
@@ -31,41 +33,49 @@ const (
 )
 
 func main() {
+	logger, err := newLogger(logOutput)
+	if err != nil {
+		slog.New(slog.NewTextHandler(logOutput, nil)).Error(fmt.Sprintf("failed to configure logging: %v", err))
+		exitProcess(1)
+		return
+	}
+	logf := logFuncFor(logger)
+
 	runtimeConfig, err := loadRuntimeConfig()
 	if err != nil {
-		logPrintf("failed to load runtime config: %v", err)
+		logf(slog.LevelError, "failed to load runtime config: %v", err)
 		exitProcess(1)
 		return
 	}
 
-	logStartupWarnings(runtimeConfig, logPrintf)
+	logStartupWarnings(runtimeConfig, logf)
 	addr := getenv(listenAddrEnv, httpapi.DefaultListenAddr(runtimeConfig.Mode))
 
 	router, err := makeRouter(runtimeConfig)
 	if err != nil {
-		logPrintf("failed to initialize router: %v", err)
+		logf(slog.LevelError, "failed to initialize router: %v", err)
 		exitProcess(1)
 		return
 	}
 
 	err = runServer(addr, router, func(server *http.Server, listener net.Listener) error {
 		return server.Serve(listener)
-	}, logPrintf)
+	}, logf)
 	if err != nil {
-		logPrintf("server failed: %v", err)
+		logf(slog.LevelError, "server failed: %v", err)
 		exitProcess(1)
 		return
 	}
 }
 
-func logStartupWarnings(runtimeConfig httpapi.RuntimeConfig, logger func(string, ...any)) {
+func logStartupWarnings(runtimeConfig httpapi.RuntimeConfig, logger logFunc) {
 	if logger == nil || !runtimeConfig.Mode.IsDevelopment() {
 		return
 	}
 
-	logger("WARNING: backend is running in development mode")
-	logger("WARNING: development mode enables header-based dev auth and permissive CORS defaults")
-	logger("WARNING: do not expose development mode to untrusted networks")
+	logger(slog.LevelWarn, "backend is running in development mode")
+	logger(slog.LevelWarn, "development mode enables header-based dev auth and permissive CORS defaults")
+	logger(slog.LevelWarn, "do not expose development mode to untrusted networks")
 }
 
 func getenv(key, fallback string) string {
@@ -77,12 +87,20 @@ func getenv(key, fallback string) string {
 	return value
 }
 
-func run(addr string, handler http.Handler, start func(*http.Server, net.Listener) error, logger func(string, ...any)) error {
+func run(addr string, handler http.Handler, start func(*http.Server, net.Listener) error, logger logFunc) error {
 	if start == nil {
 		return errors.New("start function is required")
 	}
 
-	server := newHTTPServer(addr, handler)
+	tuning, err := loadServerTuning()
+	if err != nil {
+		return err
+	}
+
+	server := newHTTPServer(addr, handler, tuning)
+	if tuning.logConnections {
+		server.ConnState = newConnStateTracker(logger).track
+	}
 
 	listener, err := (&net.ListenConfig{}).Listen(context.Background(), "tcp", addr)
 	if err != nil {
@@ -92,7 +110,7 @@ func run(addr string, handler http.Handler, start func(*http.Server, net.Listene
 		_ = listener.Close()
 	}()
 
-	logWith(logger, "plato backend listening on %s", addr)
+	logWith(logger, slog.LevelInfo, "plato backend listening on %s", addr)
 
 	serveErr := startServerAsync(server, listener, start)
 
@@ -115,7 +133,7 @@ func run(addr string, handler http.Handler, start func(*http.Server, net.Listene
 	return waitForServeDrain(ctx, serveErr, logger)
 }
 
-func newHTTPServer(addr string, handler http.Handler) *http.Server {
+func newHTTPServer(addr string, handler http.Handler, tuning serverTuning) *http.Server {
 	return &http.Server{
 		Addr:    addr,
 		Handler: handler,
@@ -126,13 +144,7 @@ func newHTTPServer(addr string, handler http.Handler) *http.Server {
 		// Limits time to write responses to prevent slow clients from tying up workers.
 		WriteTimeout: 15 * time.Second,
 		// Limits idle keep-alive duration to prevent connection exhaustion.
-		IdleTimeout: 60 * time.Second,
-	}
-}
-
-func logWith(logger func(string, ...any), format string, args ...any) {
-	if logger != nil {
-		logger(format, args...)
+		IdleTimeout: tuning.idleTimeout,
 	}
 }
 
@@ -148,38 +160,38 @@ func startServerAsync(server *http.Server, listener net.Listener, start func(*ht
 	return serveErr
 }
 
-func waitForServeResultOrShutdownSignal(serveErr <-chan error, quit <-chan os.Signal, logger func(string, ...any)) (bool, error) {
+func waitForServeResultOrShutdownSignal(serveErr <-chan error, quit <-chan os.Signal, logger logFunc) (bool, error) {
 	select {
 	case err := <-serveErr:
 		return false, err
 	case shutdownSignal := <-quit:
-		logWith(logger, "shutdown signal received (%s), draining in-flight requests", shutdownSignal)
+		logWith(logger, slog.LevelInfo, "shutdown signal received (%s), draining in-flight requests", shutdownSignal)
 		return true, nil
 	}
 }
 
-func logServerShutdown(err error, logger func(string, ...any)) {
+func logServerShutdown(err error, logger logFunc) {
 	if err != nil {
-		logWith(logger, "server forced to shutdown: %v", err)
+		logWith(logger, slog.LevelError, "server forced to shutdown: %v", err)
 		return
 	}
-	logWith(logger, "server exited gracefully")
+	logWith(logger, slog.LevelInfo, "server exited gracefully")
 }
 
-func logResourceCleanup(err error, logger func(string, ...any)) {
+func logResourceCleanup(err error, logger logFunc) {
 	if err != nil {
-		logWith(logger, "resource cleanup failed: %v", err)
+		logWith(logger, slog.LevelError, "resource cleanup failed: %v", err)
 		return
 	}
-	logWith(logger, "resource cleanup completed")
+	logWith(logger, slog.LevelInfo, "resource cleanup completed")
 }
 
-func waitForServeDrain(ctx context.Context, serveErr <-chan error, logger func(string, ...any)) error {
+func waitForServeDrain(ctx context.Context, serveErr <-chan error, logger logFunc) error {
 	select {
 	case err := <-serveErr:
 		return err
 	case <-ctx.Done():
-		logWith(logger, "timed out waiting for server goroutine to exit: %v", ctx.Err())
+		logWith(logger, slog.LevelWarn, "timed out waiting for server goroutine to exit: %v", ctx.Err())
 		return nil
 	}
 }
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -69,7 +72,7 @@ func assertRunInitializesConfiguredServer(t *testing.T, handler http.Handler, ad
 			t.Fatalf("expected idle timeout 60s, got %v", server.IdleTimeout)
 		}
 		return nil
-	}, func(_ string, _ ...any) {
+	}, func(_ slog.Level, _ string, _ ...any) {
 		loggerCalled = true
 	}); err != nil {
 		t.Fatalf("expected run success, got %v", err)
@@ -132,7 +135,7 @@ func TestRunGracefulShutdownCallsCleanup(t *testing.T) {
 		runErrors <- run(testEphemeralAddr, handler, func(_ *http.Server, _ net.Listener) error {
 			<-startRelease
 			return http.ErrServerClosed
-		}, func(format string, args ...any) {
+		}, func(_ slog.Level, format string, args ...any) {
 			logs <- fmt.Sprintf(format, args...)
 		})
 	}()
@@ -185,7 +188,7 @@ func TestRunLogsTimeoutWaitingForServerGoroutine(t *testing.T) {
 		runErrors <- run(testEphemeralAddr, http.NewServeMux(), func(_ *http.Server, _ net.Listener) error {
 			<-startRelease
 			return nil
-		}, func(format string, args ...any) {
+		}, func(_ slog.Level, format string, args ...any) {
 			logs <- fmt.Sprintf(format, args...)
 		})
 	}()
@@ -243,7 +246,7 @@ func TestRunLogsForcedShutdownWhenSlowRequestExceedsGracePeriod(t *testing.T) {
 		runErrors <- run(testEphemeralAddr, router, func(server *http.Server, listener net.Listener) error {
 			listenAddr <- listener.Addr().String()
 			return server.Serve(listener)
-		}, func(format string, args ...any) {
+		}, func(_ slog.Level, format string, args ...any) {
 			logs <- fmt.Sprintf(format, args...)
 		})
 	}()
@@ -340,7 +343,7 @@ func TestRunAllowsInFlightRequestAndRejectsNewRequestsOnShutdown(t *testing.T) {
 		runErrors <- run(testEphemeralAddr, router, func(server *http.Server, listener net.Listener) error {
 			listenAddr <- listener.Addr().String()
 			return server.Serve(listener)
-		}, func(format string, args ...any) {
+		}, func(_ slog.Level, format string, args ...any) {
 			logs <- fmt.Sprintf(format, args...)
 		})
 	}()
@@ -435,7 +438,7 @@ func TestRunLogsCleanupFailure(t *testing.T) {
 		runErrors <- run(testEphemeralAddr, handler, func(_ *http.Server, _ net.Listener) error {
 			<-startRelease
 			return nil
-		}, func(format string, args ...any) {
+		}, func(_ slog.Level, format string, args ...any) {
 			logs <- fmt.Sprintf(format, args...)
 		})
 	}()
@@ -458,13 +461,13 @@ func TestMainUsesRunServerAndExitHandler(t *testing.T) {
 	previousRunServer := runServer
 	previousMakeRouter := makeRouter
 	previousLoadRuntimeConfig := loadRuntimeConfig
-	previousLogPrintf := logPrintf
+	previousLogOutput := logOutput
 	previousExitProcess := exitProcess
 	t.Cleanup(func() {
 		runServer = previousRunServer
 		makeRouter = previousMakeRouter
 		loadRuntimeConfig = previousLoadRuntimeConfig
-		logPrintf = previousLogPrintf
+		logOutput = previousLogOutput
 		exitProcess = previousExitProcess
 	})
 
@@ -491,7 +494,7 @@ func assertMainUsesRunServerWithExplicitAddr(t *testing.T, exitCode *int) {
 	t.Helper()
 
 	runCalled := false
-	runServer = func(addr string, handler http.Handler, start func(*http.Server, net.Listener) error, logger func(string, ...any)) error {
+	runServer = func(addr string, handler http.Handler, start func(*http.Server, net.Listener) error, logger logFunc) error {
 		runCalled = true
 		if addr != ":8123" {
 			t.Fatalf("expected main to pass env addr, got %s", addr)
@@ -516,7 +519,7 @@ func assertMainUsesRunServerWithExplicitAddr(t *testing.T, exitCode *int) {
 		}
 		return nil
 	}
-	logPrintf = func(_ string, _ ...any) {}
+	logOutput = io.Discard
 
 	main()
 	if !runCalled {
@@ -531,7 +534,7 @@ func assertMainUsesFallbackAddr(t *testing.T) {
 	t.Helper()
 
 	t.Setenv(listenAddrEnv, "")
-	runServer = func(addr string, _ http.Handler, _ func(*http.Server, net.Listener) error, _ func(string, ...any)) error {
+	runServer = func(addr string, _ http.Handler, _ func(*http.Server, net.Listener) error, _ logFunc) error {
 		if addr != ":8070" {
 			t.Fatalf("expected fallback addr in main, got %s", addr)
 		}
@@ -543,18 +546,16 @@ func assertMainUsesFallbackAddr(t *testing.T) {
 func assertMainExitsOnRunServerError(t *testing.T, exitCode *int) {
 	t.Helper()
 
-	runServer = func(_ string, _ http.Handler, _ func(*http.Server, net.Listener) error, _ func(string, ...any)) error {
+	runServer = func(_ string, _ http.Handler, _ func(*http.Server, net.Listener) error, _ logFunc) error {
 		return errors.New("boom")
 	}
-	var logMessages []string
-	logPrintf = func(format string, args ...any) {
-		logMessages = append(logMessages, fmt.Sprintf(format, args...))
-	}
+	var logBuffer bytes.Buffer
+	logOutput = &logBuffer
 	main()
 	if *exitCode != 1 {
 		t.Fatalf("expected exit code 1 when runServer fails, got %d", *exitCode)
 	}
-	if !logsContain(logMessages, "server failed") {
+	if logMessages := logLines(&logBuffer); !logsContain(logMessages, "server failed") {
 		t.Fatalf("expected log message to include server failed, got %v", logMessages)
 	}
 }
@@ -564,30 +565,28 @@ func TestMainUsesModeDefaultsAndHandlesBootstrapErrors(t *testing.T) {
 	previousRunServer := runServer
 	previousMakeRouter := makeRouter
 	previousLoadRuntimeConfig := loadRuntimeConfig
-	previousLogPrintf := logPrintf
+	previousLogOutput := logOutput
 	previousExitProcess := exitProcess
 	t.Cleanup(func() {
 		runServer = previousRunServer
 		makeRouter = previousMakeRouter
 		loadRuntimeConfig = previousLoadRuntimeConfig
-		logPrintf = previousLogPrintf
+		logOutput = previousLogOutput
 		exitProcess = previousExitProcess
 	})
 
 	makeRouter = func(httpapi.RuntimeConfig) (http.Handler, error) {
 		return http.NewServeMux(), nil
 	}
-	runServer = func(addr string, _ http.Handler, _ func(*http.Server, net.Listener) error, _ func(string, ...any)) error {
+	runServer = func(addr string, _ http.Handler, _ func(*http.Server, net.Listener) error, _ logFunc) error {
 		if addr != "127.0.0.1:8070" {
 			t.Fatalf("expected development mode default addr, got %s", addr)
 		}
 		return nil
 	}
 
-	var logMessages []string
-	logPrintf = func(format string, args ...any) {
-		logMessages = append(logMessages, fmt.Sprintf(format, args...))
-	}
+	var logBuffer bytes.Buffer
+	logOutput = &logBuffer
 
 	exitCode := -1
 	exitProcess = func(code int) {
@@ -602,20 +601,20 @@ func TestMainUsesModeDefaultsAndHandlesBootstrapErrors(t *testing.T) {
 	if exitCode != -1 {
 		t.Fatalf("expected no exit during successful startup, got %d", exitCode)
 	}
-	if !logsContain(logMessages, "development mode") {
+	if logMessages := logLines(&logBuffer); !logsContain(logMessages, "development mode") {
 		t.Fatalf("expected development mode warnings, got %v", logMessages)
 	}
 
 	loadRuntimeConfig = func() (httpapi.RuntimeConfig, error) {
 		return httpapi.RuntimeConfig{}, errors.New("config failed")
 	}
-	logMessages = []string{}
+	logBuffer.Reset()
 	exitCode = -1
 	main()
 	if exitCode != 1 {
 		t.Fatalf("expected exit code 1 on runtime config failure, got %d", exitCode)
 	}
-	if !logsContain(logMessages, "failed to load runtime config") {
+	if logMessages := logLines(&logBuffer); !logsContain(logMessages, "failed to load runtime config") {
 		t.Fatalf("expected config failure log, got %v", logMessages)
 	}
 
@@ -625,13 +624,13 @@ func TestMainUsesModeDefaultsAndHandlesBootstrapErrors(t *testing.T) {
 	makeRouter = func(httpapi.RuntimeConfig) (http.Handler, error) {
 		return nil, errors.New("router failed")
 	}
-	logMessages = []string{}
+	logBuffer.Reset()
 	exitCode = -1
 	main()
 	if exitCode != 1 {
 		t.Fatalf("expected exit code 1 on router initialization failure, got %d", exitCode)
 	}
-	if !logsContain(logMessages, "failed to initialize router") {
+	if logMessages := logLines(&logBuffer); !logsContain(logMessages, "failed to initialize router") {
 		t.Fatalf("expected router failure log, got %v", logMessages)
 	}
 }
@@ -639,7 +638,7 @@ func TestMainUsesModeDefaultsAndHandlesBootstrapErrors(t *testing.T) {
 // TestLogStartupWarnings verifies the log startup warnings scenario.
 func TestLogStartupWarnings(t *testing.T) {
 	logMessages := []string{}
-	logger := func(format string, args ...any) {
+	logger := func(_ slog.Level, format string, args ...any) {
 		logMessages = append(logMessages, fmt.Sprintf(format, args...))
 	}
 
@@ -664,6 +663,74 @@ func TestLogStartupWarnings(t *testing.T) {
 	}
 }
 
+// TestMainLogsDevelopmentWarningsAsJSON verifies the main logs development warnings as JSON scenario.
+func TestMainLogsDevelopmentWarningsAsJSON(t *testing.T) {
+	previousRunServer := runServer
+	previousMakeRouter := makeRouter
+	previousLoadRuntimeConfig := loadRuntimeConfig
+	previousLogOutput := logOutput
+	previousExitProcess := exitProcess
+	t.Cleanup(func() {
+		runServer = previousRunServer
+		makeRouter = previousMakeRouter
+		loadRuntimeConfig = previousLoadRuntimeConfig
+		logOutput = previousLogOutput
+		exitProcess = previousExitProcess
+	})
+
+	loadRuntimeConfig = func() (httpapi.RuntimeConfig, error) {
+		return httpapi.RuntimeConfig{Mode: httpapi.RuntimeModeDevelopment}, nil
+	}
+	makeRouter = func(httpapi.RuntimeConfig) (http.Handler, error) {
+		return http.NewServeMux(), nil
+	}
+	runServer = func(_ string, _ http.Handler, _ func(*http.Server, net.Listener) error, logger logFunc) error {
+		logger(slog.LevelInfo, "lifecycle message below the configured level")
+		return nil
+	}
+	exitCode := -1
+	exitProcess = func(code int) {
+		exitCode = code
+	}
+	var logBuffer bytes.Buffer
+	logOutput = &logBuffer
+	t.Setenv(logLevelEnv, "warn")
+	t.Setenv(logFormatEnv, "json")
+
+	main()
+	if exitCode != -1 {
+		t.Fatalf("expected no exit during successful startup, got %d", exitCode)
+	}
+	entries := logLines(&logBuffer)
+	if len(entries) != 3 {
+		t.Fatalf("expected only the three development warnings at warn level, got %v", entries)
+	}
+	for _, entry := range entries {
+		var record struct {
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}
+		if err := json.Unmarshal([]byte(entry), &record); err != nil {
+			t.Fatalf("expected JSON log line, got %q: %v", entry, err)
+		}
+		if record.Level != "WARN" || !strings.Contains(record.Msg, "development mode") {
+			t.Fatalf("expected development warning at WARN, got %+v", record)
+		}
+	}
+
+	for env, value := range map[string]string{logLevelEnv: "loud", logFormatEnv: "xml"} {
+		t.Setenv(logLevelEnv, "")
+		t.Setenv(logFormatEnv, "")
+		t.Setenv(env, value)
+		logBuffer.Reset()
+		exitCode = -1
+		main()
+		if exitCode != 1 || !strings.Contains(logBuffer.String(), "failed to configure logging") {
+			t.Fatalf("expected %s=%s to stop startup, got exit %d logs %q", env, value, exitCode, logBuffer.String())
+		}
+	}
+}
+
 type testClosableHandler struct {
 	http.Handler
 	closeErr error
@@ -686,6 +753,10 @@ func logsContain(logs []string, substring string) bool {
 	return false
 }
 
+func logLines(buffer *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buffer.String()), "\n")
+}
+
 func waitForReady(t *testing.T, url string) {
 	t.Helper()
 
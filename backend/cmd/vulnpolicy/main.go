@@ -30,6 +30,7 @@ var (
 const (
 	defaultNVDAPIBaseURL     = "https://services.nvd.nist.gov/rest/json/cves/2.0"
 	defaultGHSAAPIBaseURL    = "https://api.github.com/advisories"
+	defaultOSVAPIBaseURL     = "https://api.osv.dev/v1/vulns"
 	scanModeSource           = "source"
 	scanModeBinary           = "binary"
 	consoleInfoDisplayCap    = 10
@@ -61,6 +62,14 @@ const (
 	severityCritical severity = "CRITICAL"
 )
 
+type severityBands struct {
+	Critical float64
+	High     float64
+	Medium   float64
+}
+
+var defaultSeverityBands = severityBands{Critical: 9.0, High: 7.0, Medium: 4.0}
+
 type severityMethod string
 
 const (
@@ -78,6 +87,10 @@ type vulnAssessment struct {
 	FixedVersions []string
 	Reachable     bool
 	OSVSeverity   severityAssessment
+
+	// ReachableTraces keeps the call stacks of reachable findings so path
+	// overrides can match the packages they pass through.
+	ReachableTraces [][]govulnTraceFrame
 }
 
 type severityAssessment struct {
@@ -94,6 +107,9 @@ type evaluatedVuln struct {
 	Override      *riskOverride
 	MatchedByID   string
 	ResolverError error
+	// ExpiringSoon marks an accepted override that expires within the
+	// -override-expiry-warning window.
+	ExpiringSoon bool
 }
 
 type evaluationResult struct {
@@ -119,11 +135,22 @@ type nvdSeverityResolver struct {
 	apiKey      string
 	ghsaBaseURL string
 	ghsaToken   string
+	osvBaseURL  string
+	osvLookup   bool
 	offline     bool
 	snapshot    map[string]severityAssessment
 	mu          sync.RWMutex
 	cache       map[string]severityAssessment
 	errorMap    map[string]error
+	// cacheExpiry holds the expiry of entries loaded from the persistent
+	// severity cache so saving keeps it instead of extending it.
+	cacheExpiry map[string]time.Time
+
+	// nvdRequestInterval is the minimum spacing between NVD requests.
+	// Zero disables pacing.
+	nvdRequestInterval time.Duration
+	nvdPaceMu          sync.Mutex
+	lastNVDRequest     time.Time
 }
 
 type govulnEvent struct {
@@ -168,6 +195,8 @@ type overrideInput struct {
 	ApprovedBy     string `json:"approved_by"`
 	ApprovedDate   string `json:"approved_date"`
 	Severity       string `json:"severity"`
+	Package        string `json:"package"`
+	Function       string `json:"function"`
 }
 
 type riskOverride struct {
@@ -180,6 +209,8 @@ type riskOverride struct {
 	ApprovedBy     string
 	ApprovedDate   *time.Time
 	Severity       severity
+	Package        string
+	Function       string
 }
 
 type nvdResponse struct {
@@ -231,17 +262,47 @@ type severitySnapshotEntry struct {
 	Score    float64 `json:"score"`
 }
 
+// severityCacheFile persists resolved severities between runs. Every entry
+// carries its own expiry so failed lookups can be retried sooner than
+// successful ones are refreshed.
+type severityCacheFile struct {
+	Entries map[string]severityCacheEntry `json:"entries"`
+}
+
+type severityCacheEntry struct {
+	Severity  severity       `json:"severity"`
+	Score     float64        `json:"score"`
+	Source    string         `json:"source,omitempty"`
+	Method    severityMethod `json:"method,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	ExpiresAt time.Time      `json:"expires_at"`
+}
+
+// severityCacheTTLs sets how long persisted cache entries stay valid. A zero
+// error TTL keeps failed lookups out of the persistent cache.
+type severityCacheTTLs struct {
+	success time.Duration
+	failure time.Duration
+}
+
 type reportConfiguration struct {
-	InputPath            string `json:"input_path"`
-	OverridesPath        string `json:"overrides_path"`
-	ExcludeInputPath     string `json:"exclude_input_path,omitempty"`
-	SeveritySnapshotPath string `json:"severity_snapshot_path,omitempty"`
-	NVDAPIBaseURL        string `json:"nvd_api_base_url"`
-	GHSAAPIBaseURL       string `json:"ghsa_api_base_url"`
-	NVDTimeout           string `json:"nvd_timeout"`
-	Offline              bool   `json:"offline"`
-	NVDAPIKeyConfigured  bool   `json:"nvd_api_key_configured"`
-	GHSATokenConfigured  bool   `json:"ghsa_token_configured"`
+	InputPath             string `json:"input_path"`
+	OverridesPath         string `json:"overrides_path"`
+	ExcludeInputPath      string `json:"exclude_input_path,omitempty"`
+	SeveritySnapshotPath  string `json:"severity_snapshot_path,omitempty"`
+	SeverityCachePath     string `json:"severity_cache_path,omitempty"`
+	NVDAPIBaseURL         string `json:"nvd_api_base_url"`
+	GHSAAPIBaseURL        string `json:"ghsa_api_base_url"`
+	OSVAPIBaseURL         string `json:"osv_api_base_url,omitempty"`
+	OSVLookup             bool   `json:"osv_lookup"`
+	StrictOverrideMatch   bool   `json:"strict_override_match"`
+	OverrideExpiryWarning string `json:"override_expiry_warning,omitempty"`
+	SeverityBands         string `json:"severity_bands"`
+	NVDTimeout            string `json:"nvd_timeout"`
+	Offline               bool   `json:"offline"`
+	NVDAPIKeyConfigured   bool   `json:"nvd_api_key_configured"`
+	GHSATokenConfigured   bool   `json:"ghsa_token_configured"`
 }
 
 type scanReport struct {
@@ -265,12 +326,13 @@ type reportTool struct {
 }
 
 type reportSummary struct {
-	Fail     int `json:"fail"`
-	Warn     int `json:"warn"`
-	Info     int `json:"info"`
-	Accepted int `json:"accepted"`
-	Expired  int `json:"expired"`
-	Blocking int `json:"blocking"`
+	Fail         int `json:"fail"`
+	Warn         int `json:"warn"`
+	Info         int `json:"info"`
+	Accepted     int `json:"accepted"`
+	ExpiringSoon int `json:"expiring_soon"`
+	Expired      int `json:"expired"`
+	Blocking     int `json:"blocking"`
 }
 
 type reportFindingGroups struct {
@@ -292,6 +354,7 @@ type reportFinding struct {
 	Override      *reportOverride `json:"override,omitempty"`
 	MatchedByID   string          `json:"matched_by_id,omitempty"`
 	ResolverError string          `json:"resolver_error,omitempty"`
+	ExpiringSoon  bool            `json:"expiring_soon,omitempty"`
 }
 
 type reportSeverity struct {
@@ -312,6 +375,8 @@ type reportOverride struct {
 	ApprovedBy     string     `json:"approved_by,omitempty"`
 	ApprovedDate   *time.Time `json:"approved_date,omitempty"`
 	Severity       severity   `json:"severity,omitempty"`
+	Package        string     `json:"package,omitempty"`
+	Function       string     `json:"function,omitempty"`
 }
 
 type reportTruncation struct {
@@ -361,7 +426,7 @@ func main() {
 }
 
 type cliConfig struct {
-	inputPath        string
+	inputPaths       []string
 	overridesPath    string
 	scanMode         string
 	excludeInput     string
@@ -369,10 +434,20 @@ type cliConfig struct {
 	nvdAPIKeyFile    string
 	ghsaAPIBaseURL   string
 	ghsaTokenFile    string
+	osvAPIBaseURL    string
+	osvLookup        bool
+	severityBands    severityBands
 	severitySnapshot string
+	severityCache    string
+	severityCacheTTL severityCacheTTLs
 	offlineMode      bool
 	nvdTimeout       time.Duration
 	reportFile       string
+	strictOverrides  bool
+	expiryWarning    time.Duration
+	// nvdRequestInterval is negative when the interval should be chosen
+	// from whether an NVD API key is configured.
+	nvdRequestInterval time.Duration
 }
 
 type policyEvaluationOutcome struct {
@@ -383,7 +458,7 @@ type policyEvaluationOutcome struct {
 }
 
 type cliFlags struct {
-	inputPath        *string
+	inputPaths       *pathListFlag
 	overridesPath    *string
 	scanMode         *string
 	excludeInput     *string
@@ -391,15 +466,46 @@ type cliFlags struct {
 	nvdAPIKeyFile    *string
 	ghsaAPIBaseURL   *string
 	ghsaTokenFile    *string
+	osvAPIBaseURL    *string
+	osvLookup        *bool
+	severityBands    *string
 	severitySnapshot *string
+	severityCache    *string
+	cacheTTL         *time.Duration
+	cacheErrorTTL    *time.Duration
 	offlineMode      *bool
 	nvdTimeout       *time.Duration
 	reportFile       *string
+	strictOverrides  *bool
+	expiryWarning    *time.Duration
+	nvdInterval      *string
+}
+
+// pathListFlag collects paths from a flag that may be repeated and whose
+// values may also be comma-separated.
+type pathListFlag []string
+
+func (paths *pathListFlag) String() string {
+	if paths == nil {
+		return ""
+	}
+	return strings.Join(*paths, ",")
+}
+
+func (paths *pathListFlag) Set(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			*paths = append(*paths, trimmed)
+		}
+	}
+	return nil
 }
 
 func registerCLIFlags(flagSet *flag.FlagSet) cliFlags {
+	inputPaths := &pathListFlag{}
+	flagSet.Var(inputPaths, "input", "path to govulncheck JSON output, repeat the flag or separate paths with commas to merge several outputs")
 	return cliFlags{
-		inputPath:        flagSet.String("input", "", "path to govulncheck JSON output"),
+		inputPaths:       inputPaths,
 		overridesPath:    flagSet.String("overrides", "", "path to vulnerability override config"),
 		scanMode:         flagSet.String("scan-mode", scanModeSource, "govulncheck scan mode used by the input: source or binary"),
 		excludeInput:     flagSet.String("exclude-input", "", "optional path to govulncheck JSON output whose vulnerabilities should be excluded"),
@@ -407,16 +513,24 @@ func registerCLIFlags(flagSet *flag.FlagSet) cliFlags {
 		nvdAPIKeyFile:    flagSet.String("nvd-api-key-file", "", "path to file containing NVD API key"),
 		ghsaAPIBaseURL:   flagSet.String("ghsa-api-base-url", defaultGHSAAPIBaseURL, "GHSA advisory API base URL"),
 		ghsaTokenFile:    flagSet.String("ghsa-token-file", "", "path to file containing optional GHSA API token"),
+		osvAPIBaseURL:    flagSet.String("osv-api-base-url", defaultOSVAPIBaseURL, "OSV vulnerability API base URL"),
+		osvLookup:        flagSet.Bool("osv-lookup", false, "look up OSV severity by Go vulnerability ID when no other source resolves it"),
+		severityBands:    flagSet.String("severity-bands", "", "optional CVSS minimum scores as critical=9.0,high=7.0,medium=4.0"),
 		severitySnapshot: flagSet.String("severity-snapshot", "", "path to pinned NVD severity snapshot JSON"),
-		offlineMode:      flagSet.Bool("offline", false, "disable live GHSA and NVD lookups and use pinned snapshot data only"),
+		severityCache:    flagSet.String("severity-cache", "", "optional path to a severity cache JSON file kept between runs, ignored with -offline"),
+		cacheTTL:         flagSet.Duration("severity-cache-ttl", 24*time.Hour, "how long a successful lookup stays in the severity cache"),
+		cacheErrorTTL:    flagSet.Duration("severity-cache-error-ttl", time.Hour, "how long a failed lookup stays in the severity cache, 0 keeps failures out of it"),
+		offlineMode:      flagSet.Bool("offline", false, "disable live GHSA, NVD, and OSV lookups and use pinned snapshot data only"),
 		nvdTimeout:       flagSet.Duration("nvd-timeout", 15*time.Second, "timeout per severity API request"),
 		reportFile:       flagSet.String("report-file", "", "optional path to write full vulnerability scan report JSON"),
+		strictOverrides:  flagSet.Bool("strict-override-match", false, "match overrides on the finding ID only, without alias expansion"),
+		expiryWarning:    flagSet.Duration("override-expiry-warning", 0, "report accepted overrides expiring within this window as expiring soon, 0 disables the warning"),
+		nvdInterval:      flagSet.String("nvd-request-interval", "", "minimum delay between NVD requests, 0 disables pacing (default 6s without an API key, 600ms with one)"),
 	}
 }
 
 func (flags cliFlags) config() (cliConfig, error) {
-	trimmedInputPath := strings.TrimSpace(*flags.inputPath)
-	if trimmedInputPath == "" {
+	if len(*flags.inputPaths) == 0 {
 		return cliConfig{}, errors.New("-input is required")
 	}
 	trimmedOverridesPath := strings.TrimSpace(*flags.overridesPath)
@@ -427,9 +541,26 @@ func (flags cliFlags) config() (cliConfig, error) {
 	if err != nil {
 		return cliConfig{}, err
 	}
+	bands, err := parseSeverityBands(*flags.severityBands)
+	if err != nil {
+		return cliConfig{}, err
+	}
+	nvdInterval, err := parseNVDRequestInterval(*flags.nvdInterval)
+	if err != nil {
+		return cliConfig{}, err
+	}
+	if *flags.expiryWarning < 0 {
+		return cliConfig{}, fmt.Errorf("-override-expiry-warning must not be negative, got %s", *flags.expiryWarning)
+	}
+	if *flags.cacheTTL <= 0 {
+		return cliConfig{}, fmt.Errorf("-severity-cache-ttl must be positive, got %s", *flags.cacheTTL)
+	}
+	if *flags.cacheErrorTTL < 0 {
+		return cliConfig{}, fmt.Errorf("-severity-cache-error-ttl must not be negative, got %s", *flags.cacheErrorTTL)
+	}
 
 	return cliConfig{
-		inputPath:        trimmedInputPath,
+		inputPaths:       append([]string(nil), *flags.inputPaths...),
 		overridesPath:    trimmedOverridesPath,
 		scanMode:         normalizedScanMode,
 		excludeInput:     strings.TrimSpace(*flags.excludeInput),
@@ -437,20 +568,55 @@ func (flags cliFlags) config() (cliConfig, error) {
 		nvdAPIKeyFile:    strings.TrimSpace(*flags.nvdAPIKeyFile),
 		ghsaAPIBaseURL:   strings.TrimSpace(*flags.ghsaAPIBaseURL),
 		ghsaTokenFile:    strings.TrimSpace(*flags.ghsaTokenFile),
+		osvAPIBaseURL:    strings.TrimSpace(*flags.osvAPIBaseURL),
+		osvLookup:        *flags.osvLookup,
+		severityBands:    bands,
 		severitySnapshot: strings.TrimSpace(*flags.severitySnapshot),
+		severityCache:    strings.TrimSpace(*flags.severityCache),
+		severityCacheTTL: severityCacheTTLs{success: *flags.cacheTTL, failure: *flags.cacheErrorTTL},
 		offlineMode:      *flags.offlineMode,
 		nvdTimeout:       *flags.nvdTimeout,
 		reportFile:       strings.TrimSpace(*flags.reportFile),
+		strictOverrides:  *flags.strictOverrides,
+		expiryWarning:    *flags.expiryWarning,
+
+		nvdRequestInterval: nvdInterval,
 	}, nil
 }
 
+// parseNVDRequestInterval returns a negative interval for an empty value so
+// the resolver picks the default for its credential state.
+func parseNVDRequestInterval(raw string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return -1, nil
+	}
+	interval, err := time.ParseDuration(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("invalid -nvd-request-interval %q: %w", raw, err)
+	}
+	if interval < 0 {
+		return 0, fmt.Errorf("-nvd-request-interval must not be negative, got %s", interval)
+	}
+	return interval, nil
+}
+
+// defaultNVDRequestInterval follows the NVD guidance of roughly six seconds
+// between unauthenticated requests. Keyed clients get a much higher quota.
+func defaultNVDRequestInterval(apiKeyConfigured bool) time.Duration {
+	if apiKeyConfigured {
+		return 600 * time.Millisecond
+	}
+	return 6 * time.Second
+}
+
 func runPolicyEvaluation(config cliConfig) (policyEvaluationOutcome, error) {
 	vulns, err := loadInputVulnerabilities(config)
 	if err != nil {
 		return policyEvaluationOutcome{}, err
 	}
 
-	overrides, err := loadOverrides(config.overridesPath)
+	overrides, pathOverrides, err := loadOverrides(config.overridesPath)
 	if err != nil {
 		return policyEvaluationOutcome{}, fmt.Errorf("load overrides: %w", err)
 	}
@@ -461,7 +627,18 @@ func runPolicyEvaluation(config cliConfig) (policyEvaluationOutcome, error) {
 	}
 
 	runTime := time.Now().UTC()
-	result := evaluateVulnerabilities(context.Background(), vulns, overrides, resolver, runTime)
+	result := evaluateVulnerabilities(
+		context.Background(),
+		vulns,
+		overrideSet{byID: overrides, byPath: pathOverrides, strict: config.strictOverrides, expiryWarning: config.expiryWarning},
+		withSeverityBands(resolver, config.severityBands),
+		runTime,
+	)
+	if usesSeverityCache(config) {
+		if err = resolver.saveSeverityCache(config.severityCache, config.severityCacheTTL, time.Now().UTC()); err != nil {
+			return policyEvaluationOutcome{}, fmt.Errorf("save severity cache: %w", err)
+		}
+	}
 	return policyEvaluationOutcome{
 		result:       result,
 		runTime:      runTime,
@@ -471,7 +648,7 @@ func runPolicyEvaluation(config cliConfig) (policyEvaluationOutcome, error) {
 }
 
 func loadInputVulnerabilities(config cliConfig) ([]vulnAssessment, error) {
-	vulns, err := parseVulnerabilityInput(config.inputPath, config.scanMode)
+	vulns, err := parseVulnerabilityInputs(config.inputPaths, config.scanMode)
 	if err != nil {
 		return nil, err
 	}
@@ -486,19 +663,28 @@ func loadInputVulnerabilities(config cliConfig) ([]vulnAssessment, error) {
 	return filterExcludedVulnerabilities(vulns, excludedIDs), nil
 }
 
-func parseVulnerabilityInput(inputPath, scanMode string) ([]vulnAssessment, error) {
-	inputFile, err := os.Open(inputPath)
-	if err != nil {
-		return nil, fmt.Errorf("open govulncheck output: %w", err)
+// parseVulnerabilityInputs merges every govulncheck output into one list so
+// a monorepo scanned per module gets a single evaluation and exit code.
+func parseVulnerabilityInputs(inputPaths []string, scanMode string) ([]vulnAssessment, error) {
+	inputFiles := make([]*os.File, 0, len(inputPaths))
+	defer func() {
+		for _, inputFile := range inputFiles {
+			_ = inputFile.Close()
+		}
+	}()
+	readers := make([]io.Reader, 0, len(inputPaths))
+	for _, inputPath := range inputPaths {
+		inputFile, err := os.Open(inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("open govulncheck output: %w", err)
+		}
+		inputFiles = append(inputFiles, inputFile)
+		readers = append(readers, inputFile)
 	}
 
-	vulns, parseErr := parseGovulncheckOutputWithMode(inputFile, scanMode)
-	closeErr := inputFile.Close()
-	if parseErr != nil {
-		return nil, fmt.Errorf("parse govulncheck output: %w", parseErr)
-	}
-	if closeErr != nil {
-		return nil, fmt.Errorf("close govulncheck output: %w", closeErr)
+	vulns, err := parseGovulncheckOutputWithMode(scanMode, readers...)
+	if err != nil {
+		return nil, fmt.Errorf("parse govulncheck output: %w", err)
 	}
 	return vulns, nil
 }
@@ -530,30 +716,57 @@ func buildSeverityResolver(config cliConfig) (resolver *nvdSeverityResolver, api
 		apiKey:      apiKey,
 		ghsaBaseURL: config.ghsaAPIBaseURL,
 		ghsaToken:   ghsaToken,
+		osvBaseURL:  config.osvAPIBaseURL,
+		osvLookup:   config.osvLookup,
 		offline:     config.offlineMode,
 		snapshot:    snapshot,
 		cache:       make(map[string]severityAssessment),
 		errorMap:    make(map[string]error),
+		cacheExpiry: make(map[string]time.Time),
+
+		nvdRequestInterval: config.nvdRequestInterval,
+	}
+	if resolver.nvdRequestInterval < 0 {
+		resolver.nvdRequestInterval = defaultNVDRequestInterval(apiKey != "")
+	}
+	if usesSeverityCache(config) {
+		entries, cacheErr := loadSeverityCache(config.severityCache, time.Now().UTC())
+		if cacheErr != nil {
+			return nil, "", "", fmt.Errorf("load severity cache: %w", cacheErr)
+		}
+		resolver.seedCache(entries)
 	}
 	return resolver, apiKey, ghsaToken, nil
 }
 
+// usesSeverityCache reports whether the persistent severity cache applies.
+// Offline runs rely on the pinned snapshot alone.
+func usesSeverityCache(config cliConfig) bool {
+	return config.severityCache != "" && !config.offlineMode
+}
+
 func writeScanReportIfConfigured(config cliConfig, outcome policyEvaluationOutcome) error {
 	if config.reportFile == "" {
 		return nil
 	}
 
 	report := buildScanReport(config.scanMode, outcome.runTime, outcome.result, reportConfiguration{
-		InputPath:            config.inputPath,
-		OverridesPath:        config.overridesPath,
-		ExcludeInputPath:     config.excludeInput,
-		SeveritySnapshotPath: config.severitySnapshot,
-		NVDAPIBaseURL:        config.nvdAPIBaseURL,
-		GHSAAPIBaseURL:       config.ghsaAPIBaseURL,
-		NVDTimeout:           config.nvdTimeout.String(),
-		Offline:              config.offlineMode,
-		NVDAPIKeyConfigured:  outcome.apiKeySet,
-		GHSATokenConfigured:  outcome.ghsaTokenSet,
+		InputPath:             strings.Join(config.inputPaths, ","),
+		OverridesPath:         config.overridesPath,
+		ExcludeInputPath:      config.excludeInput,
+		SeveritySnapshotPath:  config.severitySnapshot,
+		SeverityCachePath:     config.severityCache,
+		NVDAPIBaseURL:         config.nvdAPIBaseURL,
+		GHSAAPIBaseURL:        config.ghsaAPIBaseURL,
+		OSVAPIBaseURL:         osvAPIBaseURLForReport(config),
+		OSVLookup:             config.osvLookup,
+		StrictOverrideMatch:   config.strictOverrides,
+		OverrideExpiryWarning: expiryWarningForReport(config.expiryWarning),
+		SeverityBands:         config.severityBands.String(),
+		NVDTimeout:            config.nvdTimeout.String(),
+		Offline:               config.offlineMode,
+		NVDAPIKeyConfigured:   outcome.apiKeySet,
+		GHSATokenConfigured:   outcome.ghsaTokenSet,
 	})
 	if err := writeScanReport(config.reportFile, report); err != nil {
 		return fmt.Errorf("write report file: %w", err)
@@ -561,6 +774,20 @@ func writeScanReportIfConfigured(config cliConfig, outcome policyEvaluationOutco
 	return nil
 }
 
+func expiryWarningForReport(window time.Duration) string {
+	if window <= 0 {
+		return ""
+	}
+	return window.String()
+}
+
+func osvAPIBaseURLForReport(config cliConfig) string {
+	if !config.osvLookup {
+		return ""
+	}
+	return config.osvAPIBaseURL
+}
+
 func hasBlockingFindings(result evaluationResult) bool {
 	return len(result.Fail) > 0 || len(result.Expired) > 0
 }
@@ -571,23 +798,30 @@ func exitf(format string, args ...any) {
 }
 
 func parseGovulncheckOutput(reader io.Reader) ([]vulnAssessment, error) {
-	return parseGovulncheckOutputWithMode(reader, scanModeSource)
+	return parseGovulncheckOutputWithMode(scanModeSource, reader)
 }
 
-func parseGovulncheckOutputWithMode(reader io.Reader, scanMode string) ([]vulnAssessment, error) {
-	decoder := json.NewDecoder(reader)
+// parseGovulncheckOutputWithMode decodes one or more govulncheck outputs into
+// a single list keyed by vulnerability ID. Aliases and fixed versions are
+// united across outputs, and a finding reachable in any output is reachable.
+func parseGovulncheckOutputWithMode(scanMode string, readers ...io.Reader) ([]vulnAssessment, error) {
 	vulnByID := make(map[string]*vulnAssessment)
-
-	for {
-		var event govulnEvent
-		if err := decoder.Decode(&event); err != nil {
-			if errors.Is(err, io.EOF) {
-				break
+	for index, reader := range readers {
+		decoder := json.NewDecoder(reader)
+		for {
+			var event govulnEvent
+			if err := decoder.Decode(&event); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if len(readers) > 1 {
+					return nil, fmt.Errorf("input %d: %w", index+1, err)
+				}
+				return nil, err
 			}
-			return nil, err
-		}
 
-		processGovulnEvent(event, scanMode, vulnByID)
+			processGovulnEvent(event, scanMode, vulnByID)
+		}
 	}
 
 	return sortedVulnAssessments(vulnByID), nil
@@ -624,6 +858,9 @@ func updateVulnFromFinding(finding *govulnFinding, scanMode string, vulnByID map
 	if scanMode == scanModeBinary || findingIsReachable(finding) {
 		entry.Reachable = true
 	}
+	if findingIsReachable(finding) {
+		entry.ReachableTraces = append(entry.ReachableTraces, append([]govulnTraceFrame(nil), finding.Trace...))
+	}
 }
 
 func sortedVulnAssessments(vulnByID map[string]*vulnAssessment) []vulnAssessment {
@@ -651,6 +888,87 @@ func normalizeScanMode(value string) (string, error) {
 	}
 }
 
+func parseSeverityBands(value string) (severityBands, error) {
+	bands := defaultSeverityBands
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return bands, nil
+	}
+
+	for _, entry := range strings.Split(trimmed, ",") {
+		name, rawScore, found := strings.Cut(entry, "=")
+		if !found {
+			return severityBands{}, fmt.Errorf("invalid -severity-bands entry %q (expected name=score)", entry)
+		}
+		score, err := strconv.ParseFloat(strings.TrimSpace(rawScore), 64)
+		if err != nil {
+			return severityBands{}, fmt.Errorf("invalid -severity-bands score %q for %s", rawScore, strings.TrimSpace(name))
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "critical":
+			bands.Critical = score
+		case "high":
+			bands.High = score
+		case "medium":
+			bands.Medium = score
+		default:
+			return severityBands{}, fmt.Errorf("unsupported -severity-bands name %q (valid names: critical, high, medium)", name)
+		}
+	}
+
+	if !(bands.Critical <= 10 && bands.Critical > bands.High && bands.High > bands.Medium && bands.Medium > 0) {
+		return severityBands{}, fmt.Errorf("-severity-bands must decrease from critical to medium within (0, 10], got %s", bands)
+	}
+	return bands, nil
+}
+
+func (bands severityBands) classify(score float64) severity {
+	if score >= bands.Critical {
+		return severityCritical
+	}
+	if score >= bands.High {
+		return severityHigh
+	}
+	if score >= bands.Medium {
+		return severityMedium
+	}
+	if score > 0 {
+		return severityLow
+	}
+	return severityUnknown
+}
+
+func (bands severityBands) String() string {
+	return fmt.Sprintf(
+		"critical=%s,high=%s,medium=%s",
+		strconv.FormatFloat(bands.Critical, 'f', -1, 64),
+		strconv.FormatFloat(bands.High, 'f', -1, 64),
+		strconv.FormatFloat(bands.Medium, 'f', -1, 64),
+	)
+}
+
+// bandedSeverityResolver reclassifies scored assessments with custom CVSS bands.
+type bandedSeverityResolver struct {
+	resolver severityResolver
+	bands    severityBands
+}
+
+func withSeverityBands(resolver severityResolver, bands severityBands) severityResolver {
+	if bands == defaultSeverityBands {
+		return resolver
+	}
+	return bandedSeverityResolver{resolver: resolver, bands: bands}
+}
+
+// Resolve delegates to the wrapped resolver and applies the custom bands when a score is known.
+func (banded bandedSeverityResolver) Resolve(ctx context.Context, vuln vulnAssessment) (severityAssessment, error) {
+	assessment, err := banded.resolver.Resolve(ctx, vuln)
+	if assessment.Score > 0 {
+		assessment.Severity = banded.bands.classify(assessment.Score)
+	}
+	return assessment, err
+}
+
 func collectExcludedIDs(path string) (excludedVulnerabilityIDs, error) {
 	file, err := os.Open(strings.TrimSpace(path))
 	if err != nil {
@@ -760,69 +1078,92 @@ func uniqueStrings(values []string) []string {
 	return result
 }
 
-func loadOverrides(path string) (map[string]riskOverride, error) {
+// loadOverrides reads the override file and splits it into overrides keyed
+// by vulnerability ID and path overrides keyed by package and function.
+func loadOverrides(path string) (map[string]riskOverride, []riskOverride, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var config overrideConfig
 	unmarshalErr := json.Unmarshal(data, &config)
 	if unmarshalErr != nil {
-		return nil, unmarshalErr
+		return nil, nil, unmarshalErr
 	}
 
 	overrides := make(map[string]riskOverride, len(config.Overrides))
+	pathOverrides := make([]riskOverride, 0)
+	seenPaths := make(map[string]struct{})
 	for _, item := range config.Overrides {
 		override, parseErr := parseOverrideInput(item)
 		if parseErr != nil {
-			return nil, parseErr
+			return nil, nil, parseErr
+		}
+		if override.Package != "" {
+			label := pathOverrideLabel(override)
+			if _, exists := seenPaths[label]; exists {
+				return nil, nil, fmt.Errorf("duplicate override path: %s", label)
+			}
+			seenPaths[label] = struct{}{}
+			pathOverrides = append(pathOverrides, override)
+			continue
 		}
 		if _, exists := overrides[override.ID]; exists {
-			return nil, fmt.Errorf("duplicate override id: %s", override.ID)
+			return nil, nil, fmt.Errorf("duplicate override id: %s", override.ID)
 		}
 		overrides[override.ID] = override
 	}
 
-	return overrides, nil
+	return overrides, pathOverrides, nil
 }
 
 func parseOverrideInput(item overrideInput) (riskOverride, error) {
 	id := normalizeID(item.ID)
-	if id == "" {
+	packagePath := strings.TrimSpace(item.Package)
+	function := strings.TrimSpace(item.Function)
+	label := id
+	switch {
+	case id != "" && packagePath != "":
+		return riskOverride{}, fmt.Errorf("override %s must set either id or package, not both", id)
+	case function != "" && packagePath == "":
+		return riskOverride{}, fmt.Errorf("override function %s requires a package", function)
+	case packagePath != "":
+		label = pathOverrideLabel(riskOverride{Package: packagePath, Function: function})
+	case id == "":
 		return riskOverride{}, errors.New("override id is required")
 	}
 
-	reason, err := requiredOverrideField(id, "reason", item.Reason)
+	reason, err := requiredOverrideField(label, "reason", item.Reason)
 	if err != nil {
 		return riskOverride{}, err
 	}
-	owner, err := requiredOverrideField(id, "owner", item.Owner)
+	owner, err := requiredOverrideField(label, "owner", item.Owner)
 	if err != nil {
 		return riskOverride{}, err
 	}
-	trackingTicket, err := requiredOverrideField(id, "tracking_ticket", item.TrackingTicket)
+	trackingTicket, err := requiredOverrideField(label, "tracking_ticket", item.TrackingTicket)
 	if err != nil {
 		return riskOverride{}, err
 	}
-	scope, err := requiredOverrideField(id, "scope", item.Scope)
+	scope, err := requiredOverrideField(label, "scope", item.Scope)
 	if err != nil {
 		return riskOverride{}, err
 	}
-	expiresOn, err := requiredOverrideField(id, "expires_on", item.ExpiresOn)
+	expiresOn, err := requiredOverrideField(label, "expires_on", item.ExpiresOn)
 	if err != nil {
 		return riskOverride{}, err
 	}
 
-	expiryDate, err := parseOverrideDate(id, "expires_on", expiresOn)
+	expiryDate, err := parseOverrideDate(label, "expires_on", expiresOn)
 	if err != nil {
 		return riskOverride{}, err
 	}
-	approvedDate, hasApprovedDate, err := parseOptionalOverrideDate(id, "approved_date", item.ApprovedDate)
+	approvedDate, hasApprovedDate, err := parseOptionalOverrideDate(label, "approved_date", item.ApprovedDate)
 	if err != nil {
 		return riskOverride{}, err
 	}
-	overrideSeverity, err := parseOptionalOverrideSeverity(id, item.Severity)
+	overrideSeverity, err := parseOptionalOverrideSeverity(label, item.Severity)
 	if err != nil {
 		return riskOverride{}, err
 	}
@@ -841,6 +1182,8 @@ func parseOverrideInput(item overrideInput) (riskOverride, error) {
 		ApprovedBy:     strings.TrimSpace(item.ApprovedBy),
 		ApprovedDate:   approvedDatePtr,
 		Severity:       overrideSeverity,
+		Package:        packagePath,
+		Function:       function,
 	}, nil
 }
 
@@ -908,7 +1251,7 @@ func normalizeID(value string) string {
 func evaluateVulnerabilities(
 	ctx context.Context,
 	vulns []vulnAssessment,
-	overrides map[string]riskOverride,
+	overrides overrideSet,
 	resolver severityResolver,
 	now time.Time,
 ) evaluationResult {
@@ -922,10 +1265,13 @@ func evaluateVulnerabilities(
 
 	for _, vuln := range vulns {
 		override, matchedByID := matchOverride(vuln, overrides)
+		if override == nil {
+			override, matchedByID = matchPathOverride(vuln, overrides.byPath)
+		}
 		if override != nil {
 			evaluated := evaluatedVuln{
 				Vuln:        vuln,
-				Severity:    overrideBypassSeverity(vuln, matchedByID),
+				Severity:    overrideBypassSeverity(vuln, override.ID),
 				Override:    override,
 				MatchedByID: matchedByID,
 			}
@@ -933,6 +1279,7 @@ func evaluateVulnerabilities(
 				result.Expired = append(result.Expired, evaluated)
 				continue
 			}
+			evaluated.ExpiringSoon = overrideExpiringSoon(*override, now, overrides.expiryWarning)
 			result.Accepted = append(result.Accepted, evaluated)
 			continue
 		}
@@ -990,11 +1337,24 @@ func overrideBypassSeverity(vuln vulnAssessment, matchedByID string) severityAss
 	)
 }
 
-func matchOverride(vuln vulnAssessment, overrides map[string]riskOverride) (*riskOverride, string) {
-	candidateIDs := append([]string{vuln.ID}, vuln.Aliases...)
+// overrideSet holds the accepted-risk overrides by normalized ID. Strict sets
+// match the finding ID only, otherwise the finding's aliases are tried too.
+// Path overrides match by package and function and ignore strictness.
+type overrideSet struct {
+	byID          map[string]riskOverride
+	byPath        []riskOverride
+	strict        bool
+	expiryWarning time.Duration
+}
+
+func matchOverride(vuln vulnAssessment, overrides overrideSet) (*riskOverride, string) {
+	candidateIDs := []string{vuln.ID}
+	if !overrides.strict {
+		candidateIDs = append(candidateIDs, vuln.Aliases...)
+	}
 	for _, candidate := range candidateIDs {
 		normalized := normalizeID(candidate)
-		if override, ok := overrides[normalized]; ok {
+		if override, ok := overrides.byID[normalized]; ok {
 			overrideCopy := override
 			return &overrideCopy, normalized
 		}
@@ -1002,11 +1362,65 @@ func matchOverride(vuln vulnAssessment, overrides map[string]riskOverride) (*ris
 	return nil, ""
 }
 
+// matchPathOverride accepts a finding when every reachable trace passes
+// through a suppressed package, or through the suppressed function when the
+// override names one. When several overrides are needed to cover the traces,
+// the one expiring first is reported since it bounds the acceptance.
+func matchPathOverride(vuln vulnAssessment, pathOverrides []riskOverride) (*riskOverride, string) {
+	if len(pathOverrides) == 0 || len(vuln.ReachableTraces) == 0 {
+		return nil, ""
+	}
+
+	var binding *riskOverride
+	for _, trace := range vuln.ReachableTraces {
+		matched := pathOverrideForTrace(trace, pathOverrides)
+		if matched == nil {
+			return nil, ""
+		}
+		if binding == nil || matched.ExpiresOn.Before(binding.ExpiresOn) {
+			binding = matched
+		}
+	}
+	overrideCopy := *binding
+	return &overrideCopy, pathOverrideLabel(overrideCopy)
+}
+
+func pathOverrideForTrace(trace []govulnTraceFrame, pathOverrides []riskOverride) *riskOverride {
+	for index := range pathOverrides {
+		override := &pathOverrides[index]
+		for _, frame := range trace {
+			if strings.TrimSpace(frame.Package) != override.Package {
+				continue
+			}
+			if override.Function == "" || strings.TrimSpace(frame.Function) == override.Function {
+				return override
+			}
+		}
+	}
+	return nil
+}
+
+func pathOverrideLabel(override riskOverride) string {
+	if override.Function == "" {
+		return override.Package
+	}
+	return override.Package + "." + override.Function
+}
+
 func overrideExpired(override riskOverride, now time.Time) bool {
 	currentDate := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
 	return currentDate.After(override.ExpiresOn)
 }
 
+// overrideExpiringSoon reports whether an override that is still valid now
+// would count as expired once the warning window has passed.
+func overrideExpiringSoon(override riskOverride, now time.Time, window time.Duration) bool {
+	if window <= 0 || overrideExpired(override, now) {
+		return false
+	}
+	return overrideExpired(override, now.Add(window))
+}
+
 func sortEvaluated(items []evaluatedVuln) {
 	sort.Slice(items, func(i, j int) bool {
 		left := items[i]
@@ -1110,12 +1524,32 @@ func (resolver *nvdSeverityResolver) Resolve(ctx context.Context, vuln vulnAsses
 		return nvdResult.Best, joinedErr
 	}
 
+	osvResult := resolver.resolveBestFromCandidates(ctx, resolver.collectOSVLookupIDs(vuln), resolver.resolveOSV)
+	joinedErr = errors.Join(joinedErr, osvResult.LookupErr)
+	if osvResult.Resolved {
+		return osvResult.Best, joinedErr
+	}
+
 	reason := buildUnknownSeverityReason(ghsaResult, nvdResult)
+	if osvReason := sourceUnknownReason("OSV", osvResult, ""); osvReason != "" {
+		reason += ", " + osvReason
+	}
 	source := unknownSeveritySource(vuln, ghsaCandidates, cveCandidates)
 	assessment := unknownSeverityAssessmentWithReason(source, reason, severityMethodUnknown)
 	return assessment, joinedErr
 }
 
+func (resolver *nvdSeverityResolver) collectOSVLookupIDs(vuln vulnAssessment) []string {
+	if !resolver.osvLookup {
+		return nil
+	}
+	normalizedID := normalizeID(vuln.ID)
+	if !strings.HasPrefix(normalizedID, "GO-") {
+		return nil
+	}
+	return []string{normalizedID}
+}
+
 type sourceResolutionResult struct {
 	Best            severityAssessment
 	LookupErr       error
@@ -1241,6 +1675,29 @@ func (resolver *nvdSeverityResolver) resolveGHSA(ctx context.Context, ghsaID str
 	return resolver.resolveGHSAWithRetry(ctx, normalizedGHSA, requestURL)
 }
 
+func (resolver *nvdSeverityResolver) resolveOSV(ctx context.Context, osvID string) (severityAssessment, error) {
+	normalizedOSV := normalizeID(osvID)
+	if cached, ok, cachedErr := resolver.readCache(normalizedOSV); ok {
+		return cached, cachedErr
+	}
+
+	if snapshotSeverity, ok := resolver.snapshot[normalizedOSV]; ok {
+		resolver.writeCache(normalizedOSV, snapshotSeverity, nil)
+		return snapshotSeverity, nil
+	}
+
+	if resolver.offline {
+		return resolver.cacheUnknownWithError(normalizedOSV, fmt.Errorf("offline mode enabled and %s is missing from severity snapshot", normalizedOSV))
+	}
+
+	requestURL, err := advisoryLookupURL(resolver.osvBaseURL, normalizedOSV)
+	if err != nil {
+		return resolver.cacheUnknownWithError(normalizedOSV, err)
+	}
+
+	return resolver.resolveOSVWithRetry(ctx, normalizedOSV, requestURL)
+}
+
 func (resolver *nvdSeverityResolver) cacheUnknownWithError(id string, err error) (severityAssessment, error) {
 	assessment := unknownSeverityAssessment(id)
 	resolver.writeCache(id, assessment, err)
@@ -1251,6 +1708,9 @@ func (resolver *nvdSeverityResolver) resolveCVEWithRetry(ctx context.Context, no
 	const maxAttempts = 3
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := resolver.waitForNVDSlot(ctx); err != nil {
+			return unknownSeverityAssessment(normalizedCVE), err
+		}
 		request, apiKeyConfigured, err := resolver.newNVDRequest(ctx, requestURL)
 		if err != nil {
 			return resolver.cacheUnknownWithError(normalizedCVE, err)
@@ -1280,6 +1740,32 @@ func (resolver *nvdSeverityResolver) resolveCVEWithRetry(ctx context.Context, no
 	return resolver.cacheUnknownWithError(normalizedCVE, fmt.Errorf("exhausted NVD resolution attempts for %s", normalizedCVE))
 }
 
+// waitForNVDSlot blocks until nvdRequestInterval has passed since the
+// previous NVD request. Callers are served one at a time so concurrent
+// lookups cannot burst past the interval.
+func (resolver *nvdSeverityResolver) waitForNVDSlot(ctx context.Context) error {
+	if resolver.nvdRequestInterval <= 0 {
+		return nil
+	}
+
+	resolver.nvdPaceMu.Lock()
+	defer resolver.nvdPaceMu.Unlock()
+
+	if !resolver.lastNVDRequest.IsZero() {
+		if waitFor := resolver.nvdRequestInterval - time.Since(resolver.lastNVDRequest); waitFor > 0 {
+			timer := time.NewTimer(waitFor)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+	resolver.lastNVDRequest = time.Now()
+	return nil
+}
+
 func (resolver *nvdSeverityResolver) retryOrCacheUnknown(
 	ctx context.Context,
 	attempt int,
@@ -1426,6 +1912,67 @@ func (resolver *nvdSeverityResolver) handleGHSAResponse(response *http.Response,
 	return assessment, false, nil
 }
 
+func (resolver *nvdSeverityResolver) resolveOSVWithRetry(ctx context.Context, normalizedOSV, requestURL string) (severityAssessment, error) {
+	const maxAttempts = 3
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+		if err != nil {
+			return resolver.cacheUnknownWithError(normalizedOSV, err)
+		}
+		request.Header.Set(headerAccept, contentTypeJSON)
+		request.Header.Set("User-Agent", "plato-govuln-policy/1.0")
+		response, err := resolver.client.Do(request)
+		if err != nil {
+			retry, retryAssessment, retryErr := resolver.retryOrCacheUnknown(ctx, attempt, maxAttempts, false, normalizedOSV, err)
+			if retry {
+				continue
+			}
+			return retryAssessment, retryErr
+		}
+
+		assessment, shouldRetry, responseErr := handleOSVResponse(response, normalizedOSV)
+		if shouldRetry {
+			retry, retryAssessment, retryErr := resolver.retryOrCacheUnknown(ctx, attempt, maxAttempts, false, normalizedOSV, responseErr)
+			if retry {
+				continue
+			}
+			return retryAssessment, retryErr
+		}
+
+		resolver.writeCache(normalizedOSV, assessment, responseErr)
+		return assessment, responseErr
+	}
+
+	return resolver.cacheUnknownWithError(normalizedOSV, fmt.Errorf("exhausted OSV resolution attempts for %s", normalizedOSV))
+}
+
+func handleOSVResponse(response *http.Response, normalizedOSV string) (severityAssessment, bool, error) {
+	defer response.Body.Close()
+
+	unknown := unknownSeverityAssessment(normalizedOSV)
+	if shouldRetrySeverityStatus(response.StatusCode) {
+		return severityAssessment{}, true, fmt.Errorf("OSV API returned HTTP %d for %s", response.StatusCode, normalizedOSV)
+	}
+	if response.StatusCode != http.StatusOK {
+		return unknown, false, fmt.Errorf("OSV API returned HTTP %d for %s", response.StatusCode, normalizedOSV)
+	}
+
+	var payload govulnOSV
+	if err := json.NewDecoder(response.Body).Decode(&payload); err != nil {
+		return unknown, false, err
+	}
+	if strings.TrimSpace(payload.ID) == "" {
+		payload.ID = normalizedOSV
+	}
+
+	assessment, ok := resolveOSVSeverity(payload)
+	if !ok {
+		return unknown, false, fmt.Errorf("OSV API returned no severity data for %s", normalizedOSV)
+	}
+	return assessment, false, nil
+}
+
 func shouldRetrySeverityStatus(statusCode int) bool {
 	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
 }
@@ -1688,6 +2235,95 @@ func (resolver *nvdSeverityResolver) writeCache(cveID string, assessment severit
 	resolver.errorMap[cveID] = lookupErr
 }
 
+// loadSeverityCache reads the persistent severity cache and drops entries
+// that expired before now. A missing file is an empty cache.
+func loadSeverityCache(path string, now time.Time) (map[string]severityCacheEntry, error) {
+	rawValue, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]severityCacheEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file severityCacheFile
+	if err = json.Unmarshal(rawValue, &file); err != nil {
+		return nil, err
+	}
+	result := make(map[string]severityCacheEntry, len(file.Entries))
+	for rawID, entry := range file.Entries {
+		if !entry.ExpiresAt.After(now) {
+			continue
+		}
+		result[normalizeID(rawID)] = entry
+	}
+	return result, nil
+}
+
+// seedCache fills the in-memory cache from persisted entries.
+func (resolver *nvdSeverityResolver) seedCache(entries map[string]severityCacheEntry) {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+	for id, entry := range entries {
+		resolver.cache[id] = severityAssessment{
+			Severity: entry.Severity,
+			Score:    entry.Score,
+			Source:   entry.Source,
+			Method:   entry.Method,
+			Reason:   entry.Reason,
+		}
+		resolver.errorMap[id] = nil
+		if entry.Error != "" {
+			resolver.errorMap[id] = errors.New(entry.Error)
+		}
+		resolver.cacheExpiry[id] = entry.ExpiresAt
+	}
+}
+
+// saveSeverityCache writes the in-memory cache to path. Entries loaded from
+// the file keep their expiry, while entries resolved in this run expire after
+// the success or error TTL counted from now.
+func (resolver *nvdSeverityResolver) saveSeverityCache(path string, ttls severityCacheTTLs, now time.Time) error {
+	resolver.mu.RLock()
+	file := severityCacheFile{Entries: make(map[string]severityCacheEntry, len(resolver.cache))}
+	for id, assessment := range resolver.cache {
+		entry := severityCacheEntry{
+			Severity: assessment.Severity,
+			Score:    assessment.Score,
+			Source:   assessment.Source,
+			Method:   assessment.Method,
+			Reason:   assessment.Reason,
+		}
+		ttl := ttls.success
+		if lookupErr := resolver.errorMap[id]; lookupErr != nil {
+			entry.Error = lookupErr.Error()
+			ttl = ttls.failure
+		}
+		if expiresAt, ok := resolver.cacheExpiry[id]; ok {
+			entry.ExpiresAt = expiresAt
+		} else if ttl > 0 {
+			entry.ExpiresAt = now.Add(ttl)
+		} else {
+			continue
+		}
+		file.Entries[id] = entry
+	}
+	resolver.mu.RUnlock()
+
+	cacheDir := filepath.Dir(path)
+	if cacheDir != "." {
+		if mkdirErr := os.MkdirAll(cacheDir, 0o755); mkdirErr != nil {
+			return mkdirErr
+		}
+	}
+	cacheData, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	cacheData = append(cacheData, '\n')
+	return os.WriteFile(path, cacheData, 0o600)
+}
+
 func addQueryParam(rawURL, key, value string) (string, error) {
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
@@ -1770,19 +2406,7 @@ func normalizeSeverity(raw string, score float64) severity {
 		return severityLow
 	}
 
-	if score >= 9.0 {
-		return severityCritical
-	}
-	if score >= 7.0 {
-		return severityHigh
-	}
-	if score >= 4.0 {
-		return severityMedium
-	}
-	if score > 0 {
-		return severityLow
-	}
-	return severityUnknown
+	return defaultSeverityBands.classify(score)
 }
 
 func betterSeverity(left, right severityAssessment) bool {
@@ -1822,12 +2446,13 @@ func buildScanReport(scanMode string, generatedAt time.Time, result evaluationRe
 			Configuration: configuration,
 		},
 		Summary: reportSummary{
-			Fail:     len(result.Fail),
-			Warn:     len(result.Warn),
-			Info:     len(result.Info),
-			Accepted: len(result.Accepted),
-			Expired:  len(result.Expired),
-			Blocking: len(result.Fail) + len(result.Expired),
+			Fail:         len(result.Fail),
+			Warn:         len(result.Warn),
+			Info:         len(result.Info),
+			Accepted:     len(result.Accepted),
+			ExpiringSoon: countExpiringSoon(result.Accepted),
+			Expired:      len(result.Expired),
+			Blocking:     len(result.Fail) + len(result.Expired),
 		},
 		Findings: reportFindingGroups{
 			Fail:     reportFindingsFromEvaluated(result.Fail),
@@ -1840,6 +2465,16 @@ func buildScanReport(scanMode string, generatedAt time.Time, result evaluationRe
 	}
 }
 
+func countExpiringSoon(items []evaluatedVuln) int {
+	count := 0
+	for _, item := range items {
+		if item.ExpiringSoon {
+			count++
+		}
+	}
+	return count
+}
+
 func currentToolVersion() string {
 	buildInfo, ok := debug.ReadBuildInfo()
 	if !ok {
@@ -1903,6 +2538,7 @@ func reportFindingFromEvaluated(item evaluatedVuln) reportFinding {
 		FixedVersions: append([]string(nil), item.Vuln.FixedVersions...),
 		Reachable:     item.Vuln.Reachable,
 		MatchedByID:   item.MatchedByID,
+		ExpiringSoon:  item.ExpiringSoon,
 	}
 	if item.ResolverError != nil {
 		reportItem.ResolverError = item.ResolverError.Error()
@@ -1925,6 +2561,8 @@ func reportFindingFromEvaluated(item evaluatedVuln) reportFinding {
 			ApprovedBy:     item.Override.ApprovedBy,
 			ApprovedDate:   item.Override.ApprovedDate,
 			Severity:       item.Override.Severity,
+			Package:        item.Override.Package,
+			Function:       item.Override.Function,
 		}
 	}
 	return reportItem
@@ -2008,6 +2646,7 @@ func printResult(scanMode string, result evaluationResult) {
 	printExpiredOverrides(result.Expired)
 	printEvaluatedVulnerabilitySection("Failing vulnerabilities", result.Fail)
 	printEvaluatedVulnerabilitySection("Warning vulnerabilities", result.Warn)
+	printExpiringSoonOverrides(result.Accepted)
 	printAcceptedOverrides(result.Accepted)
 	printInformationalFindings(scanMode, result.Info)
 }
@@ -2037,14 +2676,33 @@ func printEvaluatedVulnerabilitySection(title string, items []evaluatedVuln) {
 	}
 }
 
+func printExpiringSoonOverrides(items []evaluatedVuln) {
+	if countExpiringSoon(items) == 0 {
+		return
+	}
+
+	fmt.Println("")
+	fmt.Println("Expiring soon")
+	for _, item := range items {
+		if !item.ExpiringSoon {
+			continue
+		}
+		fmt.Printf("  - %s override %s expires on %s, renew it before then\n", item.Vuln.ID, item.MatchedByID, item.Override.ExpiresOn.Format(dateLayoutISO))
+		fmt.Printf("    owner: %s, tracking: %s\n", item.Override.Owner, item.Override.TrackingTicket)
+	}
+}
+
 func printAcceptedOverrides(items []evaluatedVuln) {
-	if len(items) == 0 {
+	if len(items) == countExpiringSoon(items) {
 		return
 	}
 
 	fmt.Println("")
 	fmt.Println("Accepted risk overrides")
 	for _, item := range items {
+		if item.ExpiringSoon {
+			continue
+		}
 		fmt.Printf("  - %s accepted by %s until %s\n", item.Vuln.ID, item.MatchedByID, item.Override.ExpiresOn.Format(dateLayoutISO))
 		fmt.Printf("    reason: %s\n", item.Override.Reason)
 	}
@@ -17,6 +17,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -114,7 +115,7 @@ func TestParseGovulncheckOutputWithModeBinaryFindingsAreReachable(t *testing.T)
 	input := `{"osv":{"id":"GO-1","summary":"binary finding"}}` + "\n" +
 		`{"finding":{"osv":"GO-1","trace":[{}]}}`
 
-	fromSource, sourceErr := parseGovulncheckOutputWithMode(strings.NewReader(input), scanModeSource)
+	fromSource, sourceErr := parseGovulncheckOutputWithMode(scanModeSource, strings.NewReader(input))
 	if sourceErr != nil {
 		t.Fatalf("parseGovulncheckOutputWithMode source returned error: %v", sourceErr)
 	}
@@ -125,7 +126,7 @@ func TestParseGovulncheckOutputWithModeBinaryFindingsAreReachable(t *testing.T)
 		t.Fatal("expected source mode finding with empty trace details to remain not reachable")
 	}
 
-	fromBinary, binaryErr := parseGovulncheckOutputWithMode(strings.NewReader(input), scanModeBinary)
+	fromBinary, binaryErr := parseGovulncheckOutputWithMode(scanModeBinary, strings.NewReader(input))
 	if binaryErr != nil {
 		t.Fatalf("parseGovulncheckOutputWithMode binary returned error: %v", binaryErr)
 	}
@@ -137,6 +138,57 @@ func TestParseGovulncheckOutputWithModeBinaryFindingsAreReachable(t *testing.T)
 	}
 }
 
+// TestParseGovulncheckOutputWithModeMergesInputs verifies the parse govulncheck output with mode merges inputs scenario.
+func TestParseGovulncheckOutputWithModeMergesInputs(t *testing.T) {
+	t.Parallel()
+
+	moduleA := `{"osv":{"id":"GO-SHARED","aliases":["CVE-2026-0001"],"summary":"shared finding"}}` + "\n" +
+		`{"finding":{"osv":"GO-SHARED","fixed_version":"v1.2.0","trace":[{"package":"example.test/lib"}]}}` + "\n" +
+		`{"osv":{"id":"GO-ONLY-A"}}`
+	moduleB := `{"osv":{"id":"GO-SHARED","aliases":["GHSA-aaaa-bbbb-cccc"]}}` + "\n" +
+		`{"finding":{"osv":"GO-SHARED","fixed_version":"v1.3.0","trace":[{"package":"example.test/lib","function":"Parse"},{"package":"example.test/app","function":"main"}]}}`
+
+	vulns, err := parseGovulncheckOutputWithMode(scanModeSource, strings.NewReader(moduleA), strings.NewReader(moduleB))
+	if err != nil {
+		t.Fatalf("parseGovulncheckOutputWithMode returned error: %v", err)
+	}
+	if len(vulns) != 2 || vulns[0].ID != "GO-ONLY-A" || vulns[1].ID != "GO-SHARED" {
+		t.Fatalf("expected two deduplicated vulnerabilities, got %#v", vulns)
+	}
+	shared := vulns[1]
+	if !shared.Reachable || len(shared.ReachableTraces) != 1 {
+		t.Fatalf("expected conflicting reachability to resolve to reachable, got %#v", shared)
+	}
+	if !reflect.DeepEqual(shared.Aliases, []string{"CVE-2026-0001", "GHSA-aaaa-bbbb-cccc"}) {
+		t.Fatalf("expected aliases from both inputs, got %#v", shared.Aliases)
+	}
+	if !reflect.DeepEqual(shared.FixedVersions, []string{"v1.2.0", "v1.3.0"}) {
+		t.Fatalf("expected fixed versions from both inputs, got %#v", shared.FixedVersions)
+	}
+
+	if _, err = parseGovulncheckOutputWithMode(scanModeSource, strings.NewReader(moduleA), strings.NewReader("{")); err == nil || !strings.Contains(err.Error(), "input 2") {
+		t.Fatalf("expected decode error to name the second input, got %v", err)
+	}
+}
+
+// TestInputFlagAcceptsRepeatedAndCommaSeparatedPaths verifies the input flag accepts repeated and comma separated paths scenario.
+func TestInputFlagAcceptsRepeatedAndCommaSeparatedPaths(t *testing.T) {
+	t.Parallel()
+
+	flagSet := flag.NewFlagSet("vulnpolicy", flag.ContinueOnError)
+	flags := registerCLIFlags(flagSet)
+	if err := flagSet.Parse([]string{"-input", "a.json, b.json", "-input", "c.json", "-overrides", "overrides.json"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	config, err := flags.config()
+	if err != nil {
+		t.Fatalf("build config: %v", err)
+	}
+	if !reflect.DeepEqual(config.inputPaths, []string{"a.json", "b.json", "c.json"}) {
+		t.Fatalf("unexpected input paths: %#v", config.inputPaths)
+	}
+}
+
 // TestLoadOverrides verifies the load overrides scenario.
 func TestLoadOverrides(t *testing.T) {
 	t.Parallel()
@@ -164,7 +216,7 @@ func assertLoadOverridesRequiredFields(t *testing.T, tempDir string) {
 }`
 	writeOverrideFixture(t, path, content)
 
-	overrides, err := loadOverrides(path)
+	overrides, _, err := loadOverrides(path)
 	if err != nil {
 		t.Fatalf("loadOverrides returned error: %v", err)
 	}
@@ -210,7 +262,7 @@ func assertLoadOverridesOptionalFields(t *testing.T, tempDir string) {
 }`
 	writeOverrideFixture(t, path, content)
 
-	overrides, err := loadOverrides(path)
+	overrides, _, err := loadOverrides(path)
 	if err != nil {
 		t.Fatalf("loadOverrides returned error: %v", err)
 	}
@@ -277,6 +329,26 @@ func assertLoadOverridesInvalidInputs(t *testing.T, tempDir string) {
 			filename: "invalid-approved-date.json",
 			content:  `{"overrides":[{"id":"GO-1","reason":"x","expires_on":"2026-03-01","owner":"@a","tracking_ticket":"SEC-1","scope":"backend","approved_date":"03/01/2026"}]}`,
 		},
+		{
+			name:     "both id and package",
+			filename: "invalid-id-and-package.json",
+			content:  `{"overrides":[{"id":"GO-1","package":"example.com/mod/pkg","reason":"x","expires_on":"2026-03-01","owner":"@a","tracking_ticket":"SEC-1","scope":"backend"}]}`,
+		},
+		{
+			name:     "function without package",
+			filename: "invalid-function-without-package.json",
+			content:  `{"overrides":[{"function":"Parse","reason":"x","expires_on":"2026-03-01","owner":"@a","tracking_ticket":"SEC-1","scope":"backend"}]}`,
+		},
+		{
+			name:     "duplicate override paths",
+			filename: "invalid-duplicate-path.json",
+			content: `{
+  "overrides": [
+    {"package": "example.com/mod/pkg", "function": "Parse", "reason": "a", "expires_on": "2026-03-01", "owner": "@a", "tracking_ticket": "SEC-1", "scope": "backend"},
+    {"package": " example.com/mod/pkg ", "function": "Parse", "reason": "b", "expires_on": "2026-03-10", "owner": "@b", "tracking_ticket": "SEC-2", "scope": "backend"}
+  ]
+}`,
+		},
 		{
 			name:     "invalid severity",
 			filename: "invalid-severity.json",
@@ -288,7 +360,7 @@ func assertLoadOverridesInvalidInputs(t *testing.T, tempDir string) {
 		t.Run(testCase.name, func(t *testing.T) {
 			path := filepath.Join(tempDir, testCase.filename)
 			writeOverrideFixture(t, path, testCase.content)
-			if _, err := loadOverrides(path); err == nil {
+			if _, _, err := loadOverrides(path); err == nil {
 				t.Fatalf("expected error for %s", testCase.name)
 			}
 		})
@@ -339,6 +411,85 @@ func TestNormalizeScanMode(t *testing.T) {
 	}
 }
 
+// TestParseSeverityBands verifies the parse severity bands scenario.
+func TestParseSeverityBands(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		input   string
+		want    severityBands
+		wantErr bool
+	}{
+		{name: "empty keeps defaults", input: "", want: defaultSeverityBands},
+		{name: "full", input: "critical=8.0,high=6.5,medium=3", want: severityBands{Critical: 8, High: 6.5, Medium: 3}},
+		{name: "partial with spaces", input: " Critical = 8.0 ", want: severityBands{Critical: 8, High: 7, Medium: 4}},
+		{name: "missing separator", input: "critical8", wantErr: true},
+		{name: "invalid score", input: "high=abc", wantErr: true},
+		{name: "unknown name", input: "low=1", wantErr: true},
+		{name: "not decreasing", input: "critical=7,high=7", wantErr: true},
+		{name: "medium not positive", input: "medium=0", wantErr: true},
+		{name: "critical above ten", input: "critical=10.5", wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSeverityBands(testCase.input)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for input %q", testCase.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSeverityBands returned error for %q: %v", testCase.input, err)
+			}
+			if got != testCase.want {
+				t.Fatalf("parseSeverityBands(%q) = %+v, want %+v", testCase.input, got, testCase.want)
+			}
+		})
+	}
+}
+
+// TestSeverityBandsReclassifyScores verifies the severity bands reclassify scores scenario.
+func TestSeverityBandsReclassifyScores(t *testing.T) {
+	t.Parallel()
+
+	if got := normalizeSeverity("", 8.5); got != severityHigh {
+		t.Fatalf("expected default bands to keep 8.5 HIGH, got %s", got)
+	}
+	custom, err := parseSeverityBands("critical=8.0")
+	if err != nil {
+		t.Fatalf("parse custom bands: %v", err)
+	}
+	if got := custom.classify(8.5); got != severityCritical {
+		t.Fatalf("expected custom bands to classify 8.5 as CRITICAL, got %s", got)
+	}
+
+	inner := &fakeSeverityResolver{byID: map[string]severityAssessment{
+		"GO-SCORED":   {Severity: severityHigh, Score: 8.5, Method: severityMethodNVD},
+		"GO-UNSCORED": {Severity: severityHigh, Method: severityMethodGHSA},
+	}}
+	if resolver := withSeverityBands(inner, defaultSeverityBands); resolver != severityResolver(inner) {
+		t.Fatal("expected default bands to keep the resolver unwrapped")
+	}
+
+	resolver := withSeverityBands(inner, custom)
+	scored, err := resolver.Resolve(context.Background(), vulnAssessment{ID: "GO-SCORED"})
+	if err != nil || scored.Severity != severityCritical || scored.Method != severityMethodNVD {
+		t.Fatalf("expected scored finding to be reclassified as CRITICAL, got %#v err=%v", scored, err)
+	}
+	unscored, err := resolver.Resolve(context.Background(), vulnAssessment{ID: "GO-UNSCORED"})
+	if err != nil || unscored.Severity != severityHigh {
+		t.Fatalf("expected unscored finding to keep its label, got %#v err=%v", unscored, err)
+	}
+	if got := custom.String(); got != "critical=8,high=7,medium=4" {
+		t.Fatalf("unexpected bands string %q", got)
+	}
+}
+
 // TestEvaluateVulnerabilities verifies the evaluate vulnerabilities scenario.
 func TestEvaluateVulnerabilities(t *testing.T) {
 	t.Parallel()
@@ -375,7 +526,7 @@ func TestEvaluateVulnerabilities(t *testing.T) {
 		},
 	}
 
-	result := evaluateVulnerabilities(context.Background(), vulns, overrides, resolver, now)
+	result := evaluateVulnerabilities(context.Background(), vulns, overrideSet{byID: overrides}, resolver, now)
 
 	if len(result.Fail) != 1 || result.Fail[0].Vuln.ID != "GO-A" {
 		t.Fatalf("unexpected fail list: %#v", result.Fail)
@@ -398,6 +549,90 @@ func TestEvaluateVulnerabilities(t *testing.T) {
 	}
 }
 
+// TestEvaluateVulnerabilitiesStrictOverrideMatch verifies the evaluate vulnerabilities strict override match scenario.
+func TestEvaluateVulnerabilitiesStrictOverrideMatch(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+	vulns := []vulnAssessment{{ID: "GO-2026-0001", Aliases: []string{"cve-2026-1111"}, Reachable: true}}
+	overrides := map[string]riskOverride{
+		"CVE-2026-1111": {
+			ID:        "CVE-2026-1111",
+			Reason:    "accepted via CVE",
+			ExpiresOn: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	resolver := &fakeSeverityResolver{byID: map[string]severityAssessment{
+		"GO-2026-0001": {Severity: severityHigh, Score: testScoreEightPointOne},
+	}}
+
+	aliasAware := evaluateVulnerabilities(context.Background(), vulns, overrideSet{byID: overrides}, resolver, now)
+	if len(aliasAware.Accepted) != 1 || aliasAware.Accepted[0].MatchedByID != "CVE-2026-1111" {
+		t.Fatalf("expected default matching to accept via CVE alias, got %#v", aliasAware)
+	}
+
+	strict := evaluateVulnerabilities(context.Background(), vulns, overrideSet{byID: overrides, strict: true}, resolver, now)
+	if len(strict.Accepted) != 0 || len(strict.Fail) != 1 || strict.Fail[0].Vuln.ID != "GO-2026-0001" {
+		t.Fatalf("expected strict matching to ignore the alias override, got %#v", strict)
+	}
+
+	overrides["GO-2026-0001"] = riskOverride{ID: "GO-2026-0001", Reason: "exact", ExpiresOn: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)}
+	exact := evaluateVulnerabilities(context.Background(), vulns, overrideSet{byID: overrides, strict: true}, resolver, now)
+	if len(exact.Accepted) != 1 || exact.Accepted[0].MatchedByID != "GO-2026-0001" {
+		t.Fatalf("expected strict matching to accept the exact ID, got %#v", exact)
+	}
+}
+
+// TestEvaluateVulnerabilitiesPathOverride verifies the evaluate vulnerabilities path override scenario.
+func TestEvaluateVulnerabilitiesPathOverride(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Join([]string{
+		`{"finding":{"osv":"GO-SUPPRESSED","trace":[{"package":"golang.org/x/net/html","function":"Parse"},{"package":"plato/backend/internal/sanitize","function":"Clean"},{"package":"plato/backend/cmd/plato","function":"main"}]}}`,
+		`{"finding":{"osv":"GO-SUPPRESSED","trace":[{"package":"golang.org/x/net/html","function":"Render"},{"package":"plato/backend/internal/sanitize","function":"Clean"}]}}`,
+		`{"finding":{"osv":"GO-OPEN","trace":[{"package":"golang.org/x/net/html","function":"Parse"},{"package":"plato/backend/internal/sanitize","function":"Clean"}]}}`,
+		`{"finding":{"osv":"GO-OPEN","trace":[{"package":"golang.org/x/net/html","function":"Parse"},{"package":"plato/backend/internal/httpapi","function":"render"}]}}`,
+	}, "\n")
+	vulns, err := parseGovulncheckOutput(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseGovulncheckOutput returned error: %v", err)
+	}
+	if len(vulns) != 2 || len(vulns[1].ReachableTraces) != 2 {
+		t.Fatalf("expected reachable traces to be retained, got %#v", vulns)
+	}
+
+	path := filepath.Join(t.TempDir(), "path-overrides.json")
+	writeOverrideFixture(t, path, `{"overrides":[{"package":"plato/backend/internal/sanitize","function":"Clean","reason":"input is escaped before parsing","expires_on":"2026-03-01","owner":"@a","tracking_ticket":"SEC-1","scope":"backend"}]}`)
+	byID, byPath, err := loadOverrides(path)
+	if err != nil {
+		t.Fatalf("loadOverrides returned error: %v", err)
+	}
+	if len(byID) != 0 || len(byPath) != 1 || byPath[0].Package != "plato/backend/internal/sanitize" || byPath[0].Function != "Clean" {
+		t.Fatalf("expected one path override, got byID=%#v byPath=%#v", byID, byPath)
+	}
+
+	resolver := &fakeSeverityResolver{byID: map[string]severityAssessment{
+		"GO-OPEN": {Severity: severityHigh, Score: testScoreEightPointOne},
+	}}
+	now := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+	result := evaluateVulnerabilities(context.Background(), vulns, overrideSet{byID: byID, byPath: byPath}, resolver, now)
+	if len(result.Accepted) != 1 || result.Accepted[0].Vuln.ID != "GO-SUPPRESSED" {
+		t.Fatalf("expected finding reachable only through the suppressed package to be accepted, got %#v", result)
+	}
+	accepted := result.Accepted[0]
+	if accepted.MatchedByID != "plato/backend/internal/sanitize.Clean" || accepted.Override.Reason != "input is escaped before parsing" {
+		t.Fatalf("unexpected path override match: %#v", accepted)
+	}
+	if len(result.Fail) != 1 || result.Fail[0].Vuln.ID != "GO-OPEN" {
+		t.Fatalf("expected finding with an unsuppressed trace to fail, got %#v", result.Fail)
+	}
+
+	expired := evaluateVulnerabilities(context.Background(), vulns, overrideSet{byPath: byPath}, resolver, time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC))
+	if len(expired.Expired) != 1 || expired.Expired[0].Vuln.ID != "GO-SUPPRESSED" {
+		t.Fatalf("expected expired path override to be reported, got %#v", expired)
+	}
+}
+
 // TestCollectCVEIDs verifies the collect CVE IDs scenario.
 func TestCollectCVEIDs(t *testing.T) {
 	t.Parallel()
@@ -835,7 +1070,7 @@ func TestLoadOverridesErrorPaths(t *testing.T) {
 
 	t.Run("missing file", func(t *testing.T) {
 		t.Parallel()
-		if _, loadErr := loadOverrides(filepath.Join(tempDir, "missing.json")); loadErr == nil {
+		if _, _, loadErr := loadOverrides(filepath.Join(tempDir, "missing.json")); loadErr == nil {
 			t.Fatal("expected missing file error")
 		}
 	})
@@ -846,7 +1081,7 @@ func TestLoadOverridesErrorPaths(t *testing.T) {
 		if err := os.WriteFile(path, []byte(`{"overrides":[`), 0o600); err != nil {
 			t.Fatalf(errWriteInvalidFileFmt, err)
 		}
-		if _, loadErr := loadOverrides(path); loadErr == nil {
+		if _, _, loadErr := loadOverrides(path); loadErr == nil {
 			t.Fatal("expected invalid json error")
 		}
 	})
@@ -858,7 +1093,7 @@ func TestLoadOverridesErrorPaths(t *testing.T) {
 		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
 			t.Fatalf(errWriteInvalidFileFmt, err)
 		}
-		if _, loadErr := loadOverrides(path); loadErr == nil {
+		if _, _, loadErr := loadOverrides(path); loadErr == nil {
 			t.Fatal("expected missing id error")
 		}
 	})
@@ -870,7 +1105,7 @@ func TestLoadOverridesErrorPaths(t *testing.T) {
 		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
 			t.Fatalf(errWriteInvalidFileFmt, err)
 		}
-		if _, loadErr := loadOverrides(path); loadErr == nil {
+		if _, _, loadErr := loadOverrides(path); loadErr == nil {
 			t.Fatal("expected invalid expires_on error")
 		}
 	})
@@ -919,7 +1154,7 @@ func TestEvaluateVulnerabilitiesUnknownSeverity(t *testing.T) {
 		errID: map[string]error{},
 	}
 
-	result := evaluateVulnerabilities(context.Background(), vulns, nil, resolver, now)
+	result := evaluateVulnerabilities(context.Background(), vulns, overrideSet{}, resolver, now)
 
 	if len(result.Warn) != 1 || result.Warn[0].Vuln.ID != "GO-LOW" {
 		t.Fatalf("unexpected warn list: %#v", result.Warn)
@@ -1161,6 +1396,148 @@ func newTestResolver(client *http.Client, baseURL, apiKey string) *nvdSeverityRe
 	}
 }
 
+// TestResolveFallsBackToOSVLookupForGoIDWithoutAliases verifies the resolve falls back to OSV lookup for Go ID without aliases scenario.
+func TestResolveFallsBackToOSVLookupForGoIDWithoutAliases(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		calls.Add(1)
+		if got := request.URL.Path; got != "/v1/vulns/GO-2026-0001" {
+			t.Fatalf("unexpected OSV path: %s", got)
+		}
+		writer.Header().Set(testHeaderContentType, contentTypeJSON)
+		_, writeErr := fmt.Fprint(
+			writer,
+			`{"id":"GO-2026-0001","severity":[{"type":"CVSS_V3","score":"CVSS:3.1/AV:N/SCORE:8.1"}]}`,
+		)
+		if writeErr != nil {
+			t.Fatalf(errWriteResponseFmt, writeErr)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	resolver := &nvdSeverityResolver{
+		client:     server.Client(),
+		osvBaseURL: server.URL + "/v1/vulns",
+		osvLookup:  true,
+		snapshot:   map[string]severityAssessment{},
+		cache:      map[string]severityAssessment{},
+		errorMap:   map[string]error{},
+	}
+
+	vuln := vulnAssessment{ID: "go-2026-0001"}
+	assessment, err := resolver.Resolve(context.Background(), vuln)
+	if err != nil {
+		t.Fatalf("unexpected OSV lookup error: %v", err)
+	}
+	if assessment.Severity != severityHigh || assessment.Score != 8.1 || assessment.Method != severityMethodOSV || assessment.Source != "GO-2026-0001" {
+		t.Fatalf("unexpected OSV lookup assessment: %#v", assessment)
+	}
+
+	if _, err = resolver.Resolve(context.Background(), vuln); err != nil {
+		t.Fatalf("unexpected cached OSV lookup error: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected one OSV upstream call, got %d", calls.Load())
+	}
+}
+
+// TestResolveSkipsOSVLookupWhenDisabled verifies the resolve skips OSV lookup when disabled scenario.
+func TestResolveSkipsOSVLookupWhenDisabled(t *testing.T) {
+	t.Parallel()
+
+	resolver := &nvdSeverityResolver{
+		osvBaseURL: testInvalidURL,
+		snapshot:   map[string]severityAssessment{},
+		cache:      map[string]severityAssessment{},
+		errorMap:   map[string]error{},
+	}
+
+	assessment, err := resolver.Resolve(context.Background(), vulnAssessment{ID: "GO-2026-0001"})
+	if err != nil {
+		t.Fatalf("expected no lookup error when OSV lookup is disabled, got %v", err)
+	}
+	if assessment.Severity != severityUnknown || strings.Contains(assessment.Reason, "OSV lookup") {
+		t.Fatalf("unexpected assessment without OSV lookup: %#v", assessment)
+	}
+}
+
+// TestResolveOSVLookupOfflineUsesSnapshot verifies the resolve OSV lookup offline uses snapshot scenario.
+func TestResolveOSVLookupOfflineUsesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	resolver := &nvdSeverityResolver{
+		osvLookup: true,
+		offline:   true,
+		snapshot: map[string]severityAssessment{
+			"GO-2026-0001": {Severity: severityMedium, Score: 5.5, Source: "GO-2026-0001", Method: severityMethodOSV},
+		},
+		cache:    map[string]severityAssessment{},
+		errorMap: map[string]error{},
+	}
+
+	assessment, err := resolver.Resolve(context.Background(), vulnAssessment{ID: "GO-2026-0001"})
+	if err != nil {
+		t.Fatalf("unexpected offline OSV snapshot error: %v", err)
+	}
+	if assessment.Severity != severityMedium || assessment.Method != severityMethodOSV {
+		t.Fatalf("unexpected offline OSV snapshot assessment: %#v", assessment)
+	}
+
+	missing, missingErr := resolver.Resolve(context.Background(), vulnAssessment{ID: "GO-2026-0002"})
+	if missingErr == nil || !strings.Contains(missingErr.Error(), "offline mode enabled") {
+		t.Fatalf("expected offline OSV error, got %v", missingErr)
+	}
+	if missing.Severity != severityUnknown || !strings.Contains(missing.Reason, "OSV lookup failed") {
+		t.Fatalf("unexpected offline OSV assessment: %#v", missing)
+	}
+}
+
+// TestResolveOSVLookupResponseErrors verifies the resolve OSV lookup response errors scenario.
+func TestResolveOSVLookupResponseErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{name: "not found", status: http.StatusNotFound, body: `{}`, want: "HTTP 404"},
+		{name: "no severity", status: http.StatusOK, body: `{"id":"GO-2026-0001"}`, want: "no severity data"},
+		{name: "decode", status: http.StatusOK, body: `{`, want: "unexpected EOF"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(testCase.status)
+				_, _ = fmt.Fprint(writer, testCase.body)
+			}))
+			t.Cleanup(server.Close)
+
+			resolver := &nvdSeverityResolver{
+				client:     server.Client(),
+				osvBaseURL: server.URL,
+				osvLookup:  true,
+				cache:      map[string]severityAssessment{},
+				errorMap:   map[string]error{},
+			}
+
+			assessment, err := resolver.resolveOSV(context.Background(), "GO-2026-0001")
+			if err == nil || !strings.Contains(err.Error(), testCase.want) {
+				t.Fatalf("expected error containing %q, got %v", testCase.want, err)
+			}
+			if assessment.Severity != severityUnknown {
+				t.Fatalf("expected UNKNOWN severity, got %#v", assessment)
+			}
+		})
+	}
+}
+
 // TestResolveGHSASuccessfulLookupIsCachedWithoutToken verifies the resolve GHSA successful lookup is cached without token scenario.
 func TestResolveGHSASuccessfulLookupIsCachedWithoutToken(t *testing.T) {
 	t.Parallel()
@@ -1651,6 +2028,151 @@ func TestResolveCVESuccessfulLookupIsCached(t *testing.T) {
 	}
 }
 
+// TestResolveCVESpacesConsecutiveNVDRequests verifies the resolve CVE spaces consecutive NVD requests scenario.
+func TestResolveCVESpacesConsecutiveNVDRequests(t *testing.T) {
+	t.Parallel()
+
+	const interval = 50 * time.Millisecond
+	var (
+		mu       sync.Mutex
+		requests []time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		requests = append(requests, time.Now())
+		mu.Unlock()
+		writer.Header().Set(testHeaderContentType, contentTypeJSON)
+		_, _ = writer.Write([]byte(`{"vulnerabilities":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	resolver := newTestResolver(server.Client(), server.URL, "")
+	resolver.nvdRequestInterval = interval
+	for _, cveID := range []string{"CVE-2026-3001", "CVE-2026-3002", "CVE-2026-3003"} {
+		_, _ = resolver.resolveCVE(context.Background(), cveID)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 3 {
+		t.Fatalf("expected three upstream requests, got %d", len(requests))
+	}
+	for index := 1; index < len(requests); index++ {
+		if gap := requests[index].Sub(requests[index-1]); gap < interval {
+			t.Fatalf("expected requests %d and %d to be at least %s apart, got %s", index-1, index, interval, gap)
+		}
+	}
+}
+
+// TestWaitForNVDSlotRespectsCancellation verifies the wait for NVD slot respects cancellation scenario.
+func TestWaitForNVDSlotRespectsCancellation(t *testing.T) {
+	t.Parallel()
+
+	resolver := &nvdSeverityResolver{nvdRequestInterval: time.Hour, lastNVDRequest: time.Now()}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := resolver.waitForNVDSlot(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context cancellation, got %v", err)
+	}
+	if err := (&nvdSeverityResolver{}).waitForNVDSlot(ctx); err != nil {
+		t.Fatalf("expected zero interval to skip pacing, got %v", err)
+	}
+}
+
+// TestParseNVDRequestInterval verifies the parse NVD request interval scenario.
+func TestParseNVDRequestInterval(t *testing.T) {
+	t.Parallel()
+
+	if interval, err := parseNVDRequestInterval(""); err != nil || interval >= 0 {
+		t.Fatalf("expected empty value to select the default, got %s err=%v", interval, err)
+	}
+	if interval, err := parseNVDRequestInterval("2s"); err != nil || interval != 2*time.Second {
+		t.Fatalf("expected 2s, got %s err=%v", interval, err)
+	}
+	if _, err := parseNVDRequestInterval("-1s"); err == nil {
+		t.Fatal("expected negative interval to be rejected")
+	}
+	if _, err := parseNVDRequestInterval("soon"); err == nil {
+		t.Fatal("expected malformed interval to be rejected")
+	}
+	if defaultNVDRequestInterval(false) <= defaultNVDRequestInterval(true) {
+		t.Fatal("expected unauthenticated default interval to exceed the keyed default")
+	}
+}
+
+// TestSeverityCacheExpiresErrorsBeforeSuccesses verifies the severity cache expires errors before successes scenario.
+func TestSeverityCacheExpiresErrorsBeforeSuccesses(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache", "severity-cache.json")
+	writtenAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	ttls := severityCacheTTLs{success: 24 * time.Hour, failure: time.Hour}
+	resolver := &nvdSeverityResolver{
+		cache: map[string]severityAssessment{
+			"CVE-2026-1000": {Severity: severityHigh, Score: testScoreEightPointOne, Source: "CVE-2026-1000", Method: severityMethodNVD},
+			testCVE20261001: {Severity: severityUnknown, Source: testCVE20261001},
+		},
+		errorMap: map[string]error{
+			testCVE20261001: errors.New("NVD returned status 503"),
+		},
+	}
+	if err := resolver.saveSeverityCache(path, ttls, writtenAt); err != nil {
+		t.Fatalf("saveSeverityCache returned error: %v", err)
+	}
+
+	fresh, err := loadSeverityCache(path, writtenAt.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("loadSeverityCache returned error: %v", err)
+	}
+	if len(fresh) != 2 || fresh[testCVE20261001].Error == "" {
+		t.Fatalf("expected both entries before the error TTL, got %#v", fresh)
+	}
+
+	later, err := loadSeverityCache(path, writtenAt.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("loadSeverityCache returned error: %v", err)
+	}
+	if _, ok := later[testCVE20261001]; ok {
+		t.Fatalf("expected cached error to expire after the error TTL, got %#v", later)
+	}
+	success, ok := later["CVE-2026-1000"]
+	if !ok || success.Severity != severityHigh || success.Score != testScoreEightPointOne {
+		t.Fatalf("expected cached success to persist, got %#v", later)
+	}
+
+	reloaded := &nvdSeverityResolver{
+		cache:       map[string]severityAssessment{},
+		errorMap:    map[string]error{},
+		cacheExpiry: map[string]time.Time{},
+	}
+	reloaded.seedCache(later)
+	cached, found, cachedErr := reloaded.readCache("CVE-2026-1000")
+	if !found || cachedErr != nil || cached.Method != severityMethodNVD {
+		t.Fatalf("expected seeded success in the resolver cache, got %#v found=%v err=%v", cached, found, cachedErr)
+	}
+	if err = reloaded.saveSeverityCache(path, ttls, writtenAt.Add(2*time.Hour)); err != nil {
+		t.Fatalf("saveSeverityCache returned error: %v", err)
+	}
+	resaved, err := loadSeverityCache(path, writtenAt.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("loadSeverityCache returned error: %v", err)
+	}
+	if !resaved["CVE-2026-1000"].ExpiresAt.Equal(writtenAt.Add(24 * time.Hour)) {
+		t.Fatalf("expected a reloaded entry to keep its expiry, got %s", resaved["CVE-2026-1000"].ExpiresAt)
+	}
+}
+
+// TestLoadSeverityCacheMissingFileIsEmpty verifies the load severity cache missing file is empty scenario.
+func TestLoadSeverityCacheMissingFileIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	entries, err := loadSeverityCache(filepath.Join(t.TempDir(), "missing.json"), time.Now())
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected an empty cache for a missing file, got %#v err=%v", entries, err)
+	}
+}
+
 // TestResolveCVERetryableStatusEventuallyFails verifies the resolve CVE retryable status eventually fails scenario.
 func TestResolveCVERetryableStatusEventuallyFails(t *testing.T) {
 	t.Parallel()
@@ -2135,6 +2657,59 @@ func TestPrintResult(t *testing.T) {
 	}
 }
 
+// TestOverrideExpiryWarningWindow verifies the override expiry warning window scenario.
+func TestOverrideExpiryWarningWindow(t *testing.T) {
+	now := time.Date(2026, time.February, 22, 12, 0, 0, 0, time.UTC)
+	vulns := []vulnAssessment{
+		{ID: "GO-SOON", Reachable: true},
+		{ID: "GO-LATER", Reachable: true},
+	}
+	overrides := map[string]riskOverride{
+		"GO-SOON":  {ID: "GO-SOON", Reason: "patch pending", ExpiresOn: time.Date(2026, time.February, 25, 0, 0, 0, 0, time.UTC)},
+		"GO-LATER": {ID: "GO-LATER", Reason: "patch planned", ExpiresOn: time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	result := evaluateVulnerabilities(
+		context.Background(),
+		vulns,
+		overrideSet{byID: overrides, expiryWarning: 7 * 24 * time.Hour},
+		&fakeSeverityResolver{},
+		now,
+	)
+	if len(result.Expired) != 0 || len(result.Accepted) != 2 {
+		t.Fatalf("expected both overrides accepted and none expired, got %#v", result)
+	}
+	for _, item := range result.Accepted {
+		if item.ExpiringSoon != (item.Vuln.ID == "GO-SOON") {
+			t.Fatalf("unexpected expiring soon flag for %s: %v", item.Vuln.ID, item.ExpiringSoon)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		printResult(scanModeSource, result)
+	})
+	if strings.Contains(output, "Expired overrides") {
+		t.Fatalf("expected no expired section, got:\n%s", output)
+	}
+	expiringSection, acceptedSection, found := strings.Cut(output, "Accepted risk overrides")
+	if !found || !strings.Contains(expiringSection, "Expiring soon") || !strings.Contains(expiringSection, "GO-SOON override GO-SOON expires on 2026-02-25") {
+		t.Fatalf("expected GO-SOON in the expiring soon section, got:\n%s", output)
+	}
+	if strings.Contains(acceptedSection, "GO-SOON") || !strings.Contains(acceptedSection, "GO-LATER accepted by GO-LATER") {
+		t.Fatalf("expected only GO-LATER in the accepted section, got:\n%s", output)
+	}
+
+	report := buildScanReport(scanModeSource, now, result, reportConfiguration{})
+	if report.Summary.ExpiringSoon != 1 || report.Summary.Accepted != 2 || report.Summary.Blocking != 0 {
+		t.Fatalf("unexpected report summary: %#v", report.Summary)
+	}
+	for _, finding := range report.Findings.Accepted {
+		if finding.ExpiringSoon != (finding.ID == "GO-SOON") {
+			t.Fatalf("unexpected expiring_soon flag for %s in report", finding.ID)
+		}
+	}
+}
+
 // TestPrintResultBinaryInfoHeading verifies the print result binary info heading scenario.
 func TestPrintResultBinaryInfoHeading(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"plato/backend/internal/ports"
+)
+
+// HeaderAPIKey carries an API key issued through the key management
+// endpoints.
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyResolver maps a plain API key to the auth context it grants.
+type APIKeyResolver interface {
+	ResolveAPIKey(ctx context.Context, key string) (ports.AuthContext, error)
+}
+
+// APIKeyAuthProvider authenticates requests that send X-API-Key and hands
+// every other request to the next provider.
+type APIKeyAuthProvider struct {
+	resolver APIKeyResolver
+	next     ports.AuthProvider
+}
+
+// NewAPIKeyAuthProvider returns a provider that resolves API keys with
+// resolver and falls back to next.
+func NewAPIKeyAuthProvider(resolver APIKeyResolver, next ports.AuthProvider) *APIKeyAuthProvider {
+	return &APIKeyAuthProvider{resolver: resolver, next: next}
+}
+
+// FromRequest resolves X-API-Key when present. A request that sends a key
+// never falls back to other credentials, so a bad key always fails.
+func (p *APIKeyAuthProvider) FromRequest(r *http.Request) (ports.AuthContext, error) {
+	if p == nil || p.resolver == nil || p.next == nil {
+		return ports.AuthContext{}, errors.New("auth provider is nil")
+	}
+
+	key := strings.TrimSpace(r.Header.Get(HeaderAPIKey))
+	if key == "" {
+		return p.next.FromRequest(r)
+	}
+	return p.resolver.ResolveAPIKey(r.Context(), key)
+}
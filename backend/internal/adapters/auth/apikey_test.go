@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"plato/backend/internal/ports"
+)
+
+type staticKeyResolver map[string]ports.AuthContext
+
+func (r staticKeyResolver) ResolveAPIKey(_ context.Context, key string) (ports.AuthContext, error) {
+	authCtx, ok := r[key]
+	if !ok {
+		return ports.AuthContext{}, errors.New("unknown key")
+	}
+	return authCtx, nil
+}
+
+type fallbackProvider struct{}
+
+func (fallbackProvider) FromRequest(_ *http.Request) (ports.AuthContext, error) {
+	return ports.AuthContext{UserID: testFallbackValue}, nil
+}
+
+// TestAPIKeyAuthProviderFromRequest verifies the API key auth provider from request scenario.
+func TestAPIKeyAuthProviderFromRequest(t *testing.T) {
+	provider := NewAPIKeyAuthProvider(staticKeyResolver{
+		"plato_good": {UserID: "api_key:api_key_1", OrganisationID: "org_1", Roles: []string{"org_user"}},
+	}, fallbackProvider{})
+
+	request := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", http.NoBody)
+	request.Header.Set(HeaderAPIKey, " plato_good ")
+	authCtx, err := provider.FromRequest(request)
+	if err != nil || authCtx.OrganisationID != "org_1" || authCtx.UserID != "api_key:api_key_1" {
+		t.Fatalf("expected the key's auth context, got %+v err=%v", authCtx, err)
+	}
+
+	request.Header.Set(HeaderAPIKey, "plato_bad")
+	if _, err = provider.FromRequest(request); err == nil {
+		t.Fatal("expected an unknown key to fail without falling back")
+	}
+
+	request.Header.Del(HeaderAPIKey)
+	authCtx, err = provider.FromRequest(request)
+	if err != nil || authCtx.UserID != testFallbackValue {
+		t.Fatalf("expected requests without a key to use the next provider, got %+v err=%v", authCtx, err)
+	}
+
+	var missing *APIKeyAuthProvider
+	if _, err = missing.FromRequest(request); err == nil {
+		t.Fatal("expected a nil provider to fail")
+	}
+}
@@ -1,2 +1,2 @@
-// Package auth provides development and JWT authentication adapters.
+// Package auth provides development, JWT, and OIDC authentication adapters.
 package auth
@@ -74,20 +74,9 @@ func (p *JWTAuthProvider) FromRequest(r *http.Request) (ports.AuthContext, error
 		return ports.AuthContext{}, errors.New("auth provider is nil")
 	}
 
-	authorizationParts := strings.Fields(strings.TrimSpace(r.Header.Get(headerAuthorization)))
-	if len(authorizationParts) == 0 || !strings.EqualFold(authorizationParts[0], strings.TrimSpace(bearerPrefix)) {
-		return ports.AuthContext{}, errors.New("missing bearer token")
-	}
-	if len(authorizationParts) == 1 {
-		return ports.AuthContext{}, errors.New("empty bearer token")
-	}
-	if len(authorizationParts) > 2 {
-		return ports.AuthContext{}, errors.New("invalid bearer token format")
-	}
-
-	token := strings.TrimSpace(authorizationParts[1])
-	if token == "" {
-		return ports.AuthContext{}, errors.New("empty bearer token")
+	token, err := bearerToken(r)
+	if err != nil {
+		return ports.AuthContext{}, err
 	}
 
 	claims, err := p.parseAndValidateToken(token)
@@ -123,6 +112,26 @@ func (p *JWTAuthProvider) FromRequest(r *http.Request) (ports.AuthContext, error
 	}, nil
 }
 
+// bearerToken returns the token of a "Bearer <token>" Authorization header.
+func bearerToken(r *http.Request) (string, error) {
+	authorizationParts := strings.Fields(strings.TrimSpace(r.Header.Get(headerAuthorization)))
+	if len(authorizationParts) == 0 || !strings.EqualFold(authorizationParts[0], strings.TrimSpace(bearerPrefix)) {
+		return "", errors.New("missing bearer token")
+	}
+	if len(authorizationParts) == 1 {
+		return "", errors.New("empty bearer token")
+	}
+	if len(authorizationParts) > 2 {
+		return "", errors.New("invalid bearer token format")
+	}
+
+	token := strings.TrimSpace(authorizationParts[1])
+	if token == "" {
+		return "", errors.New("empty bearer token")
+	}
+	return token, nil
+}
+
 func (p *JWTAuthProvider) parseAndValidateToken(token string) (map[string]any, error) {
 	parts := strings.Split(token, ".")
 	if len(parts) != 3 {
@@ -0,0 +1,404 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"plato/backend/internal/ports"
+)
+
+const (
+	defaultOIDCOrganisationClaim = "org_id"
+	defaultOIDCRolesClaim        = "roles"
+	defaultOIDCKeyCacheTTL       = time.Hour
+	// oidcMinKeyRefresh limits how often the JWKS is fetched, so forged key
+	// IDs and an unreachable issuer cannot cause a fetch per request.
+	oidcMinKeyRefresh   = time.Minute
+	oidcFetchTimeout    = 10 * time.Second
+	oidcMaxResponseSize = 1 << 20
+	oidcDiscoveryPath   = "/.well-known/openid-configuration"
+)
+
+// OIDCConfig configures an OIDCAuthProvider.
+type OIDCConfig struct {
+	// Issuer is the issuer URL. Keys are discovered from its
+	// /.well-known/openid-configuration document and tokens must carry it
+	// as their iss claim.
+	Issuer string
+	// Audience, when set, must appear in the aud claim of every token.
+	Audience string
+	// OrganisationClaim and RolesClaim name the claims mapped to the
+	// organisation ID and roles. Dots walk into nested objects, such as
+	// realm_access.roles for Keycloak realm roles.
+	OrganisationClaim string
+	RolesClaim        string
+	// KeyCacheTTL is how long fetched keys are trusted before the JWKS is
+	// fetched again. Zero uses one hour.
+	KeyCacheTTL time.Duration
+	// HTTPClient fetches discovery and key documents. Nil uses a client
+	// with a ten second timeout.
+	HTTPClient *http.Client
+}
+
+// OIDCAuthProvider validates RS256 bearer tokens issued by an OpenID Connect
+// provider. Signing keys are discovered from the issuer and cached. A token
+// signed with an unknown key ID refreshes the cache, so key rotation needs no
+// restart.
+type OIDCAuthProvider struct {
+	config OIDCConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu          sync.Mutex
+	jwksURI     string
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+	// refreshing is the fetch in flight, if any. refreshStarted and
+	// refreshErr describe the latest fetch, failed or not.
+	refreshing     *keyRefresh
+	refreshStarted time.Time
+	refreshErr     error
+}
+
+// NewOIDCAuthProvider returns an OIDC auth provider for config. Discovery
+// happens on the first request so startup does not depend on the issuer.
+func NewOIDCAuthProvider(config OIDCConfig) (*OIDCAuthProvider, error) {
+	config.Issuer = strings.TrimRight(strings.TrimSpace(config.Issuer), "/")
+	if config.Issuer == "" {
+		return nil, errors.New("oidc issuer is required")
+	}
+	if !strings.HasPrefix(config.Issuer, "https://") && !strings.HasPrefix(config.Issuer, "http://") {
+		return nil, fmt.Errorf("oidc issuer %q must be an http or https URL", config.Issuer)
+	}
+	config.Audience = strings.TrimSpace(config.Audience)
+	config.OrganisationClaim = strings.TrimSpace(config.OrganisationClaim)
+	if config.OrganisationClaim == "" {
+		config.OrganisationClaim = defaultOIDCOrganisationClaim
+	}
+	config.RolesClaim = strings.TrimSpace(config.RolesClaim)
+	if config.RolesClaim == "" {
+		config.RolesClaim = defaultOIDCRolesClaim
+	}
+	if config.KeyCacheTTL <= 0 {
+		config.KeyCacheTTL = defaultOIDCKeyCacheTTL
+	}
+
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: oidcFetchTimeout}
+	}
+	return &OIDCAuthProvider{config: config, client: client, now: time.Now}, nil
+}
+
+// FromRequest validates a bearer token and returns the derived auth context.
+func (p *OIDCAuthProvider) FromRequest(r *http.Request) (ports.AuthContext, error) {
+	if p == nil {
+		return ports.AuthContext{}, errors.New("auth provider is nil")
+	}
+
+	token, err := bearerToken(r)
+	if err != nil {
+		return ports.AuthContext{}, err
+	}
+	claims, err := p.parseAndValidateToken(r.Context(), token)
+	if err != nil {
+		return ports.AuthContext{}, err
+	}
+
+	userID := claimString(claims, "sub")
+	if userID == "" {
+		return ports.AuthContext{}, errors.New("token subject is required")
+	}
+	organisationID, _ := claimAtPath(claims, p.config.OrganisationClaim).(string)
+	roles, err := parseRolesClaim(claimAtPath(claims, p.config.RolesClaim))
+	if err != nil {
+		return ports.AuthContext{}, err
+	}
+	if len(roles) == 0 {
+		return ports.AuthContext{}, errors.New("token roles are required")
+	}
+
+	return ports.AuthContext{
+		UserID:         userID,
+		OrganisationID: strings.TrimSpace(organisationID),
+		Roles:          roles,
+	}, nil
+}
+
+// claimAtPath returns the claim named by a dotted path such as
+// realm_access.roles, or nil when any step is missing.
+func claimAtPath(claims map[string]any, path string) any {
+	var current any = claims
+	for _, name := range strings.Split(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = object[name]
+	}
+	return current
+}
+
+func (p *OIDCAuthProvider) parseAndValidateToken(ctx context.Context, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token must have three segments")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("decode token header: %w", err)
+	}
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode token payload: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode token signature: %w", err)
+	}
+
+	var header map[string]any
+	if err = json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("parse token header: %w", err)
+	}
+	if claimString(header, "alg") != "RS256" {
+		return nil, errors.New("token alg must be RS256")
+	}
+
+	key, err := p.signingKey(ctx, claimString(header, "kid"))
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("token signature is invalid")
+	}
+
+	var claims map[string]any
+	if err = json.Unmarshal(payloadJSON, &claims); err != nil {
+		return nil, fmt.Errorf("parse token payload: %w", err)
+	}
+
+	now := p.now().UTC().Unix()
+	if err = validateExpiration(claims, now); err != nil {
+		return nil, err
+	}
+	if err = validateNotBefore(claims, now); err != nil {
+		return nil, err
+	}
+	if strings.TrimRight(claimString(claims, "iss"), "/") != p.config.Issuer {
+		return nil, errors.New("token issuer is not trusted")
+	}
+	if p.config.Audience != "" && !audienceContains(claims["aud"], p.config.Audience) {
+		return nil, errors.New("token audience is not accepted")
+	}
+	return claims, nil
+}
+
+func audienceContains(value any, audience string) bool {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue == audience
+	case []any:
+		for _, entry := range typedValue {
+			if entry == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// signingKey returns the cached key for kid. Expired caches are refetched,
+// and an unknown kid refetches at most once per oidcMinKeyRefresh to pick up
+// rotated keys. A failed fetch also counts as a refresh, so an unreachable
+// issuer is not retried on every request. An empty kid matches the only key
+// of a single-key set.
+func (p *OIDCAuthProvider) signingKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	stale := p.keys == nil || p.now().Sub(p.keysFetched) >= p.config.KeyCacheTTL
+	cached, known := p.cachedKeyLocked(kid)
+	if known && !stale {
+		p.mu.Unlock()
+		return cached, nil
+	}
+	refresh := p.refreshing
+	if refresh == nil {
+		if p.now().Sub(p.refreshStarted) < oidcMinKeyRefresh {
+			err := p.refreshErr
+			p.mu.Unlock()
+			return unknownSigningKey(kid, cached, known, err)
+		}
+		refresh = p.startRefreshLocked(ctx)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-refresh.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p.mu.Lock()
+	key, ok := p.cachedKeyLocked(kid)
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	return unknownSigningKey(kid, cached, known, refresh.err)
+}
+
+// unknownSigningKey answers a lookup the key cache could not satisfy. A key
+// that was known before stays trusted while the issuer is unreachable.
+func unknownSigningKey(kid string, cached *rsa.PublicKey, known bool, err error) (*rsa.PublicKey, error) {
+	if known {
+		return cached, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("token key %q is unknown", kid)
+}
+
+func (p *OIDCAuthProvider) cachedKeyLocked(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// keyRefresh is one fetch of the key set. Requests that need new keys while
+// it runs wait on done instead of starting their own fetch.
+type keyRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// startRefreshLocked fetches the key set in the background. The fetch does
+// not hold p.mu, so requests with cached keys are not held up by the issuer.
+// It runs on its own deadline, so a caller giving up does not fail the
+// requests waiting on the same fetch.
+func (p *OIDCAuthProvider) startRefreshLocked(ctx context.Context) *keyRefresh {
+	refresh := &keyRefresh{done: make(chan struct{})}
+	p.refreshing = refresh
+	p.refreshStarted = p.now()
+	jwksURI := p.jwksURI
+
+	go func() {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), oidcFetchTimeout)
+		defer cancel()
+		keys, discoveredURI, err := p.fetchKeys(fetchCtx, jwksURI)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if err == nil {
+			p.jwksURI = discoveredURI
+			p.keys = keys
+			p.keysFetched = p.now()
+		}
+		p.refreshErr = err
+		p.refreshing = nil
+		refresh.err = err
+		close(refresh.done)
+	}()
+	return refresh
+}
+
+// fetchKeys fetches the key set, discovering its URL first when jwksURI is
+// empty. It returns the keys and the key set URL.
+func (p *OIDCAuthProvider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, string, error) {
+	if jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := p.fetchJSON(ctx, p.config.Issuer+oidcDiscoveryPath, &discovery); err != nil {
+			return nil, "", fmt.Errorf("discover oidc issuer: %w", err)
+		}
+		if strings.TrimRight(discovery.Issuer, "/") != p.config.Issuer {
+			return nil, "", fmt.Errorf("oidc discovery names issuer %q, expected %q", discovery.Issuer, p.config.Issuer)
+		}
+		if strings.TrimSpace(discovery.JWKSURI) == "" {
+			return nil, "", errors.New("oidc discovery has no jwks_uri")
+		}
+		jwksURI = discovery.JWKSURI
+	}
+
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.fetchJSON(ctx, jwksURI, &keySet); err != nil {
+		return nil, "", fmt.Errorf("fetch oidc keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(keySet.Keys))
+	for _, jwk := range keySet.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") || (jwk.Alg != "" && jwk.Alg != "RS256") {
+			continue
+		}
+		key, err := jwk.rsaPublicKey()
+		if err != nil {
+			return nil, "", fmt.Errorf("parse oidc key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, jwksURI, nil
+}
+
+func (p *OIDCAuthProvider) fetchJSON(ctx context.Context, url string, target any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", url, response.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(response.Body, oidcMaxResponseSize)).Decode(target)
+}
+
+// jsonWebKey holds the fields of an RSA JSON Web Key.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+	e := new(big.Int).SetBytes(exponent)
+	if len(modulus) == 0 || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("key modulus or exponent is invalid")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(e.Int64())}, nil
+}
@@ -0,0 +1,321 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testOIDCIssuer serves discovery and key documents for a set of RSA keys.
+type testOIDCIssuer struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	keys       map[string]*rsa.PrivateKey
+	keyFetches int
+	// fail makes key fetches return an error status. hold, when set, makes
+	// key fetches wait until it is closed.
+	fail bool
+	hold chan struct{}
+}
+
+func newTestOIDCIssuer(t *testing.T) *testOIDCIssuer {
+	t.Helper()
+	issuer := &testOIDCIssuer{keys: map[string]*rsa.PrivateKey{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		issuer.mu.Lock()
+		issuer.keyFetches++
+		hold := issuer.hold
+		issuer.mu.Unlock()
+		if hold != nil {
+			<-hold
+		}
+
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		if issuer.fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		keys := make([]map[string]string, 0, len(issuer.keys))
+		for kid, key := range issuer.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *testOIDCIssuer) addKey(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys[kid] = key
+	return key
+}
+
+func (i *testOIDCIssuer) fetches() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.keyFetches
+}
+
+func makeTestRS256JWT(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	headerJSON, err := json.Marshal(map[string]any{"alg": "RS256", "typ": "JWT", "kid": kid})
+	if err != nil {
+		t.Fatalf("marshal header: %v", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func oidcRequest(token string) *http.Request {
+	request := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/persons", nil)
+	request.Header.Set(headerAuthorization, bearerPrefix+token)
+	return request
+}
+
+// TestOIDCAuthProviderFromRequest verifies the OIDC auth provider from request scenario.
+func TestOIDCAuthProviderFromRequest(t *testing.T) {
+	issuer := newTestOIDCIssuer(t)
+	key := issuer.addKey(t, "key-1")
+	provider, err := NewOIDCAuthProvider(OIDCConfig{
+		Issuer:            issuer.server.URL + "/",
+		Audience:          "plato",
+		OrganisationClaim: "tenant.id",
+		RolesClaim:        "realm_access.roles",
+	})
+	if err != nil {
+		t.Fatalf(errCreateProviderFmt, err)
+	}
+
+	now := time.Now().Unix()
+	claims := map[string]any{
+		"sub":          "user-1",
+		"iss":          issuer.server.URL,
+		"aud":          []any{"account", "plato"},
+		"exp":          now + 300,
+		"tenant":       map[string]any{"id": "org_1"},
+		"realm_access": map[string]any{"roles": []any{"org_admin", "org_user"}},
+	}
+	authCtx, err := provider.FromRequest(oidcRequest(makeTestRS256JWT(t, key, "key-1", claims)))
+	if err != nil {
+		t.Fatalf("from request: %v", err)
+	}
+	if authCtx.UserID != "user-1" || authCtx.OrganisationID != "org_1" || len(authCtx.Roles) != 2 {
+		t.Fatalf("unexpected auth context: %+v", authCtx)
+	}
+
+	rejected := map[string]map[string]any{
+		"expired":       {"sub": "user-1", "iss": issuer.server.URL, "aud": "plato", "exp": now - 10, "realm_access": map[string]any{"roles": []any{"org_user"}}},
+		"wrong issuer":  {"sub": "user-1", "iss": "https://evil.example", "aud": "plato", "exp": now + 300, "realm_access": map[string]any{"roles": []any{"org_user"}}},
+		"wrong aud":     {"sub": "user-1", "iss": issuer.server.URL, "aud": "other", "exp": now + 300, "realm_access": map[string]any{"roles": []any{"org_user"}}},
+		"missing roles": {"sub": "user-1", "iss": issuer.server.URL, "aud": "plato", "exp": now + 300},
+		"missing sub":   {"iss": issuer.server.URL, "aud": "plato", "exp": now + 300, "realm_access": map[string]any{"roles": []any{"org_user"}}},
+	}
+	for name, rejectedClaims := range rejected {
+		if _, err = provider.FromRequest(oidcRequest(makeTestRS256JWT(t, key, "key-1", rejectedClaims))); err == nil {
+			t.Fatalf("expected %s token to be rejected", name)
+		}
+	}
+
+	forger, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	if _, err = provider.FromRequest(oidcRequest(makeTestRS256JWT(t, forger, "key-1", claims))); err == nil {
+		t.Fatal("expected a token signed by another key to be rejected")
+	}
+	if _, err = provider.FromRequest(oidcRequest(makeTestJWT(t, testJWTSecret, claims))); err == nil {
+		t.Fatal("expected an HS256 token to be rejected")
+	}
+	if _, err = provider.FromRequest(httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)); err == nil {
+		t.Fatal("expected a missing bearer token to be rejected")
+	}
+}
+
+// TestOIDCAuthProviderKeyRotation verifies the OIDC auth provider key rotation scenario.
+func TestOIDCAuthProviderKeyRotation(t *testing.T) {
+	issuer := newTestOIDCIssuer(t)
+	oldKey := issuer.addKey(t, "old")
+	provider, err := NewOIDCAuthProvider(OIDCConfig{Issuer: issuer.server.URL})
+	if err != nil {
+		t.Fatalf(errCreateProviderFmt, err)
+	}
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+
+	claims := map[string]any{"sub": "user-1", "iss": issuer.server.URL, "exp": now.Add(24 * time.Hour).Unix(), "roles": "org_user"}
+	for range 3 {
+		if _, err = provider.FromRequest(oidcRequest(makeTestRS256JWT(t, oldKey, "old", claims))); err != nil {
+			t.Fatalf("from request with old key: %v", err)
+		}
+	}
+	if issuer.fetches() != 1 {
+		t.Fatalf("expected keys to be cached after one fetch, got %d fetches", issuer.fetches())
+	}
+
+	newKey := issuer.addKey(t, "new")
+	rotated := makeTestRS256JWT(t, newKey, "new", claims)
+	if _, err = provider.FromRequest(oidcRequest(rotated)); err == nil {
+		t.Fatal("expected an unknown key to wait for the minimum refresh interval")
+	}
+	if issuer.fetches() != 1 {
+		t.Fatalf("expected no refetch inside the minimum refresh interval, got %d fetches", issuer.fetches())
+	}
+
+	now = now.Add(oidcMinKeyRefresh)
+	if _, err = provider.FromRequest(oidcRequest(rotated)); err != nil {
+		t.Fatalf("from request with rotated key: %v", err)
+	}
+	if issuer.fetches() != 2 {
+		t.Fatalf("expected one refetch for the rotated key, got %d fetches", issuer.fetches())
+	}
+
+	issuer.server.Close()
+	now = now.Add(defaultOIDCKeyCacheTTL)
+	if _, err = provider.FromRequest(oidcRequest(rotated)); err != nil {
+		t.Fatalf("expected cached keys to keep working while the issuer is down: %v", err)
+	}
+}
+
+// TestOIDCAuthProviderSharesKeyFetches verifies the OIDC auth provider concurrent and failed key fetch scenario.
+func TestOIDCAuthProviderSharesKeyFetches(t *testing.T) {
+	issuer := newTestOIDCIssuer(t)
+	oldKey := issuer.addKey(t, "old")
+	provider, err := NewOIDCAuthProvider(OIDCConfig{Issuer: issuer.server.URL})
+	if err != nil {
+		t.Fatalf(errCreateProviderFmt, err)
+	}
+	var nowMu sync.Mutex
+	now := time.Now()
+	provider.now = func() time.Time {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		return now
+	}
+	advance := func(by time.Duration) {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		now = now.Add(by)
+	}
+
+	claims := map[string]any{"sub": "user-1", "iss": issuer.server.URL, "exp": now.Add(24 * time.Hour).Unix(), "roles": "org_user"}
+	oldToken := makeTestRS256JWT(t, oldKey, "old", claims)
+	if _, err = provider.FromRequest(oidcRequest(oldToken)); err != nil {
+		t.Fatalf("from request with old key: %v", err)
+	}
+
+	newKey := issuer.addKey(t, "new")
+	rotated := makeTestRS256JWT(t, newKey, "new", claims)
+	hold := make(chan struct{})
+	issuer.mu.Lock()
+	issuer.hold = hold
+	issuer.mu.Unlock()
+	advance(oidcMinKeyRefresh)
+
+	var waiting sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		waiting.Go(func() {
+			_, requestErr := provider.FromRequest(oidcRequest(rotated))
+			errs <- requestErr
+		})
+	}
+	for issuer.fetches() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err = provider.FromRequest(oidcRequest(oldToken)); err != nil {
+		t.Fatalf("expected a cached key to work while keys are fetched: %v", err)
+	}
+	close(hold)
+	waiting.Wait()
+	close(errs)
+	for requestErr := range errs {
+		if requestErr != nil {
+			t.Fatalf("from request with rotated key: %v", requestErr)
+		}
+	}
+	if issuer.fetches() != 2 {
+		t.Fatalf("expected concurrent requests to share one fetch, got %d fetches", issuer.fetches())
+	}
+
+	issuer.mu.Lock()
+	issuer.hold = nil
+	issuer.fail = true
+	issuer.mu.Unlock()
+	forged := makeTestRS256JWT(t, newKey, "forged", claims)
+	advance(oidcMinKeyRefresh)
+	if _, err = provider.FromRequest(oidcRequest(forged)); err == nil {
+		t.Fatal("expected an unknown key to be rejected while the issuer fails")
+	}
+	if _, err = provider.FromRequest(oidcRequest(forged)); err == nil {
+		t.Fatal("expected an unknown key to be rejected after a failed fetch")
+	}
+	if issuer.fetches() != 3 {
+		t.Fatalf("expected a failed fetch to wait for the minimum refresh interval, got %d fetches", issuer.fetches())
+	}
+}
+
+// TestNewOIDCAuthProviderValidatesConfig verifies the new OIDC auth provider validates config scenario.
+func TestNewOIDCAuthProviderValidatesConfig(t *testing.T) {
+	if _, err := NewOIDCAuthProvider(OIDCConfig{}); err == nil {
+		t.Fatal("expected a missing issuer to be rejected")
+	}
+	if _, err := NewOIDCAuthProvider(OIDCConfig{Issuer: "keycloak.local/realms/plato"}); err == nil {
+		t.Fatal("expected an issuer without scheme to be rejected")
+	}
+	provider, err := NewOIDCAuthProvider(OIDCConfig{Issuer: "https://keycloak.local/realms/plato/"})
+	if err != nil {
+		t.Fatalf(errCreateProviderFmt, err)
+	}
+	if provider.config.Issuer != "https://keycloak.local/realms/plato" || provider.config.RolesClaim != defaultOIDCRolesClaim ||
+		provider.config.OrganisationClaim != defaultOIDCOrganisationClaim || provider.config.KeyCacheTTL != defaultOIDCKeyCacheTTL {
+		t.Fatalf("unexpected defaults: %+v", provider.config)
+	}
+
+	var nilProvider *OIDCAuthProvider
+	if _, err = nilProvider.FromRequest(oidcRequest("token")); err == nil {
+		t.Fatal("expected a nil provider to fail")
+	}
+}
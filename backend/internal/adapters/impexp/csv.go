@@ -0,0 +1,119 @@
+package impexp
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"plato/backend/internal/domain"
+)
+
+const (
+	columnTargetType  = "target_type"
+	columnTargetName  = "target_name"
+	columnProjectName = "project_name"
+	columnStartDate   = "start_date"
+	columnEndDate     = "end_date"
+	columnPercent     = "percent"
+)
+
+var requiredAllocationColumns = []string{columnTargetName, columnProjectName, columnStartDate, columnEndDate, columnPercent}
+
+// CSVImportExport decodes CSV allocation imports and iCalendar holiday
+// files, serves the embedded national holiday dataset, and encodes tenant
+// snapshots as JSON or as a zip archive of CSV files.
+type CSVImportExport struct{}
+
+// NewCSVImportExport returns a CSV import and export adapter.
+func NewCSVImportExport() *CSVImportExport {
+	return &CSVImportExport{}
+}
+
+// DecodeAllocationImport parses CSV with a header row naming the columns
+// target_name, project_name, start_date, end_date, and percent, plus an
+// optional target_type that defaults to person. Lines that cannot be decoded
+// are returned with Error set so callers can report them per row.
+func (c *CSVImportExport) DecodeAllocationImport(raw []byte) ([]domain.AllocationImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(raw))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.Join(domain.ErrValidation, errors.New("allocation import is empty"))
+	}
+	if err != nil {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("read allocation import header: %w", err))
+	}
+	columns, err := allocationColumnIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]domain.AllocationImportRow, 0)
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			return rows, nil
+		}
+		if readErr != nil {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("read allocation import: %w", readErr))
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, decodeAllocationRecord(line, record, columns))
+	}
+}
+
+func allocationColumnIndex(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch name {
+		case columnTargetType, columnTargetName, columnProjectName, columnStartDate, columnEndDate, columnPercent:
+		default:
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("unknown allocation import column %q", name))
+		}
+		if _, duplicate := columns[name]; duplicate {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("duplicate allocation import column %q", name))
+		}
+		columns[name] = index
+	}
+	for _, name := range requiredAllocationColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("allocation import is missing column %q", name))
+		}
+	}
+	return columns, nil
+}
+
+func decodeAllocationRecord(line int, record []string, columns map[string]int) domain.AllocationImportRow {
+	row := domain.AllocationImportRow{Line: line}
+	if len(record) != len(columns) {
+		row.Error = fmt.Sprintf("expected %d fields, got %d", len(columns), len(record))
+		return row
+	}
+	field := func(name string) string {
+		index, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	row.TargetType = field(columnTargetType)
+	row.TargetName = field(columnTargetName)
+	row.ProjectName = field(columnProjectName)
+	row.StartDate = field(columnStartDate)
+	row.EndDate = field(columnEndDate)
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(field(columnPercent), "%"), 64)
+	if err != nil {
+		row.Error = fmt.Sprintf("percent %q is not a number", field(columnPercent))
+		return row
+	}
+	row.Percent = percent
+	return row
+}
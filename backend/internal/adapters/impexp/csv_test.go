@@ -0,0 +1,48 @@
+package impexp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"plato/backend/internal/domain"
+)
+
+// TestCSVDecodeAllocationImport verifies the CSV decode allocation import scenario.
+func TestCSVDecodeAllocationImport(t *testing.T) {
+	adapter := NewCSVImportExport()
+	raw := []byte("percent,project_name,target_name,start_date,end_date\n" +
+		"40%,Apollo,Ada Lovelace,2026-01-01,2026-03-31\n" +
+		"lots,Apollo,Ada Lovelace,2026-01-01,2026-03-31\n" +
+		"40,Apollo\n")
+	rows, err := adapter.DecodeAllocationImport(raw)
+	if err != nil {
+		t.Fatalf("decode allocation import: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	expected := domain.AllocationImportRow{
+		Line:        2,
+		TargetName:  "Ada Lovelace",
+		ProjectName: "Apollo",
+		StartDate:   "2026-01-01",
+		EndDate:     "2026-03-31",
+		Percent:     40,
+	}
+	if rows[0] != expected {
+		t.Fatalf("unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Line != 3 || !strings.Contains(rows[1].Error, "not a number") {
+		t.Fatalf("expected percent error on line 3, got %+v", rows[1])
+	}
+	if rows[2].Line != 4 || !strings.Contains(rows[2].Error, "expected 5 fields") {
+		t.Fatalf("expected field count error on line 4, got %+v", rows[2])
+	}
+
+	for _, invalid := range []string{"", "target_name,project_name,start_date,end_date\n", "target_name,target_name\n"} {
+		if _, err := adapter.DecodeAllocationImport([]byte(invalid)); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected validation error for %q, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package impexp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// holidayDatasetJSON lists public holidays per ISO 3166 country code as rules
+// instead of dates, so the dataset covers every year.
+//
+//go:embed holidays.json
+var holidayDatasetJSON []byte
+
+// holidayCountry holds the holidays of one country. Regions lists the region
+// codes a caller may select, and a holiday with regions only applies there.
+type holidayCountry struct {
+	Name     string        `json:"name"`
+	Regions  []string      `json:"regions"`
+	Holidays []holidayRule `json:"holidays"`
+}
+
+// holidayRule places a holiday either on a fixed month and day, on a day
+// relative to Easter Sunday, or on the last given weekday on or before a
+// fixed month and day.
+type holidayRule struct {
+	Name              string   `json:"name"`
+	Month             int      `json:"month"`
+	Day               int      `json:"day"`
+	EasterOffset      *int     `json:"easter_offset"`
+	WeekdayOnOrBefore string   `json:"weekday_on_or_before"`
+	Regions           []string `json:"regions"`
+}
+
+var loadHolidayDataset = sync.OnceValues(func() (map[string]holidayCountry, error) {
+	var dataset map[string]holidayCountry
+	if err := json.Unmarshal(holidayDatasetJSON, &dataset); err != nil {
+		return nil, fmt.Errorf("decode holiday dataset: %w", err)
+	}
+	return dataset, nil
+})
+
+// NationalHolidays returns the public holidays of a country in year from the
+// embedded dataset, ordered by date. Holidays limited to some regions are
+// only included when region names one of them.
+func (c *CSVImportExport) NationalHolidays(country, region string, year int) ([]domain.HolidayImportEntry, error) {
+	dataset, err := loadHolidayDataset()
+	if err != nil {
+		return nil, err
+	}
+	country = strings.ToUpper(strings.TrimSpace(country))
+	region = strings.ToUpper(strings.TrimSpace(region))
+	calendar, ok := dataset[country]
+	if !ok {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("no holiday data for country %q, known countries are %s", country, strings.Join(holidayCountryCodes(dataset), ", ")))
+	}
+	if region != "" && !slices.Contains(calendar.Regions, region) {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("unknown region %q for country %s", region, country))
+	}
+
+	entries := make([]domain.HolidayImportEntry, 0, len(calendar.Holidays))
+	for _, rule := range calendar.Holidays {
+		if len(rule.Regions) > 0 && !slices.Contains(rule.Regions, region) {
+			continue
+		}
+		date, ruleErr := rule.dateIn(year)
+		if ruleErr != nil {
+			return nil, ruleErr
+		}
+		entries = append(entries, domain.HolidayImportEntry{Date: date.Format(domain.DateLayout), Name: rule.Name})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date < entries[j].Date
+	})
+	return entries, nil
+}
+
+func (r holidayRule) dateIn(year int) (time.Time, error) {
+	if r.EasterOffset != nil {
+		return easterSunday(year).AddDate(0, 0, *r.EasterOffset), nil
+	}
+	date := time.Date(year, time.Month(r.Month), r.Day, 0, 0, 0, 0, time.UTC)
+	if r.WeekdayOnOrBefore == "" {
+		return date, nil
+	}
+	weekday, ok := parseHolidayWeekday(r.WeekdayOnOrBefore)
+	if !ok {
+		return time.Time{}, fmt.Errorf("holiday %q has unknown weekday %q", r.Name, r.WeekdayOnOrBefore)
+	}
+	back := (int(date.Weekday()) - int(weekday) + 7) % 7
+	return date.AddDate(0, 0, -back), nil
+}
+
+// easterSunday returns the date of Western Easter in year using the
+// anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+func parseHolidayWeekday(name string) (time.Weekday, bool) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(weekday.String(), name) {
+			return weekday, true
+		}
+	}
+	return time.Sunday, false
+}
+
+func holidayCountryCodes(dataset map[string]holidayCountry) []string {
+	codes := make([]string, 0, len(dataset))
+	for code := range dataset {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
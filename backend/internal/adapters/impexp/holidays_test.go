@@ -0,0 +1,74 @@
+package impexp
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// TestEasterSunday verifies the Easter Sunday scenario.
+func TestEasterSunday(t *testing.T) {
+	for year, want := range map[int]string{
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2026: "2026-04-05",
+		2027: "2027-03-28",
+		2038: "2038-04-25",
+	} {
+		if got := easterSunday(year).Format(time.DateOnly); got != want {
+			t.Fatalf("expected Easter %d on %s, got %s", year, want, got)
+		}
+	}
+}
+
+// TestNationalHolidays verifies the national holidays scenario.
+func TestNationalHolidays(t *testing.T) {
+	adapter := NewCSVImportExport()
+
+	national, err := adapter.NationalHolidays("de", "", 2026)
+	if err != nil {
+		t.Fatalf("national holidays: %v", err)
+	}
+	want := []domain.HolidayImportEntry{
+		{Date: "2026-01-01", Name: "New Year's Day"},
+		{Date: "2026-04-03", Name: "Good Friday"},
+		{Date: "2026-04-06", Name: "Easter Monday"},
+		{Date: "2026-05-01", Name: "Labour Day"},
+		{Date: "2026-05-14", Name: "Ascension Day"},
+		{Date: "2026-05-25", Name: "Whit Monday"},
+		{Date: "2026-10-03", Name: "German Unity Day"},
+		{Date: "2026-12-25", Name: "Christmas Day"},
+		{Date: "2026-12-26", Name: "Boxing Day"},
+	}
+	if !reflect.DeepEqual(national, want) {
+		t.Fatalf("expected %+v, got %+v", want, national)
+	}
+
+	saxony, err := adapter.NationalHolidays("DE", " sn ", 2026)
+	if err != nil {
+		t.Fatalf("regional holidays: %v", err)
+	}
+	if len(saxony) != len(want)+2 {
+		t.Fatalf("expected Reformation Day and the Day of Repentance in Saxony, got %+v", saxony)
+	}
+	if saxony[7] != (domain.HolidayImportEntry{Date: "2026-10-31", Name: "Reformation Day"}) || saxony[8] != (domain.HolidayImportEntry{Date: "2026-11-18", Name: "Day of Repentance and Prayer"}) {
+		t.Fatalf("unexpected Saxony holidays %+v", saxony[7:9])
+	}
+
+	brandenburg, err := adapter.NationalHolidays("DE", "BB", 2026)
+	if err != nil {
+		t.Fatalf("regional holidays: %v", err)
+	}
+	if brandenburg[2] != (domain.HolidayImportEntry{Date: "2026-04-05", Name: "Easter Sunday"}) {
+		t.Fatalf("expected Easter Sunday in Brandenburg, got %+v", brandenburg[2])
+	}
+
+	for _, invalid := range [][2]string{{"XX", ""}, {"DE", "ZZ"}, {"AT", "BY"}} {
+		if _, err := adapter.NationalHolidays(invalid[0], invalid[1], 2026); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected validation error for %v, got %v", invalid, err)
+		}
+	}
+}
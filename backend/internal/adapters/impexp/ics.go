@@ -0,0 +1,179 @@
+package impexp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+const icsDateLayout = "20060102"
+
+// maxICSEventDays caps how many days one calendar event may span.
+const maxICSEventDays = 366
+
+// icsEvent collects the properties of one VEVENT that the holiday import
+// reads.
+type icsEvent struct {
+	line    int
+	summary string
+	start   string
+	end     string
+	rrule   string
+}
+
+// DecodeHolidayCalendar reads the VEVENT entries of an iCalendar file and
+// returns one entry per day in year. DTSTART and DTEND are read as calendar
+// dates, with DTEND exclusive as iCalendar defines it, and timed events keep
+// the date they start on. Events repeating with a plain RRULE:FREQ=YEARLY
+// are placed on their month and day in year. Other recurrence rules are
+// rejected.
+func (c *CSVImportExport) DecodeHolidayCalendar(raw []byte, year int) ([]domain.HolidayImportEntry, error) {
+	events, err := decodeICSEvents(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.HolidayImportEntry, 0, len(events))
+	for _, event := range events {
+		eventEntries, eventErr := event.entries(year)
+		if eventErr != nil {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("calendar event on line %d: %w", event.line, eventErr))
+		}
+		entries = append(entries, eventEntries...)
+	}
+	return entries, nil
+}
+
+func decodeICSEvents(raw []byte) ([]icsEvent, error) {
+	lines := unfoldICSLines(raw)
+	if len(lines) == 0 || !strings.EqualFold(lines[0].text, "BEGIN:VCALENDAR") {
+		return nil, errors.Join(domain.ErrValidation, errors.New("calendar must start with BEGIN:VCALENDAR"))
+	}
+
+	events := make([]icsEvent, 0)
+	var current *icsEvent
+	for _, line := range lines {
+		name, value := splitICSProperty(line.text)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &icsEvent{line: line.number}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current == nil {
+				return nil, errors.Join(domain.ErrValidation, fmt.Errorf("line %d: END:VEVENT without BEGIN:VEVENT", line.number))
+			}
+			if current.start == "" {
+				return nil, errors.Join(domain.ErrValidation, fmt.Errorf("calendar event on line %d has no DTSTART", current.line))
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+		case name == "SUMMARY":
+			current.summary = unescapeICSText(value)
+		case name == "DTSTART":
+			current.start = icsDate(value)
+		case name == "DTEND":
+			current.end = icsDate(value)
+		case name == "RRULE":
+			current.rrule = strings.ToUpper(value)
+		}
+	}
+	if current != nil {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("calendar event on line %d has no END:VEVENT", current.line))
+	}
+	return events, nil
+}
+
+func (e icsEvent) entries(year int) ([]domain.HolidayImportEntry, error) {
+	start, err := time.Parse(icsDateLayout, e.start)
+	if err != nil {
+		return nil, fmt.Errorf("DTSTART %q is not a date", e.start)
+	}
+	end := start.AddDate(0, 0, 1)
+	if e.end != "" {
+		if end, err = time.Parse(icsDateLayout, e.end); err != nil {
+			return nil, fmt.Errorf("DTEND %q is not a date", e.end)
+		}
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+	}
+	days := int(end.Sub(start).Hours() / 24)
+	if days > maxICSEventDays {
+		return nil, fmt.Errorf("event spans %d days, more than %d", days, maxICSEventDays)
+	}
+
+	switch e.rrule {
+	case "":
+	case "FREQ=YEARLY":
+		if start.Year() > year {
+			return nil, nil
+		}
+		shift := year - start.Year()
+		start = start.AddDate(shift, 0, 0)
+	default:
+		return nil, fmt.Errorf("RRULE %q is not supported, only FREQ=YEARLY", e.rrule)
+	}
+
+	entries := make([]domain.HolidayImportEntry, 0, days)
+	for offset := range days {
+		day := start.AddDate(0, 0, offset)
+		if day.Year() == year {
+			entries = append(entries, domain.HolidayImportEntry{Date: day.Format(domain.DateLayout), Name: e.summary})
+		}
+	}
+	return entries, nil
+}
+
+type icsLine struct {
+	number int
+	text   string
+}
+
+// unfoldICSLines joins continuation lines, which start with a space or a tab,
+// onto the line before them and drops blank lines.
+func unfoldICSLines(raw []byte) []icsLine {
+	lines := make([]icsLine, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(raw, []byte("\ufeff"))))
+	scanner.Buffer(make([]byte, 0, 4096), len(raw)+1)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		lines = append(lines, icsLine{number: number, text: text})
+	}
+	return lines
+}
+
+// splitICSProperty splits "NAME;PARAM=X:value" into its upper-case name and
+// its value. Parameters are dropped.
+func splitICSProperty(text string) (name string, value string) {
+	head, value, _ := strings.Cut(text, ":")
+	name, _, _ = strings.Cut(head, ";")
+	return strings.ToUpper(strings.TrimSpace(name)), strings.TrimSpace(value)
+}
+
+// icsDate returns the YYYYMMDD part of a DATE or DATE-TIME value.
+func icsDate(value string) string {
+	if len(value) > len(icsDateLayout) {
+		return value[:len(icsDateLayout)]
+	}
+	return value
+}
+
+var icsTextReplacer = strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescapeICSText(value string) string {
+	return strings.TrimSpace(icsTextReplacer.Replace(value))
+}
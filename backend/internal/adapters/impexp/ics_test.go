@@ -0,0 +1,73 @@
+package impexp
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"plato/backend/internal/domain"
+)
+
+// TestCSVDecodeHolidayCalendar verifies the CSV decode holiday calendar scenario.
+func TestCSVDecodeHolidayCalendar(t *testing.T) {
+	adapter := NewCSVImportExport()
+	raw := []byte(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260101",
+		"DTEND;VALUE=DATE:20260102",
+		"SUMMARY:New Year",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20261224",
+		"DTEND;VALUE=DATE:20261227",
+		"SUMMARY:Christmas\\, with a",
+		"  long name",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20260501T090000Z",
+		"SUMMARY:Labour Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20200801",
+		"RRULE:FREQ=YEARLY",
+		"SUMMARY:Founding Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20270101",
+		"SUMMARY:Next Year",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n"))
+
+	entries, err := adapter.DecodeHolidayCalendar(raw, 2026)
+	if err != nil {
+		t.Fatalf("decode holiday calendar: %v", err)
+	}
+	want := []domain.HolidayImportEntry{
+		{Date: "2026-01-01", Name: "New Year"},
+		{Date: "2026-12-24", Name: "Christmas, with a long name"},
+		{Date: "2026-12-25", Name: "Christmas, with a long name"},
+		{Date: "2026-12-26", Name: "Christmas, with a long name"},
+		{Date: "2026-05-01", Name: "Labour Day"},
+		{Date: "2026-08-01", Name: "Founding Day"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("expected %+v, got %+v", want, entries)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"BEGIN:VEVENT\nEND:VEVENT\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:No date\nEND:VEVENT\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2026-01-01\nEND:VEVENT\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20260101\nRRULE:FREQ=WEEKLY\nEND:VEVENT\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20260101\nEND:VCALENDAR\n",
+	} {
+		if _, err := adapter.DecodeHolidayCalendar([]byte(invalid), 2026); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected validation error for %q, got %v", invalid, err)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package impexp
 
-import "context"
+import (
+	"errors"
+
+	"plato/backend/internal/domain"
+)
 
 // NoopImportExport is a no-op import and export adapter.
 type NoopImportExport struct{}
@@ -10,12 +14,27 @@ func NewNoopImportExport() *NoopImportExport {
 	return &NoopImportExport{}
 }
 
-// Import accepts input without persisting any changes.
-func (noop *NoopImportExport) Import(_ context.Context, _ []byte) error {
-	return nil
+// EncodeTenantSnapshot rejects snapshot exports, which need an encoding adapter.
+func (noop *NoopImportExport) EncodeTenantSnapshot(_ domain.TenantSnapshot, _ string) ([]byte, error) {
+	return nil, errors.Join(domain.ErrValidation, errors.New("tenant export is not supported"))
+}
+
+// DecodeTenantSnapshot rejects snapshot imports, which need a decoding adapter.
+func (noop *NoopImportExport) DecodeTenantSnapshot(_ []byte, _ string) (domain.TenantSnapshot, error) {
+	return domain.TenantSnapshot{}, errors.Join(domain.ErrValidation, errors.New("tenant import is not supported"))
+}
+
+// DecodeAllocationImport rejects allocation imports, which need a decoding adapter.
+func (noop *NoopImportExport) DecodeAllocationImport(_ []byte) ([]domain.AllocationImportRow, error) {
+	return nil, errors.Join(domain.ErrValidation, errors.New("allocation import is not supported"))
+}
+
+// DecodeHolidayCalendar rejects calendar imports, which need a decoding adapter.
+func (noop *NoopImportExport) DecodeHolidayCalendar(_ []byte, _ int) ([]domain.HolidayImportEntry, error) {
+	return nil, errors.Join(domain.ErrValidation, errors.New("holiday calendar import is not supported"))
 }
 
-// Export returns an empty JSON object payload.
-func (noop *NoopImportExport) Export(_ context.Context) ([]byte, error) {
-	return []byte("{}"), nil
+// NationalHolidays rejects national holiday imports, which need a dataset adapter.
+func (noop *NoopImportExport) NationalHolidays(_, _ string, _ int) ([]domain.HolidayImportEntry, error) {
+	return nil, errors.Join(domain.ErrValidation, errors.New("national holiday import is not supported"))
 }
@@ -1,21 +1,37 @@
 package impexp
 
 import (
-	"context"
+	"errors"
 	"testing"
+
+	"plato/backend/internal/domain"
 )
 
 // TestNoopImportExport verifies the no-op import export scenario.
 func TestNoopImportExport(t *testing.T) {
 	adapter := NewNoopImportExport()
-	if err := adapter.Import(context.Background(), []byte("{}")); err != nil {
-		t.Fatalf("unexpected import error: %v", err)
+	if _, err := adapter.EncodeTenantSnapshot(domain.TenantSnapshot{}, domain.SnapshotFormatJSON); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected export to be rejected, got %v", err)
+	}
+	if _, err := adapter.DecodeTenantSnapshot([]byte("{}"), domain.SnapshotFormatJSON); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected import to be rejected, got %v", err)
+	}
+}
+
+// TestNoopDecodeAllocationImport verifies the no-op decode allocation import scenario.
+func TestNoopDecodeAllocationImport(t *testing.T) {
+	if _, err := NewNoopImportExport().DecodeAllocationImport([]byte("target_name\n")); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
 	}
-	payload, err := adapter.Export(context.Background())
-	if err != nil {
-		t.Fatalf("unexpected export error: %v", err)
+}
+
+// TestNoopHolidayImports verifies the no-op holiday imports scenario.
+func TestNoopHolidayImports(t *testing.T) {
+	adapter := NewNoopImportExport()
+	if _, err := adapter.DecodeHolidayCalendar([]byte("BEGIN:VCALENDAR\n"), 2026); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected calendar import to be rejected, got %v", err)
 	}
-	if string(payload) != "{}" {
-		t.Fatalf("unexpected payload: %s", string(payload))
+	if _, err := adapter.NationalHolidays("DE", "", 2026); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected national holiday import to be rejected, got %v", err)
 	}
 }
@@ -0,0 +1,180 @@
+package impexp
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"plato/backend/internal/domain"
+)
+
+// Snapshot archive file names, one CSV file per record type.
+const (
+	snapshotFileOrganisation         = "organisation.csv"
+	snapshotFilePersons              = "persons.csv"
+	snapshotFileProjects             = "projects.csv"
+	snapshotFileGroups               = "groups.csv"
+	snapshotFileAllocations          = "allocations.csv"
+	snapshotFileHolidays             = "holidays.csv"
+	snapshotFileGroupUnavailability  = "group_unavailability.csv"
+	snapshotFilePersonUnavailability = "person_unavailability.csv"
+)
+
+// maxSnapshotFileBytes caps the unpacked size of one archive file so a small
+// upload cannot expand into an unbounded amount of memory.
+const maxSnapshotFileBytes = 64 << 20
+
+// EncodeTenantSnapshot renders a snapshot as JSON, or for the csv format as a
+// zip archive holding one CSV file per record type.
+func (c *CSVImportExport) EncodeTenantSnapshot(snapshot domain.TenantSnapshot, format string) ([]byte, error) {
+	switch format {
+	case domain.SnapshotFormatJSON:
+		return json.MarshalIndent(snapshot, "", "  ")
+	case domain.SnapshotFormatCSV:
+		return encodeSnapshotArchive(snapshot)
+	default:
+		return nil, unsupportedSnapshotFormat(format)
+	}
+}
+
+// DecodeTenantSnapshot parses a snapshot written by EncodeTenantSnapshot.
+// Unknown fields, columns, and archive files are rejected.
+func (c *CSVImportExport) DecodeTenantSnapshot(raw []byte, format string) (domain.TenantSnapshot, error) {
+	switch format {
+	case domain.SnapshotFormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		var snapshot domain.TenantSnapshot
+		if err := decoder.Decode(&snapshot); err != nil {
+			return domain.TenantSnapshot{}, errors.Join(domain.ErrValidation, fmt.Errorf("decode snapshot: %w", err))
+		}
+		return snapshot, nil
+	case domain.SnapshotFormatCSV:
+		return decodeSnapshotArchive(raw)
+	default:
+		return domain.TenantSnapshot{}, unsupportedSnapshotFormat(format)
+	}
+}
+
+func unsupportedSnapshotFormat(format string) error {
+	return errors.Join(domain.ErrValidation, fmt.Errorf("snapshot format %q must be %s or %s", format, domain.SnapshotFormatJSON, domain.SnapshotFormatCSV))
+}
+
+func encodeSnapshotArchive(snapshot domain.TenantSnapshot) ([]byte, error) {
+	files := []struct {
+		name   string
+		encode func() ([]byte, error)
+	}{
+		{snapshotFileOrganisation, func() ([]byte, error) { return encodeCSVRecords([]domain.Organisation{snapshot.Organisation}) }},
+		{snapshotFilePersons, func() ([]byte, error) { return encodeCSVRecords(snapshot.Persons) }},
+		{snapshotFileProjects, func() ([]byte, error) { return encodeCSVRecords(snapshot.Projects) }},
+		{snapshotFileGroups, func() ([]byte, error) { return encodeCSVRecords(snapshot.Groups) }},
+		{snapshotFileAllocations, func() ([]byte, error) { return encodeCSVRecords(snapshot.Allocations) }},
+		{snapshotFileHolidays, func() ([]byte, error) { return encodeCSVRecords(snapshot.Holidays) }},
+		{snapshotFileGroupUnavailability, func() ([]byte, error) { return encodeCSVRecords(snapshot.GroupUnavailability) }},
+		{snapshotFilePersonUnavailability, func() ([]byte, error) { return encodeCSVRecords(snapshot.PersonUnavailability) }},
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, file := range files {
+		body, err := file.encode()
+		if err != nil {
+			return nil, fmt.Errorf("encode %s: %w", file.name, err)
+		}
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = writer.Write(body); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func decodeSnapshotArchive(raw []byte) (domain.TenantSnapshot, error) {
+	archive, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return domain.TenantSnapshot{}, errors.Join(domain.ErrValidation, fmt.Errorf("read snapshot archive: %w", err))
+	}
+
+	snapshot := domain.TenantSnapshot{FormatVersion: domain.TenantSnapshotVersion}
+	decoders := map[string]func([]byte) error{
+		snapshotFileOrganisation: func(body []byte) error {
+			organisations, decodeErr := decodeCSVRecords[domain.Organisation](snapshotFileOrganisation, body)
+			if decodeErr != nil {
+				return decodeErr
+			}
+			if len(organisations) != 1 {
+				return errors.Join(domain.ErrValidation, fmt.Errorf("%s must hold exactly one organisation, got %d", snapshotFileOrganisation, len(organisations)))
+			}
+			snapshot.Organisation = organisations[0]
+			return nil
+		},
+		snapshotFilePersons:              decodeCSVFileInto(snapshotFilePersons, &snapshot.Persons),
+		snapshotFileProjects:             decodeCSVFileInto(snapshotFileProjects, &snapshot.Projects),
+		snapshotFileGroups:               decodeCSVFileInto(snapshotFileGroups, &snapshot.Groups),
+		snapshotFileAllocations:          decodeCSVFileInto(snapshotFileAllocations, &snapshot.Allocations),
+		snapshotFileHolidays:             decodeCSVFileInto(snapshotFileHolidays, &snapshot.Holidays),
+		snapshotFileGroupUnavailability:  decodeCSVFileInto(snapshotFileGroupUnavailability, &snapshot.GroupUnavailability),
+		snapshotFilePersonUnavailability: decodeCSVFileInto(snapshotFilePersonUnavailability, &snapshot.PersonUnavailability),
+	}
+
+	seen := make(map[string]bool, len(decoders))
+	for _, file := range archive.File {
+		decode, ok := decoders[file.Name]
+		if !ok {
+			return domain.TenantSnapshot{}, errors.Join(domain.ErrValidation, fmt.Errorf("unknown snapshot archive file %q", file.Name))
+		}
+		if seen[file.Name] {
+			return domain.TenantSnapshot{}, errors.Join(domain.ErrValidation, fmt.Errorf("snapshot archive holds %s twice", file.Name))
+		}
+		seen[file.Name] = true
+		body, err := readArchiveFile(file)
+		if err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+		if err = decode(body); err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+	}
+	if !seen[snapshotFileOrganisation] {
+		return domain.TenantSnapshot{}, errors.Join(domain.ErrValidation, fmt.Errorf("snapshot archive is missing %s", snapshotFileOrganisation))
+	}
+	return snapshot, nil
+}
+
+func decodeCSVFileInto[T any](name string, target *[]T) func([]byte) error {
+	return func(body []byte) error {
+		records, err := decodeCSVRecords[T](name, body)
+		if err != nil {
+			return err
+		}
+		*target = records
+		return nil
+	}
+}
+
+func readArchiveFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("open %s: %w", file.Name, err))
+	}
+	defer func() { _ = reader.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(reader, maxSnapshotFileBytes+1))
+	if err != nil {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("read %s: %w", file.Name, err))
+	}
+	if len(body) > maxSnapshotFileBytes {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("%s is larger than %d bytes", file.Name, maxSnapshotFileBytes))
+	}
+	return body, nil
+}
@@ -0,0 +1,192 @@
+package impexp
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// csvListSeparator joins ID lists such as member_ids in one CSV cell.
+const csvListSeparator = ";"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// csvColumn is one JSON field of a record type written as a CSV column.
+type csvColumn struct {
+	name  string
+	index int
+}
+
+// csvColumns lists the JSON fields of a record type in declaration order, so
+// CSV headers follow the JSON names of the API.
+func csvColumns(recordType reflect.Type) []csvColumn {
+	columns := make([]csvColumn, 0, recordType.NumField())
+	for index := 0; index < recordType.NumField(); index++ {
+		field := recordType.Field(index)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		columns = append(columns, csvColumn{name: name, index: index})
+	}
+	return columns
+}
+
+// encodeCSVRecords writes one CSV row per record. Scalars are written as
+// text, ID lists are joined with a semicolon, and nested values are JSON.
+func encodeCSVRecords[T any](records []T) ([]byte, error) {
+	columns := csvColumns(reflect.TypeOf((*T)(nil)).Elem())
+	header := make([]string, len(columns))
+	for index, column := range columns {
+		header[index] = column.name
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		value := reflect.ValueOf(record)
+		row := make([]string, len(columns))
+		for index, column := range columns {
+			cell, err := encodeCSVCell(value.Field(column.index))
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", column.name, err)
+			}
+			row[index] = cell
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+func encodeCSVCell(value reflect.Value) (string, error) {
+	switch {
+	case value.Type() == timeType:
+		moment, _ := value.Interface().(time.Time)
+		if moment.IsZero() {
+			return "", nil
+		}
+		return moment.Format(time.RFC3339Nano), nil
+	case value.Kind() == reflect.String:
+		return value.String(), nil
+	case value.Kind() == reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case value.CanInt():
+		return strconv.FormatInt(value.Int(), 10), nil
+	case value.CanFloat():
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), nil
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		items, _ := value.Interface().([]string)
+		return strings.Join(items, csvListSeparator), nil
+	case value.IsZero():
+		return "", nil
+	default:
+		body, err := json.Marshal(value.Interface())
+		return string(body), err
+	}
+}
+
+// decodeCSVRecords reads the rows written by encodeCSVRecords. Columns may
+// come in any order and missing columns keep their zero value.
+func decodeCSVRecords[T any](name string, raw []byte) ([]T, error) {
+	reader := csv.NewReader(bytes.NewReader(raw))
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("read %s header: %w", name, err))
+	}
+
+	known := make(map[string]int)
+	for _, column := range csvColumns(reflect.TypeOf((*T)(nil)).Elem()) {
+		known[column.name] = column.index
+	}
+	fieldIndexes := make([]int, len(header))
+	for index, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		fieldIndex, ok := known[column]
+		if !ok {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("unknown %s column %q", name, column))
+		}
+		fieldIndexes[index] = fieldIndex
+		header[index] = column
+	}
+
+	records := make([]T, 0)
+	for {
+		row, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			return records, nil
+		}
+		if readErr != nil {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("read %s: %w", name, readErr))
+		}
+		line, _ := reader.FieldPos(0)
+		var record T
+		value := reflect.ValueOf(&record).Elem()
+		for index, cell := range row {
+			if err = decodeCSVCell(value.Field(fieldIndexes[index]), cell); err != nil {
+				return nil, errors.Join(domain.ErrValidation, fmt.Errorf("%s line %d column %s: %w", name, line, header[index], err))
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+func decodeCSVCell(field reflect.Value, cell string) error {
+	isStringList := field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String
+	if cell == "" {
+		if isStringList {
+			field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		}
+		return nil
+	}
+	switch {
+	case field.Type() == timeType:
+		moment, err := time.Parse(time.RFC3339Nano, cell)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(moment))
+	case field.Kind() == reflect.String:
+		field.SetString(cell)
+	case field.Kind() == reflect.Bool:
+		flag, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		field.SetBool(flag)
+	case field.CanInt():
+		number, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(number)
+	case field.CanFloat():
+		number, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(number)
+	case isStringList:
+		field.Set(reflect.ValueOf(strings.Split(cell, csvListSeparator)))
+	default:
+		return json.Unmarshal([]byte(cell), field.Addr().Interface())
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package impexp
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+func testTenantSnapshot() domain.TenantSnapshot {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	holdExpiresAt := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	return domain.TenantSnapshot{
+		FormatVersion: domain.TenantSnapshotVersion,
+		Organisation: domain.Organisation{
+			ID:           "org_1",
+			Name:         "Acme",
+			HoursPerDay:  8,
+			HoursPerWeek: 40,
+			HoursPerYear: 2080,
+			CreatedAt:    created,
+			UpdatedAt:    created,
+			Version:      3,
+			WorkSchedules: []domain.WorkSchedule{
+				{Name: "Four days", WorkingDays: []bool{true, true, true, true, false, false, false}},
+			},
+		},
+		Persons: []domain.Person{{
+			ID:            "person_1",
+			Name:          "Ada, Countess",
+			EmploymentPct: 80,
+			WorkSchedule:  "Four days",
+			EmploymentChanges: []domain.EmploymentChange{
+				{EffectiveMonth: "2026-03", EmploymentPct: 60},
+			},
+			CreatedAt: created,
+			UpdatedAt: created,
+			Version:   1,
+		}},
+		Projects: []domain.Project{{ID: "project_1", Name: "Apollo", StartDate: "2026-01-01", EndDate: "2026-12-31", CreatedAt: created, UpdatedAt: created, Version: 1}},
+		Groups: []domain.Group{
+			{ID: "group_1", Name: "Team", MemberIDs: []string{"person_1"}, SubGroupIDs: []string{"group_2"}, CreatedAt: created, UpdatedAt: created, Version: 2},
+			{ID: "group_2", Name: "Empty", MemberIDs: []string{}, CreatedAt: created, UpdatedAt: created, Version: 1},
+		},
+		Allocations: []domain.Allocation{{
+			ID:            "allocation_1",
+			TargetType:    domain.AllocationTargetPerson,
+			TargetID:      "person_1",
+			ProjectID:     "project_1",
+			StartDate:     "2026-01-01",
+			EndDate:       "2026-06-30",
+			Percent:       50,
+			HoldExpiresAt: &holdExpiresAt,
+			CreatedAt:     created,
+			UpdatedAt:     created,
+			Version:       1,
+		}},
+		Holidays:             []domain.OrgHoliday{{ID: "holiday_1", Date: "2026-12-25", Hours: 8, PersonIDs: []string{"person_1"}, CreatedAt: created, UpdatedAt: created, Version: 1}},
+		GroupUnavailability:  []domain.GroupUnavailability{{ID: "gu_1", GroupID: "group_1", Date: "2026-05-01", Hours: 4, CreatedAt: created, UpdatedAt: created, Version: 1}},
+		PersonUnavailability: []domain.PersonUnavailability{{ID: "pu_1", PersonID: "person_1", Date: "2026-05-04", Hours: 2, CreatedAt: created, UpdatedAt: created, Version: 1}},
+	}
+}
+
+// TestTenantSnapshotRoundTrip verifies the tenant snapshot round trip scenario.
+func TestTenantSnapshotRoundTrip(t *testing.T) {
+	adapter := NewCSVImportExport()
+	expected := testTenantSnapshot()
+	for _, format := range []string{domain.SnapshotFormatJSON, domain.SnapshotFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			encoded, err := adapter.EncodeTenantSnapshot(expected, format)
+			if err != nil {
+				t.Fatalf("encode snapshot: %v", err)
+			}
+			decoded, err := adapter.DecodeTenantSnapshot(encoded, format)
+			if err != nil {
+				t.Fatalf("decode snapshot: %v", err)
+			}
+			// Compare the API shape, since CSV cannot tell an omitted list from an empty one.
+			want, _ := json.Marshal(expected)
+			got, _ := json.Marshal(decoded)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("round trip changed the snapshot\nexpected %s\ngot      %s", want, got)
+			}
+		})
+	}
+}
+
+// TestTenantSnapshotDecodeRejectsBadInput verifies the tenant snapshot decode rejects bad input scenario.
+func TestTenantSnapshotDecodeRejectsBadInput(t *testing.T) {
+	adapter := NewCSVImportExport()
+	archive := func(files map[string]string) []byte {
+		var buffer bytes.Buffer
+		writer := zip.NewWriter(&buffer)
+		for name, body := range files {
+			file, err := writer.Create(name)
+			if err != nil {
+				t.Fatalf("create %s: %v", name, err)
+			}
+			if _, err = file.Write([]byte(body)); err != nil {
+				t.Fatalf("write %s: %v", name, err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("close archive: %v", err)
+		}
+		return buffer.Bytes()
+	}
+
+	cases := []struct {
+		name   string
+		raw    []byte
+		format string
+	}{
+		{name: "unsupported format", raw: []byte("{}"), format: "xml"},
+		{name: "unknown json field", raw: []byte(`{"format_version":1,"extra":true}`), format: domain.SnapshotFormatJSON},
+		{name: "not an archive", raw: []byte("id,name\n"), format: domain.SnapshotFormatCSV},
+		{name: "missing organisation", raw: archive(map[string]string{snapshotFilePersons: "id,name\n"}), format: domain.SnapshotFormatCSV},
+		{name: "unknown file", raw: archive(map[string]string{snapshotFileOrganisation: "id,name\norg_1,Acme\n", "notes.txt": "hi"}), format: domain.SnapshotFormatCSV},
+		{name: "unknown column", raw: archive(map[string]string{snapshotFileOrganisation: "id,colour\norg_1,red\n"}), format: domain.SnapshotFormatCSV},
+		{name: "two organisations", raw: archive(map[string]string{snapshotFileOrganisation: "id,name\norg_1,Acme\norg_2,Beta\n"}), format: domain.SnapshotFormatCSV},
+		{name: "bad number", raw: archive(map[string]string{snapshotFileOrganisation: "id,hours_per_day\norg_1,eight\n"}), format: domain.SnapshotFormatCSV},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := adapter.DecodeTenantSnapshot(testCase.raw, testCase.format)
+			if !errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,570 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// Record kinds the caching repository keeps. A write drops the kinds it can
+// change for the organisation it touches.
+const (
+	cacheKindOrganisations        = "organisations"
+	cacheKindOrganisation         = "organisation"
+	cacheKindPersons              = "persons"
+	cacheKindProjects             = "projects"
+	cacheKindGroups               = "groups"
+	cacheKindAllocations          = "allocations"
+	cacheKindOrgHolidays          = "org_holidays"
+	cacheKindGroupUnavailability  = "group_unavailability"
+	cacheKindPersonUnavailability = "person_unavailability"
+	cacheKindUnavailabilityRules  = "unavailability_rules"
+)
+
+// organisationCacheKinds lists the kinds cached per organisation.
+var organisationCacheKinds = []string{
+	cacheKindOrganisation,
+	cacheKindPersons,
+	cacheKindProjects,
+	cacheKindGroups,
+	cacheKindAllocations,
+	cacheKindOrgHolidays,
+	cacheKindGroupUnavailability,
+	cacheKindPersonUnavailability,
+	cacheKindUnavailabilityRules,
+}
+
+// CachingRepository serves organisation lookups and the full per-organisation
+// lists that reports read from memory instead of asking the wrapped
+// repository each time. Every write drops the cached kinds it can change for
+// its organisation, and a conflict drops everything because the wrapped
+// repository may have reloaded changes from another instance. Entries also
+// expire after the TTL so such changes show up without a local write. Paged
+// lists, single records, and all other methods go straight to the wrapped
+// repository.
+type CachingRepository struct {
+	ports.Repository
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu          sync.Mutex
+	entries     map[cacheKey]cacheEntry
+	generations map[cacheKey]uint64
+	epoch       uint64
+}
+
+type cacheKey struct {
+	kind           string
+	organisationID string
+}
+
+type cacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// NewCachingRepository wraps next with a cache whose entries live for ttl.
+func NewCachingRepository(next ports.Repository, ttl time.Duration) *CachingRepository {
+	return &CachingRepository{
+		Repository:  next,
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     map[cacheKey]cacheEntry{},
+		generations: map[cacheKey]uint64{},
+	}
+}
+
+// cachedRead returns a copy of the cached value for key, loading and storing
+// it on a miss. A value loaded while a write dropped the key is returned but
+// not stored, so it cannot hide that write.
+func cachedRead[T any](
+	ctx context.Context,
+	r *CachingRepository,
+	key cacheKey,
+	load func(context.Context) (T, error),
+	clone func(T) T,
+) (T, error) {
+	var zero T
+	if err := contextErr(ctx); err != nil {
+		return zero, err
+	}
+	if cached, ok := r.lookup(key); ok {
+		return clone(cached.(T)), nil
+	}
+
+	generation, epoch := r.generation(key)
+	value, err := load(ctx)
+	if err != nil {
+		return zero, err
+	}
+	r.store(key, generation, epoch, value)
+	return clone(value), nil
+}
+
+func cloneCachedList[T any](copyItem func(T) T) func([]T) []T {
+	return func(items []T) []T {
+		clone := make([]T, 0, len(items))
+		for _, item := range items {
+			clone = append(clone, copyItem(item))
+		}
+		return clone
+	}
+}
+
+func (r *CachingRepository) lookup(key cacheKey) (any, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !r.now().Before(entry.expiresAt) {
+		delete(r.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (r *CachingRepository) generation(key cacheKey) (uint64, uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.generations[key], r.epoch
+}
+
+func (r *CachingRepository) store(key cacheKey, generation, epoch uint64, value any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.generations[key] != generation || r.epoch != epoch {
+		return
+	}
+	r.entries[key] = cacheEntry{value: value, expiresAt: r.now().Add(r.ttl)}
+}
+
+// invalidate drops the given kinds of one organisation after a write. Any
+// conflict drops the whole cache.
+func (r *CachingRepository) invalidate(err error, organisationID string, kinds ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if errors.Is(err, domain.ErrConflict) {
+		r.entries = map[cacheKey]cacheEntry{}
+		r.epoch++
+		return
+	}
+	for _, kind := range kinds {
+		key := cacheKey{kind: kind, organisationID: organisationID}
+		if kind == cacheKindOrganisations {
+			key.organisationID = ""
+		}
+		delete(r.entries, key)
+		r.generations[key]++
+	}
+}
+
+func (r *CachingRepository) invalidateOrganisation(err error, organisationID string) {
+	r.invalidate(err, organisationID, append([]string{cacheKindOrganisations}, organisationCacheKinds...)...)
+}
+
+func keepValue[T any](value T) T {
+	return value
+}
+
+func copyAllocation(allocation domain.Allocation) domain.Allocation {
+	if allocation.HoldExpiresAt != nil {
+		holdExpiresAt := *allocation.HoldExpiresAt
+		allocation.HoldExpiresAt = &holdExpiresAt
+	}
+	allocation.Warnings = append([]string(nil), allocation.Warnings...)
+	return allocation
+}
+
+// ListOrganisations serves the organisation list from the cache.
+func (r *CachingRepository) ListOrganisations(ctx context.Context) ([]domain.Organisation, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindOrganisations},
+		r.Repository.ListOrganisations, cloneCachedList(copyOrganisation))
+}
+
+// GetOrganisation serves one organisation from the cache.
+func (r *CachingRepository) GetOrganisation(ctx context.Context, id string) (domain.Organisation, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindOrganisation, organisationID: id},
+		func(ctx context.Context) (domain.Organisation, error) {
+			return r.Repository.GetOrganisation(ctx, id)
+		}, copyOrganisation)
+}
+
+// CreateOrganisation stores an organisation and drops the cached list.
+func (r *CachingRepository) CreateOrganisation(ctx context.Context, organisation domain.Organisation) (domain.Organisation, error) {
+	created, err := r.Repository.CreateOrganisation(ctx, organisation)
+	r.invalidate(err, created.ID, cacheKindOrganisations)
+	return created, err
+}
+
+// UpdateOrganisation stores an organisation and drops its cached copies.
+func (r *CachingRepository) UpdateOrganisation(ctx context.Context, organisation domain.Organisation) (domain.Organisation, error) {
+	updated, err := r.Repository.UpdateOrganisation(ctx, organisation)
+	r.invalidate(err, organisation.ID, cacheKindOrganisations, cacheKindOrganisation)
+	return updated, err
+}
+
+// DeleteOrganisation removes an organisation and everything cached for it.
+func (r *CachingRepository) DeleteOrganisation(ctx context.Context, id string) error {
+	err := r.Repository.DeleteOrganisation(ctx, id)
+	r.invalidateOrganisation(err, id)
+	return err
+}
+
+// ListPersons serves the persons of an organisation from the cache.
+func (r *CachingRepository) ListPersons(ctx context.Context, organisationID string) ([]domain.Person, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindPersons, organisationID: organisationID},
+		func(ctx context.Context) ([]domain.Person, error) {
+			return r.Repository.ListPersons(ctx, organisationID)
+		}, cloneCachedList(copyPerson))
+}
+
+// CreatePerson stores a person and drops the cached persons.
+func (r *CachingRepository) CreatePerson(ctx context.Context, person domain.Person) (domain.Person, error) {
+	created, err := r.Repository.CreatePerson(ctx, person)
+	r.invalidate(err, person.OrganisationID, cacheKindPersons)
+	return created, err
+}
+
+// CreatePersonWithLimit stores a person within the limit and drops the
+// cached persons.
+func (r *CachingRepository) CreatePersonWithLimit(ctx context.Context, person domain.Person, maxPersons int) (domain.Person, error) {
+	created, err := r.Repository.CreatePersonWithLimit(ctx, person, maxPersons)
+	r.invalidate(err, person.OrganisationID, cacheKindPersons)
+	return created, err
+}
+
+// UpdatePerson stores a person and drops the cached persons.
+func (r *CachingRepository) UpdatePerson(ctx context.Context, person domain.Person) (domain.Person, error) {
+	updated, err := r.Repository.UpdatePerson(ctx, person)
+	r.invalidate(err, person.OrganisationID, cacheKindPersons)
+	return updated, err
+}
+
+// UpdatePersonEndingAllocations stores the changes and drops the cached
+// persons and allocations.
+func (r *CachingRepository) UpdatePersonEndingAllocations(
+	ctx context.Context,
+	person domain.Person,
+	cutoff string,
+	endReason string,
+) (domain.Person, []domain.Allocation, []string, error) {
+	updated, ended, deletedIDs, err := r.Repository.UpdatePersonEndingAllocations(ctx, person, cutoff, endReason)
+	r.invalidate(err, person.OrganisationID, cacheKindPersons, cacheKindAllocations)
+	return updated, ended, deletedIDs, err
+}
+
+// DeletePerson removes a person and drops everything cached for the
+// organisation, since the delete reaches into groups, holidays, allocations,
+// and unavailability.
+func (r *CachingRepository) DeletePerson(ctx context.Context, organisationID, id string, cascade bool) error {
+	err := r.Repository.DeletePerson(ctx, organisationID, id, cascade)
+	r.invalidateOrganisation(err, organisationID)
+	return err
+}
+
+// ListProjects serves the projects of an organisation from the cache.
+func (r *CachingRepository) ListProjects(ctx context.Context, organisationID string) ([]domain.Project, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindProjects, organisationID: organisationID},
+		func(ctx context.Context) ([]domain.Project, error) {
+			return r.Repository.ListProjects(ctx, organisationID)
+		}, cloneCachedList(copyProject))
+}
+
+// CreateProject stores a project and drops the cached projects.
+func (r *CachingRepository) CreateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	created, err := r.Repository.CreateProject(ctx, project)
+	r.invalidate(err, project.OrganisationID, cacheKindProjects)
+	return created, err
+}
+
+// CreateProjectWithLimit stores a project within the limit and drops the
+// cached projects.
+func (r *CachingRepository) CreateProjectWithLimit(ctx context.Context, project domain.Project, maxProjects int) (domain.Project, error) {
+	created, err := r.Repository.CreateProjectWithLimit(ctx, project, maxProjects)
+	r.invalidate(err, project.OrganisationID, cacheKindProjects)
+	return created, err
+}
+
+// UpdateProject stores a project and drops the cached projects.
+func (r *CachingRepository) UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	updated, err := r.Repository.UpdateProject(ctx, project)
+	r.invalidate(err, project.OrganisationID, cacheKindProjects)
+	return updated, err
+}
+
+// DeleteProject removes a project with its allocations and drops both
+// cached kinds.
+func (r *CachingRepository) DeleteProject(ctx context.Context, organisationID, id string, cascade bool) error {
+	err := r.Repository.DeleteProject(ctx, organisationID, id, cascade)
+	r.invalidate(err, organisationID, cacheKindProjects, cacheKindAllocations)
+	return err
+}
+
+// ListGroups serves the groups of an organisation from the cache.
+func (r *CachingRepository) ListGroups(ctx context.Context, organisationID string) ([]domain.Group, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindGroups, organisationID: organisationID},
+		func(ctx context.Context) ([]domain.Group, error) {
+			return r.Repository.ListGroups(ctx, organisationID)
+		}, cloneCachedList(copyGroup))
+}
+
+// CreateGroup stores a group and drops the cached groups.
+func (r *CachingRepository) CreateGroup(ctx context.Context, group domain.Group) (domain.Group, error) {
+	created, err := r.Repository.CreateGroup(ctx, group)
+	r.invalidate(err, group.OrganisationID, cacheKindGroups)
+	return created, err
+}
+
+// UpdateGroup stores a group and drops the cached groups.
+func (r *CachingRepository) UpdateGroup(ctx context.Context, group domain.Group) (domain.Group, error) {
+	updated, err := r.Repository.UpdateGroup(ctx, group)
+	r.invalidate(err, group.OrganisationID, cacheKindGroups)
+	return updated, err
+}
+
+// DeleteGroup removes a group and drops everything cached for the
+// organisation, since the delete reaches into subgroups, allocations,
+// unavailability, and rules.
+func (r *CachingRepository) DeleteGroup(ctx context.Context, organisationID, id string, cascade bool) error {
+	err := r.Repository.DeleteGroup(ctx, organisationID, id, cascade)
+	r.invalidateOrganisation(err, organisationID)
+	return err
+}
+
+// ListAllocations serves the allocations of an organisation from the cache.
+func (r *CachingRepository) ListAllocations(ctx context.Context, organisationID string) ([]domain.Allocation, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindAllocations, organisationID: organisationID},
+		func(ctx context.Context) ([]domain.Allocation, error) {
+			return r.Repository.ListAllocations(ctx, organisationID)
+		}, cloneCachedList(copyAllocation))
+}
+
+// CreateAllocation stores an allocation and drops the cached allocations.
+func (r *CachingRepository) CreateAllocation(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error) {
+	created, err := r.Repository.CreateAllocation(ctx, allocation)
+	r.invalidate(err, allocation.OrganisationID, cacheKindAllocations)
+	return created, err
+}
+
+// CreateAllocations stores allocations and drops the cached allocations.
+func (r *CachingRepository) CreateAllocations(
+	ctx context.Context,
+	organisationID string,
+	allocations []domain.Allocation,
+) ([]domain.Allocation, error) {
+	created, err := r.Repository.CreateAllocations(ctx, organisationID, allocations)
+	r.invalidate(err, organisationID, cacheKindAllocations)
+	return created, err
+}
+
+// UpdateAllocation stores an allocation and drops the cached allocations.
+func (r *CachingRepository) UpdateAllocation(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error) {
+	updated, err := r.Repository.UpdateAllocation(ctx, allocation)
+	r.invalidate(err, allocation.OrganisationID, cacheKindAllocations)
+	return updated, err
+}
+
+// UpdateAllocations stores allocations and drops the cached allocations.
+func (r *CachingRepository) UpdateAllocations(
+	ctx context.Context,
+	organisationID string,
+	allocations []domain.Allocation,
+) ([]domain.Allocation, error) {
+	updated, err := r.Repository.UpdateAllocations(ctx, organisationID, allocations)
+	r.invalidate(err, organisationID, cacheKindAllocations)
+	return updated, err
+}
+
+// DeleteAllocation removes an allocation and drops the cached allocations.
+func (r *CachingRepository) DeleteAllocation(ctx context.Context, organisationID, id string) error {
+	err := r.Repository.DeleteAllocation(ctx, organisationID, id)
+	r.invalidate(err, organisationID, cacheKindAllocations)
+	return err
+}
+
+// RestoreAllocation stores a deleted allocation again and drops the cached
+// allocations.
+func (r *CachingRepository) RestoreAllocation(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error) {
+	restored, err := r.Repository.RestoreAllocation(ctx, allocation)
+	r.invalidate(err, allocation.OrganisationID, cacheKindAllocations)
+	return restored, err
+}
+
+// ApplyScenario writes a scenario and drops the cached allocations.
+func (r *CachingRepository) ApplyScenario(ctx context.Context, scenario domain.Scenario) (domain.ScenarioChanges, error) {
+	changes, err := r.Repository.ApplyScenario(ctx, scenario)
+	r.invalidate(err, scenario.OrganisationID, cacheKindAllocations)
+	return changes, err
+}
+
+// ListOrgHolidays serves the holidays of an organisation from the cache.
+func (r *CachingRepository) ListOrgHolidays(ctx context.Context, organisationID string) ([]domain.OrgHoliday, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindOrgHolidays, organisationID: organisationID},
+		func(ctx context.Context) ([]domain.OrgHoliday, error) {
+			return r.Repository.ListOrgHolidays(ctx, organisationID)
+		}, cloneCachedList(copyOrgHoliday))
+}
+
+// CreateOrgHoliday stores a holiday and drops the cached holidays.
+func (r *CachingRepository) CreateOrgHoliday(ctx context.Context, entry domain.OrgHoliday) (domain.OrgHoliday, error) {
+	created, err := r.Repository.CreateOrgHoliday(ctx, entry)
+	r.invalidate(err, entry.OrganisationID, cacheKindOrgHolidays)
+	return created, err
+}
+
+// CreateOrgHolidays stores holidays and drops the cached holidays.
+func (r *CachingRepository) CreateOrgHolidays(
+	ctx context.Context,
+	organisationID string,
+	entries []domain.OrgHoliday,
+) ([]domain.OrgHoliday, error) {
+	created, err := r.Repository.CreateOrgHolidays(ctx, organisationID, entries)
+	r.invalidate(err, organisationID, cacheKindOrgHolidays)
+	return created, err
+}
+
+// DeleteOrgHoliday removes a holiday and drops the cached holidays.
+func (r *CachingRepository) DeleteOrgHoliday(ctx context.Context, organisationID, id string) error {
+	err := r.Repository.DeleteOrgHoliday(ctx, organisationID, id)
+	r.invalidate(err, organisationID, cacheKindOrgHolidays)
+	return err
+}
+
+// ListGroupUnavailability serves group unavailability from the cache.
+func (r *CachingRepository) ListGroupUnavailability(ctx context.Context, organisationID string) ([]domain.GroupUnavailability, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindGroupUnavailability, organisationID: organisationID},
+		func(ctx context.Context) ([]domain.GroupUnavailability, error) {
+			return r.Repository.ListGroupUnavailability(ctx, organisationID)
+		}, cloneCachedList(keepValue[domain.GroupUnavailability]))
+}
+
+// CreateGroupUnavailability stores an entry and drops the cached group
+// unavailability.
+func (r *CachingRepository) CreateGroupUnavailability(
+	ctx context.Context,
+	entry domain.GroupUnavailability,
+) (domain.GroupUnavailability, error) {
+	created, err := r.Repository.CreateGroupUnavailability(ctx, entry)
+	r.invalidate(err, entry.OrganisationID, cacheKindGroupUnavailability)
+	return created, err
+}
+
+// DeleteGroupUnavailability removes an entry and drops the cached group
+// unavailability.
+func (r *CachingRepository) DeleteGroupUnavailability(ctx context.Context, organisationID, id string) error {
+	err := r.Repository.DeleteGroupUnavailability(ctx, organisationID, id)
+	r.invalidate(err, organisationID, cacheKindGroupUnavailability)
+	return err
+}
+
+// ListPersonUnavailability serves person unavailability from the cache.
+func (r *CachingRepository) ListPersonUnavailability(ctx context.Context, organisationID string) ([]domain.PersonUnavailability, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindPersonUnavailability, organisationID: organisationID},
+		func(ctx context.Context) ([]domain.PersonUnavailability, error) {
+			return r.Repository.ListPersonUnavailability(ctx, organisationID)
+		}, cloneCachedList(keepValue[domain.PersonUnavailability]))
+}
+
+// CreatePersonUnavailability stores an entry and drops the cached person
+// unavailability.
+func (r *CachingRepository) CreatePersonUnavailability(
+	ctx context.Context,
+	entry domain.PersonUnavailability,
+) (domain.PersonUnavailability, error) {
+	created, err := r.Repository.CreatePersonUnavailability(ctx, entry)
+	r.invalidate(err, entry.OrganisationID, cacheKindPersonUnavailability)
+	return created, err
+}
+
+// CreatePersonUnavailabilityWithDailyLimit stores an entry and drops the
+// cached person unavailability.
+func (r *CachingRepository) CreatePersonUnavailabilityWithDailyLimit(
+	ctx context.Context,
+	entry domain.PersonUnavailability,
+	maxHours float64,
+) (domain.PersonUnavailability, error) {
+	created, err := r.Repository.CreatePersonUnavailabilityWithDailyLimit(ctx, entry, maxHours)
+	r.invalidate(err, entry.OrganisationID, cacheKindPersonUnavailability)
+	return created, err
+}
+
+// DeletePersonUnavailability removes an entry and drops the cached person
+// unavailability.
+func (r *CachingRepository) DeletePersonUnavailability(ctx context.Context, organisationID, id string) error {
+	err := r.Repository.DeletePersonUnavailability(ctx, organisationID, id)
+	r.invalidate(err, organisationID, cacheKindPersonUnavailability)
+	return err
+}
+
+// DeletePersonUnavailabilityByPerson removes an entry and drops the cached
+// person unavailability.
+func (r *CachingRepository) DeletePersonUnavailabilityByPerson(ctx context.Context, organisationID, personID, id string) error {
+	err := r.Repository.DeletePersonUnavailabilityByPerson(ctx, organisationID, personID, id)
+	r.invalidate(err, organisationID, cacheKindPersonUnavailability)
+	return err
+}
+
+// ListUnavailabilityRules serves unavailability rules from the cache.
+func (r *CachingRepository) ListUnavailabilityRules(ctx context.Context, organisationID string) ([]domain.UnavailabilityRule, error) {
+	return cachedRead(ctx, r, cacheKey{kind: cacheKindUnavailabilityRules, organisationID: organisationID},
+		func(ctx context.Context) ([]domain.UnavailabilityRule, error) {
+			return r.Repository.ListUnavailabilityRules(ctx, organisationID)
+		}, cloneCachedList(copyUnavailabilityRule))
+}
+
+// CreateUnavailabilityRule stores a rule and drops the cached rules.
+func (r *CachingRepository) CreateUnavailabilityRule(
+	ctx context.Context,
+	rule domain.UnavailabilityRule,
+) (domain.UnavailabilityRule, error) {
+	created, err := r.Repository.CreateUnavailabilityRule(ctx, rule)
+	r.invalidate(err, rule.OrganisationID, cacheKindUnavailabilityRules)
+	return created, err
+}
+
+// UpdateUnavailabilityRule stores a rule and drops the cached rules.
+func (r *CachingRepository) UpdateUnavailabilityRule(
+	ctx context.Context,
+	rule domain.UnavailabilityRule,
+) (domain.UnavailabilityRule, error) {
+	updated, err := r.Repository.UpdateUnavailabilityRule(ctx, rule)
+	r.invalidate(err, rule.OrganisationID, cacheKindUnavailabilityRules)
+	return updated, err
+}
+
+// DeleteUnavailabilityRule removes a rule and drops the cached rules.
+func (r *CachingRepository) DeleteUnavailabilityRule(ctx context.Context, organisationID, id string) error {
+	err := r.Repository.DeleteUnavailabilityRule(ctx, organisationID, id)
+	r.invalidate(err, organisationID, cacheKindUnavailabilityRules)
+	return err
+}
+
+// PurgeCalendarEntriesBefore removes old calendar entries and drops the
+// cached holidays and unavailability.
+func (r *CachingRepository) PurgeCalendarEntriesBefore(
+	ctx context.Context,
+	organisationID, cutoffDate string,
+) (domain.CalendarPurgeResult, error) {
+	result, err := r.Repository.PurgeCalendarEntriesBefore(ctx, organisationID, cutoffDate)
+	r.invalidate(err, organisationID, cacheKindOrgHolidays, cacheKindGroupUnavailability, cacheKindPersonUnavailability)
+	return result, err
+}
+
+// ImportTenant stores a new organisation and drops the cached organisation
+// list.
+func (r *CachingRepository) ImportTenant(ctx context.Context, snapshot domain.TenantSnapshot) (domain.TenantSnapshot, error) {
+	imported, err := r.Repository.ImportTenant(ctx, snapshot)
+	r.invalidateOrganisation(err, imported.Organisation.ID)
+	return imported, err
+}
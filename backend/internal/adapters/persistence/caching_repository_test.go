@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// countingRepository counts the list calls that reach the wrapped repository.
+type countingRepository struct {
+	ports.Repository
+	personLists     int
+	allocationLists int
+}
+
+func (r *countingRepository) ListPersons(ctx context.Context, organisationID string) ([]domain.Person, error) {
+	r.personLists++
+	return r.Repository.ListPersons(ctx, organisationID)
+}
+
+func (r *countingRepository) ListAllocations(ctx context.Context, organisationID string) ([]domain.Allocation, error) {
+	r.allocationLists++
+	return r.Repository.ListAllocations(ctx, organisationID)
+}
+
+// TestCachingRepositoryInvalidatesPerKind verifies the caching repository hit, invalidation, and expiry scenario.
+func TestCachingRepositoryInvalidatesPerKind(t *testing.T) {
+	ctx := context.Background()
+	fileRepo, err := NewFileRepository(filepath.Join(t.TempDir(), testRepoFileName))
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	counting := &countingRepository{Repository: fileRepo}
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	repo := NewCachingRepository(counting, time.Minute)
+	repo.now = func() time.Time { return now }
+
+	org, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	person, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: org.ID, Name: "Alice"})
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+
+	listPersons := func() []domain.Person {
+		t.Helper()
+		persons, listErr := repo.ListPersons(ctx, org.ID)
+		if listErr != nil {
+			t.Fatalf("list persons: %v", listErr)
+		}
+		return persons
+	}
+
+	persons := listPersons()
+	persons[0].Name = "Changed by caller"
+	if again := listPersons(); again[0].Name != "Alice" || counting.personLists != 1 {
+		t.Fatalf("expected one cached read returning a private copy, got %+v after %d reads", again, counting.personLists)
+	}
+
+	if _, err = repo.CreateProject(ctx, domain.Project{OrganisationID: org.ID, Name: "Apollo", EstimatedEffortHours: 100}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	listPersons()
+	if counting.personLists != 1 {
+		t.Fatalf("expected a project write to keep cached persons, got %d reads", counting.personLists)
+	}
+
+	person.Name = "Alicia"
+	if _, err = repo.UpdatePerson(ctx, person); err != nil {
+		t.Fatalf("update person: %v", err)
+	}
+	if updated := listPersons(); updated[0].Name != "Alicia" || counting.personLists != 2 {
+		t.Fatalf("expected a person write to drop cached persons, got %+v after %d reads", updated, counting.personLists)
+	}
+
+	if _, err = repo.ListAllocations(ctx, org.ID); err != nil {
+		t.Fatalf("list allocations: %v", err)
+	}
+	if err = repo.DeletePerson(ctx, org.ID, person.ID, true); err != nil {
+		t.Fatalf("delete person: %v", err)
+	}
+	if _, err = repo.ListAllocations(ctx, org.ID); err != nil {
+		t.Fatalf("list allocations: %v", err)
+	}
+	if counting.allocationLists != 2 {
+		t.Fatalf("expected a person delete to drop cached allocations, got %d reads", counting.allocationLists)
+	}
+
+	listPersons()
+	now = now.Add(time.Minute)
+	listPersons()
+	if counting.personLists != 4 {
+		t.Fatalf("expected an expired entry to be read again, got %d reads", counting.personLists)
+	}
+}
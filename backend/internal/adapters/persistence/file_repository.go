@@ -1,10 +1,12 @@
 package persistence
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
 )
 
 type fileState struct {
@@ -24,15 +27,40 @@ type fileState struct {
 	OrgHolidays          map[string]domain.OrgHoliday           `json:"org_holidays"`
 	GroupUnavailability  map[string]domain.GroupUnavailability  `json:"group_unavailability"`
 	PersonUnavailability map[string]domain.PersonUnavailability `json:"person_unavailability"`
+	UnavailabilityRules  map[string]domain.UnavailabilityRule   `json:"unavailability_rules"`
+	AllocationTemplates  map[string]domain.AllocationTemplate   `json:"allocation_templates"`
+	Scenarios            map[string]domain.Scenario             `json:"scenarios"`
+	AllocationEvents     map[string]domain.AllocationEvent      `json:"allocation_events"`
+	Webhooks             map[string]domain.Webhook              `json:"webhooks"`
+	WebhookDeliveries    map[string]domain.WebhookDelivery      `json:"webhook_deliveries"`
+	APIKeys              map[string]domain.APIKey               `json:"api_keys,omitempty"`
 	Sequence             int64                                  `json:"sequence"`
 }
 
-// FileRepository stores backend state in a JSON file on local disk.
+// FileRepository keeps backend state in memory and stores it in a JSON file
+// on local disk, in per-organisation shards, or in an external state store.
 type FileRepository struct {
 	path           string
+	shardDir       string
+	loadedShards   map[string]bool
 	mu             sync.RWMutex
 	state          fileState
 	persistedState fileState
+	// version counts successful writes since the repository was opened, and
+	// reloads of changes other backends stored. It is only changed under mu
+	// together with persistedState.
+	version uint64
+	// diskWrites counts writes that reached the disk. With debounced saves
+	// it grows slower than version.
+	diskWrites uint64
+	// store replaces the file when set. storeRevisions holds the revision
+	// of each organisation persistedState was read or written at.
+	store          stateStore
+	storeRevisions map[string]tenantRevision
+	// observe receives the duration of each state write and shard load
+	// when set.
+	observe func(operation string, duration time.Duration)
+	saveDebounce
 }
 
 const (
@@ -44,14 +72,32 @@ const (
 	orgHolidayIDPrefix           = "org_holiday"
 	groupUnavailabilityIDPrefix  = "group_unavailability"
 	personUnavailabilityIDPrefix = "person_unavailability"
+	unavailabilityRuleIDPrefix   = "unavailability_rule"
+	allocationTemplateIDPrefix   = "allocation_template"
+	scenarioIDPrefix             = "scenario"
+	allocationEventIDPrefix      = "allocation_event"
+	webhookIDPrefix              = "webhook"
+	webhookDeliveryIDPrefix      = "webhook_delivery"
+	apiKeyIDPrefix               = "api_key"
 )
 
-// Close flushes the current in-memory state to disk.
+// Close flushes the current in-memory state to disk, including changes a
+// debounced save has not written yet, and closes the state store if any.
 func (r *FileRepository) Close() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	return r.persistLocked()
+	var err error
+	if r.debounceEnabled() {
+		r.closed = true
+		err = r.flushLocked(context.Background(), true)
+	} else {
+		err = r.persistLocked(context.Background())
+	}
+	if r.store != nil {
+		err = errors.Join(err, r.store.close())
+	}
+	return err
 }
 
 // NewFileRepository returns a file-backed repository for the provided path.
@@ -60,19 +106,7 @@ func NewFileRepository(path string) (*FileRepository, error) {
 		path = "./plato_runtime_data.json"
 	}
 
-	repo := &FileRepository{
-		path: path,
-		state: fileState{
-			Organisations:        map[string]domain.Organisation{},
-			Persons:              map[string]domain.Person{},
-			Projects:             map[string]domain.Project{},
-			Groups:               map[string]domain.Group{},
-			Allocations:          map[string]domain.Allocation{},
-			OrgHolidays:          map[string]domain.OrgHoliday{},
-			GroupUnavailability:  map[string]domain.GroupUnavailability{},
-			PersonUnavailability: map[string]domain.PersonUnavailability{},
-		},
-	}
+	repo := &FileRepository{path: path, state: emptyFileState()}
 	repo.persistedState = cloneFileState(repo.state)
 
 	if err := repo.load(); err != nil {
@@ -82,6 +116,26 @@ func NewFileRepository(path string) (*FileRepository, error) {
 	return repo, nil
 }
 
+func emptyFileState() fileState {
+	return fileState{
+		Organisations:        map[string]domain.Organisation{},
+		Persons:              map[string]domain.Person{},
+		Projects:             map[string]domain.Project{},
+		Groups:               map[string]domain.Group{},
+		Allocations:          map[string]domain.Allocation{},
+		OrgHolidays:          map[string]domain.OrgHoliday{},
+		GroupUnavailability:  map[string]domain.GroupUnavailability{},
+		PersonUnavailability: map[string]domain.PersonUnavailability{},
+		UnavailabilityRules:  map[string]domain.UnavailabilityRule{},
+		AllocationTemplates:  map[string]domain.AllocationTemplate{},
+		Scenarios:            map[string]domain.Scenario{},
+		AllocationEvents:     map[string]domain.AllocationEvent{},
+		Webhooks:             map[string]domain.Webhook{},
+		WebhookDeliveries:    map[string]domain.WebhookDelivery{},
+		APIKeys:              map[string]domain.APIKey{},
+	}
+}
+
 func (r *FileRepository) load() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -89,7 +143,7 @@ func (r *FileRepository) load() error {
 	content, err := os.ReadFile(r.path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return r.persistLocked()
+			return r.persistLocked(context.Background())
 		}
 		return err
 	}
@@ -134,6 +188,27 @@ func (r *FileRepository) ensureMapsLocked() {
 	if r.state.PersonUnavailability == nil {
 		r.state.PersonUnavailability = map[string]domain.PersonUnavailability{}
 	}
+	if r.state.UnavailabilityRules == nil {
+		r.state.UnavailabilityRules = map[string]domain.UnavailabilityRule{}
+	}
+	if r.state.AllocationTemplates == nil {
+		r.state.AllocationTemplates = map[string]domain.AllocationTemplate{}
+	}
+	if r.state.Scenarios == nil {
+		r.state.Scenarios = map[string]domain.Scenario{}
+	}
+	if r.state.AllocationEvents == nil {
+		r.state.AllocationEvents = map[string]domain.AllocationEvent{}
+	}
+	if r.state.Webhooks == nil {
+		r.state.Webhooks = map[string]domain.Webhook{}
+	}
+	if r.state.WebhookDeliveries == nil {
+		r.state.WebhookDeliveries = map[string]domain.WebhookDelivery{}
+	}
+	if r.state.APIKeys == nil {
+		r.state.APIKeys = map[string]domain.APIKey{}
+	}
 }
 
 func (r *FileRepository) nextIDLocked(prefix string) string {
@@ -141,38 +216,108 @@ func (r *FileRepository) nextIDLocked(prefix string) string {
 	return fmt.Sprintf("%s_%d", prefix, r.state.Sequence)
 }
 
-func (r *FileRepository) persistLocked() error {
-	r.ensureMapsLocked()
-	body, err := json.MarshalIndent(r.state, "", "  ")
-	if err != nil {
+func (r *FileRepository) persistLocked(ctx context.Context) error {
+	if err := r.writeStateLocked(ctx); err != nil {
+		r.state = cloneFileState(r.persistedState)
 		return err
 	}
+	return nil
+}
 
-	err = os.MkdirAll(filepath.Dir(r.path), 0o755)
-	if err != nil {
-		r.state = cloneFileState(r.persistedState)
+// SetOperationObserver registers a callback that receives the duration of
+// every state write and shard load. Call it before the repository is shared.
+func (r *FileRepository) SetOperationObserver(observe func(operation string, duration time.Duration)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observe = observe
+}
+
+func (r *FileRepository) observeLocked(operation string, started time.Time) {
+	if r.observe != nil {
+		r.observe(operation, time.Since(started))
+	}
+}
+
+// writeStateLocked writes the in-memory state and records it as persisted.
+// Unlike persistLocked it keeps the in-memory state when the write fails.
+// ctx bounds the save to a state store.
+func (r *FileRepository) writeStateLocked(ctx context.Context) error {
+	defer r.observeLocked("write", time.Now())
+	r.ensureMapsLocked()
+	if r.store != nil {
+		return r.writeStoreLocked(ctx)
+	}
+	if r.shardDir != "" {
+		return r.writeShardsLocked()
+	}
+
+	if err := writeFileAtomic(r.path, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r.state)
+	}); err != nil {
 		return err
 	}
+	r.persistedState = cloneFileState(r.state)
+	r.diskWrites++
 
-	tmp := r.path + ".tmp"
-	err = os.WriteFile(tmp, body, 0o600)
-	if err != nil {
-		_ = os.Remove(tmp)
-		r.state = cloneFileState(r.persistedState)
+	return nil
+}
+
+// writeFileAtomic streams the content from write into a new temporary file
+// next to path, syncs it, and renames it over path. Readers and a crash at
+// any point see either the old or the new file, never a partial one. The
+// temporary file is removed when any step fails.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
 
-	err = os.Rename(tmp, r.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
 	if err != nil {
-		_ = os.Remove(tmp)
-		r.state = cloneFileState(r.persistedState)
 		return err
 	}
-	r.persistedState = cloneFileState(r.state)
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
 
+	buffered := bufio.NewWriter(tmp)
+	if err = write(buffered); err != nil {
+		return err
+	}
+	if err = buffered.Flush(); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	renamed = true
+	syncDir(dir)
 	return nil
 }
 
+// syncDir makes a rename in dir durable. It is best effort because the new
+// file is already in place and some platforms cannot sync directories.
+func syncDir(dir string) {
+	handle, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = handle.Sync()
+	_ = handle.Close()
+}
+
 func contextErr(ctx context.Context) error {
 	if ctx == nil {
 		return nil
@@ -182,17 +327,129 @@ func contextErr(ctx context.Context) error {
 
 func (r *FileRepository) persistLockedWithContext(ctx context.Context) error {
 	if err := contextErr(ctx); err != nil {
-		r.state = cloneFileState(r.persistedState)
+		r.rollbackLocked()
 		return err
 	}
-	return r.persistLocked()
+	if r.debounceEnabled() {
+		r.acceptLocked()
+		r.version++
+		return nil
+	}
+	if err := r.persistLocked(ctx); err != nil {
+		return err
+	}
+	r.version++
+	return nil
+}
+
+// rollbackLocked discards the mutation in progress. Debounced saves may not
+// have written the last accepted state yet, so that state is restored
+// instead of the one on disk.
+func (r *FileRepository) rollbackLocked() {
+	if r.debounceEnabled() {
+		r.state = cloneFileState(r.acceptedState)
+		return
+	}
+	r.state = cloneFileState(r.persistedState)
+}
+
+// StateVersion returns a counter that grows with every successful write.
+// Callers can key caches and entity tags on it. It starts at zero for each
+// process and is not persisted. A store-backed repository first reads what
+// other backends stored, so their writes move the counter too.
+func (r *FileRepository) StateVersion(ctx context.Context) uint64 {
+	_ = r.syncStore(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.version
 }
 
 func copyGroup(group domain.Group) domain.Group {
 	group.MemberIDs = append([]string{}, group.MemberIDs...)
+	if group.SubGroupIDs != nil {
+		group.SubGroupIDs = append([]string{}, group.SubGroupIDs...)
+	}
 	return group
 }
 
+func copyOrgHoliday(holiday domain.OrgHoliday) domain.OrgHoliday {
+	if holiday.PersonIDs != nil {
+		holiday.PersonIDs = append([]string{}, holiday.PersonIDs...)
+	}
+	return holiday
+}
+
+func copyAPIKey(key domain.APIKey) domain.APIKey {
+	key.Roles = append([]string(nil), key.Roles...)
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		key.RevokedAt = &revokedAt
+	}
+	return key
+}
+
+func copyUnavailabilityRule(rule domain.UnavailabilityRule) domain.UnavailabilityRule {
+	rule.Weekdays = append([]string(nil), rule.Weekdays...)
+	return rule
+}
+
+func copyAllocationTemplate(template domain.AllocationTemplate) domain.AllocationTemplate {
+	template.Entries = append([]domain.AllocationTemplateEntry(nil), template.Entries...)
+	return template
+}
+
+func copyScenario(scenario domain.Scenario) domain.Scenario {
+	scenario.Allocations = append([]domain.Allocation{}, scenario.Allocations...)
+	baseVersions := make(map[string]int64, len(scenario.BaseVersions))
+	for id, version := range scenario.BaseVersions {
+		baseVersions[id] = version
+	}
+	scenario.BaseVersions = baseVersions
+	if scenario.AppliedAt != nil {
+		appliedAt := *scenario.AppliedAt
+		scenario.AppliedAt = &appliedAt
+	}
+	return scenario
+}
+
+func copyWebhook(webhook domain.Webhook) domain.Webhook {
+	webhook.Events = append([]string(nil), webhook.Events...)
+	return webhook
+}
+
+func copyProject(project domain.Project) domain.Project {
+	if project.Milestones != nil {
+		project.Milestones = append([]domain.ProjectMilestone{}, project.Milestones...)
+	}
+	if project.ArchivedAt != nil {
+		archivedAt := *project.ArchivedAt
+		project.ArchivedAt = &archivedAt
+	}
+	return project
+}
+
+func copyOrganisation(organisation domain.Organisation) domain.Organisation {
+	if organisation.WriteWindow != nil {
+		window := *organisation.WriteWindow
+		window.Weekdays = append([]string(nil), window.Weekdays...)
+		organisation.WriteWindow = &window
+	}
+	if organisation.WorkSchedules != nil {
+		schedules := make([]domain.WorkSchedule, 0, len(organisation.WorkSchedules))
+		for _, schedule := range organisation.WorkSchedules {
+			schedule.WorkingDays = append([]bool(nil), schedule.WorkingDays...)
+			schedules = append(schedules, schedule)
+		}
+		organisation.WorkSchedules = schedules
+	}
+	if organisation.HoursBaselines != nil {
+		organisation.HoursBaselines = append([]domain.HoursBaseline(nil), organisation.HoursBaselines...)
+	}
+	return organisation
+}
+
 func copyPerson(person domain.Person) domain.Person {
 	person.EmploymentChanges = append([]domain.EmploymentChange{}, person.EmploymentChanges...)
 	return person
@@ -208,17 +465,24 @@ func cloneFileState(state fileState) fileState {
 		OrgHolidays:          make(map[string]domain.OrgHoliday, len(state.OrgHolidays)),
 		GroupUnavailability:  make(map[string]domain.GroupUnavailability, len(state.GroupUnavailability)),
 		PersonUnavailability: make(map[string]domain.PersonUnavailability, len(state.PersonUnavailability)),
+		UnavailabilityRules:  make(map[string]domain.UnavailabilityRule, len(state.UnavailabilityRules)),
+		AllocationTemplates:  make(map[string]domain.AllocationTemplate, len(state.AllocationTemplates)),
+		Scenarios:            make(map[string]domain.Scenario, len(state.Scenarios)),
+		AllocationEvents:     make(map[string]domain.AllocationEvent, len(state.AllocationEvents)),
+		Webhooks:             make(map[string]domain.Webhook, len(state.Webhooks)),
+		WebhookDeliveries:    make(map[string]domain.WebhookDelivery, len(state.WebhookDeliveries)),
+		APIKeys:              make(map[string]domain.APIKey, len(state.APIKeys)),
 		Sequence:             state.Sequence,
 	}
 
 	for id, organisation := range state.Organisations {
-		clone.Organisations[id] = organisation
+		clone.Organisations[id] = copyOrganisation(organisation)
 	}
 	for id, person := range state.Persons {
 		clone.Persons[id] = copyPerson(person)
 	}
 	for id, project := range state.Projects {
-		clone.Projects[id] = project
+		clone.Projects[id] = copyProject(project)
 	}
 	for id, group := range state.Groups {
 		clone.Groups[id] = copyGroup(group)
@@ -227,7 +491,7 @@ func cloneFileState(state fileState) fileState {
 		clone.Allocations[id] = allocation
 	}
 	for id, holiday := range state.OrgHolidays {
-		clone.OrgHolidays[id] = holiday
+		clone.OrgHolidays[id] = copyOrgHoliday(holiday)
 	}
 	for id, entry := range state.GroupUnavailability {
 		clone.GroupUnavailability[id] = entry
@@ -235,6 +499,27 @@ func cloneFileState(state fileState) fileState {
 	for id, entry := range state.PersonUnavailability {
 		clone.PersonUnavailability[id] = entry
 	}
+	for id, rule := range state.UnavailabilityRules {
+		clone.UnavailabilityRules[id] = copyUnavailabilityRule(rule)
+	}
+	for id, template := range state.AllocationTemplates {
+		clone.AllocationTemplates[id] = copyAllocationTemplate(template)
+	}
+	for id, scenario := range state.Scenarios {
+		clone.Scenarios[id] = copyScenario(scenario)
+	}
+	for id, event := range state.AllocationEvents {
+		clone.AllocationEvents[id] = event
+	}
+	for id, webhook := range state.Webhooks {
+		clone.Webhooks[id] = copyWebhook(webhook)
+	}
+	for id, delivery := range state.WebhookDeliveries {
+		clone.WebhookDeliveries[id] = delivery
+	}
+	for id, key := range state.APIKeys {
+		clone.APIKeys[id] = copyAPIKey(key)
+	}
 
 	return clone
 }
@@ -275,6 +560,20 @@ func sortedGroups(items []domain.Group) {
 	})
 }
 
+// sortedAllocationEvents orders events by time and then by the order they
+// were stored in, which the numeric id suffix reflects.
+func sortedAllocationEvents(items []domain.AllocationEvent) {
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].At.Equal(items[j].At) {
+			return items[i].At.Before(items[j].At)
+		}
+		if len(items[i].ID) != len(items[j].ID) {
+			return len(items[i].ID) < len(items[j].ID)
+		}
+		return items[i].ID < items[j].ID
+	})
+}
+
 func sortedAllocations(items []domain.Allocation) {
 	sort.Slice(items, func(i, j int) bool {
 		iTargetType, iTargetID := normalizedAllocationTarget(items[i])
@@ -292,6 +591,15 @@ func sortedAllocations(items []domain.Allocation) {
 	})
 }
 
+// nextVersion checks that an update was made against the stored version and
+// returns the version to store next. An expected version of 0 skips the check.
+func nextVersion(expected, stored int64) (int64, error) {
+	if expected != 0 && expected != stored {
+		return 0, domain.ErrConflict
+	}
+	return stored + 1, nil
+}
+
 func normalizedAllocationTarget(allocation domain.Allocation) (targetType string, targetID string) {
 	targetType = strings.TrimSpace(allocation.TargetType)
 	targetID = strings.TrimSpace(allocation.TargetID)
@@ -346,13 +654,16 @@ func (r *FileRepository) ListOrganisations(ctx context.Context) ([]domain.Organi
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.syncStore(ctx); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	result := make([]domain.Organisation, 0, len(r.state.Organisations))
 	for _, organisation := range r.state.Organisations {
-		result = append(result, organisation)
+		result = append(result, copyOrganisation(organisation))
 	}
 	sortedOrganisations(result)
 	return result, nil
@@ -363,6 +674,9 @@ func (r *FileRepository) GetOrganisation(ctx context.Context, id string) (domain
 	if err := contextErr(ctx); err != nil {
 		return domain.Organisation{}, err
 	}
+	if err := r.syncStore(ctx); err != nil {
+		return domain.Organisation{}, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -371,7 +685,7 @@ func (r *FileRepository) GetOrganisation(ctx context.Context, id string) (domain
 	if !ok {
 		return domain.Organisation{}, domain.ErrNotFound
 	}
-	return organisation, nil
+	return copyOrganisation(organisation), nil
 }
 
 // CreateOrganisation stores a new organisation.
@@ -379,6 +693,9 @@ func (r *FileRepository) CreateOrganisation(ctx context.Context, organisation do
 	if err := contextErr(ctx); err != nil {
 		return domain.Organisation{}, err
 	}
+	if err := r.syncStore(ctx); err != nil {
+		return domain.Organisation{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -387,7 +704,8 @@ func (r *FileRepository) CreateOrganisation(ctx context.Context, organisation do
 	organisation.ID = r.nextIDLocked(organisationIDPrefix)
 	organisation.CreatedAt = now
 	organisation.UpdatedAt = now
-	r.state.Organisations[organisation.ID] = organisation
+	organisation.Version = 1
+	r.state.Organisations[organisation.ID] = copyOrganisation(organisation)
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
 		return domain.Organisation{}, err
@@ -401,6 +719,9 @@ func (r *FileRepository) UpdateOrganisation(ctx context.Context, organisation do
 	if err := contextErr(ctx); err != nil {
 		return domain.Organisation{}, err
 	}
+	if err := r.syncStore(ctx); err != nil {
+		return domain.Organisation{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -410,9 +731,14 @@ func (r *FileRepository) UpdateOrganisation(ctx context.Context, organisation do
 		return domain.Organisation{}, domain.ErrNotFound
 	}
 
+	version, err := nextVersion(organisation.Version, current.Version)
+	if err != nil {
+		return domain.Organisation{}, err
+	}
+	organisation.Version = version
 	organisation.CreatedAt = current.CreatedAt
 	organisation.UpdatedAt = time.Now().UTC()
-	r.state.Organisations[organisation.ID] = organisation
+	r.state.Organisations[organisation.ID] = copyOrganisation(organisation)
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
 		return domain.Organisation{}, err
@@ -421,11 +747,15 @@ func (r *FileRepository) UpdateOrganisation(ctx context.Context, organisation do
 	return organisation, nil
 }
 
-// DeleteOrganisation removes an organisation and its dependent records.
+// DeleteOrganisation removes an organisation and its dependent records,
+// including its allocation events.
 func (r *FileRepository) DeleteOrganisation(ctx context.Context, id string) error {
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, id); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -448,6 +778,12 @@ func (r *FileRepository) deleteOrganisationResourcesLocked(organisationID string
 	r.deleteOrgHolidaysByOrganisationLocked(organisationID)
 	r.deleteGroupUnavailabilityByOrganisationLocked(organisationID)
 	r.deletePersonUnavailabilityByOrganisationLocked(organisationID)
+	r.deleteUnavailabilityRulesByOrganisationLocked(organisationID)
+	r.deleteAllocationTemplatesByOrganisationLocked(organisationID)
+	r.deleteScenariosByOrganisationLocked(organisationID)
+	r.deleteAllocationEventsByOrganisationLocked(organisationID)
+	r.deleteWebhooksByOrganisationLocked(organisationID)
+	r.deleteAPIKeysByOrganisationLocked(organisationID)
 }
 
 func (r *FileRepository) deletePersonsByOrganisationLocked(organisationID string) {
@@ -482,6 +818,14 @@ func (r *FileRepository) deleteAllocationsByOrganisationLocked(organisationID st
 	}
 }
 
+func (r *FileRepository) deleteAllocationEventsByOrganisationLocked(organisationID string) {
+	for eventID, event := range r.state.AllocationEvents {
+		if event.OrganisationID == organisationID {
+			delete(r.state.AllocationEvents, eventID)
+		}
+	}
+}
+
 func (r *FileRepository) deleteOrgHolidaysByOrganisationLocked(organisationID string) {
 	for holidayID, holiday := range r.state.OrgHolidays {
 		if holiday.OrganisationID == organisationID {
@@ -511,6 +855,9 @@ func (r *FileRepository) ListPersons(ctx context.Context, organisationID string)
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -530,6 +877,9 @@ func (r *FileRepository) GetPerson(ctx context.Context, organisationID, id strin
 	if err := contextErr(ctx); err != nil {
 		return domain.Person{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.Person{}, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -543,17 +893,31 @@ func (r *FileRepository) GetPerson(ctx context.Context, organisationID, id strin
 
 // CreatePerson stores a new person.
 func (r *FileRepository) CreatePerson(ctx context.Context, person domain.Person) (domain.Person, error) {
+	return r.CreatePersonWithLimit(ctx, person, 0)
+}
+
+// CreatePersonWithLimit stores a new person unless the organisation already
+// holds maxPersons persons. A limit of zero or less means no limit.
+func (r *FileRepository) CreatePersonWithLimit(ctx context.Context, person domain.Person, maxPersons int) (domain.Person, error) {
 	if err := contextErr(ctx); err != nil {
 		return domain.Person{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, person.OrganisationID); err != nil {
+		return domain.Person{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if maxPersons > 0 && r.countPersonsLocked(person.OrganisationID) >= maxPersons {
+		return domain.Person{}, domain.QuotaError{Resource: "persons", Limit: maxPersons}
+	}
+
 	now := time.Now().UTC()
 	person.ID = r.nextIDLocked(personIDPrefix)
 	person.CreatedAt = now
 	person.UpdatedAt = now
+	person.Version = 1
 	r.state.Persons[person.ID] = person
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
@@ -563,11 +927,24 @@ func (r *FileRepository) CreatePerson(ctx context.Context, person domain.Person)
 	return person, nil
 }
 
+func (r *FileRepository) countPersonsLocked(organisationID string) int {
+	count := 0
+	for _, person := range r.state.Persons {
+		if person.OrganisationID == organisationID {
+			count++
+		}
+	}
+	return count
+}
+
 // UpdatePerson stores changes to an existing person.
 func (r *FileRepository) UpdatePerson(ctx context.Context, person domain.Person) (domain.Person, error) {
 	if err := contextErr(ctx); err != nil {
 		return domain.Person{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, person.OrganisationID); err != nil {
+		return domain.Person{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -577,6 +954,11 @@ func (r *FileRepository) UpdatePerson(ctx context.Context, person domain.Person)
 		return domain.Person{}, domain.ErrNotFound
 	}
 
+	version, err := nextVersion(person.Version, current.Version)
+	if err != nil {
+		return domain.Person{}, err
+	}
+	person.Version = version
 	person.CreatedAt = current.CreatedAt
 	person.UpdatedAt = time.Now().UTC()
 	r.state.Persons[person.ID] = person
@@ -588,11 +970,93 @@ func (r *FileRepository) UpdatePerson(ctx context.Context, person domain.Person)
 	return person, nil
 }
 
+// UpdatePersonEndingAllocations stores a person and ends the person's own
+// allocations at cutoff in one write. Allocations still running on cutoff end
+// the day before with endReason, and those starting on or after it are
+// deleted. The allocations are picked under the write lock, so one created
+// concurrently is not missed. Nothing is stored when any change fails.
+func (r *FileRepository) UpdatePersonEndingAllocations(
+	ctx context.Context,
+	person domain.Person,
+	cutoff string,
+	endReason string,
+) (domain.Person, []domain.Allocation, []string, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.Person{}, nil, nil, err
+	}
+	cutoffDate, err := time.Parse(domain.DateLayout, cutoff)
+	if err != nil {
+		return domain.Person{}, nil, nil, domain.ErrValidation
+	}
+	if err = r.ensureShardLoaded(ctx, person.OrganisationID); err != nil {
+		return domain.Person{}, nil, nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.state.Persons[person.ID]
+	if !ok || current.OrganisationID != person.OrganisationID {
+		return domain.Person{}, nil, nil, domain.ErrNotFound
+	}
+	version, err := nextVersion(person.Version, current.Version)
+	if err != nil {
+		return domain.Person{}, nil, nil, err
+	}
+	person.Version = version
+	person.CreatedAt = current.CreatedAt
+	person.UpdatedAt = time.Now().UTC()
+	r.state.Persons[person.ID] = person
+
+	lastDay := cutoffDate.AddDate(0, 0, -1).Format(domain.DateLayout)
+	affected := make([]domain.Allocation, 0)
+	for _, allocation := range r.state.Allocations {
+		targetType, targetID := normalizedAllocationTarget(allocation)
+		if allocation.OrganisationID == person.OrganisationID && targetType == domain.AllocationTargetPerson &&
+			targetID == person.ID && allocation.EndDate >= cutoff {
+			affected = append(affected, allocation)
+		}
+	}
+	sortedAllocations(affected)
+
+	ended := make([]domain.Allocation, 0, len(affected))
+	deletedIDs := make([]string, 0)
+	for index := range affected {
+		allocation := affected[index]
+		if allocation.StartDate >= cutoff {
+			delete(r.state.Allocations, allocation.ID)
+			r.recordAllocationEventLocked(ctx, domain.AllocationEventDeleted, &allocation, nil)
+			deletedIDs = append(deletedIDs, allocation.ID)
+			continue
+		}
+		allocation.EndDate = lastDay
+		allocation.EndReason = endReason
+		stored, updateErr := r.updateAllocationLocked(ctx, allocation)
+		if updateErr != nil {
+			r.rollbackLocked()
+			return domain.Person{}, nil, nil, updateErr
+		}
+		ended = append(ended, stored)
+	}
+
+	if err = r.persistLockedWithContext(ctx); err != nil {
+		return domain.Person{}, nil, nil, err
+	}
+
+	return person, ended, deletedIDs, nil
+}
+
 // DeletePerson removes a person and dependent records from one organisation.
-func (r *FileRepository) DeletePerson(ctx context.Context, organisationID, id string) error {
+// Without cascade it fails with a domain.DependentsError while allocations,
+// unavailability entries, or rules still target the person. The check runs
+// in the same write as the delete.
+func (r *FileRepository) DeletePerson(ctx context.Context, organisationID, id string, cascade bool) error {
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -601,11 +1065,16 @@ func (r *FileRepository) DeletePerson(ctx context.Context, organisationID, id st
 	if !ok || person.OrganisationID != organisationID {
 		return domain.ErrNotFound
 	}
+	if dependents := r.personDependentsLocked(organisationID, id); !cascade && !dependents.Empty() {
+		return domain.DependentsError{Resource: "person", Dependents: dependents}
+	}
 	delete(r.state.Persons, id)
 
 	r.removePersonFromOrganisationGroupsLocked(organisationID, id)
-	r.deletePersonAllocationsLocked(organisationID, id)
+	r.deletePersonAllocationsLocked(ctx, organisationID, id)
 	r.deletePersonUnavailabilityLocked(organisationID, id)
+	r.deleteUnavailabilityRulesByTargetLocked(organisationID, id, "")
+	r.removePersonFromOrganisationHolidaysLocked(organisationID, id)
 
 	return r.persistLockedWithContext(ctx)
 }
@@ -616,6 +1085,44 @@ func (r *FileRepository) removePersonFromOrganisationGroupsLocked(organisationID
 			continue
 		}
 		group.MemberIDs = removePersonFromMemberList(group.MemberIDs, personID)
+		group.Version++
+		group.UpdatedAt = time.Now().UTC()
+		r.state.Groups[groupID] = group
+	}
+}
+
+func (r *FileRepository) removePersonFromOrganisationHolidaysLocked(organisationID, personID string) {
+	for holidayID, holiday := range r.state.OrgHolidays {
+		if holiday.OrganisationID != organisationID || len(holiday.PersonIDs) == 0 {
+			continue
+		}
+		remaining := removePersonFromMemberList(holiday.PersonIDs, personID)
+		if len(remaining) == len(holiday.PersonIDs) {
+			continue
+		}
+		// A targeted holiday without targets would widen to the whole organisation.
+		if len(remaining) == 0 {
+			delete(r.state.OrgHolidays, holidayID)
+			continue
+		}
+		holiday.PersonIDs = remaining
+		holiday.Version++
+		holiday.UpdatedAt = time.Now().UTC()
+		r.state.OrgHolidays[holidayID] = holiday
+	}
+}
+
+func (r *FileRepository) removeSubGroupLocked(organisationID, subGroupID string) {
+	for groupID, group := range r.state.Groups {
+		if group.OrganisationID != organisationID || len(group.SubGroupIDs) == 0 {
+			continue
+		}
+		remaining := removePersonFromMemberList(group.SubGroupIDs, subGroupID)
+		if len(remaining) == len(group.SubGroupIDs) {
+			continue
+		}
+		group.SubGroupIDs = remaining
+		group.Version++
 		group.UpdatedAt = time.Now().UTC()
 		r.state.Groups[groupID] = group
 	}
@@ -631,13 +1138,28 @@ func removePersonFromMemberList(memberIDs []string, personID string) []string {
 	return members
 }
 
-func (r *FileRepository) deletePersonAllocationsLocked(organisationID, personID string) {
-	for allocationID, allocation := range r.state.Allocations {
+func (r *FileRepository) deletePersonAllocationsLocked(ctx context.Context, organisationID, personID string) {
+	r.deleteAllocationsLocked(ctx, organisationID, func(allocation domain.Allocation) bool {
 		targetType, targetID := normalizedAllocationTarget(allocation)
-		if allocation.OrganisationID == organisationID && targetType == domain.AllocationTargetPerson && targetID == personID {
-			delete(r.state.Allocations, allocationID)
+		return targetType == domain.AllocationTargetPerson && targetID == personID
+	})
+}
+
+// deleteAllocationsLocked removes the organisation's allocations that match
+// and records a deleted event for each, so cascading deletes show up in the
+// audit trail.
+func (r *FileRepository) deleteAllocationsLocked(ctx context.Context, organisationID string, match func(domain.Allocation) bool) {
+	removed := make([]domain.Allocation, 0)
+	for _, allocation := range r.state.Allocations {
+		if allocation.OrganisationID == organisationID && match(allocation) {
+			removed = append(removed, allocation)
 		}
 	}
+	sortedAllocations(removed)
+	for index := range removed {
+		delete(r.state.Allocations, removed[index].ID)
+		r.recordAllocationEventLocked(ctx, domain.AllocationEventDeleted, &removed[index], nil)
+	}
 }
 
 func (r *FileRepository) deletePersonUnavailabilityLocked(organisationID, personID string) {
@@ -653,6 +1175,9 @@ func (r *FileRepository) ListProjects(ctx context.Context, organisationID string
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -660,7 +1185,7 @@ func (r *FileRepository) ListProjects(ctx context.Context, organisationID string
 	result := make([]domain.Project, 0)
 	for _, project := range r.state.Projects {
 		if project.OrganisationID == organisationID {
-			result = append(result, project)
+			result = append(result, copyProject(project))
 		}
 	}
 	sortedProjects(result)
@@ -672,6 +1197,9 @@ func (r *FileRepository) GetProject(ctx context.Context, organisationID, id stri
 	if err := contextErr(ctx); err != nil {
 		return domain.Project{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.Project{}, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -680,23 +1208,37 @@ func (r *FileRepository) GetProject(ctx context.Context, organisationID, id stri
 	if !ok || project.OrganisationID != organisationID {
 		return domain.Project{}, domain.ErrNotFound
 	}
-	return project, nil
+	return copyProject(project), nil
 }
 
 // CreateProject stores a new project.
 func (r *FileRepository) CreateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	return r.CreateProjectWithLimit(ctx, project, 0)
+}
+
+// CreateProjectWithLimit stores a new project unless the organisation already
+// holds maxProjects projects. A limit of zero or less means no limit.
+func (r *FileRepository) CreateProjectWithLimit(ctx context.Context, project domain.Project, maxProjects int) (domain.Project, error) {
 	if err := contextErr(ctx); err != nil {
 		return domain.Project{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, project.OrganisationID); err != nil {
+		return domain.Project{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if maxProjects > 0 && r.countProjectsLocked(project.OrganisationID) >= maxProjects {
+		return domain.Project{}, domain.QuotaError{Resource: "projects", Limit: maxProjects}
+	}
+
 	now := time.Now().UTC()
 	project.ID = r.nextIDLocked(projectIDPrefix)
 	project.CreatedAt = now
 	project.UpdatedAt = now
-	r.state.Projects[project.ID] = project
+	project.Version = 1
+	r.state.Projects[project.ID] = copyProject(project)
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
 		return domain.Project{}, err
@@ -705,11 +1247,24 @@ func (r *FileRepository) CreateProject(ctx context.Context, project domain.Proje
 	return project, nil
 }
 
+func (r *FileRepository) countProjectsLocked(organisationID string) int {
+	count := 0
+	for _, project := range r.state.Projects {
+		if project.OrganisationID == organisationID {
+			count++
+		}
+	}
+	return count
+}
+
 // UpdateProject stores changes to an existing project.
 func (r *FileRepository) UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
 	if err := contextErr(ctx); err != nil {
 		return domain.Project{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, project.OrganisationID); err != nil {
+		return domain.Project{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -719,9 +1274,14 @@ func (r *FileRepository) UpdateProject(ctx context.Context, project domain.Proje
 		return domain.Project{}, domain.ErrNotFound
 	}
 
+	version, err := nextVersion(project.Version, current.Version)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	project.Version = version
 	project.CreatedAt = current.CreatedAt
 	project.UpdatedAt = time.Now().UTC()
-	r.state.Projects[project.ID] = project
+	r.state.Projects[project.ID] = copyProject(project)
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
 		return domain.Project{}, err
@@ -731,10 +1291,16 @@ func (r *FileRepository) UpdateProject(ctx context.Context, project domain.Proje
 }
 
 // DeleteProject removes a project and dependent records from one organisation.
-func (r *FileRepository) DeleteProject(ctx context.Context, organisationID, id string) error {
+// Without cascade it fails with a domain.DependentsError while allocations are
+// still booked on the project. The check runs in the same write as the
+// delete.
+func (r *FileRepository) DeleteProject(ctx context.Context, organisationID, id string, cascade bool) error {
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -743,13 +1309,13 @@ func (r *FileRepository) DeleteProject(ctx context.Context, organisationID, id s
 	if !ok || project.OrganisationID != organisationID {
 		return domain.ErrNotFound
 	}
-	delete(r.state.Projects, id)
-
-	for allocationID, allocation := range r.state.Allocations {
-		if allocation.OrganisationID == organisationID && allocation.ProjectID == id {
-			delete(r.state.Allocations, allocationID)
-		}
+	if dependents := r.projectDependentsLocked(organisationID, id); !cascade && !dependents.Empty() {
+		return domain.DependentsError{Resource: "project", Dependents: dependents}
 	}
+	delete(r.state.Projects, id)
+	r.deleteAllocationsLocked(ctx, organisationID, func(allocation domain.Allocation) bool {
+		return allocation.ProjectID == id
+	})
 
 	return r.persistLockedWithContext(ctx)
 }
@@ -759,6 +1325,9 @@ func (r *FileRepository) ListGroups(ctx context.Context, organisationID string)
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -778,6 +1347,9 @@ func (r *FileRepository) GetGroup(ctx context.Context, organisationID, id string
 	if err := contextErr(ctx); err != nil {
 		return domain.Group{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.Group{}, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -794,6 +1366,9 @@ func (r *FileRepository) CreateGroup(ctx context.Context, group domain.Group) (d
 	if err := contextErr(ctx); err != nil {
 		return domain.Group{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, group.OrganisationID); err != nil {
+		return domain.Group{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -801,8 +1376,12 @@ func (r *FileRepository) CreateGroup(ctx context.Context, group domain.Group) (d
 	now := time.Now().UTC()
 	group.ID = r.nextIDLocked(groupIDPrefix)
 	group.MemberIDs = uniqueStrings(group.MemberIDs)
+	if group.SubGroupIDs != nil {
+		group.SubGroupIDs = uniqueStrings(group.SubGroupIDs)
+	}
 	group.CreatedAt = now
 	group.UpdatedAt = now
+	group.Version = 1
 	r.state.Groups[group.ID] = copyGroup(group)
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
@@ -817,6 +1396,9 @@ func (r *FileRepository) UpdateGroup(ctx context.Context, group domain.Group) (d
 	if err := contextErr(ctx); err != nil {
 		return domain.Group{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, group.OrganisationID); err != nil {
+		return domain.Group{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -827,6 +1409,14 @@ func (r *FileRepository) UpdateGroup(ctx context.Context, group domain.Group) (d
 	}
 
 	group.MemberIDs = uniqueStrings(group.MemberIDs)
+	if group.SubGroupIDs != nil {
+		group.SubGroupIDs = uniqueStrings(group.SubGroupIDs)
+	}
+	version, err := nextVersion(group.Version, current.Version)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	group.Version = version
 	group.CreatedAt = current.CreatedAt
 	group.UpdatedAt = time.Now().UTC()
 	r.state.Groups[group.ID] = copyGroup(group)
@@ -838,11 +1428,17 @@ func (r *FileRepository) UpdateGroup(ctx context.Context, group domain.Group) (d
 	return group, nil
 }
 
-// DeleteGroup removes a group from one organisation.
-func (r *FileRepository) DeleteGroup(ctx context.Context, organisationID, id string) error {
+// DeleteGroup removes a group from one organisation. Without cascade it fails
+// with a domain.DependentsError while allocations, unavailability entries, or
+// rules still target the group. The check runs in the same write as the
+// delete.
+func (r *FileRepository) DeleteGroup(ctx context.Context, organisationID, id string, cascade bool) error {
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -851,19 +1447,22 @@ func (r *FileRepository) DeleteGroup(ctx context.Context, organisationID, id str
 	if !ok || group.OrganisationID != organisationID {
 		return domain.ErrNotFound
 	}
+	if dependents := r.groupDependentsLocked(organisationID, id); !cascade && !dependents.Empty() {
+		return domain.DependentsError{Resource: "group", Dependents: dependents}
+	}
 	delete(r.state.Groups, id)
+	r.removeSubGroupLocked(organisationID, id)
 
 	for entryID, entry := range r.state.GroupUnavailability {
 		if entry.OrganisationID == organisationID && entry.GroupID == id {
 			delete(r.state.GroupUnavailability, entryID)
 		}
 	}
-	for allocationID, allocation := range r.state.Allocations {
+	r.deleteUnavailabilityRulesByTargetLocked(organisationID, "", id)
+	r.deleteAllocationsLocked(ctx, organisationID, func(allocation domain.Allocation) bool {
 		targetType, targetID := normalizedAllocationTarget(allocation)
-		if allocation.OrganisationID == organisationID && targetType == domain.AllocationTargetGroup && targetID == id {
-			delete(r.state.Allocations, allocationID)
-		}
-	}
+		return targetType == domain.AllocationTargetGroup && targetID == id
+	})
 
 	return r.persistLockedWithContext(ctx)
 }
@@ -873,6 +1472,9 @@ func (r *FileRepository) ListAllocations(ctx context.Context, organisationID str
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -892,6 +1494,9 @@ func (r *FileRepository) GetAllocation(ctx context.Context, organisationID, id s
 	if err := contextErr(ctx); err != nil {
 		return domain.Allocation{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.Allocation{}, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -908,6 +1513,9 @@ func (r *FileRepository) CreateAllocation(ctx context.Context, allocation domain
 	if err := contextErr(ctx); err != nil {
 		return domain.Allocation{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, allocation.OrganisationID); err != nil {
+		return domain.Allocation{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -922,7 +1530,9 @@ func (r *FileRepository) CreateAllocation(ctx context.Context, allocation domain
 	allocation.ID = r.nextIDLocked(allocationIDPrefix)
 	allocation.CreatedAt = now
 	allocation.UpdatedAt = now
+	allocation.Version = 1
 	r.state.Allocations[allocation.ID] = allocation
+	r.recordAllocationEventLocked(ctx, domain.AllocationEventCreated, nil, &allocation)
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
 		return domain.Allocation{}, err
@@ -931,15 +1541,108 @@ func (r *FileRepository) CreateAllocation(ctx context.Context, allocation domain
 	return allocation, nil
 }
 
+// CreateAllocations stores new allocations of one organisation in a single
+// write. Either all of them are stored or none.
+func (r *FileRepository) CreateAllocations(ctx context.Context, organisationID string, allocations []domain.Allocation) ([]domain.Allocation, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.Organisations[organisationID]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	now := time.Now().UTC()
+	created := make([]domain.Allocation, 0, len(allocations))
+	for _, allocation := range allocations {
+		allocation.OrganisationID = organisationID
+		allocation.TargetType, allocation.TargetID = normalizedAllocationTarget(allocation)
+		if allocation.TargetType == domain.AllocationTargetPerson {
+			allocation.PersonID = allocation.TargetID
+		} else {
+			allocation.PersonID = ""
+		}
+		allocation.ID = r.nextIDLocked(allocationIDPrefix)
+		allocation.CreatedAt = now
+		allocation.UpdatedAt = now
+		allocation.Version = 1
+		r.state.Allocations[allocation.ID] = allocation
+		r.recordAllocationEventLocked(ctx, domain.AllocationEventCreated, nil, &allocation)
+		created = append(created, allocation)
+	}
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
 // UpdateAllocation stores changes to an existing allocation.
 func (r *FileRepository) UpdateAllocation(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error) {
 	if err := contextErr(ctx); err != nil {
 		return domain.Allocation{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, allocation.OrganisationID); err != nil {
+		return domain.Allocation{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	updated, err := r.updateAllocationLocked(ctx, allocation)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.Allocation{}, err
+	}
+
+	return updated, nil
+}
+
+// UpdateAllocations stores changes to several allocations of one
+// organisation in a single write. Nothing is stored when any allocation is
+// missing or the write fails.
+func (r *FileRepository) UpdateAllocations(ctx context.Context, organisationID string, allocations []domain.Allocation) ([]domain.Allocation, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]domain.Allocation, 0, len(allocations))
+	for _, allocation := range allocations {
+		if allocation.OrganisationID != organisationID {
+			r.rollbackLocked()
+			return nil, domain.ErrNotFound
+		}
+		updated, err := r.updateAllocationLocked(ctx, allocation)
+		if err != nil {
+			r.rollbackLocked()
+			return nil, err
+		}
+		result = append(result, updated)
+	}
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *FileRepository) updateAllocationLocked(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error) {
 	current, ok := r.state.Allocations[allocation.ID]
 	if !ok || current.OrganisationID != allocation.OrganisationID {
 		return domain.Allocation{}, domain.ErrNotFound
@@ -951,14 +1654,15 @@ func (r *FileRepository) UpdateAllocation(ctx context.Context, allocation domain
 	} else {
 		allocation.PersonID = ""
 	}
+	version, err := nextVersion(allocation.Version, current.Version)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	allocation.Version = version
 	allocation.CreatedAt = current.CreatedAt
 	allocation.UpdatedAt = time.Now().UTC()
 	r.state.Allocations[allocation.ID] = allocation
-
-	if err := r.persistLockedWithContext(ctx); err != nil {
-		return domain.Allocation{}, err
-	}
-
+	r.recordAllocationEventLocked(ctx, domain.AllocationEventUpdated, &current, &allocation)
 	return allocation, nil
 }
 
@@ -967,6 +1671,9 @@ func (r *FileRepository) DeleteAllocation(ctx context.Context, organisationID, i
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -976,14 +1683,88 @@ func (r *FileRepository) DeleteAllocation(ctx context.Context, organisationID, i
 		return domain.ErrNotFound
 	}
 	delete(r.state.Allocations, id)
+	r.recordAllocationEventLocked(ctx, domain.AllocationEventDeleted, &allocation, nil)
 	return r.persistLockedWithContext(ctx)
 }
 
+// RestoreAllocation stores a deleted allocation again under its old ID. The
+// version moves past the deleted one so stale copies still conflict.
+func (r *FileRepository) RestoreAllocation(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.Allocation{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, allocation.OrganisationID); err != nil {
+		return domain.Allocation{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.Organisations[allocation.OrganisationID]; !ok {
+		return domain.Allocation{}, domain.ErrNotFound
+	}
+	if _, ok := r.state.Allocations[allocation.ID]; ok {
+		return domain.Allocation{}, domain.ErrConflict
+	}
+	allocation.Warnings = nil
+	allocation.UpdatedAt = time.Now().UTC()
+	allocation.Version++
+	r.state.Allocations[allocation.ID] = allocation
+	r.recordAllocationEventLocked(ctx, domain.AllocationEventCreated, nil, &allocation)
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.Allocation{}, err
+	}
+
+	return allocation, nil
+}
+
+// recordAllocationEventLocked stores the audit event of an allocation
+// change. The actor and time come from the audit in ctx, and a write without
+// one is recorded without an actor at the current time. It runs before the
+// write is persisted, so the change and its event are saved together or not
+// at all.
+func (r *FileRepository) recordAllocationEventLocked(ctx context.Context, action string, before, after *domain.Allocation) {
+	audit, _ := ports.AllocationAuditFromContext(ctx)
+	if audit.At.IsZero() {
+		audit.At = time.Now().UTC()
+	}
+	event := audit.Event(action, before, after)
+	event.ID = r.nextIDLocked(allocationEventIDPrefix)
+	r.state.AllocationEvents[event.ID] = event
+}
+
+// ListAllocationEvents returns one organisation's allocation audit events,
+// oldest first.
+func (r *FileRepository) ListAllocationEvents(ctx context.Context, organisationID string) ([]domain.AllocationEvent, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.AllocationEvent, 0)
+	for _, event := range r.state.AllocationEvents {
+		if event.OrganisationID == organisationID {
+			result = append(result, event)
+		}
+	}
+	sortedAllocationEvents(result)
+	return result, nil
+}
+
 // ListOrgHolidays returns organisation holiday entries in sorted order.
 func (r *FileRepository) ListOrgHolidays(ctx context.Context, organisationID string) ([]domain.OrgHoliday, error) {
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -991,7 +1772,7 @@ func (r *FileRepository) ListOrgHolidays(ctx context.Context, organisationID str
 	result := make([]domain.OrgHoliday, 0)
 	for _, entry := range r.state.OrgHolidays {
 		if entry.OrganisationID == organisationID {
-			result = append(result, entry)
+			result = append(result, copyOrgHoliday(entry))
 		}
 	}
 	sortedOrgHolidays(result)
@@ -1003,14 +1784,19 @@ func (r *FileRepository) CreateOrgHoliday(ctx context.Context, entry domain.OrgH
 	if err := contextErr(ctx); err != nil {
 		return domain.OrgHoliday{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, entry.OrganisationID); err != nil {
+		return domain.OrgHoliday{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	now := time.Now().UTC()
+	entry = copyOrgHoliday(entry)
 	entry.ID = r.nextIDLocked(orgHolidayIDPrefix)
 	entry.CreatedAt = now
 	entry.UpdatedAt = now
+	entry.Version = 1
 	r.state.OrgHolidays[entry.ID] = entry
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
@@ -1020,11 +1806,50 @@ func (r *FileRepository) CreateOrgHoliday(ctx context.Context, entry domain.OrgH
 	return entry, nil
 }
 
+// CreateOrgHolidays stores several holiday entries of one organisation and
+// persists them once. Either every entry is stored or none is.
+func (r *FileRepository) CreateOrgHolidays(ctx context.Context, organisationID string, entries []domain.OrgHoliday) ([]domain.OrgHoliday, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.Organisations[organisationID]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	now := time.Now().UTC()
+	created := make([]domain.OrgHoliday, 0, len(entries))
+	for _, entry := range entries {
+		entry = copyOrgHoliday(entry)
+		entry.ID = r.nextIDLocked(orgHolidayIDPrefix)
+		entry.OrganisationID = organisationID
+		entry.CreatedAt = now
+		entry.UpdatedAt = now
+		entry.Version = 1
+		r.state.OrgHolidays[entry.ID] = entry
+		created = append(created, copyOrgHoliday(entry))
+	}
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
 // DeleteOrgHoliday removes an organisation holiday entry.
 func (r *FileRepository) DeleteOrgHoliday(ctx context.Context, organisationID, id string) error {
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1042,6 +1867,9 @@ func (r *FileRepository) ListGroupUnavailability(ctx context.Context, organisati
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -1061,6 +1889,9 @@ func (r *FileRepository) CreateGroupUnavailability(ctx context.Context, entry do
 	if err := contextErr(ctx); err != nil {
 		return domain.GroupUnavailability{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, entry.OrganisationID); err != nil {
+		return domain.GroupUnavailability{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1069,6 +1900,7 @@ func (r *FileRepository) CreateGroupUnavailability(ctx context.Context, entry do
 	entry.ID = r.nextIDLocked(groupUnavailabilityIDPrefix)
 	entry.CreatedAt = now
 	entry.UpdatedAt = now
+	entry.Version = 1
 	r.state.GroupUnavailability[entry.ID] = entry
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
@@ -1083,6 +1915,9 @@ func (r *FileRepository) DeleteGroupUnavailability(ctx context.Context, organisa
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1100,6 +1935,9 @@ func (r *FileRepository) ListPersonUnavailability(ctx context.Context, organisat
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -1119,6 +1957,9 @@ func (r *FileRepository) ListPersonUnavailabilityByPerson(ctx context.Context, o
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -1138,6 +1979,9 @@ func (r *FileRepository) ListPersonUnavailabilityByPersonAndDate(ctx context.Con
 	if err := contextErr(ctx); err != nil {
 		return nil, err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -1157,6 +2001,9 @@ func (r *FileRepository) CreatePersonUnavailability(ctx context.Context, entry d
 	if err := contextErr(ctx); err != nil {
 		return domain.PersonUnavailability{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, entry.OrganisationID); err != nil {
+		return domain.PersonUnavailability{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1165,6 +2012,7 @@ func (r *FileRepository) CreatePersonUnavailability(ctx context.Context, entry d
 	entry.ID = r.nextIDLocked(personUnavailabilityIDPrefix)
 	entry.CreatedAt = now
 	entry.UpdatedAt = now
+	entry.Version = 1
 	r.state.PersonUnavailability[entry.ID] = entry
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
@@ -1179,6 +2027,9 @@ func (r *FileRepository) CreatePersonUnavailabilityWithDailyLimit(ctx context.Co
 	if err := contextErr(ctx); err != nil {
 		return domain.PersonUnavailability{}, err
 	}
+	if err := r.ensureShardLoaded(ctx, entry.OrganisationID); err != nil {
+		return domain.PersonUnavailability{}, err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1197,6 +2048,7 @@ func (r *FileRepository) CreatePersonUnavailabilityWithDailyLimit(ctx context.Co
 	entry.ID = r.nextIDLocked(personUnavailabilityIDPrefix)
 	entry.CreatedAt = now
 	entry.UpdatedAt = now
+	entry.Version = 1
 	r.state.PersonUnavailability[entry.ID] = entry
 
 	if err := r.persistLockedWithContext(ctx); err != nil {
@@ -1211,6 +2063,9 @@ func (r *FileRepository) DeletePersonUnavailability(ctx context.Context, organis
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1228,6 +2083,9 @@ func (r *FileRepository) DeletePersonUnavailabilityByPerson(ctx context.Context,
 	if err := contextErr(ctx); err != nil {
 		return err
 	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1243,6 +2101,109 @@ func (r *FileRepository) DeletePersonUnavailabilityByPerson(ctx context.Context,
 	return r.persistLockedWithContext(ctx)
 }
 
+// PurgeCalendarEntriesBefore removes holidays and unavailability entries dated
+// before the cutoff in one persisted write. Allocations are never touched.
+func (r *FileRepository) PurgeCalendarEntriesBefore(ctx context.Context, organisationID, cutoffDate string) (domain.CalendarPurgeResult, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.CalendarPurgeResult{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.CalendarPurgeResult{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := domain.CalendarPurgeResult{
+		OrgHolidays: deleteEntriesWhere(r.state.OrgHolidays, func(entry domain.OrgHoliday) bool {
+			return entry.OrganisationID == organisationID && entry.Date < cutoffDate
+		}),
+		GroupUnavailability: deleteEntriesWhere(r.state.GroupUnavailability, func(entry domain.GroupUnavailability) bool {
+			return entry.OrganisationID == organisationID && entry.Date < cutoffDate
+		}),
+		PersonUnavailability: deleteEntriesWhere(r.state.PersonUnavailability, func(entry domain.PersonUnavailability) bool {
+			return entry.OrganisationID == organisationID && entry.Date < cutoffDate
+		}),
+	}
+
+	if result == (domain.CalendarPurgeResult{}) {
+		return result, nil
+	}
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.CalendarPurgeResult{}, err
+	}
+	return result, nil
+}
+
+// personDependentsLocked lists the allocations that target the person
+// directly and the person's unavailability entries and rules. Group
+// memberships and targeted holidays are not dependents.
+func (r *FileRepository) personDependentsLocked(organisationID, personID string) domain.DeleteDependents {
+	return domain.DeleteDependents{
+		AllocationIDs: entryIDsWhere(r.state.Allocations, func(allocation domain.Allocation) bool {
+			targetType, targetID := normalizedAllocationTarget(allocation)
+			return allocation.OrganisationID == organisationID && targetType == domain.AllocationTargetPerson && targetID == personID
+		}),
+		PersonUnavailabilityIDs: entryIDsWhere(r.state.PersonUnavailability, func(entry domain.PersonUnavailability) bool {
+			return entry.OrganisationID == organisationID && entry.PersonID == personID
+		}),
+		UnavailabilityRuleIDs: entryIDsWhere(r.state.UnavailabilityRules, func(rule domain.UnavailabilityRule) bool {
+			return rule.OrganisationID == organisationID && rule.PersonID == personID && rule.GroupID == ""
+		}),
+	}
+}
+
+// groupDependentsLocked lists the allocations that target the group and the
+// group's unavailability entries and rules. Sub-group links are not
+// dependents.
+func (r *FileRepository) groupDependentsLocked(organisationID, groupID string) domain.DeleteDependents {
+	return domain.DeleteDependents{
+		AllocationIDs: entryIDsWhere(r.state.Allocations, func(allocation domain.Allocation) bool {
+			targetType, targetID := normalizedAllocationTarget(allocation)
+			return allocation.OrganisationID == organisationID && targetType == domain.AllocationTargetGroup && targetID == groupID
+		}),
+		GroupUnavailabilityIDs: entryIDsWhere(r.state.GroupUnavailability, func(entry domain.GroupUnavailability) bool {
+			return entry.OrganisationID == organisationID && entry.GroupID == groupID
+		}),
+		UnavailabilityRuleIDs: entryIDsWhere(r.state.UnavailabilityRules, func(rule domain.UnavailabilityRule) bool {
+			return rule.OrganisationID == organisationID && rule.PersonID == "" && rule.GroupID == groupID
+		}),
+	}
+}
+
+// projectDependentsLocked lists the allocations booked on the project.
+func (r *FileRepository) projectDependentsLocked(organisationID, projectID string) domain.DeleteDependents {
+	return domain.DeleteDependents{
+		AllocationIDs: entryIDsWhere(r.state.Allocations, func(allocation domain.Allocation) bool {
+			return allocation.OrganisationID == organisationID && allocation.ProjectID == projectID
+		}),
+	}
+}
+
+// entryIDsWhere returns the sorted IDs of the entries that match, or nil
+// when none does.
+func entryIDsWhere[T any](entries map[string]T, matches func(T) bool) []string {
+	var ids []string
+	for id, entry := range entries {
+		if matches(entry) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func deleteEntriesWhere[T any](entries map[string]T, matches func(T) bool) int {
+	removed := 0
+	for id, entry := range entries {
+		if matches(entry) {
+			delete(entries, id)
+			removed++
+		}
+	}
+	return removed
+}
+
 func uniqueStrings(values []string) []string {
 	seen := map[string]bool{}
 	result := make([]string, 0, len(values))
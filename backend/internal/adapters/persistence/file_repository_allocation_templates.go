@@ -0,0 +1,113 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// ListAllocationTemplates returns the allocation templates of one
+// organisation ordered by id.
+func (r *FileRepository) ListAllocationTemplates(ctx context.Context, organisationID string) ([]domain.AllocationTemplate, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.AllocationTemplate, 0)
+	for _, template := range r.state.AllocationTemplates {
+		if template.OrganisationID == organisationID {
+			result = append(result, copyAllocationTemplate(template))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessStoredID(result[i].ID, result[j].ID)
+	})
+	return result, nil
+}
+
+// GetAllocationTemplate returns one allocation template.
+func (r *FileRepository) GetAllocationTemplate(ctx context.Context, organisationID, id string) (domain.AllocationTemplate, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, ok := r.state.AllocationTemplates[id]
+	if !ok || template.OrganisationID != organisationID {
+		return domain.AllocationTemplate{}, domain.ErrNotFound
+	}
+	return copyAllocationTemplate(template), nil
+}
+
+// CreateAllocationTemplate stores a new allocation template.
+func (r *FileRepository) CreateAllocationTemplate(ctx context.Context, template domain.AllocationTemplate) (domain.AllocationTemplate, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, template.OrganisationID); err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.Organisations[template.OrganisationID]; !ok {
+		return domain.AllocationTemplate{}, domain.ErrNotFound
+	}
+	now := time.Now().UTC()
+	template = copyAllocationTemplate(template)
+	template.ID = r.nextIDLocked(allocationTemplateIDPrefix)
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	template.Version = 1
+	r.state.AllocationTemplates[template.ID] = template
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+
+	return copyAllocationTemplate(template), nil
+}
+
+// DeleteAllocationTemplate removes an allocation template. Allocations
+// created from it stay.
+func (r *FileRepository) DeleteAllocationTemplate(ctx context.Context, organisationID, id string) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	template, ok := r.state.AllocationTemplates[id]
+	if !ok || template.OrganisationID != organisationID {
+		return domain.ErrNotFound
+	}
+	delete(r.state.AllocationTemplates, id)
+
+	return r.persistLockedWithContext(ctx)
+}
+
+func (r *FileRepository) deleteAllocationTemplatesByOrganisationLocked(organisationID string) {
+	for templateID, template := range r.state.AllocationTemplates {
+		if template.OrganisationID == organisationID {
+			delete(r.state.AllocationTemplates, templateID)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// ListAPIKeys returns one organisation's API keys, revoked ones included,
+// ordered by id.
+func (r *FileRepository) ListAPIKeys(ctx context.Context, organisationID string) ([]domain.APIKey, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.syncStore(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.APIKey, 0)
+	for _, key := range r.state.APIKeys {
+		if key.OrganisationID == organisationID {
+			result = append(result, copyAPIKey(key))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessStoredID(result[i].ID, result[j].ID)
+	})
+	return result, nil
+}
+
+// GetAPIKeyByHash returns the API key with the given hash from any
+// organisation.
+func (r *FileRepository) GetAPIKeyByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.APIKey{}, err
+	}
+	if err := r.syncStore(ctx); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.state.APIKeys {
+		if key.Hash == hash {
+			return copyAPIKey(key), nil
+		}
+	}
+	return domain.APIKey{}, domain.ErrNotFound
+}
+
+// CreateAPIKey stores a new API key. The plain key is never stored.
+func (r *FileRepository) CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.APIKey{}, err
+	}
+	if err := r.syncStore(ctx); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.Organisations[key.OrganisationID]; !ok {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	now := time.Now().UTC()
+	key = copyAPIKey(key)
+	key.ID = r.nextIDLocked(apiKeyIDPrefix)
+	key.Key = ""
+	key.CreatedAt = now
+	key.UpdatedAt = now
+	key.RevokedAt = nil
+	key.Version = 1
+	r.state.APIKeys[key.ID] = key
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	return copyAPIKey(key), nil
+}
+
+// RevokeAPIKey marks an API key as revoked. Revoking a revoked key keeps
+// the first revocation time.
+func (r *FileRepository) RevokeAPIKey(ctx context.Context, organisationID, id string) (domain.APIKey, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.APIKey{}, err
+	}
+	if err := r.syncStore(ctx); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.state.APIKeys[id]
+	if !ok || key.OrganisationID != organisationID {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	if key.RevokedAt != nil {
+		return copyAPIKey(key), nil
+	}
+	now := time.Now().UTC()
+	key = copyAPIKey(key)
+	key.RevokedAt = &now
+	key.UpdatedAt = now
+	key.Version++
+	r.state.APIKeys[id] = key
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	return copyAPIKey(key), nil
+}
+
+func (r *FileRepository) deleteAPIKeysByOrganisationLocked(organisationID string) {
+	for keyID, key := range r.state.APIKeys {
+		if key.OrganisationID == organisationID {
+			delete(r.state.APIKeys, keyID)
+		}
+	}
+}
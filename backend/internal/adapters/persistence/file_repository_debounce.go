@@ -0,0 +1,102 @@
+package persistence
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// saveDebounce holds the debounced save settings of a FileRepository. All
+// fields are guarded by the repository mutex.
+type saveDebounce struct {
+	quietPeriod time.Duration
+	maxDelay    time.Duration
+	timer       *time.Timer
+	dirty       bool
+	dirtySince  time.Time
+	// acceptedState is the state after the last successful mutation. It runs
+	// ahead of persistedState until the pending changes are flushed.
+	acceptedState fileState
+	// closed stops timer flushes from retrying once Close ran.
+	closed bool
+	logf   func(format string, args ...any)
+}
+
+// EnableDebouncedSave makes mutations return once the in-memory state has
+// changed and coalesces their disk writes. Pending changes are flushed after
+// quietPeriod without further mutations, and at the latest maxDelay after
+// the first unflushed change. A zero maxDelay leaves the wait unbounded.
+// A quietPeriod of zero or less keeps every write synchronous. Call it
+// before the repository is shared.
+func (r *FileRepository) EnableDebouncedSave(quietPeriod, maxDelay time.Duration) {
+	if quietPeriod <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.quietPeriod = quietPeriod
+	r.maxDelay = max(maxDelay, 0)
+	r.acceptedState = cloneFileState(r.state)
+	r.logf = log.Printf
+}
+
+func (r *FileRepository) debounceEnabled() bool {
+	return r.quietPeriod > 0
+}
+
+// acceptLocked records the current state as accepted and schedules a flush.
+func (r *FileRepository) acceptLocked() {
+	r.acceptedState = cloneFileState(r.state)
+
+	now := time.Now()
+	if !r.dirty {
+		r.dirty = true
+		r.dirtySince = now
+	}
+
+	delay := r.quietPeriod
+	if r.maxDelay > 0 {
+		remaining := r.dirtySince.Add(r.maxDelay).Sub(now)
+		delay = max(min(delay, remaining), 0)
+	}
+
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.timer = time.AfterFunc(delay, r.flushPending)
+}
+
+// flushPending runs on the debounce timer. A failed write is logged and
+// keeps the changes pending, and another flush is scheduled after the quiet
+// period so acknowledged changes do not wait for the next mutation.
+func (r *FileRepository) flushPending() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+	if err := r.flushLocked(context.Background(), false); err != nil {
+		r.logf("persistence: debounced save failed, retrying in %s: %v", r.quietPeriod, err)
+		r.timer = time.AfterFunc(r.quietPeriod, r.flushPending)
+	}
+}
+
+// flushLocked writes pending changes. With force set the state is written
+// even when nothing is pending, which Close relies on.
+func (r *FileRepository) flushLocked(ctx context.Context, force bool) error {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	if !r.dirty && !force {
+		return nil
+	}
+	if err := r.writeStateLocked(ctx); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
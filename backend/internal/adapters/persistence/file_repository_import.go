@@ -0,0 +1,173 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// ImportTenant stores a snapshot as a new organisation in one write. Every
+// record gets a new ID and references between records are rewritten to
+// match. Nothing is stored when any reference is unknown.
+func (r *FileRepository) ImportTenant(ctx context.Context, snapshot domain.TenantSnapshot) (domain.TenantSnapshot, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	if err := r.syncStore(ctx); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	imported, err := r.importTenantLocked(ctx, snapshot, time.Now().UTC())
+	if err != nil {
+		r.rollbackLocked()
+		return domain.TenantSnapshot{}, err
+	}
+	if r.shardDir != "" {
+		r.loadedShards[imported.Organisation.ID] = true
+	}
+
+	if err = r.persistLockedWithContext(ctx); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	return imported, nil
+}
+
+// tenantIDs maps the IDs of a snapshot to the IDs stored for it.
+type tenantIDs struct {
+	persons  map[string]string
+	projects map[string]string
+	groups   map[string]string
+}
+
+func resolveImportedID(kind string, known map[string]string, id string) (string, error) {
+	mapped, ok := known[id]
+	if !ok {
+		return "", fmt.Errorf("unknown %s %q: %w", kind, id, domain.ErrNotFound)
+	}
+	return mapped, nil
+}
+
+func resolveImportedIDs(kind string, known map[string]string, list []string) ([]string, error) {
+	if list == nil {
+		return nil, nil
+	}
+	mapped := make([]string, 0, len(list))
+	for _, id := range list {
+		resolved, err := resolveImportedID(kind, known, id)
+		if err != nil {
+			return nil, err
+		}
+		mapped = append(mapped, resolved)
+	}
+	return mapped, nil
+}
+
+func (r *FileRepository) importTenantLocked(ctx context.Context, snapshot domain.TenantSnapshot, now time.Time) (domain.TenantSnapshot, error) {
+	organisation := copyOrganisation(snapshot.Organisation)
+	organisation.ID = r.nextIDLocked(organisationIDPrefix)
+	organisation.CreatedAt, organisation.UpdatedAt, organisation.Version = now, now, 1
+	r.state.Organisations[organisation.ID] = copyOrganisation(organisation)
+
+	imported := domain.TenantSnapshot{FormatVersion: snapshot.FormatVersion, Organisation: organisation}
+	ids := tenantIDs{persons: map[string]string{}, projects: map[string]string{}, groups: map[string]string{}}
+	for _, person := range snapshot.Persons {
+		ids.persons[person.ID] = r.nextIDLocked(personIDPrefix)
+	}
+	for _, project := range snapshot.Projects {
+		ids.projects[project.ID] = r.nextIDLocked(projectIDPrefix)
+	}
+	for _, group := range snapshot.Groups {
+		ids.groups[group.ID] = r.nextIDLocked(groupIDPrefix)
+	}
+
+	for _, person := range snapshot.Persons {
+		person = copyPerson(person)
+		person.ID, person.OrganisationID = ids.persons[person.ID], organisation.ID
+		person.CreatedAt, person.UpdatedAt, person.Version = now, now, 1
+		r.state.Persons[person.ID] = person
+		imported.Persons = append(imported.Persons, person)
+	}
+	for _, project := range snapshot.Projects {
+		project = copyProject(project)
+		project.ID, project.OrganisationID = ids.projects[project.ID], organisation.ID
+		project.CreatedAt, project.UpdatedAt, project.Version = now, now, 1
+		r.state.Projects[project.ID] = copyProject(project)
+		imported.Projects = append(imported.Projects, project)
+	}
+	for _, group := range snapshot.Groups {
+		var err error
+		group = copyGroup(group)
+		group.ID, group.OrganisationID = ids.groups[group.ID], organisation.ID
+		if group.MemberIDs, err = resolveImportedIDs("person", ids.persons, group.MemberIDs); err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+		if group.SubGroupIDs, err = resolveImportedIDs("group", ids.groups, group.SubGroupIDs); err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+		group.CreatedAt, group.UpdatedAt, group.Version = now, now, 1
+		r.state.Groups[group.ID] = copyGroup(group)
+		imported.Groups = append(imported.Groups, group)
+	}
+
+	for _, allocation := range snapshot.Allocations {
+		var err error
+		allocation.TargetType, allocation.TargetID = normalizedAllocationTarget(allocation)
+		if allocation.TargetType == domain.AllocationTargetGroup {
+			allocation.TargetID, err = resolveImportedID("group", ids.groups, allocation.TargetID)
+			allocation.PersonID = ""
+		} else {
+			allocation.TargetID, err = resolveImportedID("person", ids.persons, allocation.TargetID)
+			allocation.PersonID = allocation.TargetID
+		}
+		if err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+		if allocation.ProjectID, err = resolveImportedID("project", ids.projects, allocation.ProjectID); err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+		allocation.ID, allocation.OrganisationID = r.nextIDLocked(allocationIDPrefix), organisation.ID
+		allocation.Warnings = nil
+		allocation.CreatedAt, allocation.UpdatedAt, allocation.Version = now, now, 1
+		r.state.Allocations[allocation.ID] = allocation
+		r.recordAllocationEventLocked(ctx, domain.AllocationEventCreated, nil, &allocation)
+		imported.Allocations = append(imported.Allocations, allocation)
+	}
+
+	for _, holiday := range snapshot.Holidays {
+		var err error
+		holiday = copyOrgHoliday(holiday)
+		if holiday.PersonIDs, err = resolveImportedIDs("person", ids.persons, holiday.PersonIDs); err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+		holiday.ID, holiday.OrganisationID = r.nextIDLocked(orgHolidayIDPrefix), organisation.ID
+		holiday.CreatedAt, holiday.UpdatedAt, holiday.Version = now, now, 1
+		r.state.OrgHolidays[holiday.ID] = copyOrgHoliday(holiday)
+		imported.Holidays = append(imported.Holidays, holiday)
+	}
+	for _, entry := range snapshot.GroupUnavailability {
+		var err error
+		if entry.GroupID, err = resolveImportedID("group", ids.groups, entry.GroupID); err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+		entry.ID, entry.OrganisationID = r.nextIDLocked(groupUnavailabilityIDPrefix), organisation.ID
+		entry.CreatedAt, entry.UpdatedAt, entry.Version = now, now, 1
+		r.state.GroupUnavailability[entry.ID] = entry
+		imported.GroupUnavailability = append(imported.GroupUnavailability, entry)
+	}
+	for _, entry := range snapshot.PersonUnavailability {
+		var err error
+		if entry.PersonID, err = resolveImportedID("person", ids.persons, entry.PersonID); err != nil {
+			return domain.TenantSnapshot{}, err
+		}
+		entry.ID, entry.OrganisationID = r.nextIDLocked(personUnavailabilityIDPrefix), organisation.ID
+		entry.CreatedAt, entry.UpdatedAt, entry.Version = now, now, 1
+		r.state.PersonUnavailability[entry.ID] = entry
+		imported.PersonUnavailability = append(imported.PersonUnavailability, entry)
+	}
+	return imported, nil
+}
@@ -0,0 +1,44 @@
+package persistence
+
+import (
+	"context"
+
+	"plato/backend/internal/domain"
+)
+
+// ListPersonsPage returns one page of an organisation's people. Records keep
+// the ListPersons order wherever the query sort leaves them tied.
+func (r *FileRepository) ListPersonsPage(ctx context.Context, organisationID string, query domain.ListQuery) (domain.ListPage[domain.Person], error) {
+	persons, err := r.ListPersons(ctx, organisationID)
+	if err != nil {
+		return domain.ListPage[domain.Person]{}, err
+	}
+	return domain.PageList(persons, query)
+}
+
+// ListProjectsPage returns one page of an organisation's projects.
+func (r *FileRepository) ListProjectsPage(ctx context.Context, organisationID string, query domain.ListQuery) (domain.ListPage[domain.Project], error) {
+	projects, err := r.ListProjects(ctx, organisationID)
+	if err != nil {
+		return domain.ListPage[domain.Project]{}, err
+	}
+	return domain.PageList(projects, query)
+}
+
+// ListGroupsPage returns one page of an organisation's groups.
+func (r *FileRepository) ListGroupsPage(ctx context.Context, organisationID string, query domain.ListQuery) (domain.ListPage[domain.Group], error) {
+	groups, err := r.ListGroups(ctx, organisationID)
+	if err != nil {
+		return domain.ListPage[domain.Group]{}, err
+	}
+	return domain.PageList(groups, query)
+}
+
+// ListAllocationsPage returns one page of an organisation's allocations.
+func (r *FileRepository) ListAllocationsPage(ctx context.Context, organisationID string, query domain.ListQuery) (domain.ListPage[domain.Allocation], error) {
+	allocations, err := r.ListAllocations(ctx, organisationID)
+	if err != nil {
+		return domain.ListPage[domain.Allocation]{}, err
+	}
+	return domain.PageList(allocations, query)
+}
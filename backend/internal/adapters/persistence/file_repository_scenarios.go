@@ -0,0 +1,249 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// ListScenarios returns the scenarios of one organisation ordered by id.
+func (r *FileRepository) ListScenarios(ctx context.Context, organisationID string) ([]domain.Scenario, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.Scenario, 0)
+	for _, scenario := range r.state.Scenarios {
+		if scenario.OrganisationID == organisationID {
+			result = append(result, copyScenario(scenario))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessStoredID(result[i].ID, result[j].ID)
+	})
+	return result, nil
+}
+
+// GetScenario returns one scenario with its allocations.
+func (r *FileRepository) GetScenario(ctx context.Context, organisationID, id string) (domain.Scenario, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.Scenario{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.Scenario{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scenario, ok := r.state.Scenarios[id]
+	if !ok || scenario.OrganisationID != organisationID {
+		return domain.Scenario{}, domain.ErrNotFound
+	}
+	return copyScenario(scenario), nil
+}
+
+// CreateScenario stores a new scenario.
+func (r *FileRepository) CreateScenario(ctx context.Context, scenario domain.Scenario) (domain.Scenario, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.Scenario{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, scenario.OrganisationID); err != nil {
+		return domain.Scenario{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.Organisations[scenario.OrganisationID]; !ok {
+		return domain.Scenario{}, domain.ErrNotFound
+	}
+	now := time.Now().UTC()
+	scenario = copyScenario(scenario)
+	scenario.ID = r.nextIDLocked(scenarioIDPrefix)
+	scenario.AppliedAt = nil
+	scenario.CreatedAt = now
+	scenario.UpdatedAt = now
+	scenario.Version = 1
+	r.state.Scenarios[scenario.ID] = scenario
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.Scenario{}, err
+	}
+
+	return copyScenario(scenario), nil
+}
+
+// UpdateScenario stores the name and allocations of a scenario. Applied
+// scenarios can no longer be changed.
+func (r *FileRepository) UpdateScenario(ctx context.Context, scenario domain.Scenario) (domain.Scenario, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.Scenario{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, scenario.OrganisationID); err != nil {
+		return domain.Scenario{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.state.Scenarios[scenario.ID]
+	if !ok || current.OrganisationID != scenario.OrganisationID {
+		return domain.Scenario{}, domain.ErrNotFound
+	}
+	if current.AppliedAt != nil {
+		return domain.Scenario{}, domain.ErrConflict
+	}
+	version, err := nextVersion(scenario.Version, current.Version)
+	if err != nil {
+		return domain.Scenario{}, err
+	}
+	scenario = copyScenario(scenario)
+	scenario.BaseVersions = current.BaseVersions
+	scenario.AppliedAt = nil
+	scenario.Version = version
+	scenario.CreatedAt = current.CreatedAt
+	scenario.UpdatedAt = time.Now().UTC()
+	r.state.Scenarios[scenario.ID] = scenario
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.Scenario{}, err
+	}
+
+	return copyScenario(scenario), nil
+}
+
+// DeleteScenario removes a scenario. Live allocations are not touched.
+func (r *FileRepository) DeleteScenario(ctx context.Context, organisationID, id string) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scenario, ok := r.state.Scenarios[id]
+	if !ok || scenario.OrganisationID != organisationID {
+		return domain.ErrNotFound
+	}
+	delete(r.state.Scenarios, id)
+
+	return r.persistLockedWithContext(ctx)
+}
+
+// ApplyScenario writes the changes of a scenario to the live allocations and
+// marks the scenario applied in a single write. The scenario must still have
+// the given version, and every live allocation it updates or deletes must
+// still have the version it was cloned at. Nothing is stored otherwise. The
+// returned changes hold the live allocations as stored.
+func (r *FileRepository) ApplyScenario(ctx context.Context, scenario domain.Scenario) (domain.ScenarioChanges, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.ScenarioChanges{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, scenario.OrganisationID); err != nil {
+		return domain.ScenarioChanges{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.state.Scenarios[scenario.ID]
+	if !ok || current.OrganisationID != scenario.OrganisationID {
+		return domain.ScenarioChanges{}, domain.ErrNotFound
+	}
+	if current.AppliedAt != nil {
+		return domain.ScenarioChanges{}, domain.ErrConflict
+	}
+	version, err := nextVersion(scenario.Version, current.Version)
+	if err != nil {
+		return domain.ScenarioChanges{}, err
+	}
+
+	changes := current.Changes()
+	for _, allocation := range changes.Updated {
+		if !r.liveAllocationAtLocked(current, allocation.ID) {
+			return domain.ScenarioChanges{}, domain.ErrConflict
+		}
+	}
+	for _, id := range changes.DeletedIDs {
+		if !r.liveAllocationAtLocked(current, id) {
+			return domain.ScenarioChanges{}, domain.ErrConflict
+		}
+	}
+
+	now := time.Now().UTC()
+	applied := domain.ScenarioChanges{
+		Created:    make([]domain.Allocation, 0, len(changes.Created)),
+		Updated:    make([]domain.Allocation, 0, len(changes.Updated)),
+		DeletedIDs: changes.DeletedIDs,
+	}
+	for _, allocation := range changes.Updated {
+		allocation.OrganisationID = current.OrganisationID
+		allocation.Version = current.BaseVersions[allocation.ID]
+		updated, updateErr := r.updateAllocationLocked(ctx, allocation)
+		if updateErr != nil {
+			r.rollbackLocked()
+			return domain.ScenarioChanges{}, updateErr
+		}
+		applied.Updated = append(applied.Updated, updated)
+	}
+	for _, id := range changes.DeletedIDs {
+		deleted := r.state.Allocations[id]
+		delete(r.state.Allocations, id)
+		r.recordAllocationEventLocked(ctx, domain.AllocationEventDeleted, &deleted, nil)
+	}
+	for _, allocation := range changes.Created {
+		allocation.OrganisationID = current.OrganisationID
+		allocation.TargetType, allocation.TargetID = normalizedAllocationTarget(allocation)
+		if allocation.TargetType == domain.AllocationTargetPerson {
+			allocation.PersonID = allocation.TargetID
+		} else {
+			allocation.PersonID = ""
+		}
+		allocation.ID = r.nextIDLocked(allocationIDPrefix)
+		allocation.CreatedAt = now
+		allocation.UpdatedAt = now
+		allocation.Version = 1
+		r.state.Allocations[allocation.ID] = allocation
+		r.recordAllocationEventLocked(ctx, domain.AllocationEventCreated, nil, &allocation)
+		applied.Created = append(applied.Created, allocation)
+	}
+
+	current = copyScenario(current)
+	current.AppliedAt = &now
+	current.UpdatedAt = now
+	current.Version = version
+	r.state.Scenarios[current.ID] = current
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.ScenarioChanges{}, err
+	}
+
+	return applied, nil
+}
+
+// liveAllocationAtLocked reports whether a live allocation still has the
+// version the scenario cloned it at.
+func (r *FileRepository) liveAllocationAtLocked(scenario domain.Scenario, allocationID string) bool {
+	allocation, ok := r.state.Allocations[allocationID]
+	return ok && allocation.OrganisationID == scenario.OrganisationID && allocation.Version == scenario.BaseVersions[allocationID]
+}
+
+func (r *FileRepository) deleteScenariosByOrganisationLocked(organisationID string) {
+	for scenarioID, scenario := range r.state.Scenarios {
+		if scenario.OrganisationID == organisationID {
+			delete(r.state.Scenarios, scenarioID)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+const shardIndexFileName = "index.json"
+
+// shardIndex is the cross-tenant part of sharded state. It lists the
+// organisations and carries the shared id sequence. API keys live here so a
+// request can be resolved to its organisation before any shard is loaded.
+type shardIndex struct {
+	Organisations map[string]domain.Organisation `json:"organisations"`
+	APIKeys       map[string]domain.APIKey       `json:"api_keys,omitempty"`
+	Sequence      int64                          `json:"sequence"`
+}
+
+// NewShardedFileRepository returns a file-backed repository that keeps one
+// JSON file per organisation under dir plus an index of organisations.
+// A tenant's file is loaded on first use and only rewritten when that
+// tenant's records change.
+func NewShardedFileRepository(dir string) (*FileRepository, error) {
+	if dir == "" {
+		return nil, errors.New("shard directory is required")
+	}
+
+	repo := &FileRepository{
+		path:         filepath.Join(dir, shardIndexFileName),
+		shardDir:     dir,
+		loadedShards: map[string]bool{},
+		state:        emptyFileState(),
+	}
+	repo.persistedState = cloneFileState(repo.state)
+
+	if err := repo.load(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *FileRepository) shardPath(organisationID string) string {
+	return filepath.Join(r.shardDir, "org-"+filepath.Base(organisationID)+".json")
+}
+
+// ensureShardLoaded reads the organisation's shard into memory when the
+// repository is sharded and the shard has not been read yet. A store-backed
+// repository reads what other backends stored instead.
+func (r *FileRepository) ensureShardLoaded(ctx context.Context, organisationID string) error {
+	if r.store != nil {
+		return r.syncStore(ctx)
+	}
+	if r.shardDir == "" {
+		return nil
+	}
+
+	r.mu.RLock()
+	loaded := r.loadedShards[organisationID]
+	r.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.loadShardLocked(organisationID)
+}
+
+func (r *FileRepository) loadShardLocked(organisationID string) error {
+	if r.loadedShards[organisationID] {
+		return nil
+	}
+	if _, ok := r.state.Organisations[organisationID]; !ok {
+		return nil
+	}
+	defer r.observeLocked("load_shard", time.Now())
+
+	content, err := os.ReadFile(r.shardPath(organisationID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	shard := emptyFileState()
+	if len(content) > 0 {
+		if err = json.Unmarshal(content, &shard); err != nil {
+			return fmt.Errorf("decode shard for organisation %s: %w", organisationID, err)
+		}
+	}
+
+	mergeTenantState(&r.persistedState, cloneFileState(shard))
+	if r.debounceEnabled() {
+		mergeTenantState(&r.acceptedState, cloneFileState(shard))
+	}
+	mergeTenantState(&r.state, shard)
+	r.normalizeLegacyAllocationsLocked()
+	r.loadedShards[organisationID] = true
+	return nil
+}
+
+func mergeTenantState(target *fileState, shard fileState) {
+	for id, person := range shard.Persons {
+		target.Persons[id] = person
+	}
+	for id, project := range shard.Projects {
+		target.Projects[id] = project
+	}
+	for id, group := range shard.Groups {
+		target.Groups[id] = group
+	}
+	for id, allocation := range shard.Allocations {
+		target.Allocations[id] = allocation
+	}
+	for id, holiday := range shard.OrgHolidays {
+		target.OrgHolidays[id] = holiday
+	}
+	for id, entry := range shard.GroupUnavailability {
+		target.GroupUnavailability[id] = entry
+	}
+	for id, entry := range shard.PersonUnavailability {
+		target.PersonUnavailability[id] = entry
+	}
+	for id, rule := range shard.UnavailabilityRules {
+		target.UnavailabilityRules[id] = rule
+	}
+	for id, template := range shard.AllocationTemplates {
+		target.AllocationTemplates[id] = template
+	}
+	for id, scenario := range shard.Scenarios {
+		target.Scenarios[id] = scenario
+	}
+	for id, event := range shard.AllocationEvents {
+		target.AllocationEvents[id] = event
+	}
+	for id, webhook := range shard.Webhooks {
+		target.Webhooks[id] = webhook
+	}
+	for id, delivery := range shard.WebhookDeliveries {
+		target.WebhookDeliveries[id] = delivery
+	}
+}
+
+// tenantState returns the records of one organisation. Organisations and the
+// sequence stay in the index.
+func tenantState(state fileState, organisationID string) fileState {
+	tenant := emptyFileState()
+	for id, person := range state.Persons {
+		if person.OrganisationID == organisationID {
+			tenant.Persons[id] = person
+		}
+	}
+	for id, project := range state.Projects {
+		if project.OrganisationID == organisationID {
+			tenant.Projects[id] = project
+		}
+	}
+	for id, group := range state.Groups {
+		if group.OrganisationID == organisationID {
+			tenant.Groups[id] = group
+		}
+	}
+	for id, allocation := range state.Allocations {
+		if allocation.OrganisationID == organisationID {
+			tenant.Allocations[id] = allocation
+		}
+	}
+	for id, holiday := range state.OrgHolidays {
+		if holiday.OrganisationID == organisationID {
+			tenant.OrgHolidays[id] = holiday
+		}
+	}
+	for id, entry := range state.GroupUnavailability {
+		if entry.OrganisationID == organisationID {
+			tenant.GroupUnavailability[id] = entry
+		}
+	}
+	for id, entry := range state.PersonUnavailability {
+		if entry.OrganisationID == organisationID {
+			tenant.PersonUnavailability[id] = entry
+		}
+	}
+	for id, rule := range state.UnavailabilityRules {
+		if rule.OrganisationID == organisationID {
+			tenant.UnavailabilityRules[id] = rule
+		}
+	}
+	for id, template := range state.AllocationTemplates {
+		if template.OrganisationID == organisationID {
+			tenant.AllocationTemplates[id] = template
+		}
+	}
+	for id, scenario := range state.Scenarios {
+		if scenario.OrganisationID == organisationID {
+			tenant.Scenarios[id] = scenario
+		}
+	}
+	for id, event := range state.AllocationEvents {
+		if event.OrganisationID == organisationID {
+			tenant.AllocationEvents[id] = event
+		}
+	}
+	for id, webhook := range state.Webhooks {
+		if webhook.OrganisationID == organisationID {
+			tenant.Webhooks[id] = webhook
+		}
+	}
+	for id, delivery := range state.WebhookDeliveries {
+		if delivery.OrganisationID == organisationID {
+			tenant.WebhookDeliveries[id] = delivery
+		}
+	}
+	return tenant
+}
+
+// writeShardsLocked writes the index and every loaded shard whose content
+// changed since the last successful write. Shards of deleted organisations
+// are removed.
+func (r *FileRepository) writeShardsLocked() error {
+	if err := r.writeChangedShardsLocked(); err != nil {
+		return err
+	}
+	r.persistedState = cloneFileState(r.state)
+	r.diskWrites++
+	for organisationID := range r.loadedShards {
+		if _, ok := r.state.Organisations[organisationID]; !ok {
+			delete(r.loadedShards, organisationID)
+		}
+	}
+	return nil
+}
+
+func (r *FileRepository) writeChangedShardsLocked() error {
+	for organisationID := range r.loadedShards {
+		if _, ok := r.state.Organisations[organisationID]; !ok {
+			if err := os.Remove(r.shardPath(organisationID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		if err := writeIfChanged(
+			r.shardPath(organisationID),
+			tenantState(r.state, organisationID),
+			tenantState(r.persistedState, organisationID),
+		); err != nil {
+			return err
+		}
+	}
+
+	index := shardIndex{Organisations: r.state.Organisations, APIKeys: r.state.APIKeys, Sequence: r.state.Sequence}
+	previous := shardIndex{Organisations: r.persistedState.Organisations, APIKeys: r.persistedState.APIKeys, Sequence: r.persistedState.Sequence}
+	if _, err := os.Stat(r.path); errors.Is(err, os.ErrNotExist) {
+		previous = shardIndex{}
+	}
+	return writeIfChanged(r.path, index, previous)
+}
+
+func writeIfChanged(path string, current, previous any) error {
+	body, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+	previousBody, err := json.MarshalIndent(previous, "", "  ")
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(body, previousBody) {
+		return nil
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(body)
+		return err
+	})
+}
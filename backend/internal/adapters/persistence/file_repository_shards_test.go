@@ -0,0 +1,176 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+func createShardedTenant(ctx context.Context, t *testing.T, repo *FileRepository, name string) (domain.Organisation, domain.Person) {
+	t.Helper()
+	organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: name, HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	person, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: name + " Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	return organisation, person
+}
+
+// TestShardedFileRepositoryWritesOnlyMutatedTenant verifies the sharded file repository writes only mutated tenant scenario.
+func TestShardedFileRepositoryWritesOnlyMutatedTenant(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := NewShardedFileRepository(dir)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+
+	orgA, _ := createShardedTenant(ctx, t, repo, "Org A")
+	orgB, _ := createShardedTenant(ctx, t, repo, "Org B")
+
+	shardB := repo.shardPath(orgB.ID)
+	before, err := os.ReadFile(shardB)
+	if err != nil {
+		t.Fatalf("read org B shard: %v", err)
+	}
+	past := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if err = os.Chtimes(shardB, past, past); err != nil {
+		t.Fatalf("reset org B shard mtime: %v", err)
+	}
+
+	if _, err = repo.CreatePerson(ctx, domain.Person{OrganisationID: orgA.ID, Name: "Second A", EmploymentPct: 50}); err != nil {
+		t.Fatalf("create second person: %v", err)
+	}
+
+	info, err := os.Stat(shardB)
+	if err != nil {
+		t.Fatalf("stat org B shard: %v", err)
+	}
+	if !info.ModTime().Equal(past) {
+		t.Fatalf("expected org B shard to stay untouched, mtime changed to %s", info.ModTime())
+	}
+	after, err := os.ReadFile(shardB)
+	if err != nil || !bytes.Equal(before, after) {
+		t.Fatalf("expected org B shard content to be unchanged, err=%v", err)
+	}
+	shardA, err := os.ReadFile(repo.shardPath(orgA.ID))
+	if err != nil || !bytes.Contains(shardA, []byte("Second A")) {
+		t.Fatalf("expected org A shard to contain the new person, err=%v", err)
+	}
+	if bytes.Contains(shardA, []byte("Org B Person")) {
+		t.Fatal("expected org A shard to exclude org B records")
+	}
+}
+
+// TestShardedFileRepositoryLoadsTenantShardOnDemand verifies the sharded file repository loads tenant shard on demand scenario.
+func TestShardedFileRepositoryLoadsTenantShardOnDemand(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := NewShardedFileRepository(dir)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	orgA, personA := createShardedTenant(ctx, t, repo, "Org A")
+	orgB, personB := createShardedTenant(ctx, t, repo, "Org B")
+	if err = repo.Close(); err != nil {
+		t.Fatalf("close repository: %v", err)
+	}
+
+	reopened, err := NewShardedFileRepository(dir)
+	if err != nil {
+		t.Fatalf("reopen repository: %v", err)
+	}
+	organisations, err := reopened.ListOrganisations(ctx)
+	if err != nil || len(organisations) != 2 {
+		t.Fatalf("expected both organisations from the index, got %+v err=%v", organisations, err)
+	}
+	if len(reopened.loadedShards) != 0 {
+		t.Fatalf("expected no shards loaded before tenant access, got %v", reopened.loadedShards)
+	}
+
+	got, err := reopened.GetPerson(ctx, orgA.ID, personA.ID)
+	if err != nil || got.Name != personA.Name {
+		t.Fatalf("expected org A person from its shard, got %+v err=%v", got, err)
+	}
+	if !reopened.loadedShards[orgA.ID] || reopened.loadedShards[orgB.ID] {
+		t.Fatalf("expected only org A shard to be loaded, got %v", reopened.loadedShards)
+	}
+	if _, err = reopened.GetPerson(ctx, orgA.ID, personB.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected org B person to stay hidden from org A, got %v", err)
+	}
+
+	persons, err := reopened.ListPersons(ctx, orgB.ID)
+	if err != nil || len(persons) != 1 || persons[0].ID != personB.ID {
+		t.Fatalf("expected org B shard to load its own person, got %+v err=%v", persons, err)
+	}
+
+	if err = reopened.DeleteOrganisation(ctx, orgB.ID); err != nil {
+		t.Fatalf("delete organisation: %v", err)
+	}
+	if _, err = os.Stat(reopened.shardPath(orgB.ID)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected deleted organisation shard to be removed, got stat err: %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, shardIndexFileName)); err != nil {
+		t.Fatalf("expected shard index to exist: %v", err)
+	}
+	if _, err = NewShardedFileRepository(""); err == nil {
+		t.Fatal("expected empty shard directory to be rejected")
+	}
+}
+
+// TestShardedFileRepositoryKeepsAPIKeysInIndex verifies the sharded file repository API key index scenario.
+func TestShardedFileRepositoryKeepsAPIKeysInIndex(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := NewShardedFileRepository(dir)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	organisation, _ := createShardedTenant(ctx, t, repo, "Org Keys")
+	if _, err = repo.CreateAPIKey(ctx, domain.APIKey{OrganisationID: "missing", Hash: "unused"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for unknown organisation, got %v", err)
+	}
+	created, err := repo.CreateAPIKey(ctx, domain.APIKey{OrganisationID: organisation.ID, Name: "ci", Roles: []string{domain.RoleOrgUser}, Hash: "abc", Key: "plain"})
+	if err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+	if created.Key != "" {
+		t.Fatalf("expected the plain key not to be stored, got %+v", created)
+	}
+
+	reopened, err := NewShardedFileRepository(dir)
+	if err != nil {
+		t.Fatalf("reopen repository: %v", err)
+	}
+	found, err := reopened.GetAPIKeyByHash(ctx, "abc")
+	if err != nil || found.ID != created.ID {
+		t.Fatalf("expected the key to resolve from the index, got %+v err=%v", found, err)
+	}
+	if len(reopened.loadedShards) != 0 {
+		t.Fatalf("expected no shard to be loaded by a key lookup, got %v", reopened.loadedShards)
+	}
+
+	revoked, err := reopened.RevokeAPIKey(ctx, organisation.ID, created.ID)
+	if err != nil || revoked.RevokedAt == nil {
+		t.Fatalf("expected the key to be revoked, got %+v err=%v", revoked, err)
+	}
+	if _, err = reopened.RevokeAPIKey(ctx, "other", created.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected another organisation's key to be hidden, got %v", err)
+	}
+
+	if err = reopened.DeleteOrganisation(ctx, organisation.ID); err != nil {
+		t.Fatalf("delete organisation: %v", err)
+	}
+	if _, err = reopened.GetAPIKeyByHash(ctx, "abc"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected keys to go with their organisation, got %v", err)
+	}
+}
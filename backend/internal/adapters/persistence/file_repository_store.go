@@ -0,0 +1,353 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"plato/backend/internal/domain"
+)
+
+// stateStore keeps the repository state outside the local file system.
+// Every organisation carries its own revision, so backends sharing a store
+// only conflict when they write records of the same organisation.
+type stateStore interface {
+	// load reads every record, the id sequence, and the revision of each
+	// organisation.
+	load(ctx context.Context) (fileState, map[string]tenantRevision, error)
+	// revisions reads the revision of each organisation and the id sequence.
+	revisions(ctx context.Context) (map[string]tenantRevision, int64, error)
+	// loadTenants reads the records and revisions of the given organisations.
+	loadTenants(ctx context.Context, organisationIDs []string) (fileState, map[string]tenantRevision, error)
+	// save writes changes in one transaction. It fails with errStaleState
+	// and writes nothing when another writer saved records of a touched
+	// organisation since the known revision or already used a new id. It
+	// returns the revisions the writer has now seen of the organisations
+	// it touched.
+	save(ctx context.Context, changes storeChanges, known map[string]tenantRevision) (map[string]tenantRevision, error)
+	close() error
+}
+
+// tenantRevision tells which saves of one organisation a repository has
+// read. revision grows with every save that changes records other writes
+// depend on and is checked on write. logRevision grows with saves that only
+// touch the webhook delivery log, which never conflict with other writes.
+type tenantRevision struct {
+	revision    int64
+	logRevision int64
+}
+
+// storeChanges holds the records one save writes. Deleted records only
+// carry their organisation. sequence is the new id sequence, or zero when
+// the save issued no ids.
+type storeChanges struct {
+	inserts  map[recordKey]storedRecord
+	updates  map[recordKey]storedRecord
+	deletes  map[recordKey]storedRecord
+	sequence int64
+}
+
+// errStaleState reports a save against a revision another writer replaced.
+var errStaleState = fmt.Errorf("stored state changed since it was loaded: %w", domain.ErrConflict)
+
+// newStoreRepository returns a repository backed by store, loaded with the
+// state the store currently holds.
+func newStoreRepository(ctx context.Context, store stateStore) (*FileRepository, error) {
+	state, revisions, err := store.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load repository state: %w", err)
+	}
+
+	repo := &FileRepository{store: store, state: state, storeRevisions: revisions}
+	repo.ensureMapsLocked()
+	repo.normalizeLegacyAllocationsLocked()
+	repo.persistedState = cloneFileState(repo.state)
+	return repo, nil
+}
+
+// writeStoreLocked saves the records that changed since the last successful
+// save within ctx. When another writer saved first, the repository reads the
+// organisations that writer changed so a retry works on the newer data.
+func (r *FileRepository) writeStoreLocked(ctx context.Context) error {
+	changes, err := diffStateRecords(r.persistedState, r.state)
+	if err != nil {
+		return err
+	}
+	if changes.empty() {
+		r.persistedState = cloneFileState(r.state)
+		return nil
+	}
+
+	saved, err := r.store.save(ctx, changes, r.storeRevisions)
+	if err != nil {
+		if errors.Is(err, errStaleState) {
+			if syncErr := r.syncStoreLocked(ctx); syncErr != nil {
+				return errors.Join(err, syncErr)
+			}
+		}
+		return err
+	}
+
+	for organisationID, revision := range saved {
+		r.storeRevisions[organisationID] = revision
+	}
+	r.persistedState = cloneFileState(r.state)
+	r.diskWrites++
+	return nil
+}
+
+// syncStore reads the organisations other backends saved since this
+// repository last read or wrote them, so reads see their changes. It does
+// nothing for repositories without a state store.
+func (r *FileRepository) syncStore(ctx context.Context) error {
+	if r.store == nil {
+		return nil
+	}
+	revisions, sequence, err := r.store.revisions(ctx)
+	if err != nil {
+		return fmt.Errorf("read stored revisions: %w", err)
+	}
+
+	r.mu.RLock()
+	current := len(r.staleTenantsLocked(revisions)) == 0 && sequence <= r.persistedState.Sequence
+	r.mu.RUnlock()
+	if current {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.applyStoreRevisionsLocked(ctx, revisions, sequence)
+}
+
+func (r *FileRepository) syncStoreLocked(ctx context.Context) error {
+	revisions, sequence, err := r.store.revisions(ctx)
+	if err != nil {
+		return fmt.Errorf("read stored revisions: %w", err)
+	}
+	return r.applyStoreRevisionsLocked(ctx, revisions, sequence)
+}
+
+// applyStoreRevisionsLocked reloads the organisations whose stored revision
+// differs from the one last seen and moves the id sequence forward.
+func (r *FileRepository) applyStoreRevisionsLocked(ctx context.Context, revisions map[string]tenantRevision, sequence int64) error {
+	stale := r.staleTenantsLocked(revisions)
+	if len(stale) == 0 && sequence <= r.persistedState.Sequence {
+		return nil
+	}
+
+	if len(stale) > 0 {
+		tenants, loaded, err := r.store.loadTenants(ctx, stale)
+		if err != nil {
+			return fmt.Errorf("reload organisations: %w", err)
+		}
+		for _, organisationID := range stale {
+			removeTenantRecords(&r.state, organisationID)
+			removeTenantRecords(&r.persistedState, organisationID)
+			r.storeRevisions[organisationID] = loaded[organisationID]
+		}
+		mergeStoredTenants(&r.state, cloneFileState(tenants))
+		mergeStoredTenants(&r.persistedState, tenants)
+	}
+	r.persistedState.Sequence = max(r.persistedState.Sequence, sequence)
+	r.state.Sequence = max(r.state.Sequence, sequence)
+	r.normalizeLegacyAllocationsLocked()
+	r.version++
+	return nil
+}
+
+func (r *FileRepository) staleTenantsLocked(revisions map[string]tenantRevision) []string {
+	stale := make([]string, 0)
+	for organisationID, revision := range revisions {
+		if r.storeRevisions[organisationID] != revision {
+			stale = append(stale, organisationID)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// mergeStoredTenants adds the organisations, API keys, and records of
+// tenants to target.
+func mergeStoredTenants(target *fileState, tenants fileState) {
+	for id, organisation := range tenants.Organisations {
+		target.Organisations[id] = organisation
+	}
+	for id, key := range tenants.APIKeys {
+		target.APIKeys[id] = key
+	}
+	mergeTenantState(target, tenants)
+}
+
+// removeTenantRecords drops an organisation and every record it owns.
+func removeTenantRecords(state *fileState, organisationID string) {
+	delete(state.Organisations, organisationID)
+	deleteEntriesWhere(state.Persons, func(person domain.Person) bool { return person.OrganisationID == organisationID })
+	deleteEntriesWhere(state.Projects, func(project domain.Project) bool { return project.OrganisationID == organisationID })
+	deleteEntriesWhere(state.Groups, func(group domain.Group) bool { return group.OrganisationID == organisationID })
+	deleteEntriesWhere(state.Allocations, func(allocation domain.Allocation) bool {
+		return allocation.OrganisationID == organisationID
+	})
+	deleteEntriesWhere(state.OrgHolidays, func(holiday domain.OrgHoliday) bool { return holiday.OrganisationID == organisationID })
+	deleteEntriesWhere(state.GroupUnavailability, func(entry domain.GroupUnavailability) bool {
+		return entry.OrganisationID == organisationID
+	})
+	deleteEntriesWhere(state.PersonUnavailability, func(entry domain.PersonUnavailability) bool {
+		return entry.OrganisationID == organisationID
+	})
+	deleteEntriesWhere(state.UnavailabilityRules, func(rule domain.UnavailabilityRule) bool {
+		return rule.OrganisationID == organisationID
+	})
+	deleteEntriesWhere(state.AllocationTemplates, func(template domain.AllocationTemplate) bool {
+		return template.OrganisationID == organisationID
+	})
+	deleteEntriesWhere(state.Scenarios, func(scenario domain.Scenario) bool { return scenario.OrganisationID == organisationID })
+	deleteEntriesWhere(state.AllocationEvents, func(event domain.AllocationEvent) bool {
+		return event.OrganisationID == organisationID
+	})
+	deleteEntriesWhere(state.Webhooks, func(webhook domain.Webhook) bool { return webhook.OrganisationID == organisationID })
+	deleteEntriesWhere(state.WebhookDeliveries, func(delivery domain.WebhookDelivery) bool {
+		return delivery.OrganisationID == organisationID
+	})
+	deleteEntriesWhere(state.APIKeys, func(key domain.APIKey) bool { return key.OrganisationID == organisationID })
+}
+
+// nextTenantRevision returns the revision a save moves an organisation to.
+// A save that only touches the webhook delivery log is never stale. Any
+// other save fails with errStaleState when the stored revision moved on
+// since known.
+func nextTenantRevision(stored, known tenantRevision, logOnly bool) (tenantRevision, error) {
+	if logOnly {
+		stored.logRevision++
+		return stored, nil
+	}
+	if stored.revision != known.revision {
+		return tenantRevision{}, errStaleState
+	}
+	stored.revision++
+	return stored, nil
+}
+
+// seenRevision returns the revision a writer has read after saving next over
+// stored. A counter another writer moved since known keeps the known value,
+// so the next sync reloads the organisation.
+func seenRevision(known, stored, next tenantRevision) tenantRevision {
+	seen := known
+	if known.revision == stored.revision {
+		seen.revision = next.revision
+	}
+	if known.logRevision == stored.logRevision {
+		seen.logRevision = next.logRevision
+	}
+	return seen
+}
+
+func (c storeChanges) empty() bool {
+	return len(c.inserts) == 0 && len(c.updates) == 0 && len(c.deletes) == 0 && c.sequence == 0
+}
+
+// tenants returns the organisations the changes touch, sorted so concurrent
+// saves lock them in the same order, and reports which of them only have
+// changes to the webhook delivery log.
+func (c storeChanges) tenants() ([]string, map[string]bool) {
+	logOnly := map[string]bool{}
+	for _, records := range []map[recordKey]storedRecord{c.inserts, c.updates, c.deletes} {
+		for key, record := range records {
+			onlyLog, seen := logOnly[record.organisationID]
+			logOnly[record.organisationID] = key.kind == recordKindWebhookDelivery && (onlyLog || !seen)
+		}
+	}
+	organisationIDs := make([]string, 0, len(logOnly))
+	for organisationID := range logOnly {
+		organisationIDs = append(organisationIDs, organisationID)
+	}
+	sort.Strings(organisationIDs)
+	return organisationIDs, logOnly
+}
+
+// diffStateRecords lists the records that are new, changed, or gone in
+// current. Only new and changed records are encoded.
+func diffStateRecords(previous, current fileState) (storeChanges, error) {
+	changes := storeChanges{
+		inserts: map[recordKey]storedRecord{},
+		updates: map[recordKey]storedRecord{},
+		deletes: map[recordKey]storedRecord{},
+	}
+	if current.Sequence != previous.Sequence {
+		changes.sequence = current.Sequence
+	}
+
+	err := errors.Join(
+		diffRecords(&changes, recordKindOrganisation, previous.Organisations, current.Organisations, copyOrganisation,
+			func(id string, _ domain.Organisation) string { return id }),
+		diffRecords(&changes, recordKindPerson, previous.Persons, current.Persons, copyPerson,
+			func(_ string, person domain.Person) string { return person.OrganisationID }),
+		diffRecords(&changes, recordKindProject, previous.Projects, current.Projects, copyProject,
+			func(_ string, project domain.Project) string { return project.OrganisationID }),
+		diffRecords(&changes, recordKindGroup, previous.Groups, current.Groups, copyGroup,
+			func(_ string, group domain.Group) string { return group.OrganisationID }),
+		diffRecords(&changes, recordKindAllocation, previous.Allocations, current.Allocations, keepValue[domain.Allocation],
+			func(_ string, allocation domain.Allocation) string { return allocation.OrganisationID }),
+		diffRecords(&changes, recordKindOrgHoliday, previous.OrgHolidays, current.OrgHolidays, copyOrgHoliday,
+			func(_ string, holiday domain.OrgHoliday) string { return holiday.OrganisationID }),
+		diffRecords(&changes, recordKindGroupUnavailability, previous.GroupUnavailability, current.GroupUnavailability, keepValue[domain.GroupUnavailability],
+			func(_ string, entry domain.GroupUnavailability) string { return entry.OrganisationID }),
+		diffRecords(&changes, recordKindPersonUnavailability, previous.PersonUnavailability, current.PersonUnavailability, keepValue[domain.PersonUnavailability],
+			func(_ string, entry domain.PersonUnavailability) string { return entry.OrganisationID }),
+		diffRecords(&changes, recordKindUnavailabilityRule, previous.UnavailabilityRules, current.UnavailabilityRules, copyUnavailabilityRule,
+			func(_ string, rule domain.UnavailabilityRule) string { return rule.OrganisationID }),
+		diffRecords(&changes, recordKindAllocationTemplate, previous.AllocationTemplates, current.AllocationTemplates, copyAllocationTemplate,
+			func(_ string, template domain.AllocationTemplate) string { return template.OrganisationID }),
+		diffRecords(&changes, recordKindScenario, previous.Scenarios, current.Scenarios, copyScenario,
+			func(_ string, scenario domain.Scenario) string { return scenario.OrganisationID }),
+		diffRecords(&changes, recordKindAllocationEvent, previous.AllocationEvents, current.AllocationEvents, keepValue[domain.AllocationEvent],
+			func(_ string, event domain.AllocationEvent) string { return event.OrganisationID }),
+		diffRecords(&changes, recordKindWebhook, previous.Webhooks, current.Webhooks, copyWebhook,
+			func(_ string, webhook domain.Webhook) string { return webhook.OrganisationID }),
+		diffRecords(&changes, recordKindWebhookDelivery, previous.WebhookDeliveries, current.WebhookDeliveries, keepValue[domain.WebhookDelivery],
+			func(_ string, delivery domain.WebhookDelivery) string { return delivery.OrganisationID }),
+		diffRecords(&changes, recordKindAPIKey, previous.APIKeys, current.APIKeys, copyAPIKey,
+			func(_ string, key domain.APIKey) string { return key.OrganisationID }),
+	)
+	if err != nil {
+		return storeChanges{}, err
+	}
+	return changes, nil
+}
+
+// diffRecords adds the changes between the records of one kind. Both sides
+// go through copyRecord before they are compared, because copies turn nil
+// slices into empty ones and would otherwise count as changed.
+func diffRecords[T any](
+	changes *storeChanges,
+	kind string,
+	previous, current map[string]T,
+	copyRecord func(T) T,
+	organisationOf func(id string, value T) string,
+) error {
+	for id, value := range current {
+		old, existed := previous[id]
+		if existed && reflect.DeepEqual(copyRecord(old), copyRecord(value)) {
+			continue
+		}
+		body, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("encode %s %s: %w", kind, id, err)
+		}
+		record := storedRecord{organisationID: organisationOf(id, value), body: body}
+		if existed {
+			changes.updates[recordKey{kind: kind, id: id}] = record
+		} else {
+			changes.inserts[recordKey{kind: kind, id: id}] = record
+		}
+	}
+	for id, value := range previous {
+		if _, ok := current[id]; !ok {
+			changes.deletes[recordKey{kind: kind, id: id}] = storedRecord{organisationID: organisationOf(id, value)}
+		}
+	}
+	return nil
+}
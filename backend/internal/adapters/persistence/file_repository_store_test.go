@@ -0,0 +1,476 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"plato/backend/internal/domain"
+)
+
+// repositoryOpener opens a repository whose data lives at path. Opening the
+// same path again must return the data saved before.
+type repositoryOpener func(path string) (*FileRepository, error)
+
+// forEachRepositoryBackend runs the shared repository tests against every
+// storage backend that works without an external server.
+func forEachRepositoryBackend(t *testing.T, test func(t *testing.T, open repositoryOpener)) {
+	t.Helper()
+	t.Run("file", func(t *testing.T) {
+		test(t, NewFileRepository)
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		test(t, func(path string) (*FileRepository, error) {
+			repo, err := NewSQLiteRepository(context.Background(), strings.TrimSuffix(path, filepath.Ext(path))+".db")
+			if err == nil {
+				t.Cleanup(func() { _ = repo.Close() })
+			}
+			return repo, err
+		})
+	})
+}
+
+// memoryStore is a stateStore that keeps records in memory, standing in for
+// a database several backends write to. beforeSave runs once before the next
+// save takes the store lock, so a test can slip in another backend's write.
+type memoryStore struct {
+	mu         sync.Mutex
+	records    map[recordKey]storedRecord
+	tenants    map[string]tenantRevision
+	sequence   int64
+	saves      int
+	closed     bool
+	beforeSave func()
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: map[recordKey]storedRecord{}, tenants: map[string]tenantRevision{}}
+}
+
+func (s *memoryStore) load(_ context.Context) (fileState, map[string]tenantRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, err := s.decodeLocked(func(string) bool { return true })
+	state.Sequence = s.sequence
+	return state, maps.Clone(s.tenants), err
+}
+
+func (s *memoryStore) revisions(_ context.Context) (map[string]tenantRevision, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.tenants), s.sequence, nil
+}
+
+func (s *memoryStore) loadTenants(_ context.Context, organisationIDs []string) (fileState, map[string]tenantRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revisions := map[string]tenantRevision{}
+	for _, organisationID := range organisationIDs {
+		revisions[organisationID] = s.tenants[organisationID]
+	}
+	state, err := s.decodeLocked(func(organisationID string) bool { return slices.Contains(organisationIDs, organisationID) })
+	return state, revisions, err
+}
+
+func (s *memoryStore) decodeLocked(include func(organisationID string) bool) (fileState, error) {
+	state := emptyFileState()
+	for key, record := range s.records {
+		if !include(record.organisationID) {
+			continue
+		}
+		if err := decodeRecord(&state, key.kind, key.id, record.body); err != nil {
+			return fileState{}, err
+		}
+	}
+	return state, nil
+}
+
+func (s *memoryStore) save(ctx context.Context, changes storeChanges, known map[string]tenantRevision) (map[string]tenantRevision, error) {
+	if hook := s.beforeSave; hook != nil {
+		s.beforeSave = nil
+		hook()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	organisationIDs, logOnly := changes.tenants()
+	next := map[string]tenantRevision{}
+	saved := map[string]tenantRevision{}
+	for _, organisationID := range organisationIDs {
+		stored := s.tenants[organisationID]
+		revision, err := nextTenantRevision(stored, known[organisationID], logOnly[organisationID])
+		if err != nil {
+			return nil, err
+		}
+		next[organisationID] = revision
+		saved[organisationID] = seenRevision(known[organisationID], stored, revision)
+	}
+	for key := range changes.inserts {
+		if _, ok := s.records[key]; ok {
+			return nil, errStaleState
+		}
+	}
+
+	maps.Copy(s.tenants, next)
+	for key := range changes.deletes {
+		delete(s.records, key)
+	}
+	maps.Copy(s.records, changes.updates)
+	maps.Copy(s.records, changes.inserts)
+	s.sequence = max(s.sequence, changes.sequence)
+	s.saves++
+	return saved, nil
+}
+
+func (s *memoryStore) close() error {
+	s.closed = true
+	return nil
+}
+
+func testOrganisation(name string) domain.Organisation {
+	return domain.Organisation{Name: name, HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}
+}
+
+// TestStoreRepositoryReadsOtherBackendsWrites verifies the store repository read through scenario.
+func TestStoreRepositoryReadsOtherBackendsWrites(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	first, err := newStoreRepository(ctx, store)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	second, err := newStoreRepository(ctx, store)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+
+	saved, err := first.CreateOrganisation(ctx, testOrganisation("First"))
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	if _, err = second.GetOrganisation(ctx, saved.ID); err != nil {
+		t.Fatalf("expected the second backend to read the first backend's organisation, got %v", err)
+	}
+	other, err := second.CreateOrganisation(ctx, testOrganisation("Second"))
+	if err != nil {
+		t.Fatalf("expected a write after the read to succeed, got %v", err)
+	}
+	if other.ID == saved.ID {
+		t.Fatalf("expected a fresh id, got %s twice", other.ID)
+	}
+
+	if _, err = first.CreatePerson(ctx, domain.Person{OrganisationID: saved.ID, Name: "Alice", EmploymentPct: 100}); err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	persons, err := second.ListPersons(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("list persons: %v", err)
+	}
+	if len(persons) != 1 || persons[0].Name != "Alice" {
+		t.Fatalf("expected the second backend to list the new person, got %+v", persons)
+	}
+	if store.saves != 3 {
+		t.Fatalf("expected three saves, got %d", store.saves)
+	}
+}
+
+// TestStoreRepositoryVersionsEachOrganisation verifies the store repository per organisation conflict scenario.
+func TestStoreRepositoryVersionsEachOrganisation(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	first, err := newStoreRepository(ctx, store)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	second, err := newStoreRepository(ctx, store)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+
+	orgA, err := first.CreateOrganisation(ctx, testOrganisation("A"))
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	orgB, err := first.CreateOrganisation(ctx, testOrganisation("B"))
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	createPerson := func(organisationID, name string) domain.Person {
+		t.Helper()
+		person, createErr := first.CreatePerson(ctx, domain.Person{OrganisationID: organisationID, Name: name, EmploymentPct: 100})
+		if createErr != nil {
+			t.Fatalf("create person: %v", createErr)
+		}
+		return person
+	}
+	alice := createPerson(orgA.ID, "Alice")
+	bob := createPerson(orgB.ID, "Bob")
+	webhook, err := first.CreateWebhook(ctx, domain.Webhook{OrganisationID: orgA.ID, URL: "https://example.com/hook", Events: []string{"*"}})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	renamed := func(repo *FileRepository, person domain.Person, name string) (domain.Person, error) {
+		current, getErr := repo.GetPerson(ctx, person.OrganisationID, person.ID)
+		if getErr != nil {
+			return domain.Person{}, getErr
+		}
+		current.Name = name
+		return repo.UpdatePerson(ctx, current)
+	}
+
+	store.beforeSave = func() {
+		if _, hookErr := renamed(first, bob, "Robert"); hookErr != nil {
+			t.Errorf("rename bob: %v", hookErr)
+		}
+	}
+	if _, err = renamed(second, alice, "Alicia"); err != nil {
+		t.Fatalf("expected a write to another organisation not to conflict, got %v", err)
+	}
+
+	store.beforeSave = func() {
+		delivery := domain.WebhookDelivery{OrganisationID: orgA.ID, WebhookID: webhook.ID, Event: "person.updated", Attempt: 1}
+		if _, hookErr := first.AppendWebhookDelivery(ctx, delivery); hookErr != nil {
+			t.Errorf("append delivery: %v", hookErr)
+		}
+	}
+	if _, err = renamed(second, alice, "Ali"); err != nil {
+		t.Fatalf("expected a delivery log write not to conflict, got %v", err)
+	}
+	deliveries, err := second.ListWebhookDeliveries(ctx, orgA.ID, webhook.ID)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("expected the second backend to read the delivery, got %+v, %v", deliveries, err)
+	}
+
+	store.beforeSave = func() {
+		if _, hookErr := renamed(first, alice, "Alice"); hookErr != nil {
+			t.Errorf("rename alice: %v", hookErr)
+		}
+	}
+	if _, err = renamed(second, bob, "Bobby"); err != nil {
+		t.Fatalf("expected a write to another organisation not to conflict, got %v", err)
+	}
+	store.beforeSave = func() {
+		if _, hookErr := renamed(first, alice, "Alice B"); hookErr != nil {
+			t.Errorf("rename alice: %v", hookErr)
+		}
+	}
+	if _, err = second.CreatePerson(ctx, domain.Person{OrganisationID: orgA.ID, Name: "Carol", EmploymentPct: 100}); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a stale write to the same organisation to fail with ErrConflict, got %v", err)
+	}
+	persons, err := second.ListPersons(ctx, orgA.ID)
+	if err != nil {
+		t.Fatalf("list persons: %v", err)
+	}
+	if len(persons) != 1 || persons[0].Name != "Alice B" {
+		t.Fatalf("expected the rejected backend to reload the organisation, got %+v", persons)
+	}
+	if _, err = second.CreatePerson(ctx, domain.Person{OrganisationID: orgA.ID, Name: "Carol", EmploymentPct: 100}); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+}
+
+// TestStoreRepositoryCloseClosesStore verifies the store repository close closes store scenario.
+func TestStoreRepositoryCloseClosesStore(t *testing.T) {
+	store := newMemoryStore()
+	repo, err := newStoreRepository(context.Background(), store)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	if err = repo.Close(); err != nil {
+		t.Fatalf("close repository: %v", err)
+	}
+	if !store.closed {
+		t.Fatal("expected Close to close the state store")
+	}
+}
+
+// TestDiffStateRecords verifies the diff state records scenario.
+func TestDiffStateRecords(t *testing.T) {
+	previous := emptyFileState()
+	previous.Organisations["org_1"] = testOrganisation("Org")
+	previous.Persons["person_1"] = domain.Person{ID: "person_1", OrganisationID: "org_1", Name: "Kept", EmploymentPct: 100}
+	previous.Persons["person_2"] = domain.Person{ID: "person_2", OrganisationID: "org_1", Name: "Removed", EmploymentPct: 100}
+	previous.Projects["project_1"] = domain.Project{ID: "project_1", OrganisationID: "org_1", Name: "Before"}
+	previous.Sequence = 5
+
+	current := cloneFileState(previous)
+	delete(current.Persons, "person_2")
+	current.Projects["project_1"] = domain.Project{ID: "project_1", OrganisationID: "org_1", Name: "After"}
+	current.Groups["group_6"] = domain.Group{ID: "group_6", OrganisationID: "org_1", Name: "New"}
+	current.Sequence = 6
+
+	changes, err := diffStateRecords(previous, current)
+	if err != nil {
+		t.Fatalf("diff records: %v", err)
+	}
+	if len(changes.updates) != 1 || len(changes.inserts) != 1 || changes.sequence != 6 {
+		t.Fatalf("expected the changed project, the new group, and the new sequence, got %+v", changes)
+	}
+	if _, ok := changes.updates[recordKey{kind: recordKindProject, id: "project_1"}]; !ok {
+		t.Fatal("expected the renamed project to be stored")
+	}
+	if record, ok := changes.inserts[recordKey{kind: recordKindGroup, id: "group_6"}]; !ok || record.organisationID != "org_1" {
+		t.Fatalf("expected the new group under org_1, got %+v", record)
+	}
+	deleted, ok := changes.deletes[recordKey{kind: recordKindPerson, id: "person_2"}]
+	if len(changes.deletes) != 1 || !ok || deleted.organisationID != "org_1" {
+		t.Fatalf("expected only person_2 of org_1 to be deleted, got %+v", changes.deletes)
+	}
+	if organisationIDs, logOnly := changes.tenants(); len(organisationIDs) != 1 || logOnly["org_1"] {
+		t.Fatalf("expected one checked organisation, got %v %v", organisationIDs, logOnly)
+	}
+
+	state := emptyFileState()
+	for _, records := range []map[recordKey]storedRecord{changes.updates, changes.inserts} {
+		for key, record := range records {
+			if err = decodeRecord(&state, key.kind, key.id, record.body); err != nil {
+				t.Fatalf("decode %s: %v", key.kind, err)
+			}
+		}
+	}
+	if state.Projects["project_1"].Name != "After" || state.Groups["group_6"].Name != "New" {
+		t.Fatalf("expected stored records to decode back, got %+v", state)
+	}
+	if err = decodeRecord(&state, "unknown", "x_1", []byte("{}")); err == nil {
+		t.Fatal("expected an unknown record kind to be rejected")
+	}
+
+	logged := cloneFileState(current)
+	logged.WebhookDeliveries["webhook_delivery_7"] = domain.WebhookDelivery{ID: "webhook_delivery_7", OrganisationID: "org_1"}
+	logged.Sequence = 7
+	if changes, err = diffStateRecords(current, logged); err != nil {
+		t.Fatalf("diff records: %v", err)
+	}
+	if _, logOnly := changes.tenants(); !logOnly["org_1"] {
+		t.Fatal("expected a delivery log write to skip the revision check")
+	}
+	if changes, err = diffStateRecords(current, cloneFileState(current)); err != nil || !changes.empty() {
+		t.Fatalf("expected no changes between equal states, got %+v, %v", changes, err)
+	}
+}
+
+// TestPostgresRepositorySharesStateAcrossBackends verifies the postgres repository shared state scenario.
+// It needs a disposable database named by PLATO_TEST_DATABASE_URL.
+func TestPostgresRepositorySharesStateAcrossBackends(t *testing.T) {
+	databaseURL := os.Getenv("PLATO_TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("PLATO_TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	first, err := NewPostgresRepository(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	defer func() { _ = first.Close() }()
+	second, err := NewPostgresRepository(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	defer func() { _ = second.Close() }()
+
+	saved, err := first.CreateOrganisation(ctx, testOrganisation("Postgres "+t.Name()))
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	if _, err = second.GetOrganisation(ctx, saved.ID); err != nil {
+		t.Fatalf("expected the second repository to read the first save, got %v", err)
+	}
+	if _, err = second.CreatePerson(ctx, domain.Person{OrganisationID: saved.ID, Name: "Alice", EmploymentPct: 100}); err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	if persons, listErr := first.ListPersons(ctx, saved.ID); listErr != nil || len(persons) != 1 {
+		t.Fatalf("expected the first repository to read the person, got %+v, %v", persons, listErr)
+	}
+	if err = second.DeleteOrganisation(ctx, saved.ID); err != nil {
+		t.Fatalf("delete organisation: %v", err)
+	}
+
+	reopened, err := NewPostgresRepository(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	defer func() { _ = reopened.Close() }()
+	if _, err = reopened.GetOrganisation(ctx, saved.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected the deletion to be stored, got %v", err)
+	}
+}
+
+// TestSQLiteRepositoryUsesWALAndSharesState verifies the sqlite repository uses WAL and shared state scenario.
+func TestSQLiteRepositoryUsesWALAndSharesState(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", "plato.db")
+	first, err := NewSQLiteRepository(ctx, path)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	defer func() { _ = first.Close() }()
+	second, err := NewSQLiteRepository(ctx, path)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	defer func() { _ = second.Close() }()
+
+	store, ok := first.store.(*sqlStore)
+	if !ok {
+		t.Fatalf("expected a SQL store, got %T", first.store)
+	}
+	var journalMode string
+	if err = store.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode); err != nil {
+		t.Fatalf("read journal mode: %v", err)
+	}
+	if journalMode != "wal" {
+		t.Fatalf("expected WAL journal mode, got %q", journalMode)
+	}
+
+	saved, err := first.CreateOrganisation(ctx, testOrganisation("First"))
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	if _, err = second.GetOrganisation(ctx, saved.ID); err != nil {
+		t.Fatalf("expected the second repository to read the first save, got %v", err)
+	}
+	other, err := second.CreateOrganisation(ctx, testOrganisation("Second"))
+	if err != nil || other.ID == saved.ID {
+		t.Fatalf("expected the second repository to store a new organisation, got %+v, %v", other, err)
+	}
+
+	if _, err = NewSQLiteRepository(ctx, ""); err == nil {
+		t.Fatal("expected an empty path to be rejected")
+	}
+}
+
+// TestStoreRepositorySavesWithCallerContext verifies the store repository save context scenario.
+func TestStoreRepositorySavesWithCallerContext(t *testing.T) {
+	store := newMemoryStore()
+	repo, err := newStoreRepository(context.Background(), store)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	organisation, err := repo.CreateOrganisation(context.Background(), testOrganisation("Context Org"))
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store.beforeSave = cancel
+	_, err = repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Cancelled", EmploymentPct: 100})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled caller context to stop the save, got %v", err)
+	}
+	persons, err := repo.ListPersons(context.Background(), organisation.ID)
+	if err != nil {
+		t.Fatalf("list persons: %v", err)
+	}
+	if len(persons) != 0 || store.saves != 1 {
+		t.Fatalf("expected nothing stored after the cancelled save, got %d persons and %d saves", len(persons), store.saves)
+	}
+}
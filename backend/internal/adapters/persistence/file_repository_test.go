@@ -3,12 +3,19 @@ package persistence
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
 )
 
 const (
@@ -21,15 +28,19 @@ const (
 
 // TestFileRepositoryCRUDAndCascade verifies the file repository CRUD and cascade scenario.
 func TestFileRepositoryCRUDAndCascade(t *testing.T) {
-	state := setupRepositoryCascadeState(t)
-	createRepositoryCascadeFixtures(t, state)
-	createRepositoryCascadeAllocationsAndCalendar(t, state)
-	executeRepositoryCascadeDeletions(t, state)
-	verifyRepositoryCascadePersistence(t, state)
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		state := setupRepositoryCascadeState(t, open)
+		createRepositoryCascadeFixtures(t, state)
+		createRepositoryCascadeAllocationsAndCalendar(t, state)
+		executeRepositoryCascadeDeletions(t, state)
+		verifyRepositoryCascadePersistence(t, state)
+	})
 }
 
 type repositoryCascadeState struct {
 	repo                       *FileRepository
+	open                       repositoryOpener
+	path                       string
 	orgA                       domain.Organisation
 	orgB                       domain.Organisation
 	personA1                   domain.Person
@@ -47,13 +58,14 @@ type repositoryCascadeState struct {
 	personUnavailabilityScoped domain.PersonUnavailability
 }
 
-func setupRepositoryCascadeState(t *testing.T) *repositoryCascadeState {
+func setupRepositoryCascadeState(t *testing.T, open repositoryOpener) *repositoryCascadeState {
 	t.Helper()
-	repo, err := NewFileRepository(filepath.Join(t.TempDir(), testRepoFileName))
+	path := filepath.Join(t.TempDir(), testRepoFileName)
+	repo, err := open(path)
 	if err != nil {
 		t.Fatalf("new repo: %v", err)
 	}
-	return &repositoryCascadeState{repo: repo}
+	return &repositoryCascadeState{repo: repo, open: open, path: path}
 }
 
 func createRepositoryCascadeFixtures(t *testing.T, state *repositoryCascadeState) {
@@ -377,7 +389,7 @@ func deleteRepositoryCascadeCalendarAndPerson(ctx context.Context, t *testing.T,
 	if err := state.repo.DeleteOrgHoliday(ctx, state.orgA.ID, state.holiday.ID); err != nil {
 		t.Fatalf("delete holiday: %v", err)
 	}
-	if err := state.repo.DeletePerson(ctx, state.orgA.ID, state.personA1.ID); err != nil {
+	if err := state.repo.DeletePerson(ctx, state.orgA.ID, state.personA1.ID, true); err != nil {
 		t.Fatalf("delete person A1: %v", err)
 	}
 }
@@ -404,13 +416,13 @@ func deleteRepositoryCascadeRemainingResources(ctx context.Context, t *testing.T
 	if _, err = state.repo.CreateAllocation(ctx, domain.Allocation{OrganisationID: state.orgA.ID, PersonID: state.personA2.ID, ProjectID: projectWithAllocation.ID, Percent: 20}); err != nil {
 		t.Fatalf("create project allocation: %v", err)
 	}
-	if err = state.repo.DeleteProject(ctx, state.orgA.ID, projectWithAllocation.ID); err != nil {
+	if err = state.repo.DeleteProject(ctx, state.orgA.ID, projectWithAllocation.ID, true); err != nil {
 		t.Fatalf("delete project with allocations: %v", err)
 	}
-	if err = state.repo.DeleteProject(ctx, state.orgA.ID, state.projectA2.ID); err != nil {
+	if err = state.repo.DeleteProject(ctx, state.orgA.ID, state.projectA2.ID, true); err != nil {
 		t.Fatalf("delete project A2: %v", err)
 	}
-	if err = state.repo.DeleteGroup(ctx, state.orgA.ID, state.groupA.ID); err != nil {
+	if err = state.repo.DeleteGroup(ctx, state.orgA.ID, state.groupA.ID, true); err != nil {
 		t.Fatalf("delete group: %v", err)
 	}
 	if _, err = state.repo.GetAllocation(ctx, state.orgA.ID, state.groupAllocation.ID); !errors.Is(err, domain.ErrNotFound) {
@@ -441,7 +453,7 @@ func verifyRepositoryCascadePersistence(t *testing.T, state *repositoryCascadeSt
 		t.Fatalf("unexpected org B id: %s", orgBFromRepo.ID)
 	}
 
-	reloaded, err := NewFileRepository(state.repo.path)
+	reloaded, err := state.open(state.path)
 	if err != nil {
 		t.Fatalf("reload repository: %v", err)
 	}
@@ -456,52 +468,188 @@ func verifyRepositoryCascadePersistence(t *testing.T, state *repositoryCascadeSt
 
 // TestFileRepositoryNotFoundCases verifies the file repository not found cases scenario.
 func TestFileRepositoryNotFoundCases(t *testing.T) {
-	ctx := context.Background()
-	repo, err := NewFileRepository(filepath.Join(t.TempDir(), testRepoFileName))
-	if err != nil {
-		t.Fatalf("new repo: %v", err)
-	}
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		repo, err := open(filepath.Join(t.TempDir(), testRepoFileName))
+		if err != nil {
+			t.Fatalf("new repo: %v", err)
+		}
 
-	_, err = repo.GetOrganisation(ctx, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for organisation, got %v", err)
-	}
-	err = repo.DeleteOrganisation(ctx, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for delete organisation, got %v", err)
-	}
-	_, err = repo.GetProject(ctx, testNonexistentOrgID, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for project, got %v", err)
-	}
-	err = repo.DeletePerson(ctx, testNonexistentOrgID, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for person delete, got %v", err)
-	}
-	err = repo.DeleteGroup(ctx, testNonexistentOrgID, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for group delete, got %v", err)
-	}
-	err = repo.DeleteAllocation(ctx, testNonexistentOrgID, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for allocation delete, got %v", err)
-	}
-	err = repo.DeleteOrgHoliday(ctx, testNonexistentOrgID, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for holiday delete, got %v", err)
-	}
-	err = repo.DeleteGroupUnavailability(ctx, testNonexistentOrgID, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for group unavailability delete, got %v", err)
-	}
-	err = repo.DeletePersonUnavailability(ctx, testNonexistentOrgID, testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for person unavailability delete, got %v", err)
-	}
-	err = repo.DeletePersonUnavailabilityByPerson(ctx, testNonexistentOrgID, "person", testMissingID)
-	if !errors.Is(err, domain.ErrNotFound) {
-		t.Fatalf("expected not found for person-scoped person unavailability delete, got %v", err)
-	}
+		_, err = repo.GetOrganisation(ctx, testMissingID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for organisation, got %v", err)
+		}
+		err = repo.DeleteOrganisation(ctx, testMissingID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for delete organisation, got %v", err)
+		}
+		_, err = repo.GetProject(ctx, testNonexistentOrgID, testMissingID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for project, got %v", err)
+		}
+		err = repo.DeletePerson(ctx, testNonexistentOrgID, testMissingID, true)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for person delete, got %v", err)
+		}
+		err = repo.DeleteGroup(ctx, testNonexistentOrgID, testMissingID, true)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for group delete, got %v", err)
+		}
+		err = repo.DeleteAllocation(ctx, testNonexistentOrgID, testMissingID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for allocation delete, got %v", err)
+		}
+		err = repo.DeleteOrgHoliday(ctx, testNonexistentOrgID, testMissingID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for holiday delete, got %v", err)
+		}
+		err = repo.DeleteGroupUnavailability(ctx, testNonexistentOrgID, testMissingID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for group unavailability delete, got %v", err)
+		}
+		err = repo.DeletePersonUnavailability(ctx, testNonexistentOrgID, testMissingID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for person unavailability delete, got %v", err)
+		}
+		err = repo.DeletePersonUnavailabilityByPerson(ctx, testNonexistentOrgID, "person", testMissingID)
+		if !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for person-scoped person unavailability delete, got %v", err)
+		}
+	})
+}
+
+// TestFileRepositoryDeleteRefusesDependents verifies the file repository delete without cascade scenario.
+func TestFileRepositoryDeleteRefusesDependents(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		repo, err := open(filepath.Join(t.TempDir(), "dependents.json"))
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+		organisation, err := repo.CreateOrganisation(ctx, testOrganisation("Dependents Org"))
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		person, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Booked", EmploymentPct: 100})
+		if err != nil {
+			t.Fatalf("create person: %v", err)
+		}
+		project, err := repo.CreateProject(ctx, domain.Project{OrganisationID: organisation.ID, Name: "Booked Project"})
+		if err != nil {
+			t.Fatalf("create project: %v", err)
+		}
+		group, err := repo.CreateGroup(ctx, domain.Group{OrganisationID: organisation.ID, Name: "Booked Group", MemberIDs: []string{person.ID}})
+		if err != nil {
+			t.Fatalf("create group: %v", err)
+		}
+		allocation, err := repo.CreateAllocation(ctx, domain.Allocation{OrganisationID: organisation.ID, TargetType: domain.AllocationTargetPerson, TargetID: person.ID, ProjectID: project.ID, StartDate: "2026-01-01", EndDate: "2026-12-31", Percent: 50})
+		if err != nil {
+			t.Fatalf("create allocation: %v", err)
+		}
+		entry, err := repo.CreatePersonUnavailability(ctx, domain.PersonUnavailability{OrganisationID: organisation.ID, PersonID: person.ID, Date: "2026-01-05", Hours: 2})
+		if err != nil {
+			t.Fatalf("create person unavailability: %v", err)
+		}
+		groupEntry, err := repo.CreateGroupUnavailability(ctx, domain.GroupUnavailability{OrganisationID: organisation.ID, GroupID: group.ID, Date: "2026-01-06", Hours: 2})
+		if err != nil {
+			t.Fatalf("create group unavailability: %v", err)
+		}
+
+		var dependentsErr domain.DependentsError
+		err = repo.DeletePerson(ctx, organisation.ID, person.ID, false)
+		if !errors.As(err, &dependentsErr) || !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected person delete to be refused, got %v", err)
+		}
+		if dependentsErr.Resource != "person" || !slices.Equal(dependentsErr.Dependents.AllocationIDs, []string{allocation.ID}) ||
+			!slices.Equal(dependentsErr.Dependents.PersonUnavailabilityIDs, []string{entry.ID}) {
+			t.Fatalf("unexpected person dependents: %+v", dependentsErr)
+		}
+		err = repo.DeleteProject(ctx, organisation.ID, project.ID, false)
+		if !errors.As(err, &dependentsErr) || dependentsErr.Resource != "project" ||
+			!slices.Equal(dependentsErr.Dependents.AllocationIDs, []string{allocation.ID}) {
+			t.Fatalf("expected project delete to be refused, got %v", err)
+		}
+		err = repo.DeleteGroup(ctx, organisation.ID, group.ID, false)
+		if !errors.As(err, &dependentsErr) || dependentsErr.Resource != "group" ||
+			!slices.Equal(dependentsErr.Dependents.GroupUnavailabilityIDs, []string{groupEntry.ID}) {
+			t.Fatalf("expected group delete to be refused, got %v", err)
+		}
+		if _, err = repo.GetPerson(ctx, organisation.ID, person.ID); err != nil {
+			t.Fatalf("expected refused delete to keep the person, got %v", err)
+		}
+		if _, err = repo.GetAllocation(ctx, organisation.ID, allocation.ID); err != nil {
+			t.Fatalf("expected refused delete to keep the allocation, got %v", err)
+		}
+
+		if err = repo.DeletePerson(ctx, organisation.ID, person.ID, true); err != nil {
+			t.Fatalf("cascade delete person: %v", err)
+		}
+		if err = repo.DeleteProject(ctx, organisation.ID, project.ID, false); err != nil {
+			t.Fatalf("expected project without dependents to delete, got %v", err)
+		}
+	})
+}
+
+// TestFileRepositoryDeletePersonUpdatesTargetedHolidays verifies the file repository delete person updates targeted holidays scenario.
+func TestFileRepositoryDeletePersonUpdatesTargetedHolidays(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		repo, err := open(filepath.Join(t.TempDir(), testRepoFileName))
+		if err != nil {
+			t.Fatalf("new repo: %v", err)
+		}
+		organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		personOne, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "One", EmploymentPct: 100})
+		if err != nil {
+			t.Fatalf("create person: %v", err)
+		}
+		personTwo, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Two", EmploymentPct: 100})
+		if err != nil {
+			t.Fatalf("create person: %v", err)
+		}
+
+		shared, err := repo.CreateOrgHoliday(ctx, domain.OrgHoliday{OrganisationID: organisation.ID, Date: "2026-01-01", Hours: 8, PersonIDs: []string{personOne.ID, personTwo.ID}})
+		if err != nil {
+			t.Fatalf("create shared holiday: %v", err)
+		}
+		_, err = repo.CreateOrgHoliday(ctx, domain.OrgHoliday{OrganisationID: organisation.ID, Date: "2026-01-02", Hours: 8, PersonIDs: []string{personOne.ID}})
+		if err != nil {
+			t.Fatalf("create single-target holiday: %v", err)
+		}
+		orgWide, err := repo.CreateOrgHoliday(ctx, domain.OrgHoliday{OrganisationID: organisation.ID, Date: "2026-01-03", Hours: 8})
+		if err != nil {
+			t.Fatalf("create organisation-wide holiday: %v", err)
+		}
+
+		if err = repo.DeletePerson(ctx, organisation.ID, personOne.ID, true); err != nil {
+			t.Fatalf("delete person: %v", err)
+		}
+
+		holidays, err := repo.ListOrgHolidays(ctx, organisation.ID)
+		if err != nil {
+			t.Fatalf("list holidays: %v", err)
+		}
+		if len(holidays) != 2 {
+			t.Fatalf("expected single-target holiday to be removed, got %+v", holidays)
+		}
+		for _, holiday := range holidays {
+			switch holiday.ID {
+			case shared.ID:
+				if len(holiday.PersonIDs) != 1 || holiday.PersonIDs[0] != personTwo.ID {
+					t.Fatalf("expected shared holiday to keep remaining target, got %v", holiday.PersonIDs)
+				}
+			case orgWide.ID:
+				if len(holiday.PersonIDs) != 0 {
+					t.Fatalf("expected organisation-wide holiday to stay untargeted, got %v", holiday.PersonIDs)
+				}
+			default:
+				t.Fatalf("unexpected holiday %+v", holiday)
+			}
+		}
+	})
 }
 
 // TestFileRepositoryNormalizesLegacyAllocationTargets verifies the file repository normalizes legacy allocation targets scenario.
@@ -610,7 +758,7 @@ func TestPersistenceHelperBranches(t *testing.T) {
 		t.Fatalf("create blocker file: %v", err)
 	}
 	repo.path = filepath.Join(blockerPath, testRepoFileName)
-	if err := repo.persistLocked(); err == nil {
+	if err := repo.persistLocked(context.Background()); err == nil {
 		t.Fatal("expected persist error when parent path is a file")
 	}
 
@@ -619,11 +767,12 @@ func TestPersistenceHelperBranches(t *testing.T) {
 		t.Fatalf("create rename failure target directory: %v", mkdirErr)
 	}
 	repo.path = renameFailureDir
-	if err := repo.persistLocked(); err == nil {
+	if err := repo.persistLocked(context.Background()); err == nil {
 		t.Fatal("expected persist error when rename target is a directory")
 	}
-	if _, err := os.Stat(renameFailureDir + ".tmp"); !errors.Is(err, os.ErrNotExist) {
-		t.Fatalf("expected temp file cleanup after rename failure, got stat err: %v", err)
+	leftovers, err := filepath.Glob(filepath.Join(baseDir, "*.tmp"))
+	if err != nil || len(leftovers) != 0 {
+		t.Fatalf("expected temp file cleanup after rename failure, got %v (%v)", leftovers, err)
 	}
 }
 
@@ -661,6 +810,971 @@ func TestFileRepositoryRollsBackStateOnPersistFailure(t *testing.T) {
 	}
 }
 
+// TestFileRepositoryStateVersion verifies the file repository state version scenario.
+func TestFileRepositoryStateVersion(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewFileRepository(filepath.Join(t.TempDir(), "state-version.json"))
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	if version := repo.StateVersion(ctx); version != 0 {
+		t.Fatalf("expected a fresh repository to start at version 0, got %d", version)
+	}
+
+	organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Versioned Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	var person domain.Person
+	var allocation domain.Allocation
+	steps := []struct {
+		name  string
+		write bool
+		run   func() error
+	}{
+		{name: "list organisations", run: func() error { _, stepErr := repo.ListOrganisations(ctx); return stepErr }},
+		{name: "update organisation", write: true, run: func() error { _, stepErr := repo.UpdateOrganisation(ctx, organisation); return stepErr }},
+		{name: "create person", write: true, run: func() error {
+			var stepErr error
+			person, stepErr = repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Versioned", EmploymentPct: 100})
+			return stepErr
+		}},
+		{name: "get person", run: func() error { _, stepErr := repo.GetPerson(ctx, organisation.ID, person.ID); return stepErr }},
+		{name: "create allocation", write: true, run: func() error {
+			var stepErr error
+			allocation, stepErr = repo.CreateAllocation(ctx, domain.Allocation{OrganisationID: organisation.ID, TargetType: domain.AllocationTargetPerson, TargetID: person.ID, ProjectID: "project_1", StartDate: "2026-01-01", EndDate: "2026-01-31", Percent: 50})
+			return stepErr
+		}},
+		{name: "update allocations", write: true, run: func() error {
+			_, stepErr := repo.UpdateAllocations(ctx, organisation.ID, []domain.Allocation{allocation})
+			return stepErr
+		}},
+		{name: "list allocations", run: func() error { _, stepErr := repo.ListAllocations(ctx, organisation.ID); return stepErr }},
+		{name: "delete allocation", write: true, run: func() error { return repo.DeleteAllocation(ctx, organisation.ID, allocation.ID) }},
+		{name: "delete missing person", run: func() error {
+			if stepErr := repo.DeletePerson(ctx, organisation.ID, testMissingID, true); !errors.Is(stepErr, domain.ErrNotFound) {
+				return fmt.Errorf("expected not found, got %w", stepErr)
+			}
+			return nil
+		}},
+		{name: "delete organisation", write: true, run: func() error { return repo.DeleteOrganisation(ctx, organisation.ID) }},
+	}
+
+	for _, step := range steps {
+		before := repo.StateVersion(ctx)
+		if stepErr := step.run(); stepErr != nil {
+			t.Fatalf("%s: %v", step.name, stepErr)
+		}
+		expected := before
+		if step.write {
+			expected++
+		}
+		if after := repo.StateVersion(ctx); after != expected {
+			t.Fatalf("%s: expected version %d, got %d", step.name, expected, after)
+		}
+	}
+
+	const writers = 8
+	before := repo.StateVersion(ctx)
+	var wg sync.WaitGroup
+	for index := 0; index < writers; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, createErr := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Concurrent Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}); createErr != nil {
+				t.Errorf("concurrent create: %v", createErr)
+			}
+			_ = repo.StateVersion(ctx)
+		}()
+	}
+	wg.Wait()
+	if after := repo.StateVersion(ctx); after != before+writers {
+		t.Fatalf("expected %d concurrent writes to add %d, got %d", writers, writers, after-before)
+	}
+
+	repo.path = t.TempDir()
+	if _, err = repo.CreateOrganisation(ctx, domain.Organisation{Name: "Unpersisted Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}); err == nil {
+		t.Fatal("expected create to fail when the data file path is a directory")
+	}
+	if after := repo.StateVersion(ctx); after != before+writers {
+		t.Fatalf("expected a failed write to keep version %d, got %d", before+writers, after)
+	}
+}
+
+// TestFileRepositoryCreateWithLimit verifies the file repository create with limit scenario.
+func TestFileRepositoryCreateWithLimit(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewFileRepository(filepath.Join(t.TempDir(), "limits.json"))
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Limited Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+
+	const limit, writers = 3, 8
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created, refused := 0, 0
+	for index := 0; index < writers; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, personErr := repo.CreatePersonWithLimit(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Concurrent", EmploymentPct: 100}, limit)
+			_, projectErr := repo.CreateProjectWithLimit(ctx, domain.Project{OrganisationID: organisation.ID, Name: "Concurrent", StartDate: "2026-01-01"}, limit)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, createErr := range []error{personErr, projectErr} {
+				switch {
+				case createErr == nil:
+					created++
+				case errors.As(createErr, &domain.QuotaError{}) && errors.Is(createErr, domain.ErrValidation):
+					refused++
+				default:
+					t.Errorf("concurrent create: %v", createErr)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	projects, err := repo.ListProjects(ctx, organisation.ID)
+	if err != nil {
+		t.Fatalf("list projects: %v", err)
+	}
+	if created != 2*limit || refused != 2*(writers-limit) || len(projects) != limit {
+		t.Fatalf("expected %d creates per kind, got %d created, %d refused, %d projects", limit, created, refused, len(projects))
+	}
+	if _, err = repo.CreatePersonWithLimit(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Unlimited", EmploymentPct: 100}, 0); err != nil {
+		t.Fatalf("expected a create without limit to pass, got %v", err)
+	}
+}
+
+// TestFileRepositoryDebouncedSaveCoalescesBurst verifies the file repository debounced save coalesces burst scenario.
+func TestFileRepositoryDebouncedSaveCoalescesBurst(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "debounced.json")
+	repo, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	repo.EnableDebouncedSave(time.Hour, 0)
+	writesBefore := diskWriteCount(repo)
+
+	const burst = 20
+	for i := range burst {
+		if _, err = repo.CreateOrganisation(ctx, domain.Organisation{Name: fmt.Sprintf("Burst Org %d", i), HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}); err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+	}
+	if writes := diskWriteCount(repo); writes != writesBefore {
+		t.Fatalf("expected the burst to stay in memory, got %d disk writes", writes-writesBefore)
+	}
+	if version := repo.StateVersion(ctx); version != burst {
+		t.Fatalf("expected version %d after the burst, got %d", burst, version)
+	}
+	organisations, err := repo.ListOrganisations(ctx)
+	if err != nil {
+		t.Fatalf("list organisations: %v", err)
+	}
+	if len(organisations) != burst {
+		t.Fatalf("expected reads to see %d pending organisations, got %d", burst, len(organisations))
+	}
+	if onDisk := reopenOrganisationCount(t, path); onDisk != 0 {
+		t.Fatalf("expected no organisations on disk before flush, got %d", onDisk)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("close repository: %v", err)
+	}
+	if writes := diskWriteCount(repo); writes != writesBefore+1 {
+		t.Fatalf("expected one disk write for the burst, got %d", writes-writesBefore)
+	}
+	if onDisk := reopenOrganisationCount(t, path); onDisk != burst {
+		t.Fatalf("expected %d organisations on disk after close, got %d", burst, onDisk)
+	}
+}
+
+// TestFileRepositoryDebouncedSaveFlushesOnTimer verifies the file repository debounced save flushes on timer scenario.
+func TestFileRepositoryDebouncedSaveFlushesOnTimer(t *testing.T) {
+	tests := []struct {
+		name        string
+		quietPeriod time.Duration
+		maxDelay    time.Duration
+	}{
+		{name: "quiet period", quietPeriod: 10 * time.Millisecond},
+		{name: "max delay", quietPeriod: time.Hour, maxDelay: 10 * time.Millisecond},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "debounced.json")
+			repo, err := NewFileRepository(path)
+			if err != nil {
+				t.Fatalf(errCreateRepositoryFmt, err)
+			}
+			repo.EnableDebouncedSave(tc.quietPeriod, tc.maxDelay)
+			writesBefore := diskWriteCount(repo)
+
+			if _, err = repo.CreateOrganisation(ctx, domain.Organisation{Name: "Timer Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}); err != nil {
+				t.Fatalf(errCreateOrganisationFmt, err)
+			}
+			deadline := time.Now().Add(5 * time.Second)
+			for diskWriteCount(repo) == writesBefore {
+				if time.Now().After(deadline) {
+					t.Fatal("expected the pending change to be flushed")
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			if onDisk := reopenOrganisationCount(t, path); onDisk != 1 {
+				t.Fatalf("expected the organisation on disk after the flush, got %d", onDisk)
+			}
+		})
+	}
+}
+
+// TestFileRepositoryDebouncedSaveRetriesFailedFlush verifies the file repository debounced save retries failed flush scenario.
+func TestFileRepositoryDebouncedSaveRetriesFailedFlush(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "debounced.json")
+	repo, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	repo.EnableDebouncedSave(10*time.Millisecond, 0)
+	failures := make(chan string, 16)
+	repo.mu.Lock()
+	repo.logf = func(format string, args ...any) {
+		select {
+		case failures <- fmt.Sprintf(format, args...):
+		default:
+		}
+	}
+	repo.path = t.TempDir()
+	repo.mu.Unlock()
+	writesBefore := diskWriteCount(repo)
+
+	if _, err = repo.CreateOrganisation(ctx, domain.Organisation{Name: "Retried Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}); err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	select {
+	case message := <-failures:
+		if !strings.Contains(message, "debounced save failed") {
+			t.Fatalf("expected the failed flush to be logged, got %q", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the failed flush to be logged")
+	}
+
+	repo.mu.Lock()
+	repo.path = path
+	repo.mu.Unlock()
+	deadline := time.Now().Add(5 * time.Second)
+	for diskWriteCount(repo) == writesBefore {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a later flush to write the pending change without another mutation")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if onDisk := reopenOrganisationCount(t, path); onDisk != 1 {
+		t.Fatalf("expected the organisation on disk after the retry, got %d", onDisk)
+	}
+}
+
+// TestFileRepositoryDebouncedSaveRollback verifies the file repository debounced save rollback scenario.
+func TestFileRepositoryDebouncedSaveRollback(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "debounced.json")
+	repo, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+	repo.EnableDebouncedSave(time.Hour, 0)
+
+	if _, err = repo.CreateOrganisation(ctx, domain.Organisation{Name: "Accepted Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}); err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err = repo.CreateOrganisation(cancelled, domain.Organisation{Name: "Cancelled Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled create to fail, got %v", err)
+	}
+
+	organisations, err := repo.ListOrganisations(ctx)
+	if err != nil {
+		t.Fatalf("list organisations: %v", err)
+	}
+	if len(organisations) != 1 || organisations[0].Name != "Accepted Org" {
+		t.Fatalf("expected only the accepted pending organisation, got %+v", organisations)
+	}
+	if err = repo.Close(); err != nil {
+		t.Fatalf("close repository: %v", err)
+	}
+	if onDisk := reopenOrganisationCount(t, path); onDisk != 1 {
+		t.Fatalf("expected the accepted organisation on disk, got %d", onDisk)
+	}
+}
+
+func diskWriteCount(repo *FileRepository) uint64 {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return repo.diskWrites
+}
+
+func reopenOrganisationCount(t *testing.T, path string) int {
+	t.Helper()
+	reopened, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("reopen repository: %v", err)
+	}
+	organisations, err := reopened.ListOrganisations(context.Background())
+	if err != nil {
+		t.Fatalf("list reopened organisations: %v", err)
+	}
+	return len(organisations)
+}
+
+// TestFileRepositoryPurgeCalendarEntriesIsTransactional verifies the file repository purge calendar entries is transactional scenario.
+func TestFileRepositoryPurgeCalendarEntriesIsTransactional(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "purge-calendar.json")
+	repo, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatalf(errCreateRepositoryFmt, err)
+	}
+
+	organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Purge Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+	if err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
+	}
+	for _, date := range []string{"2025-01-01", "2026-01-01"} {
+		if _, err = repo.CreateOrgHoliday(ctx, domain.OrgHoliday{OrganisationID: organisation.ID, Date: date, Hours: 8}); err != nil {
+			t.Fatalf("create holiday: %v", err)
+		}
+		if _, err = repo.CreatePersonUnavailability(ctx, domain.PersonUnavailability{OrganisationID: organisation.ID, PersonID: "person_1", Date: date, Hours: 2}); err != nil {
+			t.Fatalf("create person unavailability: %v", err)
+		}
+	}
+
+	renameFailureTarget := filepath.Join(t.TempDir(), "rename-target-dir")
+	if err = os.Mkdir(renameFailureTarget, 0o755); err != nil {
+		t.Fatalf("create rename target directory: %v", err)
+	}
+	repo.path = renameFailureTarget
+	if _, err = repo.PurgeCalendarEntriesBefore(ctx, organisation.ID, "2026-01-01"); err == nil {
+		t.Fatal("expected purge to fail when persist cannot rename to directory path")
+	}
+	holidays, err := repo.ListOrgHolidays(ctx, organisation.ID)
+	if err != nil || len(holidays) != 2 {
+		t.Fatalf("expected failed purge to keep both holidays, got %+v err=%v", holidays, err)
+	}
+
+	repo.path = path
+	result, err := repo.PurgeCalendarEntriesBefore(ctx, organisation.ID, "2026-01-01")
+	if err != nil {
+		t.Fatalf("purge calendar entries: %v", err)
+	}
+	if result != (domain.CalendarPurgeResult{OrgHolidays: 1, PersonUnavailability: 1}) {
+		t.Fatalf("unexpected purge counts: %+v", result)
+	}
+	entries, err := repo.ListPersonUnavailability(ctx, organisation.ID)
+	if err != nil || len(entries) != 1 || entries[0].Date != "2026-01-01" {
+		t.Fatalf("expected cutoff-dated entry to remain, got %+v err=%v", entries, err)
+	}
+}
+
+// TestFileRepositoryAllocationEvents verifies the file repository allocation events scenario.
+func TestFileRepositoryAllocationEvents(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "allocation-events.json")
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+
+		organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Audit Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		person, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Audited", EmploymentPct: 100})
+		if err != nil {
+			t.Fatalf("create person: %v", err)
+		}
+		project, err := repo.CreateProject(ctx, domain.Project{OrganisationID: organisation.ID, Name: "Audited", StartDate: "2026-01-01"})
+		if err != nil {
+			t.Fatalf("create project: %v", err)
+		}
+		input := domain.Allocation{OrganisationID: organisation.ID, TargetType: domain.AllocationTargetPerson, TargetID: person.ID, ProjectID: project.ID, StartDate: "2026-01-01", EndDate: "2026-12-31", Percent: 20}
+		unaudited, err := repo.CreateAllocation(ctx, input)
+		if err != nil {
+			t.Fatalf("create unaudited allocation: %v", err)
+		}
+
+		// Audited writes are dated after the unaudited one, which takes the
+		// current time.
+		at := time.Now().UTC().Truncate(time.Second).Add(time.Hour)
+		audited := ports.WithAllocationAudit(ctx, domain.AllocationAudit{ActorID: "user-1", At: at})
+		created, err := repo.CreateAllocation(audited, input)
+		if err != nil {
+			t.Fatalf("create audited allocation: %v", err)
+		}
+		changed := created
+		changed.Percent = 40
+		changed.Warnings = []string{"response only"}
+		ending := ports.WithAllocationAudit(ctx, domain.AllocationAudit{ActorID: "user-1", At: at.Add(time.Hour), UpdateAction: domain.AllocationEventEnded})
+		if _, err = repo.UpdateAllocations(ending, organisation.ID, []domain.Allocation{changed, {ID: testMissingID, OrganisationID: organisation.ID}}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected a failing batch to be rejected, got %v", err)
+		}
+		if _, err = repo.UpdateAllocation(ending, changed); err != nil {
+			t.Fatalf("update audited allocation: %v", err)
+		}
+		if err = repo.DeleteAllocation(ports.WithAllocationAudit(ctx, domain.AllocationAudit{ActorID: "user-2", At: at.Add(2 * time.Hour)}), organisation.ID, created.ID); err != nil {
+			t.Fatalf("delete audited allocation: %v", err)
+		}
+		if err = repo.DeleteProject(ports.WithAllocationAudit(ctx, domain.AllocationAudit{ActorID: "user-3", At: at.Add(3 * time.Hour)}), organisation.ID, project.ID, true); err != nil {
+			t.Fatalf("delete project: %v", err)
+		}
+
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		events, err := reopened.ListAllocationEvents(ctx, organisation.ID)
+		if err != nil {
+			t.Fatalf("list events: %v", err)
+		}
+		actions := map[string][]string{}
+		for _, event := range events {
+			if event.OrganisationID != organisation.ID || event.ID == "" || event.At.IsZero() {
+				t.Fatalf("expected stored events of the organisation, got %+v", events)
+			}
+			actions[event.AllocationID] = append(actions[event.AllocationID], event.Action+" by "+event.ActorID)
+		}
+		want := map[string][]string{
+			unaudited.ID: {"created by ", "deleted by user-3"},
+			created.ID:   {"created by user-1", "ended by user-1", "deleted by user-2"},
+		}
+		if !reflect.DeepEqual(actions, want) {
+			t.Fatalf("expected one event per write including the cascading delete, got %v", actions)
+		}
+		var ended domain.AllocationEvent
+		for _, event := range events {
+			if event.Action == domain.AllocationEventEnded {
+				ended = event
+			}
+		}
+		if ended.Before == nil || ended.Before.Percent != 20 || ended.After == nil || ended.After.Percent != 40 || ended.After.Warnings != nil {
+			t.Fatalf("expected before and after snapshots without warnings to persist, got %+v", ended)
+		}
+
+		if err := reopened.DeleteOrganisation(ctx, organisation.ID); err != nil {
+			t.Fatalf("delete organisation: %v", err)
+		}
+		remaining, err := reopened.ListAllocationEvents(ctx, organisation.ID)
+		if err != nil {
+			t.Fatalf("list events after delete: %v", err)
+		}
+		if len(remaining) != 0 {
+			t.Fatalf("expected events to go with their organisation, got %d", len(remaining))
+		}
+	})
+}
+
+// TestFileRepositoryWebhooks verifies the file repository webhooks scenario.
+func TestFileRepositoryWebhooks(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "webhooks.json")
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+
+		organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Webhook Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		if _, err := repo.CreateWebhook(ctx, domain.Webhook{OrganisationID: "missing"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for unknown organisation, got %v", err)
+		}
+		webhook, err := repo.CreateWebhook(ctx, domain.Webhook{
+			OrganisationID: organisation.ID,
+			URL:            "https://example.com/hook",
+			Events:         []string{"person.created"},
+			Secret:         "secret",
+		})
+		if err != nil {
+			t.Fatalf("create webhook: %v", err)
+		}
+		if _, err := repo.AppendWebhookDelivery(ctx, domain.WebhookDelivery{OrganisationID: organisation.ID, WebhookID: "missing"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for unknown webhook, got %v", err)
+		}
+
+		at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		for attempt := range maxWebhookDeliveries + 2 {
+			if _, err := repo.AppendWebhookDelivery(ctx, domain.WebhookDelivery{
+				OrganisationID: organisation.ID,
+				WebhookID:      webhook.ID,
+				Attempt:        attempt,
+				At:             at.Add(time.Duration(attempt) * time.Second),
+			}); err != nil {
+				t.Fatalf("append delivery %d: %v", attempt, err)
+			}
+		}
+
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		stored, err := reopened.GetWebhook(ctx, organisation.ID, webhook.ID)
+		if err != nil || stored.Secret != "secret" || len(stored.Events) != 1 {
+			t.Fatalf("expected webhook to persist with its secret, got %+v err=%v", stored, err)
+		}
+		deliveries, err := reopened.ListWebhookDeliveries(ctx, organisation.ID, webhook.ID)
+		if err != nil {
+			t.Fatalf("list deliveries: %v", err)
+		}
+		if len(deliveries) != maxWebhookDeliveries || deliveries[0].Attempt != 2 {
+			t.Fatalf("expected the oldest deliveries to be dropped, got %d starting at attempt %d", len(deliveries), deliveries[0].Attempt)
+		}
+
+		if err := reopened.DeleteWebhook(ctx, organisation.ID, webhook.ID); err != nil {
+			t.Fatalf("delete webhook: %v", err)
+		}
+		if _, err := reopened.ListWebhookDeliveries(ctx, organisation.ID, webhook.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected deliveries to go with their webhook, got %v", err)
+		}
+		webhooks, err := reopened.ListWebhooks(ctx, organisation.ID)
+		if err != nil || len(webhooks) != 0 {
+			t.Fatalf("expected no webhooks after delete, got %+v err=%v", webhooks, err)
+		}
+	})
+}
+
+// TestFileRepositoryUnavailabilityRules verifies the file repository unavailability rules scenario.
+func TestFileRepositoryUnavailabilityRules(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "rules.json")
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+
+		organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Rule Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		person, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Rule Person", EmploymentPct: 100})
+		if err != nil {
+			t.Fatalf("create person: %v", err)
+		}
+		if _, err := repo.CreateUnavailabilityRule(ctx, domain.UnavailabilityRule{OrganisationID: "missing"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for unknown organisation, got %v", err)
+		}
+		rule, err := repo.CreateUnavailabilityRule(ctx, domain.UnavailabilityRule{
+			OrganisationID: organisation.ID,
+			PersonID:       person.ID,
+			Weekdays:       []string{"friday"},
+			Hours:          4,
+			StartDate:      "2026-03-01",
+		})
+		if err != nil {
+			t.Fatalf("create rule: %v", err)
+		}
+
+		rule.PersonID = "someone-else"
+		rule.Hours = 2
+		updated, err := repo.UpdateUnavailabilityRule(ctx, rule)
+		if err != nil || updated.PersonID != person.ID || updated.Hours != 2 || updated.Version != 2 {
+			t.Fatalf("expected the update to keep the target and bump the version, got %+v err=%v", updated, err)
+		}
+		if _, err := repo.UpdateUnavailabilityRule(ctx, rule); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected a stale update to conflict, got %v", err)
+		}
+
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		stored, err := reopened.GetUnavailabilityRule(ctx, organisation.ID, rule.ID)
+		if err != nil || stored.Hours != 2 || len(stored.Weekdays) != 1 {
+			t.Fatalf("expected the rule to persist, got %+v err=%v", stored, err)
+		}
+		if _, err := reopened.GetUnavailabilityRule(ctx, "other-org", rule.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected another organisation not to see the rule, got %v", err)
+		}
+
+		if err := reopened.DeletePerson(ctx, organisation.ID, person.ID, true); err != nil {
+			t.Fatalf("delete person: %v", err)
+		}
+		rules, err := reopened.ListUnavailabilityRules(ctx, organisation.ID)
+		if err != nil || len(rules) != 0 {
+			t.Fatalf("expected rules to go with their person, got %+v err=%v", rules, err)
+		}
+		if err := reopened.DeleteUnavailabilityRule(ctx, organisation.ID, rule.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected deleting a removed rule to fail with not found, got %v", err)
+		}
+	})
+}
+
+// TestFileRepositoryAllocationTemplates verifies the file repository allocation templates scenario.
+func TestFileRepositoryAllocationTemplates(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "templates.json")
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+
+		organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Template Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		if _, err := repo.CreateAllocationTemplate(ctx, domain.AllocationTemplate{OrganisationID: "missing"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for unknown organisation, got %v", err)
+		}
+		template, err := repo.CreateAllocationTemplate(ctx, domain.AllocationTemplate{
+			OrganisationID: organisation.ID,
+			Name:           "Rotation",
+			Entries:        []domain.AllocationTemplateEntry{{TargetType: domain.AllocationTargetPerson, TargetID: "person_1", Percent: 50}},
+		})
+		if err != nil || template.Version != 1 {
+			t.Fatalf("create template: %+v err=%v", template, err)
+		}
+
+		if _, err := repo.CreateAllocations(ctx, "missing", []domain.Allocation{{ProjectID: "project_1"}}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for unknown organisation, got %v", err)
+		}
+		created, err := repo.CreateAllocations(ctx, organisation.ID, []domain.Allocation{
+			{TargetType: domain.AllocationTargetPerson, TargetID: "person_1", ProjectID: "project_1", StartDate: "2026-04-01", EndDate: "2026-06-30", Percent: 50},
+			{TargetType: domain.AllocationTargetGroup, TargetID: "group_1", ProjectID: "project_1", StartDate: "2026-04-01", EndDate: "2026-06-30", Percent: 20},
+		})
+		if err != nil || len(created) != 2 || created[0].ID == created[1].ID {
+			t.Fatalf("create allocations: %+v err=%v", created, err)
+		}
+		if created[0].PersonID != "person_1" || created[1].PersonID != "" || created[1].OrganisationID != organisation.ID {
+			t.Fatalf("expected targets to be normalized, got %+v", created)
+		}
+
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		stored, err := reopened.GetAllocationTemplate(ctx, organisation.ID, template.ID)
+		if err != nil || stored.Name != "Rotation" || len(stored.Entries) != 1 {
+			t.Fatalf("expected the template to persist, got %+v err=%v", stored, err)
+		}
+		if _, err := reopened.GetAllocationTemplate(ctx, "other-org", template.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected another organisation not to see the template, got %v", err)
+		}
+		allocations, err := reopened.ListAllocations(ctx, organisation.ID)
+		if err != nil || len(allocations) != 2 {
+			t.Fatalf("expected both allocations to persist, got %+v err=%v", allocations, err)
+		}
+
+		if err := reopened.DeleteAllocationTemplate(ctx, organisation.ID, template.ID); err != nil {
+			t.Fatalf("delete template: %v", err)
+		}
+		templates, err := reopened.ListAllocationTemplates(ctx, organisation.ID)
+		if err != nil || len(templates) != 0 {
+			t.Fatalf("expected no templates, got %+v err=%v", templates, err)
+		}
+		if err := reopened.DeleteAllocationTemplate(ctx, organisation.ID, template.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected deleting a removed template to fail with not found, got %v", err)
+		}
+	})
+}
+
+// TestFileRepositoryScenarios verifies the scenario storage and apply scenario.
+func TestFileRepositoryScenarios(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "scenarios.json")
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+
+		organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Scenario Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		live, err := repo.CreateAllocations(ctx, organisation.ID, []domain.Allocation{
+			{TargetType: domain.AllocationTargetPerson, TargetID: "person_1", ProjectID: "project_1", StartDate: "2026-04-01", EndDate: "2026-06-30", Percent: 50},
+			{TargetType: domain.AllocationTargetPerson, TargetID: "person_1", ProjectID: "project_1", StartDate: "2026-04-01", EndDate: "2026-06-30", Percent: 20},
+		})
+		if err != nil {
+			t.Fatalf("create allocations: %v", err)
+		}
+		if _, err := repo.CreateScenario(ctx, domain.Scenario{OrganisationID: "missing"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for unknown organisation, got %v", err)
+		}
+		scenario, err := repo.CreateScenario(ctx, domain.Scenario{
+			OrganisationID: organisation.ID,
+			Name:           "Reshuffle",
+			Allocations:    live,
+			BaseVersions:   map[string]int64{live[0].ID: live[0].Version, live[1].ID: live[1].Version},
+		})
+		if err != nil || scenario.Version != 1 {
+			t.Fatalf("create scenario: %+v err=%v", scenario, err)
+		}
+
+		changed := scenario.Allocations[0]
+		changed.Percent = 60
+		changed.Version++
+		scenario.Allocations = []domain.Allocation{changed, {
+			ID:         domain.ScenarioAllocationIDPrefix + "1",
+			TargetType: domain.AllocationTargetPerson,
+			TargetID:   "person_1",
+			ProjectID:  "project_1",
+			StartDate:  "2026-04-01",
+			EndDate:    "2026-06-30",
+			Percent:    40,
+			Version:    1,
+		}}
+		scenario.BaseVersions = nil
+		edited, err := repo.UpdateScenario(ctx, scenario)
+		if err != nil || edited.Version != 2 || len(edited.BaseVersions) != 2 {
+			t.Fatalf("expected the update to keep the base versions, got %+v err=%v", edited, err)
+		}
+		if _, err := repo.UpdateScenario(ctx, scenario); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected a stale scenario update to conflict, got %v", err)
+		}
+		if _, err := repo.ApplyScenario(ctx, scenario); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected a stale scenario apply to conflict, got %v", err)
+		}
+
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		stored, err := reopened.GetScenario(ctx, organisation.ID, scenario.ID)
+		if err != nil || len(stored.Allocations) != 2 || stored.Allocations[0].Percent != 60 {
+			t.Fatalf("expected the scenario to persist, got %+v err=%v", stored, err)
+		}
+		if _, err := reopened.GetScenario(ctx, "other-org", scenario.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected another organisation not to see the scenario, got %v", err)
+		}
+
+		applied, err := reopened.ApplyScenario(ctx, stored)
+		if err != nil {
+			t.Fatalf("apply scenario: %v", err)
+		}
+		if len(applied.Created) != 1 || len(applied.Updated) != 1 || len(applied.DeletedIDs) != 1 || applied.DeletedIDs[0] != live[1].ID {
+			t.Fatalf("unexpected applied changes %+v", applied)
+		}
+		if applied.Created[0].ID == domain.ScenarioAllocationIDPrefix+"1" || applied.Updated[0].Version != live[0].Version+1 {
+			t.Fatalf("expected stored IDs and versions, got %+v", applied)
+		}
+		allocations, err := reopened.ListAllocations(ctx, organisation.ID)
+		if err != nil || len(allocations) != 2 {
+			t.Fatalf("expected the applied allocations, got %+v err=%v", allocations, err)
+		}
+		stored, err = reopened.GetScenario(ctx, organisation.ID, scenario.ID)
+		if err != nil || stored.AppliedAt == nil {
+			t.Fatalf("expected the scenario to be marked applied, got %+v err=%v", stored, err)
+		}
+		if _, err := reopened.ApplyScenario(ctx, stored); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected applying twice to conflict, got %v", err)
+		}
+		if _, err := reopened.UpdateScenario(ctx, stored); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected updating an applied scenario to conflict, got %v", err)
+		}
+
+		if err := reopened.DeleteScenario(ctx, organisation.ID, scenario.ID); err != nil {
+			t.Fatalf("delete scenario: %v", err)
+		}
+		scenarios, err := reopened.ListScenarios(ctx, organisation.ID)
+		if err != nil || len(scenarios) != 0 {
+			t.Fatalf("expected no scenarios, got %+v err=%v", scenarios, err)
+		}
+	})
+}
+
+// TestFileRepositoryRestoreAllocation verifies the restore allocation scenario.
+func TestFileRepositoryRestoreAllocation(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "restore.json")
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+
+		organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Restore Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		allocation, err := repo.CreateAllocation(ctx, domain.Allocation{
+			OrganisationID: organisation.ID,
+			TargetType:     domain.AllocationTargetPerson,
+			TargetID:       "person_1",
+			ProjectID:      "project_1",
+			StartDate:      "2026-04-01",
+			EndDate:        "2026-06-30",
+			Percent:        50,
+		})
+		if err != nil {
+			t.Fatalf("create allocation: %v", err)
+		}
+		if _, err := repo.RestoreAllocation(ctx, allocation); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected restoring an existing ID to conflict, got %v", err)
+		}
+		if err := repo.DeleteAllocation(ctx, organisation.ID, allocation.ID); err != nil {
+			t.Fatalf("delete allocation: %v", err)
+		}
+		restored, err := repo.RestoreAllocation(ctx, allocation)
+		if err != nil || restored.ID != allocation.ID || restored.Version != allocation.Version+1 || !restored.CreatedAt.Equal(allocation.CreatedAt) {
+			t.Fatalf("expected the allocation back under its ID with the next version, got %+v err=%v", restored, err)
+		}
+
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		if stored, err := reopened.GetAllocation(ctx, organisation.ID, allocation.ID); err != nil || stored.Percent != 50 {
+			t.Fatalf("expected the restored allocation to persist, got %+v err=%v", stored, err)
+		}
+	})
+}
+
+// TestFileRepositoryUpdatePersonEndingAllocations verifies the file repository update person ending allocations scenario.
+func TestFileRepositoryUpdatePersonEndingAllocations(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		repo, err := open(filepath.Join(t.TempDir(), "person-allocations.json"))
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+		organisation, err := repo.CreateOrganisation(ctx, domain.Organisation{Name: "Leavers Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		person, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Leaver", EmploymentPct: 100})
+		if err != nil {
+			t.Fatalf("create person: %v", err)
+		}
+		newAllocation := func(targetType, targetID, startDate, endDate string) domain.Allocation {
+			t.Helper()
+			allocation, createErr := repo.CreateAllocation(ctx, domain.Allocation{OrganisationID: organisation.ID, TargetType: targetType, TargetID: targetID, ProjectID: "project_1", StartDate: startDate, EndDate: endDate, Percent: 50})
+			if createErr != nil {
+				t.Fatalf("create allocation: %v", createErr)
+			}
+			return allocation
+		}
+		running := newAllocation(domain.AllocationTargetPerson, person.ID, "2026-01-01", "2026-12-31")
+		later := newAllocation(domain.AllocationTargetPerson, person.ID, "2026-08-01", "2026-09-30")
+		finished := newAllocation(domain.AllocationTargetPerson, person.ID, "2026-01-01", "2026-03-31")
+		group := newAllocation(domain.AllocationTargetGroup, "group_1", "2026-01-01", "2026-12-31")
+
+		stale := person
+		stale.Version = person.Version + 1
+		stale.Name = "Rolled Back"
+		if _, _, _, err = repo.UpdatePersonEndingAllocations(ctx, stale, "2026-06-01", "left"); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected stale person version to conflict, got %v", err)
+		}
+		stored, err := repo.GetPerson(ctx, organisation.ID, person.ID)
+		if err != nil {
+			t.Fatalf("get person: %v", err)
+		}
+		storedRunning, err := repo.GetAllocation(ctx, organisation.ID, running.ID)
+		if err != nil {
+			t.Fatalf("get allocation: %v", err)
+		}
+		if stored.Name != "Leaver" || storedRunning.EndDate != "2026-12-31" {
+			t.Fatalf("expected failed write to leave records alone, got person %+v allocation %+v", stored, storedRunning)
+		}
+
+		person.Name = "Left"
+		updatedPerson, ended, deletedIDs, err := repo.UpdatePersonEndingAllocations(ctx, person, "2026-06-01", "left")
+		if err != nil {
+			t.Fatalf("update person ending allocations: %v", err)
+		}
+		if updatedPerson.Name != "Left" || len(ended) != 1 || ended[0].ID != running.ID || ended[0].EndDate != "2026-05-31" || ended[0].EndReason != "left" {
+			t.Fatalf("unexpected ended allocations: %+v %+v", updatedPerson, ended)
+		}
+		if len(deletedIDs) != 1 || deletedIDs[0] != later.ID {
+			t.Fatalf("expected only the later allocation to be deleted, got %v", deletedIDs)
+		}
+		if _, err = repo.GetAllocation(ctx, organisation.ID, later.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected later allocation to be deleted, got %v", err)
+		}
+		for _, untouched := range []domain.Allocation{finished, group} {
+			storedAllocation, getErr := repo.GetAllocation(ctx, organisation.ID, untouched.ID)
+			if getErr != nil || storedAllocation.EndDate != untouched.EndDate || storedAllocation.EndReason != "" {
+				t.Fatalf("expected allocation %s to stay unchanged, got %+v err=%v", untouched.ID, storedAllocation, getErr)
+			}
+		}
+
+		missing := domain.Person{ID: testMissingID, OrganisationID: organisation.ID, Name: "Ghost", EmploymentPct: 100}
+		if _, _, _, err = repo.UpdatePersonEndingAllocations(ctx, missing, "2026-06-01", "left"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected missing person to fail, got %v", err)
+		}
+	})
+}
+
+// TestFileRepositoryEntityVersions verifies the file repository entity versions scenario.
+func TestFileRepositoryEntityVersions(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "entity-versions.json")
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+		organisation, err := repo.CreateOrganisation(ctx, testOrganisation("Versions Org"))
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		person, err := repo.CreatePerson(ctx, domain.Person{OrganisationID: organisation.ID, Name: "Versioned", EmploymentPct: 100})
+		if err != nil {
+			t.Fatalf("create person: %v", err)
+		}
+		if organisation.Version != 1 || person.Version != 1 {
+			t.Fatalf("expected new records at version 1, got organisation %d person %d", organisation.Version, person.Version)
+		}
+
+		updated, err := repo.UpdatePerson(ctx, person)
+		if err != nil {
+			t.Fatalf("update person: %v", err)
+		}
+		if updated.Version != 2 {
+			t.Fatalf("expected version 2 after an update, got %d", updated.Version)
+		}
+		if _, err = repo.UpdatePerson(ctx, person); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("expected an update against version 1 to conflict, got %v", err)
+		}
+		person.Version = 0
+		if updated, err = repo.UpdatePerson(ctx, person); err != nil || updated.Version != 3 {
+			t.Fatalf("expected an unversioned update to reach version 3, got %d err=%v", updated.Version, err)
+		}
+
+		group, err := repo.CreateGroup(ctx, domain.Group{OrganisationID: organisation.ID, Name: "Team", MemberIDs: []string{person.ID}})
+		if err != nil {
+			t.Fatalf("create group: %v", err)
+		}
+		if err = repo.DeletePerson(ctx, organisation.ID, person.ID, true); err != nil {
+			t.Fatalf("delete person: %v", err)
+		}
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		stored, err := reopened.GetGroup(ctx, organisation.ID, group.ID)
+		if err != nil {
+			t.Fatalf("get group: %v", err)
+		}
+		if stored.Version != group.Version+1 || len(stored.MemberIDs) != 0 {
+			t.Fatalf("expected removing the member to bump the group version, got %+v", stored)
+		}
+	})
+}
+
 // TestSortingHelpers verifies the sorting helpers scenario.
 func TestSortingHelpers(t *testing.T) {
 	verifySortedOrganisations(t)
@@ -759,269 +1873,374 @@ func verifySortedPersonUnavailability(t *testing.T) {
 
 // TestFileRepositoryClose verifies the file repository close scenario.
 func TestFileRepositoryClose(t *testing.T) {
-	ctx := context.Background()
-	path := filepath.Join(t.TempDir(), testRepoFileName)
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), testRepoFileName)
 
-	repo, err := NewFileRepository(path)
-	if err != nil {
-		t.Fatalf(errCreateRepositoryFmt, err)
-	}
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
 
-	created, err := repo.CreateOrganisation(ctx, domain.Organisation{
-		Name:         "Close Persisted Org",
-		HoursPerDay:  8,
-		HoursPerWeek: 40,
-		HoursPerYear: 2080,
-	})
-	if err != nil {
-		t.Fatalf(errCreateOrganisationFmt, err)
-	}
+		created, err := repo.CreateOrganisation(ctx, domain.Organisation{
+			Name:         "Close Persisted Org",
+			HoursPerDay:  8,
+			HoursPerWeek: 40,
+			HoursPerYear: 2080,
+		})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
 
-	err = repo.Close()
-	if err != nil {
-		t.Fatalf("close repository: %v", err)
-	}
+		err = repo.Close()
+		if err != nil {
+			t.Fatalf("close repository: %v", err)
+		}
 
-	err = repo.Close()
-	if err != nil {
-		t.Fatalf("close repository second time: %v", err)
-	}
+		err = repo.Close()
+		if err != nil {
+			t.Fatalf("close repository second time: %v", err)
+		}
 
-	reopened, err := NewFileRepository(path)
-	if err != nil {
-		t.Fatalf("reopen repository: %v", err)
-	}
-	organisations, err := reopened.ListOrganisations(ctx)
-	if err != nil {
-		t.Fatalf("list organisations after reopen: %v", err)
-	}
-	if len(organisations) != 1 || organisations[0].ID != created.ID {
-		t.Fatalf("expected persisted organisation after close, got %+v", organisations)
-	}
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		organisations, err := reopened.ListOrganisations(ctx)
+		if err != nil {
+			t.Fatalf("list organisations after reopen: %v", err)
+		}
+		if len(organisations) != 1 || organisations[0].ID != created.ID {
+			t.Fatalf("expected persisted organisation after close, got %+v", organisations)
+		}
+	})
 }
 
 // TestFileRepositoryContextCancellation verifies the file repository context cancellation scenario.
 func TestFileRepositoryContextCancellation(t *testing.T) {
-	repo, err := NewFileRepository(filepath.Join(t.TempDir(), "context-cancel-repo.json"))
-	if err != nil {
-		t.Fatalf(errCreateRepositoryFmt, err)
-	}
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		repo, err := open(filepath.Join(t.TempDir(), "context-cancel-repo.json"))
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
 
-	cancelledCtx, cancel := context.WithCancel(context.Background())
-	cancel()
+		cancelledCtx, cancel := context.WithCancel(context.Background())
+		cancel()
 
-	_, err = repo.ListOrganisations(cancelledCtx)
-	if !errors.Is(err, context.Canceled) {
-		t.Fatalf("expected context canceled from list organisations, got %v", err)
-	}
-	_, err = repo.CreateOrganisation(cancelledCtx, domain.Organisation{
-		Name:         "Canceled Org",
-		HoursPerDay:  8,
-		HoursPerWeek: 40,
-		HoursPerYear: 2080,
-	})
-	if !errors.Is(err, context.Canceled) {
-		t.Fatalf("expected context canceled from create organisation, got %v", err)
-	}
+		_, err = repo.ListOrganisations(cancelledCtx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context canceled from list organisations, got %v", err)
+		}
+		_, err = repo.CreateOrganisation(cancelledCtx, domain.Organisation{
+			Name:         "Canceled Org",
+			HoursPerDay:  8,
+			HoursPerWeek: 40,
+			HoursPerYear: 2080,
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context canceled from create organisation, got %v", err)
+		}
 
-	organisations, err := repo.ListOrganisations(context.Background())
-	if err != nil {
-		t.Fatalf("list organisations after canceled create: %v", err)
-	}
-	if len(organisations) != 0 {
-		t.Fatalf("expected no organisations after canceled create, got %+v", organisations)
-	}
+		organisations, err := repo.ListOrganisations(context.Background())
+		if err != nil {
+			t.Fatalf("list organisations after canceled create: %v", err)
+		}
+		if len(organisations) != 0 {
+			t.Fatalf("expected no organisations after canceled create, got %+v", organisations)
+		}
 
-	created, err := repo.CreateOrganisation(context.Background(), domain.Organisation{
-		Name:         "Active Org",
-		HoursPerDay:  8,
-		HoursPerWeek: 40,
-		HoursPerYear: 2080,
-	})
-	if err != nil {
-		t.Fatalf(errCreateOrganisationFmt, err)
-	}
+		created, err := repo.CreateOrganisation(context.Background(), domain.Organisation{
+			Name:         "Active Org",
+			HoursPerDay:  8,
+			HoursPerWeek: 40,
+			HoursPerYear: 2080,
+		})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
 
-	err = repo.DeleteOrganisation(cancelledCtx, created.ID)
-	if !errors.Is(err, context.Canceled) {
-		t.Fatalf("expected context canceled from delete organisation, got %v", err)
-	}
+		err = repo.DeleteOrganisation(cancelledCtx, created.ID)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context canceled from delete organisation, got %v", err)
+		}
 
-	_, err = repo.GetOrganisation(context.Background(), created.ID)
-	if err != nil {
-		t.Fatalf("expected organisation to remain after canceled delete, got %v", err)
-	}
+		_, err = repo.GetOrganisation(context.Background(), created.ID)
+		if err != nil {
+			t.Fatalf("expected organisation to remain after canceled delete, got %v", err)
+		}
+	})
 }
 
 // TestFileRepositoryCancelledContextAcrossMethods verifies the file repository cancelled context across methods scenario.
 func TestFileRepositoryCancelledContextAcrossMethods(t *testing.T) {
-	repo, err := NewFileRepository(filepath.Join(t.TempDir(), "context-cancel-all-methods.json"))
-	if err != nil {
-		t.Fatalf(errCreateRepositoryFmt, err)
-	}
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		repo, err := open(filepath.Join(t.TempDir(), "context-cancel-all-methods.json"))
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
 
-	backgroundCtx := context.Background()
-	organisation, err := repo.CreateOrganisation(backgroundCtx, domain.Organisation{
-		Name:         "Org",
-		HoursPerDay:  8,
-		HoursPerWeek: 40,
-		HoursPerYear: 2080,
-	})
-	if err != nil {
-		t.Fatalf(errCreateOrganisationFmt, err)
-	}
-	person, err := repo.CreatePerson(backgroundCtx, domain.Person{
-		OrganisationID: organisation.ID,
-		Name:           "Person",
-		EmploymentPct:  100,
+		backgroundCtx := context.Background()
+		organisation, err := repo.CreateOrganisation(backgroundCtx, domain.Organisation{
+			Name:         "Org",
+			HoursPerDay:  8,
+			HoursPerWeek: 40,
+			HoursPerYear: 2080,
+		})
+		if err != nil {
+			t.Fatalf(errCreateOrganisationFmt, err)
+		}
+		person, err := repo.CreatePerson(backgroundCtx, domain.Person{
+			OrganisationID: organisation.ID,
+			Name:           "Person",
+			EmploymentPct:  100,
+		})
+		if err != nil {
+			t.Fatalf("create person: %v", err)
+		}
+		project, err := repo.CreateProject(backgroundCtx, domain.Project{
+			OrganisationID: organisation.ID,
+			Name:           "Project",
+		})
+		if err != nil {
+			t.Fatalf("create project: %v", err)
+		}
+		group, err := repo.CreateGroup(backgroundCtx, domain.Group{
+			OrganisationID: organisation.ID,
+			Name:           "Group",
+			MemberIDs:      []string{person.ID},
+		})
+		if err != nil {
+			t.Fatalf("create group: %v", err)
+		}
+		allocation, err := repo.CreateAllocation(backgroundCtx, domain.Allocation{
+			OrganisationID: organisation.ID,
+			TargetType:     domain.AllocationTargetPerson,
+			TargetID:       person.ID,
+			ProjectID:      project.ID,
+			StartDate:      "2026-01-01",
+			EndDate:        "2026-01-02",
+			Percent:        25,
+		})
+		if err != nil {
+			t.Fatalf("create allocation: %v", err)
+		}
+		holiday, err := repo.CreateOrgHoliday(backgroundCtx, domain.OrgHoliday{
+			OrganisationID: organisation.ID,
+			Date:           "2026-01-01",
+			Hours:          8,
+		})
+		if err != nil {
+			t.Fatalf("create holiday: %v", err)
+		}
+		groupUnavailable, err := repo.CreateGroupUnavailability(backgroundCtx, domain.GroupUnavailability{
+			OrganisationID: organisation.ID,
+			GroupID:        group.ID,
+			Date:           "2026-01-02",
+			Hours:          4,
+		})
+		if err != nil {
+			t.Fatalf("create group unavailability: %v", err)
+		}
+		personUnavailable, err := repo.CreatePersonUnavailability(backgroundCtx, domain.PersonUnavailability{
+			OrganisationID: organisation.ID,
+			PersonID:       person.ID,
+			Date:           "2026-01-03",
+			Hours:          2,
+		})
+		if err != nil {
+			t.Fatalf("create person unavailability: %v", err)
+		}
+
+		cancelledCtx, cancel := context.WithCancel(backgroundCtx)
+		cancel()
+		expectCanceled := func(err error) {
+			t.Helper()
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context canceled error, got %v", err)
+			}
+		}
+
+		_, err = repo.ListOrganisations(cancelledCtx)
+		expectCanceled(err)
+		_, err = repo.GetOrganisation(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+		_, err = repo.CreateOrganisation(cancelledCtx, domain.Organisation{})
+		expectCanceled(err)
+		_, err = repo.UpdateOrganisation(cancelledCtx, organisation)
+		expectCanceled(err)
+		err = repo.DeleteOrganisation(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+
+		_, err = repo.ListPersons(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+		_, err = repo.GetPerson(cancelledCtx, organisation.ID, person.ID)
+		expectCanceled(err)
+		_, err = repo.CreatePerson(cancelledCtx, person)
+		expectCanceled(err)
+		_, err = repo.UpdatePerson(cancelledCtx, person)
+		expectCanceled(err)
+		err = repo.DeletePerson(cancelledCtx, organisation.ID, person.ID, true)
+		expectCanceled(err)
+
+		_, err = repo.ListProjects(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+		_, err = repo.GetProject(cancelledCtx, organisation.ID, project.ID)
+		expectCanceled(err)
+		_, err = repo.CreateProject(cancelledCtx, project)
+		expectCanceled(err)
+		_, err = repo.UpdateProject(cancelledCtx, project)
+		expectCanceled(err)
+		err = repo.DeleteProject(cancelledCtx, organisation.ID, project.ID, true)
+		expectCanceled(err)
+
+		_, err = repo.ListGroups(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+		_, err = repo.GetGroup(cancelledCtx, organisation.ID, group.ID)
+		expectCanceled(err)
+		_, err = repo.CreateGroup(cancelledCtx, group)
+		expectCanceled(err)
+		_, err = repo.UpdateGroup(cancelledCtx, group)
+		expectCanceled(err)
+		err = repo.DeleteGroup(cancelledCtx, organisation.ID, group.ID, true)
+		expectCanceled(err)
+
+		_, err = repo.ListAllocations(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+		_, err = repo.GetAllocation(cancelledCtx, organisation.ID, allocation.ID)
+		expectCanceled(err)
+		_, err = repo.CreateAllocation(cancelledCtx, allocation)
+		expectCanceled(err)
+		_, err = repo.UpdateAllocation(cancelledCtx, allocation)
+		expectCanceled(err)
+		err = repo.DeleteAllocation(cancelledCtx, organisation.ID, allocation.ID)
+		expectCanceled(err)
+
+		_, err = repo.ListOrgHolidays(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+		_, err = repo.CreateOrgHoliday(cancelledCtx, holiday)
+		expectCanceled(err)
+		err = repo.DeleteOrgHoliday(cancelledCtx, organisation.ID, holiday.ID)
+		expectCanceled(err)
+
+		_, err = repo.ListGroupUnavailability(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+		_, err = repo.CreateGroupUnavailability(cancelledCtx, groupUnavailable)
+		expectCanceled(err)
+		err = repo.DeleteGroupUnavailability(cancelledCtx, organisation.ID, groupUnavailable.ID)
+		expectCanceled(err)
+
+		_, err = repo.ListPersonUnavailability(cancelledCtx, organisation.ID)
+		expectCanceled(err)
+		_, err = repo.ListPersonUnavailabilityByPerson(cancelledCtx, organisation.ID, person.ID)
+		expectCanceled(err)
+		_, err = repo.ListPersonUnavailabilityByPersonAndDate(cancelledCtx, organisation.ID, person.ID, personUnavailable.Date)
+		expectCanceled(err)
+		_, err = repo.CreatePersonUnavailability(cancelledCtx, personUnavailable)
+		expectCanceled(err)
+		_, err = repo.CreatePersonUnavailabilityWithDailyLimit(cancelledCtx, personUnavailable, 8)
+		expectCanceled(err)
+		err = repo.DeletePersonUnavailability(cancelledCtx, organisation.ID, personUnavailable.ID)
+		expectCanceled(err)
+		err = repo.DeletePersonUnavailabilityByPerson(cancelledCtx, organisation.ID, person.ID, personUnavailable.ID)
+		expectCanceled(err)
+
+		_, err = repo.PurgeCalendarEntriesBefore(cancelledCtx, organisation.ID, "2027-01-01")
+		expectCanceled(err)
 	})
-	if err != nil {
-		t.Fatalf("create person: %v", err)
-	}
-	project, err := repo.CreateProject(backgroundCtx, domain.Project{
-		OrganisationID: organisation.ID,
-		Name:           "Project",
+}
+
+// TestFileRepositoryImportTenant verifies the file repository import tenant scenario.
+func TestFileRepositoryImportTenant(t *testing.T) {
+	forEachRepositoryBackend(t, func(t *testing.T, open repositoryOpener) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "import-tenant.json")
+		repo, err := open(path)
+		if err != nil {
+			t.Fatalf(errCreateRepositoryFmt, err)
+		}
+
+		snapshot := domain.TenantSnapshot{
+			FormatVersion: domain.TenantSnapshotVersion,
+			Organisation:  testOrganisation("Imported Org"),
+			Persons:       []domain.Person{{ID: "p1", Name: "Ada", EmploymentPct: 100}},
+			Projects:      []domain.Project{{ID: "x1", Name: "Apollo", StartDate: "2026-01-01", EndDate: "2026-12-31"}},
+			Groups:        []domain.Group{{ID: "g1", Name: "Team", MemberIDs: []string{"p1"}}},
+			Allocations: []domain.Allocation{{
+				TargetType: domain.AllocationTargetGroup, TargetID: "g1", ProjectID: "x1",
+				StartDate: "2026-01-01", EndDate: "2026-03-31", Percent: 20,
+			}},
+			Holidays:             []domain.OrgHoliday{{Date: "2026-12-25", Hours: 8, PersonIDs: []string{"p1"}}},
+			PersonUnavailability: []domain.PersonUnavailability{{PersonID: "p1", Date: "2026-02-02", Hours: 4}},
+		}
+		imported, err := repo.ImportTenant(ctx, snapshot)
+		if err != nil {
+			t.Fatalf("import tenant: %v", err)
+		}
+		organisationID := imported.Organisation.ID
+		personID := imported.Persons[0].ID
+		if personID == "p1" || imported.Groups[0].MemberIDs[0] != personID {
+			t.Fatalf("expected remapped person IDs, got %+v", imported)
+		}
+		if imported.Allocations[0].TargetID != imported.Groups[0].ID || imported.Allocations[0].ProjectID != imported.Projects[0].ID {
+			t.Fatalf("expected remapped allocation references, got %+v", imported.Allocations[0])
+		}
+		if imported.Holidays[0].PersonIDs[0] != personID || imported.PersonUnavailability[0].PersonID != personID {
+			t.Fatalf("expected remapped calendar references, got %+v", imported)
+		}
+
+		reopened, err := open(path)
+		if err != nil {
+			t.Fatalf("reopen repository: %v", err)
+		}
+		allocations, err := reopened.ListAllocations(ctx, organisationID)
+		if err != nil || len(allocations) != 1 || allocations[0].Version != 1 {
+			t.Fatalf("expected the imported allocation after reopen, got %+v (%v)", allocations, err)
+		}
+
+		broken := snapshot
+		broken.Organisation = testOrganisation("Broken Org")
+		broken.Groups = []domain.Group{{ID: "g1", Name: "Team", MemberIDs: []string{"missing"}}}
+		if _, err = repo.ImportTenant(ctx, broken); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected not found for an unknown reference, got %v", err)
+		}
+		organisations, err := repo.ListOrganisations(ctx)
+		if err != nil || len(organisations) != 1 {
+			t.Fatalf("expected a failed import to store nothing, got %+v (%v)", organisations, err)
+		}
 	})
+}
+
+// TestFileRepositoryWritesAtomicallyAndReportsLatency verifies the file repository atomic write and write latency scenario.
+func TestFileRepositoryWritesAtomicallyAndReportsLatency(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "atomic.json")
+	repo, err := NewFileRepository(path)
 	if err != nil {
-		t.Fatalf("create project: %v", err)
+		t.Fatalf(errCreateRepositoryFmt, err)
 	}
-	group, err := repo.CreateGroup(backgroundCtx, domain.Group{
-		OrganisationID: organisation.ID,
-		Name:           "Group",
-		MemberIDs:      []string{person.ID},
+	var observed []string
+	repo.SetOperationObserver(func(operation string, duration time.Duration) {
+		if duration < 0 {
+			t.Errorf("expected a non-negative %s duration, got %s", operation, duration)
+		}
+		observed = append(observed, operation)
 	})
-	if err != nil {
-		t.Fatalf("create group: %v", err)
+
+	if _, err = repo.CreateOrganisation(ctx, domain.Organisation{Name: "Atomic Org", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}); err != nil {
+		t.Fatalf(errCreateOrganisationFmt, err)
 	}
-	allocation, err := repo.CreateAllocation(backgroundCtx, domain.Allocation{
-		OrganisationID: organisation.ID,
-		TargetType:     domain.AllocationTargetPerson,
-		TargetID:       person.ID,
-		ProjectID:      project.ID,
-		StartDate:      "2026-01-01",
-		EndDate:        "2026-01-02",
-		Percent:        25,
-	})
-	if err != nil {
-		t.Fatalf("create allocation: %v", err)
+	if len(observed) != 1 || observed[0] != "write" {
+		t.Fatalf("expected one observed write, got %v", observed)
 	}
-	holiday, err := repo.CreateOrgHoliday(backgroundCtx, domain.OrgHoliday{
-		OrganisationID: organisation.ID,
-		Date:           "2026-01-01",
-		Hours:          8,
-	})
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		t.Fatalf("create holiday: %v", err)
+		t.Fatalf("read data directory: %v", err)
 	}
-	groupUnavailable, err := repo.CreateGroupUnavailability(backgroundCtx, domain.GroupUnavailability{
-		OrganisationID: organisation.ID,
-		GroupID:        group.ID,
-		Date:           "2026-01-02",
-		Hours:          4,
-	})
-	if err != nil {
-		t.Fatalf("create group unavailability: %v", err)
+	if len(entries) != 1 || entries[0].Name() != "atomic.json" {
+		t.Fatalf("expected only the data file after the rename, got %v", entries)
 	}
-	personUnavailable, err := repo.CreatePersonUnavailability(backgroundCtx, domain.PersonUnavailability{
-		OrganisationID: organisation.ID,
-		PersonID:       person.ID,
-		Date:           "2026-01-03",
-		Hours:          2,
-	})
-	if err != nil {
-		t.Fatalf("create person unavailability: %v", err)
+	if onDisk := reopenOrganisationCount(t, path); onDisk != 1 {
+		t.Fatalf("expected the organisation on disk, got %d", onDisk)
 	}
-
-	cancelledCtx, cancel := context.WithCancel(backgroundCtx)
-	cancel()
-	expectCanceled := func(err error) {
-		t.Helper()
-		if !errors.Is(err, context.Canceled) {
-			t.Fatalf("expected context canceled error, got %v", err)
-		}
-	}
-
-	_, err = repo.ListOrganisations(cancelledCtx)
-	expectCanceled(err)
-	_, err = repo.GetOrganisation(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-	_, err = repo.CreateOrganisation(cancelledCtx, domain.Organisation{})
-	expectCanceled(err)
-	_, err = repo.UpdateOrganisation(cancelledCtx, organisation)
-	expectCanceled(err)
-	err = repo.DeleteOrganisation(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-
-	_, err = repo.ListPersons(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-	_, err = repo.GetPerson(cancelledCtx, organisation.ID, person.ID)
-	expectCanceled(err)
-	_, err = repo.CreatePerson(cancelledCtx, person)
-	expectCanceled(err)
-	_, err = repo.UpdatePerson(cancelledCtx, person)
-	expectCanceled(err)
-	err = repo.DeletePerson(cancelledCtx, organisation.ID, person.ID)
-	expectCanceled(err)
-
-	_, err = repo.ListProjects(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-	_, err = repo.GetProject(cancelledCtx, organisation.ID, project.ID)
-	expectCanceled(err)
-	_, err = repo.CreateProject(cancelledCtx, project)
-	expectCanceled(err)
-	_, err = repo.UpdateProject(cancelledCtx, project)
-	expectCanceled(err)
-	err = repo.DeleteProject(cancelledCtx, organisation.ID, project.ID)
-	expectCanceled(err)
-
-	_, err = repo.ListGroups(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-	_, err = repo.GetGroup(cancelledCtx, organisation.ID, group.ID)
-	expectCanceled(err)
-	_, err = repo.CreateGroup(cancelledCtx, group)
-	expectCanceled(err)
-	_, err = repo.UpdateGroup(cancelledCtx, group)
-	expectCanceled(err)
-	err = repo.DeleteGroup(cancelledCtx, organisation.ID, group.ID)
-	expectCanceled(err)
-
-	_, err = repo.ListAllocations(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-	_, err = repo.GetAllocation(cancelledCtx, organisation.ID, allocation.ID)
-	expectCanceled(err)
-	_, err = repo.CreateAllocation(cancelledCtx, allocation)
-	expectCanceled(err)
-	_, err = repo.UpdateAllocation(cancelledCtx, allocation)
-	expectCanceled(err)
-	err = repo.DeleteAllocation(cancelledCtx, organisation.ID, allocation.ID)
-	expectCanceled(err)
-
-	_, err = repo.ListOrgHolidays(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-	_, err = repo.CreateOrgHoliday(cancelledCtx, holiday)
-	expectCanceled(err)
-	err = repo.DeleteOrgHoliday(cancelledCtx, organisation.ID, holiday.ID)
-	expectCanceled(err)
-
-	_, err = repo.ListGroupUnavailability(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-	_, err = repo.CreateGroupUnavailability(cancelledCtx, groupUnavailable)
-	expectCanceled(err)
-	err = repo.DeleteGroupUnavailability(cancelledCtx, organisation.ID, groupUnavailable.ID)
-	expectCanceled(err)
-
-	_, err = repo.ListPersonUnavailability(cancelledCtx, organisation.ID)
-	expectCanceled(err)
-	_, err = repo.ListPersonUnavailabilityByPerson(cancelledCtx, organisation.ID, person.ID)
-	expectCanceled(err)
-	_, err = repo.ListPersonUnavailabilityByPersonAndDate(cancelledCtx, organisation.ID, person.ID, personUnavailable.Date)
-	expectCanceled(err)
-	_, err = repo.CreatePersonUnavailability(cancelledCtx, personUnavailable)
-	expectCanceled(err)
-	_, err = repo.CreatePersonUnavailabilityWithDailyLimit(cancelledCtx, personUnavailable, 8)
-	expectCanceled(err)
-	err = repo.DeletePersonUnavailability(cancelledCtx, organisation.ID, personUnavailable.ID)
-	expectCanceled(err)
-	err = repo.DeletePersonUnavailabilityByPerson(cancelledCtx, organisation.ID, person.ID, personUnavailable.ID)
-	expectCanceled(err)
 }
@@ -0,0 +1,158 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// ListUnavailabilityRules returns the recurring unavailability rules of one
+// organisation ordered by id.
+func (r *FileRepository) ListUnavailabilityRules(ctx context.Context, organisationID string) ([]domain.UnavailabilityRule, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.UnavailabilityRule, 0)
+	for _, rule := range r.state.UnavailabilityRules {
+		if rule.OrganisationID == organisationID {
+			result = append(result, copyUnavailabilityRule(rule))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessStoredID(result[i].ID, result[j].ID)
+	})
+	return result, nil
+}
+
+// GetUnavailabilityRule returns one recurring unavailability rule.
+func (r *FileRepository) GetUnavailabilityRule(ctx context.Context, organisationID, id string) (domain.UnavailabilityRule, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, ok := r.state.UnavailabilityRules[id]
+	if !ok || rule.OrganisationID != organisationID {
+		return domain.UnavailabilityRule{}, domain.ErrNotFound
+	}
+	return copyUnavailabilityRule(rule), nil
+}
+
+// CreateUnavailabilityRule stores a new recurring unavailability rule.
+func (r *FileRepository) CreateUnavailabilityRule(ctx context.Context, rule domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, rule.OrganisationID); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.Organisations[rule.OrganisationID]; !ok {
+		return domain.UnavailabilityRule{}, domain.ErrNotFound
+	}
+	now := time.Now().UTC()
+	rule = copyUnavailabilityRule(rule)
+	rule.ID = r.nextIDLocked(unavailabilityRuleIDPrefix)
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	rule.Version = 1
+	r.state.UnavailabilityRules[rule.ID] = rule
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+
+	return copyUnavailabilityRule(rule), nil
+}
+
+// UpdateUnavailabilityRule replaces a recurring unavailability rule. The
+// person or group it targets never changes.
+func (r *FileRepository) UpdateUnavailabilityRule(ctx context.Context, rule domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, rule.OrganisationID); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.state.UnavailabilityRules[rule.ID]
+	if !ok || current.OrganisationID != rule.OrganisationID {
+		return domain.UnavailabilityRule{}, domain.ErrNotFound
+	}
+	version, err := nextVersion(rule.Version, current.Version)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	rule = copyUnavailabilityRule(rule)
+	rule.PersonID = current.PersonID
+	rule.GroupID = current.GroupID
+	rule.Version = version
+	rule.CreatedAt = current.CreatedAt
+	rule.UpdatedAt = time.Now().UTC()
+	r.state.UnavailabilityRules[rule.ID] = rule
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+
+	return copyUnavailabilityRule(rule), nil
+}
+
+// DeleteUnavailabilityRule removes a recurring unavailability rule.
+func (r *FileRepository) DeleteUnavailabilityRule(ctx context.Context, organisationID, id string) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.state.UnavailabilityRules[id]
+	if !ok || rule.OrganisationID != organisationID {
+		return domain.ErrNotFound
+	}
+	delete(r.state.UnavailabilityRules, id)
+
+	return r.persistLockedWithContext(ctx)
+}
+
+// deleteUnavailabilityRulesByTargetLocked removes the rules of a person or,
+// with an empty personID, of a group.
+func (r *FileRepository) deleteUnavailabilityRulesByTargetLocked(organisationID, personID, groupID string) {
+	for ruleID, rule := range r.state.UnavailabilityRules {
+		if rule.OrganisationID == organisationID && rule.PersonID == personID && rule.GroupID == groupID {
+			delete(r.state.UnavailabilityRules, ruleID)
+		}
+	}
+}
+
+func (r *FileRepository) deleteUnavailabilityRulesByOrganisationLocked(organisationID string) {
+	for ruleID, rule := range r.state.UnavailabilityRules {
+		if rule.OrganisationID == organisationID {
+			delete(r.state.UnavailabilityRules, ruleID)
+		}
+	}
+}
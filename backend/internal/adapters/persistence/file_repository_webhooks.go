@@ -0,0 +1,202 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+// maxWebhookDeliveries is the number of delivery outcomes kept per webhook.
+// Older outcomes are dropped when new ones are appended.
+const maxWebhookDeliveries = 100
+
+// ListWebhooks returns one organisation's webhooks ordered by id.
+func (r *FileRepository) ListWebhooks(ctx context.Context, organisationID string) ([]domain.Webhook, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]domain.Webhook, 0)
+	for _, webhook := range r.state.Webhooks {
+		if webhook.OrganisationID == organisationID {
+			result = append(result, copyWebhook(webhook))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessStoredID(result[i].ID, result[j].ID)
+	})
+	return result, nil
+}
+
+// GetWebhook returns a webhook by organisation and id.
+func (r *FileRepository) GetWebhook(ctx context.Context, organisationID, id string) (domain.Webhook, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.Webhook{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.state.Webhooks[id]
+	if !ok || webhook.OrganisationID != organisationID {
+		return domain.Webhook{}, domain.ErrNotFound
+	}
+	return copyWebhook(webhook), nil
+}
+
+// CreateWebhook stores a new webhook.
+func (r *FileRepository) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.Webhook{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, webhook.OrganisationID); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.Organisations[webhook.OrganisationID]; !ok {
+		return domain.Webhook{}, domain.ErrNotFound
+	}
+	now := time.Now().UTC()
+	webhook = copyWebhook(webhook)
+	webhook.ID = r.nextIDLocked(webhookIDPrefix)
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	webhook.Version = 1
+	r.state.Webhooks[webhook.ID] = webhook
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+// DeleteWebhook removes a webhook together with its delivery log.
+func (r *FileRepository) DeleteWebhook(ctx context.Context, organisationID, id string) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.state.Webhooks[id]
+	if !ok || webhook.OrganisationID != organisationID {
+		return domain.ErrNotFound
+	}
+	delete(r.state.Webhooks, id)
+	for deliveryID, delivery := range r.state.WebhookDeliveries {
+		if delivery.WebhookID == id {
+			delete(r.state.WebhookDeliveries, deliveryID)
+		}
+	}
+	return r.persistLockedWithContext(ctx)
+}
+
+// AppendWebhookDelivery stores the outcome of one delivery and drops the
+// oldest outcomes of the same webhook beyond maxWebhookDeliveries.
+func (r *FileRepository) AppendWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	if err := contextErr(ctx); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if err := r.ensureShardLoaded(ctx, delivery.OrganisationID); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.state.Webhooks[delivery.WebhookID]
+	if !ok || webhook.OrganisationID != delivery.OrganisationID {
+		return domain.WebhookDelivery{}, domain.ErrNotFound
+	}
+	delivery.ID = r.nextIDLocked(webhookDeliveryIDPrefix)
+	r.state.WebhookDeliveries[delivery.ID] = delivery
+
+	deliveries := r.webhookDeliveriesLocked(delivery.OrganisationID, delivery.WebhookID)
+	for len(deliveries) > maxWebhookDeliveries {
+		delete(r.state.WebhookDeliveries, deliveries[0].ID)
+		deliveries = deliveries[1:]
+	}
+
+	if err := r.persistLockedWithContext(ctx); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// ListWebhookDeliveries returns the delivery outcomes of one webhook, oldest
+// first.
+func (r *FileRepository) ListWebhookDeliveries(ctx context.Context, organisationID, webhookID string) ([]domain.WebhookDelivery, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+	if err := r.ensureShardLoaded(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.state.Webhooks[webhookID]
+	if !ok || webhook.OrganisationID != organisationID {
+		return nil, domain.ErrNotFound
+	}
+	return r.webhookDeliveriesLocked(organisationID, webhookID), nil
+}
+
+func (r *FileRepository) webhookDeliveriesLocked(organisationID, webhookID string) []domain.WebhookDelivery {
+	result := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range r.state.WebhookDeliveries {
+		if delivery.OrganisationID == organisationID && delivery.WebhookID == webhookID {
+			result = append(result, delivery)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].At.Equal(result[j].At) {
+			return result[i].At.Before(result[j].At)
+		}
+		return lessStoredID(result[i].ID, result[j].ID)
+	})
+	return result
+}
+
+func (r *FileRepository) deleteWebhooksByOrganisationLocked(organisationID string) {
+	for webhookID, webhook := range r.state.Webhooks {
+		if webhook.OrganisationID == organisationID {
+			delete(r.state.Webhooks, webhookID)
+		}
+	}
+	for deliveryID, delivery := range r.state.WebhookDeliveries {
+		if delivery.OrganisationID == organisationID {
+			delete(r.state.WebhookDeliveries, deliveryID)
+		}
+	}
+}
+
+// lessStoredID orders ids by the order they were stored in, which the
+// numeric suffix reflects.
+func lessStoredID(left, right string) bool {
+	if len(left) != len(right) {
+		return len(left) < len(right)
+	}
+	return left < right
+}
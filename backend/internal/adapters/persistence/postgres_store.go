@@ -0,0 +1,78 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	// Registers the pgx driver with database/sql.
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// postgresMigrationLock is the advisory lock key that serialises schema
+// migrations when several backends start at once.
+const postgresMigrationLock = 0x706c61746f
+
+var postgresDialect = sqlDialect{
+	migrations: []string{
+		`CREATE TABLE plato_state_meta (
+			id SMALLINT PRIMARY KEY CHECK (id = 1),
+			sequence BIGINT NOT NULL
+		);
+		INSERT INTO plato_state_meta (id, sequence) VALUES (1, 0);
+		CREATE TABLE plato_tenants (
+			organisation_id TEXT PRIMARY KEY,
+			revision BIGINT NOT NULL,
+			log_revision BIGINT NOT NULL
+		);
+		CREATE TABLE plato_records (
+			kind TEXT NOT NULL,
+			id TEXT NOT NULL,
+			organisation_id TEXT NOT NULL,
+			body JSONB NOT NULL,
+			PRIMARY KEY (kind, id)
+		);
+		CREATE INDEX plato_records_organisation_idx ON plato_records (organisation_id);`,
+	},
+	lockMigrations: fmt.Sprintf(`SELECT pg_advisory_xact_lock(%d)`, postgresMigrationLock),
+	createMigrationTable: `CREATE TABLE IF NOT EXISTS plato_schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	recordMigration:      `INSERT INTO plato_schema_migrations (version) VALUES ($1)`,
+	loadTxOptions:        &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true},
+	selectTenant:         `SELECT revision, log_revision FROM plato_tenants WHERE organisation_id = $1`,
+	selectTenantForWrite: `SELECT revision, log_revision FROM plato_tenants WHERE organisation_id = $1 FOR UPDATE`,
+	selectTenantRecords:  `SELECT kind, id, body FROM plato_records WHERE organisation_id = $1`,
+	insertTenant: `INSERT INTO plato_tenants (organisation_id, revision, log_revision) VALUES ($1, $2, $3)
+		ON CONFLICT (organisation_id) DO NOTHING`,
+	updateTenant: `UPDATE plato_tenants SET revision = $1, log_revision = $2 WHERE organisation_id = $3`,
+	deleteRecord: `DELETE FROM plato_records WHERE kind = $1 AND id = $2`,
+	insertRecord: `INSERT INTO plato_records (kind, id, organisation_id, body) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (kind, id) DO NOTHING`,
+	upsertRecord: `INSERT INTO plato_records (kind, id, organisation_id, body) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (kind, id) DO UPDATE SET organisation_id = EXCLUDED.organisation_id, body = EXCLUDED.body`,
+	updateSequence: `UPDATE plato_state_meta SET sequence = GREATEST(sequence, $1) WHERE id = 1`,
+}
+
+// NewPostgresRepository returns a repository that keeps its state in the
+// PostgreSQL database at databaseURL. Pending schema migrations run first.
+// Every write is one transaction that only touches changed records and fails
+// with domain.ErrConflict when another backend saved records of the same
+// organisation in the meantime. Reads first pick up the organisations other
+// backends changed, so a retry sees the other change.
+func NewPostgresRepository(ctx context.Context, databaseURL string) (*FileRepository, error) {
+	if databaseURL == "" {
+		return nil, errors.New("database URL is required")
+	}
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return openSQLRepository(ctx, &sqlStore{db: db, dialect: postgresDialect})
+}
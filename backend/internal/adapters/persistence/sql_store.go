@@ -0,0 +1,367 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// sqlDialect holds the statements a database needs to serve as a state store.
+type sqlDialect struct {
+	// migrations are applied in order. Append new steps and never edit one
+	// that has shipped.
+	migrations []string
+	// lockMigrations serialises migrations across processes. It stays empty
+	// when every write transaction already holds the database lock.
+	lockMigrations       string
+	createMigrationTable string
+	recordMigration      string
+	loadTxOptions        *sql.TxOptions
+	selectTenant         string
+	selectTenantForWrite string
+	selectTenantRecords  string
+	insertTenant         string
+	updateTenant         string
+	deleteRecord         string
+	insertRecord         string
+	upsertRecord         string
+	updateSequence       string
+}
+
+const (
+	recordKindOrganisation         = "organisation"
+	recordKindPerson               = "person"
+	recordKindProject              = "project"
+	recordKindGroup                = "group"
+	recordKindAllocation           = "allocation"
+	recordKindOrgHoliday           = "org_holiday"
+	recordKindGroupUnavailability  = "group_unavailability"
+	recordKindPersonUnavailability = "person_unavailability"
+	recordKindUnavailabilityRule   = "unavailability_rule"
+	recordKindAllocationTemplate   = "allocation_template"
+	recordKindScenario             = "scenario"
+	recordKindAllocationEvent      = "allocation_event"
+	recordKindWebhook              = "webhook"
+	recordKindWebhookDelivery      = "webhook_delivery"
+	recordKindAPIKey               = "api_key"
+)
+
+type recordKey struct {
+	kind string
+	id   string
+}
+
+type storedRecord struct {
+	organisationID string
+	body           []byte
+}
+
+// sqlStore keeps the repository state in a SQL database, one row per record
+// plus one revision row per organisation and a meta row holding the id
+// sequence.
+type sqlStore struct {
+	db      *sql.DB
+	dialect sqlDialect
+}
+
+func (s *sqlStore) close() error {
+	return s.db.Close()
+}
+
+func (s *sqlStore) migrate(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if s.dialect.lockMigrations != "" {
+		if _, err = tx.ExecContext(ctx, s.dialect.lockMigrations); err != nil {
+			return fmt.Errorf("lock migrations: %w", err)
+		}
+	}
+	if _, err = tx.ExecContext(ctx, s.dialect.createMigrationTable); err != nil {
+		return fmt.Errorf("create migration table: %w", err)
+	}
+
+	var applied int
+	if err = tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM plato_schema_migrations`).Scan(&applied); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	migrations := s.dialect.migrations
+	if applied > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this backend supports (%d)", applied, len(migrations))
+	}
+	for version := applied + 1; version <= len(migrations); version++ {
+		if _, err = tx.ExecContext(ctx, migrations[version-1]); err != nil {
+			return fmt.Errorf("apply migration %d: %w", version, err)
+		}
+		if _, err = tx.ExecContext(ctx, s.dialect.recordMigration, version); err != nil {
+			return fmt.Errorf("record migration %d: %w", version, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStore) load(ctx context.Context) (fileState, map[string]tenantRevision, error) {
+	tx, err := s.db.BeginTx(ctx, s.dialect.loadTxOptions)
+	if err != nil {
+		return fileState{}, nil, fmt.Errorf("begin load: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	revisions, sequence, err := readRevisions(ctx, tx)
+	if err != nil {
+		return fileState{}, nil, err
+	}
+	state := emptyFileState()
+	state.Sequence = sequence
+	rows, err := tx.QueryContext(ctx, `SELECT kind, id, body FROM plato_records`)
+	if err != nil {
+		return fileState{}, nil, fmt.Errorf("read records: %w", err)
+	}
+	if err = decodeRecords(&state, rows); err != nil {
+		return fileState{}, nil, err
+	}
+	return state, revisions, nil
+}
+
+func (s *sqlStore) revisions(ctx context.Context) (map[string]tenantRevision, int64, error) {
+	tx, err := s.db.BeginTx(ctx, s.dialect.loadTxOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("begin revision read: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	return readRevisions(ctx, tx)
+}
+
+func (s *sqlStore) loadTenants(ctx context.Context, organisationIDs []string) (fileState, map[string]tenantRevision, error) {
+	tx, err := s.db.BeginTx(ctx, s.dialect.loadTxOptions)
+	if err != nil {
+		return fileState{}, nil, fmt.Errorf("begin load: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	state := emptyFileState()
+	revisions := map[string]tenantRevision{}
+	for _, organisationID := range organisationIDs {
+		revision, _, err := s.readTenant(ctx, tx, s.dialect.selectTenant, organisationID)
+		if err != nil {
+			return fileState{}, nil, err
+		}
+		revisions[organisationID] = revision
+
+		rows, err := tx.QueryContext(ctx, s.dialect.selectTenantRecords, organisationID)
+		if err != nil {
+			return fileState{}, nil, fmt.Errorf("read records of %s: %w", organisationID, err)
+		}
+		if err = decodeRecords(&state, rows); err != nil {
+			return fileState{}, nil, err
+		}
+	}
+	return state, revisions, nil
+}
+
+func (s *sqlStore) save(ctx context.Context, changes storeChanges, known map[string]tenantRevision) (map[string]tenantRevision, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin save: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	organisationIDs, logOnly := changes.tenants()
+	saved := make(map[string]tenantRevision, len(organisationIDs))
+	for _, organisationID := range organisationIDs {
+		stored, exists, err := s.readTenant(ctx, tx, s.dialect.selectTenantForWrite, organisationID)
+		if err != nil {
+			return nil, err
+		}
+		next, err := nextTenantRevision(stored, known[organisationID], logOnly[organisationID])
+		if err != nil {
+			return nil, err
+		}
+		if err = s.writeTenant(ctx, tx, organisationID, exists, next); err != nil {
+			return nil, err
+		}
+		saved[organisationID] = seenRevision(known[organisationID], stored, next)
+	}
+
+	for key := range changes.deletes {
+		if _, err = tx.ExecContext(ctx, s.dialect.deleteRecord, key.kind, key.id); err != nil {
+			return nil, fmt.Errorf("delete %s %s: %w", key.kind, key.id, err)
+		}
+	}
+	for key, record := range changes.updates {
+		if _, err = tx.ExecContext(ctx, s.dialect.upsertRecord, key.kind, key.id, record.organisationID, record.body); err != nil {
+			return nil, fmt.Errorf("store %s %s: %w", key.kind, key.id, err)
+		}
+	}
+	for key, record := range changes.inserts {
+		result, err := tx.ExecContext(ctx, s.dialect.insertRecord, key.kind, key.id, record.organisationID, record.body)
+		if err != nil {
+			return nil, fmt.Errorf("store %s %s: %w", key.kind, key.id, err)
+		}
+		// Another backend used the same id from a sequence this one had
+		// not read yet.
+		if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+			return nil, errors.Join(errStaleState, err)
+		}
+	}
+	if changes.sequence != 0 {
+		if _, err = tx.ExecContext(ctx, s.dialect.updateSequence, changes.sequence); err != nil {
+			return nil, fmt.Errorf("update id sequence: %w", err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit save: %w", err)
+	}
+	return saved, nil
+}
+
+// readTenant reads the revision of one organisation and whether its row
+// exists. An organisation without a row has never been saved and reads as
+// the zero revision.
+func (s *sqlStore) readTenant(ctx context.Context, tx *sql.Tx, query, organisationID string) (tenantRevision, bool, error) {
+	var revision tenantRevision
+	err := tx.QueryRowContext(ctx, query, organisationID).Scan(&revision.revision, &revision.logRevision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tenantRevision{}, false, nil
+	}
+	if err != nil {
+		return tenantRevision{}, false, fmt.Errorf("read revision of %s: %w", organisationID, err)
+	}
+	return revision, true, nil
+}
+
+func (s *sqlStore) writeTenant(ctx context.Context, tx *sql.Tx, organisationID string, exists bool, next tenantRevision) error {
+	if exists {
+		if _, err := tx.ExecContext(ctx, s.dialect.updateTenant, next.revision, next.logRevision, organisationID); err != nil {
+			return fmt.Errorf("update revision of %s: %w", organisationID, err)
+		}
+		return nil
+	}
+	result, err := tx.ExecContext(ctx, s.dialect.insertTenant, organisationID, next.revision, next.logRevision)
+	if err != nil {
+		return fmt.Errorf("store revision of %s: %w", organisationID, err)
+	}
+	// A missing row cannot be locked, so two first saves of one organisation
+	// race on the insert and the loser is stale.
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+		return errors.Join(errStaleState, err)
+	}
+	return nil
+}
+
+func readRevisions(ctx context.Context, tx *sql.Tx) (map[string]tenantRevision, int64, error) {
+	var sequence int64
+	if err := tx.QueryRowContext(ctx, `SELECT sequence FROM plato_state_meta WHERE id = 1`).Scan(&sequence); err != nil {
+		return nil, 0, fmt.Errorf("read id sequence: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT organisation_id, revision, log_revision FROM plato_tenants`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read revisions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	revisions := map[string]tenantRevision{}
+	for rows.Next() {
+		var organisationID string
+		var revision tenantRevision
+		if err = rows.Scan(&organisationID, &revision.revision, &revision.logRevision); err != nil {
+			return nil, 0, fmt.Errorf("read revision: %w", err)
+		}
+		revisions[organisationID] = revision
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("read revisions: %w", err)
+	}
+	return revisions, sequence, nil
+}
+
+// decodeRecords adds every row of kind, id, and body to state and closes rows.
+func decodeRecords(state *fileState, rows *sql.Rows) error {
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var kind, id string
+		var body []byte
+		if err := rows.Scan(&kind, &id, &body); err != nil {
+			return fmt.Errorf("read record: %w", err)
+		}
+		if err := decodeRecord(state, kind, id, body); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read records: %w", err)
+	}
+	return nil
+}
+
+func decodeRecord(state *fileState, kind, id string, body []byte) error {
+	var err error
+	switch kind {
+	case recordKindOrganisation:
+		err = decodeInto(state.Organisations, id, body)
+	case recordKindPerson:
+		err = decodeInto(state.Persons, id, body)
+	case recordKindProject:
+		err = decodeInto(state.Projects, id, body)
+	case recordKindGroup:
+		err = decodeInto(state.Groups, id, body)
+	case recordKindAllocation:
+		err = decodeInto(state.Allocations, id, body)
+	case recordKindOrgHoliday:
+		err = decodeInto(state.OrgHolidays, id, body)
+	case recordKindGroupUnavailability:
+		err = decodeInto(state.GroupUnavailability, id, body)
+	case recordKindPersonUnavailability:
+		err = decodeInto(state.PersonUnavailability, id, body)
+	case recordKindUnavailabilityRule:
+		err = decodeInto(state.UnavailabilityRules, id, body)
+	case recordKindAllocationTemplate:
+		err = decodeInto(state.AllocationTemplates, id, body)
+	case recordKindScenario:
+		err = decodeInto(state.Scenarios, id, body)
+	case recordKindAllocationEvent:
+		err = decodeInto(state.AllocationEvents, id, body)
+	case recordKindWebhook:
+		err = decodeInto(state.Webhooks, id, body)
+	case recordKindWebhookDelivery:
+		err = decodeInto(state.WebhookDeliveries, id, body)
+	case recordKindAPIKey:
+		err = decodeInto(state.APIKeys, id, body)
+	default:
+		return fmt.Errorf("unknown record kind %q for %s", kind, id)
+	}
+	if err != nil {
+		return fmt.Errorf("decode %s %s: %w", kind, id, err)
+	}
+	return nil
+}
+
+func decodeInto[T any](target map[string]T, id string, body []byte) error {
+	var value T
+	if err := json.Unmarshal(body, &value); err != nil {
+		return err
+	}
+	target[id] = value
+	return nil
+}
+
+// openSQLRepository migrates the store's schema and loads the repository
+// from it. The store is closed when either step fails.
+func openSQLRepository(ctx context.Context, store *sqlStore) (*FileRepository, error) {
+	if err := store.migrate(ctx); err != nil {
+		_ = store.close()
+		return nil, err
+	}
+	repo, err := newStoreRepository(ctx, store)
+	if err != nil {
+		_ = store.close()
+		return nil, err
+	}
+	return repo, nil
+}
@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	// Registers the pure Go SQLite driver with database/sql.
+	_ "modernc.org/sqlite"
+)
+
+// sqliteBusyTimeoutMillis is how long a write waits for another connection's
+// lock before SQLite reports the database as busy.
+const sqliteBusyTimeoutMillis = 5000
+
+// sqliteDialect needs no migration lock or FOR UPDATE because every write
+// transaction starts IMMEDIATE and so holds the database write lock.
+var sqliteDialect = sqlDialect{
+	migrations: []string{
+		`CREATE TABLE plato_state_meta (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			sequence INTEGER NOT NULL
+		);
+		INSERT INTO plato_state_meta (id, sequence) VALUES (1, 0);
+		CREATE TABLE plato_tenants (
+			organisation_id TEXT PRIMARY KEY,
+			revision INTEGER NOT NULL,
+			log_revision INTEGER NOT NULL
+		);
+		CREATE TABLE plato_records (
+			kind TEXT NOT NULL,
+			id TEXT NOT NULL,
+			organisation_id TEXT NOT NULL,
+			body BLOB NOT NULL,
+			PRIMARY KEY (kind, id)
+		);
+		CREATE INDEX plato_records_organisation_idx ON plato_records (organisation_id);`,
+	},
+	createMigrationTable: `CREATE TABLE IF NOT EXISTS plato_schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`,
+	recordMigration:      `INSERT INTO plato_schema_migrations (version) VALUES (?)`,
+	selectTenant:         `SELECT revision, log_revision FROM plato_tenants WHERE organisation_id = ?`,
+	selectTenantForWrite: `SELECT revision, log_revision FROM plato_tenants WHERE organisation_id = ?`,
+	selectTenantRecords:  `SELECT kind, id, body FROM plato_records WHERE organisation_id = ?`,
+	insertTenant: `INSERT INTO plato_tenants (organisation_id, revision, log_revision) VALUES (?, ?, ?)
+		ON CONFLICT (organisation_id) DO NOTHING`,
+	updateTenant: `UPDATE plato_tenants SET revision = ?, log_revision = ? WHERE organisation_id = ?`,
+	deleteRecord: `DELETE FROM plato_records WHERE kind = ? AND id = ?`,
+	insertRecord: `INSERT INTO plato_records (kind, id, organisation_id, body) VALUES (?, ?, ?, ?)
+		ON CONFLICT (kind, id) DO NOTHING`,
+	upsertRecord: `INSERT INTO plato_records (kind, id, organisation_id, body) VALUES (?, ?, ?, ?)
+		ON CONFLICT (kind, id) DO UPDATE SET organisation_id = excluded.organisation_id, body = excluded.body`,
+	updateSequence: `UPDATE plato_state_meta SET sequence = MAX(sequence, ?) WHERE id = 1`,
+}
+
+// NewSQLiteRepository returns a repository that keeps its state in the SQLite
+// database file at path, creating the file and its schema when missing. The
+// database runs in WAL mode so reads do not block the single writer. Writes
+// only touch changed records, and a second process writing the same
+// organisation gets domain.ErrConflict like the PostgreSQL backend.
+func NewSQLiteRepository(ctx context.Context, path string) (*FileRepository, error) {
+	if path == "" {
+		return nil, errors.New("database path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create database directory: %w", err)
+	}
+
+	query := url.Values{}
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", sqliteBusyTimeoutMillis))
+	query.Set("_txlock", "immediate")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	// One connection keeps the pragmas and the write lock in one place.
+	db.SetMaxOpenConns(1)
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+	return openSQLRepository(ctx, &sqlStore{db: db, dialect: sqliteDialect})
+}
@@ -0,0 +1,30 @@
+package telemetry
+
+import (
+	"errors"
+
+	"plato/backend/internal/ports"
+)
+
+// Fanout passes every event to each of its adapters in order.
+type Fanout []ports.Telemetry
+
+var _ ports.Telemetry = Fanout(nil)
+
+// Record passes the event to every adapter.
+func (f Fanout) Record(name string, attributes map[string]string) {
+	for _, adapter := range f {
+		adapter.Record(name, attributes)
+	}
+}
+
+// Close closes every adapter that holds resources and joins their errors.
+func (f Fanout) Close() error {
+	var err error
+	for _, adapter := range f {
+		if closer, ok := adapter.(interface{ Close() error }); ok {
+			err = errors.Join(err, closer.Close())
+		}
+	}
+	return err
+}
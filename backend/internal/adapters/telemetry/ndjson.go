@@ -0,0 +1,136 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"plato/backend/internal/ports"
+)
+
+// ndjsonFileMode keeps the event stream readable by its owner only.
+const ndjsonFileMode = 0o600
+
+// ndjsonEvent is one line of the NDJSON event stream.
+type ndjsonEvent struct {
+	Time       time.Time         `json:"time"`
+	Name       string            `json:"name"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// NDJSONTelemetry appends each event as one JSON line to a file. When the
+// file would grow past maxBytes it is renamed with a ".1" suffix, replacing
+// any earlier rotated file, and a new file is started. It is safe for
+// concurrent use.
+type NDJSONTelemetry struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	file     *os.File
+	size     int64
+	now      func() time.Time
+	logf     func(format string, args ...any)
+}
+
+var _ ports.Telemetry = (*NDJSONTelemetry)(nil)
+
+// NewNDJSONTelemetry opens path for appending. A maxBytes of zero or less
+// never rotates the file.
+func NewNDJSONTelemetry(path string, maxBytes int64) (*NDJSONTelemetry, error) {
+	if path == "" {
+		return nil, errors.New("new ndjson telemetry: path is empty")
+	}
+	adapter := &NDJSONTelemetry{
+		path:     path,
+		maxBytes: max(maxBytes, 0),
+		now:      time.Now,
+		logf:     log.Printf,
+	}
+	if err := adapter.openLocked(); err != nil {
+		return nil, err
+	}
+	return adapter, nil
+}
+
+// Record appends the event to the stream. Write failures are logged and
+// dropped so telemetry never fails a business operation.
+func (n *NDJSONTelemetry) Record(name string, attributes map[string]string) {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	line, err := json.Marshal(ndjsonEvent{Time: n.now().UTC(), Name: name, Attributes: attributes})
+	if err != nil {
+		n.logf("telemetry encode %q failed: %v", name, err)
+		return
+	}
+	line = append(line, '\n')
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.writeLocked(line); err != nil {
+		n.logf("telemetry write %q failed: %v", name, err)
+	}
+}
+
+// Close closes the underlying file. Later events are dropped.
+func (n *NDJSONTelemetry) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.file == nil {
+		return nil
+	}
+	err := n.file.Close()
+	n.file = nil
+	return err
+}
+
+func (n *NDJSONTelemetry) writeLocked(line []byte) error {
+	if n.file == nil {
+		return errors.New("telemetry stream is closed")
+	}
+	if n.maxBytes > 0 && n.size > 0 && n.size+int64(len(line)) > n.maxBytes {
+		if err := n.rotateLocked(); err != nil {
+			return err
+		}
+	}
+	written, err := n.file.Write(line)
+	n.size += int64(written)
+	return err
+}
+
+func (n *NDJSONTelemetry) rotateLocked() error {
+	if err := n.file.Close(); err != nil {
+		return fmt.Errorf("close telemetry stream: %w", err)
+	}
+	n.file = nil
+	renameErr := os.Rename(n.path, n.path+".1")
+	// Reopen even when the rename failed so later events still land somewhere.
+	if err := n.openLocked(); err != nil {
+		return errors.Join(renameErr, err)
+	}
+	if renameErr != nil {
+		return fmt.Errorf("rotate telemetry stream: %w", renameErr)
+	}
+	return nil
+}
+
+func (n *NDJSONTelemetry) openLocked() error {
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, ndjsonFileMode)
+	if err != nil {
+		return fmt.Errorf("open telemetry stream %q: %w", n.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat telemetry stream %q: %w", n.path, err)
+	}
+	n.file = file
+	n.size = info.Size()
+	return nil
+}
@@ -0,0 +1,141 @@
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestNDJSONTelemetryRecord verifies the NDJSON telemetry record scenario.
+func TestNDJSONTelemetryRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	adapter, err := NewNDJSONTelemetry(path, 0)
+	if err != nil {
+		t.Fatalf("create adapter: %v", err)
+	}
+
+	adapter.Record("person.created", map[string]string{"person_id": "person_1"})
+	adapter.Record("allocation.deleted", map[string]string{"allocation_id": "allocation_1"})
+	adapter.Record("organisation.created", nil)
+	if err := adapter.Close(); err != nil {
+		t.Fatalf("close adapter: %v", err)
+	}
+
+	events := readNDJSONEvents(t, path)
+	want := []ndjsonEvent{
+		{Name: "person.created", Attributes: map[string]string{"person_id": "person_1"}},
+		{Name: "allocation.deleted", Attributes: map[string]string{"allocation_id": "allocation_1"}},
+		{Name: "organisation.created", Attributes: map[string]string{}},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d lines, got %d", len(want), len(events))
+	}
+	for idx, event := range events {
+		if event.Time.IsZero() {
+			t.Fatalf("expected line %d to carry a time", idx)
+		}
+		if event.Name != want[idx].Name || !reflect.DeepEqual(event.Attributes, want[idx].Attributes) {
+			t.Fatalf("line %d: expected %+v, got %+v", idx, want[idx], event)
+		}
+	}
+}
+
+// TestNDJSONTelemetryConcurrentRecord verifies the NDJSON telemetry concurrent record scenario.
+func TestNDJSONTelemetryConcurrentRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	adapter, err := NewNDJSONTelemetry(path, 0)
+	if err != nil {
+		t.Fatalf("create adapter: %v", err)
+	}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for idx := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			adapter.Record("event", map[string]string{"index": fmt.Sprint(idx)})
+		}()
+	}
+	wg.Wait()
+	if err := adapter.Close(); err != nil {
+		t.Fatalf("close adapter: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, event := range readNDJSONEvents(t, path) {
+		seen[event.Attributes["index"]] = true
+	}
+	if len(seen) != writers {
+		t.Fatalf("expected %d distinct intact lines, got %d", writers, len(seen))
+	}
+}
+
+// TestNDJSONTelemetryRotatesBySize verifies the NDJSON telemetry rotates by size scenario.
+func TestNDJSONTelemetryRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	adapter, err := NewNDJSONTelemetry(path, 120)
+	if err != nil {
+		t.Fatalf("create adapter: %v", err)
+	}
+
+	for idx := range 3 {
+		adapter.Record("event", map[string]string{"index": fmt.Sprint(idx)})
+	}
+	if err := adapter.Close(); err != nil {
+		t.Fatalf("close adapter: %v", err)
+	}
+
+	// Each line is longer than half the limit, so every event starts a new
+	// file and only the two newest events survive.
+	current := readNDJSONEvents(t, path)
+	rotated := readNDJSONEvents(t, path+".1")
+	if len(current) != 1 || current[0].Attributes["index"] != "2" {
+		t.Fatalf("expected only the newest event in the current file, got %+v", current)
+	}
+	if len(rotated) != 1 || rotated[0].Attributes["index"] != "1" {
+		t.Fatalf("expected the previous event in the rotated file, got %+v", rotated)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat current file: %v", err)
+	}
+	if info.Size() > 120 {
+		t.Fatalf("expected current file within the size limit, got %d bytes", info.Size())
+	}
+}
+
+// TestNewNDJSONTelemetryRejectsEmptyPath verifies the new NDJSON telemetry rejects empty path scenario.
+func TestNewNDJSONTelemetryRejectsEmptyPath(t *testing.T) {
+	if _, err := NewNDJSONTelemetry("", 0); err == nil {
+		t.Fatal("expected an empty path to be rejected")
+	}
+}
+
+func readNDJSONEvents(t *testing.T, path string) []ndjsonEvent {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var events []ndjsonEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event ndjsonEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scan %s: %v", path, err)
+	}
+	return events
+}
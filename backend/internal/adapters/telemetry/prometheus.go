@@ -0,0 +1,246 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"plato/backend/internal/ports"
+)
+
+// prometheusContentType is the media type of the text exposition format.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// durationBuckets are the histogram upper bounds in seconds.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type requestKey struct {
+	method string
+	route  string
+	status string
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+type histogram struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+func (h *histogram) observe(seconds float64) {
+	if h.buckets == nil {
+		h.buckets = make([]uint64, len(durationBuckets))
+	}
+	for index, bound := range durationBuckets {
+		if seconds <= bound {
+			h.buckets[index]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// gauge is read when metrics are scraped. collect returns one value per
+// label value, or one value under the empty key when label is empty.
+type gauge struct {
+	name    string
+	help    string
+	label   string
+	collect func() (map[string]float64, error)
+}
+
+// PrometheusTelemetry keeps counters and histograms in memory and serves them
+// in the Prometheus text exposition format. Every telemetry event counts
+// toward plato_events_total. It is safe for concurrent use.
+type PrometheusTelemetry struct {
+	mu                  sync.Mutex
+	events              map[string]uint64
+	requests            map[requestKey]uint64
+	requestDurations    map[routeKey]*histogram
+	repositoryDurations map[string]*histogram
+	gauges              []gauge
+	logf                func(format string, args ...any)
+}
+
+var _ ports.Telemetry = (*PrometheusTelemetry)(nil)
+
+// NewPrometheusTelemetry returns an adapter with no recorded metrics.
+func NewPrometheusTelemetry() *PrometheusTelemetry {
+	return &PrometheusTelemetry{
+		events:              map[string]uint64{},
+		requests:            map[requestKey]uint64{},
+		requestDurations:    map[routeKey]*histogram{},
+		repositoryDurations: map[string]*histogram{},
+		logf:                log.Printf,
+	}
+}
+
+// Record counts the event by name. Attributes are dropped because their
+// values, such as record IDs, would grow the label space without bound.
+func (p *PrometheusTelemetry) Record(name string, _ map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events[name]++
+}
+
+// ObserveRequest counts one HTTP request and records its duration. route
+// should be a route template so IDs do not become label values.
+func (p *PrometheusTelemetry) ObserveRequest(method, route string, status int, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests[requestKey{method: method, route: route, status: strconv.Itoa(status)}]++
+	key := routeKey{method: method, route: route}
+	if p.requestDurations[key] == nil {
+		p.requestDurations[key] = &histogram{}
+	}
+	p.requestDurations[key].observe(duration.Seconds())
+}
+
+// ObserveRepositoryOperation records how long one repository operation took.
+func (p *PrometheusTelemetry) ObserveRepositoryOperation(operation string, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.repositoryDurations[operation] == nil {
+		p.repositoryDurations[operation] = &histogram{}
+	}
+	p.repositoryDurations[operation].observe(duration.Seconds())
+}
+
+// RegisterGauge adds a gauge that collect fills at scrape time, one sample
+// per value of label. An empty label writes a single sample without labels
+// from the value under the empty key. A failing collect is logged and leaves
+// the gauge out of that scrape.
+func (p *PrometheusTelemetry) RegisterGauge(name, help, label string, collect func() (map[string]float64, error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.gauges = append(p.gauges, gauge{name: name, help: help, label: label, collect: collect})
+}
+
+// ServeHTTP writes every metric in the text exposition format.
+func (p *PrometheusTelemetry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", prometheusContentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := p.WriteText(w); err != nil {
+		p.logf("write metrics failed: %v", err)
+	}
+}
+
+// WriteText writes every metric in the text exposition format. Gauges are
+// collected before the lock is taken so slow collectors do not block
+// recording.
+func (p *PrometheusTelemetry) WriteText(w io.Writer) error {
+	p.mu.Lock()
+	gauges := slices.Clone(p.gauges)
+	p.mu.Unlock()
+	collected := make([]map[string]float64, len(gauges))
+	for index, entry := range gauges {
+		values, err := entry.collect()
+		if err != nil {
+			p.logf("collect metric %s failed: %v", entry.name, err)
+			continue
+		}
+		collected[index] = values
+	}
+
+	var out strings.Builder
+	p.mu.Lock()
+	writeHeader(&out, "plato_events_total", "Telemetry events recorded by the service, by event name.", "counter")
+	for _, name := range sortedKeys(p.events) {
+		fmt.Fprintf(&out, "plato_events_total{event=%s} %d\n", quoteLabel(name), p.events[name])
+	}
+
+	writeHeader(&out, "plato_http_requests_total", "HTTP requests by method, route template, and status code.", "counter")
+	requestKeys := make([]requestKey, 0, len(p.requests))
+	for key := range p.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	slices.SortFunc(requestKeys, func(a, b requestKey) int {
+		return strings.Compare(a.route+" "+a.method+" "+a.status, b.route+" "+b.method+" "+b.status)
+	})
+	for _, key := range requestKeys {
+		fmt.Fprintf(&out, "plato_http_requests_total{method=%s,route=%s,status=%s} %d\n",
+			quoteLabel(key.method), quoteLabel(key.route), quoteLabel(key.status), p.requests[key])
+	}
+
+	writeHeader(&out, "plato_http_request_duration_seconds", "HTTP request latency by method and route template.", "histogram")
+	routeKeys := make([]routeKey, 0, len(p.requestDurations))
+	for key := range p.requestDurations {
+		routeKeys = append(routeKeys, key)
+	}
+	slices.SortFunc(routeKeys, func(a, b routeKey) int {
+		return strings.Compare(a.route+" "+a.method, b.route+" "+b.method)
+	})
+	for _, key := range routeKeys {
+		labels := "method=" + quoteLabel(key.method) + ",route=" + quoteLabel(key.route)
+		writeHistogram(&out, "plato_http_request_duration_seconds", labels, p.requestDurations[key])
+	}
+
+	writeHeader(&out, "plato_repository_operation_duration_seconds", "Repository operation latency by operation.", "histogram")
+	for _, operation := range sortedKeys(p.repositoryDurations) {
+		writeHistogram(&out, "plato_repository_operation_duration_seconds", "operation="+quoteLabel(operation), p.repositoryDurations[operation])
+	}
+	p.mu.Unlock()
+
+	for index, entry := range gauges {
+		if collected[index] == nil {
+			continue
+		}
+		writeHeader(&out, entry.name, entry.help, "gauge")
+		if entry.label == "" {
+			fmt.Fprintf(&out, "%s %s\n", entry.name, strconv.FormatFloat(collected[index][""], 'g', -1, 64))
+			continue
+		}
+		for _, labelValue := range sortedKeys(collected[index]) {
+			fmt.Fprintf(&out, "%s{%s=%s} %s\n", entry.name, entry.label, quoteLabel(labelValue),
+				strconv.FormatFloat(collected[index][labelValue], 'g', -1, 64))
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func writeHeader(out *strings.Builder, name, help, metricType string) {
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func writeHistogram(out *strings.Builder, name, labels string, values *histogram) {
+	for index, bound := range durationBuckets {
+		fmt.Fprintf(out, "%s_bucket{%s,le=%s} %d\n", name, labels, quoteLabel(strconv.FormatFloat(bound, 'g', -1, 64)), values.buckets[index])
+	}
+	fmt.Fprintf(out, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, values.count)
+	fmt.Fprintf(out, "%s_sum{%s} %s\n", name, labels, strconv.FormatFloat(values.sum, 'g', -1, 64))
+	fmt.Fprintf(out, "%s_count{%s} %d\n", name, labels, values.count)
+}
+
+// quoteLabel escapes a label value the way the exposition format expects.
+func quoteLabel(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,123 @@
+package telemetry
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPrometheusTelemetryWritesMetrics verifies the Prometheus telemetry writes metrics scenario.
+func TestPrometheusTelemetryWritesMetrics(t *testing.T) {
+	adapter := NewPrometheusTelemetry()
+	adapter.Record("person.created", map[string]string{"person_id": "person_1"})
+	adapter.Record("person.created", map[string]string{"person_id": "person_2"})
+	adapter.ObserveRequest(http.MethodGet, "/api/persons/{id}", http.StatusOK, 20*time.Millisecond)
+	adapter.ObserveRequest(http.MethodGet, "/api/persons/{id}", http.StatusNotFound, 2*time.Second)
+	adapter.ObserveRepositoryOperation("write", 3*time.Millisecond)
+	adapter.RegisterGauge("plato_active_allocations", "Active allocations.", "organisation_id", func() (map[string]float64, error) {
+		return map[string]float64{"org_1": 2, `org "2"`: 0}, nil
+	})
+	adapter.RegisterGauge("plato_total", "Unlabelled total.", "", func() (map[string]float64, error) {
+		return map[string]float64{"": 7}, nil
+	})
+
+	var out strings.Builder
+	if err := adapter.WriteText(&out); err != nil {
+		t.Fatalf("write metrics: %v", err)
+	}
+	body := out.String()
+	for _, want := range []string{
+		"# TYPE plato_events_total counter",
+		`plato_events_total{event="person.created"} 2`,
+		`plato_http_requests_total{method="GET",route="/api/persons/{id}",status="200"} 1`,
+		`plato_http_requests_total{method="GET",route="/api/persons/{id}",status="404"} 1`,
+		"# TYPE plato_http_request_duration_seconds histogram",
+		`plato_http_request_duration_seconds_bucket{method="GET",route="/api/persons/{id}",le="0.025"} 1`,
+		`plato_http_request_duration_seconds_bucket{method="GET",route="/api/persons/{id}",le="2.5"} 2`,
+		`plato_http_request_duration_seconds_bucket{method="GET",route="/api/persons/{id}",le="+Inf"} 2`,
+		`plato_http_request_duration_seconds_count{method="GET",route="/api/persons/{id}"} 2`,
+		`plato_repository_operation_duration_seconds_bucket{operation="write",le="0.005"} 1`,
+		"# TYPE plato_active_allocations gauge",
+		`plato_active_allocations{organisation_id="org_1"} 2`,
+		`plato_active_allocations{organisation_id="org \"2\""} 0`,
+		"\nplato_total 7\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected metrics to contain %s, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "person_1") {
+		t.Fatalf("expected event attributes to stay out of labels, got:\n%s", body)
+	}
+}
+
+// TestPrometheusTelemetrySkipsFailingGauge verifies the Prometheus telemetry skips failing gauge scenario.
+func TestPrometheusTelemetrySkipsFailingGauge(t *testing.T) {
+	adapter := NewPrometheusTelemetry()
+	var logged []string
+	adapter.logf = func(format string, args ...any) {
+		logged = append(logged, format)
+	}
+	adapter.RegisterGauge("plato_broken", "Broken gauge.", "organisation_id", func() (map[string]float64, error) {
+		return nil, errors.New("store offline")
+	})
+
+	var out strings.Builder
+	if err := adapter.WriteText(&out); err != nil {
+		t.Fatalf("write metrics: %v", err)
+	}
+	if strings.Contains(out.String(), "plato_broken") {
+		t.Fatalf("expected failing gauge to be skipped, got:\n%s", out.String())
+	}
+	if len(logged) != 1 {
+		t.Fatalf("expected one logged failure, got %d", len(logged))
+	}
+}
+
+// TestPrometheusTelemetryServeHTTP verifies the Prometheus telemetry serve HTTP scenario.
+func TestPrometheusTelemetryServeHTTP(t *testing.T) {
+	adapter := NewPrometheusTelemetry()
+	adapter.Record("organisation.created", nil)
+
+	response := httptest.NewRecorder()
+	adapter.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", response.Code)
+	}
+	if got := response.Header().Get("Content-Type"); got != prometheusContentType {
+		t.Fatalf("expected content type %q, got %q", prometheusContentType, got)
+	}
+	if !strings.Contains(response.Body.String(), `plato_events_total{event="organisation.created"} 1`) {
+		t.Fatalf("expected event counter, got:\n%s", response.Body.String())
+	}
+
+	response = httptest.NewRecorder()
+	adapter.ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", response.Code)
+	}
+}
+
+// TestFanoutRecordsToEveryAdapter verifies the fanout records to every adapter scenario.
+func TestFanoutRecordsToEveryAdapter(t *testing.T) {
+	first := NewPrometheusTelemetry()
+	second := NewPrometheusTelemetry()
+	fanout := Fanout{first, second}
+	fanout.Record("project.created", nil)
+	if err := fanout.Close(); err != nil {
+		t.Fatalf("close fanout: %v", err)
+	}
+
+	for index, adapter := range []*PrometheusTelemetry{first, second} {
+		var out strings.Builder
+		if err := adapter.WriteText(&out); err != nil {
+			t.Fatalf("write metrics: %v", err)
+		}
+		if !strings.Contains(out.String(), `plato_events_total{event="project.created"} 1`) {
+			t.Fatalf("expected adapter %d to record the event, got:\n%s", index, out.String())
+		}
+	}
+}
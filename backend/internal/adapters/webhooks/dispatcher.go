@@ -0,0 +1,354 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// Headers sent with every delivery. SignatureHeader holds
+// "sha256=" followed by the hex HMAC computed by Sign.
+const (
+	EventHeader     = "X-Plato-Event"
+	DeliveryHeader  = "X-Plato-Delivery"
+	TimestampHeader = "X-Plato-Timestamp"
+	SignatureHeader = "X-Plato-Signature"
+)
+
+const (
+	defaultMaxAttempts  = 5
+	defaultBaseBackoff  = time.Second
+	defaultQueueSize    = 256
+	defaultTimeout      = 10 * time.Second
+	defaultWorkers      = 8
+	defaultDrainTimeout = 10 * time.Second
+)
+
+// Store is the part of the repository the dispatcher reads webhooks from
+// and logs delivery outcomes to.
+type Store interface {
+	ListWebhooks(ctx context.Context, organisationID string) ([]domain.Webhook, error)
+	AppendWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error)
+}
+
+// Options tunes delivery. Zero values select the defaults: five attempts,
+// a one second base backoff, a queue of 256 events, eight workers, a ten
+// second drain on Close, and an HTTP client with a ten second timeout that
+// only connects to public addresses. Redirects are never followed, also
+// with a custom Client.
+type Options struct {
+	Client       *http.Client
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	QueueSize    int
+	Workers      int
+	DrainTimeout time.Duration
+	// AllowPrivateTargets lets the default client connect to loopback,
+	// link-local, and private addresses.
+	AllowPrivateTargets bool
+}
+
+// Dispatcher queues published events and posts them to every webhook of the
+// event's organisation that subscribes to it. A fixed number of workers
+// deliver, and failed attempts are retried with exponential backoff. It is
+// safe for concurrent use.
+type Dispatcher struct {
+	store        Store
+	client       *http.Client
+	maxAttempts  int
+	baseBackoff  time.Duration
+	drainTimeout time.Duration
+	now          func() time.Time
+	logf         func(format string, args ...any)
+
+	mu      sync.Mutex
+	closed  bool
+	queue   chan domain.Event
+	jobs    chan deliveryJob
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+}
+
+// deliveryJob is one event bound for one webhook.
+type deliveryJob struct {
+	webhook domain.Webhook
+	event   domain.Event
+	body    []byte
+}
+
+var _ ports.EventPublisher = (*Dispatcher)(nil)
+
+// NewDispatcher starts a dispatcher that reads webhooks from store. Call
+// Close to stop it.
+func NewDispatcher(store Store, options Options) *Dispatcher {
+	if options.Client == nil {
+		options.Client = newClient(options.AllowPrivateTargets)
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+	if options.BaseBackoff <= 0 {
+		options.BaseBackoff = defaultBaseBackoff
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaultQueueSize
+	}
+	if options.Workers <= 0 {
+		options.Workers = defaultWorkers
+	}
+	if options.DrainTimeout <= 0 {
+		options.DrainTimeout = defaultDrainTimeout
+	}
+	client := *options.Client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher := &Dispatcher{
+		store:        store,
+		client:       &client,
+		maxAttempts:  options.MaxAttempts,
+		baseBackoff:  options.BaseBackoff,
+		drainTimeout: options.DrainTimeout,
+		now:          time.Now,
+		logf:         log.Printf,
+		queue:        make(chan domain.Event, options.QueueSize),
+		jobs:         make(chan deliveryJob),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	dispatcher.workers.Go(dispatcher.run)
+	for range options.Workers {
+		dispatcher.workers.Go(dispatcher.work)
+	}
+	return dispatcher
+}
+
+// newClient returns the default delivery client. Unless private targets are
+// allowed, it checks every address it connects to, so a host that resolves
+// differently after registration still cannot reach a private network. It
+// ignores proxy settings because a proxy would connect on its behalf.
+func newClient(allowPrivateTargets bool) *http.Client {
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivateTargets {
+		dialer.Control = refusePrivateAddress
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: defaultTimeout, Transport: transport}
+}
+
+func refusePrivateAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !domain.WebhookAddressAllowed(addrPort.Addr()) {
+		return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+	}
+	return nil
+}
+
+// Publish queues an event for delivery and assigns its ID. Events are
+// dropped and logged when the queue is full or the dispatcher is closed,
+// so publishing never blocks a request.
+func (d *Dispatcher) Publish(event domain.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	if event.ID == "" {
+		event.ID = "evt_" + randomHex()
+	}
+	select {
+	case d.queue <- event:
+	default:
+		d.logf("webhook queue full, dropping %s event %s", event.Name, event.ID)
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be
+// delivered. Deliveries still running after the drain timeout are cancelled
+// and logged with their last outcome, and the remaining events are dropped.
+func (d *Dispatcher) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	close(d.queue)
+	d.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(drained)
+	}()
+	timer := time.NewTimer(d.drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-drained:
+	case <-timer.C:
+		d.logf("webhook deliveries did not drain within %s, cancelling them", d.drainTimeout)
+		d.cancel()
+		<-drained
+	}
+	d.cancel()
+	return nil
+}
+
+// run turns queued events into delivery jobs. Handing a job over blocks
+// until a worker is free, so the queue absorbs bursts.
+func (d *Dispatcher) run() {
+	defer close(d.jobs)
+	for event := range d.queue {
+		if d.ctx.Err() != nil {
+			continue
+		}
+		webhooks, err := d.store.ListWebhooks(d.ctx, event.OrganisationID)
+		if err != nil {
+			d.logf("list webhooks of %s: %v", event.OrganisationID, err)
+			continue
+		}
+		body, err := json.Marshal(event)
+		if err != nil {
+			d.logf("encode %s event %s: %v", event.Name, event.ID, err)
+			continue
+		}
+		for _, webhook := range webhooks {
+			if !webhook.Subscribes(event.Name) {
+				continue
+			}
+			select {
+			case d.jobs <- deliveryJob{webhook: webhook, event: event, body: body}:
+			case <-d.ctx.Done():
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) work() {
+	for job := range d.jobs {
+		if d.ctx.Err() != nil {
+			continue
+		}
+		d.deliver(job)
+	}
+}
+
+// deliver posts one event to one webhook until it succeeds, fails with a
+// status that retrying cannot fix, runs out of attempts, or the dispatcher
+// is cancelled. Only the final outcome is logged, so retries do not push
+// other deliveries out of the log.
+func (d *Dispatcher) deliver(job deliveryJob) {
+	outcome := domain.WebhookDelivery{
+		OrganisationID: job.webhook.OrganisationID,
+		WebhookID:      job.webhook.ID,
+		EventID:        job.event.ID,
+		Event:          job.event.Name,
+	}
+	for attempt := 1; ; attempt++ {
+		status, err := d.post(job.webhook, job.event, job.body)
+		succeeded := err == nil && status >= 200 && status < 300
+		outcome.Attempt = attempt
+		outcome.StatusCode = status
+		outcome.Succeeded = succeeded
+		outcome.At = d.now().UTC()
+		outcome.Error = ""
+		if err != nil {
+			outcome.Error = err.Error()
+		} else if !succeeded {
+			outcome.Error = "unexpected status " + strconv.Itoa(status)
+		}
+		if succeeded || (err == nil && !retryableStatus(status)) || attempt == d.maxAttempts || !d.backoff(attempt) {
+			break
+		}
+	}
+
+	// The log is written without the dispatcher context so a delivery
+	// cancelled by Close is still recorded.
+	if _, err := d.store.AppendWebhookDelivery(context.Background(), outcome); err != nil {
+		d.logf("log delivery of %s to %s: %v", job.event.ID, job.webhook.ID, err)
+	}
+}
+
+// backoff waits before the attempt after attempt and reports false when the
+// dispatcher was cancelled meanwhile.
+func (d *Dispatcher) backoff(attempt int) bool {
+	timer := time.NewTimer(d.baseBackoff << (attempt - 1))
+	defer timer.Stop()
+	select {
+	case <-d.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (d *Dispatcher) post(webhook domain.Webhook, event domain.Event, body []byte) (int, error) {
+	request, err := http.NewRequestWithContext(d.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, event.Name)
+	request.Header.Set(DeliveryHeader, event.ID)
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, timestamp, body))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+	return response.StatusCode, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of timestamp, a dot and body keyed with
+// secret. Receivers recompute it to check that a payload came from this
+// server and was not replayed with a different timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryableStatus reports whether a later attempt may succeed where this
+// response failed.
+func retryableStatus(status int) bool {
+	return status >= http.StatusInternalServerError ||
+		status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests
+}
+
+func randomHex() string {
+	raw := make([]byte, 16)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
@@ -0,0 +1,248 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"plato/backend/internal/domain"
+)
+
+type memoryStore struct {
+	mu         sync.Mutex
+	webhooks   []domain.Webhook
+	deliveries []domain.WebhookDelivery
+	delivered  chan struct{}
+}
+
+func (s *memoryStore) ListWebhooks(_ context.Context, organisationID string) ([]domain.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]domain.Webhook, 0)
+	for _, webhook := range s.webhooks {
+		if webhook.OrganisationID == organisationID {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryStore) AppendWebhookDelivery(_ context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	s.mu.Lock()
+	s.deliveries = append(s.deliveries, delivery)
+	s.mu.Unlock()
+	s.delivered <- struct{}{}
+	return delivery, nil
+}
+
+func waitForDeliveries(t *testing.T, store *memoryStore, count int) []domain.WebhookDelivery {
+	t.Helper()
+	for range count {
+		select {
+		case <-store.delivered:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %d deliveries", count)
+		}
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return append([]domain.WebhookDelivery(nil), store.deliveries...)
+}
+
+// TestDispatcherRetriesAndSignsDeliveries verifies the retry and signature scenario.
+func TestDispatcherRetriesAndSignsDeliveries(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get(SignatureHeader), "sha256="+Sign("secret", r.Header.Get(TimestampHeader), body); got != want {
+			t.Errorf("expected signature %q, got %q", want, got)
+		}
+		if r.Header.Get(EventHeader) != "person.created" || r.Header.Get(DeliveryHeader) == "" {
+			t.Errorf("unexpected event headers %v", r.Header)
+		}
+		mu.Lock()
+		requests++
+		attempt := requests
+		mu.Unlock()
+		if attempt < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{
+		webhooks: []domain.Webhook{
+			{ID: "webhook_1", OrganisationID: "org_1", URL: server.URL, Events: []string{"person.created"}, Secret: "secret"},
+			{ID: "webhook_2", OrganisationID: "org_1", URL: server.URL, Events: []string{"project.deleted"}, Secret: "secret"},
+			{ID: "webhook_3", OrganisationID: "org_2", URL: server.URL, Events: []string{domain.WebhookEventAll}, Secret: "secret"},
+		},
+		delivered: make(chan struct{}, 10),
+	}
+	dispatcher := NewDispatcher(store, Options{BaseBackoff: time.Millisecond, AllowPrivateTargets: true})
+	defer dispatcher.Close()
+
+	dispatcher.Publish(domain.Event{Name: "person.created", OrganisationID: "org_1", Data: map[string]string{"person_id": "person_1"}})
+
+	deliveries := waitForDeliveries(t, store, 1)
+	delivery := deliveries[0]
+	if delivery.WebhookID != "webhook_1" || delivery.EventID == "" || delivery.Attempt != 3 {
+		t.Fatalf("expected one logged outcome after three attempts, got %+v", deliveries)
+	}
+	if !delivery.Succeeded || delivery.StatusCode != http.StatusNoContent || delivery.Error != "" {
+		t.Fatalf("expected the final attempt to succeed, got %+v", delivery)
+	}
+}
+
+// TestDispatcherStopsOnPermanentFailure verifies the client error scenario.
+func TestDispatcherStopsOnPermanentFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{
+		webhooks:  []domain.Webhook{{ID: "webhook_1", OrganisationID: "org_1", URL: server.URL, Events: []string{domain.WebhookEventAll}}},
+		delivered: make(chan struct{}, 10),
+	}
+	dispatcher := NewDispatcher(store, Options{BaseBackoff: time.Millisecond, AllowPrivateTargets: true})
+	dispatcher.Publish(domain.Event{Name: "allocation.deleted", OrganisationID: "org_1"})
+	waitForDeliveries(t, store, 1)
+	if err := dispatcher.Close(); err != nil {
+		t.Fatalf("close dispatcher: %v", err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(store.deliveries) != 1 || store.deliveries[0].StatusCode != http.StatusGone {
+		t.Fatalf("expected one failed attempt, got %+v", store.deliveries)
+	}
+}
+
+// TestDispatcherRefusesRedirectsAndPrivateTargets verifies the redirect and private address scenario.
+func TestDispatcherRefusesRedirectsAndPrivateTargets(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/hook" {
+			http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{
+		webhooks:  []domain.Webhook{{ID: "webhook_1", OrganisationID: "org_1", URL: server.URL + "/hook", Events: []string{domain.WebhookEventAll}}},
+		delivered: make(chan struct{}, 10),
+	}
+	dispatcher := NewDispatcher(store, Options{BaseBackoff: time.Millisecond, AllowPrivateTargets: true})
+	defer dispatcher.Close()
+	dispatcher.Publish(domain.Event{Name: "person.created", OrganisationID: "org_1"})
+	redirected := waitForDeliveries(t, store, 1)[0]
+	if redirected.Succeeded || redirected.StatusCode != http.StatusTemporaryRedirect || redirected.Attempt != 1 {
+		t.Fatalf("expected the redirect to be logged as a failed outcome, got %+v", redirected)
+	}
+
+	guarded := NewDispatcher(store, Options{BaseBackoff: time.Millisecond, MaxAttempts: 2})
+	defer guarded.Close()
+	guarded.Publish(domain.Event{Name: "person.created", OrganisationID: "org_1"})
+	refused := waitForDeliveries(t, store, 1)[1]
+	if refused.Succeeded || refused.StatusCode != 0 || !strings.Contains(refused.Error, "not public") || refused.Attempt != 2 {
+		t.Fatalf("expected the loopback target to be refused, got %+v", refused)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if hits["/hook"] != 1 || hits["/internal"] != 0 {
+		t.Fatalf("expected one request and no followed redirect, got %v", hits)
+	}
+}
+
+// TestDispatcherDrainsOnClose verifies the worker pool and shutdown scenario.
+func TestDispatcherDrainsOnClose(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{delivered: make(chan struct{}, 10)}
+	for i := range 5 {
+		store.webhooks = append(store.webhooks, domain.Webhook{
+			ID: "webhook_" + strconv.Itoa(i), OrganisationID: "org_1", URL: server.URL, Events: []string{domain.WebhookEventAll},
+		})
+	}
+	dispatcher := NewDispatcher(store, Options{Workers: 2, AllowPrivateTargets: true})
+	dispatcher.Publish(domain.Event{Name: "person.created", OrganisationID: "org_1"})
+	if err := dispatcher.Close(); err != nil {
+		t.Fatalf("close dispatcher: %v", err)
+	}
+	deliveries := waitForDeliveries(t, store, 5)
+	for _, delivery := range deliveries {
+		if !delivery.Succeeded {
+			t.Fatalf("expected queued deliveries to finish before Close returns, got %+v", deliveries)
+		}
+	}
+	mu.Lock()
+	if peak > 2 {
+		t.Fatalf("expected at most two concurrent deliveries, got %d", peak)
+	}
+	mu.Unlock()
+
+	down := &memoryStore{
+		webhooks:  []domain.Webhook{{ID: "webhook_1", OrganisationID: "org_1", URL: server.URL + "/down", Events: []string{domain.WebhookEventAll}}},
+		delivered: make(chan struct{}, 10),
+	}
+	stuck := NewDispatcher(down, Options{BaseBackoff: time.Hour, DrainTimeout: 50 * time.Millisecond, AllowPrivateTargets: true})
+	stuck.Publish(domain.Event{Name: "person.created", OrganisationID: "org_1"})
+	started := time.Now()
+	if err := stuck.Close(); err != nil {
+		t.Fatalf("close dispatcher: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("expected Close to give up after the drain timeout, took %s", elapsed)
+	}
+	cancelled := waitForDeliveries(t, down, 1)[0]
+	if cancelled.Succeeded || cancelled.StatusCode != http.StatusServiceUnavailable || cancelled.Attempt != 1 {
+		t.Fatalf("expected the cancelled delivery to log its last outcome, got %+v", cancelled)
+	}
+}
+
+// TestSign verifies the signature scenario.
+func TestSign(t *testing.T) {
+	got := Sign("secret", "1700000000", []byte(`{"event":"person.created"}`))
+	if len(got) != 64 {
+		t.Fatalf("expected a hex SHA-256 digest, got %q", got)
+	}
+	if got == Sign("other", "1700000000", []byte(`{"event":"person.created"}`)) {
+		t.Fatal("expected the secret to change the signature")
+	}
+	if got == Sign("secret", "1700000001", []byte(`{"event":"person.created"}`)) {
+		t.Fatal("expected the timestamp to change the signature")
+	}
+}
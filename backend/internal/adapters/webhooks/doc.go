@@ -0,0 +1,2 @@
+// Package webhooks delivers domain events to registered webhook endpoints.
+package webhooks
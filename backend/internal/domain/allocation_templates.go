@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// AllocationTemplate is a named set of allocation entries for a recurring
+// staffing pattern. Applying it to a project and a date range creates one
+// allocation per entry.
+type AllocationTemplate struct {
+	ID             string                    `json:"id"`
+	OrganisationID string                    `json:"organisation_id"`
+	Name           string                    `json:"name"`
+	Entries        []AllocationTemplateEntry `json:"entries"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+	Version        int64                     `json:"version"`
+}
+
+// AllocationTemplateEntry names the person or group to allocate and the
+// percent to allocate them at.
+type AllocationTemplateEntry struct {
+	TargetType string  `json:"target_type"`
+	TargetID   string  `json:"target_id"`
+	Percent    float64 `json:"percent"`
+}
+
+// MaxAllocationTemplateEntries caps how many entries one template may hold.
+const MaxAllocationTemplateEntries = 200
+
+// Normalized returns the template with a trimmed name and entries with
+// trimmed IDs and lowercase target types. An empty target type means a
+// person.
+func (t AllocationTemplate) Normalized() AllocationTemplate {
+	t.Name = strings.TrimSpace(t.Name)
+	entries := make([]AllocationTemplateEntry, 0, len(t.Entries))
+	for _, entry := range t.Entries {
+		entry.TargetType = strings.ToLower(strings.TrimSpace(entry.TargetType))
+		if entry.TargetType == "" {
+			entry.TargetType = AllocationTargetPerson
+		}
+		entry.TargetID = strings.TrimSpace(entry.TargetID)
+		entries = append(entries, entry)
+	}
+	t.Entries = entries
+	return t
+}
+
+// Validate checks the name and entries of a normalized template.
+func (t AllocationTemplate) Validate() error {
+	if err := ValidateName(t.Name); err != nil {
+		return ErrValidation
+	}
+	if len(t.Entries) == 0 || len(t.Entries) > MaxAllocationTemplateEntries {
+		return ErrValidation
+	}
+	for _, entry := range t.Entries {
+		if entry.TargetType != AllocationTargetPerson && entry.TargetType != AllocationTargetGroup {
+			return ErrValidation
+		}
+		if entry.TargetID == "" {
+			return ErrValidation
+		}
+		if math.IsNaN(entry.Percent) || math.IsInf(entry.Percent, 0) || entry.Percent <= 0 {
+			return ErrValidation
+		}
+	}
+	return nil
+}
+
+// AllocationTemplateApplication selects the project and dates an allocation
+// template is applied to. Empty dates default to the project's dates.
+type AllocationTemplateApplication struct {
+	ProjectID string
+	StartDate string
+	EndDate   string
+}
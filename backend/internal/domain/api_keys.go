@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// APIKey is a long-lived credential for machine clients such as CI scripts.
+// It acts as one organisation with fixed roles. Only Hash, the SHA-256 of
+// the key, is stored. Key carries the plain key in the create response and
+// is never stored.
+type APIKey struct {
+	ID             string     `json:"id"`
+	OrganisationID string     `json:"organisation_id"`
+	Name           string     `json:"name"`
+	Roles          []string   `json:"roles"`
+	Prefix         string     `json:"prefix"`
+	Hash           string     `json:"hash,omitempty"`
+	Key            string     `json:"key,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	Version        int64      `json:"version"`
+}
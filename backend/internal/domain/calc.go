@@ -8,6 +8,9 @@ import (
 	"time"
 )
 
+// emptyBucketEpsilon absorbs float noise when SkipEmpty checks for zero hours.
+const emptyBucketEpsilon = 1e-9
+
 // CalculationInput bundles the data required to calculate report buckets.
 type CalculationInput struct {
 	Organisation         Organisation
@@ -18,14 +21,16 @@ type CalculationInput struct {
 	OrgHolidays          []OrgHoliday
 	GroupUnavailability  []GroupUnavailability
 	PersonUnavailability []PersonUnavailability
+	UnavailabilityRules  []UnavailabilityRule
 	Request              ReportRequest
 }
 
 type personAllocation struct {
-	ProjectID string
-	Percent   float64
-	StartDate time.Time
-	EndDate   time.Time
+	AllocationID string
+	ProjectID    string
+	Percent      float64
+	StartDate    time.Time
+	EndDate      time.Time
 }
 
 type allocationResolution struct {
@@ -40,8 +45,10 @@ type calculationLookups struct {
 	personGroupIDs         map[string][]string
 	allocationsByPerson    map[string][]personAllocation
 	orgHolidayHoursByDate  map[string]float64
+	personHolidayHours     map[string]float64
 	groupUnavailableHours  map[string]float64
 	personUnavailableHours map[string]float64
+	personWorkSchedules    map[string]WorkSchedule
 	allPersonIDs           []string
 	allGroupIDs            []string
 	allProjectIDs          []string
@@ -52,6 +59,7 @@ type personDayTotals struct {
 	loadHours         float64
 	projectLoadHours  float64
 	freeHours         float64
+	activeAllocations []personAllocation
 }
 
 // CalculateAvailabilityLoad computes availability and load buckets from a data snapshot.
@@ -62,6 +70,9 @@ func CalculateAvailabilityLoad(input CalculationInput) ([]ReportBucket, error) {
 	if err := ValidateGranularity(input.Request.Granularity); err != nil {
 		return nil, err
 	}
+	if err := ValidateReportUnit(input.Request.Unit); err != nil {
+		return nil, err
+	}
 
 	fromDate, toDate, err := parseReportDateRange(input.Request.FromDate, input.Request.ToDate)
 	if err != nil {
@@ -91,7 +102,7 @@ func CalculateAvailabilityLoad(input CalculationInput) ([]ReportBucket, error) {
 		fromDate,
 		toDate,
 		input.Request,
-		input.Organisation.HoursPerDay,
+		input.Organisation,
 		projectEstimationHours,
 		selectedPersonIDs,
 		targetProjectIDs,
@@ -100,8 +111,73 @@ func CalculateAvailabilityLoad(input CalculationInput) ([]ReportBucket, error) {
 	if err != nil {
 		return nil, err
 	}
+	if input.Request.Scope == ScopeProject {
+		milestoneWindow := reportWindow{from: fromDate, to: toDate, granularity: input.Request.Granularity}
+		err = annotateMilestones(buckets, input.Projects, targetProjectIDs, milestoneWindow, input.Organisation, lookups)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return summarizeBuckets(buckets, input.Request.Scope), nil
+	if input.Request.Unit == ReportUnitFTE {
+		err = convertBucketsToFTE(buckets, fromDate, toDate, input.Request.Granularity, input.Organisation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := summarizeBuckets(buckets, input.Request.Scope, input.Organisation.OverloadBands())
+	if input.Request.SkipEmpty {
+		result = skipEmptyBuckets(result)
+	}
+	return result, nil
+}
+
+// convertBucketsToFTE divides the person hours of each bucket by the hours a
+// full-time person is available in it. Availability counts the hours per day
+// in effect on every calendar day, so a full-time bucket holds their sum over
+// the report days it covers, and one full-time person is 1 FTE at any
+// granularity. Peak load covers a single day. Project effort stays in hours.
+func convertBucketsToFTE(buckets map[string]ReportBucket, fromDate, toDate time.Time, granularity string, organisation Organisation) error {
+	fullTimeHoursByPeriod := map[string]float64{}
+	err := iterateDateRange(fromDate, toDate, func(current time.Time) error {
+		hoursPerDay := organisation.HoursPerDayOn(current.Format(DateLayout))
+		if hoursPerDay <= 0 {
+			return ErrValidation
+		}
+		fullTimeHoursByPeriod[periodStart(current, granularity).Format(DateLayout)] += hoursPerDay
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, bucket := range buckets {
+		fullTimeHours := fullTimeHoursByPeriod[key]
+		if fullTimeHours <= 0 {
+			continue
+		}
+		bucket.AvailabilityHours /= fullTimeHours
+		bucket.LoadHours /= fullTimeHours
+		bucket.FreeHours /= fullTimeHours
+		bucket.PeakLoadHours /= organisation.HoursPerDayOn(bucket.PeakLoadDate)
+		buckets[key] = bucket
+	}
+	return nil
+}
+
+// skipEmptyBuckets drops buckets whose availability and load are both
+// effectively zero. Buckets carrying milestones are kept.
+func skipEmptyBuckets(buckets []ReportBucket) []ReportBucket {
+	result := make([]ReportBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		empty := math.Abs(bucket.AvailabilityHours) < emptyBucketEpsilon && math.Abs(bucket.LoadHours) < emptyBucketEpsilon
+		if empty && len(bucket.Milestones) == 0 {
+			continue
+		}
+		result = append(result, bucket)
+	}
+	return result
 }
 
 func parseReportDateRange(fromDate, toDate string) (start time.Time, end time.Time, err error) {
@@ -123,7 +199,10 @@ func parseReportDateRange(fromDate, toDate string) (start time.Time, end time.Ti
 
 func buildCalculationLookups(input CalculationInput) (calculationLookups, error) {
 	personsByID, allPersonIDs := indexPersons(input.Persons)
-	groupsByID, allGroupIDs, personGroupIDs := indexGroups(input.Groups)
+	groupsByID, allGroupIDs, personGroupIDs, err := indexGroups(input.Groups)
+	if err != nil {
+		return calculationLookups{}, err
+	}
 	allProjectIDs := collectProjectIDs(input.Projects)
 
 	allocationsByPerson, err := aggregateAllocations(input.Allocations, personsByID, groupsByID)
@@ -131,14 +210,26 @@ func buildCalculationLookups(input CalculationInput) (calculationLookups, error)
 		return calculationLookups{}, err
 	}
 
+	groupUnavailableHours := aggregateGroupUnavailableHours(input.GroupUnavailability)
+	personUnavailableHours := aggregatePersonUnavailableHours(input.PersonUnavailability)
+	if len(input.UnavailabilityRules) > 0 {
+		fromDate, toDate, rangeErr := parseReportDateRange(input.Request.FromDate, input.Request.ToDate)
+		if rangeErr != nil {
+			return calculationLookups{}, rangeErr
+		}
+		expandUnavailabilityRules(input.UnavailabilityRules, fromDate, toDate, personUnavailableHours, groupUnavailableHours)
+	}
+
 	return calculationLookups{
 		personsByID:            personsByID,
 		groupsByID:             groupsByID,
 		personGroupIDs:         personGroupIDs,
 		allocationsByPerson:    allocationsByPerson,
 		orgHolidayHoursByDate:  aggregateOrgHolidayHours(input.OrgHolidays),
-		groupUnavailableHours:  aggregateGroupUnavailableHours(input.GroupUnavailability),
-		personUnavailableHours: aggregatePersonUnavailableHours(input.PersonUnavailability),
+		personHolidayHours:     aggregatePersonHolidayHours(input.OrgHolidays),
+		groupUnavailableHours:  groupUnavailableHours,
+		personUnavailableHours: personUnavailableHours,
+		personWorkSchedules:    indexPersonWorkSchedules(input.Organisation, input.Persons),
 		allPersonIDs:           allPersonIDs,
 		allGroupIDs:            allGroupIDs,
 		allProjectIDs:          allProjectIDs,
@@ -156,19 +247,49 @@ func indexPersons(persons []Person) (map[string]Person, []string) {
 	return personsByID, allPersonIDs
 }
 
-func indexGroups(groups []Group) (map[string]Group, []string, map[string][]string) {
+// indexGroups maps each person to every group they belong to, including
+// groups that reach them through nested subgroups.
+// indexPersonWorkSchedules maps each person with a known work schedule to it.
+func indexPersonWorkSchedules(organisation Organisation, persons []Person) map[string]WorkSchedule {
+	schedules := map[string]WorkSchedule{}
+	for _, person := range persons {
+		if person.WorkSchedule == "" {
+			continue
+		}
+		if schedule, ok := organisation.WorkSchedule(person.WorkSchedule); ok {
+			schedules[person.ID] = schedule
+		}
+	}
+	return schedules
+}
+
+// personWorksOn reports whether the person's work schedule, if any, has date
+// as a working day.
+func personWorksOn(personID string, date time.Time, lookups calculationLookups) bool {
+	schedule, ok := lookups.personWorkSchedules[personID]
+	return !ok || schedule.WorksOn(date)
+}
+
+func indexGroups(groups []Group) (map[string]Group, []string, map[string][]string, error) {
 	groupsByID := make(map[string]Group, len(groups))
 	allGroupIDs := make([]string, 0, len(groups))
-	personGroupIDs := make(map[string][]string)
 	for _, group := range groups {
 		groupsByID[group.ID] = group
 		allGroupIDs = append(allGroupIDs, group.ID)
-		for _, memberID := range group.MemberIDs {
-			personGroupIDs[memberID] = append(personGroupIDs[memberID], group.ID)
+	}
+
+	personGroupIDs := make(map[string][]string)
+	for _, groupID := range allGroupIDs {
+		memberIDs, err := GroupPersonIDs(groupID, groupsByID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, memberID := range memberIDs {
+			personGroupIDs[memberID] = append(personGroupIDs[memberID], groupID)
 		}
 	}
 
-	return groupsByID, allGroupIDs, personGroupIDs
+	return groupsByID, allGroupIDs, personGroupIDs, nil
 }
 
 func collectProjectIDs(projects []Project) []string {
@@ -197,10 +318,11 @@ func aggregateAllocations(
 
 		for _, personID := range resolved.personIDs {
 			allocationsByPerson[personID] = append(allocationsByPerson[personID], personAllocation{
-				ProjectID: allocation.ProjectID,
-				Percent:   allocation.Percent,
-				StartDate: resolved.startDate,
-				EndDate:   resolved.endDate,
+				AllocationID: allocation.ID,
+				ProjectID:    allocation.ProjectID,
+				Percent:      allocation.Percent,
+				StartDate:    resolved.startDate,
+				EndDate:      resolved.endDate,
 			})
 		}
 	}
@@ -211,12 +333,26 @@ func aggregateAllocations(
 func aggregateOrgHolidayHours(holidays []OrgHoliday) map[string]float64 {
 	orgHolidayHoursByDate := make(map[string]float64)
 	for _, holiday := range holidays {
+		if len(holiday.PersonIDs) > 0 {
+			continue
+		}
 		orgHolidayHoursByDate[holiday.Date] += holiday.Hours
 	}
 
 	return orgHolidayHoursByDate
 }
 
+func aggregatePersonHolidayHours(holidays []OrgHoliday) map[string]float64 {
+	personHolidayHours := make(map[string]float64)
+	for _, holiday := range holidays {
+		for _, personID := range holiday.PersonIDs {
+			personHolidayHours[compoundDateKey(personID, holiday.Date)] += holiday.Hours
+		}
+	}
+
+	return personHolidayHours
+}
+
 func aggregateGroupUnavailableHours(entries []GroupUnavailability) map[string]float64 {
 	groupUnavailableHours := make(map[string]float64)
 	for _, entry := range entries {
@@ -239,13 +375,15 @@ func calculateBuckets(
 	fromDate time.Time,
 	toDate time.Time,
 	request ReportRequest,
-	hoursPerDay float64,
+	organisation Organisation,
 	projectEstimationHours float64,
 	selectedPersonIDs []string,
 	targetProjectIDs map[string]bool,
 	lookups calculationLookups,
 ) (map[string]ReportBucket, error) {
 	buckets := map[string]ReportBucket{}
+	contributorsByPeriod := map[string]map[string]ReportContributor{}
+	trackPeak := request.IncludePeakLoad && request.Granularity != GranularityDay
 	err := iterateDateRange(fromDate, toDate, func(current time.Time) error {
 		periodKey := periodStart(current, request.Granularity).Format(DateLayout)
 		bucket := buckets[periodKey]
@@ -253,6 +391,8 @@ func calculateBuckets(
 		bucket.ProjectEstimation = projectEstimationHours
 
 		dayKey := current.Format(DateLayout)
+		hoursPerDay := organisation.HoursPerDayOn(dayKey)
+		var dayLoadHours float64
 		for _, personID := range selectedPersonIDs {
 			person, ok := lookups.personsByID[personID]
 			if !ok {
@@ -275,8 +415,16 @@ func calculateBuckets(
 
 			bucket.AvailabilityHours += totals.availabilityHours
 			bucket.LoadHours += totals.loadHours
+			dayLoadHours += totals.loadHours
 			bucket.ProjectLoadHours += totals.projectLoadHours
 			bucket.FreeHours += totals.freeHours
+			if request.IncludeContributors {
+				addContributions(contributorsByPeriod, periodKey, totals.activeAllocations, hoursPerDay)
+			}
+		}
+		if trackPeak && dayLoadHours > bucket.PeakLoadHours {
+			bucket.PeakLoadHours = dayLoadHours
+			bucket.PeakLoadDate = dayKey
 		}
 
 		buckets[periodKey] = bucket
@@ -286,9 +434,169 @@ func calculateBuckets(
 		return nil, err
 	}
 
+	if request.IncludeContributors {
+		attachContributors(buckets, contributorsByPeriod)
+	}
 	return buckets, nil
 }
 
+func addContributions(
+	contributorsByPeriod map[string]map[string]ReportContributor,
+	periodKey string,
+	allocations []personAllocation,
+	hoursPerDay float64,
+) {
+	if len(allocations) == 0 {
+		return
+	}
+	contributors, ok := contributorsByPeriod[periodKey]
+	if !ok {
+		contributors = map[string]ReportContributor{}
+		contributorsByPeriod[periodKey] = contributors
+	}
+	for _, allocation := range allocations {
+		contributor := contributors[allocation.AllocationID]
+		contributor.AllocationID = allocation.AllocationID
+		contributor.Percent = allocation.Percent
+		contributor.Hours += hoursPerDay * allocation.Percent / 100
+		contributors[allocation.AllocationID] = contributor
+	}
+}
+
+func attachContributors(buckets map[string]ReportBucket, contributorsByPeriod map[string]map[string]ReportContributor) {
+	for periodKey, bucket := range buckets {
+		contributors := contributorsByPeriod[periodKey]
+		bucket.Contributors = make([]ReportContributor, 0, len(contributors))
+		for _, contributor := range contributors {
+			contributor.Hours = round2(contributor.Hours)
+			bucket.Contributors = append(bucket.Contributors, contributor)
+		}
+		sort.Slice(bucket.Contributors, func(i, j int) bool {
+			return bucket.Contributors[i].AllocationID < bucket.Contributors[j].AllocationID
+		})
+		buckets[periodKey] = bucket
+	}
+}
+
+type reportWindow struct {
+	from        time.Time
+	to          time.Time
+	granularity string
+}
+
+// annotateMilestones attaches each target project milestone inside the report
+// window to its bucket. Cumulative load is counted from the project start so the
+// result does not depend on where the report window begins.
+func annotateMilestones(
+	buckets map[string]ReportBucket,
+	projects []Project,
+	targetProjectIDs map[string]bool,
+	window reportWindow,
+	organisation Organisation,
+	lookups calculationLookups,
+) error {
+	for _, project := range projects {
+		if !targetProjectIDs[project.ID] || len(project.Milestones) == 0 {
+			continue
+		}
+		projectStart, err := time.Parse(DateLayout, project.StartDate)
+		if err != nil {
+			return ErrValidation
+		}
+		for _, milestone := range project.Milestones {
+			report, periodKey, milestoneErr := milestoneReport(project.ID, projectStart, milestone, window, organisation, lookups)
+			if milestoneErr != nil {
+				return milestoneErr
+			}
+			if periodKey == "" {
+				continue
+			}
+			bucket := buckets[periodKey]
+			bucket.Milestones = append(bucket.Milestones, report)
+			buckets[periodKey] = bucket
+		}
+	}
+
+	for periodKey, bucket := range buckets {
+		sortReportMilestones(bucket.Milestones)
+		buckets[periodKey] = bucket
+	}
+	return nil
+}
+
+// projectLoadBetween sums the load one project receives between two dates,
+// using the same full-time capacity rule as calculatePersonAvailability.
+func projectLoadBetween(projectID string, fromDate, toDate time.Time, organisation Organisation, lookups calculationLookups) (float64, error) {
+	var total float64
+	err := iterateDateRange(fromDate, toDate, func(current time.Time) error {
+		dayKey := current.Format(DateLayout)
+		hoursPerDay := organisation.HoursPerDayOn(dayKey)
+		for _, personID := range lookups.allPersonIDs {
+			allocations := lookups.allocationsByPerson[personID]
+			if len(allocations) == 0 {
+				continue
+			}
+			employmentPct, err := EmploymentPctOnDate(lookups.personsByID[personID], dayKey)
+			if err != nil {
+				return ErrValidation
+			}
+			if hoursPerDay*employmentPct/100 <= 0 || !personWorksOn(personID, current, lookups) {
+				continue
+			}
+			for _, allocation := range allocations {
+				if allocation.ProjectID == projectID && allocationAppliesToDate(allocation, current) {
+					total += hoursPerDay * allocation.Percent / 100
+				}
+			}
+		}
+		return nil
+	})
+	return total, err
+}
+
+func milestoneReport(
+	projectID string,
+	projectStart time.Time,
+	milestone ProjectMilestone,
+	window reportWindow,
+	organisation Organisation,
+	lookups calculationLookups,
+) (report ReportMilestone, periodKey string, err error) {
+	milestoneDate, err := time.Parse(DateLayout, milestone.Date)
+	if err != nil {
+		return ReportMilestone{}, "", ErrValidation
+	}
+	if milestoneDate.Before(window.from) || milestoneDate.After(window.to) {
+		return ReportMilestone{}, "", nil
+	}
+
+	cumulative, err := projectLoadBetween(projectID, projectStart, milestoneDate, organisation, lookups)
+	if err != nil {
+		return ReportMilestone{}, "", err
+	}
+	periodKey = periodStart(milestoneDate, window.granularity).Format(DateLayout)
+	return ReportMilestone{
+		ProjectID:           projectID,
+		Name:                milestone.Name,
+		Date:                milestoneDate.Format(DateLayout),
+		TargetEffortHours:   round2(milestone.TargetEffortHours),
+		CumulativeLoadHours: round2(cumulative),
+		Met:                 round2(cumulative) >= round2(milestone.TargetEffortHours),
+	}, periodKey, nil
+}
+
+func sortReportMilestones(milestones []ReportMilestone) {
+	sort.Slice(milestones, func(i, j int) bool {
+		if milestones[i].Date != milestones[j].Date {
+			return milestones[i].Date < milestones[j].Date
+		}
+		if milestones[i].ProjectID != milestones[j].ProjectID {
+			return milestones[i].ProjectID < milestones[j].ProjectID
+		}
+		return milestones[i].Name < milestones[j].Name
+	})
+}
+
 func iterateDateRange(fromDate, toDate time.Time, visit func(time.Time) error) error {
 	for current := fromDate; !current.After(toDate); current = current.AddDate(0, 0, 1) {
 		if err := visit(current); err != nil {
@@ -314,19 +622,22 @@ func calculatePersonAvailability(
 		return personDayTotals{}, ErrValidation
 	}
 
+	// Non-working days of the person's schedule carry no capacity and, like
+	// days at 0% employment, no load.
 	baseCapacity := hoursPerDay * employmentPct / 100
-	if baseCapacity <= 0 {
+	if baseCapacity <= 0 || !personWorksOn(personID, currentDate, lookups) {
 		return personDayTotals{}, nil
 	}
 
 	unavailableHours := unavailableHoursForPersonOnDate(personID, dayKey, baseCapacity, lookups)
 	effectiveAvailability := baseCapacity - unavailableHours
-	allocationPct := allocationPercentForPersonOnDate(
+	activeAllocations := activeAllocationsForPersonOnDate(
 		lookups.allocationsByPerson[personID],
 		currentDate,
 		scope,
 		targetProjectIDs,
 	)
+	allocationPct := sumAllocationPercent(activeAllocations)
 
 	// Allocation percent is interpreted on full-time capacity.
 	// Capacity limits are enforced during allocation writes.
@@ -335,6 +646,7 @@ func calculatePersonAvailability(
 		availabilityHours: effectiveAvailability,
 		loadHours:         loadHours,
 		freeHours:         effectiveAvailability - loadHours,
+		activeAllocations: activeAllocations,
 	}
 	if scope == ScopeProject {
 		totals.projectLoadHours = loadHours
@@ -350,6 +662,7 @@ func unavailableHoursForPersonOnDate(
 	lookups calculationLookups,
 ) float64 {
 	unavailableHours := lookups.orgHolidayHoursByDate[dayKey]
+	unavailableHours += lookups.personHolidayHours[compoundDateKey(personID, dayKey)]
 	unavailableHours += lookups.personUnavailableHours[compoundDateKey(personID, dayKey)]
 	for _, groupID := range lookups.personGroupIDs[personID] {
 		unavailableHours += lookups.groupUnavailableHours[compoundDateKey(groupID, dayKey)]
@@ -365,14 +678,23 @@ func unavailableHoursForPersonOnDate(
 	return unavailableHours
 }
 
-func allocationPercentForPersonOnDate(
+func sumAllocationPercent(allocations []personAllocation) float64 {
+	var total float64
+	for _, allocation := range allocations {
+		total += allocation.Percent
+	}
+
+	return total
+}
+
+func activeAllocationsForPersonOnDate(
 	allocations []personAllocation,
 	date time.Time,
 	scope string,
 	targetProjectIDs map[string]bool,
-) float64 {
+) []personAllocation {
 	isProjectScope := scope == ScopeProject
-	var total float64
+	active := make([]personAllocation, 0, len(allocations))
 	for _, allocation := range allocations {
 		if isProjectScope && !targetProjectIDs[allocation.ProjectID] {
 			continue
@@ -380,13 +702,13 @@ func allocationPercentForPersonOnDate(
 		if !allocationAppliesToDate(allocation, date) {
 			continue
 		}
-		total += allocation.Percent
+		active = append(active, allocation)
 	}
 
-	return total
+	return active
 }
 
-func summarizeBuckets(buckets map[string]ReportBucket, scope string) []ReportBucket {
+func summarizeBuckets(buckets map[string]ReportBucket, scope string, overloadBands OverloadBands) []ReportBucket {
 	sortedKeys := make([]string, 0, len(buckets))
 	for key := range buckets {
 		sortedKeys = append(sortedKeys, key)
@@ -400,6 +722,7 @@ func summarizeBuckets(buckets map[string]ReportBucket, scope string) []ReportBuc
 		if bucket.AvailabilityHours > 0 {
 			bucket.UtilizationPct = bucket.LoadHours / bucket.AvailabilityHours * 100
 		}
+		bucket.OverloadSeverity = overloadBands.Classify(bucket.AvailabilityHours, bucket.LoadHours)
 		if scope == ScopeProject {
 			cumulativeProjectLoad += bucket.ProjectLoadHours
 			bucket.ProjectLoadHours = cumulativeProjectLoad
@@ -414,6 +737,7 @@ func summarizeBuckets(buckets map[string]ReportBucket, scope string) []ReportBuc
 		bucket.FreeHours = round2(bucket.FreeHours)
 		bucket.UtilizationPct = round2(bucket.UtilizationPct)
 		bucket.CompletionPct = round2(bucket.CompletionPct)
+		bucket.PeakLoadHours = round2(bucket.PeakLoadHours)
 		result = append(result, bucket)
 	}
 
@@ -483,11 +807,11 @@ func selectPeopleForGroupScope(
 
 	selected := make([]string, 0)
 	for _, id := range ids {
-		group, ok := groupsByID[id]
-		if !ok {
-			return nil, nil, ErrNotFound
+		memberIDs, err := GroupPersonIDs(id, groupsByID)
+		if err != nil {
+			return nil, nil, err
 		}
-		for _, memberID := range group.MemberIDs {
+		for _, memberID := range memberIDs {
 			if _, exists := personsByID[memberID]; !exists {
 				continue
 			}
@@ -555,11 +879,11 @@ func peopleForProjectAllocation(
 		}
 		return []string{targetID}
 	case AllocationTargetGroup:
-		group, ok := groupsByID[targetID]
-		if !ok {
+		memberIDs, err := GroupPersonIDs(targetID, groupsByID)
+		if err != nil {
 			return nil
 		}
-		return group.MemberIDs
+		return memberIDs
 	default:
 		return nil
 	}
@@ -593,6 +917,8 @@ func projectEstimationForScope(scope string, projects []Project, targetProjectID
 	return total
 }
 
+// periodStart returns the first day of the bucket holding date. Weeks start
+// on Monday, so the first ISO week of a year may begin in December.
 func periodStart(date time.Time, granularity string) time.Time {
 	switch granularity {
 	case GranularityDay:
@@ -605,6 +931,9 @@ func periodStart(date time.Time, granularity string) time.Time {
 		return date.AddDate(0, 0, -(weekday - 1))
 	case GranularityMonth:
 		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case GranularityQuarter:
+		quarterMonth := time.Month((int(date.Month())-1)/3*3 + 1)
+		return time.Date(date.Year(), quarterMonth, 1, 0, 0, 0, 0, time.UTC)
 	case GranularityYear:
 		return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
 	default:
@@ -649,13 +978,16 @@ func resolveAllocation(
 			endDate:   endDate,
 		}, true, nil
 	case AllocationTargetGroup:
-		group, ok := groupsByID[targetID]
-		if !ok {
+		if _, ok := groupsByID[targetID]; !ok {
 			return allocationResolution{}, false, nil
 		}
+		memberIDs, err := GroupPersonIDs(targetID, groupsByID)
+		if err != nil {
+			return allocationResolution{}, false, err
+		}
 
-		personIDs := make([]string, 0, len(group.MemberIDs))
-		for _, memberID := range group.MemberIDs {
+		personIDs := make([]string, 0, len(memberIDs))
+		for _, memberID := range memberIDs {
 			if _, exists := personsByID[memberID]; exists {
 				personIDs = append(personIDs, memberID)
 			}
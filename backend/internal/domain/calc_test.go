@@ -76,6 +76,108 @@ func TestCalculateAvailabilityLoadPersonScopeWithHolidaysAndOverrides(t *testing
 	assertBucket(t, result[2], "2026-01-03", 2, 4, -2)
 }
 
+// TestCalculateAvailabilityLoadAppliesPersonTargetedHolidays verifies the calculate availability load applies person targeted holidays scenario.
+func TestCalculateAvailabilityLoadAppliesPersonTargetedHolidays(t *testing.T) {
+	input := CalculationInput{
+		Organisation: Organisation{ID: "org-1", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		Persons: []Person{
+			{ID: "p1", OrganisationID: "org-1", EmploymentPct: 100},
+			{ID: "p2", OrganisationID: "org-1", EmploymentPct: 100},
+		},
+		OrgHolidays: []OrgHoliday{
+			{ID: "h1", OrganisationID: "org-1", Date: date20260101, Hours: 4, PersonIDs: []string{"p1"}},
+			{ID: "h2", OrganisationID: "org-1", Date: date20260102, Hours: 2},
+		},
+		Request: ReportRequest{
+			Scope:       ScopePerson,
+			FromDate:    date20260101,
+			ToDate:      date20260102,
+			Granularity: GranularityDay,
+		},
+	}
+
+	input.Request.IDs = []string{"p1"}
+	result, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	assertBucket(t, result[0], date20260101, 4, 0, 4)
+	assertBucket(t, result[1], date20260102, 6, 0, 6)
+
+	input.Request.IDs = []string{"p2"}
+	result, err = CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	assertBucket(t, result[0], date20260101, 8, 0, 8)
+	assertBucket(t, result[1], date20260102, 6, 0, 6)
+}
+
+// TestCalculateAvailabilityLoadExpandsUnavailabilityRules verifies the calculate availability load expands unavailability rules scenario.
+func TestCalculateAvailabilityLoadExpandsUnavailabilityRules(t *testing.T) {
+	input := CalculationInput{
+		Organisation: Organisation{
+			ID:           "org-1",
+			HoursPerDay:  8,
+			HoursPerWeek: 40,
+			HoursPerYear: 2080,
+		},
+		Persons: []Person{{ID: "p1", OrganisationID: "org-1", EmploymentPct: 100}},
+		Groups: []Group{{
+			ID:             "g1",
+			OrganisationID: "org-1",
+			MemberIDs:      []string{"p1"},
+		}},
+		PersonUnavailability: []PersonUnavailability{{
+			ID:             "pu1",
+			OrganisationID: "org-1",
+			PersonID:       "p1",
+			Date:           "2026-01-09",
+			Hours:          2,
+		}},
+		UnavailabilityRules: []UnavailabilityRule{
+			{
+				ID:             "r1",
+				OrganisationID: "org-1",
+				PersonID:       "p1",
+				Weekdays:       []string{"friday"},
+				Hours:          4,
+				StartDate:      date20260102,
+			},
+			{
+				ID:             "r2",
+				OrganisationID: "org-1",
+				GroupID:        "g1",
+				Weekdays:       []string{"monday"},
+				Hours:          3,
+				StartDate:      date20260101,
+				UntilDate:      "2026-01-05",
+			},
+		},
+		Request: ReportRequest{
+			Scope:       ScopePerson,
+			IDs:         []string{"p1"},
+			FromDate:    date20260101,
+			ToDate:      "2026-01-12",
+			Granularity: GranularityDay,
+		},
+	}
+
+	result, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 12 {
+		t.Fatalf("expected 12 buckets, got %d", len(result))
+	}
+
+	assertBucket(t, result[0], date20260101, 8, 0, 8)
+	assertBucket(t, result[1], date20260102, 4, 0, 4)
+	assertBucket(t, result[4], "2026-01-05", 5, 0, 5)
+	assertBucket(t, result[8], "2026-01-09", 2, 0, 2)
+	assertBucket(t, result[11], "2026-01-12", 8, 0, 8)
+}
+
 // TestCalculateAvailabilityLoadGroupScopeMonthAggregation verifies the calculate availability load group scope month aggregation scenario.
 func TestCalculateAvailabilityLoadGroupScopeMonthAggregation(t *testing.T) {
 	input := CalculationInput{
@@ -118,6 +220,270 @@ func TestCalculateAvailabilityLoadGroupScopeMonthAggregation(t *testing.T) {
 	}
 }
 
+// TestCalculateAvailabilityLoadIncludesContributors verifies the calculate availability load includes contributors scenario.
+func TestCalculateAvailabilityLoadIncludesContributors(t *testing.T) {
+	input := CalculationInput{
+		Organisation: Organisation{ID: "org-1", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		Persons: []Person{
+			{ID: "p1", OrganisationID: "org-1", EmploymentPct: 100},
+			{ID: "p2", OrganisationID: "org-1", EmploymentPct: 50},
+		},
+		Groups:   []Group{{ID: "g1", OrganisationID: "org-1", MemberIDs: []string{"p1", "p2"}}},
+		Projects: []Project{testProject(projectIDPrimary), testProject(projectIDSecondary)},
+		Allocations: []Allocation{
+			personAllocationEntry("a1", "p1", projectIDPrimary, 25, date20260101, "2026-01-10"),
+			groupAllocation("a2", "g1", projectIDSecondary, 20, "2026-01-05", date20260131),
+		},
+		Request: ReportRequest{
+			Scope:       ScopeOrganisation,
+			FromDate:    date20260101,
+			ToDate:      date20260131,
+			Granularity: GranularityMonth,
+		},
+	}
+
+	result, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 1 || result[0].Contributors != nil {
+		t.Fatalf("expected lean bucket without contributors, got %+v", result)
+	}
+
+	input.Request.IncludeContributors = true
+	result, err = CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 1 {
+		t.Fatalf(errExpectedOneBucket, len(result))
+	}
+
+	contributors := result[0].Contributors
+	if len(contributors) != 2 || contributors[0].AllocationID != "a1" || contributors[1].AllocationID != "a2" {
+		t.Fatalf("expected contributors a1 and a2, got %+v", contributors)
+	}
+	// a1: 10 days * 8h * 25%, a2: 27 days * 2 members * 8h * 20%.
+	if !approxEqual(20, contributors[0].Hours, approxTolerance) || !approxEqual(86.4, contributors[1].Hours, approxTolerance) {
+		t.Fatalf("unexpected contributor hours %+v", contributors)
+	}
+	if contributors[1].Percent != 20 {
+		t.Fatalf("expected contributor percent 20, got %v", contributors[1].Percent)
+	}
+
+	var contributedHours float64
+	for _, contributor := range contributors {
+		contributedHours += contributor.Hours
+	}
+	if !approxEqual(result[0].LoadHours, contributedHours, approxTolerance) {
+		t.Fatalf("expected contributors to sum to load %v, got %v", result[0].LoadHours, contributedHours)
+	}
+}
+
+// TestCalculateAvailabilityLoadPeakLoad verifies the calculate availability load peak load scenario.
+func TestCalculateAvailabilityLoadPeakLoad(t *testing.T) {
+	// p1 carries 25% all month and a second 100% allocation on 2026-01-15 only.
+	input := CalculationInput{
+		Organisation: Organisation{ID: "org-1", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		Persons:      []Person{{ID: "p1", OrganisationID: "org-1", EmploymentPct: 100}},
+		Projects:     []Project{testProject(projectIDPrimary), testProject(projectIDSecondary)},
+		Allocations: []Allocation{
+			personAllocationEntry("a1", "p1", projectIDPrimary, 25, date20260101, date20260131),
+			personAllocationEntry("a2", "p1", projectIDSecondary, 100, "2026-01-15", "2026-01-15"),
+		},
+		Request: ReportRequest{
+			Scope:       ScopePerson,
+			IDs:         []string{"p1"},
+			FromDate:    date20260101,
+			ToDate:      date20260131,
+			Granularity: GranularityMonth,
+		},
+	}
+
+	result, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 1 || result[0].PeakLoadHours != 0 || result[0].PeakLoadDate != "" {
+		t.Fatalf("expected lean bucket without peak load, got %+v", result)
+	}
+
+	input.Request.IncludePeakLoad = true
+	result, err = CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 1 {
+		t.Fatalf(errExpectedOneBucket, len(result))
+	}
+	// The month averages 2.26h per day, while the spike day carries 2h + 8h.
+	if !approxEqual(10, result[0].PeakLoadHours, approxTolerance) || result[0].PeakLoadDate != "2026-01-15" {
+		t.Fatalf("expected 10h peak on 2026-01-15, got %v on %q", result[0].PeakLoadHours, result[0].PeakLoadDate)
+	}
+	if average := result[0].LoadHours / 31; average >= result[0].PeakLoadHours/2 {
+		t.Fatalf("expected peak to stand out from daily average %v", average)
+	}
+
+	input.Request.Granularity = GranularityDay
+	result, err = CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if result[14].PeakLoadDate != "" {
+		t.Fatalf("expected day buckets to omit peak load, got %+v", result[14])
+	}
+}
+
+// TestCalculateAvailabilityLoadSkipEmptyBuckets verifies the calculate availability load skip empty buckets scenario.
+func TestCalculateAvailabilityLoadSkipEmptyBuckets(t *testing.T) {
+	// The weekend of 2026-01-03 and 2026-01-04 and the holiday on 2026-01-02
+	// leave no availability, and the allocation ends on 2026-01-01.
+	input := CalculationInput{
+		Organisation: Organisation{ID: "org-1", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		Persons:      []Person{{ID: "p1", OrganisationID: "org-1", EmploymentPct: 100}},
+		Projects:     []Project{testProject(projectIDPrimary)},
+		Allocations: []Allocation{
+			personAllocationEntry("a1", "p1", projectIDPrimary, 50, date20260101, date20260101),
+		},
+		OrgHolidays: []OrgHoliday{
+			{ID: "h1", OrganisationID: "org-1", Date: date20260102, Hours: 8},
+			{ID: "h2", OrganisationID: "org-1", Date: "2026-01-03", Hours: 8},
+			{ID: "h3", OrganisationID: "org-1", Date: "2026-01-04", Hours: 8},
+		},
+		Request: ReportRequest{
+			Scope:       ScopePerson,
+			IDs:         []string{"p1"},
+			FromDate:    date20260101,
+			ToDate:      "2026-01-05",
+			Granularity: GranularityDay,
+		},
+	}
+
+	result, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 5 {
+		t.Fatalf("expected 5 buckets without skip_empty, got %d", len(result))
+	}
+
+	input.Request.SkipEmpty = true
+	result, err = CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 buckets with skip_empty, got %+v", result)
+	}
+	assertBucket(t, result[0], date20260101, 8, 4, 4)
+	assertBucket(t, result[1], "2026-01-05", 8, 0, 8)
+}
+
+// TestCalculateAvailabilityLoadClassifiesOverload verifies the calculate availability load classifies overload scenario.
+func TestCalculateAvailabilityLoadClassifiesOverload(t *testing.T) {
+	input := CalculationInput{
+		Organisation: Organisation{ID: "org-1", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		Persons:      []Person{{ID: "p1", OrganisationID: "org-1", EmploymentPct: 50}},
+		Projects:     []Project{testProject(projectIDPrimary)},
+		Allocations: []Allocation{
+			personAllocationEntry("a1", "p1", projectIDPrimary, 55, date20260101, date20260101),
+			personAllocationEntry("a2", "p1", projectIDPrimary, 65, date20260102, date20260102),
+			personAllocationEntry("a3", "p1", projectIDPrimary, 120, "2026-01-03", "2026-01-03"),
+			personAllocationEntry("a4", "p1", projectIDPrimary, 40, "2026-01-04", "2026-01-04"),
+		},
+		Request: ReportRequest{
+			Scope:       ScopePerson,
+			IDs:         []string{"p1"},
+			FromDate:    date20260101,
+			ToDate:      "2026-01-04",
+			Granularity: GranularityDay,
+		},
+	}
+
+	result, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	expected := []string{OverloadMinor, OverloadModerate, OverloadSevere, ""}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d buckets, got %d", len(expected), len(result))
+	}
+	for idx, bucket := range result {
+		if bucket.OverloadSeverity != expected[idx] {
+			t.Fatalf("bucket %s: expected severity %q, got %q", bucket.PeriodStart, expected[idx], bucket.OverloadSeverity)
+		}
+	}
+
+	input.Organisation.OverloadModeratePct = 5
+	result, err = CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if result[0].OverloadSeverity != OverloadModerate {
+		t.Fatalf("expected organisation bands to apply, got %q", result[0].OverloadSeverity)
+	}
+}
+
+// TestCalculateAvailabilityLoadAnnotatesProjectMilestones verifies the calculate availability load annotates project milestones scenario.
+func TestCalculateAvailabilityLoadAnnotatesProjectMilestones(t *testing.T) {
+	primary := testProject(projectIDPrimary)
+	primary.Milestones = []ProjectMilestone{
+		{Name: "Build", Date: "2026-01-20", TargetEffortHours: 100},
+		{Name: "Design", Date: "2026-01-10", TargetEffortHours: 40},
+		{Name: "Launch", Date: "2026-02-15", TargetEffortHours: 200},
+	}
+	secondary := testProject(projectIDSecondary)
+	secondary.Milestones = []ProjectMilestone{{Name: "Other", Date: "2026-01-15", TargetEffortHours: 1}}
+
+	input := CalculationInput{
+		Organisation: Organisation{ID: "org-1", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		Persons:      []Person{{ID: "p1", OrganisationID: "org-1", EmploymentPct: 100}},
+		Projects:     []Project{primary, secondary},
+		Allocations: []Allocation{
+			personAllocationEntry("a1", "p1", projectIDPrimary, 50, date20260101, date20260131),
+		},
+		Request: ReportRequest{
+			Scope:       ScopeProject,
+			IDs:         []string{projectIDPrimary},
+			FromDate:    "2026-01-05",
+			ToDate:      date20260131,
+			Granularity: GranularityMonth,
+		},
+	}
+
+	result, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 1 {
+		t.Fatalf(errExpectedOneBucket, len(result))
+	}
+
+	milestones := result[0].Milestones
+	if len(milestones) != 2 {
+		t.Fatalf("expected two milestones inside the report window, got %+v", milestones)
+	}
+	// Load is counted from the project start: 4h per day since 2026-01-01.
+	design := milestones[0]
+	if design.Name != "Design" || design.ProjectID != projectIDPrimary || !approxEqual(40, design.CumulativeLoadHours, approxTolerance) || !design.Met {
+		t.Fatalf("expected design milestone to be met on time, got %+v", design)
+	}
+	build := milestones[1]
+	if build.Name != "Build" || !approxEqual(80, build.CumulativeLoadHours, approxTolerance) || build.Met {
+		t.Fatalf("expected build milestone to be flagged as missed, got %+v", build)
+	}
+
+	input.Request.Scope = ScopeOrganisation
+	input.Request.IDs = nil
+	result, err = CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if result[0].Milestones != nil {
+		t.Fatalf("expected no milestones outside project scope, got %+v", result[0].Milestones)
+	}
+}
+
 // TestCalculateAvailabilityLoadProjectScopeFiltersAllocationAndRange verifies the calculate availability load project scope filters allocation and range scenario.
 func TestCalculateAvailabilityLoadProjectScopeFiltersAllocationAndRange(t *testing.T) {
 	input := CalculationInput{
@@ -316,6 +682,58 @@ func TestCalculateAvailabilityLoadUsesEmploymentTimelineByDate(t *testing.T) {
 	assertBucket(t, result[1], "2026-06-01", 4, 0, 4)
 }
 
+// TestCalculateAvailabilityLoadUsesHoursBaselinesByDate verifies the calculate availability load uses hours baselines by date scenario.
+func TestCalculateAvailabilityLoadUsesHoursBaselinesByDate(t *testing.T) {
+	input := CalculationInput{
+		Organisation: Organisation{
+			ID:           "org-1",
+			HoursPerDay:  8,
+			HoursPerWeek: 40,
+			HoursPerYear: 2080,
+			HoursBaselines: []HoursBaseline{
+				{StartDate: "2027-01-01", EndDate: "2027-12-31", HoursPerDay: 6, HoursPerWeek: 30, HoursPerYear: 1560},
+			},
+		},
+		Persons:     []Person{{ID: "p1", OrganisationID: "org-1", EmploymentPct: 100}},
+		Projects:    []Project{testProject(projectIDPrimary)},
+		Allocations: []Allocation{personAllocationEntry("a1", "p1", projectIDPrimary, 50, "2026-12-01", "2027-01-31")},
+		Request: ReportRequest{
+			Scope:       ScopePerson,
+			IDs:         []string{"p1"},
+			FromDate:    "2026-12-31",
+			ToDate:      "2027-01-01",
+			Granularity: GranularityDay,
+		},
+	}
+
+	result, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(result))
+	}
+	assertBucket(t, result[0], "2026-12-31", 8, 4, 4)
+	assertBucket(t, result[1], "2027-01-01", 6, 3, 3)
+
+	input.Request.Granularity = GranularityYear
+	input.Request.FromDate = "2026-12-31"
+	input.Request.ToDate = "2027-01-01"
+	input.Request.Unit = ReportUnitFTE
+	result, err = CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 yearly buckets, got %d", len(result))
+	}
+	for _, bucket := range result {
+		if !approxEqual(1, bucket.AvailabilityHours, approxTolerance) || !approxEqual(0.5, bucket.LoadHours, approxTolerance) {
+			t.Fatalf("expected 1 FTE available and 0.5 FTE loaded in %s, got %+v", bucket.PeriodStart, bucket)
+		}
+	}
+}
+
 // TestCalculateAvailabilityLoadValidation verifies the calculate availability load validation scenario.
 func TestCalculateAvailabilityLoadValidation(t *testing.T) {
 	_, err := CalculateAvailabilityLoad(CalculationInput{
@@ -600,6 +1018,60 @@ func TestScopeSelectionHelperFunctions(t *testing.T) {
 	}
 }
 
+// TestCalculateAvailabilityLoadWeekAndQuarterBoundaries verifies that week
+// and quarter buckets split at the right days and keep calendar entries in
+// the bucket that holds their date.
+func TestCalculateAvailabilityLoadWeekAndQuarterBoundaries(t *testing.T) {
+	input := CalculationInput{
+		Organisation: Organisation{ID: "org-1", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		Persons:      []Person{{ID: "p1", OrganisationID: "org-1", EmploymentPct: 100}},
+		Projects:     []Project{testProject(projectIDPrimary)},
+		Allocations:  []Allocation{personAllocationEntry("a1", "p1", projectIDPrimary, 50, "2025-12-01", "2026-12-31")},
+		OrgHolidays:  []OrgHoliday{{ID: "h1", OrganisationID: "org-1", Date: date20260101, Hours: 8}},
+		PersonUnavailability: []PersonUnavailability{
+			{ID: "u1", OrganisationID: "org-1", PersonID: "p1", Date: "2026-04-01", Hours: 4},
+		},
+		Request: ReportRequest{
+			Scope:       ScopeOrganisation,
+			FromDate:    "2025-12-29",
+			ToDate:      "2026-01-11",
+			Granularity: GranularityWeek,
+		},
+	}
+
+	weeks, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(weeks) != 2 {
+		t.Fatalf("expected two week buckets, got %+v", weeks)
+	}
+	if weeks[0].PeriodStart != "2025-12-29" || weeks[1].PeriodStart != "2026-01-05" {
+		t.Fatalf("expected weeks to start on Monday across the year change, got %s and %s", weeks[0].PeriodStart, weeks[1].PeriodStart)
+	}
+	if weeks[1].AvailabilityHours-weeks[0].AvailabilityHours != 8 {
+		t.Fatalf("expected the New Year holiday to reduce only the first week, got %+v", weeks)
+	}
+
+	input.Request.FromDate = "2026-03-30"
+	input.Request.ToDate = "2026-04-02"
+	input.Request.Granularity = GranularityQuarter
+	quarters, err := CalculateAvailabilityLoad(input)
+	if err != nil {
+		t.Fatalf(errUnexpected, err)
+	}
+	if len(quarters) != 2 || quarters[0].PeriodStart != date20260101 || quarters[1].PeriodStart != "2026-04-01" {
+		t.Fatalf("expected first and second quarter buckets, got %+v", quarters)
+	}
+	if quarters[0].AvailabilityHours-quarters[1].AvailabilityHours != 4 {
+		t.Fatalf("expected the April unavailability to reduce only the second quarter, got %+v", quarters)
+	}
+
+	if err = ValidateGranularity(GranularityQuarter); err != nil {
+		t.Fatalf("expected quarter granularity to be valid, got %v", err)
+	}
+}
+
 func testProject(id string) Project {
 	return Project{
 		ID:                   id,
@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// CapacityHours splits capacity into its sources. HolidayHours covers
+// organisation holidays, including those that target a person.
+// AvailableHours is the contractual hours minus holidays and unavailability,
+// floored at zero, and FreeHours is AvailableHours minus AllocatedHours.
+type CapacityHours struct {
+	ContractualHours       float64 `json:"contractual_hours"`
+	HolidayHours           float64 `json:"holiday_hours"`
+	PersonUnavailableHours float64 `json:"person_unavailable_hours"`
+	GroupUnavailableHours  float64 `json:"group_unavailable_hours"`
+	AvailableHours         float64 `json:"available_hours"`
+	AllocatedHours         float64 `json:"allocated_hours"`
+	FreeHours              float64 `json:"free_hours"`
+}
+
+func (h *CapacityHours) add(other CapacityHours) {
+	h.ContractualHours += other.ContractualHours
+	h.HolidayHours += other.HolidayHours
+	h.PersonUnavailableHours += other.PersonUnavailableHours
+	h.GroupUnavailableHours += other.GroupUnavailableHours
+	h.AvailableHours += other.AvailableHours
+	h.AllocatedHours += other.AllocatedHours
+	h.FreeHours += other.FreeHours
+}
+
+func (h CapacityHours) rounded() CapacityHours {
+	return CapacityHours{
+		ContractualHours:       round2(h.ContractualHours),
+		HolidayHours:           round2(h.HolidayHours),
+		PersonUnavailableHours: round2(h.PersonUnavailableHours),
+		GroupUnavailableHours:  round2(h.GroupUnavailableHours),
+		AvailableHours:         round2(h.AvailableHours),
+		AllocatedHours:         round2(h.AllocatedHours),
+		FreeHours:              round2(h.FreeHours),
+	}
+}
+
+// PersonCapacity is the day by day capacity breakdown of one person.
+type PersonCapacity struct {
+	PersonID string              `json:"person_id"`
+	FromDate string              `json:"from_date"`
+	ToDate   string              `json:"to_date"`
+	Days     []PersonCapacityDay `json:"days"`
+}
+
+// PersonCapacityDay is one day of a person's capacity. Non-working days, by
+// schedule or at 0% employment, report zero hours throughout.
+type PersonCapacityDay struct {
+	Date    string `json:"date"`
+	Working bool   `json:"working"`
+	CapacityHours
+	Projects []PersonCapacityProject `json:"projects"`
+}
+
+// PersonCapacityProject is the load one project puts on a person on a day,
+// summed over direct and group allocations.
+type PersonCapacityProject struct {
+	ProjectID string  `json:"project_id"`
+	Hours     float64 `json:"hours"`
+}
+
+// GroupCapacity is the capacity of a group's members, nested subgroups
+// included, summed per report period.
+type GroupCapacity struct {
+	GroupID     string                `json:"group_id"`
+	FromDate    string                `json:"from_date"`
+	ToDate      string                `json:"to_date"`
+	Granularity string                `json:"granularity"`
+	Buckets     []GroupCapacityBucket `json:"buckets"`
+}
+
+// GroupCapacityBucket sums the capacity of all members over one period and
+// lists each member's share. GroupUnavailableHours counts unavailability of
+// every group a member belongs to, not only this one.
+type GroupCapacityBucket struct {
+	PeriodStart string `json:"period_start"`
+	CapacityHours
+	Members []GroupCapacityMember `json:"members"`
+}
+
+// GroupCapacityMember is one member's capacity over a bucket period.
+type GroupCapacityMember struct {
+	PersonID string `json:"person_id"`
+	CapacityHours
+}
+
+// CalculatePersonCapacity breaks down the capacity of one person for every
+// day from Request.FromDate through Request.ToDate. It applies the same
+// rules as CalculateAvailabilityLoad, so the daily figures add up to the
+// person's report buckets.
+func CalculatePersonCapacity(input CalculationInput, personID string) (PersonCapacity, error) {
+	fromDate, toDate, err := parseReportDateRange(input.Request.FromDate, input.Request.ToDate)
+	if err != nil {
+		return PersonCapacity{}, err
+	}
+	lookups, err := buildCalculationLookups(input)
+	if err != nil {
+		return PersonCapacity{}, err
+	}
+	person, ok := lookups.personsByID[personID]
+	if !ok {
+		return PersonCapacity{}, ErrNotFound
+	}
+
+	capacity := PersonCapacity{
+		PersonID: personID,
+		FromDate: fromDate.Format(DateLayout),
+		ToDate:   toDate.Format(DateLayout),
+		Days:     []PersonCapacityDay{},
+	}
+	err = iterateDateRange(fromDate, toDate, func(current time.Time) error {
+		day, dayErr := personCapacityOnDate(person, current, input.Organisation.HoursPerDayOn(current.Format(DateLayout)), lookups)
+		if dayErr != nil {
+			return dayErr
+		}
+		day.CapacityHours = day.CapacityHours.rounded()
+		for i := range day.Projects {
+			day.Projects[i].Hours = round2(day.Projects[i].Hours)
+		}
+		capacity.Days = append(capacity.Days, day)
+		return nil
+	})
+	if err != nil {
+		return PersonCapacity{}, err
+	}
+
+	return capacity, nil
+}
+
+// CalculateGroupCapacity sums the capacity of a group's members per
+// Request.Granularity period from Request.FromDate through Request.ToDate.
+func CalculateGroupCapacity(input CalculationInput, groupID string) (GroupCapacity, error) {
+	fromDate, toDate, err := parseReportDateRange(input.Request.FromDate, input.Request.ToDate)
+	if err != nil {
+		return GroupCapacity{}, err
+	}
+	if err = ValidateGranularity(input.Request.Granularity); err != nil {
+		return GroupCapacity{}, err
+	}
+	lookups, err := buildCalculationLookups(input)
+	if err != nil {
+		return GroupCapacity{}, err
+	}
+	memberIDs, err := GroupPersonIDs(groupID, lookups.groupsByID)
+	if err != nil {
+		return GroupCapacity{}, err
+	}
+	members := make([]Person, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		if person, ok := lookups.personsByID[memberID]; ok {
+			members = append(members, person)
+		}
+	}
+
+	bucketsByPeriod := map[string]*GroupCapacityBucket{}
+	err = iterateDateRange(fromDate, toDate, func(current time.Time) error {
+		periodKey := periodStart(current, input.Request.Granularity).Format(DateLayout)
+		bucket, ok := bucketsByPeriod[periodKey]
+		if !ok {
+			bucket = &GroupCapacityBucket{PeriodStart: periodKey, Members: make([]GroupCapacityMember, len(members))}
+			for i, member := range members {
+				bucket.Members[i].PersonID = member.ID
+			}
+			bucketsByPeriod[periodKey] = bucket
+		}
+		for i, member := range members {
+			day, dayErr := personCapacityOnDate(member, current, input.Organisation.HoursPerDayOn(current.Format(DateLayout)), lookups)
+			if dayErr != nil {
+				return dayErr
+			}
+			bucket.Members[i].add(day.CapacityHours)
+			bucket.add(day.CapacityHours)
+		}
+		return nil
+	})
+	if err != nil {
+		return GroupCapacity{}, err
+	}
+
+	capacity := GroupCapacity{
+		GroupID:     groupID,
+		FromDate:    fromDate.Format(DateLayout),
+		ToDate:      toDate.Format(DateLayout),
+		Granularity: input.Request.Granularity,
+		Buckets:     make([]GroupCapacityBucket, 0, len(bucketsByPeriod)),
+	}
+	for _, bucket := range bucketsByPeriod {
+		bucket.CapacityHours = bucket.CapacityHours.rounded()
+		for i := range bucket.Members {
+			bucket.Members[i].CapacityHours = bucket.Members[i].CapacityHours.rounded()
+		}
+		capacity.Buckets = append(capacity.Buckets, *bucket)
+	}
+	sort.Slice(capacity.Buckets, func(i, j int) bool {
+		return capacity.Buckets[i].PeriodStart < capacity.Buckets[j].PeriodStart
+	})
+
+	return capacity, nil
+}
+
+// personCapacityOnDate returns the unrounded capacity of a person on one day.
+func personCapacityOnDate(person Person, current time.Time, hoursPerDay float64, lookups calculationLookups) (PersonCapacityDay, error) {
+	dayKey := current.Format(DateLayout)
+	day := PersonCapacityDay{Date: dayKey, Projects: []PersonCapacityProject{}}
+	employmentPct, err := EmploymentPctOnDate(person, dayKey)
+	if err != nil {
+		return PersonCapacityDay{}, ErrValidation
+	}
+
+	baseCapacity := hoursPerDay * employmentPct / 100
+	if baseCapacity <= 0 || !personWorksOn(person.ID, current, lookups) {
+		return day, nil
+	}
+
+	personDateKey := compoundDateKey(person.ID, dayKey)
+	var groupUnavailableHours float64
+	for _, groupID := range lookups.personGroupIDs[person.ID] {
+		groupUnavailableHours += lookups.groupUnavailableHours[compoundDateKey(groupID, dayKey)]
+	}
+	availableHours := baseCapacity - unavailableHoursForPersonOnDate(person.ID, dayKey, baseCapacity, lookups)
+
+	hoursByProject := map[string]float64{}
+	var allocatedHours float64
+	activeAllocations := activeAllocationsForPersonOnDate(lookups.allocationsByPerson[person.ID], current, ScopePerson, nil)
+	for _, allocation := range activeAllocations {
+		// Allocation percent is interpreted on full-time capacity.
+		hours := hoursPerDay * allocation.Percent / 100
+		hoursByProject[allocation.ProjectID] += hours
+		allocatedHours += hours
+	}
+	for projectID, hours := range hoursByProject {
+		day.Projects = append(day.Projects, PersonCapacityProject{ProjectID: projectID, Hours: hours})
+	}
+	sort.Slice(day.Projects, func(i, j int) bool {
+		return day.Projects[i].ProjectID < day.Projects[j].ProjectID
+	})
+
+	day.Working = true
+	day.CapacityHours = CapacityHours{
+		ContractualHours:       baseCapacity,
+		HolidayHours:           lookups.orgHolidayHoursByDate[dayKey] + lookups.personHolidayHours[personDateKey],
+		PersonUnavailableHours: lookups.personUnavailableHours[personDateKey],
+		GroupUnavailableHours:  groupUnavailableHours,
+		AvailableHours:         availableHours,
+		AllocatedHours:         allocatedHours,
+		FreeHours:              availableHours - allocatedHours,
+	}
+	return day, nil
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultListLimit is the page size used when a paged request sets no limit.
+	DefaultListLimit = 50
+	// MaxListLimit caps the page size of one list request.
+	MaxListLimit     = 500
+	listCursorPrefix = "offset:"
+)
+
+// SortField orders a list by one JSON field.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ListQuery pages, sorts, and filters a list. Filters keep records whose
+// field equals one of the listed values. A zero Limit returns every record
+// after Offset.
+type ListQuery struct {
+	Limit   int
+	Offset  int
+	Sort    []SortField
+	Filters map[string][]string
+}
+
+// ListPage is one page of a list. Total counts every record that matched the
+// filters, and NextCursor is set while more records follow.
+type ListPage[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// EncodeListCursor returns the opaque cursor for the page starting at offset.
+func EncodeListCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(listCursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeListCursor returns the offset stored in a cursor from EncodeListCursor.
+func DecodeListCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("cursor is invalid: %w", ErrValidation)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), listCursorPrefix))
+	if err != nil || !strings.HasPrefix(string(raw), listCursorPrefix) || offset < 0 {
+		return 0, fmt.Errorf("cursor is invalid: %w", ErrValidation)
+	}
+	return offset, nil
+}
+
+// ListFields returns the JSON names of the fields of T that lists can be
+// sorted and filtered by. Only scalar and time fields qualify.
+func ListFields[T any]() map[string]bool {
+	fields := map[string]bool{}
+	for name := range listFieldIndexes(reflect.TypeOf((*T)(nil)).Elem()) {
+		fields[name] = true
+	}
+	return fields
+}
+
+var listTimeType = reflect.TypeOf(time.Time{})
+
+func listFieldIndexes(recordType reflect.Type) map[string]int {
+	indexes := map[string]int{}
+	for index := 0; index < recordType.NumField(); index++ {
+		field := recordType.Field(index)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case fieldType == listTimeType, fieldType.Kind() == reflect.String, fieldType.Kind() == reflect.Bool,
+			fieldType.Kind() >= reflect.Int && fieldType.Kind() <= reflect.Float64:
+			indexes[name] = index
+		}
+	}
+	return indexes
+}
+
+// PageList filters, sorts, and pages items. Records with equal sort keys keep
+// their order in items so pages stay stable. Unknown sort or filter fields are
+// a validation error.
+func PageList[T any](items []T, query ListQuery) (ListPage[T], error) {
+	if query.Limit < 0 || query.Limit > MaxListLimit {
+		return ListPage[T]{}, fmt.Errorf("limit must be between 1 and %d: %w", MaxListLimit, ErrValidation)
+	}
+	if query.Offset < 0 {
+		return ListPage[T]{}, fmt.Errorf("offset must not be negative: %w", ErrValidation)
+	}
+	indexes := listFieldIndexes(reflect.TypeOf((*T)(nil)).Elem())
+	for _, field := range query.Sort {
+		if _, ok := indexes[field.Field]; !ok {
+			return ListPage[T]{}, fmt.Errorf("cannot sort by %q: %w", field.Field, ErrValidation)
+		}
+	}
+	for name := range query.Filters {
+		if _, ok := indexes[name]; !ok {
+			return ListPage[T]{}, fmt.Errorf("cannot filter by %q: %w", name, ErrValidation)
+		}
+	}
+
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		value := reflect.ValueOf(item)
+		keep := true
+		for name, accepted := range query.Filters {
+			if !slices.Contains(accepted, listFieldText(value.Field(indexes[name]))) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			matched = append(matched, item)
+		}
+	}
+
+	slices.SortStableFunc(matched, func(left, right T) int {
+		leftValue, rightValue := reflect.ValueOf(left), reflect.ValueOf(right)
+		for _, field := range query.Sort {
+			order := compareListField(leftValue.Field(indexes[field.Field]), rightValue.Field(indexes[field.Field]))
+			if field.Descending {
+				order = -order
+			}
+			if order != 0 {
+				return order
+			}
+		}
+		return 0
+	})
+
+	page := ListPage[T]{Total: len(matched), Limit: query.Limit, Offset: query.Offset}
+	start := min(query.Offset, len(matched))
+	end := len(matched)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, len(matched))
+	}
+	page.Items = matched[start:end]
+	if end < len(matched) {
+		page.NextCursor = EncodeListCursor(end)
+	}
+	return page, nil
+}
+
+// listFieldText renders a field the way it appears in JSON, so filters
+// compare against the values clients see.
+func listFieldText(value reflect.Value) string {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	switch {
+	case value.Type() == listTimeType:
+		moment, _ := value.Interface().(time.Time)
+		return moment.Format(time.RFC3339Nano)
+	case value.Kind() == reflect.String:
+		return value.String()
+	case value.Kind() == reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case value.CanInt():
+		return strconv.FormatInt(value.Int(), 10)
+	default:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	}
+}
+
+// compareListField orders two values of one field. Missing values sort
+// first and text compares without regard to case.
+func compareListField(left, right reflect.Value) int {
+	if left.Kind() == reflect.Pointer {
+		switch {
+		case left.IsNil() && right.IsNil():
+			return 0
+		case left.IsNil():
+			return -1
+		case right.IsNil():
+			return 1
+		}
+		left, right = left.Elem(), right.Elem()
+	}
+	switch {
+	case left.Type() == listTimeType:
+		leftTime, _ := left.Interface().(time.Time)
+		rightTime, _ := right.Interface().(time.Time)
+		return leftTime.Compare(rightTime)
+	case left.Kind() == reflect.String:
+		if order := strings.Compare(strings.ToLower(left.String()), strings.ToLower(right.String())); order != 0 {
+			return order
+		}
+		return strings.Compare(left.String(), right.String())
+	case left.Kind() == reflect.Bool:
+		return cmp.Compare(boolRank(left.Bool()), boolRank(right.Bool()))
+	case left.CanInt():
+		return cmp.Compare(left.Int(), right.Int())
+	default:
+		return cmp.Compare(left.Float(), right.Float())
+	}
+}
+
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
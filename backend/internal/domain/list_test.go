@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestPageList verifies filtering, sorting, and paging of a list.
+func TestPageList(t *testing.T) {
+	early := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	late := early.AddDate(0, 1, 0)
+	persons := []Person{
+		{ID: "person_1", Name: "bob", EmploymentPct: 80, CreatedAt: late},
+		{ID: "person_2", Name: "Alice", EmploymentPct: 100, CreatedAt: early},
+		{ID: "person_3", Name: "Carol", EmploymentPct: 80, CreatedAt: early},
+	}
+
+	page, err := PageList(persons, ListQuery{Limit: 2, Sort: []SortField{{Field: "name"}}})
+	if err != nil {
+		t.Fatalf("page persons: %v", err)
+	}
+	if page.Total != 3 || len(page.Items) != 2 || page.Items[0].ID != "person_2" || page.Items[1].ID != "person_1" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	offset, err := DecodeListCursor(page.NextCursor)
+	if err != nil || offset != 2 {
+		t.Fatalf("expected cursor for offset 2, got %d err=%v", offset, err)
+	}
+
+	page, err = PageList(persons, ListQuery{
+		Sort:    []SortField{{Field: "created_at"}, {Field: "id", Descending: true}},
+		Filters: map[string][]string{"employment_pct": {"80"}},
+	})
+	if err != nil {
+		t.Fatalf("page filtered persons: %v", err)
+	}
+	if page.Total != 2 || page.Items[0].ID != "person_3" || page.Items[1].ID != "person_1" || page.NextCursor != "" {
+		t.Fatalf("unexpected filtered page: %+v", page)
+	}
+
+	for _, query := range []ListQuery{
+		{Sort: []SortField{{Field: "employment_changes"}}},
+		{Filters: map[string][]string{"shoe_size": {"42"}}},
+		{Limit: MaxListLimit + 1},
+		{Offset: -1},
+	} {
+		if _, err = PageList(persons, query); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected validation error for %+v, got %v", query, err)
+		}
+	}
+	for _, cursor := range []string{"%%%", EncodeListCursor(-1)} {
+		if _, err = DecodeListCursor(cursor); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected invalid cursor %q to fail, got %v", cursor, err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Operation kinds kept in the undo history.
+const (
+	OperationAllocationCreated = "allocation.created"
+	OperationAllocationUpdated = "allocation.updated"
+	OperationAllocationEnded   = "allocation.ended"
+	OperationAllocationDeleted = "allocation.deleted"
+)
+
+// Operation is a recent change a user can undo.
+type Operation struct {
+	Kind         string    `json:"kind"`
+	ActorID      string    `json:"actor_id"`
+	AllocationID string    `json:"allocation_id"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// UndoResult reports the operation that was undone and the allocation as it
+// is afterwards. Allocation is nil when undoing removed it.
+type UndoResult struct {
+	Operation  Operation   `json:"operation"`
+	Allocation *Allocation `json:"allocation,omitempty"`
+}
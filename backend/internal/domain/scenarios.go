@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Scenario is a named sandbox copy of an organisation's allocations.
+// Allocations are created, updated, and deleted inside it without touching
+// live data, reports can run against it, and applying it writes its changes
+// back.
+type Scenario struct {
+	ID             string       `json:"id"`
+	OrganisationID string       `json:"organisation_id"`
+	Name           string       `json:"name"`
+	Allocations    []Allocation `json:"allocations"`
+	// BaseVersions holds the version of every live allocation when the
+	// scenario was created, keyed by allocation ID. An allocation the
+	// scenario changes must still have that version when it is applied.
+	BaseVersions map[string]int64 `json:"base_versions"`
+	// AppliedAt is set once the scenario has been applied. An applied
+	// scenario can still be read and reported on but no longer changed.
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Version   int64      `json:"version"`
+}
+
+// ScenarioAllocationIDPrefix starts the IDs of allocations created inside a
+// scenario. They get stored IDs when the scenario is applied.
+const ScenarioAllocationIDPrefix = "scenario_allocation_"
+
+// Changes compares the scenario's allocations with the live allocations it
+// was cloned from. An allocation is updated when its version moved on inside
+// the scenario, created when it has no base version, and deleted when a base
+// allocation is no longer in the scenario.
+func (s Scenario) Changes() ScenarioChanges {
+	changes := ScenarioChanges{Created: []Allocation{}, Updated: []Allocation{}, DeletedIDs: []string{}}
+	kept := make(map[string]bool, len(s.Allocations))
+	for _, allocation := range s.Allocations {
+		baseVersion, cloned := s.BaseVersions[allocation.ID]
+		switch {
+		case !cloned:
+			changes.Created = append(changes.Created, allocation)
+		case allocation.Version != baseVersion:
+			changes.Updated = append(changes.Updated, allocation)
+		}
+		kept[allocation.ID] = true
+	}
+	for id := range s.BaseVersions {
+		if !kept[id] {
+			changes.DeletedIDs = append(changes.DeletedIDs, id)
+		}
+	}
+	sort.Strings(changes.DeletedIDs)
+	return changes
+}
+
+// ScenarioChanges lists the allocations applying a scenario creates, updates,
+// and deletes in the live data.
+type ScenarioChanges struct {
+	Created    []Allocation `json:"created"`
+	Updated    []Allocation `json:"updated"`
+	DeletedIDs []string     `json:"deleted_ids"`
+}
+
+// Empty reports whether applying the changes would leave live data as is.
+func (c ScenarioChanges) Empty() bool {
+	return len(c.Created) == 0 && len(c.Updated) == 0 && len(c.DeletedIDs) == 0
+}
+
+// ScenarioSummary describes a scenario without its allocations.
+type ScenarioSummary struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	AllocationCount int        `json:"allocation_count"`
+	AppliedAt       *time.Time `json:"applied_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Version         int64      `json:"version"`
+}
+
+// Summary returns the scenario without its allocations.
+func (s Scenario) Summary() ScenarioSummary {
+	return ScenarioSummary{
+		ID:              s.ID,
+		Name:            s.Name,
+		AllocationCount: len(s.Allocations),
+		AppliedAt:       s.AppliedAt,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+		Version:         s.Version,
+	}
+}
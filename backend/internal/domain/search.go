@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Entity types a search can return.
+const (
+	SearchEntityPerson  = "person"
+	SearchEntityProject = "project"
+	SearchEntityGroup   = "group"
+)
+
+// SearchResult is one record matching a search query. Snippet holds the
+// matching text HTML escaped, with the matched parts wrapped in <mark> tags.
+type SearchResult struct {
+	EntityType string `json:"entity_type"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Snippet    string `json:"snippet"`
+}
+
+// SearchIndex maps the words in the names of persons, projects, and groups to
+// the records that hold them. Projects are also found by their milestone
+// names, and archived projects are left out. An index is a snapshot, so it
+// has to be built again after the records change.
+type SearchIndex struct {
+	documents []searchDocument
+	// words is sorted so all words sharing a prefix sit next to each other.
+	words    []string
+	postings map[string][]int
+}
+
+type searchDocument struct {
+	entityType string
+	id         string
+	name       string
+	// texts holds the name first and then any other searchable text.
+	texts []string
+}
+
+// searchEntityRank orders results of equal relevance by entity type.
+var searchEntityRank = map[string]int{
+	SearchEntityPerson:  0,
+	SearchEntityProject: 1,
+	SearchEntityGroup:   2,
+}
+
+// NewSearchIndex builds an index over the given records.
+func NewSearchIndex(persons []Person, projects []Project, groups []Group) SearchIndex {
+	documents := make([]searchDocument, 0, len(persons)+len(projects)+len(groups))
+	for _, person := range persons {
+		documents = append(documents, searchDocument{
+			entityType: SearchEntityPerson, id: person.ID, name: person.Name, texts: []string{person.Name},
+		})
+	}
+	for _, project := range projects {
+		if project.ArchivedAt != nil {
+			continue
+		}
+		texts := []string{project.Name}
+		for _, milestone := range project.Milestones {
+			texts = append(texts, milestone.Name)
+		}
+		documents = append(documents, searchDocument{
+			entityType: SearchEntityProject, id: project.ID, name: project.Name, texts: texts,
+		})
+	}
+	for _, group := range groups {
+		documents = append(documents, searchDocument{
+			entityType: SearchEntityGroup, id: group.ID, name: group.Name, texts: []string{group.Name},
+		})
+	}
+
+	index := SearchIndex{documents: documents, postings: map[string][]int{}}
+	for position, document := range documents {
+		for _, text := range document.texts {
+			for _, word := range searchWords(text) {
+				postings := index.postings[word]
+				if len(postings) > 0 && postings[len(postings)-1] == position {
+					continue
+				}
+				if len(postings) == 0 {
+					index.words = append(index.words, word)
+				}
+				index.postings[word] = append(postings, position)
+			}
+		}
+	}
+	sort.Strings(index.words)
+	return index
+}
+
+// Search returns up to limit records holding a word that starts with each
+// word of query, ignoring case. Records whose name matches every query word
+// come first, then persons before projects before groups, then by name. A
+// query without words matches nothing.
+func (index SearchIndex) Search(query string, limit int) []SearchResult {
+	terms := searchWords(query)
+	if len(terms) == 0 || limit <= 0 {
+		return []SearchResult{}
+	}
+
+	var matched map[int]bool
+	for _, term := range terms {
+		found := index.documentsWithPrefix(term)
+		if matched != nil {
+			for position := range matched {
+				if !found[position] {
+					delete(matched, position)
+				}
+			}
+		} else {
+			matched = found
+		}
+		if len(matched) == 0 {
+			return []SearchResult{}
+		}
+	}
+
+	type rankedResult struct {
+		result      SearchResult
+		nameMatches bool
+	}
+	ranked := make([]rankedResult, 0, len(matched))
+	for position := range matched {
+		document := index.documents[position]
+		snippetText := document.texts[0]
+		nameMatches := countMatchedTerms(snippetText, terms) == len(terms)
+		if !nameMatches {
+			best := -1
+			for _, text := range document.texts {
+				if count := countMatchedTerms(text, terms); count > best {
+					best = count
+					snippetText = text
+				}
+			}
+		}
+		ranked = append(ranked, rankedResult{
+			result: SearchResult{
+				EntityType: document.entityType,
+				ID:         document.id,
+				Name:       document.name,
+				Snippet:    highlightSearchTerms(snippetText, terms),
+			},
+			nameMatches: nameMatches,
+		})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		left, right := ranked[i], ranked[j]
+		if left.nameMatches != right.nameMatches {
+			return left.nameMatches
+		}
+		if left.result.EntityType != right.result.EntityType {
+			return searchEntityRank[left.result.EntityType] < searchEntityRank[right.result.EntityType]
+		}
+		leftName, rightName := strings.ToLower(left.result.Name), strings.ToLower(right.result.Name)
+		if leftName != rightName {
+			return leftName < rightName
+		}
+		return left.result.ID < right.result.ID
+	})
+
+	results := make([]SearchResult, 0, min(limit, len(ranked)))
+	for _, entry := range ranked[:min(limit, len(ranked))] {
+		results = append(results, entry.result)
+	}
+	return results
+}
+
+func (index SearchIndex) documentsWithPrefix(prefix string) map[int]bool {
+	found := map[int]bool{}
+	for position := sort.SearchStrings(index.words, prefix); position < len(index.words); position++ {
+		word := index.words[position]
+		if !strings.HasPrefix(word, prefix) {
+			break
+		}
+		for _, document := range index.postings[word] {
+			found[document] = true
+		}
+	}
+	return found
+}
+
+// searchWords splits text into lower-case runs of letters and digits.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isNotSearchRune)
+}
+
+func isNotSearchRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func countMatchedTerms(text string, terms []string) int {
+	words := searchWords(text)
+	count := 0
+	for _, term := range terms {
+		for _, word := range words {
+			if strings.HasPrefix(word, term) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// highlightSearchTerms escapes text and marks the longest query word each
+// word of text starts with.
+func highlightSearchTerms(text string, terms []string) string {
+	var out strings.Builder
+	rest := text
+	for rest != "" {
+		start := strings.IndexFunc(rest, func(r rune) bool { return !isNotSearchRune(r) })
+		if start < 0 {
+			out.WriteString(html.EscapeString(rest))
+			break
+		}
+		out.WriteString(html.EscapeString(rest[:start]))
+		rest = rest[start:]
+		end := strings.IndexFunc(rest, isNotSearchRune)
+		if end < 0 {
+			end = len(rest)
+		}
+		word := rest[:end]
+		rest = rest[end:]
+
+		marked := 0
+		lowerWord := strings.ToLower(word)
+		for _, term := range terms {
+			if strings.HasPrefix(lowerWord, term) {
+				marked = max(marked, utf8.RuneCountInString(term))
+			}
+		}
+		if marked == 0 {
+			out.WriteString(html.EscapeString(word))
+			continue
+		}
+		split := len(word)
+		if runes := []rune(word); marked < len(runes) {
+			split = len(string(runes[:marked]))
+		}
+		out.WriteString("<mark>")
+		out.WriteString(html.EscapeString(word[:split]))
+		out.WriteString("</mark>")
+		out.WriteString(html.EscapeString(word[split:]))
+	}
+	return out.String()
+}
@@ -2,6 +2,9 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"time"
 )
@@ -17,6 +20,10 @@ const (
 	RoleOrgAdmin = "org_admin"
 	// RoleOrgUser grants standard organisation user permissions.
 	RoleOrgUser = "org_user"
+	// RoleOrgPlanner grants read access plus changes to allocations,
+	// scenarios, and person and group unavailability. It cannot manage the
+	// organisation, persons, projects, or groups.
+	RoleOrgPlanner = "org_planner"
 )
 
 const (
@@ -40,14 +47,67 @@ const (
 const (
 	// GranularityDay groups report output by day.
 	GranularityDay = "day"
-	// GranularityWeek groups report output by week.
+	// GranularityWeek groups report output by ISO week, starting on Monday.
 	GranularityWeek = "week"
 	// GranularityMonth groups report output by month.
 	GranularityMonth = "month"
+	// GranularityQuarter groups report output by calendar quarter.
+	GranularityQuarter = "quarter"
 	// GranularityYear groups report output by year.
 	GranularityYear = "year"
 )
 
+const (
+	// ReportUnitHours reports availability and load in hours.
+	ReportUnitHours = "hours"
+	// ReportUnitFTE reports availability and load in full-time equivalents.
+	ReportUnitFTE = "fte"
+)
+
+const (
+	// SnapNone keeps allocation dates as submitted.
+	SnapNone = "none"
+	// SnapWeek widens allocation dates to Monday through Sunday.
+	SnapWeek = "week"
+	// SnapMonth widens allocation dates to whole calendar months.
+	SnapMonth = "month"
+)
+
+// percentStepTolerance absorbs floating point error when comparing percents
+// against a step.
+const percentStepTolerance = 1e-9
+
+// EmploymentPctDecimals is the number of decimal places an employment
+// percentage may carry, enough for contracts such as 37.5% or 33.33%.
+const EmploymentPctDecimals = 2
+
+const (
+	// PercentStepReject rejects allocation percents that are not a multiple of the step.
+	PercentStepReject = "reject"
+	// PercentStepSnap rounds allocation percents to the nearest multiple of the step.
+	PercentStepSnap = "snap"
+)
+
+const (
+	// ReconcileReport lists out-of-range allocations without changing them.
+	ReconcileReport = "report"
+	// ReconcileClip trims out-of-range allocations to the project range.
+	ReconcileClip = "clip"
+)
+
+const (
+	// OverloadMinor marks load slightly above availability.
+	OverloadMinor = "minor"
+	// OverloadModerate marks load at or above the moderate band.
+	OverloadModerate = "moderate"
+	// OverloadSevere marks load at or above the severe band.
+	OverloadSevere = "severe"
+	// DefaultOverloadModeratePct is the default overload percentage for the moderate band.
+	DefaultOverloadModeratePct = 25.0
+	// DefaultOverloadSeverePct is the default overload percentage for the severe band.
+	DefaultOverloadSeverePct = 100.0
+)
+
 var (
 	// ErrValidation reports invalid input data.
 	ErrValidation = errors.New("validation failed")
@@ -55,17 +115,455 @@ var (
 	ErrForbidden = errors.New("forbidden")
 	// ErrNotFound reports a missing resource.
 	ErrNotFound = errors.New("not found")
+	// ErrReadOnly reports a write to an organisation in read-only maintenance mode.
+	ErrReadOnly = errors.New("organisation is read-only")
+	// ErrOutsideWriteWindow reports a write outside the organisation's write window.
+	ErrOutsideWriteWindow = errors.New("outside the organisation's write window")
+	// ErrConflict reports a write that lost against a concurrent save of the same data.
+	ErrConflict = errors.New("the data was changed by another request, reload and retry")
 )
 
+// ProjectRangeError reports allocation dates outside the project window.
+// It wraps ErrValidation and carries the valid range for clients.
+type ProjectRangeError struct {
+	ProjectStart string
+	ProjectEnd   string
+}
+
+// Error describes the valid project date range.
+func (e ProjectRangeError) Error() string {
+	if e.ProjectEnd == "" {
+		return "allocation dates must fall within open-ended project range starting " + e.ProjectStart
+	}
+	return "allocation dates must fall within project range " + e.ProjectStart + " to " + e.ProjectEnd
+}
+
+// Unwrap exposes ErrValidation for errors.Is checks.
+func (e ProjectRangeError) Unwrap() error {
+	return ErrValidation
+}
+
+// QuotaError reports a create refused because the organisation already holds
+// as many records of one kind as its quota allows. It wraps ErrValidation.
+type QuotaError struct {
+	Resource string
+	Limit    int
+}
+
+// Error names the quota the organisation has reached.
+func (e QuotaError) Error() string {
+	return fmt.Sprintf("organisation has reached its limit of %d %s", e.Limit, e.Resource)
+}
+
+// Unwrap exposes ErrValidation for errors.Is checks.
+func (e QuotaError) Unwrap() error {
+	return ErrValidation
+}
+
+// DeleteDependents lists the records that still reference a resource and
+// would be removed together with it.
+type DeleteDependents struct {
+	AllocationIDs           []string `json:"allocation_ids,omitempty"`
+	PersonUnavailabilityIDs []string `json:"person_unavailability_ids,omitempty"`
+	GroupUnavailabilityIDs  []string `json:"group_unavailability_ids,omitempty"`
+	UnavailabilityRuleIDs   []string `json:"unavailability_rule_ids,omitempty"`
+}
+
+// Empty reports whether no record depends on the resource.
+func (d DeleteDependents) Empty() bool {
+	return len(d.AllocationIDs) == 0 && len(d.PersonUnavailabilityIDs) == 0 && len(d.GroupUnavailabilityIDs) == 0 &&
+		len(d.UnavailabilityRuleIDs) == 0
+}
+
+// DependentsError reports a delete refused because other records still
+// reference the resource. It wraps ErrConflict and carries the dependents
+// so clients can show them or retry with a cascading delete.
+type DependentsError struct {
+	Resource   string
+	Dependents DeleteDependents
+}
+
+// Error names the resource that still has dependents.
+func (e DependentsError) Error() string {
+	return e.Resource + " still has dependent records, delete them first or pass cascade=true"
+}
+
+// Unwrap exposes ErrConflict for errors.Is checks.
+func (e DependentsError) Unwrap() error {
+	return ErrConflict
+}
+
 // Organisation describes an organisation and its working-time baselines.
 type Organisation struct {
-	ID           string    `json:"id"`
-	Name         string    `json:"name"`
-	HoursPerDay  float64   `json:"hours_per_day"`
-	HoursPerWeek float64   `json:"hours_per_week"`
-	HoursPerYear float64   `json:"hours_per_year"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	HoursPerDay  float64 `json:"hours_per_day"`
+	HoursPerWeek float64 `json:"hours_per_week"`
+	HoursPerYear float64 `json:"hours_per_year"`
+	// EnforceMembershipAllocationLimit rejects group membership changes that
+	// push a new member beyond the daily allocation limit.
+	EnforceMembershipAllocationLimit bool `json:"enforce_membership_allocation_limit,omitempty"`
+	// SnapAllocationDatesTo widens allocation dates to period boundaries on write.
+	SnapAllocationDatesTo string `json:"snap_allocation_dates_to,omitempty"`
+	// RequireEmploymentForAllocations rejects person allocations whose whole
+	// window falls in months where the person's employment is 0%.
+	RequireEmploymentForAllocations bool `json:"require_employment_for_allocations,omitempty"`
+	// RejectAllocationsOverProjectEffort rejects allocations that would commit
+	// more hours to a project than its estimated effort.
+	RejectAllocationsOverProjectEffort bool `json:"reject_allocations_over_project_effort,omitempty"`
+	// RejectOverEmployment rejects allocations that push a person's combined
+	// load above their employment percentage on any day.
+	RejectOverEmployment bool `json:"reject_over_employment,omitempty"`
+	// EndAllocationsOnZeroEmployment ends a person's allocations when their
+	// employment drops to 0% from a month onward for good.
+	EndAllocationsOnZeroEmployment bool `json:"end_allocations_on_zero_employment,omitempty"`
+	// AllocationPercentStep constrains allocation percents to multiples of the
+	// step. Zero disables the constraint. AllocationPercentStepMode chooses
+	// whether off-step percents are rejected or snapped, defaulting to reject.
+	AllocationPercentStep     float64 `json:"allocation_percent_step,omitempty"`
+	AllocationPercentStepMode string  `json:"allocation_percent_step_mode,omitempty"`
+	// ReadOnly freezes the organisation for maintenance. Reads and reports
+	// keep working while every create, update, and delete is rejected.
+	ReadOnly bool `json:"read_only,omitempty"`
+	// WriteWindow limits changes to certain weekdays and hours in the
+	// organisation's time zone. Nil allows changes at any time.
+	WriteWindow *WriteWindow `json:"write_window,omitempty"`
+	// WorkSchedules are named working-day patterns that persons can be
+	// assigned. A person without one works every day.
+	WorkSchedules []WorkSchedule `json:"work_schedules,omitempty"`
+	// HoursBaselines replace HoursPerDay, HoursPerWeek, and HoursPerYear
+	// between their start and end dates, such as for one fiscal year. Dates
+	// outside every baseline use the organisation's own hours.
+	HoursBaselines []HoursBaseline `json:"hours_baselines,omitempty"`
+	// Timezone is an IANA zone name used to resolve now-relative dates such as
+	// the current month. Stored dates stay date-only. Empty means UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// OverloadModeratePct and OverloadSeverePct set how far load must exceed
+	// availability, in percent, before a report bucket is classified moderate
+	// or severe. Zero keeps the defaults.
+	OverloadModeratePct float64   `json:"overload_moderate_pct,omitempty"`
+	OverloadSeverePct   float64   `json:"overload_severe_pct,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	// Version grows by one with every stored change. Updates that send a
+	// version other than the stored one fail with ErrConflict, and 0 skips
+	// the check.
+	Version int64 `json:"version"`
+}
+
+// Location returns the organisation's time zone, defaulting to UTC.
+func (o Organisation) Location() (*time.Location, error) {
+	return LoadTimezone(o.Timezone)
+}
+
+// LoadTimezone resolves an IANA zone name. An empty name resolves to UTC.
+func LoadTimezone(name string) (*time.Location, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("timezone %q is not a valid IANA time zone name: %w", trimmed, ErrValidation)
+	}
+	return location, nil
+}
+
+// CurrentMonthRange returns the first and last date of the month containing
+// now in the given location.
+func CurrentMonthRange(now time.Time, location *time.Location) (fromDate string, toDate string) {
+	local := now.In(location)
+	first := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	return first.Format(DateLayout), last.Format(DateLayout)
+}
+
+// WriteWindow describes when an organisation accepts changes. Reads are
+// never restricted.
+type WriteWindow struct {
+	// Weekdays lists lowercase English day names such as "monday". Empty
+	// allows every day.
+	Weekdays []string `json:"weekdays,omitempty"`
+	// Start and End are HH:MM times of day. Start is included and End is
+	// excluded, and Start must come before End.
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+const writeWindowTimeLayout = "15:04"
+
+// Normalized returns the window with trimmed times and lowercase, trimmed weekdays.
+func (w WriteWindow) Normalized() WriteWindow {
+	weekdays := make([]string, 0, len(w.Weekdays))
+	for _, weekday := range w.Weekdays {
+		weekdays = append(weekdays, strings.ToLower(strings.TrimSpace(weekday)))
+	}
+	if len(weekdays) == 0 {
+		weekdays = nil
+	}
+	return WriteWindow{
+		Weekdays: weekdays,
+		Start:    strings.TrimSpace(w.Start),
+		End:      strings.TrimSpace(w.End),
+	}
+}
+
+// Validate checks the weekday names and the time range of a normalized window.
+func (w WriteWindow) Validate() error {
+	for _, weekday := range w.Weekdays {
+		if _, ok := parseWeekday(weekday); !ok {
+			return ErrValidation
+		}
+	}
+	start, err := time.Parse(writeWindowTimeLayout, w.Start)
+	if err != nil {
+		return ErrValidation
+	}
+	end, err := time.Parse(writeWindowTimeLayout, w.End)
+	if err != nil {
+		return ErrValidation
+	}
+	if !start.Before(end) {
+		return ErrValidation
+	}
+	return nil
+}
+
+// Allows reports whether the window admits a change at local, which must
+// already be in the organisation's time zone.
+func (w WriteWindow) Allows(local time.Time) bool {
+	if len(w.Weekdays) > 0 {
+		allowedDay := false
+		for _, name := range w.Weekdays {
+			if weekday, ok := parseWeekday(name); ok && weekday == local.Weekday() {
+				allowedDay = true
+				break
+			}
+		}
+		if !allowedDay {
+			return false
+		}
+	}
+	start, startErr := time.Parse(writeWindowTimeLayout, w.Start)
+	end, endErr := time.Parse(writeWindowTimeLayout, w.End)
+	if startErr != nil || endErr != nil {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	return minute >= start.Hour()*60+start.Minute() && minute < end.Hour()*60+end.Minute()
+}
+
+// WorkSchedule is a named working-day pattern, such as a nine-day fortnight,
+// that repeats from CycleStart.
+type WorkSchedule struct {
+	Name string `json:"name"`
+	// WorkingDays holds one entry per day of the cycle, starting on a Monday.
+	// The cycle spans one to four whole weeks and needs a working day.
+	WorkingDays []bool `json:"working_days"`
+	// CycleStart is the Monday on which the first cycle begins. Empty anchors
+	// the cycle at DefaultWorkScheduleCycleStart.
+	CycleStart string `json:"cycle_start,omitempty"`
+}
+
+// DefaultWorkScheduleCycleStart anchors work schedules without a cycle start.
+const DefaultWorkScheduleCycleStart = "1970-01-05"
+
+const maxWorkScheduleWeeks = 4
+
+// Normalized returns the schedule with a trimmed name and cycle start.
+func (w WorkSchedule) Normalized() WorkSchedule {
+	return WorkSchedule{
+		Name:        strings.TrimSpace(w.Name),
+		WorkingDays: append([]bool(nil), w.WorkingDays...),
+		CycleStart:  strings.TrimSpace(w.CycleStart),
+	}
+}
+
+// Validate checks the name, cycle length, and cycle start of a normalized schedule.
+func (w WorkSchedule) Validate() error {
+	if err := ValidateName(w.Name); err != nil {
+		return ErrValidation
+	}
+	days := len(w.WorkingDays)
+	if days == 0 || days%7 != 0 || days > 7*maxWorkScheduleWeeks {
+		return ErrValidation
+	}
+	if !slices.Contains(w.WorkingDays, true) {
+		return ErrValidation
+	}
+	start, err := w.cycleStart()
+	if err != nil || start.Weekday() != time.Monday {
+		return ErrValidation
+	}
+	return nil
+}
+
+// WorksOn reports whether date, a UTC calendar date, is a working day.
+func (w WorkSchedule) WorksOn(date time.Time) bool {
+	start, err := w.cycleStart()
+	if err != nil || len(w.WorkingDays) == 0 {
+		return true
+	}
+	offset := int(date.Sub(start).Hours()/24) % len(w.WorkingDays)
+	if offset < 0 {
+		offset += len(w.WorkingDays)
+	}
+	return w.WorkingDays[offset]
+}
+
+func (w WorkSchedule) cycleStart() (time.Time, error) {
+	if w.CycleStart == "" {
+		return time.Parse(DateLayout, DefaultWorkScheduleCycleStart)
+	}
+	return time.Parse(DateLayout, w.CycleStart)
+}
+
+// WorkSchedule returns the organisation's schedule with the given name,
+// ignoring case.
+func (o Organisation) WorkSchedule(name string) (WorkSchedule, bool) {
+	name = strings.TrimSpace(name)
+	for _, schedule := range o.WorkSchedules {
+		if strings.EqualFold(schedule.Name, name) {
+			return schedule, true
+		}
+	}
+	return WorkSchedule{}, false
+}
+
+// HoursBaseline holds the organisation's working hours from StartDate
+// through EndDate. An empty EndDate keeps the baseline in effect onward.
+type HoursBaseline struct {
+	StartDate    string  `json:"start_date"`
+	EndDate      string  `json:"end_date,omitempty"`
+	HoursPerDay  float64 `json:"hours_per_day"`
+	HoursPerWeek float64 `json:"hours_per_week"`
+	HoursPerYear float64 `json:"hours_per_year"`
+}
+
+// Normalized returns the baseline with trimmed dates.
+func (b HoursBaseline) Normalized() HoursBaseline {
+	b.StartDate = strings.TrimSpace(b.StartDate)
+	b.EndDate = strings.TrimSpace(b.EndDate)
+	return b
+}
+
+// Validate checks the dates and hours of a normalized baseline.
+func (b HoursBaseline) Validate() error {
+	if _, err := ValidateDate(b.StartDate); err != nil {
+		return ErrValidation
+	}
+	if b.EndDate != "" {
+		if _, err := ValidateDate(b.EndDate); err != nil {
+			return ErrValidation
+		}
+		if b.EndDate < b.StartDate {
+			return ErrValidation
+		}
+	}
+	for _, hours := range []float64{b.HoursPerDay, b.HoursPerWeek, b.HoursPerYear} {
+		if math.IsNaN(hours) || math.IsInf(hours, 0) || hours <= 0 {
+			return ErrValidation
+		}
+	}
+	return nil
+}
+
+// covers reports whether date, in YYYY-MM-DD form, falls in the baseline.
+func (b HoursBaseline) covers(date string) bool {
+	return date >= b.StartDate && (b.EndDate == "" || date <= b.EndDate)
+}
+
+// HoursBaselinesOverlap reports whether two normalized baselines share a
+// date.
+func HoursBaselinesOverlap(first, second HoursBaseline) bool {
+	return first.covers(second.StartDate) || second.covers(first.StartDate)
+}
+
+// HoursOn returns the working hours in effect on date, in YYYY-MM-DD form.
+// Without a baseline covering date it returns the organisation's own hours
+// with empty dates.
+func (o Organisation) HoursOn(date string) HoursBaseline {
+	for _, baseline := range o.HoursBaselines {
+		if baseline.covers(date) {
+			return baseline
+		}
+	}
+	return HoursBaseline{HoursPerDay: o.HoursPerDay, HoursPerWeek: o.HoursPerWeek, HoursPerYear: o.HoursPerYear}
+}
+
+// HoursPerDayOn returns the hours of a full-time working day on date.
+func (o Organisation) HoursPerDayOn(date string) float64 {
+	return o.HoursOn(date).HoursPerDay
+}
+
+// MaxHoursPerDay returns the longest working day of the organisation's own
+// hours and every baseline.
+func (o Organisation) MaxHoursPerDay() float64 {
+	longest := o.HoursPerDay
+	for _, baseline := range o.HoursBaselines {
+		longest = math.Max(longest, baseline.HoursPerDay)
+	}
+	return longest
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.ToLower(weekday.String()) == name {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
+
+// OverloadBands holds the thresholds used to classify overloaded report buckets.
+type OverloadBands struct {
+	ModeratePct float64
+	SeverePct   float64
+}
+
+// OverloadBands returns the organisation's overload bands with defaults applied.
+func (o Organisation) OverloadBands() OverloadBands {
+	bands := OverloadBands{ModeratePct: DefaultOverloadModeratePct, SeverePct: DefaultOverloadSeverePct}
+	if o.OverloadModeratePct > 0 {
+		bands.ModeratePct = o.OverloadModeratePct
+	}
+	if o.OverloadSeverePct > 0 {
+		bands.SeverePct = o.OverloadSeverePct
+	}
+	return bands
+}
+
+// Validate checks that the bands are positive and increase from moderate to severe.
+func (b OverloadBands) Validate() error {
+	for _, value := range []float64{b.ModeratePct, b.SeverePct} {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return ErrValidation
+		}
+	}
+	if b.ModeratePct <= 0 || b.SeverePct <= b.ModeratePct {
+		return ErrValidation
+	}
+	return nil
+}
+
+// Classify returns the overload severity for a bucket or an empty string when
+// load stays within availability.
+func (b OverloadBands) Classify(availabilityHours, loadHours float64) string {
+	if loadHours <= availabilityHours+emptyBucketEpsilon {
+		return ""
+	}
+	if availabilityHours <= 0 {
+		return OverloadSevere
+	}
+	overloadPct := (loadHours - availabilityHours) / availabilityHours * 100
+	switch {
+	case overloadPct >= b.SeverePct:
+		return OverloadSevere
+	case overloadPct >= b.ModeratePct:
+		return OverloadModerate
+	default:
+		return OverloadMinor
+	}
 }
 
 // Person describes a person and their employment settings.
@@ -76,8 +574,19 @@ type Person struct {
 	EmploymentPct                float64            `json:"employment_pct"`
 	EmploymentChanges            []EmploymentChange `json:"employment_changes,omitempty"`
 	EmploymentEffectiveFromMonth string             `json:"employment_effective_from_month,omitempty"`
-	CreatedAt                    time.Time          `json:"created_at"`
-	UpdatedAt                    time.Time          `json:"updated_at"`
+	// WorkSchedule names one of the organisation's work schedules. Empty
+	// means the person works every day.
+	WorkSchedule string    `json:"work_schedule,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Version      int64     `json:"version"`
+}
+
+// PersonBatch holds the people found by a batch lookup and the IDs that were
+// not found in the caller's organisation.
+type PersonBatch struct {
+	Persons    []Person `json:"persons"`
+	MissingIDs []string `json:"missing_ids"`
 }
 
 // EmploymentChange records a person's employment percentage from a month onward.
@@ -87,25 +596,81 @@ type EmploymentChange struct {
 }
 
 // Project describes a project tracked within an organisation.
+// An empty EndDate marks an ongoing project without a fixed end.
 type Project struct {
-	ID                   string    `json:"id"`
-	OrganisationID       string    `json:"organisation_id"`
-	Name                 string    `json:"name"`
-	StartDate            string    `json:"start_date"`
-	EndDate              string    `json:"end_date"`
-	EstimatedEffortHours float64   `json:"estimated_effort_hours"`
-	CreatedAt            time.Time `json:"created_at"`
-	UpdatedAt            time.Time `json:"updated_at"`
+	ID                   string             `json:"id"`
+	OrganisationID       string             `json:"organisation_id"`
+	Name                 string             `json:"name"`
+	StartDate            string             `json:"start_date"`
+	EndDate              string             `json:"end_date"`
+	EstimatedEffortHours float64            `json:"estimated_effort_hours"`
+	Milestones           []ProjectMilestone `json:"milestones,omitempty"`
+	// Archived hides a finished project from listings and new allocations
+	// while its allocations keep feeding reports.
+	Archived   bool       `json:"archived,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Version    int64      `json:"version"`
+}
+
+// ProjectMilestone describes an interim project deadline and its effort target.
+type ProjectMilestone struct {
+	Name              string  `json:"name"`
+	Date              string  `json:"date"`
+	TargetEffortHours float64 `json:"target_effort_hours"`
 }
 
 // Group describes a named group of people within an organisation.
+// Members of the groups listed in SubGroupIDs count as members too.
 type Group struct {
 	ID             string    `json:"id"`
 	OrganisationID string    `json:"organisation_id"`
 	Name           string    `json:"name"`
 	MemberIDs      []string  `json:"member_ids"`
+	SubGroupIDs    []string  `json:"sub_group_ids,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int64     `json:"version"`
+}
+
+// GroupPersonIDs returns the direct and transitive member persons of a group.
+// It returns ErrNotFound for an unknown group and ErrValidation when a
+// subgroup is missing or the nesting forms a cycle.
+func GroupPersonIDs(groupID string, groupsByID map[string]Group) ([]string, error) {
+	if _, ok := groupsByID[groupID]; !ok {
+		return nil, ErrNotFound
+	}
+	personIDs := make([]string, 0)
+	err := collectGroupPersonIDs(groupID, groupsByID, map[string]bool{}, map[string]bool{}, &personIDs)
+	if err != nil {
+		return nil, err
+	}
+	return uniqueStrings(personIDs), nil
+}
+
+func collectGroupPersonIDs(groupID string, groupsByID map[string]Group, visiting, done map[string]bool, personIDs *[]string) error {
+	if done[groupID] {
+		return nil
+	}
+	if visiting[groupID] {
+		return fmt.Errorf("group %s is nested in itself: %w", groupID, ErrValidation)
+	}
+	group, ok := groupsByID[groupID]
+	if !ok {
+		return fmt.Errorf("subgroup %s does not exist: %w", groupID, ErrValidation)
+	}
+
+	visiting[groupID] = true
+	*personIDs = append(*personIDs, group.MemberIDs...)
+	for _, subGroupID := range group.SubGroupIDs {
+		if err := collectGroupPersonIDs(subGroupID, groupsByID, visiting, done, personIDs); err != nil {
+			return err
+		}
+	}
+	delete(visiting, groupID)
+	done[groupID] = true
+	return nil
 }
 
 // Allocation assigns project effort to a person or a group.
@@ -120,18 +685,72 @@ type Allocation struct {
 	Percent        float64   `json:"percent"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int64     `json:"version"`
+	// HoldExpiresAt marks a tentative hold that stops counting once it passes.
+	// Confirming the allocation clears it.
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`
+	// EndReason records why the allocation was ended early.
+	EndReason string `json:"end_reason,omitempty"`
 	// PersonID is kept for compatibility with older local JSON records.
 	PersonID string `json:"person_id,omitempty"`
+	// Warnings carries non-blocking notices from a create or update, such as
+	// a double booking on the same project. It is never stored.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// HoldExpired reports whether the allocation is a tentative hold whose expiry has passed.
+func (a Allocation) HoldExpired(now time.Time) bool {
+	return a.HoldExpiresAt != nil && !now.Before(*a.HoldExpiresAt)
+}
+
+// ActiveAllocations drops tentative holds that expired at or before now.
+func ActiveAllocations(allocations []Allocation, now time.Time) []Allocation {
+	active := make([]Allocation, 0, len(allocations))
+	for _, allocation := range allocations {
+		if !allocation.HoldExpired(now) {
+			active = append(active, allocation)
+		}
+	}
+	return active
 }
 
-// OrgHoliday records organisation-wide unavailable hours for a date.
+// OrgHoliday records unavailable hours for a date.
+// It applies organisation-wide unless PersonIDs limits it to specific people.
 type OrgHoliday struct {
 	ID             string    `json:"id"`
 	OrganisationID string    `json:"organisation_id"`
 	Date           string    `json:"date"`
+	Name           string    `json:"name,omitempty"`
 	Hours          float64   `json:"hours"`
+	PersonIDs      []string  `json:"person_ids,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int64     `json:"version"`
+}
+
+// HolidayImportEntry is one holiday read from a calendar file or from the
+// built-in national holiday dataset.
+type HolidayImportEntry struct {
+	Date string `json:"date"`
+	Name string `json:"name,omitempty"`
+}
+
+// HolidayImportRequest selects what a holiday import reads. Exactly one of
+// Country and Calendar is set. Hours defaults to the organisation's working
+// day.
+type HolidayImportRequest struct {
+	Year     int
+	Country  string
+	Region   string
+	Hours    float64
+	Calendar []byte
+}
+
+// HolidayImportResult lists the holidays an import created and the entries
+// it skipped because an organisation-wide holiday already covers the date.
+type HolidayImportResult struct {
+	Created []OrgHoliday         `json:"created"`
+	Skipped []HolidayImportEntry `json:"skipped"`
 }
 
 // GroupUnavailability records unavailable hours for a group on a date.
@@ -143,6 +762,7 @@ type GroupUnavailability struct {
 	Hours          float64   `json:"hours"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int64     `json:"version"`
 }
 
 // PersonUnavailability records unavailable hours for a person on a date.
@@ -154,6 +774,225 @@ type PersonUnavailability struct {
 	Hours          float64   `json:"hours"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int64     `json:"version"`
+}
+
+// ActiveAllocation is an allocation with its target and project names
+// resolved for display.
+type ActiveAllocation struct {
+	Allocation
+	TargetName  string `json:"target_name"`
+	ProjectName string `json:"project_name"`
+}
+
+// AllocationValidation is the outcome of checking an allocation payload
+// without saving it.
+type AllocationValidation struct {
+	Valid  bool                   `json:"valid"`
+	Errors []AllocationFieldError `json:"errors"`
+}
+
+// AllocationFieldError explains why one allocation field failed validation.
+type AllocationFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Allocation event actions.
+const (
+	AllocationEventCreated = "created"
+	AllocationEventUpdated = "updated"
+	AllocationEventEnded   = "ended"
+	AllocationEventDeleted = "deleted"
+)
+
+// AllocationEvent records one change to an allocation for audit
+// reconstruction. Before is empty for creations and After for deletions.
+type AllocationEvent struct {
+	ID             string      `json:"id"`
+	OrganisationID string      `json:"organisation_id"`
+	AllocationID   string      `json:"allocation_id"`
+	Action         string      `json:"action"`
+	ActorID        string      `json:"actor_id"`
+	At             time.Time   `json:"at"`
+	Before         *Allocation `json:"before,omitempty"`
+	After          *Allocation `json:"after,omitempty"`
+}
+
+// AllocationAudit names who changes allocations and when. Repositories turn
+// it into one AllocationEvent per allocation they write. Updates are
+// recorded with UpdateAction, or AllocationEventUpdated when it is empty.
+type AllocationAudit struct {
+	ActorID      string
+	At           time.Time
+	UpdateAction string
+}
+
+// Event returns the audit event of one allocation change. before is nil for
+// a creation and after is nil for a deletion. The snapshots leave out
+// response-only fields.
+func (a AllocationAudit) Event(action string, before, after *Allocation) AllocationEvent {
+	if action == AllocationEventUpdated && a.UpdateAction != "" {
+		action = a.UpdateAction
+	}
+	event := AllocationEvent{
+		Action:  action,
+		ActorID: a.ActorID,
+		At:      a.At,
+		Before:  auditSnapshot(before),
+		After:   auditSnapshot(after),
+	}
+	if after != nil {
+		event.OrganisationID = after.OrganisationID
+		event.AllocationID = after.ID
+	} else if before != nil {
+		event.OrganisationID = before.OrganisationID
+		event.AllocationID = before.ID
+	}
+	return event
+}
+
+func auditSnapshot(allocation *Allocation) *Allocation {
+	if allocation == nil {
+		return nil
+	}
+	snapshot := *allocation
+	snapshot.Warnings = nil
+	return &snapshot
+}
+
+// AllocationHistoryEntry describes one recorded change to an allocation and
+// the fields it touched.
+type AllocationHistoryEntry struct {
+	Action  string                  `json:"action"`
+	ActorID string                  `json:"actor_id"`
+	At      time.Time               `json:"at"`
+	Changes []AllocationFieldChange `json:"changes"`
+}
+
+// AllocationFieldChange holds the old and new value of one allocation field.
+type AllocationFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// AllocationFieldChanges lists the stored fields that differ between two
+// versions of an allocation, in a fixed field order.
+func AllocationFieldChanges(before, after Allocation) []AllocationFieldChange {
+	changes := []AllocationFieldChange{}
+	addString := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, AllocationFieldChange{Field: field, From: from, To: to})
+		}
+	}
+	addString("target_type", before.TargetType, after.TargetType)
+	addString("target_id", before.TargetID, after.TargetID)
+	addString("project_id", before.ProjectID, after.ProjectID)
+	addString("start_date", before.StartDate, after.StartDate)
+	addString("end_date", before.EndDate, after.EndDate)
+	if before.Percent != after.Percent {
+		changes = append(changes, AllocationFieldChange{Field: "percent", From: before.Percent, To: after.Percent})
+	}
+	if !sameOptionalTime(before.HoldExpiresAt, after.HoldExpiresAt) {
+		changes = append(changes, AllocationFieldChange{Field: "hold_expires_at", From: before.HoldExpiresAt, To: after.HoldExpiresAt})
+	}
+	addString("end_reason", before.EndReason, after.EndReason)
+	return changes
+}
+
+func sameOptionalTime(left, right *time.Time) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	return left.Equal(*right)
+}
+
+// CalendarPurgeResult counts calendar entries removed by a purge, per entry type.
+type CalendarPurgeResult struct {
+	OrgHolidays          int `json:"org_holidays"`
+	GroupUnavailability  int `json:"group_unavailability"`
+	PersonUnavailability int `json:"person_unavailability"`
+}
+
+// AllocationReconciliation lists a project's allocations that fell outside
+// its date range. Clipped holds allocations trimmed to the range and
+// OutOfRange holds allocations left for manual handling.
+type AllocationReconciliation struct {
+	ProjectID  string       `json:"project_id"`
+	Mode       string       `json:"mode"`
+	Clipped    []Allocation `json:"clipped"`
+	OutOfRange []Allocation `json:"out_of_range"`
+}
+
+// AllocationImportRow is one decoded allocation import line. It names its
+// target and project instead of referencing them by ID. Error is set when the
+// line could not be decoded and the row must be reported as failed.
+type AllocationImportRow struct {
+	Line        int
+	TargetType  string
+	TargetName  string
+	ProjectName string
+	StartDate   string
+	EndDate     string
+	Percent     float64
+	Error       string
+}
+
+// AllocationImportRowResult reports the outcome of one import line.
+type AllocationImportRowResult struct {
+	Line         int    `json:"line"`
+	AllocationID string `json:"allocation_id,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// AllocationImportResult summarizes an allocation import, one entry per line.
+type AllocationImportResult struct {
+	Created int                         `json:"created"`
+	Failed  int                         `json:"failed"`
+	Rows    []AllocationImportRowResult `json:"rows"`
+}
+
+// Tenant snapshot formats for bulk import and export.
+const (
+	SnapshotFormatJSON = "json"
+	SnapshotFormatCSV  = "csv"
+)
+
+// TenantSnapshotVersion is the snapshot layout this backend writes and reads.
+const TenantSnapshotVersion = 1
+
+// TenantSnapshot holds every record of one organisation for bulk export and
+// import. Record IDs only link the records to each other. An import stores
+// them under new IDs in a new organisation.
+type TenantSnapshot struct {
+	FormatVersion        int                    `json:"format_version"`
+	Organisation         Organisation           `json:"organisation"`
+	Persons              []Person               `json:"persons"`
+	Projects             []Project              `json:"projects"`
+	Groups               []Group                `json:"groups"`
+	Allocations          []Allocation           `json:"allocations"`
+	Holidays             []OrgHoliday           `json:"holidays"`
+	GroupUnavailability  []GroupUnavailability  `json:"group_unavailability"`
+	PersonUnavailability []PersonUnavailability `json:"person_unavailability"`
+}
+
+// TenantImportIssue is one validation problem found in a snapshot. Record
+// names the offending entry, for example persons[2].
+type TenantImportIssue struct {
+	Record  string `json:"record"`
+	Message string `json:"message"`
+}
+
+// TenantImportResult reports a snapshot import. Nothing is stored unless
+// Imported is set, which needs a valid snapshot outside dry-run mode.
+type TenantImportResult struct {
+	DryRun         bool                `json:"dry_run"`
+	Valid          bool                `json:"valid"`
+	Imported       bool                `json:"imported"`
+	OrganisationID string              `json:"organisation_id,omitempty"`
+	Counts         map[string]int      `json:"counts"`
+	Issues         []TenantImportIssue `json:"issues"`
 }
 
 // ReportRequest defines an availability and load report query.
@@ -163,6 +1002,86 @@ type ReportRequest struct {
 	FromDate    string   `json:"from_date"`
 	ToDate      string   `json:"to_date"`
 	Granularity string   `json:"granularity"`
+	// IncludeContributors attaches the contributing allocations to each bucket.
+	IncludeContributors bool `json:"include_contributors,omitempty"`
+	// SkipEmpty omits buckets without availability or load.
+	SkipEmpty bool `json:"skip_empty,omitempty"`
+	// TolerateMissing skips IDs that do not resolve in the organisation
+	// instead of failing the report.
+	TolerateMissing bool `json:"tolerate_missing,omitempty"`
+	// IncludePeakLoad reports the busiest single day of each bucket for
+	// granularities coarser than a day.
+	IncludePeakLoad bool `json:"include_peak_load,omitempty"`
+	// Unit is ReportUnitHours by default. ReportUnitFTE divides availability,
+	// load, free, and peak load hours by the full-time hours of the bucket.
+	Unit string `json:"unit,omitempty"`
+	// ScenarioID runs the report against the allocations of that scenario
+	// instead of the live allocations.
+	ScenarioID string `json:"scenario_id,omitempty"`
+}
+
+// ReportResult is a generated report with the requested IDs that were skipped
+// because they do not exist in the organisation.
+type ReportResult struct {
+	Buckets    []ReportBucket `json:"buckets"`
+	SkippedIDs []string       `json:"skipped_ids,omitempty"`
+}
+
+// WhatIfReportRequest is a report query evaluated as if the proposed
+// allocations existed alongside the stored ones.
+type WhatIfReportRequest struct {
+	ReportRequest
+	ProposedAllocations []Allocation `json:"proposed_allocations"`
+}
+
+// ReportDiffRequest compares a baseline report with a comparison report.
+// The comparison may propose allocations as in a what-if report, and buckets
+// are aligned by period start.
+type ReportDiffRequest struct {
+	Baseline   ReportRequest       `json:"baseline"`
+	Comparison WhatIfReportRequest `json:"comparison"`
+}
+
+// ReportDiffBucket holds both sides of one aligned period and the change
+// from baseline to comparison. A period missing on one side counts as zero.
+type ReportDiffBucket struct {
+	PeriodStart                 string  `json:"period_start"`
+	BaselineLoadHours           float64 `json:"baseline_load_hours"`
+	ComparisonLoadHours         float64 `json:"comparison_load_hours"`
+	LoadHoursDelta              float64 `json:"load_hours_delta"`
+	BaselineAvailabilityHours   float64 `json:"baseline_availability_hours"`
+	ComparisonAvailabilityHours float64 `json:"comparison_availability_hours"`
+	AvailabilityHoursDelta      float64 `json:"availability_hours_delta"`
+}
+
+// PeakOverallocation describes the stretch of days where a person's combined
+// allocation load exceeds their employment percentage the most. Overallocated
+// is false and the dates are empty when the load never exceeds it.
+type PeakOverallocation struct {
+	PersonID      string  `json:"person_id"`
+	Overallocated bool    `json:"overallocated"`
+	StartDate     string  `json:"start_date,omitempty"`
+	EndDate       string  `json:"end_date,omitempty"`
+	LoadPct       float64 `json:"load_pct"`
+	CapacityPct   float64 `json:"capacity_pct"`
+	ExcessPct     float64 `json:"excess_pct"`
+}
+
+// AllocationConflict is a stretch of days where a person's combined direct
+// and group allocation load exceeds their employment percentage.
+// Severity classifies the excess relative to the capacity with the
+// organisation's overload bands. AllocationIDs lists every allocation that
+// reaches the person on those days.
+type AllocationConflict struct {
+	PersonID      string   `json:"person_id"`
+	PersonName    string   `json:"person_name"`
+	StartDate     string   `json:"start_date"`
+	EndDate       string   `json:"end_date"`
+	LoadPct       float64  `json:"load_pct"`
+	CapacityPct   float64  `json:"capacity_pct"`
+	ExcessPct     float64  `json:"excess_pct"`
+	Severity      string   `json:"severity"`
+	AllocationIDs []string `json:"allocation_ids"`
 }
 
 // ReportBucket contains aggregated report values for one period.
@@ -175,6 +1094,33 @@ type ReportBucket struct {
 	FreeHours         float64 `json:"free_hours"`
 	UtilizationPct    float64 `json:"utilization_pct"`
 	CompletionPct     float64 `json:"project_completion_pct"`
+	// Contributors is only populated when the request sets IncludeContributors.
+	Contributors []ReportContributor `json:"contributors,omitempty"`
+	// Milestones lists project milestones dated within this bucket for project scope reports.
+	Milestones []ReportMilestone `json:"milestones,omitempty"`
+	// OverloadSeverity classifies buckets whose load exceeds availability.
+	OverloadSeverity string `json:"overload_severity,omitempty"`
+	// PeakLoadHours and PeakLoadDate are only populated when the request sets
+	// IncludePeakLoad. They hold the highest single-day load in the bucket.
+	PeakLoadHours float64 `json:"peak_load_hours,omitempty"`
+	PeakLoadDate  string  `json:"peak_load_date,omitempty"`
+}
+
+// ReportMilestone reports whether cumulative project load reached a milestone target by its date.
+type ReportMilestone struct {
+	ProjectID           string  `json:"project_id"`
+	Name                string  `json:"name"`
+	Date                string  `json:"date"`
+	TargetEffortHours   float64 `json:"target_effort_hours"`
+	CumulativeLoadHours float64 `json:"cumulative_load_hours"`
+	Met                 bool    `json:"met"`
+}
+
+// ReportContributor describes how much load one allocation added to a bucket.
+type ReportContributor struct {
+	AllocationID string  `json:"allocation_id"`
+	Percent      float64 `json:"percent"`
+	Hours        float64 `json:"hours"`
 }
 
 // ValidateDate normalizes and validates a full date string.
@@ -215,10 +1161,36 @@ func ValidatePercent(value float64) error {
 	return nil
 }
 
+// ValidateEmploymentPct validates an employment percentage. It must lie
+// within 0 to 100 and carry at most EmploymentPctDecimals decimal places.
+func ValidateEmploymentPct(value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return ErrValidation
+	}
+	if err := ValidatePercent(value); err != nil {
+		return err
+	}
+	scaled := value * math.Pow10(EmploymentPctDecimals)
+	if math.Abs(scaled-math.Round(scaled)) > percentStepTolerance*math.Max(1, math.Abs(scaled)) {
+		return ErrValidation
+	}
+	return nil
+}
+
 // ValidateGranularity validates a report granularity value.
 func ValidateGranularity(value string) error {
 	switch value {
-	case GranularityDay, GranularityWeek, GranularityMonth, GranularityYear:
+	case GranularityDay, GranularityWeek, GranularityMonth, GranularityQuarter, GranularityYear:
+		return nil
+	default:
+		return ErrValidation
+	}
+}
+
+// ValidateReportUnit validates a report unit value. Empty means hours.
+func ValidateReportUnit(value string) error {
+	switch value {
+	case "", ReportUnitHours, ReportUnitFTE:
 		return nil
 	default:
 		return ErrValidation
@@ -245,6 +1217,98 @@ func ValidateAllocationTargetType(value string) error {
 	}
 }
 
+// ValidateAllocationDateSnap validates an allocation date snap option.
+// An empty value is treated as SnapNone.
+func ValidateAllocationDateSnap(value string) error {
+	switch value {
+	case "", SnapNone, SnapWeek, SnapMonth:
+		return nil
+	default:
+		return ErrValidation
+	}
+}
+
+// ValidateAllocationPercentStep validates an allocation percent step. Zero
+// disables stepping, and any other step must divide 100 into whole parts.
+func ValidateAllocationPercentStep(step float64) error {
+	if step == 0 {
+		return nil
+	}
+	if math.IsNaN(step) || math.IsInf(step, 0) || step < 0 || step > 100 {
+		return ErrValidation
+	}
+	parts := 100 / step
+	if math.Abs(parts-math.Round(parts)) > percentStepTolerance {
+		return ErrValidation
+	}
+	return nil
+}
+
+// ValidateAllocationPercentStepMode validates an allocation percent step mode.
+// An empty value is treated as PercentStepReject.
+func ValidateAllocationPercentStepMode(value string) error {
+	switch value {
+	case "", PercentStepReject, PercentStepSnap:
+		return nil
+	default:
+		return ErrValidation
+	}
+}
+
+// SnapPercentToStep rounds percent to the nearest multiple of step and
+// reports whether percent already was one. A step of zero keeps the percent.
+func SnapPercentToStep(percent, step float64) (snapped float64, onStep bool) {
+	if step <= 0 {
+		return percent, true
+	}
+	snapped = math.Round(percent/step) * step
+	return snapped, math.Abs(snapped-percent) <= percentStepTolerance
+}
+
+// SnapAllocationDates moves the start back to its period start and the end
+// forward to its period end for the given snap option. A snapped date never
+// moves past earliest or latest when the given date was within them, so a
+// range inside a project that starts or ends mid-period stays inside it.
+// Empty bounds are open.
+func SnapAllocationDates(startDate, endDate, snap, earliest, latest string) (snappedStart string, snappedEnd string, err error) {
+	if err = ValidateAllocationDateSnap(snap); err != nil {
+		return "", "", err
+	}
+	if snap == "" || snap == SnapNone {
+		return startDate, endDate, nil
+	}
+
+	start, err := time.Parse(DateLayout, startDate)
+	if err != nil {
+		return "", "", ErrValidation
+	}
+	end, err := time.Parse(DateLayout, endDate)
+	if err != nil {
+		return "", "", ErrValidation
+	}
+
+	granularity := GranularityWeek
+	if snap == SnapMonth {
+		granularity = GranularityMonth
+	}
+	start = periodStart(start, granularity)
+	end = periodStart(end, granularity)
+	if snap == SnapMonth {
+		end = end.AddDate(0, 1, -1)
+	} else {
+		end = end.AddDate(0, 0, 6)
+	}
+
+	snappedStart, snappedEnd = start.Format(DateLayout), end.Format(DateLayout)
+	if earliest != "" && startDate >= earliest && snappedStart < earliest {
+		snappedStart = earliest
+	}
+	if latest != "" && endDate <= latest && snappedEnd > latest {
+		snappedEnd = latest
+	}
+	return snappedStart, snappedEnd, nil
+}
+
 // EmploymentPctOnDate returns the effective employment percentage for a date.
 func EmploymentPctOnDate(person Person, date string) (float64, error) {
 	normalizedDate, err := ValidateDate(date)
@@ -261,7 +1325,7 @@ func employmentPctOnMonth(person Person, month string) (float64, error) {
 		return 0, ErrValidation
 	}
 
-	err = ValidatePercent(person.EmploymentPct)
+	err = ValidateEmploymentPct(person.EmploymentPct)
 	if err != nil {
 		return 0, ErrValidation
 	}
@@ -278,7 +1342,7 @@ func employmentPctOnMonth(person Person, month string) (float64, error) {
 			return 0, ErrValidation
 		}
 		seenMonths[effectiveMonth] = true
-		if percentErr := ValidatePercent(change.EmploymentPct); percentErr != nil {
+		if percentErr := ValidateEmploymentPct(change.EmploymentPct); percentErr != nil {
 			return 0, ErrValidation
 		}
 		if effectiveMonth <= normalizedMonth && effectiveMonth > latestMonth {
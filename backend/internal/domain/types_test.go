@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"math"
+	"strings"
 	"testing"
 	"time"
 )
@@ -146,6 +148,106 @@ func testEmploymentPctOnDateValidation(t *testing.T) {
 	}
 }
 
+// TestSnapAllocationDates verifies the snap allocation dates scenario.
+func TestSnapAllocationDates(t *testing.T) {
+	testCases := []struct {
+		snap      string
+		wantStart string
+		wantEnd   string
+	}{
+		{snap: "", wantStart: "2026-02-18", wantEnd: "2026-03-04"},
+		{snap: SnapNone, wantStart: "2026-02-18", wantEnd: "2026-03-04"},
+		{snap: SnapWeek, wantStart: "2026-02-16", wantEnd: "2026-03-08"},
+		{snap: SnapMonth, wantStart: "2026-02-01", wantEnd: "2026-03-31"},
+	}
+	for _, testCase := range testCases {
+		start, end, err := SnapAllocationDates("2026-02-18", "2026-03-04", testCase.snap, "", "")
+		if err != nil {
+			t.Fatalf("snap %q: %v", testCase.snap, err)
+		}
+		if start != testCase.wantStart || end != testCase.wantEnd {
+			t.Fatalf("snap %q: expected %s..%s, got %s..%s", testCase.snap, testCase.wantStart, testCase.wantEnd, start, end)
+		}
+	}
+
+	// The bounds start on a Tuesday and end on a Thursday. Dates already
+	// outside them are left for range validation to reject.
+	boundedCases := []struct {
+		snap, start, end, wantStart, wantEnd string
+	}{
+		{snap: SnapWeek, start: "2026-02-18", end: "2026-03-04", wantStart: "2026-02-17", wantEnd: "2026-03-05"},
+		{snap: SnapMonth, start: "2026-02-18", end: "2026-03-04", wantStart: "2026-02-17", wantEnd: "2026-03-05"},
+		{snap: SnapWeek, start: "2026-02-19", end: "2026-02-26", wantStart: "2026-02-17", wantEnd: "2026-03-01"},
+		{snap: SnapWeek, start: "2026-02-16", end: "2026-03-06", wantStart: "2026-02-16", wantEnd: "2026-03-08"},
+	}
+	for _, testCase := range boundedCases {
+		start, end, err := SnapAllocationDates(testCase.start, testCase.end, testCase.snap, "2026-02-17", "2026-03-05")
+		if err != nil {
+			t.Fatalf("snap %q %s..%s: %v", testCase.snap, testCase.start, testCase.end, err)
+		}
+		if start != testCase.wantStart || end != testCase.wantEnd {
+			t.Fatalf("snap %q %s..%s: expected %s..%s within bounds, got %s..%s",
+				testCase.snap, testCase.start, testCase.end, testCase.wantStart, testCase.wantEnd, start, end)
+		}
+	}
+
+	if _, _, err := SnapAllocationDates("2026-02-18", "2026-03-04", "quarter", "", ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected invalid snap option to fail validation, got %v", err)
+	}
+	if _, _, err := SnapAllocationDates("bad", "2026-03-04", SnapWeek, "", ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected invalid start date to fail validation, got %v", err)
+	}
+	if _, _, err := SnapAllocationDates("2026-02-18", "bad", SnapWeek, "", ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected invalid end date to fail validation, got %v", err)
+	}
+}
+
+// TestAllocationPercentStep verifies the allocation percent step scenario.
+func TestAllocationPercentStep(t *testing.T) {
+	for _, step := range []float64{0, 0.5, 5, 12.5, 25, 100} {
+		if err := ValidateAllocationPercentStep(step); err != nil {
+			t.Fatalf("expected step %v to be valid, got %v", step, err)
+		}
+	}
+	for _, step := range []float64{-5, 3, 30, 150, math.NaN()} {
+		if err := ValidateAllocationPercentStep(step); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected step %v to fail validation, got %v", step, err)
+		}
+	}
+	if err := ValidateAllocationPercentStepMode("round"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown step mode to fail validation, got %v", err)
+	}
+
+	if snapped, onStep := SnapPercentToStep(42, 5); snapped != 40 || onStep {
+		t.Fatalf("expected 42 to snap to 40 off step, got %v onStep=%v", snapped, onStep)
+	}
+	if snapped, onStep := SnapPercentToStep(33.3, 0); snapped != 33.3 || !onStep {
+		t.Fatalf("expected zero step to keep the percent, got %v onStep=%v", snapped, onStep)
+	}
+}
+
+// TestValidateEmploymentPct verifies the validate employment pct scenario.
+func TestValidateEmploymentPct(t *testing.T) {
+	for _, pct := range []float64{0, 33.33, 37.5, 62.5, 80, 100} {
+		if err := ValidateEmploymentPct(pct); err != nil {
+			t.Fatalf("expected employment pct %v to be valid, got %v", pct, err)
+		}
+	}
+	for _, pct := range []float64{-0.5, 100.5, 37.125, 12.3456, math.NaN(), math.Inf(1)} {
+		if err := ValidateEmploymentPct(pct); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected employment pct %v to fail validation, got %v", pct, err)
+		}
+	}
+
+	person := Person{EmploymentPct: 37.5, EmploymentChanges: []EmploymentChange{{EffectiveMonth: "2026-07", EmploymentPct: 62.5}}}
+	if pct, err := EmploymentPctOnDate(person, "2026-03-10"); err != nil || pct != 37.5 {
+		t.Fatalf("expected 37.5%% before the change, got %v err=%v", pct, err)
+	}
+	if pct, err := EmploymentPctOnDate(person, "2026-07-01"); err != nil || pct != 62.5 {
+		t.Fatalf("expected 62.5%% from the change, got %v err=%v", pct, err)
+	}
+}
+
 // TestPeriodStartAndRoundHelpers verifies the period start and round helpers scenario.
 func TestPeriodStartAndRoundHelpers(t *testing.T) {
 	day := time.Date(2026, time.February, 18, 0, 0, 0, 0, time.UTC)
@@ -221,3 +323,392 @@ func TestSelectedPeopleForScope(t *testing.T) {
 		t.Fatalf("expected not found for missing project, got %v", err)
 	}
 }
+
+// TestProjectRangeError verifies the project range error scenario.
+func TestProjectRangeError(t *testing.T) {
+	err := error(ProjectRangeError{ProjectStart: date20260101, ProjectEnd: "2026-12-31"})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected project range error to wrap ErrValidation, got %v", err)
+	}
+	var rangeErr ProjectRangeError
+	if !errors.As(err, &rangeErr) || rangeErr.ProjectEnd != "2026-12-31" {
+		t.Fatalf("expected typed project range detail, got %+v", rangeErr)
+	}
+	if err.Error() != "allocation dates must fall within project range 2026-01-01 to 2026-12-31" {
+		t.Fatalf("unexpected project range message %q", err.Error())
+	}
+}
+
+// TestOverloadBands verifies the overload bands scenario.
+func TestOverloadBands(t *testing.T) {
+	defaults := Organisation{}.OverloadBands()
+	if defaults.ModeratePct != DefaultOverloadModeratePct || defaults.SeverePct != DefaultOverloadSeverePct {
+		t.Fatalf("unexpected default bands: %+v", defaults)
+	}
+	custom := Organisation{OverloadModeratePct: 20, OverloadSeverePct: 50}.OverloadBands()
+	if custom.ModeratePct != 20 || custom.SeverePct != 50 {
+		t.Fatalf("unexpected custom bands: %+v", custom)
+	}
+
+	for _, bands := range []OverloadBands{
+		{ModeratePct: 0, SeverePct: 50},
+		{ModeratePct: 50, SeverePct: 50},
+		{ModeratePct: 60, SeverePct: 50},
+		{ModeratePct: math.NaN(), SeverePct: 50},
+		{ModeratePct: 20, SeverePct: math.Inf(1)},
+	} {
+		if err := bands.Validate(); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected bands %+v to be rejected, got %v", bands, err)
+		}
+	}
+	if err := custom.Validate(); err != nil {
+		t.Fatalf("expected monotonic bands to be valid: %v", err)
+	}
+
+	testCases := []struct {
+		availability float64
+		load         float64
+		expected     string
+	}{
+		{availability: 8, load: 8, expected: ""},
+		{availability: 8, load: 4, expected: ""},
+		{availability: 8, load: 8.8, expected: OverloadMinor},
+		{availability: 8, load: 10, expected: OverloadModerate},
+		{availability: 8, load: 12, expected: OverloadSevere},
+		{availability: 8, load: 16, expected: OverloadSevere},
+		{availability: 0, load: 2, expected: OverloadSevere},
+	}
+	for _, testCase := range testCases {
+		if got := custom.Classify(testCase.availability, testCase.load); got != testCase.expected {
+			t.Fatalf("classify(%v, %v): expected %q, got %q", testCase.availability, testCase.load, testCase.expected, got)
+		}
+	}
+}
+
+// TestCurrentMonthRangeUsesLocation verifies the current month range uses location scenario.
+func TestCurrentMonthRangeUsesLocation(t *testing.T) {
+	now := time.Date(2026, time.January, 31, 12, 0, 0, 0, time.UTC)
+	kiritimati, err := LoadTimezone("Pacific/Kiritimati")
+	if err != nil {
+		t.Fatalf("load time zone: %v", err)
+	}
+
+	if from, to := CurrentMonthRange(now, time.UTC); from != date20260101 || to != "2026-01-31" {
+		t.Fatalf("unexpected UTC month range %s..%s", from, to)
+	}
+	if from, to := CurrentMonthRange(now, kiritimati); from != "2026-02-01" || to != "2026-02-28" {
+		t.Fatalf("unexpected Kiritimati month range %s..%s", from, to)
+	}
+
+	if location, loadErr := LoadTimezone(" "); loadErr != nil || location != time.UTC {
+		t.Fatalf("expected empty time zone to resolve to UTC, got %v %v", location, loadErr)
+	}
+	if _, err = LoadTimezone("Mars/Olympus_Mons"); !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), `"Mars/Olympus_Mons"`) {
+		t.Fatalf("expected unknown time zone to fail validation naming the zone, got %v", err)
+	}
+}
+
+// TestWorkSchedule verifies the work schedule scenario.
+func TestWorkSchedule(t *testing.T) {
+	fortnight := make([]bool, 14)
+	for idx := range fortnight {
+		// Weekdays work, except the second Friday of the fortnight.
+		fortnight[idx] = idx%7 < 5 && idx != 11
+	}
+	schedule := WorkSchedule{Name: " 9-day fortnight ", WorkingDays: fortnight, CycleStart: " 2026-03-02 "}.Normalized()
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("expected a nine-day fortnight to be valid, got %v", err)
+	}
+
+	workingDays := map[string]bool{
+		"2026-03-02": true,
+		"2026-03-06": true,
+		"2026-03-07": false,
+		"2026-03-13": false,
+		"2026-03-20": true,
+		"2026-03-27": false,
+		"2026-02-27": false,
+	}
+	for date, want := range workingDays {
+		day, err := time.Parse(DateLayout, date)
+		if err != nil {
+			t.Fatalf("parse %s: %v", date, err)
+		}
+		if got := schedule.WorksOn(day); got != want {
+			t.Fatalf("expected WorksOn(%s) to be %v", date, want)
+		}
+	}
+
+	invalid := []WorkSchedule{
+		{Name: "", WorkingDays: fortnight},
+		{Name: "Short", WorkingDays: []bool{true, true, true}},
+		{Name: "Long", WorkingDays: make([]bool, 35)},
+		{Name: "Idle", WorkingDays: make([]bool, 7)},
+		{Name: "Tuesday", WorkingDays: fortnight, CycleStart: "2026-03-03"},
+		{Name: "Garbled", WorkingDays: fortnight, CycleStart: "March"},
+	}
+	for _, candidate := range invalid {
+		if err := candidate.Validate(); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected schedule %q to fail validation, got %v", candidate.Name, err)
+		}
+	}
+
+	organisation := Organisation{WorkSchedules: []WorkSchedule{schedule}}
+	if found, ok := organisation.WorkSchedule("9-DAY FORTNIGHT"); !ok || found.Name != "9-day fortnight" {
+		t.Fatalf("expected a case-insensitive schedule lookup, got %+v %v", found, ok)
+	}
+	if _, ok := organisation.WorkSchedule(""); ok {
+		t.Fatal("expected an empty name not to match a schedule")
+	}
+}
+
+// TestHoursBaseline verifies the hours baseline scenario.
+func TestHoursBaseline(t *testing.T) {
+	fiscal2026 := HoursBaseline{StartDate: " 2026-01-01 ", EndDate: " 2026-12-31 ", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}.Normalized()
+	if err := fiscal2026.Validate(); err != nil {
+		t.Fatalf("expected the 2026 baseline to be valid, got %v", err)
+	}
+	onward := HoursBaseline{StartDate: "2027-01-01", HoursPerDay: 7.5, HoursPerWeek: 37.5, HoursPerYear: 1950}
+	if err := onward.Validate(); err != nil {
+		t.Fatalf("expected an open-ended baseline to be valid, got %v", err)
+	}
+
+	invalid := []HoursBaseline{
+		{EndDate: "2026-12-31", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		{StartDate: "2026-02-30", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		{StartDate: "2026-06-01", EndDate: "2026-05-31", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		{StartDate: "2026-01-01", HoursPerDay: 0, HoursPerWeek: 40, HoursPerYear: 2080},
+		{StartDate: "2026-01-01", HoursPerDay: 8, HoursPerWeek: math.NaN(), HoursPerYear: 2080},
+	}
+	for _, candidate := range invalid {
+		if err := candidate.Validate(); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected baseline %+v to fail validation, got %v", candidate, err)
+		}
+	}
+
+	if HoursBaselinesOverlap(fiscal2026, onward) {
+		t.Fatal("expected consecutive baselines not to overlap")
+	}
+	if !HoursBaselinesOverlap(fiscal2026, HoursBaseline{StartDate: "2026-12-31"}) {
+		t.Fatal("expected a baseline starting on the last day of another to overlap it")
+	}
+	if !HoursBaselinesOverlap(onward, HoursBaseline{StartDate: "2026-06-01", EndDate: "2030-01-01"}) {
+		t.Fatal("expected a baseline reaching into an open-ended one to overlap it")
+	}
+
+	organisation := Organisation{HoursPerDay: 8.4, HoursPerWeek: 42, HoursPerYear: 2184, HoursBaselines: []HoursBaseline{fiscal2026, onward}}
+	hoursByDate := map[string]float64{
+		"2025-12-31": 8.4,
+		"2026-01-01": 8,
+		"2026-12-31": 8,
+		"2027-01-01": 7.5,
+		"2031-06-15": 7.5,
+	}
+	for date, want := range hoursByDate {
+		if got := organisation.HoursPerDayOn(date); got != want {
+			t.Fatalf("expected %v hours per day on %s, got %v", want, date, got)
+		}
+	}
+	if got := organisation.HoursOn("2027-03-01").HoursPerYear; got != 1950 {
+		t.Fatalf("expected the 2027 baseline's yearly hours, got %v", got)
+	}
+	if got := organisation.MaxHoursPerDay(); got != 8.4 {
+		t.Fatalf("expected the longest day to be 8.4 hours, got %v", got)
+	}
+}
+
+// TestAllocationTemplate verifies the allocation template scenario.
+func TestAllocationTemplate(t *testing.T) {
+	template := AllocationTemplate{
+		Name: " Rotation ",
+		Entries: []AllocationTemplateEntry{
+			{TargetID: " person_1 ", Percent: 50},
+			{TargetType: " Group ", TargetID: "group_1", Percent: 20},
+		},
+	}.Normalized()
+	if err := template.Validate(); err != nil {
+		t.Fatalf("expected the template to be valid, got %v", err)
+	}
+	if template.Name != "Rotation" || template.Entries[0].TargetType != AllocationTargetPerson || template.Entries[0].TargetID != "person_1" || template.Entries[1].TargetType != AllocationTargetGroup {
+		t.Fatalf("expected a normalized template, got %+v", template)
+	}
+
+	tooMany := make([]AllocationTemplateEntry, MaxAllocationTemplateEntries+1)
+	for idx := range tooMany {
+		tooMany[idx] = AllocationTemplateEntry{TargetType: AllocationTargetPerson, TargetID: "person_1", Percent: 1}
+	}
+	invalid := []AllocationTemplate{
+		{Name: "", Entries: template.Entries},
+		{Name: "Empty"},
+		{Name: "Too many", Entries: tooMany},
+		{Name: "Team", Entries: []AllocationTemplateEntry{{TargetType: "team", TargetID: "t", Percent: 10}}},
+		{Name: "No target", Entries: []AllocationTemplateEntry{{TargetType: AllocationTargetPerson, Percent: 10}}},
+		{Name: "Zero", Entries: []AllocationTemplateEntry{{TargetType: AllocationTargetPerson, TargetID: "p", Percent: 0}}},
+		{Name: "NaN", Entries: []AllocationTemplateEntry{{TargetType: AllocationTargetPerson, TargetID: "p", Percent: math.NaN()}}},
+	}
+	for _, candidate := range invalid {
+		if err := candidate.Validate(); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected template %q to fail validation, got %v", candidate.Name, err)
+		}
+	}
+}
+
+// TestScenarioChanges verifies the scenario changes scenario.
+func TestScenarioChanges(t *testing.T) {
+	scenario := Scenario{
+		Allocations: []Allocation{
+			{ID: "allocation_1", Version: 2},
+			{ID: "allocation_2", Version: 4},
+			{ID: ScenarioAllocationIDPrefix + "1", Version: 1},
+		},
+		BaseVersions: map[string]int64{"allocation_1": 2, "allocation_2": 3, "allocation_3": 1},
+	}
+	changes := scenario.Changes()
+	if len(changes.Created) != 1 || changes.Created[0].ID != ScenarioAllocationIDPrefix+"1" {
+		t.Fatalf("expected the scenario allocation to be created, got %+v", changes.Created)
+	}
+	if len(changes.Updated) != 1 || changes.Updated[0].ID != "allocation_2" {
+		t.Fatalf("expected the allocation with a new version to be updated, got %+v", changes.Updated)
+	}
+	if len(changes.DeletedIDs) != 1 || changes.DeletedIDs[0] != "allocation_3" {
+		t.Fatalf("expected the missing base allocation to be deleted, got %+v", changes.DeletedIDs)
+	}
+	if changes.Empty() {
+		t.Fatal("expected changes not to be empty")
+	}
+	if !(Scenario{Allocations: []Allocation{{ID: "allocation_1", Version: 2}}, BaseVersions: map[string]int64{"allocation_1": 2}}).Changes().Empty() {
+		t.Fatal("expected an unchanged scenario to have no changes")
+	}
+	if summary := scenario.Summary(); summary.AllocationCount != 3 {
+		t.Fatalf("expected the summary to count allocations, got %+v", summary)
+	}
+}
+
+// TestUnavailabilityRule verifies the unavailability rule scenario.
+func TestUnavailabilityRule(t *testing.T) {
+	rule := UnavailabilityRule{
+		Weekdays:  []string{" Friday ", "friday", "MONDAY"},
+		Hours:     4,
+		StartDate: " 2026-01-02 ",
+		UntilDate: "2026-01-23",
+	}.Normalized()
+	if len(rule.Weekdays) != 2 || rule.Weekdays[0] != "friday" || rule.Weekdays[1] != "monday" {
+		t.Fatalf("expected normalized weekdays [friday monday], got %v", rule.Weekdays)
+	}
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("expected rule to be valid, got %v", err)
+	}
+
+	appliesOn := map[string]bool{
+		"2026-01-01": false,
+		"2026-01-02": true,
+		"2026-01-03": false,
+		"2026-01-05": true,
+		"2026-01-09": true,
+		"2026-01-23": true,
+		"2026-01-26": false,
+		"2026-01-30": false,
+	}
+	for date, want := range appliesOn {
+		day, err := time.Parse(DateLayout, date)
+		if err != nil {
+			t.Fatalf("parse %s: %v", date, err)
+		}
+		if got := rule.AppliesOn(day); got != want {
+			t.Fatalf("expected AppliesOn(%s) to be %v", date, want)
+		}
+	}
+
+	openEnded := UnavailabilityRule{Weekdays: []string{"friday"}, Hours: 8, StartDate: "2026-01-02"}
+	farFuture, err := time.Parse(DateLayout, "2030-01-04")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !openEnded.AppliesOn(farFuture) {
+		t.Fatal("expected a rule without until_date to keep applying")
+	}
+
+	invalid := []UnavailabilityRule{
+		{Hours: 4, StartDate: "2026-01-02"},
+		{Weekdays: []string{"fri-day"}, Hours: 4, StartDate: "2026-01-02"},
+		{Weekdays: []string{"friday"}, Hours: 0, StartDate: "2026-01-02"},
+		{Weekdays: []string{"friday"}, Hours: math.NaN(), StartDate: "2026-01-02"},
+		{Weekdays: []string{"friday"}, Hours: 4, StartDate: "January"},
+		{Weekdays: []string{"friday"}, Hours: 4, StartDate: "2026-01-02", UntilDate: "2026-01-01"},
+		{Weekdays: []string{"friday"}, Hours: 4, StartDate: "2026-01-02", UntilDate: "later"},
+	}
+	for idx, candidate := range invalid {
+		if err := candidate.Normalized().Validate(); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected rule %d to fail validation, got %v", idx, err)
+		}
+	}
+}
+
+// TestAllocationFieldChanges verifies the allocation field changes scenario.
+func TestAllocationFieldChanges(t *testing.T) {
+	hold := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	sameHold := hold
+	before := Allocation{TargetType: AllocationTargetPerson, TargetID: "person_1", ProjectID: "project_1", StartDate: "2026-01-01", EndDate: "2026-12-31", Percent: 20, HoldExpiresAt: &hold}
+	after := before
+	after.HoldExpiresAt = &sameHold
+	if changes := AllocationFieldChanges(before, after); len(changes) != 0 {
+		t.Fatalf("expected no changes for equal allocations, got %+v", changes)
+	}
+
+	after.Percent = 40
+	after.EndDate = "2026-06-30"
+	after.HoldExpiresAt = nil
+	changes := AllocationFieldChanges(before, after)
+	if len(changes) != 3 {
+		t.Fatalf("expected three changes, got %+v", changes)
+	}
+	if changes[0].Field != "end_date" || changes[1].Field != "percent" || changes[2].Field != "hold_expires_at" {
+		t.Fatalf("expected changes in field order, got %+v", changes)
+	}
+	if changes[1].From != 20.0 || changes[1].To != 40.0 {
+		t.Fatalf("unexpected percent change: %+v", changes[1])
+	}
+}
+
+// TestSearchIndex verifies the search index matching, ranking, and highlight scenario.
+func TestSearchIndex(t *testing.T) {
+	archivedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	index := NewSearchIndex(
+		[]Person{{ID: "person_1", Name: "Ada Lovelace"}, {ID: "person_2", Name: "Grace <Hopper>"}},
+		[]Project{
+			{ID: "project_1", Name: "Analytical Engine", Milestones: []ProjectMilestone{{Name: "Ada review"}}},
+			{ID: "project_2", Name: "Ada Archive", ArchivedAt: &archivedAt},
+		},
+		[]Group{{ID: "group_1", Name: "Ada Team"}},
+	)
+
+	results := index.Search("ada", 10)
+	if len(results) != 3 {
+		t.Fatalf("expected three results without the archived project, got %+v", results)
+	}
+	if results[0].ID != "person_1" || results[1].ID != "group_1" || results[2].ID != "project_1" {
+		t.Fatalf("expected name matches by entity type before the milestone match, got %+v", results)
+	}
+	if results[0].Snippet != "<mark>Ada</mark> Lovelace" || results[2].Snippet != "<mark>Ada</mark> review" {
+		t.Fatalf("expected highlighted snippets, got %q and %q", results[0].Snippet, results[2].Snippet)
+	}
+	if results[2].EntityType != SearchEntityProject || results[2].Name != "Analytical Engine" {
+		t.Fatalf("expected the project name with the milestone snippet, got %+v", results[2])
+	}
+
+	if results = index.Search("LOVE ad", 10); len(results) != 1 || results[0].Snippet != "<mark>Ad</mark>a <mark>Love</mark>lace" {
+		t.Fatalf("expected every query word to match by prefix, got %+v", results)
+	}
+	if results = index.Search("hop", 10); len(results) != 1 || results[0].Snippet != "Grace &lt;<mark>Hop</mark>per&gt;" {
+		t.Fatalf("expected an escaped snippet, got %+v", results)
+	}
+	if results = index.Search("ada", 1); len(results) != 1 {
+		t.Fatalf("expected the limit to cap results, got %+v", results)
+	}
+	if results = index.Search("ada zzz", 10); len(results) != 0 {
+		t.Fatalf("expected no result when a query word matches nothing, got %+v", results)
+	}
+	if results = index.Search(" - ", 10); len(results) != 0 {
+		t.Fatalf("expected no result for a query without words, got %+v", results)
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// UnavailabilityRule repeats unavailable hours every week on the given
+// weekdays, from StartDate through the optional UntilDate. Exactly one of
+// PersonID and GroupID is set. Reports and capacity views expand rules into
+// daily unavailability alongside the single-day entries.
+type UnavailabilityRule struct {
+	ID             string `json:"id"`
+	OrganisationID string `json:"organisation_id"`
+	PersonID       string `json:"person_id,omitempty"`
+	GroupID        string `json:"group_id,omitempty"`
+	// Weekdays lists lowercase English day names such as "friday".
+	Weekdays  []string  `json:"weekdays"`
+	Hours     float64   `json:"hours"`
+	StartDate string    `json:"start_date"`
+	UntilDate string    `json:"until_date,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `json:"version"`
+}
+
+// Normalized returns the rule with trimmed dates and lowercase, trimmed,
+// deduplicated weekdays.
+func (r UnavailabilityRule) Normalized() UnavailabilityRule {
+	weekdays := make([]string, 0, len(r.Weekdays))
+	for _, weekday := range r.Weekdays {
+		weekday = strings.ToLower(strings.TrimSpace(weekday))
+		if !slices.Contains(weekdays, weekday) {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+	r.Weekdays = weekdays
+	r.StartDate = strings.TrimSpace(r.StartDate)
+	r.UntilDate = strings.TrimSpace(r.UntilDate)
+	return r
+}
+
+// Validate checks the weekdays, hours, and date range of a normalized rule.
+func (r UnavailabilityRule) Validate() error {
+	if len(r.Weekdays) == 0 {
+		return ErrValidation
+	}
+	for _, weekday := range r.Weekdays {
+		if _, ok := parseWeekday(weekday); !ok {
+			return ErrValidation
+		}
+	}
+	if math.IsNaN(r.Hours) || math.IsInf(r.Hours, 0) || r.Hours <= 0 {
+		return ErrValidation
+	}
+	if _, err := ValidateDate(r.StartDate); err != nil {
+		return ErrValidation
+	}
+	if r.UntilDate != "" {
+		if _, err := ValidateDate(r.UntilDate); err != nil {
+			return ErrValidation
+		}
+		if r.UntilDate < r.StartDate {
+			return ErrValidation
+		}
+	}
+	return nil
+}
+
+// AppliesOn reports whether the rule marks date as unavailable.
+func (r UnavailabilityRule) AppliesOn(date time.Time) bool {
+	day := date.Format(DateLayout)
+	if day < r.StartDate || (r.UntilDate != "" && day > r.UntilDate) {
+		return false
+	}
+	for _, name := range r.Weekdays {
+		if weekday, ok := parseWeekday(name); ok && weekday == date.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// expandUnavailabilityRules adds the hours of every rule to the person and
+// group unavailability lookups for each day it applies on between fromDate
+// and toDate.
+func expandUnavailabilityRules(
+	rules []UnavailabilityRule,
+	fromDate time.Time,
+	toDate time.Time,
+	personUnavailableHours map[string]float64,
+	groupUnavailableHours map[string]float64,
+) {
+	if len(rules) == 0 {
+		return
+	}
+	_ = iterateDateRange(fromDate, toDate, func(current time.Time) error {
+		dayKey := current.Format(DateLayout)
+		for _, rule := range rules {
+			if !rule.AppliesOn(current) {
+				continue
+			}
+			if rule.PersonID != "" {
+				personUnavailableHours[compoundDateKey(rule.PersonID, dayKey)] += rule.Hours
+			}
+			if rule.GroupID != "" {
+				groupUnavailableHours[compoundDateKey(rule.GroupID, dayKey)] += rule.Hours
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// WebhookEventAll subscribes a webhook to every event in WebhookEvents.
+const WebhookEventAll = "*"
+
+// WebhookEvents lists the events webhooks can subscribe to. They are the
+// telemetry event names of stored changes.
+var WebhookEvents = []string{
+	"organisation.updated",
+	"person.created",
+	"person.updated",
+	"person.deleted",
+	"person.allocations_ended",
+	"project.created",
+	"project.updated",
+	"project.archived",
+	"project.restored",
+	"project.deleted",
+	"project.allocations_reconciled",
+	"group.created",
+	"group.updated",
+	"group.deleted",
+	"allocation.created",
+	"allocation.updated",
+	"allocation.ended",
+	"allocation.deleted",
+	"allocation.imported",
+	"holiday.created",
+	"holiday.imported",
+	"holiday.deleted",
+	"person_unavailability.created",
+	"person_unavailability.deleted",
+	"group_unavailability.created",
+	"group_unavailability.deleted",
+	"unavailability_rule.created",
+	"unavailability_rule.updated",
+	"unavailability_rule.deleted",
+	"allocation_template.created",
+	"allocation_template.deleted",
+	"allocation_template.applied",
+	"scenario.created",
+	"scenario.updated",
+	"scenario.deleted",
+	"scenario.applied",
+}
+
+// Event is a stored change published to webhooks. Data carries the IDs of
+// the records involved.
+type Event struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"event"`
+	OrganisationID string            `json:"organisation_id"`
+	OccurredAt     time.Time         `json:"occurred_at"`
+	RequestID      string            `json:"request_id,omitempty"`
+	Data           map[string]string `json:"data"`
+}
+
+// Webhook is an endpoint that receives the organisation's events. Secret
+// signs every payload and is only returned when the webhook is created.
+type Webhook struct {
+	ID             string    `json:"id"`
+	OrganisationID string    `json:"organisation_id"`
+	URL            string    `json:"url"`
+	Events         []string  `json:"events"`
+	Secret         string    `json:"secret,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int64     `json:"version"`
+}
+
+// Subscribes reports whether the webhook wants the named event.
+func (w Webhook) Subscribes(event string) bool {
+	return slices.Contains(w.Events, WebhookEventAll) || slices.Contains(w.Events, event)
+}
+
+// WebhookDelivery records the outcome of delivering an event to a webhook.
+// Attempt is the number of attempts made, and StatusCode, Error, and At
+// describe the last one. StatusCode is zero when no response arrived.
+type WebhookDelivery struct {
+	ID             string    `json:"id"`
+	OrganisationID string    `json:"organisation_id"`
+	WebhookID      string    `json:"webhook_id"`
+	EventID        string    `json:"event_id"`
+	Event          string    `json:"event"`
+	Attempt        int       `json:"attempt"`
+	StatusCode     int       `json:"status_code,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	Succeeded      bool      `json:"succeeded"`
+	At             time.Time `json:"at"`
+}
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// WebhookAddressAllowed reports whether webhooks may be delivered to addr.
+// Loopback, link-local, private, shared, unspecified, and multicast
+// addresses are refused so a webhook cannot reach the server's own network.
+func WebhookAddressAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsUnspecified() &&
+		!addr.IsMulticast() &&
+		!sharedAddressSpace.Contains(addr)
+}
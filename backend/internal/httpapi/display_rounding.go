@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"math"
+
+	"plato/backend/internal/domain"
+)
+
+// maxDisplayDecimals caps the configurable display precision.
+const maxDisplayDecimals = 6
+
+// DisplayPrecision rounds response values to a number of decimal places.
+// The zero value leaves values untouched.
+type DisplayPrecision struct {
+	Enabled  bool
+	Decimals int
+}
+
+func (p DisplayPrecision) round(value float64) float64 {
+	if !p.Enabled || math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+	scale := math.Pow(10, float64(p.Decimals))
+	return math.Round(value*scale) / scale
+}
+
+// displayRounding applies the configured precision to values while they are
+// written out. Services keep computing and storing exact values.
+type displayRounding struct {
+	fte     DisplayPrecision
+	percent DisplayPrecision
+}
+
+func (d displayRounding) allocation(allocation domain.Allocation) domain.Allocation {
+	allocation.Percent = d.percent.round(allocation.Percent)
+	return allocation
+}
+
+func (d displayRounding) allocations(allocations []domain.Allocation) []domain.Allocation {
+	if allocations == nil {
+		return nil
+	}
+	rounded := make([]domain.Allocation, len(allocations))
+	for i, allocation := range allocations {
+		rounded[i] = d.allocation(allocation)
+	}
+	return rounded
+}
+
+func (d displayRounding) activeAllocations(allocations []domain.ActiveAllocation) []domain.ActiveAllocation {
+	if allocations == nil {
+		return nil
+	}
+	rounded := make([]domain.ActiveAllocation, len(allocations))
+	for i, allocation := range allocations {
+		allocation.Allocation = d.allocation(allocation.Allocation)
+		rounded[i] = allocation
+	}
+	return rounded
+}
+
+// reportBuckets rounds percentages and, for FTE reports, the person load
+// figures. Project effort fields stay in hours and keep their precision.
+func (d displayRounding) reportBuckets(buckets []domain.ReportBucket, unit string) []domain.ReportBucket {
+	if buckets == nil {
+		return nil
+	}
+	rounded := make([]domain.ReportBucket, len(buckets))
+	for i, bucket := range buckets {
+		bucket.UtilizationPct = d.percent.round(bucket.UtilizationPct)
+		bucket.CompletionPct = d.percent.round(bucket.CompletionPct)
+		if unit == domain.ReportUnitFTE {
+			bucket.AvailabilityHours = d.fte.round(bucket.AvailabilityHours)
+			bucket.LoadHours = d.fte.round(bucket.LoadHours)
+			bucket.FreeHours = d.fte.round(bucket.FreeHours)
+			bucket.PeakLoadHours = d.fte.round(bucket.PeakLoadHours)
+		}
+		if bucket.Contributors != nil {
+			contributors := make([]domain.ReportContributor, len(bucket.Contributors))
+			for j, contributor := range bucket.Contributors {
+				contributor.Percent = d.percent.round(contributor.Percent)
+				contributors[j] = contributor
+			}
+			bucket.Contributors = contributors
+		}
+		rounded[i] = bucket
+	}
+	return rounded
+}
+
+func (d displayRounding) reportDiffBuckets(buckets []domain.ReportDiffBucket, unit string) []domain.ReportDiffBucket {
+	if buckets == nil || unit != domain.ReportUnitFTE {
+		return buckets
+	}
+	rounded := make([]domain.ReportDiffBucket, len(buckets))
+	for i, bucket := range buckets {
+		bucket.BaselineLoadHours = d.fte.round(bucket.BaselineLoadHours)
+		bucket.ComparisonLoadHours = d.fte.round(bucket.ComparisonLoadHours)
+		bucket.LoadHoursDelta = d.fte.round(bucket.LoadHoursDelta)
+		bucket.BaselineAvailabilityHours = d.fte.round(bucket.BaselineAvailabilityHours)
+		bucket.ComparisonAvailabilityHours = d.fte.round(bucket.ComparisonAvailabilityHours)
+		bucket.AvailabilityHoursDelta = d.fte.round(bucket.AvailabilityHoursDelta)
+		rounded[i] = bucket
+	}
+	return rounded
+}
@@ -0,0 +1,33 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"plato/backend/internal/domain"
+)
+
+// setVersionETag sends an entity's version as a strong ETag so clients can
+// hand it back in If-Match on the next update.
+func setVersionETag(w http.ResponseWriter, version int64) {
+	w.Header().Set(headerETag, strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ifMatchVersion returns the version an update must be based on. An If-Match
+// header wins over the version in the body. A missing header or "*" keeps the
+// body version, where 0 skips the check.
+func ifMatchVersion(r *http.Request, bodyVersion int64) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return bodyVersion, nil
+	}
+	tag, quoted := strings.CutPrefix(header, `"`)
+	tag, closed := strings.CutSuffix(tag, `"`)
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if !quoted || !closed || err != nil || version < 1 {
+		return 0, fmt.Errorf("%s must be a quoted version such as \"3\": %w", headerIfMatch, domain.ErrValidation)
+	}
+	return version, nil
+}
@@ -0,0 +1,177 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"plato/backend/internal/domain"
+)
+
+const (
+	fieldsQueryParam = "fields"
+	fieldID          = "id"
+)
+
+// fieldSelection is a sparse fieldset requested through the fields query
+// parameter. The id field is always kept. Strict selections reject names
+// the resource does not have instead of ignoring them.
+type fieldSelection struct {
+	requested []string
+	strict    bool
+}
+
+// fieldSelectionWriter carries a field selection from ServeHTTP to
+// writeJSON so handlers stay unaware of it.
+type fieldSelectionWriter struct {
+	http.ResponseWriter
+	selection fieldSelection
+}
+
+func (w fieldSelectionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func withFieldSelection(w http.ResponseWriter, r *http.Request, strict bool) http.ResponseWriter {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return w
+	}
+	requested := parseCSV(r.URL.Query().Get(fieldsQueryParam))
+	if len(requested) == 0 {
+		return w
+	}
+	return fieldSelectionWriter{ResponseWriter: w, selection: fieldSelection{requested: requested, strict: strict}}
+}
+
+func fieldSelectionFor(w http.ResponseWriter) (fieldSelection, bool) {
+	for {
+		if selecting, ok := w.(fieldSelectionWriter); ok {
+			return selecting.selection, true
+		}
+		wrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return fieldSelection{}, false
+		}
+		w = wrapper.Unwrap()
+	}
+}
+
+// listPagePackage is the package of domain.ListPage, used to recognise its
+// instances, which reflection only names as ListPage[...].
+var listPagePackage = reflect.TypeOf(domain.ListPage[struct{}]{}).PkgPath()
+
+// apply reduces a resource or a list of resources to the selected fields.
+// A list page keeps its envelope and only its items are reduced. Other
+// bodies, such as wrapped objects and maps, pass through unchanged.
+func (s fieldSelection) apply(body any) (any, error) {
+	resourceType := reflect.TypeOf(body)
+	if resourceType == nil {
+		return body, nil
+	}
+	if resourceType.PkgPath() == listPagePackage && strings.HasPrefix(resourceType.Name(), "ListPage[") {
+		return s.applyToPage(body)
+	}
+	isList := resourceType.Kind() == reflect.Slice
+	if isList {
+		resourceType = resourceType.Elem()
+	}
+	if resourceType.Kind() == reflect.Pointer {
+		resourceType = resourceType.Elem()
+	}
+	if resourceType.Kind() != reflect.Struct {
+		return body, nil
+	}
+
+	if s.strict {
+		known := jsonFieldNames(resourceType)
+		for _, name := range s.requested {
+			if !known[name] {
+				return nil, fmt.Errorf("unknown field %q", name)
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	if !isList {
+		var resource map[string]json.RawMessage
+		if err = json.Unmarshal(encoded, &resource); err != nil {
+			return nil, err
+		}
+		return s.filter(resource), nil
+	}
+
+	var resources []map[string]json.RawMessage
+	if err = json.Unmarshal(encoded, &resources); err != nil {
+		return nil, err
+	}
+	selected := make([]map[string]json.RawMessage, 0, len(resources))
+	for _, resource := range resources {
+		selected = append(selected, s.filter(resource))
+	}
+	return selected, nil
+}
+
+func (s fieldSelection) applyToPage(page any) (any, error) {
+	items, err := s.apply(reflect.ValueOf(page).FieldByName("Items").Interface())
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(page)
+	if err != nil {
+		return nil, err
+	}
+	var envelope map[string]json.RawMessage
+	if err = json.Unmarshal(encoded, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope["items"], err = json.Marshal(items); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+func (s fieldSelection) filter(resource map[string]json.RawMessage) map[string]json.RawMessage {
+	selected := make(map[string]json.RawMessage, len(s.requested)+1)
+	if value, ok := resource[fieldID]; ok {
+		selected[fieldID] = value
+	}
+	for _, name := range s.requested {
+		if value, ok := resource[name]; ok {
+			selected[name] = value
+		}
+	}
+	return selected
+}
+
+// jsonFieldNames lists the JSON keys a struct type can encode, including
+// the keys of embedded structs.
+func jsonFieldNames(structType reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded := range jsonFieldNames(field.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
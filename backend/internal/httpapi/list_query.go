@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"plato/backend/internal/domain"
+)
+
+const (
+	listLimitParam  = "limit"
+	listOffsetParam = "offset"
+	listCursorParam = "cursor"
+	listSortParam   = "sort"
+)
+
+// listReservedParams are the list query parameters that are never filters.
+var listReservedParams = []string{listLimitParam, listOffsetParam, listCursorParam, listSortParam, fieldsQueryParam}
+
+// listQueryFor reads the limit, offset, cursor, sort, and field filter
+// parameters of a list request for records of type T. Every other query
+// parameter except fields and the endpoint's own options is a filter on a
+// listable field of T, with comma separated or repeated values, and an
+// unknown one is a validation error. paged is true when the request pages or
+// sorts. Only then the endpoint answers with a page envelope, so a filtered
+// request without paging still gets a bare array.
+func listQueryFor[T any](r *http.Request, options ...string) (query domain.ListQuery, paged bool, err error) {
+	values := r.URL.Query()
+	fields := domain.ListFields[T]()
+	for name, raw := range values {
+		if slices.Contains(listReservedParams, name) || slices.Contains(options, name) {
+			continue
+		}
+		if !fields[name] {
+			return domain.ListQuery{}, false, fmt.Errorf("cannot filter by %q: %w", name, domain.ErrValidation)
+		}
+		if query.Filters == nil {
+			query.Filters = map[string][]string{}
+		}
+		for _, value := range raw {
+			query.Filters[name] = append(query.Filters[name], parseCSV(value)...)
+		}
+	}
+	for _, name := range []string{listLimitParam, listOffsetParam, listCursorParam, listSortParam} {
+		paged = paged || values.Has(name)
+	}
+	if !paged {
+		return query, false, nil
+	}
+
+	query.Limit = domain.DefaultListLimit
+	if raw := strings.TrimSpace(values.Get(listLimitParam)); raw != "" {
+		if query.Limit, err = strconv.Atoi(raw); err != nil || query.Limit < 1 {
+			return domain.ListQuery{}, true, fmt.Errorf("limit must be between 1 and %d: %w", domain.MaxListLimit, domain.ErrValidation)
+		}
+	}
+	cursor := strings.TrimSpace(values.Get(listCursorParam))
+	offset := strings.TrimSpace(values.Get(listOffsetParam))
+	switch {
+	case cursor != "" && offset != "":
+		return domain.ListQuery{}, true, fmt.Errorf("cursor and offset cannot be combined: %w", domain.ErrValidation)
+	case cursor != "":
+		if query.Offset, err = domain.DecodeListCursor(cursor); err != nil {
+			return domain.ListQuery{}, true, err
+		}
+	case offset != "":
+		if query.Offset, err = strconv.Atoi(offset); err != nil {
+			return domain.ListQuery{}, true, fmt.Errorf("offset must be a whole number: %w", domain.ErrValidation)
+		}
+	}
+	for _, field := range parseCSV(values.Get(listSortParam)) {
+		name, descending := strings.CutPrefix(field, "-")
+		query.Sort = append(query.Sort, domain.SortField{Field: name, Descending: descending})
+	}
+	return query, true, nil
+}
+
+// nonNilPage keeps the items of an empty page encoded as [] instead of null.
+func nonNilPage[T any](page domain.ListPage[T]) domain.ListPage[T] {
+	page.Items = nonNilList(page.Items)
+	return page
+}
+
+// writeListPage answers a list request with the page envelope when it was
+// paged and with the bare array of matching records otherwise.
+func writeListPage[T any](w http.ResponseWriter, page domain.ListPage[T], paged bool) {
+	if !paged {
+		writeJSON(w, http.StatusOK, nonNilList(page.Items))
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilPage(page))
+}
@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"context"
+	"strings"
+
+	"plato/backend/internal/adapters/telemetry"
+	"plato/backend/internal/service"
+)
+
+const (
+	metricsRoutePath = "/metrics"
+	// unmatchedRoute labels requests that match no route template so
+	// arbitrary paths cannot grow the metric label space.
+	unmatchedRoute = "unmatched"
+)
+
+// registerServiceGauges adds the gauges that are read from the service at
+// scrape time. /metrics is served without authentication, so the gauges sum
+// over all organisations instead of naming them in labels.
+func registerServiceGauges(metrics *telemetry.PrometheusTelemetry, svc *service.Service) {
+	metrics.RegisterGauge(
+		"plato_active_allocations",
+		"Allocations covering today across all organisations, without expired holds.",
+		"",
+		func() (map[string]float64, error) {
+			counts, err := svc.ActiveAllocationCounts(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			total := 0
+			for _, count := range counts {
+				total += count
+			}
+			return map[string]float64{"": float64(total)}, nil
+		},
+	)
+}
+
+// routeTemplate returns the routeTable path that matches the escaped
+// request path. When several match, the one with the most literal segments
+// wins so /api/allocations/conflicts beats /api/allocations/{id}.
+func routeTemplate(escapedPath string) string {
+	segments := splitPath(escapedPath)
+	best := unmatchedRoute
+	bestLiterals := -1
+	for _, route := range routeTable {
+		templateSegments := splitPath(route.Path)
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+		literals := 0
+		matched := true
+		for index, segment := range templateSegments {
+			if strings.HasPrefix(segment, "{") {
+				continue
+			}
+			if segment != segments[index] {
+				matched = false
+				break
+			}
+			literals++
+		}
+		if matched && literals > bestLiterals {
+			best = route.Path
+			bestLiterals = literals
+		}
+	}
+	return best
+}
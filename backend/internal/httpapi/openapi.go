@@ -0,0 +1,769 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"plato/backend/internal/adapters/auth"
+	"plato/backend/internal/domain"
+)
+
+const (
+	openAPIRoutePath = "/api/openapi.json"
+	apiDocsRoutePath = "/api/docs"
+
+	contentTypeCSV      = "text/csv"
+	contentTypeCalendar = "text/calendar"
+	contentTypeHTML     = "text/html; charset=utf-8"
+	contentTypeText     = "text/plain"
+)
+
+// openAPIParameter documents one query parameter.
+type openAPIParameter struct {
+	name        string
+	schemaType  string
+	description string
+}
+
+// openAPIOperation documents one method on a routeTable path. request and
+// response are the Go types encoded in the bodies, nil when there is none.
+// alternatives are other bodies a query parameter can switch to, and pageOf
+// marks list endpoints that answer with a domain.ListPage of that type once
+// paging parameters are sent.
+type openAPIOperation struct {
+	summary       string
+	query         []openAPIParameter
+	request       reflect.Type
+	requestMedia  string
+	status        int
+	response      reflect.Type
+	responseMedia string
+	alternatives  []reflect.Type
+	pageOf        reflect.Type
+}
+
+// errorResponse is the body of every failed request. Dependents is only set
+// on 409 answers to deletes that still have dependent records.
+type errorResponse struct {
+	Error      string                   `json:"error"`
+	Dependents *domain.DeleteDependents `json:"dependents,omitempty"`
+}
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+type routeTableResponse struct {
+	Routes []routeDescriptor `json:"routes"`
+}
+
+type reportBucketsResponse struct {
+	Buckets []domain.ReportBucket `json:"buckets"`
+}
+
+type reportDiffResponse struct {
+	Buckets []domain.ReportDiffBucket `json:"buckets"`
+}
+
+type batchGetRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type groupMemberRequest struct {
+	PersonID string `json:"person_id"`
+}
+
+// webhookRequest is the body that registers a webhook. Events lists names
+// from domain.WebhookEvents, or "*" for all of them.
+type webhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// apiKeyRequest is the body that issues an API key. Roles lists org_admin,
+// org_user, or both.
+type apiKeyRequest struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+type endAllocationRequest struct {
+	EndDate string `json:"end_date"`
+	Reason  string `json:"reason"`
+}
+
+func dateQuery(name, description string) openAPIParameter {
+	return openAPIParameter{name: name, schemaType: "date", description: description}
+}
+
+func boolQuery(name, description string) openAPIParameter {
+	return openAPIParameter{name: name, schemaType: "boolean", description: description}
+}
+
+func stringQuery(name, description string) openAPIParameter {
+	return openAPIParameter{name: name, schemaType: "string", description: description}
+}
+
+var cascadeQuery = boolQuery("cascade", "Delete dependent allocations and unavailability entries in the same write.")
+
+// openAPIOperations documents every routeTable entry by path and method.
+// HEAD reuses the GET entry. Keep it in sync with routeTable.
+var openAPIOperations = map[string]map[string]openAPIOperation{
+	healthRoutePath: {
+		http.MethodGet: {summary: "Report that the backend is up", response: reflect.TypeFor[healthResponse]()},
+	},
+	metricsRoutePath: {
+		http.MethodGet: {summary: "Prometheus metrics when PLATO_METRICS_ENABLED is set", responseMedia: contentTypeText},
+	},
+	"/api/_routes": {
+		http.MethodGet: {summary: "List every route in development mode", response: reflect.TypeFor[routeTableResponse]()},
+	},
+	openAPIRoutePath: {
+		http.MethodGet: {summary: "This OpenAPI document", response: reflect.TypeFor[map[string]any]()},
+	},
+	apiDocsRoutePath: {
+		http.MethodGet: {summary: "Swagger UI for this document in development mode", responseMedia: contentTypeHTML},
+	},
+	"/api/organisations": {
+		http.MethodGet: {
+			summary:  "List organisations visible to the caller",
+			query:    []openAPIParameter{stringQuery("q", "Keep organisations whose name contains this text, ignoring case.")},
+			response: reflect.TypeFor[[]domain.Organisation](),
+		},
+		http.MethodPost: {summary: "Create an organisation", request: reflect.TypeFor[domain.Organisation](), status: http.StatusCreated, response: reflect.TypeFor[domain.Organisation]()},
+	},
+	"/api/organisations/{id}": {
+		http.MethodGet:    {summary: "Get an organisation", response: reflect.TypeFor[domain.Organisation]()},
+		http.MethodPut:    {summary: "Update an organisation", request: reflect.TypeFor[domain.Organisation](), response: reflect.TypeFor[domain.Organisation]()},
+		http.MethodDelete: {summary: "Delete an organisation and all of its records", status: http.StatusNoContent},
+	},
+	"/api/organisations/{id}/holidays": {
+		http.MethodGet: {
+			summary: "List organisation holidays",
+			query: []openAPIParameter{
+				stringQuery("year", "Keep holidays in this calendar year."),
+				dateQuery("from", "Keep holidays on or after this date."),
+				dateQuery("to", "Keep holidays on or before this date."),
+			},
+			response: reflect.TypeFor[[]domain.OrgHoliday](),
+		},
+		http.MethodPost: {summary: "Create an organisation holiday", request: reflect.TypeFor[domain.OrgHoliday](), status: http.StatusCreated, response: reflect.TypeFor[domain.OrgHoliday]()},
+	},
+	"/api/organisations/{id}/holidays/import": {
+		http.MethodPost: {
+			summary: "Create a year of holidays from the national dataset or an iCalendar file",
+			query: []openAPIParameter{
+				{name: "year", schemaType: "integer", description: "Import holidays of this calendar year."},
+				stringQuery("country", "Read the national holidays of this ISO country code instead of a calendar file."),
+				stringQuery("region", "Add the regional holidays of this region of the country."),
+				{name: "hours", schemaType: "number", description: "Hours per holiday, the organisation's hours per day by default."},
+			},
+			requestMedia: contentTypeCalendar,
+			response:     reflect.TypeFor[domain.HolidayImportResult](),
+		},
+	},
+	"/api/organisations/{id}/holidays/{holiday_id}": {
+		http.MethodDelete: {summary: "Delete an organisation holiday", status: http.StatusNoContent},
+	},
+	"/api/organisations/{id}/calendar": {
+		http.MethodDelete: {
+			summary:  "Purge holidays and unavailability before a date",
+			query:    []openAPIParameter{dateQuery("before", "Delete calendar entries dated before this day.")},
+			response: reflect.TypeFor[domain.CalendarPurgeResult](),
+		},
+	},
+	"/api/persons": {
+		http.MethodGet:  {summary: "List persons", response: reflect.TypeFor[[]domain.Person](), pageOf: reflect.TypeFor[domain.Person]()},
+		http.MethodPost: {summary: "Create a person", request: reflect.TypeFor[domain.Person](), status: http.StatusCreated, response: reflect.TypeFor[domain.Person]()},
+	},
+	"/api/persons/batch-get": {
+		http.MethodPost: {summary: "Get several persons by ID", request: reflect.TypeFor[batchGetRequest](), response: reflect.TypeFor[domain.PersonBatch]()},
+	},
+	"/api/persons/{id}": {
+		http.MethodGet:    {summary: "Get a person", response: reflect.TypeFor[domain.Person]()},
+		http.MethodPut:    {summary: "Update a person", request: reflect.TypeFor[domain.Person](), response: reflect.TypeFor[domain.Person]()},
+		http.MethodDelete: {summary: "Delete a person", query: []openAPIParameter{cascadeQuery}, status: http.StatusNoContent},
+	},
+	"/api/persons/{id}/unavailability": {
+		http.MethodGet:  {summary: "List unavailability of a person", response: reflect.TypeFor[[]domain.PersonUnavailability]()},
+		http.MethodPost: {summary: "Record unavailability of a person", request: reflect.TypeFor[domain.PersonUnavailability](), status: http.StatusCreated, response: reflect.TypeFor[domain.PersonUnavailability]()},
+	},
+	"/api/persons/{id}/unavailability/{entry_id}": {
+		http.MethodDelete: {summary: "Delete an unavailability entry of a person", status: http.StatusNoContent},
+	},
+	"/api/persons/{id}/unavailability/recurring": {
+		http.MethodGet:  {summary: "List recurring unavailability rules of a person", response: reflect.TypeFor[[]domain.UnavailabilityRule]()},
+		http.MethodPost: {summary: "Create a weekly unavailability rule for a person", request: reflect.TypeFor[domain.UnavailabilityRule](), status: http.StatusCreated, response: reflect.TypeFor[domain.UnavailabilityRule]()},
+	},
+	"/api/persons/{id}/unavailability/recurring/{rule_id}": {
+		http.MethodPut:    {summary: "Replace a recurring unavailability rule of a person", request: reflect.TypeFor[domain.UnavailabilityRule](), response: reflect.TypeFor[domain.UnavailabilityRule]()},
+		http.MethodDelete: {summary: "Delete a recurring unavailability rule of a person", status: http.StatusNoContent},
+	},
+	"/api/persons/{id}/peak-overallocation": {
+		http.MethodGet: {
+			summary:  "Find the worst overallocation of a person",
+			query:    []openAPIParameter{dateQuery("from", "First day of the range."), dateQuery("to", "Last day of the range.")},
+			response: reflect.TypeFor[domain.PeakOverallocation](),
+		},
+	},
+	"/api/persons/{id}/capacity": {
+		http.MethodGet: {
+			summary:  "Break down the capacity of a person day by day",
+			query:    []openAPIParameter{dateQuery("from_date", "First day of the range."), dateQuery("to_date", "Last day of the range.")},
+			response: reflect.TypeFor[domain.PersonCapacity](),
+		},
+	},
+	"/api/projects": {
+		http.MethodGet: {
+			summary:  "List projects",
+			query:    []openAPIParameter{boolQuery("include_archived", "Also list archived projects.")},
+			response: reflect.TypeFor[[]domain.Project](),
+			pageOf:   reflect.TypeFor[domain.Project](),
+		},
+		http.MethodPost: {summary: "Create a project", request: reflect.TypeFor[domain.Project](), status: http.StatusCreated, response: reflect.TypeFor[domain.Project]()},
+	},
+	"/api/projects/{id}": {
+		http.MethodGet: {summary: "Get a project", response: reflect.TypeFor[domain.Project]()},
+		http.MethodPut: {summary: "Update a project", request: reflect.TypeFor[domain.Project](), response: reflect.TypeFor[domain.Project]()},
+		http.MethodDelete: {
+			summary: "Archive a project, or remove it for good with purge",
+			query: []openAPIParameter{
+				boolQuery("purge", "Remove the project and its allocations instead of archiving it."),
+				cascadeQuery,
+			},
+			status: http.StatusNoContent,
+		},
+	},
+	"/api/projects/{id}/reconcile-allocations": {
+		http.MethodPost: {
+			summary:  "List or clip allocations outside the project dates",
+			query:    []openAPIParameter{stringQuery("mode", "report (default) lists the allocations, clip trims them.")},
+			response: reflect.TypeFor[domain.AllocationReconciliation](),
+		},
+	},
+	"/api/projects/{id}/restore": {
+		http.MethodPost: {summary: "Restore an archived project", response: reflect.TypeFor[domain.Project]()},
+	},
+	"/api/groups": {
+		http.MethodGet:  {summary: "List groups", response: reflect.TypeFor[[]domain.Group](), pageOf: reflect.TypeFor[domain.Group]()},
+		http.MethodPost: {summary: "Create a group", request: reflect.TypeFor[domain.Group](), status: http.StatusCreated, response: reflect.TypeFor[domain.Group]()},
+	},
+	"/api/groups/{id}": {
+		http.MethodGet:    {summary: "Get a group", response: reflect.TypeFor[domain.Group]()},
+		http.MethodPut:    {summary: "Update a group", request: reflect.TypeFor[domain.Group](), response: reflect.TypeFor[domain.Group]()},
+		http.MethodDelete: {summary: "Delete a group", query: []openAPIParameter{cascadeQuery}, status: http.StatusNoContent},
+	},
+	"/api/groups/{id}/members": {
+		http.MethodPost: {summary: "Add a member to a group", request: reflect.TypeFor[groupMemberRequest](), response: reflect.TypeFor[domain.Group]()},
+	},
+	"/api/groups/{id}/members/{person_id}": {
+		http.MethodDelete: {summary: "Remove a member from a group", response: reflect.TypeFor[domain.Group]()},
+	},
+	"/api/groups/{id}/unavailability": {
+		http.MethodGet:  {summary: "List unavailability of a group", response: reflect.TypeFor[[]domain.GroupUnavailability]()},
+		http.MethodPost: {summary: "Record unavailability of a group", request: reflect.TypeFor[domain.GroupUnavailability](), status: http.StatusCreated, response: reflect.TypeFor[domain.GroupUnavailability]()},
+	},
+	"/api/groups/{id}/unavailability/{entry_id}": {
+		http.MethodDelete: {summary: "Delete an unavailability entry of a group", status: http.StatusNoContent},
+	},
+	"/api/groups/{id}/unavailability/recurring": {
+		http.MethodGet:  {summary: "List recurring unavailability rules of a group", response: reflect.TypeFor[[]domain.UnavailabilityRule]()},
+		http.MethodPost: {summary: "Create a weekly unavailability rule for a group", request: reflect.TypeFor[domain.UnavailabilityRule](), status: http.StatusCreated, response: reflect.TypeFor[domain.UnavailabilityRule]()},
+	},
+	"/api/groups/{id}/unavailability/recurring/{rule_id}": {
+		http.MethodPut:    {summary: "Replace a recurring unavailability rule of a group", request: reflect.TypeFor[domain.UnavailabilityRule](), response: reflect.TypeFor[domain.UnavailabilityRule]()},
+		http.MethodDelete: {summary: "Delete a recurring unavailability rule of a group", status: http.StatusNoContent},
+	},
+	"/api/groups/{id}/allocations": {
+		http.MethodGet: {
+			summary:  "List allocations of a group",
+			query:    []openAPIParameter{boolQuery("resolve_members", "Also list the direct allocations of every member.")},
+			response: reflect.TypeFor[[]domain.Allocation](),
+		},
+	},
+	"/api/groups/{id}/capacity": {
+		http.MethodGet: {
+			summary: "Sum the capacity of a group's members per period",
+			query: []openAPIParameter{
+				dateQuery("from_date", "First day of the range."),
+				dateQuery("to_date", "Last day of the range."),
+				stringQuery("granularity", "Bucket period: day, week, month, quarter, or year. Defaults to week."),
+			},
+			response: reflect.TypeFor[domain.GroupCapacity](),
+		},
+	},
+	"/api/allocations": {
+		http.MethodGet: {
+			summary:      "List allocations",
+			query:        []openAPIParameter{dateQuery("active_on", "List allocations covering this day with target and project names instead.")},
+			response:     reflect.TypeFor[[]domain.Allocation](),
+			alternatives: []reflect.Type{reflect.TypeFor[[]domain.ActiveAllocation]()},
+			pageOf:       reflect.TypeFor[domain.Allocation](),
+		},
+		http.MethodPost: {summary: "Create an allocation", request: reflect.TypeFor[domain.Allocation](), status: http.StatusCreated, response: reflect.TypeFor[domain.Allocation]()},
+	},
+	"/api/allocations/import": {
+		http.MethodPost: {
+			summary:      "Create allocations from CSV rows that name persons, groups, and projects",
+			requestMedia: contentTypeCSV,
+			response:     reflect.TypeFor[domain.AllocationImportResult](),
+		},
+	},
+	"/api/allocations/validate": {
+		http.MethodPost: {
+			summary:  "Check an allocation without saving it",
+			query:    []openAPIParameter{boolQuery("check_limit", "Also check the daily allocation limit.")},
+			request:  reflect.TypeFor[domain.Allocation](),
+			response: reflect.TypeFor[domain.AllocationValidation](),
+		},
+	},
+	"/api/allocations/conflicts": {
+		http.MethodGet: {
+			summary:  "List overallocated stretches in the organisation",
+			query:    []openAPIParameter{dateQuery("from_date", "First day of the range."), dateQuery("to_date", "Last day of the range.")},
+			response: reflect.TypeFor[[]domain.AllocationConflict](),
+		},
+	},
+	"/api/allocations/{id}": {
+		http.MethodGet:    {summary: "Get an allocation", response: reflect.TypeFor[domain.Allocation]()},
+		http.MethodPut:    {summary: "Update an allocation", request: reflect.TypeFor[domain.Allocation](), response: reflect.TypeFor[domain.Allocation]()},
+		http.MethodDelete: {summary: "Delete an allocation", status: http.StatusNoContent},
+	},
+	"/api/allocations/{id}/end": {
+		http.MethodPost: {summary: "End an allocation early", request: reflect.TypeFor[endAllocationRequest](), response: reflect.TypeFor[domain.Allocation]()},
+	},
+	"/api/allocations/{id}/history": {
+		http.MethodGet: {summary: "List the changes to an allocation", response: reflect.TypeFor[[]domain.AllocationHistoryEntry]()},
+	},
+	"/api/allocation-templates": {
+		http.MethodGet:  {summary: "List allocation templates", response: reflect.TypeFor[[]domain.AllocationTemplate]()},
+		http.MethodPost: {summary: "Create an allocation template", request: reflect.TypeFor[domain.AllocationTemplate](), status: http.StatusCreated, response: reflect.TypeFor[domain.AllocationTemplate]()},
+	},
+	"/api/allocation-templates/{id}": {
+		http.MethodGet:    {summary: "Get an allocation template", response: reflect.TypeFor[domain.AllocationTemplate]()},
+		http.MethodDelete: {summary: "Delete an allocation template", status: http.StatusNoContent},
+	},
+	"/api/allocation-templates/{id}/apply": {
+		http.MethodPost: {
+			summary: "Create one allocation per template entry, all or none",
+			query: []openAPIParameter{
+				stringQuery("project_id", "Project to allocate to."),
+				dateQuery("start_date", "First day of the allocations. Defaults to the project start."),
+				dateQuery("end_date", "Last day of the allocations. Defaults to the project end."),
+			},
+			status:   http.StatusCreated,
+			response: reflect.TypeFor[[]domain.Allocation](),
+		},
+	},
+	"/api/scenarios": {
+		http.MethodGet:  {summary: "List scenarios", response: reflect.TypeFor[[]domain.ScenarioSummary]()},
+		http.MethodPost: {summary: "Create a scenario from the live allocations", request: reflect.TypeFor[domain.Scenario](), status: http.StatusCreated, response: reflect.TypeFor[domain.Scenario]()},
+	},
+	"/api/scenarios/{id}": {
+		http.MethodGet:    {summary: "Get a scenario with its allocations", response: reflect.TypeFor[domain.Scenario]()},
+		http.MethodDelete: {summary: "Delete a scenario", status: http.StatusNoContent},
+	},
+	"/api/scenarios/{id}/allocations": {
+		http.MethodGet:  {summary: "List the allocations of a scenario", response: reflect.TypeFor[[]domain.Allocation]()},
+		http.MethodPost: {summary: "Create an allocation in a scenario", request: reflect.TypeFor[domain.Allocation](), status: http.StatusCreated, response: reflect.TypeFor[domain.Allocation]()},
+	},
+	"/api/scenarios/{id}/allocations/{allocation_id}": {
+		http.MethodPut:    {summary: "Update an allocation in a scenario", request: reflect.TypeFor[domain.Allocation](), response: reflect.TypeFor[domain.Allocation]()},
+		http.MethodDelete: {summary: "Delete an allocation from a scenario", status: http.StatusNoContent},
+	},
+	"/api/scenarios/{id}/apply": {
+		http.MethodPost: {summary: "Write a scenario's changes to the live allocations", response: reflect.TypeFor[domain.ScenarioChanges]()},
+	},
+	"/api/reports/availability-load": {
+		http.MethodPost: {summary: "Report availability and load", request: reflect.TypeFor[domain.ReportRequest](), response: reflect.TypeFor[domain.ReportResult]()},
+	},
+	"/api/reports/what-if": {
+		http.MethodPost: {summary: "Report with proposed allocations", request: reflect.TypeFor[domain.WhatIfReportRequest](), response: reflect.TypeFor[reportBucketsResponse]()},
+	},
+	"/api/reports/diff": {
+		http.MethodPost: {summary: "Compare two report runs", request: reflect.TypeFor[domain.ReportDiffRequest](), response: reflect.TypeFor[reportDiffResponse]()},
+	},
+	"/api/reports/unallocated": {
+		http.MethodGet: {
+			summary: "List persons without allocation load",
+			query: []openAPIParameter{
+				dateQuery("as_of", "Day to check."),
+				dateQuery("from", "First day of a range to check instead."),
+				dateQuery("to", "Last day of a range to check instead."),
+			},
+			response: reflect.TypeFor[[]domain.Person](),
+		},
+	},
+	"/api/audit/allocations": {
+		http.MethodGet: {
+			summary:  "List allocation changes oldest first",
+			query:    []openAPIParameter{dateQuery("from", "First day in the organisation time zone."), dateQuery("to", "Last day in the organisation time zone.")},
+			response: reflect.TypeFor[[]domain.AllocationEvent](),
+		},
+	},
+	"/api/operations/undo": {
+		http.MethodPost: {summary: "Undo the caller's last allocation change", response: reflect.TypeFor[domain.UndoResult]()},
+	},
+	"/api/search": {
+		http.MethodGet: {
+			summary:  "Search persons, projects, and groups by name",
+			query:    []openAPIParameter{stringQuery("q", "Words the names must hold a word starting with, ignoring case.")},
+			response: reflect.TypeFor[[]domain.SearchResult](),
+		},
+	},
+	"/api/export": {
+		http.MethodPost: {
+			summary:  "Export the organisation as JSON, or as a zip of CSV files",
+			query:    []openAPIParameter{stringQuery("format", "json (default) or csv.")},
+			response: reflect.TypeFor[domain.TenantSnapshot](),
+		},
+	},
+	"/api/import": {
+		http.MethodPost: {
+			summary: "Import a snapshot as a new organisation",
+			query: []openAPIParameter{
+				stringQuery("format", "json (default) or csv for a zip of CSV files."),
+				boolQuery("dry_run", "Only report what would be imported."),
+			},
+			request:  reflect.TypeFor[domain.TenantSnapshot](),
+			response: reflect.TypeFor[domain.TenantImportResult](),
+		},
+	},
+	"/api/webhooks": {
+		http.MethodGet: {summary: "List webhooks without their secrets", response: reflect.TypeFor[[]domain.Webhook]()},
+		http.MethodPost: {
+			summary:  "Register a webhook. The response carries the signing secret, which is not shown again",
+			request:  reflect.TypeFor[webhookRequest](),
+			status:   http.StatusCreated,
+			response: reflect.TypeFor[domain.Webhook](),
+		},
+	},
+	"/api/webhooks/{id}": {
+		http.MethodGet:    {summary: "Get a webhook without its secret", response: reflect.TypeFor[domain.Webhook]()},
+		http.MethodDelete: {summary: "Delete a webhook and its delivery log", status: http.StatusNoContent},
+	},
+	"/api/webhooks/{id}/deliveries": {
+		http.MethodGet: {summary: "List the last delivery outcomes of a webhook oldest first", response: reflect.TypeFor[[]domain.WebhookDelivery]()},
+	},
+	"/api/api-keys": {
+		http.MethodGet: {summary: "List API keys without their hashes, revoked ones included", response: reflect.TypeFor[[]domain.APIKey]()},
+		http.MethodPost: {
+			summary:  "Issue an API key bound to the organisation. The response carries the key, which is not shown again",
+			request:  reflect.TypeFor[apiKeyRequest](),
+			status:   http.StatusCreated,
+			response: reflect.TypeFor[domain.APIKey](),
+		},
+	},
+	"/api/api-keys/{id}/revoke": {
+		http.MethodPost: {summary: "Revoke an API key", response: reflect.TypeFor[domain.APIKey]()},
+	},
+}
+
+// openAPIDocument builds the document once from routeTable and
+// openAPIOperations.
+var openAPIDocument = sync.OnceValues(func() ([]byte, error) {
+	return json.Marshal(buildOpenAPIDocument())
+})
+
+func buildOpenAPIDocument() map[string]any {
+	schemas := openAPISchemas{}
+	errorSchema := schemas.schemaFor(reflect.TypeFor[errorResponse]())
+	paths := map[string]any{}
+	for _, route := range routeTable {
+		item := map[string]any{}
+		for _, method := range route.Methods {
+			operation, ok := openAPIOperations[route.Path][method]
+			if method == http.MethodHead {
+				operation, ok = openAPIOperations[route.Path][http.MethodGet]
+			}
+			if !ok {
+				continue
+			}
+			item[strings.ToLower(method)] = schemas.operation(route.Path, method, operation, errorSchema)
+		}
+		paths[route.Path] = item
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "Plato API",
+			"version": "1",
+			"description": "Capacity planning API. Production requests send a bearer token or an X-API-Key. " +
+				"Development mode also accepts the X-User-ID, X-Org-ID, and X-Role headers. " +
+				"Every response carries X-Request-ID.",
+		},
+		"security": []any{map[string]any{"bearerAuth": []string{}}, map[string]any{"apiKeyAuth": []string{}}},
+		"paths":    paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKeyAuth": map[string]any{"type": "apiKey", "in": "header", "name": auth.HeaderAPIKey},
+			},
+		},
+	}
+}
+
+// openAPISchemas collects named component schemas for the structs it meets.
+type openAPISchemas map[string]any
+
+func (s openAPISchemas) operation(path, method string, operation openAPIOperation, errorSchema map[string]any) map[string]any {
+	tag, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(path, "/api"), "/"), "/")
+	result := map[string]any{
+		"summary":     operation.summary,
+		"operationId": openAPIOperationID(path, method),
+		"tags":        []string{tag},
+	}
+	if !strings.HasPrefix(path, "/api/") || path == openAPIRoutePath || path == apiDocsRoutePath {
+		result["security"] = []any{}
+	}
+
+	parameters := make([]any, 0)
+	for _, segment := range splitPath(path) {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			parameters = append(parameters, map[string]any{
+				"name": strings.TrimSuffix(name, "}"), "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+	}
+	for _, parameter := range operation.query {
+		parameters = append(parameters, map[string]any{
+			"name": parameter.name, "in": "query", "description": parameter.description, "schema": openAPIScalar(parameter.schemaType),
+		})
+	}
+	if operation.pageOf != nil {
+		parameters = append(parameters, openAPIPagingParameters()...)
+	}
+	if method == http.MethodPut {
+		parameters = append(parameters, map[string]any{
+			"name": headerIfMatch, "in": "header", "description": "Quoted version the update is based on, such as \"3\".", "schema": map[string]any{"type": "string"},
+		})
+	}
+	if len(parameters) > 0 {
+		result["parameters"] = parameters
+	}
+
+	if operation.request != nil || operation.requestMedia != "" {
+		result["requestBody"] = map[string]any{
+			"required": true,
+			"content":  s.content(operation.requestMedia, operation.request),
+		}
+	}
+
+	status := operation.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]any{"description": http.StatusText(status)}
+	if status != http.StatusNoContent && method != http.MethodHead {
+		content := s.content(operation.responseMedia, operation.response)
+		if len(operation.alternatives) > 0 || operation.pageOf != nil {
+			variants := []any{s.schemaFor(operation.response)}
+			for _, alternative := range operation.alternatives {
+				variants = append(variants, s.schemaFor(alternative))
+			}
+			if operation.pageOf != nil {
+				variants = append(variants, s.pageSchemaFor(operation.pageOf))
+			}
+			content[contentTypeJSON] = map[string]any{"schema": map[string]any{"oneOf": variants}}
+		}
+		success["content"] = content
+	}
+	result["responses"] = map[string]any{
+		strconv.Itoa(status): success,
+		"default": map[string]any{
+			"description": "Error",
+			"content":     map[string]any{contentTypeJSON: map[string]any{"schema": errorSchema}},
+		},
+	}
+	return result
+}
+
+func (s openAPISchemas) content(media string, body reflect.Type) map[string]any {
+	if media == "" {
+		media = contentTypeJSON
+	}
+	schema := map[string]any{"type": "string"}
+	if body != nil {
+		schema = s.schemaFor(body)
+	}
+	return map[string]any{media: map[string]any{"schema": schema}}
+}
+
+// pageSchemaFor documents the envelope PageList returns for items of type
+// item.
+func (s openAPISchemas) pageSchemaFor(item reflect.Type) map[string]any {
+	name := item.Name() + "Page"
+	if _, ok := s[name]; !ok {
+		s[name] = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"items":       map[string]any{"type": "array", "items": s.schemaFor(item)},
+				"total":       map[string]any{"type": "integer"},
+				"limit":       map[string]any{"type": "integer"},
+				"offset":      map[string]any{"type": "integer"},
+				"next_cursor": map[string]any{"type": "string"},
+			},
+		}
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// schemaFor describes a Go type the way encoding/json writes it. Named
+// structs become component schemas and are referenced by name.
+func (s openAPISchemas) schemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": s.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := s[name]; !ok {
+			s[name] = map[string]any{}
+			s[name] = s.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (s openAPISchemas) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	s.addStructFields(t, properties)
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+// addStructFields adds the JSON fields of t, flattening embedded structs
+// the way encoding/json does.
+func (s openAPISchemas) addStructFields(t reflect.Type, properties map[string]any) {
+	for index := range t.NumField() {
+		field := t.Field(index)
+		tag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			s.addStructFields(field.Type, properties)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.schemaFor(field.Type)
+	}
+}
+
+func openAPIScalar(schemaType string) map[string]any {
+	if schemaType == "date" {
+		return map[string]any{"type": "string", "format": "date"}
+	}
+	return map[string]any{"type": schemaType}
+}
+
+func openAPIPagingParameters() []any {
+	return []any{
+		map[string]any{"name": "limit", "in": "query", "description": fmt.Sprintf("Page size, default %d and at most %d. Paging or sorting switches the answer to a page envelope.", domain.DefaultListLimit, domain.MaxListLimit), "schema": map[string]any{"type": "integer"}},
+		map[string]any{"name": "offset", "in": "query", "description": "Records to skip.", "schema": map[string]any{"type": "integer"}},
+		map[string]any{"name": "cursor", "in": "query", "description": "next_cursor of the previous page.", "schema": map[string]any{"type": "string"}},
+		map[string]any{"name": "sort", "in": "query", "description": "Comma separated fields, with a leading - for descending order.", "schema": map[string]any{"type": "string"}},
+	}
+}
+
+// openAPIOperationID derives a stable ID such as getApiPersonsId.
+func openAPIOperationID(path, method string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	for _, segment := range splitPath(path) {
+		for part := range strings.FieldsFuncSeq(segment, func(r rune) bool {
+			return r == '{' || r == '}' || r == '_' || r == '-' || r == '.'
+		}) {
+			id.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return id.String()
+}
+
+// serveOpenAPI writes the OpenAPI document. It needs no authentication so
+// tools can fetch it before they hold a token.
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	body, err := openAPIDocument()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(body); err != nil {
+		log.Printf("write openapi document failed: err=%v", err)
+	}
+}
+
+// apiDocsPage loads Swagger UI from a CDN and points it at the document.
+const apiDocsPage = `<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Plato API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>SwaggerUIBundle({url: "` + openAPIRoutePath + `", dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
+
+// serveAPIDocs serves Swagger UI in development mode only. Production
+// routers answer 404.
+func (a *API) serveAPIDocs(w http.ResponseWriter, r *http.Request) {
+	if !a.exposeAPIDocs {
+		notFound(w)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeHTML)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(apiDocsPage)); err != nil {
+		log.Printf("write api docs failed: err=%v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// reportRetryAfterSeconds is the Retry-After hint sent when every report slot is busy.
+const reportRetryAfterSeconds = 1
+
+// reportLimiter caps how many reports are generated at once so heavy
+// reporting cannot starve the CRUD endpoints. A nil limiter is unlimited.
+type reportLimiter struct {
+	slots chan struct{}
+	// acquired runs while the slot is held, before the report starts.
+	// Tests use it to keep reports open.
+	acquired func()
+}
+
+func newReportLimiter(maxConcurrent int) *reportLimiter {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+	return &reportLimiter{slots: make(chan struct{}, maxConcurrent)}
+}
+
+// tryAcquire takes a report slot without waiting. The caller must call
+// release once the report is written.
+func (l *reportLimiter) tryAcquire() (release func(), ok bool) {
+	if l == nil {
+		return func() {}, true
+	}
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		return nil, false
+	}
+	if l.acquired != nil {
+		l.acquired()
+	}
+	return func() { <-l.slots }, true
+}
+
+func writeReportsBusy(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", strconv.Itoa(reportRetryAfterSeconds))
+	writeError(w, http.StatusServiceUnavailable, "too many reports are running, retry shortly")
+}
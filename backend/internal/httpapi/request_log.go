@@ -0,0 +1,140 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"plato/backend/internal/ports"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	// maxRequestIDLength bounds caller supplied IDs so they stay cheap to
+	// log and echo.
+	maxRequestIDLength = 128
+)
+
+// statusRecorder remembers what a request produced for the request log and
+// metrics: the status code, the caller, and an unexpected service error.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	auth   ports.AuthContext
+	err    error
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(body []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(body)
+}
+
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusCode returns the written status, or 200 when the handler wrote
+// nothing.
+func (w *statusRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func statusRecorderFor(w http.ResponseWriter) (*statusRecorder, bool) {
+	for {
+		if recorder, ok := w.(*statusRecorder); ok {
+			return recorder, true
+		}
+		wrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil, false
+		}
+		w = wrapper.Unwrap()
+	}
+}
+
+// recordAuth notes the authenticated caller for the request log.
+func recordAuth(w http.ResponseWriter, authCtx ports.AuthContext) {
+	if recorder, ok := statusRecorderFor(w); ok {
+		recorder.auth = authCtx
+	}
+}
+
+// recordError notes an error the client only sees as a generic 500 so the
+// request log keeps the cause.
+func recordError(w http.ResponseWriter, err error) {
+	if recorder, ok := statusRecorderFor(w); ok {
+		recorder.err = errors.Join(recorder.err, err)
+	}
+}
+
+// requestLoggerFor returns a JSON logger on stderr when request logging is
+// enabled and nil otherwise.
+func requestLoggerFor(runtimeConfig RuntimeConfig) *slog.Logger {
+	if !runtimeConfig.RequestLog {
+		return nil
+	}
+	return slog.New(slog.NewJSONHandler(os.Stderr, nil))
+}
+
+// requestIDFor returns the caller's X-Request-ID when it is safe to echo
+// and log, and a new random ID otherwise.
+func requestIDFor(r *http.Request) string {
+	requestID := strings.TrimSpace(r.Header.Get(requestIDHeader))
+	if validRequestID(requestID) {
+		return requestID
+	}
+	raw := make([]byte, 16)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
+
+// validRequestID accepts printable ASCII without spaces up to
+// maxRequestIDLength bytes.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for index := range len(requestID) {
+		if requestID[index] < '!' || requestID[index] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// logRequest writes one structured line per request. Unexpected service
+// errors raise the level to error and add the cause.
+func (a *API) logRequest(r *http.Request, recorder *statusRecorder, requestID string, duration time.Duration) {
+	level := slog.LevelInfo
+	attributes := []slog.Attr{
+		slog.String("request_id", requestID),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", recorder.statusCode()),
+		slog.Float64("duration_ms", float64(duration.Microseconds())/1000),
+		slog.String("organisation_id", recorder.auth.OrganisationID),
+		slog.String("user_id", recorder.auth.UserID),
+	}
+	if recorder.err != nil {
+		level = slog.LevelError
+		attributes = append(attributes, slog.String("error", recorder.err.Error()))
+	}
+	a.requestLog.LogAttrs(r.Context(), level, "http request", attributes...)
+}
@@ -1,34 +1,58 @@
 package httpapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"plato/backend/internal/adapters/auth"
 	"plato/backend/internal/adapters/impexp"
 	"plato/backend/internal/adapters/persistence"
 	"plato/backend/internal/adapters/telemetry"
+	"plato/backend/internal/adapters/webhooks"
 	"plato/backend/internal/ports"
 	"plato/backend/internal/service"
 )
 
 const (
-	maxJSONBodyBytes int64 = 1 << 20
-	dataFileEnvVar         = "PLATO_DATA_FILE"
-	healthRoutePath        = "/healthz"
+	maxJSONBodyBytes    int64 = 1 << 20
+	maxImportBodyBytes  int64 = 32 << 20
+	dataFileEnvVar            = "PLATO_DATA_FILE"
+	dataDirEnvVar             = "PLATO_DATA_DIR"
+	persistenceEnvVar         = "PLATO_PERSISTENCE"
+	databaseURLEnvVar         = "PLATO_DATABASE_URL"
+	sqlitePathEnvVar          = "PLATO_SQLITE_PATH"
+	defaultSQLitePath         = "./plato.db"
+	persistenceFile           = "file"
+	persistencePostgres       = "postgres"
+	persistenceSQLite         = "sqlite"
+	healthRoutePath           = "/healthz"
 )
 
 // API serves the backend HTTP API with auth, routing, and cleanup support.
 type API struct {
-	authProvider ports.AuthProvider
-	corsPolicy   corsPolicy
-	service      *service.Service
-	cleanup      func() error
-	closeOnce    sync.Once
-	closeErr     error
+	authProvider     ports.AuthProvider
+	corsPolicy       corsPolicy
+	securityHeaders  securityHeadersPolicy
+	validationStatus int
+	exposeRouteTable bool
+	exposeAPIDocs    bool
+	strictFields     bool
+	display          displayRounding
+	reports          *reportLimiter
+	metrics          *telemetry.PrometheusTelemetry
+	requestLog       *slog.Logger
+	service          *service.Service
+	cleanup          func() error
+	closeOnce        sync.Once
+	closeErr         error
 }
 
 type apiRouteMatcher func(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool
@@ -39,43 +63,123 @@ var apiRouteMatchers = []apiRouteMatcher{
 	matchProjectsRoute,
 	matchGroupsRoute,
 	matchAllocationsRoute,
+	matchAllocationTemplatesRoute,
+	matchScenariosRoute,
 	matchReportsRoute,
+	matchAuditRoute,
+	matchOperationsRoute,
+	matchSearchRoute,
+	matchTransferRoute,
+	matchWebhooksRoute,
+	matchAPIKeysRoute,
+	matchRouteTableRoute,
 }
 
 // NewRouter constructs a router from runtime configuration and default adapters.
 func NewRouter(runtimeConfig RuntimeConfig) (http.Handler, error) {
-	dataFile := strings.TrimSpace(os.Getenv(dataFileEnvVar))
-	repo, err := persistence.NewFileRepository(dataFile)
+	repo, dataFile, err := newRepositoryFromEnv(runtimeConfig)
 	if err != nil {
-		return nil, fmt.Errorf("create repository (%q): %w", dataFile, err)
+		return nil, err
 	}
+	cleanup := repo.Close
 	cleanupOnError := func(cause error) error {
-		if closeErr := repo.Close(); closeErr != nil {
+		if closeErr := cleanup(); closeErr != nil {
 			return fmt.Errorf("%w (cleanup repository: %s)", cause, closeErr.Error())
 		}
 		return cause
 	}
+	repo.EnableDebouncedSave(runtimeConfig.PersistDebounce, runtimeConfig.PersistMaxDelay)
+
+	var metrics *telemetry.PrometheusTelemetry
+	if runtimeConfig.MetricsEnabled {
+		metrics = telemetry.NewPrometheusTelemetry()
+		repo.SetOperationObserver(metrics.ObserveRepositoryOperation)
+	}
+
+	telemetryAdapter, err := telemetryFromConfig(runtimeConfig, metrics)
+	if err != nil {
+		return nil, cleanupOnError(err)
+	}
+	if closer, ok := telemetryAdapter.(interface{ Close() error }); ok {
+		cleanup = func() error {
+			return errors.Join(repo.Close(), closer.Close())
+		}
+	}
 
-	svc, err := service.New(repo, telemetry.NewNoopTelemetry(), impexp.NewNoopImportExport())
+	var serviceRepo ports.Repository = repo
+	if runtimeConfig.RepositoryCacheTTL > 0 {
+		serviceRepo = persistence.NewCachingRepository(repo, runtimeConfig.RepositoryCacheTTL)
+	}
+	svc, err := service.New(serviceRepo, telemetryAdapter, impexp.NewCSVImportExport())
 	if err != nil {
 		return nil, cleanupOnError(fmt.Errorf("create service (%q): %w", dataFile, err))
 	}
+	if metrics != nil {
+		registerServiceGauges(metrics, svc)
+	}
+
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{AllowPrivateTargets: runtimeConfig.AllowPrivateWebhooks})
+	svc.SetEventPublisher(dispatcher)
+	closeRepository := cleanup
+	cleanup = func() error {
+		return errors.Join(dispatcher.Close(), closeRepository())
+	}
+
+	svc.SetQuotas(service.Quotas{
+		MaxPersonsPerOrganisation:  runtimeConfig.MaxPersonsPerOrganisation,
+		MaxProjectsPerOrganisation: runtimeConfig.MaxProjectsPerOrganisation,
+	})
+	svc.SetUniqueOrganisationNames(runtimeConfig.UniqueOrganisationNames)
+	svc.SetAllowPrivateWebhooks(runtimeConfig.AllowPrivateWebhooks)
+	if err = seedDemoTenant(svc, runtimeConfig); err != nil {
+		return nil, cleanupOnError(err)
+	}
 
-	authProvider, err := authProviderFromMode(runtimeConfig.Mode)
+	authProvider, err := authProviderFromConfig(runtimeConfig)
 	if err != nil {
 		return nil, cleanupOnError(err)
 	}
+	authProvider = auth.NewAPIKeyAuthProvider(svc, authProvider)
 
 	api := &API{
-		authProvider: authProvider,
-		corsPolicy:   newCORSPolicy(runtimeConfig),
-		service:      svc,
-		cleanup:      repo.Close,
+		authProvider:     authProvider,
+		corsPolicy:       newCORSPolicy(runtimeConfig),
+		securityHeaders:  newSecurityHeadersPolicy(runtimeConfig),
+		validationStatus: validationStatusFor(runtimeConfig),
+		exposeRouteTable: runtimeConfig.Mode.IsDevelopment(),
+		exposeAPIDocs:    runtimeConfig.Mode.IsDevelopment(),
+		strictFields:     runtimeConfig.StrictFieldSelection,
+		display:          displayRounding{fte: runtimeConfig.FTEPrecision, percent: runtimeConfig.PercentPrecision},
+		reports:          newReportLimiter(runtimeConfig.MaxConcurrentReports),
+		metrics:          metrics,
+		requestLog:       requestLoggerFor(runtimeConfig),
+		service:          svc,
+		cleanup:          cleanup,
 	}
 
 	return api, nil
 }
 
+// telemetryFromConfig returns the NDJSON file adapter when a telemetry file
+// is configured, the metrics adapter when metrics are enabled, both when
+// both are set, and the no-op adapter otherwise.
+func telemetryFromConfig(runtimeConfig RuntimeConfig, metrics *telemetry.PrometheusTelemetry) (ports.Telemetry, error) {
+	if runtimeConfig.TelemetryFile == "" {
+		if metrics != nil {
+			return metrics, nil
+		}
+		return telemetry.NewNoopTelemetry(), nil
+	}
+	adapter, err := telemetry.NewNDJSONTelemetry(runtimeConfig.TelemetryFile, runtimeConfig.TelemetryMaxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("create telemetry: %w", err)
+	}
+	if metrics != nil {
+		return telemetry.Fanout{adapter, metrics}, nil
+	}
+	return adapter, nil
+}
+
 // NewRouterFromEnv loads runtime configuration from the environment and constructs a router.
 func NewRouterFromEnv() (http.Handler, error) {
 	runtimeConfig, err := LoadRuntimeConfigFromEnv()
@@ -93,21 +197,137 @@ func NewRouterWithDependencies(authProvider ports.AuthProvider, svc *service.Ser
 			Mode:               RuntimeModeDevelopment,
 			AllowAnyCORSOrigin: true,
 		}),
-		service: svc,
+		validationStatus: http.StatusBadRequest,
+		exposeRouteTable: true,
+		exposeAPIDocs:    true,
+		service:          svc,
 	}
 }
 
-func authProviderFromMode(mode RuntimeMode) (ports.AuthProvider, error) {
-	if mode.IsDevelopment() {
-		return auth.NewDevAuthProvider(), nil
+// newRepositoryFromEnv opens the repository PLATO_PERSISTENCE selects. The
+// postgres backend connects to PLATO_DATABASE_URL and the sqlite backend
+// opens the database file PLATO_SQLITE_PATH names. The default file backend
+// uses per-organisation shards under PLATO_DATA_DIR, otherwise the single
+// file PLATO_DATA_FILE names.
+func newRepositoryFromEnv(runtimeConfig RuntimeConfig) (*persistence.FileRepository, string, error) {
+	dataFile := strings.TrimSpace(os.Getenv(dataFileEnvVar))
+	dataDir := strings.TrimSpace(os.Getenv(dataDirEnvVar))
+	switch backend := strings.ToLower(strings.TrimSpace(os.Getenv(persistenceEnvVar))); backend {
+	case "", persistenceFile:
+	case persistencePostgres:
+		return newPostgresRepositoryFromEnv(runtimeConfig, dataFile, dataDir)
+	case persistenceSQLite:
+		return newSQLiteRepositoryFromEnv(runtimeConfig, dataFile, dataDir)
+	default:
+		return nil, "", fmt.Errorf("%s must be %s, %s or %s, got %q", persistenceEnvVar, persistenceFile, persistencePostgres, persistenceSQLite, backend)
+	}
+	if dataDir == "" {
+		repo, err := persistence.NewFileRepository(dataFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("create repository (%q): %w", dataFile, err)
+		}
+		return repo, dataFile, nil
+	}
+	if dataFile != "" {
+		return nil, "", fmt.Errorf("%s and %s cannot both be set", dataFileEnvVar, dataDirEnvVar)
+	}
+	repo, err := persistence.NewShardedFileRepository(dataDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("create sharded repository (%q): %w", dataDir, err)
+	}
+	return repo, dataDir, nil
+}
+
+func newPostgresRepositoryFromEnv(runtimeConfig RuntimeConfig, dataFile, dataDir string) (*persistence.FileRepository, string, error) {
+	databaseURL := strings.TrimSpace(os.Getenv(databaseURLEnvVar))
+	if databaseURL == "" {
+		return nil, "", fmt.Errorf("%s=%s requires %s", persistenceEnvVar, persistencePostgres, databaseURLEnvVar)
+	}
+	if err := validateDatabaseBackendEnv(persistencePostgres, runtimeConfig, dataFile, dataDir); err != nil {
+		return nil, "", err
+	}
+	repo, err := persistence.NewPostgresRepository(context.Background(), databaseURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("create postgres repository: %w", err)
+	}
+	return repo, persistencePostgres, nil
+}
+
+func newSQLiteRepositoryFromEnv(runtimeConfig RuntimeConfig, dataFile, dataDir string) (*persistence.FileRepository, string, error) {
+	if err := validateDatabaseBackendEnv(persistenceSQLite, runtimeConfig, dataFile, dataDir); err != nil {
+		return nil, "", err
+	}
+	path := strings.TrimSpace(os.Getenv(sqlitePathEnvVar))
+	if path == "" {
+		path = defaultSQLitePath
+	}
+	repo, err := persistence.NewSQLiteRepository(context.Background(), path)
+	if err != nil {
+		return nil, "", fmt.Errorf("create sqlite repository (%q): %w", path, err)
+	}
+	return repo, path, nil
+}
+
+// validateDatabaseBackendEnv rejects file backend settings next to a database
+// backend.
+func validateDatabaseBackendEnv(backend string, runtimeConfig RuntimeConfig, dataFile, dataDir string) error {
+	if dataFile != "" || dataDir != "" {
+		return fmt.Errorf("%s and %s cannot be combined with %s=%s", dataFileEnvVar, dataDirEnvVar, persistenceEnvVar, backend)
+	}
+	// A debounced save that loses against another writer would drop every
+	// change it was holding, so database backends always save synchronously.
+	if runtimeConfig.PersistDebounce > 0 {
+		return fmt.Errorf("%s cannot be combined with %s=%s", envPersistDebounce, persistenceEnvVar, backend)
 	}
+	return nil
+}
 
-	provider, err := auth.NewJWTAuthProviderFromEnv()
+// seedDemoTenant seeds the demo organisation when PLATO_SEED_DEMO is set.
+// Production mode never seeds.
+func seedDemoTenant(svc *service.Service, runtimeConfig RuntimeConfig) error {
+	if !runtimeConfig.SeedDemo || !runtimeConfig.Mode.IsDevelopment() {
+		return nil
+	}
+	seeded, err := svc.SeedDemoTenant(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("create production auth provider: %w", err)
+		return fmt.Errorf("seed demo tenant: %w", err)
+	}
+	if seeded {
+		log.Printf("seeded demo organisation %q", service.DemoOrganisationName)
+	}
+	return nil
+}
+
+// authProviderFromConfig builds the configured auth provider. Without an
+// explicit choice development mode uses dev auth and production uses jwt.
+func authProviderFromConfig(runtimeConfig RuntimeConfig) (ports.AuthProvider, error) {
+	selected := runtimeConfig.AuthProvider
+	if selected == "" {
+		selected = AuthProviderJWT
+		if runtimeConfig.Mode.IsDevelopment() {
+			selected = AuthProviderDev
+		}
 	}
 
-	return provider, nil
+	switch selected {
+	case AuthProviderDev:
+		if !runtimeConfig.Mode.IsDevelopment() {
+			return nil, fmt.Errorf("%s auth is only available in development mode", AuthProviderDev)
+		}
+		return auth.NewDevAuthProvider(), nil
+	case AuthProviderOIDC:
+		provider, err := auth.NewOIDCAuthProvider(runtimeConfig.OIDC)
+		if err != nil {
+			return nil, fmt.Errorf("create oidc auth provider: %w", err)
+		}
+		return provider, nil
+	default:
+		provider, err := auth.NewJWTAuthProviderFromEnv()
+		if err != nil {
+			return nil, fmt.Errorf("create production auth provider: %w", err)
+		}
+		return provider, nil
+	}
 }
 
 // Close runs router cleanup at most once.
@@ -125,8 +345,30 @@ func (a *API) Close() error {
 	return a.closeErr
 }
 
-// ServeHTTP applies CORS, authenticates the request, and dispatches the API route.
+// ServeHTTP applies security headers and CORS, authenticates the request, and dispatches the API route.
 func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := requestIDFor(r)
+	w.Header().Set(requestIDHeader, requestID)
+	r = r.WithContext(ports.WithRequestID(r.Context(), requestID))
+	if a.metrics == nil && a.requestLog == nil {
+		a.serveRequest(w, r)
+		return
+	}
+
+	recorder := &statusRecorder{ResponseWriter: w}
+	started := time.Now()
+	a.serveRequest(recorder, r)
+	duration := time.Since(started)
+	if a.metrics != nil {
+		a.metrics.ObserveRequest(r.Method, routeTemplate(r.URL.EscapedPath()), recorder.statusCode(), duration)
+	}
+	if a.requestLog != nil {
+		a.logRequest(r, recorder, requestID, duration)
+	}
+}
+
+func (a *API) serveRequest(w http.ResponseWriter, r *http.Request) {
+	setSecurityHeaders(w, a.securityHeaders)
 	setCORS(w, r, a.corsPolicy)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusNoContent)
@@ -138,6 +380,20 @@ func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Path == metricsRoutePath && a.metrics != nil {
+		a.metrics.ServeHTTP(w, r)
+		return
+	}
+
+	switch r.URL.Path {
+	case openAPIRoutePath:
+		serveOpenAPI(w, r)
+		return
+	case apiDocsRoutePath:
+		a.serveAPIDocs(w, r)
+		return
+	}
+
 	if !strings.HasPrefix(r.URL.Path, "/api/") {
 		notFound(w)
 		return
@@ -148,8 +404,14 @@ func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusUnauthorized, "authentication failed")
 		return
 	}
+	recordAuth(w, authCtx)
 
-	segments := splitPath(r.URL.Path)
+	segments, err := decodePathSegments(splitPath(r.URL.EscapedPath()))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w = withFieldSelection(w, r, a.strictFields)
 	if a.dispatchRoute(w, r, authCtx, segments) {
 		return
 	}
@@ -227,10 +489,41 @@ func matchAllocationsRoute(api *API, w http.ResponseWriter, r *http.Request, aut
 }
 
 func matchReportsRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
-	if !isExactRoute(segments, "api", "reports", "availability-load") {
-		return false
+	if isExactRoute(segments, "api", "reports", "availability-load") {
+		api.handleReportAvailabilityLoad(w, r, authCtx)
+		return true
+	}
+	if isExactRoute(segments, "api", "reports", "what-if") {
+		api.handleReportWhatIf(w, r, authCtx)
+		return true
+	}
+	if isExactRoute(segments, "api", "reports", "diff") {
+		api.handleReportDiff(w, r, authCtx)
+		return true
+	}
+	if isExactRoute(segments, "api", "reports", "unallocated") {
+		api.handleReportUnallocated(w, r, authCtx)
+		return true
 	}
+	return false
+}
+
+func matchTransferRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
+	if isExactRoute(segments, "api", "export") {
+		api.handleExport(w, r, authCtx)
+		return true
+	}
+	if isExactRoute(segments, "api", "import") {
+		api.handleImport(w, r, authCtx)
+		return true
+	}
+	return false
+}
 
-	api.handleReportAvailabilityLoad(w, r, authCtx)
-	return true
+func matchAuditRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
+	if isExactRoute(segments, "api", "audit", "allocations") {
+		api.handleAuditAllocations(w, r, authCtx)
+		return true
+	}
+	return false
 }
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"plato/backend/internal/adapters/auth"
 	"plato/backend/internal/domain"
 )
 
@@ -16,6 +19,15 @@ type corsPolicy struct {
 	allowedOrigins map[string]struct{}
 	allowHeaders   string
 	allowMethods   string
+	exposeHeaders  string
+}
+
+// securityHeadersPolicy holds the security response headers applied in
+// production mode. Development mode leaves them off so local tooling can
+// frame and sniff responses freely.
+type securityHeadersPolicy struct {
+	enabled           bool
+	hstsMaxAgeSeconds int
 }
 
 const (
@@ -24,14 +36,17 @@ const (
 	contentTypeJSON                = "application/json"
 	headerOrigin                   = "Origin"
 	headerAccessControlAllowOrigin = "Access-Control-Allow-Origin"
+	headerETag                     = "ETag"
+	headerIfMatch                  = "If-Match"
 )
 
 func newCORSPolicy(config RuntimeConfig) corsPolicy {
 	policy := corsPolicy{
 		allowAnyOrigin: config.AllowAnyCORSOrigin,
 		allowedOrigins: make(map[string]struct{}, len(config.CORSAllowedOrigins)),
-		allowHeaders:   "Content-Type, Authorization, X-User-ID, X-Org-ID, X-Role",
+		allowHeaders:   "Content-Type, Authorization, X-User-ID, X-Org-ID, X-Role, If-Match, " + auth.HeaderAPIKey + ", " + requestIDHeader,
 		allowMethods:   "GET, POST, PUT, DELETE, OPTIONS",
+		exposeHeaders:  headerETag + ", " + requestIDHeader,
 	}
 	for _, origin := range config.CORSAllowedOrigins {
 		policy.allowedOrigins[origin] = struct{}{}
@@ -47,6 +62,26 @@ func splitPath(path string) []string {
 	return strings.Split(trimmed, "/")
 }
 
+var errEncodedSlashInPath = errors.New("path segments must not contain encoded slashes")
+
+// decodePathSegments percent-decodes segments split from the escaped path so
+// IDs match their stored form. A segment that decodes to a slash is rejected
+// instead of being split into extra segments.
+func decodePathSegments(segments []string) ([]string, error) {
+	decoded := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		value, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path segment %q: %w", segment, err)
+		}
+		if strings.Contains(value, "/") {
+			return nil, errEncodedSlashInPath
+		}
+		decoded = append(decoded, value)
+	}
+	return decoded, nil
+}
+
 func parseResourceID(segments []string) (string, bool) {
 	if len(segments) < 3 {
 		return "", false
@@ -93,8 +128,33 @@ func isSubresourceRoute(segments []string, subresource string) bool {
 	return ok && value == subresource
 }
 
+// headResponseWriter discards body writes so HEAD responses keep the GET
+// status and headers without transferring the payload.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(body []byte) (int, error) {
+	return len(body), nil
+}
+
+func (w headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func bodylessForHead(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	if r.Method != http.MethodHead {
+		return w
+	}
+	return headResponseWriter{ResponseWriter: w}
+}
+
+// methodNotAllowed answers 405 with an Allow header listing the route's
+// methods. OPTIONS is always included because ServeHTTP answers preflight
+// requests on every route.
 func methodNotAllowed(w http.ResponseWriter, methods ...string) {
-	w.Header().Set(headerAllow, strings.Join(methods, ", "))
+	allowed := append(append(make([]string, 0, len(methods)+1), methods...), http.MethodOptions)
+	w.Header().Set(headerAllow, strings.Join(allowed, ", "))
 	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 }
 
@@ -102,6 +162,20 @@ func notFound(w http.ResponseWriter) {
 	writeError(w, http.StatusNotFound, "not found")
 }
 
+// queryBool reads an optional boolean query flag. A missing or blank flag is
+// false and anything strconv.ParseBool rejects is a validation error.
+func queryBool(r *http.Request, name string) (bool, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean: %w", name, domain.ErrValidation)
+	}
+	return parsed, nil
+}
+
 func decodeJSON(w http.ResponseWriter, r *http.Request, target any) error {
 	r.Body = http.MaxBytesReader(w, r.Body, maxJSONBodyBytes)
 	decoder := json.NewDecoder(r.Body)
@@ -110,6 +184,14 @@ func decodeJSON(w http.ResponseWriter, r *http.Request, target any) error {
 }
 
 func writeJSON(w http.ResponseWriter, status int, body any) {
+	if selection, ok := fieldSelectionFor(w); ok && status < http.StatusMultipleChoices {
+		selected, err := selection.apply(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		body = selected
+	}
 	w.Header().Set(headerContentType, contentTypeJSON)
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(body); err != nil {
@@ -119,6 +201,15 @@ func writeJSON(w http.ResponseWriter, status int, body any) {
 	}
 }
 
+// nonNilList keeps list responses encoded as [] instead of null when a
+// tenant has no entries.
+func nonNilList[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, map[string]string{"error": message})
 }
@@ -131,7 +222,25 @@ func writeDecodeError(w http.ResponseWriter, err error) {
 	writeError(w, http.StatusBadRequest, message)
 }
 
-func writeServiceError(w http.ResponseWriter, err error) {
+// validationStatusFor returns the status used for semantic validation failures.
+// Malformed request bodies always use 400.
+func validationStatusFor(config RuntimeConfig) int {
+	if config.UnprocessableValidation {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+func (a *API) writeServiceError(w http.ResponseWriter, err error) {
+	writeServiceError(w, err, a.validationStatus)
+}
+
+func writeServiceError(w http.ResponseWriter, err error, validationStatus int) {
+	if validationStatus == 0 {
+		validationStatus = http.StatusBadRequest
+	}
+	var dependentsErr domain.DependentsError
+
 	switch {
 	case errors.Is(err, domain.ErrForbidden):
 		writeError(w, http.StatusForbidden, "forbidden")
@@ -143,18 +252,47 @@ func writeServiceError(w http.ResponseWriter, err error) {
 		if detailed != "" && detailed != domain.ErrValidation.Error() {
 			message = detailed
 		}
-		writeError(w, http.StatusBadRequest, message)
+		writeError(w, validationStatus, message)
 	case errors.Is(err, domain.ErrNotFound):
 		writeError(w, http.StatusNotFound, "not found")
+	case errors.Is(err, domain.ErrReadOnly):
+		writeError(w, http.StatusConflict, domain.ErrReadOnly.Error())
+	case errors.Is(err, domain.ErrOutsideWriteWindow):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.As(err, &dependentsErr):
+		writeJSON(w, http.StatusConflict, map[string]any{"error": dependentsErr.Error(), "dependents": dependentsErr.Dependents})
+	case errors.Is(err, domain.ErrConflict):
+		writeError(w, http.StatusConflict, domain.ErrConflict.Error())
 	default:
+		recordError(w, err)
 		writeError(w, http.StatusInternalServerError, "internal server error")
 	}
 }
 
+func newSecurityHeadersPolicy(config RuntimeConfig) securityHeadersPolicy {
+	return securityHeadersPolicy{
+		enabled:           config.Mode.IsProduction(),
+		hstsMaxAgeSeconds: config.HSTSMaxAgeSeconds,
+	}
+}
+
+func setSecurityHeaders(w http.ResponseWriter, policy securityHeadersPolicy) {
+	if !policy.enabled {
+		return
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if policy.hstsMaxAgeSeconds > 0 {
+		w.Header().Set("Strict-Transport-Security", fmt.Sprintf("max-age=%d; includeSubDomains", policy.hstsMaxAgeSeconds))
+	}
+}
+
 func setCORS(w http.ResponseWriter, r *http.Request, policy corsPolicy) {
 	if policy.allowAnyOrigin {
 		w.Header().Set("Access-Control-Allow-Headers", policy.allowHeaders)
 		w.Header().Set("Access-Control-Allow-Methods", policy.allowMethods)
+		w.Header().Set("Access-Control-Expose-Headers", policy.exposeHeaders)
 		w.Header().Set(headerAccessControlAllowOrigin, "*")
 		return
 	}
@@ -169,6 +307,7 @@ func setCORS(w http.ResponseWriter, r *http.Request, policy corsPolicy) {
 
 	w.Header().Set("Access-Control-Allow-Headers", policy.allowHeaders)
 	w.Header().Set("Access-Control-Allow-Methods", policy.allowMethods)
+	w.Header().Set("Access-Control-Expose-Headers", policy.exposeHeaders)
 	w.Header().Set(headerAccessControlAllowOrigin, origin)
 	w.Header().Set("Vary", headerOrigin)
 }
@@ -2,9 +2,12 @@ package httpapi
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"plato/backend/internal/domain"
 )
 
 const (
@@ -65,3 +68,45 @@ func TestSetCORS(t *testing.T) {
 		}
 	})
 }
+
+// TestNonNilList verifies the non nil list scenario.
+func TestNonNilList(t *testing.T) {
+	var missing []string
+	if got := nonNilList(missing); got == nil || len(got) != 0 {
+		t.Fatalf("expected empty non-nil list, got %#v", got)
+	}
+
+	items := []string{"a"}
+	if got := nonNilList(items); len(got) != 1 || got[0] != "a" {
+		t.Fatalf("expected list to pass through, got %#v", got)
+	}
+}
+
+// TestMethodNotAllowedListsOptions verifies the method not allowed lists options scenario.
+func TestMethodNotAllowedListsOptions(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	methodNotAllowed(recorder, http.MethodGet, http.MethodPost)
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get(headerAllow); got != "GET, POST, OPTIONS" {
+		t.Fatalf("expected Allow to list route methods and OPTIONS, got %q", got)
+	}
+
+	router := newTestRouter(t)
+	preflight := doJSONRequest(t, router, http.MethodOptions, "/api/persons", nil, nil)
+	if preflight.Code != http.StatusNoContent {
+		t.Fatalf("expected OPTIONS advertised in Allow to succeed, got %d", preflight.Code)
+	}
+}
+
+// TestWriteServiceErrorMapsConflict verifies the write service error maps conflict scenario.
+func TestWriteServiceErrorMapsConflict(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writeServiceError(recorder, fmt.Errorf("save organisation: %w", domain.ErrConflict), http.StatusBadRequest)
+
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a conflicting save, got %d", recorder.Code)
+	}
+}
@@ -6,17 +6,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"plato/backend/internal/adapters/auth"
 	"plato/backend/internal/adapters/impexp"
 	"plato/backend/internal/adapters/persistence"
 	"plato/backend/internal/adapters/telemetry"
+	"plato/backend/internal/adapters/webhooks"
 	"plato/backend/internal/domain"
 	"plato/backend/internal/ports"
 	"plato/backend/internal/service"
@@ -27,12 +35,181 @@ const (
 	routeProjects         = "/api/projects"
 	routePersons          = "/api/persons"
 	routeGroups           = "/api/groups"
+	routeWebhooks         = "/api/webhooks"
+	routeAPIKeys          = "/api/api-keys"
 	routeAvailabilityLoad = "/api/reports/availability-load"
 	envBoolTrue           = "true"
 	testOrgIDOne          = "org_1"
 	errCreateServiceFmt   = "create service: %v"
+	testMissingResourceID = "missing"
 )
 
+// TestListEndpointsReturnEmptyArraysForEmptyTenant verifies the list endpoints return empty arrays for empty tenant scenario.
+func TestListEndpointsReturnEmptyArraysForEmptyTenant(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+
+	emptyPaths := []string{
+		routePersons,
+		routeProjects,
+		routeGroups,
+		routeAllocations,
+		testOrganisationsPath + "/" + orgID + "/holidays",
+	}
+	for _, path := range emptyPaths {
+		assertEmptyJSONArray(t, doJSONRequest(t, router, http.MethodGet, path, nil, headers), path)
+	}
+
+	personID := createPerson(t, router, orgID, "Empty Lists", 100)
+	group := decodeCreatedGroupForMethodNotAllowed(t, router, personID, headers)
+	assertEmptyJSONArray(t, doJSONRequest(t, router, http.MethodGet, routePersons+"/"+personID+"/unavailability", nil, headers), "person unavailability")
+	assertEmptyJSONArray(t, doJSONRequest(t, router, http.MethodGet, routeGroups+"/"+group.ID+"/unavailability", nil, headers), "group unavailability")
+
+	otherOrgHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": "org_without_data"}
+	assertEmptyJSONArray(t, doJSONRequest(t, router, http.MethodGet, testOrganisationsPath, nil, otherOrgHeaders), testOrganisationsPath)
+}
+
+func assertEmptyJSONArray(t *testing.T, response *httptest.ResponseRecorder, label string) {
+	t.Helper()
+	if response.Code != http.StatusOK {
+		t.Fatalf("list %s failed: %d body=%s", label, response.Code, response.Body.String())
+	}
+	if body := strings.TrimSpace(response.Body.String()); body != "[]" {
+		t.Fatalf("expected empty JSON array for %s, got %s", label, body)
+	}
+}
+
+// TestPurgeOrganisationCalendarRemovesOnlyOldEntries verifies the purge organisation calendar removes only old entries scenario.
+func TestPurgeOrganisationCalendarRemovesOnlyOldEntries(t *testing.T) {
+	router := newTestRouter(t)
+	state := setupMethodNotAllowedState(t, router)
+	headers := state.adminHeaders
+	holidaysPath := testOrganisationsPath + "/" + state.orgID + "/holidays"
+	calendarPath := testOrganisationsPath + "/" + state.orgID + "/calendar"
+
+	oldEntries := []struct {
+		path string
+		date string
+	}{
+		{path: holidaysPath, date: "2024-12-25"},
+		{path: holidaysPath, date: "2025-12-31"},
+		{path: routePersons + "/" + state.personID + "/unavailability", date: "2025-06-01"},
+		{path: routeGroups + "/" + state.groupID + "/unavailability", date: "2025-07-01"},
+	}
+	for _, entry := range oldEntries {
+		response := doJSONRequest(t, router, http.MethodPost, entry.path, map[string]any{"date": entry.date, "hours": 2}, headers)
+		if response.Code != http.StatusCreated {
+			t.Fatalf("seed %s on %s failed: %d body=%s", entry.path, entry.date, response.Code, response.Body.String())
+		}
+	}
+
+	otherOrgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	otherHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": otherOrgID}
+	otherHoliday := doJSONRequest(t, router, http.MethodPost, testOrganisationsPath+"/"+otherOrgID+"/holidays", map[string]any{"date": "2024-01-01", "hours": 8}, otherHeaders)
+	if otherHoliday.Code != http.StatusCreated {
+		t.Fatalf("seed other organisation holiday failed: %d body=%s", otherHoliday.Code, otherHoliday.Body.String())
+	}
+
+	for _, invalidPath := range []string{calendarPath, calendarPath + "?before=2026-02-30"} {
+		if response := doJSONRequest(t, router, http.MethodDelete, invalidPath, nil, headers); response.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d body=%s", invalidPath, response.Code, response.Body.String())
+		}
+	}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": state.orgID}
+	if response := doJSONRequest(t, router, http.MethodDelete, calendarPath+"?before=2026-01-01", nil, userHeaders); response.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for org_user, got %d", response.Code)
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, calendarPath+"?before=2026-01-01", nil, otherHeaders); response.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for another tenant, got %d", response.Code)
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, calendarPath, nil, headers); response.Code != http.StatusMethodNotAllowed || response.Header().Get(headerAllow) != "DELETE, OPTIONS" {
+		t.Fatalf("expected 405 with Allow DELETE and OPTIONS, got %d allow=%q", response.Code, response.Header().Get(headerAllow))
+	}
+
+	purge := doJSONRequest(t, router, http.MethodDelete, calendarPath+"?before=2026-01-01", nil, headers)
+	if purge.Code != http.StatusOK {
+		t.Fatalf("purge calendar failed: %d body=%s", purge.Code, purge.Body.String())
+	}
+	var counts domain.CalendarPurgeResult
+	if err := json.Unmarshal(purge.Body.Bytes(), &counts); err != nil {
+		t.Fatalf("decode purge result: %v", err)
+	}
+	if counts != (domain.CalendarPurgeResult{OrgHolidays: 2, GroupUnavailability: 1, PersonUnavailability: 1}) {
+		t.Fatalf("unexpected purge counts: %+v", counts)
+	}
+
+	var holidays []domain.OrgHoliday
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, holidaysPath, nil, headers), &holidays)
+	if len(holidays) != 1 || holidays[0].ID != state.holidayID {
+		t.Fatalf("expected only the recent holiday to remain, got %+v", holidays)
+	}
+	var personEntries []domain.PersonUnavailability
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"/"+state.personID+"/unavailability", nil, headers), &personEntries)
+	if len(personEntries) != 1 || personEntries[0].ID != state.personUnavailabilityID {
+		t.Fatalf("expected only the recent person unavailability to remain, got %+v", personEntries)
+	}
+	var groupEntries []domain.GroupUnavailability
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeGroups+"/"+state.groupID+"/unavailability", nil, headers), &groupEntries)
+	if len(groupEntries) != 1 || groupEntries[0].ID != state.groupUnavailabilityID {
+		t.Fatalf("expected only the recent group unavailability to remain, got %+v", groupEntries)
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, routeAllocations+"/"+state.allocationID, nil, headers); response.Code != http.StatusOK {
+		t.Fatalf("expected allocation to survive the purge, got %d", response.Code)
+	}
+	var otherHolidays []domain.OrgHoliday
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, testOrganisationsPath+"/"+otherOrgID+"/holidays", nil, otherHeaders), &otherHolidays)
+	if len(otherHolidays) != 1 {
+		t.Fatalf("expected other tenant holidays to be untouched, got %+v", otherHolidays)
+	}
+
+	repeat := doJSONRequest(t, router, http.MethodDelete, calendarPath+"?before=2026-01-01", nil, headers)
+	if repeat.Code != http.StatusOK || !strings.Contains(repeat.Body.String(), `"org_holidays":0`) {
+		t.Fatalf("expected empty repeat purge, got %d body=%s", repeat.Code, repeat.Body.String())
+	}
+}
+
+func decodeJSONResponse(t *testing.T, response *httptest.ResponseRecorder, target any) {
+	t.Helper()
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d body=%s", response.Code, response.Body.String())
+	}
+	if err := json.Unmarshal(response.Body.Bytes(), target); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+}
+
+// TestRouterValidationStatusDistinguishesMalformedBodies verifies the router validation status distinguishes malformed bodies scenario.
+func TestRouterValidationStatusDistinguishesMalformedBodies(t *testing.T) {
+	adminHeaders := map[string]string{"X-Role": "org_admin"}
+	invalidOrganisation := map[string]any{"name": " ", "hours_per_day": 8, "hours_per_week": 40, "hours_per_year": 2080}
+
+	router := newTestRouter(t)
+	rec := doJSONRequest(t, router, http.MethodPost, testOrganisationsPath, invalidOrganisation, adminHeaders)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected legacy 400 for semantic validation, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	api, ok := router.(*API)
+	if !ok {
+		t.Fatalf("expected *API router, got %T", router)
+	}
+	api.validationStatus = validationStatusFor(RuntimeConfig{UnprocessableValidation: true})
+
+	rec = doJSONRequest(t, router, http.MethodPost, testOrganisationsPath, invalidOrganisation, adminHeaders)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for semantic validation, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doRawRequest(t, router, http.MethodPost, testOrganisationsPath, []byte(`{"name":`), adminHeaders)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed JSON, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doRawRequest(t, router, http.MethodPost, testOrganisationsPath, []byte(`{"unknown":true}`), adminHeaders)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown fields, got %d body=%s", rec.Code, rec.Body.String())
+	}
+}
+
 // TestHealthz verifies the healthz scenario.
 func TestHealthz(t *testing.T) {
 	router := newTestRouter(t)
@@ -89,6 +266,261 @@ func TestTenantScopingForPersonAndOrganisation(t *testing.T) {
 	}
 }
 
+// TestOrganisationsNameSearch verifies the organisations name search scenario.
+func TestOrganisationsNameSearch(t *testing.T) {
+	router := newTestRouter(t)
+	globalHeaders := map[string]string{"X-Role": "org_admin"}
+	createNamed := func(name string) string {
+		t.Helper()
+		response := doJSONRequest(t, router, http.MethodPost, testOrganisationsPath, map[string]any{
+			"name": name, "hours_per_day": 8, "hours_per_week": 40, "hours_per_year": 2080,
+		}, globalHeaders)
+		if response.Code != http.StatusCreated {
+			t.Fatalf("create organisation failed: %d body=%s", response.Code, response.Body.String())
+		}
+		var organisation domain.Organisation
+		if err := json.Unmarshal(response.Body.Bytes(), &organisation); err != nil {
+			t.Fatalf("decode organisation: %v", err)
+		}
+		return organisation.ID
+	}
+	northID := createNamed("Northwind Traders")
+	createNamed("Contoso")
+
+	var found []domain.Organisation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, testOrganisationsPath+"?q=NORTH", nil, globalHeaders), &found)
+	if len(found) != 1 || found[0].ID != northID {
+		t.Fatalf("expected the search to narrow to Northwind, got %+v", found)
+	}
+
+	tenantHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": northID}
+	var tenantFound []domain.Organisation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, testOrganisationsPath+"?q=contoso", nil, tenantHeaders), &tenantFound)
+	if len(tenantFound) != 0 {
+		t.Fatalf("expected a tenant admin not to find another organisation, got %+v", tenantFound)
+	}
+
+	overlong := doJSONRequest(t, router, http.MethodGet, testOrganisationsPath+"?q="+strings.Repeat("x", 101), nil, globalHeaders)
+	if overlong.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an overlong query, got %d", overlong.Code)
+	}
+}
+
+// TestPersonsBatchGet verifies the persons batch get scenario.
+func TestPersonsBatchGet(t *testing.T) {
+	router := newTestRouter(t)
+	orgA := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	orgB := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgA}
+	batchPath := routePersons + "/batch-get"
+
+	alice := createPerson(t, router, orgA, "Alice", 100)
+	bob := createPerson(t, router, orgA, "Bob", 80)
+	outsider := createPerson(t, router, orgB, "Mallory", 100)
+
+	var allFound domain.PersonBatch
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, batchPath, map[string]any{"ids": []string{alice, bob, alice}}, userHeaders), &allFound)
+	if len(allFound.Persons) != 2 || allFound.Persons[0].ID != alice || allFound.Persons[1].ID != bob || len(allFound.MissingIDs) != 0 {
+		t.Fatalf("unexpected all-found batch: %+v", allFound)
+	}
+
+	var partial domain.PersonBatch
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, batchPath, map[string]any{"ids": []string{bob, "missing", outsider}}, userHeaders), &partial)
+	if len(partial.Persons) != 1 || partial.Persons[0].ID != bob {
+		t.Fatalf("expected only the tenant person to be returned, got %+v", partial.Persons)
+	}
+	if !reflect.DeepEqual(partial.MissingIDs, []string{"missing", outsider}) {
+		t.Fatalf("expected missing and cross-tenant IDs to be reported missing, got %v", partial.MissingIDs)
+	}
+
+	if rec := doJSONRequest(t, router, http.MethodPost, batchPath, map[string]any{"ids": []string{" "}}, userHeaders); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty ID set, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if rec := doRawRequest(t, router, http.MethodPost, batchPath, []byte(`{"ids":`), userHeaders); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed batch payload, got %d", rec.Code)
+	}
+	if rec := doJSONRequest(t, router, http.MethodGet, batchPath, nil, userHeaders); rec.Code != http.StatusMethodNotAllowed || rec.Header().Get(headerAllow) != "POST, OPTIONS" {
+		t.Fatalf("expected 405 with POST allow header, got %d allow=%q", rec.Code, rec.Header().Get(headerAllow))
+	}
+}
+
+// TestAllocationOutsideProjectRangeReportsBounds verifies the allocation outside project range reports bounds scenario.
+func TestAllocationOutsideProjectRangeReportsBounds(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Dana", 100)
+	projectID := createProject(t, router, orgID, "Bounded")
+
+	created := doJSONRequest(t, router, http.MethodPost, routeAllocations, personAllocationPayload(personID, projectID, 50), headers)
+	if created.Code != http.StatusCreated {
+		t.Fatalf("create allocation failed: %d body=%s", created.Code, created.Body.String())
+	}
+	var allocation domain.Allocation
+	if err := json.Unmarshal(created.Body.Bytes(), &allocation); err != nil {
+		t.Fatalf("decode allocation: %v", err)
+	}
+
+	outOfRange := personAllocationPayload(personID, projectID, 50)
+	outOfRange["end_date"] = "2027-03-31"
+	assertBounds := func(rec *httptest.ResponseRecorder, expectedStatus int) {
+		t.Helper()
+		if rec.Code != expectedStatus {
+			t.Fatalf("expected status %d, got %d body=%s", expectedStatus, rec.Code, rec.Body.String())
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode validation response: %v", err)
+		}
+		if !strings.Contains(body["error"], "2026-01-01") || !strings.Contains(body["error"], "2026-12-31") {
+			t.Fatalf("expected project bounds in error detail, got %+v", body)
+		}
+	}
+
+	assertBounds(doJSONRequest(t, router, http.MethodPut, routeAllocations+"/"+allocation.ID, outOfRange, headers), http.StatusBadRequest)
+
+	api, ok := router.(*API)
+	if !ok {
+		t.Fatalf("expected *API router, got %T", router)
+	}
+	api.validationStatus = validationStatusFor(RuntimeConfig{UnprocessableValidation: true})
+	assertBounds(doJSONRequest(t, router, http.MethodPut, routeAllocations+"/"+allocation.ID, outOfRange, headers), http.StatusUnprocessableEntity)
+}
+
+// TestAllocationValidateEndpoint verifies the allocation validate endpoint scenario.
+func TestAllocationValidateEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Dana", 100)
+	projectID := createProject(t, router, orgID, "Bounded")
+	validatePath := routeAllocations + "/validate"
+
+	var valid domain.AllocationValidation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, validatePath+"?check_limit=true", personAllocationPayload(personID, projectID, 50), headers), &valid)
+	if !valid.Valid || len(valid.Errors) != 0 {
+		t.Fatalf("expected valid payload, got %+v", valid)
+	}
+
+	outOfRange := personAllocationPayload(personID, projectID, 50)
+	outOfRange["end_date"] = "2027-03-31"
+	var invalid domain.AllocationValidation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, validatePath, outOfRange, headers), &invalid)
+	if invalid.Valid || len(invalid.Errors) != 1 {
+		t.Fatalf("expected one field error, got %+v", invalid)
+	}
+	if invalid.Errors[0].Field != "end_date" || !strings.Contains(invalid.Errors[0].Message, "2026-12-31") {
+		t.Fatalf("expected end_date error with project end, got %+v", invalid.Errors[0])
+	}
+
+	overLimit := personAllocationPayload(personID, projectID, 400)
+	var limited domain.AllocationValidation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, validatePath+"?check_limit=true", overLimit, headers), &limited)
+	if limited.Valid || len(limited.Errors) != 1 || limited.Errors[0].Field != "percent" {
+		t.Fatalf("expected percent error with limit check, got %+v", limited)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodPost, validatePath+"?check_limit=maybe", overLimit, headers); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid check_limit, got %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, validatePath, nil, headers); response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", response.Code)
+	}
+
+	var allocations []domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeAllocations, nil, headers), &allocations)
+	if len(allocations) != 0 {
+		t.Fatalf("expected validation not to persist allocations, got %+v", allocations)
+	}
+}
+
+// TestProjectArchiveEndpoints verifies the project archive endpoints scenario.
+func TestProjectArchiveEndpoints(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Dana", 100)
+	projectID := createProject(t, router, orgID, "Finished")
+	projectPath := routeProjects + "/" + projectID
+
+	if response := doJSONRequest(t, router, http.MethodDelete, projectPath+"?purge=maybe", nil, headers); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid purge flag, got %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, projectPath, nil, headers); response.Code != http.StatusNoContent {
+		t.Fatalf("expected archive success, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	var projects []domain.Project
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeProjects, nil, headers), &projects)
+	if len(projects) != 0 {
+		t.Fatalf("expected archived project to be hidden, got %+v", projects)
+	}
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeProjects+"?include_archived=true", nil, headers), &projects)
+	if len(projects) != 1 || !projects[0].Archived {
+		t.Fatalf("expected archived project when included, got %+v", projects)
+	}
+
+	rejected := doJSONRequest(t, router, http.MethodPost, routeAllocations, personAllocationPayload(personID, projectID, 50), headers)
+	if rejected.Code != http.StatusBadRequest {
+		t.Fatalf("expected allocation against archived project to fail, got %d body=%s", rejected.Code, rejected.Body.String())
+	}
+
+	var restored domain.Project
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, projectPath+"/restore", nil, headers), &restored)
+	if restored.Archived {
+		t.Fatalf("expected restored project to be active, got %+v", restored)
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, projectPath+"?purge=true", nil, headers); response.Code != http.StatusNoContent {
+		t.Fatalf("expected purge success, got %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, projectPath, nil, headers); response.Code != http.StatusNotFound {
+		t.Fatalf("expected purged project to be gone, got %d", response.Code)
+	}
+}
+
+// TestAllocationEndEndpoint verifies the allocation end endpoint scenario.
+func TestAllocationEndEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Eve", 100)
+	projectID := createProject(t, router, orgID, "Shortened")
+
+	created := doJSONRequest(t, router, http.MethodPost, routeAllocations, personAllocationPayload(personID, projectID, 50), headers)
+	if created.Code != http.StatusCreated {
+		t.Fatalf("create allocation failed: %d body=%s", created.Code, created.Body.String())
+	}
+	var allocation domain.Allocation
+	if err := json.Unmarshal(created.Body.Bytes(), &allocation); err != nil {
+		t.Fatalf("decode allocation: %v", err)
+	}
+	endPath := routeAllocations + "/" + allocation.ID + "/end"
+
+	wrongMethod := doJSONRequest(t, router, http.MethodGet, endPath, nil, headers)
+	if wrongMethod.Code != http.StatusMethodNotAllowed || wrongMethod.Header().Get(headerAllow) != "POST, OPTIONS" {
+		t.Fatalf("expected 405 with POST allow header, got %d allow=%q", wrongMethod.Code, wrongMethod.Header().Get(headerAllow))
+	}
+	unknown := doJSONRequest(t, router, http.MethodPost, routeAllocations+"/"+allocation.ID+"/unknown", nil, headers)
+	if unknown.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown allocation subresource, got %d", unknown.Code)
+	}
+	malformed := doRawRequest(t, router, http.MethodPost, endPath, []byte(`{"end_date":`), headers)
+	if malformed.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed end payload, got %d", malformed.Code)
+	}
+	outOfRange := doJSONRequest(t, router, http.MethodPost, endPath, map[string]any{"end_date": "2027-01-01", "reason": "moved"}, headers)
+	if outOfRange.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for out of range end date, got %d body=%s", outOfRange.Code, outOfRange.Body.String())
+	}
+
+	ended := doJSONRequest(t, router, http.MethodPost, endPath, map[string]any{"end_date": "2026-06-30", "reason": "moved"}, headers)
+	var endedAllocation domain.Allocation
+	decodeJSONResponse(t, ended, &endedAllocation)
+	if endedAllocation.EndDate != "2026-06-30" || endedAllocation.EndReason != "moved" {
+		t.Fatalf("unexpected ended allocation: %+v", endedAllocation)
+	}
+}
+
 // TestAllocationValidationAndReportEndpoint verifies the allocation validation and report endpoint scenario.
 func TestAllocationValidationAndReportEndpoint(t *testing.T) {
 	router := newTestRouter(t)
@@ -145,7 +577,7 @@ func TestMethodAndJSONErrors(t *testing.T) {
 	if badMethod.Code != http.StatusMethodNotAllowed {
 		t.Fatalf("expected 405, got %d", badMethod.Code)
 	}
-	if got := badMethod.Header().Get(headerAllow); got != "GET, POST" {
+	if got := badMethod.Header().Get(headerAllow); got != "GET, POST, OPTIONS" {
 		t.Fatalf("expected allow header GET, POST for /api/persons method error, got %q", got)
 	}
 
@@ -458,117 +890,2377 @@ func verifyEndToEndRouterMetaEndpoints(t *testing.T, router http.Handler, state
 	if reportMethodNotAllowed.Code != http.StatusMethodNotAllowed {
 		t.Fatalf("expected report wrong method 405, got %d", reportMethodNotAllowed.Code)
 	}
-	if got := reportMethodNotAllowed.Header().Get(headerAllow); got != "POST" {
+	if got := reportMethodNotAllowed.Header().Get(headerAllow); got != "POST, OPTIONS" {
 		t.Fatalf("expected allow header POST for report endpoint, got %q", got)
 	}
 }
 
-func executeEndToEndDeletionFlow(t *testing.T, router http.Handler, state *endToEndCRUDRoutesState) {
-	t.Helper()
+func executeEndToEndDeletionFlow(t *testing.T, router http.Handler, state *endToEndCRUDRoutesState) {
+	t.Helper()
+
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/groups/"+state.groupID+"/unavailability/"+state.groupUnavailabilityID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete group unavailability success, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personA+"/unavailability/missing", nil, state.adminHeaders).Code; code != http.StatusNotFound {
+		t.Fatalf("expected delete person unavailability with missing entry to be not found, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personB+"/unavailability/"+state.personUnavailabilityID, nil, state.adminHeaders).Code; code != http.StatusForbidden {
+		t.Fatalf("expected delete person unavailability with mismatched person path to be forbidden, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personA+"/unavailability/"+state.personUnavailabilityID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete person unavailability success, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/organisations/"+state.orgID+"/holidays/"+state.holidayID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete holiday success, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/allocations/"+state.allocationID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete allocation success, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/projects/"+state.projectB, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete project success, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/projects/"+state.projectA, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete project A success, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/groups/"+state.groupID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete group success, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personB, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete person B success, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personA, nil, state.adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete person A success, got %d", code)
+	}
+}
+
+// TestRouterNewRouterAndAuthFailure verifies the router new router and auth failure scenario.
+func TestRouterNewRouterAndAuthFailure(t *testing.T) {
+	t.Setenv("DEV_MODE", envBoolTrue)
+	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "router-data.json"))
+	router, err := NewRouterFromEnv()
+	if err != nil {
+		t.Fatalf("create router: %v", err)
+	}
+	health := doRawRequest(t, router, http.MethodGet, "/healthz", nil, nil)
+	if health.Code != http.StatusOK {
+		t.Fatalf("expected health from NewRouter, got %d", health.Code)
+	}
+
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "auth-fail-data.json"))
+	if err != nil {
+		t.Fatalf("create repo: %v", err)
+	}
+	svc, err := service.New(repo, telemetry.NewNoopTelemetry(), impexp.NewNoopImportExport())
+	if err != nil {
+		t.Fatalf(errCreateServiceFmt, err)
+	}
+	routerWithFailingAuth := NewRouterWithDependencies(failingAuthProvider{}, svc)
+
+	response := doRawRequest(t, routerWithFailingAuth, http.MethodGet, testOrganisationsPath, nil, nil)
+	if response.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized for failing auth provider, got %d", response.Code)
+	}
+}
+
+// TestRouterNewRouterWritesTelemetryFile verifies the router new router writes telemetry file scenario.
+func TestRouterNewRouterWritesTelemetryFile(t *testing.T) {
+	dir := t.TempDir()
+	telemetryPath := filepath.Join(dir, "events.ndjson")
+	t.Setenv("DEV_MODE", envBoolTrue)
+	t.Setenv(dataFileEnvVar, filepath.Join(dir, "telemetry-data.json"))
+	t.Setenv(envTelemetryFile, telemetryPath)
+
+	router, err := NewRouterFromEnv()
+	if err != nil {
+		t.Fatalf("create router: %v", err)
+	}
+	api, ok := router.(*API)
+	if !ok {
+		t.Fatalf("expected *API router, got %T", router)
+	}
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	if closeErr := api.Close(); closeErr != nil {
+		t.Fatalf("close router: %v", closeErr)
+	}
+
+	raw, err := os.ReadFile(telemetryPath)
+	if err != nil {
+		t.Fatalf("read telemetry file: %v", err)
+	}
+	var event struct {
+		Name       string            `json:"name"`
+		Attributes map[string]string `json:"attributes"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(raw), &event); err != nil {
+		t.Fatalf("decode telemetry line %q: %v", raw, err)
+	}
+	if event.Name != "organisation.created" || event.Attributes["organisation_id"] != orgID {
+		t.Fatalf("expected organisation.created for %s, got %+v", orgID, event)
+	}
+}
+
+// TestRouterNewRouterServesMetrics verifies the router new router serves metrics scenario.
+func TestRouterNewRouterServesMetrics(t *testing.T) {
+	t.Setenv("DEV_MODE", envBoolTrue)
+	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "metrics-data.json"))
+	t.Setenv(envMetricsEnabled, envBoolTrue)
+
+	router, err := NewRouterFromEnv()
+	if err != nil {
+		t.Fatalf("create router: %v", err)
+	}
+	t.Cleanup(func() {
+		if api, ok := router.(*API); ok {
+			_ = api.Close()
+		}
+	})
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Metrics Person", 100)
+	project := projectPayload("Metrics Project")
+	project["start_date"] = "2000-01-01"
+	project["end_date"] = "2099-12-31"
+	createdProject := doJSONRequest(t, router, http.MethodPost, routeProjects, project, headers)
+	if createdProject.Code != http.StatusCreated {
+		t.Fatalf("create project failed: %d body=%s", createdProject.Code, createdProject.Body.String())
+	}
+	var projectRecord domain.Project
+	if err := json.Unmarshal(createdProject.Body.Bytes(), &projectRecord); err != nil {
+		t.Fatalf("decode project: %v", err)
+	}
+	allocation := personAllocationPayload(personID, projectRecord.ID, 50)
+	allocation["start_date"] = "2000-01-01"
+	allocation["end_date"] = "2099-12-31"
+	if response := doJSONRequest(t, router, http.MethodPost, routeAllocations, allocation, headers); response.Code != http.StatusCreated {
+		t.Fatalf("create allocation failed: %d body=%s", response.Code, response.Body.String())
+	}
+	missing := doJSONRequest(t, router, http.MethodGet, "/api/persons/person_missing", nil, headers)
+	if missing.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing person, got %d", missing.Code)
+	}
+
+	response := doJSONRequest(t, router, http.MethodGet, metricsRoutePath, nil, nil)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected 200 from metrics, got %d body=%s", response.Code, response.Body.String())
+	}
+	if contentType := response.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Fatalf("expected text exposition content type, got %q", contentType)
+	}
+	body := response.Body.String()
+	for _, want := range []string{
+		`plato_http_requests_total{method="POST",route="/api/organisations",status="201"} 1`,
+		`plato_http_requests_total{method="GET",route="/api/persons/{id}",status="404"} 1`,
+		`plato_http_request_duration_seconds_count{method="POST",route="/api/allocations"} 1`,
+		`plato_events_total{event="organisation.created"} 1`,
+		`plato_repository_operation_duration_seconds_count{operation="write"}`,
+		"\nplato_active_allocations 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected metrics to contain %s, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, orgID) {
+		t.Fatalf("expected unauthenticated metrics to leave out organisation ids, got:\n%s", body)
+	}
+}
+
+// TestRouterServesNoMetricsByDefault verifies the router serves no metrics by default scenario.
+func TestRouterServesNoMetricsByDefault(t *testing.T) {
+	router := newTestRouter(t)
+	response := doJSONRequest(t, router, http.MethodGet, metricsRoutePath, nil, nil)
+	if response.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without metrics enabled, got %d", response.Code)
+	}
+}
+
+// TestRouteTemplate verifies the route template scenario.
+func TestRouteTemplate(t *testing.T) {
+	cases := map[string]string{
+		"/api/persons":                 "/api/persons",
+		"/api/persons/person_1":        "/api/persons/{id}",
+		"/api/allocations/conflicts":   "/api/allocations/conflicts",
+		"/api/allocations/alloc_1/end": "/api/allocations/{id}/end",
+		"/api/unknown/path":            unmatchedRoute,
+		"/":                            unmatchedRoute,
+	}
+	for path, want := range cases {
+		if got := routeTemplate(path); got != want {
+			t.Fatalf("routeTemplate(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// recordingTelemetry keeps every event for assertions.
+type recordingTelemetry struct {
+	mu     sync.Mutex
+	events []map[string]string
+}
+
+func (r *recordingTelemetry) Record(name string, attributes map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := map[string]string{"name": name}
+	for key, value := range attributes {
+		event[key] = value
+	}
+	r.events = append(r.events, event)
+}
+
+// TestRequestLogCorrelatesRequests verifies the request log correlates requests scenario.
+func TestRequestLogCorrelatesRequests(t *testing.T) {
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "request-log.json"))
+	if err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	events := &recordingTelemetry{}
+	svc, err := service.New(repo, events, impexp.NewNoopImportExport())
+	if err != nil {
+		t.Fatalf(errCreateServiceFmt, err)
+	}
+	router := NewRouterWithDependencies(auth.NewDevAuthProvider(), svc)
+	var logs bytes.Buffer
+	router.(*API).requestLog = slog.New(slog.NewJSONHandler(&logs, nil))
+
+	response := doJSONRequest(t, router, http.MethodPost, testOrganisationsPath, map[string]any{
+		"name":           "Logged Org",
+		"hours_per_day":  8,
+		"hours_per_week": 40,
+		"hours_per_year": 2080,
+	}, map[string]string{"X-Role": "org_admin", "X-User-ID": "user_7", "X-Request-ID": "client-req-1"})
+	if response.Code != http.StatusCreated {
+		t.Fatalf("create organisation failed: %d body=%s", response.Code, response.Body.String())
+	}
+	if got := response.Header().Get("X-Request-ID"); got != "client-req-1" {
+		t.Fatalf("expected the caller request ID to be echoed, got %q", got)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log line %q: %v", logs.String(), err)
+	}
+	for key, want := range map[string]any{
+		"msg":        "http request",
+		"request_id": "client-req-1",
+		"method":     http.MethodPost,
+		"path":       testOrganisationsPath,
+		"status":     float64(http.StatusCreated),
+		"user_id":    "user_7",
+	} {
+		if entry[key] != want {
+			t.Fatalf("expected log %s=%v, got %v in %s", key, want, entry[key], logs.String())
+		}
+	}
+	if _, ok := entry["duration_ms"].(float64); !ok {
+		t.Fatalf("expected a numeric duration, got %s", logs.String())
+	}
+	if len(events.events) != 1 || events.events[0]["request_id"] != "client-req-1" {
+		t.Fatalf("expected the telemetry event to carry the request ID, got %+v", events.events)
+	}
+
+	for _, supplied := range []string{"", "has space", strings.Repeat("x", maxRequestIDLength+1)} {
+		headers := map[string]string{"X-Role": "org_admin"}
+		if supplied != "" {
+			headers["X-Request-ID"] = supplied
+		}
+		generated := doJSONRequest(t, router, http.MethodGet, testOrganisationsPath, nil, headers)
+		requestID := generated.Header().Get("X-Request-ID")
+		if requestID == supplied || len(requestID) != 32 {
+			t.Fatalf("expected a generated request ID for %q, got %q", supplied, requestID)
+		}
+	}
+}
+
+// TestWriteServiceErrorRecordsUnexpectedError verifies the write service error records unexpected error scenario.
+func TestWriteServiceErrorRecordsUnexpectedError(t *testing.T) {
+	recorder := &statusRecorder{ResponseWriter: httptest.NewRecorder()}
+	writeServiceError(recorder, errors.New("disk full"), 0)
+	if recorder.statusCode() != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", recorder.statusCode())
+	}
+	if recorder.err == nil || recorder.err.Error() != "disk full" {
+		t.Fatalf("expected the cause to be recorded, got %v", recorder.err)
+	}
+
+	recorder = &statusRecorder{ResponseWriter: httptest.NewRecorder()}
+	writeServiceError(recorder, domain.ErrNotFound, 0)
+	if recorder.err != nil {
+		t.Fatalf("expected mapped errors to stay out of the log, got %v", recorder.err)
+	}
+}
+
+// TestOpenAPIOperationsMatchRouteTable verifies the OpenAPI operations match route table scenario.
+func TestOpenAPIOperationsMatchRouteTable(t *testing.T) {
+	listed := map[string]bool{}
+	for _, route := range routeTable {
+		for _, method := range route.Methods {
+			listed[method+" "+route.Path] = true
+			lookup := method
+			if method == http.MethodHead {
+				lookup = http.MethodGet
+			}
+			if _, ok := openAPIOperations[route.Path][lookup]; !ok {
+				t.Errorf("routeTable lists %s %s without an OpenAPI operation", method, route.Path)
+			}
+		}
+	}
+	for path, operations := range openAPIOperations {
+		for method := range operations {
+			if !listed[method+" "+path] {
+				t.Errorf("OpenAPI documents %s %s which routeTable does not list", method, path)
+			}
+		}
+	}
+}
+
+// TestOpenAPIEndpoint verifies the OpenAPI endpoint scenario.
+func TestOpenAPIEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	response := doJSONRequest(t, router, http.MethodGet, openAPIRoutePath, nil, nil)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", response.Code, response.Body.String())
+	}
+	var document struct {
+		OpenAPI    string                               `json:"openapi"`
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(response.Body.Bytes(), &document); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	if document.OpenAPI != "3.1.0" {
+		t.Fatalf("expected OpenAPI 3.1.0, got %q", document.OpenAPI)
+	}
+	for _, route := range routeTable {
+		for _, method := range route.Methods {
+			if _, ok := document.Paths[route.Path][strings.ToLower(method)]; !ok {
+				t.Fatalf("expected %s %s in the document", method, route.Path)
+			}
+		}
+	}
+	if _, ok := document.Components.Schemas["Person"].Properties["employment_pct"]; !ok {
+		t.Fatalf("expected the Person schema to list employment_pct, got %+v", document.Components.Schemas["Person"])
+	}
+	if _, ok := document.Components.Schemas["WhatIfReportRequest"].Properties["granularity"]; !ok {
+		t.Fatal("expected embedded report request fields to be flattened")
+	}
+
+	refs := regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(response.Body.String(), -1)
+	if len(refs) == 0 {
+		t.Fatal("expected schema references in the document")
+	}
+	for _, ref := range refs {
+		if _, ok := document.Components.Schemas[ref[1]]; !ok {
+			t.Fatalf("expected referenced schema %s to exist", ref[1])
+		}
+	}
+}
+
+// TestAPIDocsServedInDevelopmentOnly verifies the API docs served in development only scenario.
+func TestAPIDocsServedInDevelopmentOnly(t *testing.T) {
+	router := newTestRouter(t)
+	response := doJSONRequest(t, router, http.MethodGet, apiDocsRoutePath, nil, nil)
+	if response.Code != http.StatusOK || !strings.Contains(response.Body.String(), openAPIRoutePath) {
+		t.Fatalf("expected Swagger UI pointing at the document, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	router.(*API).exposeAPIDocs = false
+	response = doJSONRequest(t, router, http.MethodGet, apiDocsRoutePath, nil, nil)
+	if response.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 outside development mode, got %d", response.Code)
+	}
+}
+
+// TestWebhooksDeliverSignedEvents verifies the webhook registration and delivery scenario.
+func TestWebhooksDeliverSignedEvents(t *testing.T) {
+	type receivedRequest struct {
+		header http.Header
+		body   []byte
+	}
+	received := make(chan receivedRequest, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "test-data.json"))
+	if err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	svc, err := service.New(repo, telemetry.NewNoopTelemetry(), impexp.NewNoopImportExport())
+	if err != nil {
+		t.Fatalf(errCreateServiceFmt, err)
+	}
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{BaseBackoff: time.Millisecond, AllowPrivateTargets: true})
+	defer dispatcher.Close()
+	svc.SetEventPublisher(dispatcher)
+	router := NewRouterWithDependencies(auth.NewDevAuthProvider(), svc)
+
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	memberHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+
+	rec := doJSONRequest(t, router, http.MethodPost, routeWebhooks, map[string]any{"url": receiver.URL, "events": []string{"person.created"}}, memberHeaders)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected org_user registration to be forbidden, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doJSONRequest(t, router, http.MethodPost, routeWebhooks, map[string]any{"url": receiver.URL, "events": []string{"person.renamed"}}, adminHeaders)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown event to be rejected, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doJSONRequest(t, router, http.MethodPost, routeWebhooks, map[string]any{"url": "ftp://example.com", "events": []string{"person.created"}}, adminHeaders)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected non-http url to be rejected, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doJSONRequest(t, router, http.MethodPost, routeWebhooks, map[string]any{"url": receiver.URL, "events": []string{"person.created"}}, adminHeaders)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a loopback url to be rejected, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	svc.SetAllowPrivateWebhooks(true)
+
+	rec = doJSONRequest(t, router, http.MethodPost, routeWebhooks, map[string]any{"url": receiver.URL, "events": []string{"person.created"}}, adminHeaders)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register webhook failed: %d body=%s", rec.Code, rec.Body.String())
+	}
+	var created domain.Webhook
+	if err = json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode webhook: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, "whsec_") {
+		t.Fatalf("expected the created webhook to carry its secret, got %+v", created)
+	}
+
+	rec = doJSONRequest(t, router, http.MethodGet, routeWebhooks+"/"+created.ID, nil, adminHeaders)
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), created.Secret) {
+		t.Fatalf("expected the secret to be hidden after creation, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	createProject(t, router, orgID, "Unwatched")
+	personID := createPerson(t, router, orgID, "Watched", 100)
+
+	var request receivedRequest
+	select {
+	case request = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the webhook delivery")
+	}
+	signature := "sha256=" + webhooks.Sign(created.Secret, request.header.Get(webhooks.TimestampHeader), request.body)
+	if request.header.Get(webhooks.SignatureHeader) != signature {
+		t.Fatalf("expected signature %q, got %q", signature, request.header.Get(webhooks.SignatureHeader))
+	}
+	var event domain.Event
+	if err = json.Unmarshal(request.body, &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if event.Name != "person.created" || event.OrganisationID != orgID || event.Data["person_id"] != personID || event.RequestID == "" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec = doJSONRequest(t, router, http.MethodGet, routeWebhooks+"/"+created.ID+"/deliveries", nil, adminHeaders)
+		var deliveries []domain.WebhookDelivery
+		if err = json.Unmarshal(rec.Body.Bytes(), &deliveries); err != nil {
+			t.Fatalf("decode deliveries: %v body=%s", err, rec.Body.String())
+		}
+		if len(deliveries) == 1 && deliveries[0].Succeeded && deliveries[0].EventID == event.ID {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected one successful delivery, got %+v", deliveries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec = doJSONRequest(t, router, http.MethodDelete, routeWebhooks+"/"+created.ID, nil, adminHeaders)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("delete webhook failed: %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doJSONRequest(t, router, http.MethodGet, routeWebhooks+"/"+created.ID+"/deliveries", nil, adminHeaders)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected deleted webhook deliveries to be gone, got %d", rec.Code)
+	}
+}
+
+// TestAPIKeysAuthenticateMachineClients verifies the API key issue, use, and revoke scenario.
+func TestAPIKeysAuthenticateMachineClients(t *testing.T) {
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "test-data.json"))
+	if err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	svc, err := service.New(repo, telemetry.NewNoopTelemetry(), impexp.NewNoopImportExport())
+	if err != nil {
+		t.Fatalf(errCreateServiceFmt, err)
+	}
+	router := NewRouterWithDependencies(auth.NewAPIKeyAuthProvider(svc, auth.NewDevAuthProvider()), svc)
+
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	createPerson(t, router, orgID, "Visible", 100)
+
+	rec := doJSONRequest(t, router, http.MethodPost, routeAPIKeys, map[string]any{"name": "ci", "roles": []string{"org_owner"}}, adminHeaders)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown role to be rejected, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doJSONRequest(t, router, http.MethodPost, routeAPIKeys, map[string]any{"name": "ci", "roles": []string{"org_user"}}, adminHeaders)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("issue api key failed: %d body=%s", rec.Code, rec.Body.String())
+	}
+	var created domain.APIKey
+	if err = json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode api key: %v", err)
+	}
+	if !strings.HasPrefix(created.Key, created.Prefix) || created.Hash != "" {
+		t.Fatalf("expected the plain key once and no hash, got %+v", created)
+	}
+
+	rec = doJSONRequest(t, router, http.MethodGet, routeAPIKeys, nil, adminHeaders)
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), created.Key) || strings.Contains(rec.Body.String(), `"hash"`) {
+		t.Fatalf("expected listed keys without key or hash, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	keyHeaders := map[string]string{"X-API-Key": created.Key, "X-Role": "org_admin", "X-Org-ID": "org_other"}
+	rec = doJSONRequest(t, router, http.MethodGet, routePersons, nil, keyHeaders)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Visible") {
+		t.Fatalf("expected the key to read its organisation, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doJSONRequest(t, router, http.MethodPost, routePersons, map[string]any{"name": "Denied", "employment_pct": 100}, keyHeaders)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected the key to keep its org_user role, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	rec = doJSONRequest(t, router, http.MethodPost, routeAPIKeys+"/"+created.ID+"/revoke", nil, keyHeaders)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected org_user key to be unable to revoke, got %d", rec.Code)
+	}
+	rec = doJSONRequest(t, router, http.MethodPost, routeAPIKeys+"/"+created.ID+"/revoke", nil, adminHeaders)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"revoked_at"`) {
+		t.Fatalf("revoke api key failed: %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = doJSONRequest(t, router, http.MethodGet, routePersons, nil, keyHeaders)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected revoked key to be rejected, got %d", rec.Code)
+	}
+	rec = doJSONRequest(t, router, http.MethodGet, routePersons, nil, map[string]string{"X-API-Key": "plato_unknown"})
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unknown key to be rejected, got %d", rec.Code)
+	}
+}
+
+// TestRouterNewRouterSeedsDemoTenantOnce verifies the router new router seeds demo tenant once scenario.
+func TestRouterNewRouterSeedsDemoTenantOnce(t *testing.T) {
+	t.Setenv("DEV_MODE", envBoolTrue)
+	t.Setenv(envSeedDemo, envBoolTrue)
+	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "demo-data.json"))
+	adminHeaders := map[string]string{"X-Role": "org_admin"}
+
+	listOrganisations := func() []domain.Organisation {
+		t.Helper()
+		router, err := NewRouterFromEnv()
+		if err != nil {
+			t.Fatalf("create router: %v", err)
+		}
+		api, ok := router.(*API)
+		if !ok {
+			t.Fatalf("expected *API router, got %T", router)
+		}
+		defer func() {
+			if closeErr := api.Close(); closeErr != nil {
+				t.Fatalf("close router: %v", closeErr)
+			}
+		}()
+		var organisations []domain.Organisation
+		decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, testOrganisationsPath, nil, adminHeaders), &organisations)
+
+		if len(organisations) == 1 {
+			var persons []domain.Person
+			headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": organisations[0].ID}
+			decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons, nil, headers), &persons)
+			if len(persons) == 0 {
+				t.Fatal("expected demo persons to be seeded")
+			}
+		}
+		return organisations
+	}
+
+	first := listOrganisations()
+	if len(first) != 1 || first[0].Name != service.DemoOrganisationName {
+		t.Fatalf("expected one demo organisation after first startup, got %+v", first)
+	}
+	second := listOrganisations()
+	if len(second) != 1 || second[0].ID != first[0].ID {
+		t.Fatalf("expected second startup to keep the single demo organisation, got %+v", second)
+	}
+}
+
+// TestRouterNewRouterProductionModeRequiresJWTSecret verifies the router new router production mode requires JWT secret scenario.
+func TestRouterNewRouterProductionModeRequiresJWTSecret(t *testing.T) {
+	t.Setenv("PRODUCTION_MODE", envBoolTrue)
+	t.Setenv("PLATO_CORS_ALLOWED_ORIGINS", testAppOrigin)
+	t.Setenv("PLATO_AUTH_JWT_HS256_SIGNING_KEY", "")
+	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "prod-data.json"))
+
+	if _, err := NewRouterFromEnv(); err == nil {
+		t.Fatal("expected router creation to fail without production JWT secret")
+	}
+}
+
+// TestRouterAuthProviderFromConfig verifies the router auth provider from config scenario.
+func TestRouterAuthProviderFromConfig(t *testing.T) {
+	t.Setenv("PLATO_AUTH_JWT_HS256_SIGNING_KEY", "test-secret")
+	cases := []struct {
+		name     string
+		config   RuntimeConfig
+		expected any
+	}{
+		{name: "development default", config: RuntimeConfig{Mode: RuntimeModeDevelopment}, expected: &auth.DevAuthProvider{}},
+		{name: "production default", config: RuntimeConfig{Mode: RuntimeModeProduction}, expected: &auth.JWTAuthProvider{}},
+		{name: "jwt in development", config: RuntimeConfig{Mode: RuntimeModeDevelopment, AuthProvider: AuthProviderJWT}, expected: &auth.JWTAuthProvider{}},
+		{
+			name:     "oidc",
+			config:   RuntimeConfig{Mode: RuntimeModeProduction, AuthProvider: AuthProviderOIDC, OIDC: auth.OIDCConfig{Issuer: "https://keycloak.local/realms/plato"}},
+			expected: &auth.OIDCAuthProvider{},
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			provider, err := authProviderFromConfig(testCase.config)
+			if err != nil {
+				t.Fatalf("auth provider: %v", err)
+			}
+			if reflect.TypeOf(provider) != reflect.TypeOf(testCase.expected) {
+				t.Fatalf("expected %T, got %T", testCase.expected, provider)
+			}
+		})
+	}
+
+	if _, err := authProviderFromConfig(RuntimeConfig{Mode: RuntimeModeProduction, AuthProvider: AuthProviderDev}); err == nil {
+		t.Fatal("expected dev auth to be rejected in production mode")
+	}
+	if _, err := authProviderFromConfig(RuntimeConfig{Mode: RuntimeModeProduction, AuthProvider: AuthProviderOIDC}); err == nil {
+		t.Fatal("expected oidc without an issuer to be rejected")
+	}
+}
+
+// TestRouterNewRouterValidatesPersistenceBackend verifies the router new router validates persistence backend scenario.
+func TestRouterNewRouterValidatesPersistenceBackend(t *testing.T) {
+	t.Setenv("DEV_MODE", envBoolTrue)
+	t.Setenv(dataFileEnvVar, "")
+	t.Setenv(dataDirEnvVar, "")
+	t.Setenv(databaseURLEnvVar, "")
+	t.Setenv(envPersistDebounce, "")
+
+	cases := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "unknown backend", env: map[string]string{persistenceEnvVar: "mongo"}, wantErr: persistenceEnvVar},
+		{name: "missing database url", env: map[string]string{persistenceEnvVar: persistencePostgres}, wantErr: databaseURLEnvVar},
+		{
+			name:    "data file with postgres",
+			env:     map[string]string{persistenceEnvVar: persistencePostgres, databaseURLEnvVar: "postgres://plato@localhost/plato", dataFileEnvVar: "data.json"},
+			wantErr: dataFileEnvVar,
+		},
+		{
+			name:    "debounce with postgres",
+			env:     map[string]string{persistenceEnvVar: persistencePostgres, databaseURLEnvVar: "postgres://plato@localhost/plato", envPersistDebounce: "1s"},
+			wantErr: envPersistDebounce,
+		},
+		{
+			name:    "data dir with sqlite",
+			env:     map[string]string{persistenceEnvVar: persistenceSQLite, dataDirEnvVar: "data"},
+			wantErr: dataDirEnvVar,
+		},
+		{
+			name:    "debounce with sqlite",
+			env:     map[string]string{persistenceEnvVar: persistenceSQLite, envPersistDebounce: "1s"},
+			wantErr: envPersistDebounce,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+			_, err := NewRouterFromEnv()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected an error naming %s, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestRouterPersonUpdateUsesETags verifies the router person update uses ETags scenario.
+func TestRouterPersonUpdateUsesETags(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	personID := createPerson(t, router, orgID, "Tagged", 100)
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	personPath := routePersons + "/" + personID
+
+	response := doJSONRequest(t, router, http.MethodGet, personPath, nil, headers)
+	if response.Code != http.StatusOK || response.Header().Get(headerETag) != `"1"` {
+		t.Fatalf("expected GET to return ETag \"1\", got %d %q", response.Code, response.Header().Get(headerETag))
+	}
+
+	update := map[string]any{"name": "Renamed", "employment_pct": 80}
+	response = doJSONRequest(t, router, http.MethodPut, personPath, update, map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID, headerIfMatch: `"1"`})
+	if response.Code != http.StatusOK || response.Header().Get(headerETag) != `"2"` {
+		t.Fatalf("expected PUT to return ETag \"2\", got %d %q body=%s", response.Code, response.Header().Get(headerETag), response.Body.String())
+	}
+
+	update["name"] = "Lost Update"
+	response = doJSONRequest(t, router, http.MethodPut, personPath, update, map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID, headerIfMatch: `"1"`})
+	if response.Code != http.StatusConflict {
+		t.Fatalf("expected a stale If-Match to return 409, got %d body=%s", response.Code, response.Body.String())
+	}
+	update["version"] = 1
+	response = doJSONRequest(t, router, http.MethodPut, personPath, update, headers)
+	if response.Code != http.StatusConflict {
+		t.Fatalf("expected a stale body version to return 409, got %d body=%s", response.Code, response.Body.String())
+	}
+	response = doJSONRequest(t, router, http.MethodPut, personPath, update, map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID, headerIfMatch: "v2"})
+	if response.Code != http.StatusBadRequest {
+		t.Fatalf("expected a malformed If-Match to return 400, got %d body=%s", response.Code, response.Body.String())
+	}
+	response = doJSONRequest(t, router, http.MethodPut, personPath, update, map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID, headerIfMatch: "*"})
+	if response.Code != http.StatusConflict {
+		t.Fatalf("expected * to fall back to the stale body version, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	var person domain.Person
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, personPath, nil, headers), &person)
+	if person.Name != "Renamed" || person.Version != 2 {
+		t.Fatalf("expected only the first update to be stored, got %+v", person)
+	}
+}
+
+// TestRouterTenantExportImport verifies the router tenant export import scenario.
+func TestRouterTenantExportImport(t *testing.T) {
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "transfer-data.json"))
+	if err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	svc, err := service.New(repo, telemetry.NewNoopTelemetry(), impexp.NewCSVImportExport())
+	if err != nil {
+		t.Fatalf(errCreateServiceFmt, err)
+	}
+	router := NewRouterWithDependencies(auth.NewDevAuthProvider(), svc)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	createPerson(t, router, orgID, "Exported", 100)
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+
+	response := doRawRequest(t, router, http.MethodGet, "/api/export", nil, headers)
+	if response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET export to return 405, got %d", response.Code)
+	}
+	response = doRawRequest(t, router, http.MethodPost, "/api/export?format=csv", nil, headers)
+	if response.Code != http.StatusOK || response.Header().Get(headerContentType) != contentTypeZip {
+		t.Fatalf("expected a zip export, got %d %q", response.Code, response.Header().Get(headerContentType))
+	}
+	response = doRawRequest(t, router, http.MethodPost, "/api/export", nil, headers)
+	if response.Code != http.StatusOK || response.Header().Get(headerContentType) != contentTypeJSON {
+		t.Fatalf("expected a JSON export, got %d %q", response.Code, response.Header().Get(headerContentType))
+	}
+	snapshot := response.Body.Bytes()
+
+	var exported map[string]any
+	if err = json.Unmarshal(snapshot, &exported); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	importHeaders := map[string]string{"X-Role": "org_admin"}
+	exported["format_version"] = 99
+	response = doJSONRequest(t, router, http.MethodPost, "/api/import", exported, importHeaders)
+	if response.Code != http.StatusBadRequest || !strings.Contains(response.Body.String(), "format_version") {
+		t.Fatalf("expected an unsupported format version to be rejected, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	exported["format_version"] = domain.TenantSnapshotVersion
+	exported["organisation"].(map[string]any)["name"] = "Imported Copy"
+	response = doJSONRequest(t, router, http.MethodPost, "/api/import?dry_run=true", exported, importHeaders)
+	var result domain.TenantImportResult
+	decodeJSONResponse(t, response, &result)
+	if !result.DryRun || !result.Valid || result.Imported || result.Counts["persons"] != 1 {
+		t.Fatalf("unexpected dry run result: %+v", result)
+	}
+
+	response = doJSONRequest(t, router, http.MethodPost, "/api/import", exported, importHeaders)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("expected import to return 201, got %d body=%s", response.Code, response.Body.String())
+	}
+	if err = json.Unmarshal(response.Body.Bytes(), &result); err != nil || result.OrganisationID == "" {
+		t.Fatalf("expected the new organisation ID, got %+v (%v)", result, err)
+	}
+	response = doJSONRequest(t, router, http.MethodGet, routePersons, nil, map[string]string{"X-Role": "org_admin", "X-Org-ID": result.OrganisationID})
+	var persons []domain.Person
+	decodeJSONResponse(t, response, &persons)
+	if len(persons) != 1 || persons[0].Name != "Exported" {
+		t.Fatalf("expected the imported person, got %+v", persons)
+	}
+}
+
+// TestRouterNewRouterUsesSQLitePersistence verifies the router new router uses sqlite persistence scenario.
+func TestRouterNewRouterUsesSQLitePersistence(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DEV_MODE", envBoolTrue)
+	t.Setenv(dataFileEnvVar, "")
+	t.Setenv(dataDirEnvVar, "")
+	t.Setenv(envPersistDebounce, "")
+	t.Setenv(persistenceEnvVar, persistenceSQLite)
+	t.Setenv(sqlitePathEnvVar, filepath.Join(dir, "plato.db"))
+	adminHeaders := map[string]string{"X-Role": "org_admin"}
+
+	openRouter := func() (http.Handler, *API) {
+		t.Helper()
+		router, err := NewRouterFromEnv()
+		if err != nil {
+			t.Fatalf("create router: %v", err)
+		}
+		api, ok := router.(*API)
+		if !ok {
+			t.Fatalf("expected *API router, got %T", router)
+		}
+		return router, api
+	}
+
+	router, api := openRouter()
+	orgID := createOrganisation(t, router, adminHeaders)
+	if closeErr := api.Close(); closeErr != nil {
+		t.Fatalf("close router: %v", closeErr)
+	}
+
+	reopened, reopenedAPI := openRouter()
+	defer func() { _ = reopenedAPI.Close() }()
+	response := doJSONRequest(t, reopened, http.MethodGet, testOrganisationsPath+"/"+orgID, nil, adminHeaders)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected the organisation to survive a restart, got %d body=%s", response.Code, response.Body.String())
+	}
+}
+
+// TestRouterNewRouterProductionModeCORSAllowlistAndAuth verifies the router new router production mode CORS allowlist and auth scenario.
+func TestRouterNewRouterProductionModeCORSAllowlistAndAuth(t *testing.T) {
+	t.Setenv("PRODUCTION_MODE", envBoolTrue)
+	t.Setenv("PLATO_AUTH_JWT_HS256_SIGNING_KEY", "test-secret")
+	t.Setenv("PLATO_CORS_ALLOWED_ORIGINS", testAppOrigin)
+	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "prod-router-data.json"))
+
+	router, err := NewRouterFromEnv()
+	if err != nil {
+		t.Fatalf("create production router: %v", err)
+	}
+
+	allowlistedOriginResponse := doRawRequest(t, router, http.MethodGet, testOrganisationsPath, nil, map[string]string{
+		"Origin": testAppOrigin,
+	})
+	if allowlistedOriginResponse.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized without bearer token, got %d", allowlistedOriginResponse.Code)
+	}
+	if got := allowlistedOriginResponse.Header().Get("Access-Control-Allow-Origin"); got != testAppOrigin {
+		t.Fatalf("expected allowlisted origin header, got %q", got)
+	}
+
+	blockedOriginResponse := doRawRequest(t, router, http.MethodGet, testOrganisationsPath, nil, map[string]string{
+		"Origin": "https://blocked.example.com",
+	})
+	if got := blockedOriginResponse.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected blocked origin to be omitted from CORS header, got %q", got)
+	}
+}
+
+// TestRouteTableEndpoint verifies the route table endpoint scenario.
+func TestRouteTableEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	headers := map[string]string{"X-Role": "org_user"}
+
+	response := doJSONRequest(t, router, http.MethodGet, "/api/_routes", nil, headers)
+	var payload struct {
+		Routes []routeDescriptor `json:"routes"`
+	}
+	decodeJSONResponse(t, response, &payload)
+	found := false
+	for _, route := range payload.Routes {
+		if route.Path != routePersons {
+			continue
+		}
+		found = true
+		if !reflect.DeepEqual(route.Methods, []string{http.MethodGet, http.MethodPost}) {
+			t.Fatalf("expected %s methods GET and POST, got %v", routePersons, route.Methods)
+		}
+	}
+	if !found {
+		t.Fatalf("expected route table to include %s, got %+v", routePersons, payload.Routes)
+	}
+
+	postResponse := doJSONRequest(t, router, http.MethodPost, "/api/_routes", nil, headers)
+	if postResponse.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for POST route table, got %d", postResponse.Code)
+	}
+
+	t.Setenv("PRODUCTION_MODE", envBoolTrue)
+	t.Setenv("PLATO_AUTH_JWT_HS256_SIGNING_KEY", "test-secret")
+	t.Setenv("PLATO_CORS_ALLOWED_ORIGINS", testAppOrigin)
+	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "prod-routes-data.json"))
+	productionRouter, err := NewRouterFromEnv()
+	if err != nil {
+		t.Fatalf("create production router: %v", err)
+	}
+	api, ok := productionRouter.(*API)
+	if !ok {
+		t.Fatal("expected production router to be *API")
+	}
+	defer func() {
+		if closeErr := api.Close(); closeErr != nil {
+			t.Fatalf("close production router: %v", closeErr)
+		}
+	}()
+	api.authProvider = auth.NewDevAuthProvider()
+
+	productionResponse := doJSONRequest(t, api, http.MethodGet, "/api/_routes", nil, headers)
+	if productionResponse.Code != http.StatusNotFound {
+		t.Fatalf("expected route table to 404 in production mode, got %d", productionResponse.Code)
+	}
+}
+
+// TestEncodedPathSegments verifies the encoded path segments scenario.
+func TestEncodedPathSegments(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	headers := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Encoded", 100)
+
+	encodedID := strings.Replace(personID, "_", "%5F", 1)
+	response := doRawRequest(t, router, http.MethodGet, routePersons+"/"+encodedID, nil, headers)
+	var person domain.Person
+	decodeJSONResponse(t, response, &person)
+	if person.ID != personID {
+		t.Fatalf("expected encoded id %s to resolve person %s, got %+v", encodedID, personID, person)
+	}
+
+	slashResponse := doRawRequest(t, router, http.MethodGet, routePersons+"/"+personID+"%2Funavailability", nil, headers)
+	if slashResponse.Code != http.StatusBadRequest {
+		t.Fatalf("expected encoded slash to be rejected with 400, got %d body=%s", slashResponse.Code, slashResponse.Body.String())
+	}
+
+	segments, err := decodePathSegments([]string{"api", "persons", "a%20b"})
+	if err != nil || segments[2] != "a b" {
+		t.Fatalf("expected decoded segment, got %v err=%v", segments, err)
+	}
+	if _, err = decodePathSegments([]string{"api", "persons", "a%2fb"}); !errors.Is(err, errEncodedSlashInPath) {
+		t.Fatalf("expected lowercase encoded slash to be rejected, got %v", err)
+	}
+	if _, err = decodePathSegments([]string{"api", "%zz"}); err == nil {
+		t.Fatal("expected invalid escape to be rejected")
+	}
+}
+
+// TestReportWhatIfEndpoint verifies the report what-if endpoint scenario.
+func TestReportWhatIfEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	personID := createPerson(t, router, orgID, "What If", 100)
+	projectID := createProject(t, router, orgID, "What If Project")
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	report := map[string]any{"scope": "person", "ids": []string{personID}, "from_date": "2026-01-01", "to_date": "2026-01-01", "granularity": "day"}
+
+	var baseline struct {
+		Buckets []domain.ReportBucket `json:"buckets"`
+	}
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, routeAvailabilityLoad, report, userHeaders), &baseline)
+
+	whatIfPayload := map[string]any{"proposed_allocations": []map[string]any{personAllocationPayload(personID, projectID, 50)}}
+	for key, value := range report {
+		whatIfPayload[key] = value
+	}
+	var whatIf struct {
+		Buckets []domain.ReportBucket `json:"buckets"`
+	}
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, "/api/reports/what-if", whatIfPayload, userHeaders), &whatIf)
+
+	if len(baseline.Buckets) != 1 || len(whatIf.Buckets) != 1 {
+		t.Fatalf("expected one bucket per report, got baseline=%+v what-if=%+v", baseline.Buckets, whatIf.Buckets)
+	}
+	if baseline.Buckets[0].LoadHours != 0 || whatIf.Buckets[0].LoadHours != 4 {
+		t.Fatalf("expected what-if load 4 over baseline 0, got baseline=%v what-if=%v", baseline.Buckets[0].LoadHours, whatIf.Buckets[0].LoadHours)
+	}
+
+	allocations := doJSONRequest(t, router, http.MethodGet, routeAllocations, nil, userHeaders)
+	var listed []domain.Allocation
+	decodeJSONResponse(t, allocations, &listed)
+	if len(listed) != 0 {
+		t.Fatalf("expected what-if to persist no allocations, got %+v", listed)
+	}
+
+	getResponse := doJSONRequest(t, router, http.MethodGet, "/api/reports/what-if", nil, userHeaders)
+	if getResponse.Code != http.StatusMethodNotAllowed || getResponse.Header().Get(headerAllow) != "POST, OPTIONS" {
+		t.Fatalf("expected 405 with POST allowed, got %d allow=%q", getResponse.Code, getResponse.Header().Get(headerAllow))
+	}
+}
+
+// TestReportConcurrencyLimit verifies the report concurrency limit scenario.
+func TestReportConcurrencyLimit(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	personID := createPerson(t, router, orgID, "Busy Reader", 100)
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	report := map[string]any{"scope": "person", "ids": []string{personID}, "from_date": "2026-01-01", "to_date": "2026-01-31", "granularity": "month"}
+	payload, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshal report: %v", err)
+	}
+
+	api, ok := router.(*API)
+	if !ok {
+		t.Fatal("expected router to be *API")
+	}
+	api.reports = newReportLimiter(1)
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	var enteredOnce sync.Once
+	api.reports.acquired = func() {
+		enteredOnce.Do(func() { close(entered) })
+		<-unblock
+	}
+
+	held := make(chan int, 1)
+	go func() {
+		request := httptest.NewRequestWithContext(context.Background(), http.MethodPost, routeAvailabilityLoad, bytes.NewReader(payload))
+		for key, value := range userHeaders {
+			request.Header.Set(key, value)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		held <- recorder.Code
+	}()
+	<-entered
+
+	busy := doJSONRequest(t, router, http.MethodPost, routeAvailabilityLoad, report, userHeaders)
+	if busy.Code != http.StatusServiceUnavailable || busy.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 503 with Retry-After while the report slot is held, got %d headers=%v", busy.Code, busy.Header())
+	}
+	if listed := doJSONRequest(t, router, http.MethodGet, routePersons, nil, userHeaders); listed.Code != http.StatusOK {
+		t.Fatalf("expected CRUD to stay available while reports are busy, got %d", listed.Code)
+	}
+
+	close(unblock)
+	if code := <-held; code != http.StatusOK {
+		t.Fatalf("expected the held report to finish with 200, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodPost, routeAvailabilityLoad, report, userHeaders).Code; code != http.StatusOK {
+		t.Fatalf("expected a report to run once the slot is free, got %d", code)
+	}
+}
+
+// TestDisplayPrecisionRounding verifies the display precision rounding scenario.
+func TestDisplayPrecisionRounding(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	personID := createPerson(t, router, orgID, "Rounded", 100)
+	projectID := createProject(t, router, orgID, "Rounded Project")
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+
+	api, ok := router.(*API)
+	if !ok {
+		t.Fatal("expected router to be *API")
+	}
+	api.display = displayRounding{
+		fte:     DisplayPrecision{Enabled: true, Decimals: 1},
+		percent: DisplayPrecision{Enabled: true, Decimals: 0},
+	}
+
+	created := decodeCreatedAllocationForPayload(t, router, personAllocationPayload(personID, projectID, 33.333), adminHeaders)
+	if created.Percent != 33 {
+		t.Fatalf("expected created percent to be shown as 33, got %v", created.Percent)
+	}
+	var listed []domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeAllocations, nil, userHeaders), &listed)
+	if len(listed) != 1 || listed[0].Percent != 33 {
+		t.Fatalf("expected listed percent to be shown as 33, got %+v", listed)
+	}
+
+	report := map[string]any{
+		"scope": "person", "ids": []string{personID}, "from_date": "2026-01-01", "to_date": "2026-01-01",
+		"granularity": "day", "unit": "fte", "include_contributors": true,
+	}
+	var rounded struct {
+		Buckets []domain.ReportBucket `json:"buckets"`
+	}
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, routeAvailabilityLoad, report, userHeaders), &rounded)
+	if len(rounded.Buckets) != 1 {
+		t.Fatalf("expected one bucket, got %+v", rounded.Buckets)
+	}
+	bucket := rounded.Buckets[0]
+	if bucket.LoadHours != 0.3 || bucket.FreeHours != 0.7 || bucket.AvailabilityHours != 1 {
+		t.Fatalf("expected FTE values rounded to one decimal, got %+v", bucket)
+	}
+	if bucket.UtilizationPct != 33 || len(bucket.Contributors) != 1 || bucket.Contributors[0].Percent != 33 {
+		t.Fatalf("expected percentages rounded to whole numbers, got %+v", bucket)
+	}
+
+	api.display = displayRounding{}
+	stored := doJSONRequest(t, router, http.MethodGet, routeAllocations+"/"+created.ID, nil, userHeaders)
+	var exact domain.Allocation
+	decodeJSONResponse(t, stored, &exact)
+	if exact.Percent != 33.333 {
+		t.Fatalf("expected stored percent to stay exact, got %v", exact.Percent)
+	}
+	var unrounded struct {
+		Buckets []domain.ReportBucket `json:"buckets"`
+	}
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, routeAvailabilityLoad, report, userHeaders), &unrounded)
+	if len(unrounded.Buckets) != 1 || unrounded.Buckets[0].LoadHours != 0.33 {
+		t.Fatalf("expected unrounded FTE load of 0.33, got %+v", unrounded.Buckets)
+	}
+}
+
+// TestReportDiffEndpoint verifies the report diff endpoint scenario.
+func TestReportDiffEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	personID := createPerson(t, router, orgID, "Diff", 100)
+	projectID := createProject(t, router, orgID, "Diff Project")
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	report := map[string]any{"scope": "person", "ids": []string{personID}, "from_date": "2026-01-01", "to_date": "2026-01-01", "granularity": "day"}
+	comparison := map[string]any{"proposed_allocations": []map[string]any{personAllocationPayload(personID, projectID, 50)}}
+	for key, value := range report {
+		comparison[key] = value
+	}
+
+	response := doJSONRequest(t, router, http.MethodPost, "/api/reports/diff", map[string]any{"baseline": report, "comparison": comparison}, userHeaders)
+	var diff struct {
+		Buckets []domain.ReportDiffBucket `json:"buckets"`
+	}
+	decodeJSONResponse(t, response, &diff)
+	if len(diff.Buckets) != 1 || diff.Buckets[0].PeriodStart != "2026-01-01" || diff.Buckets[0].LoadHoursDelta != 4 {
+		t.Fatalf("expected a 4 hour load delta on 2026-01-01, got %+v", diff.Buckets)
+	}
+
+	getResponse := doJSONRequest(t, router, http.MethodGet, "/api/reports/diff", nil, userHeaders)
+	if getResponse.Code != http.StatusMethodNotAllowed || getResponse.Header().Get(headerAllow) != "POST, OPTIONS" {
+		t.Fatalf("expected 405 with POST allowed, got %d allow=%q", getResponse.Code, getResponse.Header().Get(headerAllow))
+	}
+}
+
+// TestSecurityHeadersByRuntimeMode verifies the security headers by runtime mode scenario.
+func TestSecurityHeadersByRuntimeMode(t *testing.T) {
+	securityHeaders := []string{"X-Content-Type-Options", "X-Frame-Options", "Referrer-Policy", "Strict-Transport-Security"}
+
+	devResponse := doRawRequest(t, newTestRouter(t), http.MethodGet, healthRoutePath, nil, nil)
+	for _, header := range securityHeaders {
+		if got := devResponse.Header().Get(header); got != "" {
+			t.Fatalf("expected %s to be absent in development mode, got %q", header, got)
+		}
+	}
+
+	t.Setenv("PRODUCTION_MODE", envBoolTrue)
+	t.Setenv("PLATO_AUTH_JWT_HS256_SIGNING_KEY", "test-secret")
+	t.Setenv("PLATO_CORS_ALLOWED_ORIGINS", testAppOrigin)
+	t.Setenv("PLATO_HSTS_MAX_AGE_SECONDS", "31536000")
+	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "prod-headers-data.json"))
+	router, err := NewRouterFromEnv()
+	if err != nil {
+		t.Fatalf("create production router: %v", err)
+	}
+
+	expected := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "no-referrer",
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+	}
+	for _, path := range []string{healthRoutePath, testOrganisationsPath} {
+		response := doRawRequest(t, router, http.MethodGet, path, nil, nil)
+		for header, want := range expected {
+			if got := response.Header().Get(header); got != want {
+				t.Fatalf("expected %s %q on %s (status %d), got %q", header, want, path, response.Code, got)
+			}
+		}
+	}
+}
+
+// TestAllocationsActiveOnDate verifies the allocations active on date scenario.
+func TestAllocationsActiveOnDate(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Standup Person", 100)
+	projectID := createProject(t, router, orgID, "Standup Project")
+
+	createAllocationInRange := func(startDate, endDate string) string {
+		t.Helper()
+		payload := personAllocationPayload(personID, projectID, 20)
+		payload["start_date"] = startDate
+		payload["end_date"] = endDate
+		response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders)
+		if response.Code != http.StatusCreated {
+			t.Fatalf("create allocation %s to %s: %d body=%s", startDate, endDate, response.Code, response.Body.String())
+		}
+		var allocation domain.Allocation
+		if err := json.Unmarshal(response.Body.Bytes(), &allocation); err != nil {
+			t.Fatalf("decode allocation: %v", err)
+		}
+		return allocation.ID
+	}
+	activeID := createAllocationInRange("2026-03-01", "2026-03-31")
+	endingID := createAllocationInRange("2026-01-01", "2026-03-15")
+	createAllocationInRange("2026-03-16", "2026-04-30")
+	createAllocationInRange("2026-01-01", "2026-02-28")
+
+	response := doJSONRequest(t, router, http.MethodGet, routeAllocations+"?active_on=2026-03-15", nil, userHeaders)
+	var active []domain.ActiveAllocation
+	decodeJSONResponse(t, response, &active)
+	gotIDs := map[string]bool{}
+	for _, allocation := range active {
+		gotIDs[allocation.ID] = true
+		if allocation.TargetName != "Standup Person" || allocation.ProjectName != "Standup Project" {
+			t.Fatalf("expected resolved names, got target=%q project=%q", allocation.TargetName, allocation.ProjectName)
+		}
+	}
+	if len(active) != 2 || !gotIDs[activeID] || !gotIDs[endingID] {
+		t.Fatalf("expected only allocations %s and %s to be active, got %+v", activeID, endingID, active)
+	}
+
+	invalid := doJSONRequest(t, router, http.MethodGet, routeAllocations+"?active_on=2026-13-01", nil, userHeaders)
+	if invalid.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid active_on date to return 400, got %d", invalid.Code)
+	}
+	empty := doJSONRequest(t, router, http.MethodGet, routeAllocations+"?active_on=", nil, userHeaders)
+	if empty.Code != http.StatusBadRequest {
+		t.Fatalf("expected empty active_on date to return 400, got %d", empty.Code)
+	}
+}
+
+// TestListPagination verifies paging, sorting, and filtering on list endpoints.
+func TestListPagination(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	createPerson(t, router, orgID, "Charlie", 100)
+	aliceID := createPerson(t, router, orgID, "Alice", 80)
+	bobID := createPerson(t, router, orgID, "Bob", 80)
+
+	var page domain.ListPage[domain.Person]
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"?limit=2&sort=name", nil, userHeaders), &page)
+	if page.Total != 3 || len(page.Items) != 2 || page.Items[0].ID != aliceID || page.Items[1].ID != bobID || page.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	var lastPage domain.ListPage[domain.Person]
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"?limit=2&sort=name&cursor="+page.NextCursor, nil, userHeaders), &lastPage)
+	if len(lastPage.Items) != 1 || lastPage.Items[0].Name != "Charlie" || lastPage.Offset != 2 || lastPage.NextCursor != "" {
+		t.Fatalf("unexpected second page: %+v", lastPage)
+	}
+	var filtered domain.ListPage[domain.Person]
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"?employment_pct=80&sort=-name", nil, userHeaders), &filtered)
+	if filtered.Total != 2 || filtered.Items[0].ID != bobID || filtered.Limit != domain.DefaultListLimit {
+		t.Fatalf("unexpected filtered page: %+v", filtered)
+	}
+
+	var persons []domain.Person
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons, nil, userHeaders), &persons)
+	if len(persons) != 3 {
+		t.Fatalf("expected plain list without paging parameters, got %+v", persons)
+	}
+
+	projectID := createProject(t, router, orgID, "Paged Project")
+	otherProjectID := createProject(t, router, orgID, "Other Project")
+	for _, payload := range []map[string]any{
+		personAllocationPayload(aliceID, projectID, 20),
+		personAllocationPayload(bobID, projectID, 30),
+		personAllocationPayload(bobID, otherProjectID, 40),
+	} {
+		if response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders); response.Code != http.StatusCreated {
+			t.Fatalf("create allocation: %d body=%s", response.Code, response.Body.String())
+		}
+	}
+	var allocations domain.ListPage[domain.Allocation]
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeAllocations+"?project_id="+projectID+"&target_type=person&sort=-percent", nil, userHeaders), &allocations)
+	if allocations.Total != 2 || allocations.Items[0].Percent != 30 || allocations.Items[1].Percent != 20 {
+		t.Fatalf("unexpected allocation page: %+v", allocations)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodDelete, routeProjects+"/"+otherProjectID, nil, adminHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("archive project: %d body=%s", response.Code, response.Body.String())
+	}
+	var projects domain.ListPage[domain.Project]
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeProjects+"?limit=10", nil, userHeaders), &projects)
+	if projects.Total != 1 || projects.Items[0].ID != projectID {
+		t.Fatalf("expected archived project to stay hidden, got %+v", projects)
+	}
+	var archived []domain.Project
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeProjects+"?archived=true", nil, userHeaders), &archived)
+	if len(archived) != 1 || archived[0].ID != otherProjectID {
+		t.Fatalf("expected a filter without paging to list archived projects as a plain array, got %+v", archived)
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=501", "?offset=-1", "?offset=1&cursor=abc", "?cursor=%25%25", "?sort=shoe_size", "?shoe_size=42"} {
+		if response := doJSONRequest(t, router, http.MethodGet, routeGroups+query, nil, userHeaders); response.Code != http.StatusBadRequest {
+			t.Fatalf("expected %s to be rejected, got %d body=%s", query, response.Code, response.Body.String())
+		}
+	}
+}
+
+// TestReportTolerateMissingIDs verifies the report tolerate missing IDs scenario.
+func TestReportTolerateMissingIDs(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Dashboard Person", 100)
+	projectID := createProject(t, router, orgID, "Dashboard Project")
+	if response := doJSONRequest(t, router, http.MethodPost, routeAllocations, personAllocationPayload(personID, projectID, 50), adminHeaders); response.Code != http.StatusCreated {
+		t.Fatalf("create allocation: %d body=%s", response.Code, response.Body.String())
+	}
+
+	report := func(ids []string, tolerate bool) *httptest.ResponseRecorder {
+		return doJSONRequest(t, router, http.MethodPost, routeAvailabilityLoad, map[string]any{
+			"scope": "person", "ids": ids, "from_date": "2026-01-01", "to_date": "2026-01-01", "granularity": "day", "tolerate_missing": tolerate,
+		}, userHeaders)
+	}
+
+	if strict := report([]string{personID, testMissingResourceID}, false); strict.Code != http.StatusNotFound {
+		t.Fatalf("expected strict report with a missing id to return 404, got %d", strict.Code)
+	}
+
+	var partial struct {
+		Buckets    []domain.ReportBucket `json:"buckets"`
+		SkippedIDs []string              `json:"skipped_ids"`
+	}
+	decodeJSONResponse(t, report([]string{personID, testMissingResourceID}, true), &partial)
+	if len(partial.Buckets) != 1 || partial.Buckets[0].LoadHours != 4 || partial.Buckets[0].AvailabilityHours != 8 {
+		t.Fatalf("expected report to cover the valid person only, got %+v", partial.Buckets)
+	}
+	if !reflect.DeepEqual(partial.SkippedIDs, []string{testMissingResourceID}) {
+		t.Fatalf("expected skipped ids [%s], got %v", testMissingResourceID, partial.SkippedIDs)
+	}
+
+	var allMissing struct {
+		Buckets    []domain.ReportBucket `json:"buckets"`
+		SkippedIDs []string              `json:"skipped_ids"`
+	}
+	decodeJSONResponse(t, report([]string{testMissingResourceID}, true), &allMissing)
+	if len(allMissing.Buckets) != 0 || !reflect.DeepEqual(allMissing.SkippedIDs, []string{testMissingResourceID}) {
+		t.Fatalf("expected no buckets when every id is skipped, got %+v", allMissing)
+	}
+}
+
+// TestProjectReconcileAllocations verifies the project reconcile allocations scenario.
+func TestProjectReconcileAllocations(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Reconcile Person", 100)
+	projectID := createProject(t, router, orgID, "Reconcile Project")
+	projectPath := routeProjects + "/" + projectID
+	reconcilePath := projectPath + "/reconcile-allocations"
+
+	createAllocationInRange := func(startDate, endDate string) string {
+		t.Helper()
+		payload := personAllocationPayload(personID, projectID, 20)
+		payload["start_date"] = startDate
+		payload["end_date"] = endDate
+		response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders)
+		if response.Code != http.StatusCreated {
+			t.Fatalf("create allocation %s to %s: %d body=%s", startDate, endDate, response.Code, response.Body.String())
+		}
+		var allocation domain.Allocation
+		if err := json.Unmarshal(response.Body.Bytes(), &allocation); err != nil {
+			t.Fatalf("decode allocation: %v", err)
+		}
+		return allocation.ID
+	}
+	overhangingID := createAllocationInRange("2026-05-01", "2026-12-31")
+	outsideID := createAllocationInRange("2026-10-01", "2026-11-30")
+	createAllocationInRange("2026-01-01", "2026-03-31")
+
+	shortened := projectPayload("Reconcile Project")
+	shortened["end_date"] = "2026-06-30"
+	if response := doJSONRequest(t, router, http.MethodPut, projectPath, shortened, adminHeaders); response.Code != http.StatusOK {
+		t.Fatalf("shorten project: %d body=%s", response.Code, response.Body.String())
+	}
+
+	var report domain.AllocationReconciliation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, reconcilePath, nil, adminHeaders), &report)
+	if report.Mode != domain.ReconcileReport || len(report.Clipped) != 0 || len(report.OutOfRange) != 2 {
+		t.Fatalf("expected report mode to list two allocations, got %+v", report)
+	}
+	var unchanged domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeAllocations+"/"+overhangingID, nil, adminHeaders), &unchanged)
+	if unchanged.EndDate != "2026-12-31" {
+		t.Fatalf("expected report mode to leave allocations untouched, got end %s", unchanged.EndDate)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodPost, reconcilePath+"?mode=clip", nil, userHeaders); response.Code != http.StatusForbidden {
+		t.Fatalf("expected org_user reconcile to return 403, got %d", response.Code)
+	}
+	if response := doJSONRequest(t, router, http.MethodPost, reconcilePath+"?mode=shrink", nil, adminHeaders); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown mode to return 400, got %d", response.Code)
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, reconcilePath, nil, adminHeaders); response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET reconcile to return 405, got %d", response.Code)
+	}
+
+	var clipped domain.AllocationReconciliation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, reconcilePath+"?mode=clip", nil, adminHeaders), &clipped)
+	if len(clipped.Clipped) != 1 || clipped.Clipped[0].ID != overhangingID || clipped.Clipped[0].EndDate != "2026-06-30" {
+		t.Fatalf("expected overhanging allocation to be clipped to the new end date, got %+v", clipped.Clipped)
+	}
+	if len(clipped.OutOfRange) != 1 || clipped.OutOfRange[0].ID != outsideID {
+		t.Fatalf("expected allocation outside the new range to need manual handling, got %+v", clipped.OutOfRange)
+	}
+	var stored domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeAllocations+"/"+overhangingID, nil, adminHeaders), &stored)
+	if stored.StartDate != "2026-05-01" || stored.EndDate != "2026-06-30" {
+		t.Fatalf("expected clipped allocation to be stored, got %s to %s", stored.StartDate, stored.EndDate)
+	}
+}
+
+// TestSparseFieldsets verifies the sparse fieldsets scenario.
+func TestSparseFieldsets(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Sparse Person", 80)
+	createPerson(t, router, orgID, "Second Sparse Person", 50)
+
+	assertKeys := func(resource map[string]any, keys ...string) {
+		t.Helper()
+		if len(resource) != len(keys) {
+			t.Fatalf("expected keys %v, got %v", keys, resource)
+		}
+		for _, key := range keys {
+			if _, ok := resource[key]; !ok {
+				t.Fatalf("expected key %q in %v", key, resource)
+			}
+		}
+	}
+
+	var persons []map[string]any
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"?fields=id,name", nil, userHeaders), &persons)
+	if len(persons) != 2 {
+		t.Fatalf("expected two persons, got %v", persons)
+	}
+	for _, person := range persons {
+		assertKeys(person, "id", "name")
+	}
+
+	var person map[string]any
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"/"+personID+"?fields=employment_pct", nil, userHeaders), &person)
+	assertKeys(person, "id", "employment_pct")
+
+	var lenient []map[string]any
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"?fields=name,shoe_size", nil, userHeaders), &lenient)
+	assertKeys(lenient[0], "id", "name")
+
+	var page struct {
+		Items []map[string]any `json:"items"`
+		Total int              `json:"total"`
+	}
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"?fields=name&limit=1", nil, userHeaders), &page)
+	if len(page.Items) != 1 || page.Total != 2 {
+		t.Fatalf("expected a page envelope with one of two persons, got %+v", page)
+	}
+	assertKeys(page.Items[0], "id", "name")
+
+	api, ok := router.(*API)
+	if !ok {
+		t.Fatal("expected router to be *API")
+	}
+	api.strictFields = true
+	strict := doJSONRequest(t, router, http.MethodGet, routePersons+"?fields=name,shoe_size", nil, userHeaders)
+	if strict.Code != http.StatusBadRequest || !strings.Contains(strict.Body.String(), "shoe_size") {
+		t.Fatalf("expected strict unknown field to return 400, got %d body=%s", strict.Code, strict.Body.String())
+	}
+	var full []map[string]any
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons, nil, userHeaders), &full)
+	if _, ok := full[0]["organisation_id"]; !ok {
+		t.Fatalf("expected requests without fields to return full resources, got %v", full[0])
+	}
+}
+
+// TestOrganisationHolidaysPeriodFilter verifies the organisation holidays period filter scenario.
+func TestOrganisationHolidaysPeriodFilter(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	holidaysPath := testOrganisationsPath + "/" + orgID + "/holidays"
+
+	for _, date := range []string{"2025-12-25", "2025-12-31", "2026-01-01", "2026-05-01", "2026-12-25", "2027-01-01"} {
+		response := doJSONRequest(t, router, http.MethodPost, holidaysPath, map[string]any{"date": date, "hours": 8}, adminHeaders)
+		if response.Code != http.StatusCreated {
+			t.Fatalf("create holiday %s: %d body=%s", date, response.Code, response.Body.String())
+		}
+	}
+
+	holidayDates := func(query string) []string {
+		t.Helper()
+		var holidays []domain.OrgHoliday
+		decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, holidaysPath+query, nil, userHeaders), &holidays)
+		dates := make([]string, 0, len(holidays))
+		for _, holiday := range holidays {
+			dates = append(dates, holiday.Date)
+		}
+		return dates
+	}
+	if got := holidayDates("?year=2026"); !reflect.DeepEqual(got, []string{"2026-01-01", "2026-05-01", "2026-12-25"}) {
+		t.Fatalf("expected only 2026 holidays, got %v", got)
+	}
+	if got := holidayDates("?year=2026&from=2026-02-01"); !reflect.DeepEqual(got, []string{"2026-05-01", "2026-12-25"}) {
+		t.Fatalf("expected from to narrow the year, got %v", got)
+	}
+	if got := holidayDates("?from=2025-12-31&to=2026-01-01"); !reflect.DeepEqual(got, []string{"2025-12-31", "2026-01-01"}) {
+		t.Fatalf("expected holidays across the year boundary, got %v", got)
+	}
+	if got := holidayDates(""); len(got) != 6 {
+		t.Fatalf("expected unfiltered list to keep every holiday, got %v", got)
+	}
+
+	for _, query := range []string{"?year=26", "?year=abcd", "?from=2026-02-30", "?from=2026-06-01&to=2026-05-01", "?year=2026&to=2025-12-31"} {
+		if code := doJSONRequest(t, router, http.MethodGet, holidaysPath+query, nil, userHeaders).Code; code != http.StatusBadRequest {
+			t.Fatalf("expected %s to return 400, got %d", query, code)
+		}
+	}
+}
+
+// TestOrganisationHolidaysImport verifies the organisation holidays import scenario.
+func TestOrganisationHolidaysImport(t *testing.T) {
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "holiday-data.json"))
+	if err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	svc, err := service.New(repo, telemetry.NewNoopTelemetry(), impexp.NewCSVImportExport())
+	if err != nil {
+		t.Fatalf(errCreateServiceFmt, err)
+	}
+	router := NewRouterWithDependencies(auth.NewDevAuthProvider(), svc)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	importPath := testOrganisationsPath + "/" + orgID + "/holidays/import"
+
+	if response := doJSONRequest(t, router, http.MethodPost, testOrganisationsPath+"/"+orgID+"/holidays", map[string]any{"date": "2026-10-03", "hours": 8}, adminHeaders); response.Code != http.StatusCreated {
+		t.Fatalf("create holiday: %d body=%s", response.Code, response.Body.String())
+	}
+
+	var national domain.HolidayImportResult
+	decodeJSONResponse(t, doRawRequest(t, router, http.MethodPost, importPath+"?year=2026&country=at", nil, adminHeaders), &national)
+	if len(national.Created) != 13 || len(national.Skipped) != 0 || national.Created[0].Name != "New Year's Day" {
+		t.Fatalf("expected the 13 Austrian holidays, got %+v", national)
+	}
+
+	calendar := []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20271003\r\nSUMMARY:Unity Day\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20271026\r\nSUMMARY:Company Day\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n")
+	calendarHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID, "Content-Type": "text/calendar"}
+	var fromCalendar domain.HolidayImportResult
+	decodeJSONResponse(t, doRawRequest(t, router, http.MethodPost, importPath+"?year=2027&hours=4", calendar, calendarHeaders), &fromCalendar)
+	if len(fromCalendar.Created) != 2 || fromCalendar.Created[0].Hours != 4 || fromCalendar.Created[1].Name != "Company Day" {
+		t.Fatalf("expected both calendar holidays with 4 hours, got %+v", fromCalendar)
+	}
+	var repeated domain.HolidayImportResult
+	decodeJSONResponse(t, doRawRequest(t, router, http.MethodPost, importPath+"?year=2027", calendar, calendarHeaders), &repeated)
+	if len(repeated.Created) != 0 || len(repeated.Skipped) != 2 {
+		t.Fatalf("expected a repeated import to skip both dates, got %+v", repeated)
+	}
+
+	var holidays []domain.OrgHoliday
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, testOrganisationsPath+"/"+orgID+"/holidays?year=2026", nil, userHeaders), &holidays)
+	if len(holidays) != 14 {
+		t.Fatalf("expected 14 holidays in 2026, got %d", len(holidays))
+	}
+
+	for _, query := range []string{"", "?year=twenty", "?year=2026", "?year=2026&country=XX", "?year=2026&country=DE&region=ZZ", "?year=2026&country=DE&hours=many"} {
+		if code := doRawRequest(t, router, http.MethodPost, importPath+query, nil, adminHeaders).Code; code != http.StatusBadRequest {
+			t.Fatalf("expected %q to return 400, got %d", query, code)
+		}
+	}
+	if code := doRawRequest(t, router, http.MethodPost, importPath+"?year=2026&country=DE", nil, userHeaders).Code; code != http.StatusForbidden {
+		t.Fatalf("expected org_user import to return 403, got %d", code)
+	}
+	if code := doRawRequest(t, router, http.MethodGet, importPath, nil, adminHeaders).Code; code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET to return 405, got %d", code)
+	}
+}
+
+// TestReportUnallocatedPersons verifies the report unallocated persons scenario.
+func TestReportUnallocatedPersons(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	allocatedID := createPerson(t, router, orgID, "Busy Person", 100)
+	benchID := createPerson(t, router, orgID, "Bench Person", 100)
+	projectID := createProject(t, router, orgID, "Bench Project")
+
+	payload := personAllocationPayload(allocatedID, projectID, 50)
+	payload["start_date"] = "2026-03-01"
+	payload["end_date"] = "2026-03-31"
+	if response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders); response.Code != http.StatusCreated {
+		t.Fatalf("create allocation: %d body=%s", response.Code, response.Body.String())
+	}
+
+	unallocatedIDs := func(query string) []string {
+		t.Helper()
+		var persons []domain.Person
+		decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/reports/unallocated"+query, nil, userHeaders), &persons)
+		ids := make([]string, 0, len(persons))
+		for _, person := range persons {
+			ids = append(ids, person.ID)
+		}
+		return ids
+	}
+	if got := unallocatedIDs("?as_of=2026-03-15"); !reflect.DeepEqual(got, []string{benchID}) {
+		t.Fatalf("expected only %s on the bench, got %v", benchID, got)
+	}
+	if got := unallocatedIDs("?as_of=2026-04-01"); len(got) != 2 {
+		t.Fatalf("expected both persons on the bench after the allocation ends, got %v", got)
+	}
+	if got := unallocatedIDs("?from=2026-03-25&to=2026-04-10"); !reflect.DeepEqual(got, []string{benchID}) {
+		t.Fatalf("expected a partially allocated range to exclude %s, got %v", allocatedID, got)
+	}
+
+	for _, query := range []string{"", "?as_of=2026-02-30", "?from=2026-03-01", "?from=2026-04-01&to=2026-03-01", "?as_of=2026-03-15&from=2026-03-01&to=2026-03-31"} {
+		if code := doJSONRequest(t, router, http.MethodGet, "/api/reports/unallocated"+query, nil, userHeaders).Code; code != http.StatusBadRequest {
+			t.Fatalf("expected %q to return 400, got %d", query, code)
+		}
+	}
+}
+
+// TestPersonPeakOverallocationEndpoint verifies the person peak overallocation endpoint scenario.
+func TestPersonPeakOverallocationEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Stretched Person", 80)
+	projectID := createProject(t, router, orgID, "Stretched Project")
+
+	for _, allocation := range []struct {
+		percent    float64
+		start, end string
+	}{
+		{60, "2026-03-01", "2026-03-31"},
+		{40, "2026-03-10", "2026-03-20"},
+		{30, "2026-03-15", "2026-03-25"},
+	} {
+		payload := personAllocationPayload(personID, projectID, allocation.percent)
+		payload["start_date"] = allocation.start
+		payload["end_date"] = allocation.end
+		if response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders); response.Code != http.StatusCreated {
+			t.Fatalf("create allocation: %d body=%s", response.Code, response.Body.String())
+		}
+	}
+
+	path := routePersons + "/" + personID + "/peak-overallocation"
+	var peak domain.PeakOverallocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, path+"?from=2026-03-01&to=2026-03-31", nil, userHeaders), &peak)
+	want := domain.PeakOverallocation{
+		PersonID:      personID,
+		Overallocated: true,
+		StartDate:     "2026-03-15",
+		EndDate:       "2026-03-20",
+		LoadPct:       130,
+		CapacityPct:   80,
+		ExcessPct:     50,
+	}
+	if peak != want {
+		t.Fatalf("expected peak %+v, got %+v", want, peak)
+	}
+
+	var clipped domain.PeakOverallocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, path+"?from=2026-03-21&to=2026-03-31", nil, userHeaders), &clipped)
+	if clipped.StartDate != "2026-03-21" || clipped.EndDate != "2026-03-25" || clipped.ExcessPct != 10 {
+		t.Fatalf("expected the peak inside the range to be 2026-03-21 to 2026-03-25 at 10%%, got %+v", clipped)
+	}
+
+	var idle domain.PeakOverallocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, path+"?from=2026-04-01&to=2026-04-30", nil, userHeaders), &idle)
+	if idle.Overallocated || idle.StartDate != "" || idle.ExcessPct != 0 {
+		t.Fatalf("expected no overallocation in April, got %+v", idle)
+	}
+
+	for _, query := range []string{"", "?from=2026-03-01", "?from=2026-03-31&to=2026-03-01", "?from=2026-02-30&to=2026-03-31"} {
+		if code := doJSONRequest(t, router, http.MethodGet, path+query, nil, userHeaders).Code; code != http.StatusBadRequest {
+			t.Fatalf("expected %q to return 400, got %d", query, code)
+		}
+	}
+	missing := doJSONRequest(t, router, http.MethodGet, routePersons+"/missing/peak-overallocation?from=2026-03-01&to=2026-03-31", nil, userHeaders)
+	if missing.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown person to return 404, got %d", missing.Code)
+	}
+}
+
+// TestPersonCapacityEndpoint verifies the person capacity calendar scenario.
+func TestPersonCapacityEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Planned Person", 80)
+	alphaID := createProject(t, router, orgID, "Alpha")
+	betaID := createProject(t, router, orgID, "Beta")
+
+	createGroup := doJSONRequest(t, router, http.MethodPost, routeGroups, map[string]any{"name": "Planned Team", "member_ids": []string{personID}}, adminHeaders)
+	if createGroup.Code != http.StatusCreated {
+		t.Fatalf("create group: %d body=%s", createGroup.Code, createGroup.Body.String())
+	}
+	var group domain.Group
+	if err := json.Unmarshal(createGroup.Body.Bytes(), &group); err != nil {
+		t.Fatalf("decode group: %v", err)
+	}
+
+	for _, entry := range []struct {
+		path  string
+		date  string
+		hours float64
+	}{
+		{path: testOrganisationsPath + "/" + orgID + "/holidays", date: "2026-03-02", hours: 2},
+		{path: routePersons + "/" + personID + "/unavailability", date: "2026-03-03", hours: 1},
+		{path: routeGroups + "/" + group.ID + "/unavailability", date: "2026-03-03", hours: 2},
+	} {
+		response := doJSONRequest(t, router, http.MethodPost, entry.path, map[string]any{"date": entry.date, "hours": entry.hours}, adminHeaders)
+		if response.Code != http.StatusCreated {
+			t.Fatalf("seed %s: %d body=%s", entry.path, response.Code, response.Body.String())
+		}
+	}
+	for _, allocation := range []struct {
+		projectID string
+		percent   float64
+	}{
+		{alphaID, 50},
+		{betaID, 10},
+	} {
+		payload := personAllocationPayload(personID, allocation.projectID, allocation.percent)
+		payload["start_date"] = "2026-03-01"
+		payload["end_date"] = "2026-03-31"
+		if response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders); response.Code != http.StatusCreated {
+			t.Fatalf("create allocation: %d body=%s", response.Code, response.Body.String())
+		}
+	}
+
+	path := routePersons + "/" + personID + "/capacity"
+	var capacity domain.PersonCapacity
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, path+"?from_date=2026-03-02&to_date=2026-03-04", nil, userHeaders), &capacity)
+	if capacity.PersonID != personID || capacity.FromDate != "2026-03-02" || capacity.ToDate != "2026-03-04" || len(capacity.Days) != 3 {
+		t.Fatalf("unexpected capacity header %+v", capacity)
+	}
+	projects := []domain.PersonCapacityProject{{ProjectID: alphaID, Hours: 4}, {ProjectID: betaID, Hours: 0.8}}
+	want := []domain.PersonCapacityDay{
+		{Date: "2026-03-02", Working: true, Projects: projects, CapacityHours: domain.CapacityHours{ContractualHours: 6.4, HolidayHours: 2, AvailableHours: 4.4, AllocatedHours: 4.8, FreeHours: -0.4}},
+		{Date: "2026-03-03", Working: true, Projects: projects, CapacityHours: domain.CapacityHours{ContractualHours: 6.4, PersonUnavailableHours: 1, GroupUnavailableHours: 2, AvailableHours: 3.4, AllocatedHours: 4.8, FreeHours: -1.4}},
+		{Date: "2026-03-04", Working: true, Projects: projects, CapacityHours: domain.CapacityHours{ContractualHours: 6.4, AvailableHours: 6.4, AllocatedHours: 4.8, FreeHours: 1.6}},
+	}
+	if !reflect.DeepEqual(capacity.Days, want) {
+		t.Fatalf("expected days %+v, got %+v", want, capacity.Days)
+	}
+
+	for _, query := range []string{"", "?from_date=2026-03-01", "?from_date=2026-03-31&to_date=2026-03-01", "?from_date=2026-01-01&to_date=2027-01-02"} {
+		if code := doJSONRequest(t, router, http.MethodGet, path+query, nil, userHeaders).Code; code != http.StatusBadRequest {
+			t.Fatalf("expected %q to return 400, got %d", query, code)
+		}
+	}
+	missing := doJSONRequest(t, router, http.MethodGet, routePersons+"/"+testMissingResourceID+"/capacity?from_date=2026-03-01&to_date=2026-03-31", nil, userHeaders)
+	if missing.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown person to return 404, got %d", missing.Code)
+	}
+	if code := doJSONRequest(t, router, http.MethodPost, path, nil, adminHeaders).Code; code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected POST to return 405, got %d", code)
+	}
+}
+
+// TestGroupCapacityEndpoint verifies the group capacity scenario.
+func TestGroupCapacityEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	fullID := createPerson(t, router, orgID, "Full Member", 100)
+	halfID := createPerson(t, router, orgID, "Half Member", 50)
+	projectID := createProject(t, router, orgID, "Team Project")
+
+	createGroup := doJSONRequest(t, router, http.MethodPost, routeGroups, map[string]any{"name": "Capacity Team", "member_ids": []string{fullID, halfID}}, adminHeaders)
+	if createGroup.Code != http.StatusCreated {
+		t.Fatalf("create group: %d body=%s", createGroup.Code, createGroup.Body.String())
+	}
+	var group domain.Group
+	if err := json.Unmarshal(createGroup.Body.Bytes(), &group); err != nil {
+		t.Fatalf("decode group: %v", err)
+	}
+	unavailability := doJSONRequest(t, router, http.MethodPost, routeGroups+"/"+group.ID+"/unavailability", map[string]any{"date": "2026-03-03", "hours": 2}, adminHeaders)
+	if unavailability.Code != http.StatusCreated {
+		t.Fatalf("create group unavailability: %d body=%s", unavailability.Code, unavailability.Body.String())
+	}
+	payload := personAllocationPayload(fullID, projectID, 25)
+	payload["start_date"] = "2026-03-01"
+	payload["end_date"] = "2026-03-31"
+	if response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders); response.Code != http.StatusCreated {
+		t.Fatalf("create allocation: %d body=%s", response.Code, response.Body.String())
+	}
+
+	path := routeGroups + "/" + group.ID + "/capacity"
+	var capacity domain.GroupCapacity
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, path+"?from_date=2026-03-02&to_date=2026-03-10", nil, userHeaders), &capacity)
+	if capacity.GroupID != group.ID || capacity.Granularity != domain.GranularityWeek {
+		t.Fatalf("unexpected capacity header %+v", capacity)
+	}
+	want := []domain.GroupCapacityBucket{
+		{
+			PeriodStart:   "2026-03-02",
+			CapacityHours: domain.CapacityHours{ContractualHours: 84, GroupUnavailableHours: 4, AvailableHours: 80, AllocatedHours: 14, FreeHours: 66},
+			Members: []domain.GroupCapacityMember{
+				{PersonID: fullID, CapacityHours: domain.CapacityHours{ContractualHours: 56, GroupUnavailableHours: 2, AvailableHours: 54, AllocatedHours: 14, FreeHours: 40}},
+				{PersonID: halfID, CapacityHours: domain.CapacityHours{ContractualHours: 28, GroupUnavailableHours: 2, AvailableHours: 26, FreeHours: 26}},
+			},
+		},
+		{
+			PeriodStart:   "2026-03-09",
+			CapacityHours: domain.CapacityHours{ContractualHours: 24, AvailableHours: 24, AllocatedHours: 4, FreeHours: 20},
+			Members: []domain.GroupCapacityMember{
+				{PersonID: fullID, CapacityHours: domain.CapacityHours{ContractualHours: 16, AvailableHours: 16, AllocatedHours: 4, FreeHours: 12}},
+				{PersonID: halfID, CapacityHours: domain.CapacityHours{ContractualHours: 8, AvailableHours: 8, FreeHours: 8}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(capacity.Buckets, want) {
+		t.Fatalf("expected buckets %+v, got %+v", want, capacity.Buckets)
+	}
+
+	var monthly domain.GroupCapacity
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, path+"?from_date=2026-03-02&to_date=2026-03-10&granularity=month", nil, userHeaders), &monthly)
+	if len(monthly.Buckets) != 1 || monthly.Buckets[0].PeriodStart != "2026-03-01" || monthly.Buckets[0].FreeHours != 86 {
+		t.Fatalf("expected one March bucket with 86 free hours, got %+v", monthly.Buckets)
+	}
+
+	for _, query := range []string{"", "?from_date=2026-03-01", "?from_date=2026-03-01&to_date=2026-03-31&granularity=fortnight"} {
+		if code := doJSONRequest(t, router, http.MethodGet, path+query, nil, userHeaders).Code; code != http.StatusBadRequest {
+			t.Fatalf("expected %q to return 400, got %d", query, code)
+		}
+	}
+	missing := doJSONRequest(t, router, http.MethodGet, routeGroups+"/"+testMissingResourceID+"/capacity?from_date=2026-03-01&to_date=2026-03-31", nil, userHeaders)
+	if missing.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown group to return 404, got %d", missing.Code)
+	}
+}
+
+// TestUnavailabilityRulesEndpoints verifies the unavailability rules endpoints scenario.
+func TestUnavailabilityRulesEndpoints(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Rule Person", 100)
+	otherID := createPerson(t, router, orgID, "Other Person", 100)
+
+	createGroup := doJSONRequest(t, router, http.MethodPost, routeGroups, map[string]any{"name": "Rule Team", "member_ids": []string{personID, otherID}}, adminHeaders)
+	if createGroup.Code != http.StatusCreated {
+		t.Fatalf("create group: %d body=%s", createGroup.Code, createGroup.Body.String())
+	}
+	var group domain.Group
+	if err := json.Unmarshal(createGroup.Body.Bytes(), &group); err != nil {
+		t.Fatalf("decode group: %v", err)
+	}
+
+	personRules := routePersons + "/" + personID + "/unavailability/recurring"
+	groupRules := routeGroups + "/" + group.ID + "/unavailability/recurring"
+	createRule := doJSONRequest(t, router, http.MethodPost, personRules, map[string]any{"weekdays": []string{"friday"}, "hours": 4, "start_date": "2026-03-01"}, adminHeaders)
+	if createRule.Code != http.StatusCreated {
+		t.Fatalf("create person rule: %d body=%s", createRule.Code, createRule.Body.String())
+	}
+	var rule domain.UnavailabilityRule
+	if err := json.Unmarshal(createRule.Body.Bytes(), &rule); err != nil {
+		t.Fatalf("decode rule: %v", err)
+	}
+	if rule.PersonID != personID || rule.Version != 1 {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+	createGroupRule := doJSONRequest(t, router, http.MethodPost, groupRules, map[string]any{"weekdays": []string{"monday"}, "hours": 1, "start_date": "2026-03-01", "until_date": "2026-03-08"}, adminHeaders)
+	if createGroupRule.Code != http.StatusCreated {
+		t.Fatalf("create group rule: %d body=%s", createGroupRule.Code, createGroupRule.Body.String())
+	}
+
+	var listed []domain.UnavailabilityRule
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, personRules, nil, userHeaders), &listed)
+	if len(listed) != 1 || listed[0].ID != rule.ID {
+		t.Fatalf("expected the person's rule, got %+v", listed)
+	}
+
+	var capacity domain.PersonCapacity
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routePersons+"/"+personID+"/capacity?from_date=2026-03-02&to_date=2026-03-13", nil, userHeaders), &capacity)
+	unavailable := map[string][2]float64{}
+	for _, day := range capacity.Days {
+		unavailable[day.Date] = [2]float64{day.PersonUnavailableHours, day.GroupUnavailableHours}
+	}
+	for date, want := range map[string][2]float64{
+		"2026-03-02": {0, 1},
+		"2026-03-05": {0, 0},
+		"2026-03-06": {4, 0},
+		"2026-03-09": {0, 0},
+		"2026-03-13": {4, 0},
+	} {
+		if unavailable[date] != want {
+			t.Fatalf("expected person and group unavailability %v on %s, got %v", want, date, unavailable[date])
+		}
+	}
+
+	var groupCapacity domain.GroupCapacity
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeGroups+"/"+group.ID+"/capacity?from_date=2026-03-02&to_date=2026-03-08", nil, userHeaders), &groupCapacity)
+	if len(groupCapacity.Buckets) != 1 || groupCapacity.Buckets[0].PersonUnavailableHours != 4 || groupCapacity.Buckets[0].GroupUnavailableHours != 2 {
+		t.Fatalf("expected the rules in the group capacity, got %+v", groupCapacity.Buckets)
+	}
+
+	rulePath := personRules + "/" + rule.ID
+	if code := doJSONRequest(t, router, http.MethodPut, routePersons+"/"+otherID+"/unavailability/recurring/"+rule.ID, map[string]any{"weekdays": []string{"friday"}, "hours": 2, "start_date": "2026-03-01"}, adminHeaders).Code; code != http.StatusNotFound {
+		t.Fatalf("expected another person's rule to return 404, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodPut, rulePath, map[string]any{"weekdays": []string{"friday"}, "hours": 2, "start_date": "2026-03-01"}, userHeaders).Code; code != http.StatusForbidden {
+		t.Fatalf("expected org_user update to return 403, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodPost, personRules, map[string]any{"weekdays": []string{"someday"}, "hours": 2, "start_date": "2026-03-01"}, adminHeaders).Code; code != http.StatusBadRequest {
+		t.Fatalf("expected an unknown weekday to return 400, got %d", code)
+	}
+
+	var updated domain.UnavailabilityRule
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPut, rulePath, map[string]any{"weekdays": []string{"thursday"}, "hours": 2, "start_date": "2026-03-01", "version": rule.Version}, adminHeaders), &updated)
+	if updated.Version != 2 || updated.Weekdays[0] != "thursday" || updated.PersonID != personID {
+		t.Fatalf("unexpected updated rule %+v", updated)
+	}
+	if code := doJSONRequest(t, router, http.MethodPatch, rulePath, nil, adminHeaders).Code; code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected PATCH to return 405, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, rulePath, nil, adminHeaders).Code; code != http.StatusNoContent {
+		t.Fatalf("expected delete to return 204, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, rulePath, nil, adminHeaders).Code; code != http.StatusNotFound {
+		t.Fatalf("expected a second delete to return 404, got %d", code)
+	}
+	if code := doJSONRequest(t, router, http.MethodGet, routePersons+"/"+testMissingResourceID+"/unavailability/recurring", nil, userHeaders).Code; code != http.StatusNotFound {
+		t.Fatalf("expected unknown person to return 404, got %d", code)
+	}
+}
+
+// TestAllocationConflictsEndpoint verifies the allocation conflicts endpoint scenario.
+func TestAllocationConflictsEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	busyID := createPerson(t, router, orgID, "Busy Person", 80)
+	calmID := createPerson(t, router, orgID, "Calm Person", 100)
+	projectID := createProject(t, router, orgID, "Conflict Project")
+
+	createGroup := doJSONRequest(t, router, http.MethodPost, routeGroups, map[string]any{"name": "Conflict Team", "member_ids": []string{busyID, calmID}}, adminHeaders)
+	if createGroup.Code != http.StatusCreated {
+		t.Fatalf("create group: %d body=%s", createGroup.Code, createGroup.Body.String())
+	}
+	var group domain.Group
+	if err := json.Unmarshal(createGroup.Body.Bytes(), &group); err != nil {
+		t.Fatalf("decode group: %v", err)
+	}
+
+	createInRange := func(payload map[string]any, start, end string) string {
+		t.Helper()
+		payload["start_date"] = start
+		payload["end_date"] = end
+		response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders)
+		if response.Code != http.StatusCreated {
+			t.Fatalf("create allocation: %d body=%s", response.Code, response.Body.String())
+		}
+		var allocation domain.Allocation
+		if err := json.Unmarshal(response.Body.Bytes(), &allocation); err != nil {
+			t.Fatalf("decode allocation: %v", err)
+		}
+		return allocation.ID
+	}
+	directID := createInRange(personAllocationPayload(busyID, projectID, 60), "2026-03-01", "2026-03-31")
+	groupPayload := personAllocationPayload(group.ID, projectID, 40)
+	groupPayload["target_type"] = "group"
+	groupID := createInRange(groupPayload, "2026-03-10", "2026-03-20")
+
+	var conflicts []domain.AllocationConflict
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeAllocations+"/conflicts?from_date=2026-03-01&to_date=2026-03-31", nil, userHeaders), &conflicts)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected one conflict for the busy person, got %+v", conflicts)
+	}
+	conflict := conflicts[0]
+	if conflict.PersonID != busyID || conflict.StartDate != "2026-03-10" || conflict.EndDate != "2026-03-20" ||
+		conflict.LoadPct != 100 || conflict.CapacityPct != 80 || conflict.ExcessPct != 20 || conflict.Severity != domain.OverloadModerate {
+		t.Fatalf("unexpected conflict %+v", conflict)
+	}
+	if len(conflict.AllocationIDs) != 2 || !slices.Contains(conflict.AllocationIDs, directID) || !slices.Contains(conflict.AllocationIDs, groupID) {
+		t.Fatalf("expected direct and group allocation IDs, got %v", conflict.AllocationIDs)
+	}
+
+	var none []domain.AllocationConflict
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeAllocations+"/conflicts?from_date=2026-04-01&to_date=2026-04-30", nil, userHeaders), &none)
+	if len(none) != 0 {
+		t.Fatalf("expected no conflicts in April, got %+v", none)
+	}
+	for _, query := range []string{"", "?from_date=2026-03-01", "?from_date=2026-03-31&to_date=2026-03-01"} {
+		if code := doJSONRequest(t, router, http.MethodGet, routeAllocations+"/conflicts"+query, nil, userHeaders).Code; code != http.StatusBadRequest {
+			t.Fatalf("expected %q to return 400, got %d", query, code)
+		}
+	}
+	if code := doJSONRequest(t, router, http.MethodPost, routeAllocations+"/conflicts", nil, adminHeaders).Code; code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected POST to return 405, got %d", code)
+	}
+}
+
+// TestDeleteWithDependents verifies the delete with dependents scenario.
+func TestDeleteWithDependents(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Referenced Person", 100)
+	bystanderID := createPerson(t, router, orgID, "Bystander", 100)
+	projectID := createProject(t, router, orgID, "Referenced Project")
+
+	createGroup := doJSONRequest(t, router, http.MethodPost, routeGroups, map[string]any{"name": "Referenced Team", "member_ids": []string{personID, bystanderID}}, adminHeaders)
+	if createGroup.Code != http.StatusCreated {
+		t.Fatalf("create group: %d body=%s", createGroup.Code, createGroup.Body.String())
+	}
+	var group domain.Group
+	if err := json.Unmarshal(createGroup.Body.Bytes(), &group); err != nil {
+		t.Fatalf("decode group: %v", err)
+	}
+	createAllocation := doJSONRequest(t, router, http.MethodPost, routeAllocations, personAllocationPayload(personID, projectID, 50), adminHeaders)
+	if createAllocation.Code != http.StatusCreated {
+		t.Fatalf("create allocation: %d body=%s", createAllocation.Code, createAllocation.Body.String())
+	}
+	var allocation domain.Allocation
+	if err := json.Unmarshal(createAllocation.Body.Bytes(), &allocation); err != nil {
+		t.Fatalf("decode allocation: %v", err)
+	}
+
+	personPath := routePersons + "/" + personID
+	refused := doJSONRequest(t, router, http.MethodDelete, personPath, nil, adminHeaders)
+	if refused.Code != http.StatusConflict {
+		t.Fatalf("expected delete with allocations to return 409, got %d body=%s", refused.Code, refused.Body.String())
+	}
+	var body struct {
+		Error      string                  `json:"error"`
+		Dependents domain.DeleteDependents `json:"dependents"`
+	}
+	if err := json.Unmarshal(refused.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode conflict body: %v", err)
+	}
+	if len(body.Dependents.AllocationIDs) != 1 || body.Dependents.AllocationIDs[0] != allocation.ID {
+		t.Fatalf("expected the allocation to be listed as dependent, got %+v", body)
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, routeProjects+"/"+projectID+"?purge=true", nil, adminHeaders).Code; code != http.StatusConflict {
+		t.Fatalf("expected purge with allocations to return 409, got %d", code)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodDelete, personPath+"?cascade=true", nil, adminHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("expected cascading delete to succeed, got %d body=%s", response.Code, response.Body.String())
+	}
+	if code := doJSONRequest(t, router, http.MethodGet, routeAllocations+"/"+allocation.ID, nil, adminHeaders).Code; code != http.StatusNotFound {
+		t.Fatalf("expected cascaded allocation to be gone, got %d", code)
+	}
+	var cleaned domain.Group
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeGroups+"/"+group.ID, nil, adminHeaders), &cleaned)
+	if len(cleaned.MemberIDs) != 1 || cleaned.MemberIDs[0] != bystanderID {
+		t.Fatalf("expected deleted person to leave the group, got %v", cleaned.MemberIDs)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodDelete, routeGroups+"/"+group.ID, nil, adminHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("expected group without dependents to delete, got %d body=%s", response.Code, response.Body.String())
+	}
+	if code := doJSONRequest(t, router, http.MethodDelete, personPath+"?cascade=maybe", nil, adminHeaders).Code; code != http.StatusBadRequest {
+		t.Fatalf("expected invalid cascade flag to return 400, got %d", code)
+	}
+}
+
+// TestReadOnlyOrganisationRejectsWrites verifies the read-only organisation rejects writes scenario.
+func TestReadOnlyOrganisationRejectsWrites(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	organisationPath := testOrganisationsPath + "/" + orgID
+	update := map[string]any{"name": "Frozen Org", "hours_per_day": 8, "hours_per_week": 40, "hours_per_year": 2080, "read_only": true}
+
+	if response := doJSONRequest(t, router, http.MethodPut, organisationPath, update, adminHeaders); response.Code != http.StatusOK {
+		t.Fatalf("expected read-only toggle to succeed, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	created := doJSONRequest(t, router, http.MethodPost, routePersons, map[string]any{"name": "Blocked", "employment_pct": 100}, adminHeaders)
+	if created.Code != http.StatusConflict || !strings.Contains(created.Body.String(), "organisation is read-only") {
+		t.Fatalf("expected 409 read-only error, got %d body=%s", created.Code, created.Body.String())
+	}
+	if listed := doJSONRequest(t, router, http.MethodGet, routePersons, nil, adminHeaders); listed.Code != http.StatusOK {
+		t.Fatalf("expected list to succeed while read-only, got %d", listed.Code)
+	}
+
+	update["read_only"] = false
+	if response := doJSONRequest(t, router, http.MethodPut, organisationPath, update, adminHeaders); response.Code != http.StatusOK {
+		t.Fatalf("expected clearing read-only to succeed, got %d body=%s", response.Code, response.Body.String())
+	}
+	if restored := doJSONRequest(t, router, http.MethodPost, routePersons, map[string]any{"name": "Restored", "employment_pct": 100}, adminHeaders); restored.Code != http.StatusCreated {
+		t.Fatalf("expected create to succeed after clearing read-only, got %d body=%s", restored.Code, restored.Body.String())
+	}
+}
+
+// TestAllocationOverlapWarnings verifies the allocation overlap warnings scenario.
+func TestAllocationOverlapWarnings(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	personID := createPerson(t, router, orgID, "Double Booked", 100)
+	projectID := createProject(t, router, orgID, "Overlap Project")
+	otherProjectID := createProject(t, router, orgID, "Other Project")
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	createAllocation := func(payload map[string]any) domain.Allocation {
+		t.Helper()
+		response := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, adminHeaders)
+		if response.Code != http.StatusCreated {
+			t.Fatalf("expected allocation to be created, got %d body=%s", response.Code, response.Body.String())
+		}
+		var allocation domain.Allocation
+		if err := json.Unmarshal(response.Body.Bytes(), &allocation); err != nil {
+			t.Fatalf("decode allocation: %v", err)
+		}
+		return allocation
+	}
+
+	first := createAllocation(personAllocationPayload(personID, projectID, 20))
+	if len(first.Warnings) != 0 {
+		t.Fatalf("expected no warnings on the first allocation, got %v", first.Warnings)
+	}
+	otherProject := createAllocation(personAllocationPayload(personID, otherProjectID, 20))
+	if len(otherProject.Warnings) != 0 {
+		t.Fatalf("expected no warnings for a different project, got %v", otherProject.Warnings)
+	}
+
+	overlapping := personAllocationPayload(personID, projectID, 20)
+	overlapping["start_date"] = "2026-06-01"
+	overlapping["end_date"] = "2026-12-31"
+	second := createAllocation(overlapping)
+	expected := []string{"overlaps allocation " + first.ID + " on the same project from 2026-06-01 to 2026-12-31"}
+	if !reflect.DeepEqual(second.Warnings, expected) {
+		t.Fatalf("expected overlap warning %v, got %v", expected, second.Warnings)
+	}
+
+	var fetched domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, routeAllocations+"/"+second.ID, nil, adminHeaders), &fetched)
+	if len(fetched.Warnings) != 0 {
+		t.Fatalf("expected warnings not to be stored, got %v", fetched.Warnings)
+	}
+
+	overlapping["start_date"] = "2026-01-01"
+	overlapping["end_date"] = "2026-03-31"
+	var updated domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPut, routeAllocations+"/"+second.ID, overlapping, adminHeaders), &updated)
+	if len(updated.Warnings) != 1 || !strings.Contains(updated.Warnings[0], "from 2026-01-01 to 2026-03-31") {
+		t.Fatalf("expected update to report the overlap, got %v", updated.Warnings)
+	}
+}
+
+// TestAllocationHistoryEndpoint verifies the allocation history endpoint scenario.
+func TestAllocationHistoryEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID, "X-User-ID": "planner"}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Dana", 100)
+	projectID := createProject(t, router, orgID, "Timeline")
+
+	payload := personAllocationPayload(personID, projectID, 20)
+	allocation := decodeCreatedAllocationForPayload(t, router, payload, adminHeaders)
+	allocationPath := routeAllocations + "/" + allocation.ID
 
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/groups/"+state.groupID+"/unavailability/"+state.groupUnavailabilityID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete group unavailability success, got %d", code)
+	payload["percent"] = 40
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPut, allocationPath, payload, adminHeaders), &domain.Allocation{})
+	payload["end_date"] = "2026-06-30"
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPut, allocationPath, payload, adminHeaders), &domain.Allocation{})
+
+	var history []domain.AllocationHistoryEntry
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, allocationPath+"/history", nil, userHeaders), &history)
+	if len(history) != 2 {
+		t.Fatalf("expected two history entries, got %+v", history)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personA+"/unavailability/missing", nil, state.adminHeaders).Code; code != http.StatusNotFound {
-		t.Fatalf("expected delete person unavailability with missing entry to be not found, got %d", code)
+	if history[1].At.Before(history[0].At) {
+		t.Fatalf("expected chronological order, got %+v", history)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personB+"/unavailability/"+state.personUnavailabilityID, nil, state.adminHeaders).Code; code != http.StatusForbidden {
-		t.Fatalf("expected delete person unavailability with mismatched person path to be forbidden, got %d", code)
+	first, second := history[0], history[1]
+	if first.Action != domain.AllocationEventUpdated || first.ActorID != "planner" {
+		t.Fatalf("unexpected first entry: %+v", first)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personA+"/unavailability/"+state.personUnavailabilityID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete person unavailability success, got %d", code)
+	if len(first.Changes) != 1 || first.Changes[0].Field != "percent" || first.Changes[0].From != 20.0 || first.Changes[0].To != 40.0 {
+		t.Fatalf("expected percent change from 20 to 40, got %+v", first.Changes)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/organisations/"+state.orgID+"/holidays/"+state.holidayID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete holiday success, got %d", code)
+	if len(second.Changes) != 1 || second.Changes[0].Field != "end_date" || second.Changes[0].From != "2026-12-31" || second.Changes[0].To != "2026-06-30" {
+		t.Fatalf("expected end_date change, got %+v", second.Changes)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/allocations/"+state.allocationID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete allocation success, got %d", code)
+
+	if response := doJSONRequest(t, router, http.MethodGet, routeAllocations+"/missing/history", nil, userHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown allocation, got %d", response.Code)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/projects/"+state.projectB, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete project success, got %d", code)
+	otherOrgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	otherHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": otherOrgID}
+	if response := doJSONRequest(t, router, http.MethodGet, allocationPath+"/history", nil, otherHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another organisation, got %d", response.Code)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/projects/"+state.projectA, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete project A success, got %d", code)
+	if response := doJSONRequest(t, router, http.MethodPost, allocationPath+"/history", nil, adminHeaders); response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for POST, got %d", response.Code)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/groups/"+state.groupID, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete group success, got %d", code)
+}
+
+// TestGroupAllocationsEndpoint verifies the group allocations endpoint scenario.
+func TestGroupAllocationsEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	memberID := createPerson(t, router, orgID, "Member", 100)
+	outsiderID := createPerson(t, router, orgID, "Outsider", 100)
+	projectID := createProject(t, router, orgID, "Group Project")
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	group := decodeCreatedGroupForMethodNotAllowed(t, router, memberID, adminHeaders)
+
+	groupPayload := personAllocationPayload(group.ID, projectID, 20)
+	groupPayload["target_type"] = "group"
+	groupAllocation := decodeCreatedAllocationForPayload(t, router, groupPayload, adminHeaders)
+	memberAllocation := decodeCreatedAllocationForPayload(t, router, personAllocationPayload(memberID, projectID, 30), adminHeaders)
+	decodeCreatedAllocationForPayload(t, router, personAllocationPayload(outsiderID, projectID, 10), adminHeaders)
+
+	groupAllocationsPath := routeGroups + "/" + group.ID + "/allocations"
+	allocationIDs := func(path string) []string {
+		t.Helper()
+		var allocations []domain.Allocation
+		decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, path, nil, userHeaders), &allocations)
+		ids := make([]string, 0, len(allocations))
+		for _, allocation := range allocations {
+			ids = append(ids, allocation.ID)
+		}
+		return ids
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personB, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete person B success, got %d", code)
+	if ids := allocationIDs(groupAllocationsPath); !reflect.DeepEqual(ids, []string{groupAllocation.ID}) {
+		t.Fatalf("expected only the direct group allocation, got %v", ids)
 	}
-	if code := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+state.personA, nil, state.adminHeaders).Code; code != http.StatusNoContent {
-		t.Fatalf("expected delete person A success, got %d", code)
+	resolved := allocationIDs(groupAllocationsPath + "?resolve_members=true")
+	if !reflect.DeepEqual(resolved, []string{groupAllocation.ID, memberAllocation.ID}) {
+		t.Fatalf("expected direct and member allocations, got %v", resolved)
 	}
-}
 
-// TestRouterNewRouterAndAuthFailure verifies the router new router and auth failure scenario.
-func TestRouterNewRouterAndAuthFailure(t *testing.T) {
-	t.Setenv("DEV_MODE", envBoolTrue)
-	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "router-data.json"))
-	router, err := NewRouterFromEnv()
-	if err != nil {
-		t.Fatalf("create router: %v", err)
+	if response := doJSONRequest(t, router, http.MethodGet, groupAllocationsPath+"?resolve_members=maybe", nil, userHeaders); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid resolve_members, got %d", response.Code)
 	}
-	health := doRawRequest(t, router, http.MethodGet, "/healthz", nil, nil)
-	if health.Code != http.StatusOK {
-		t.Fatalf("expected health from NewRouter, got %d", health.Code)
+	if response := doJSONRequest(t, router, http.MethodGet, routeGroups+"/missing/allocations", nil, userHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown group, got %d", response.Code)
 	}
-
-	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "auth-fail-data.json"))
-	if err != nil {
-		t.Fatalf("create repo: %v", err)
+	otherOrgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	otherHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": otherOrgID}
+	if response := doJSONRequest(t, router, http.MethodGet, groupAllocationsPath, nil, otherHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another tenant's group, got %d", response.Code)
 	}
-	svc, err := service.New(repo, telemetry.NewNoopTelemetry(), impexp.NewNoopImportExport())
-	if err != nil {
-		t.Fatalf(errCreateServiceFmt, err)
+	if response := doJSONRequest(t, router, http.MethodPost, groupAllocationsPath, nil, adminHeaders); response.Code != http.StatusMethodNotAllowed || response.Header().Get(headerAllow) != "GET, HEAD, OPTIONS" {
+		t.Fatalf("expected 405 with GET and HEAD allowed, got %d allow=%q", response.Code, response.Header().Get(headerAllow))
 	}
-	routerWithFailingAuth := NewRouterWithDependencies(failingAuthProvider{}, svc)
+}
 
-	response := doRawRequest(t, routerWithFailingAuth, http.MethodGet, testOrganisationsPath, nil, nil)
-	if response.Code != http.StatusUnauthorized {
-		t.Fatalf("expected unauthorized for failing auth provider, got %d", response.Code)
+// TestAuditAllocationsEndpoint verifies the audit allocations endpoint scenario.
+func TestAuditAllocationsEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	personID := createPerson(t, router, orgID, "Audited", 100)
+	projectID := createProject(t, router, orgID, "Audited Project")
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID, "X-User-ID": "auditor"}
+
+	created := decodeCreatedAllocationForPayload(t, router, personAllocationPayload(personID, projectID, 20), adminHeaders)
+	allocationPath := routeAllocations + "/" + created.ID
+	if response := doJSONRequest(t, router, http.MethodPut, allocationPath, personAllocationPayload(personID, projectID, 40), adminHeaders); response.Code != http.StatusOK {
+		t.Fatalf("expected update to succeed, got %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, allocationPath, nil, adminHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("expected delete to succeed, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	var events []domain.AllocationEvent
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/audit/allocations?from=2000-01-01", nil, adminHeaders), &events)
+	actions := make([]string, 0, len(events))
+	for _, event := range events {
+		actions = append(actions, event.Action)
+		if event.AllocationID != created.ID || event.ActorID != "auditor" || event.OrganisationID != orgID {
+			t.Fatalf("unexpected event identity %+v", event)
+		}
+	}
+	want := []string{domain.AllocationEventCreated, domain.AllocationEventUpdated, domain.AllocationEventDeleted}
+	if !reflect.DeepEqual(actions, want) {
+		t.Fatalf("expected actions %v, got %v", want, actions)
+	}
+	if events[0].Before != nil || events[0].After == nil || events[0].After.Percent != 20 {
+		t.Fatalf("expected created event with after only, got %+v", events[0])
+	}
+	if events[1].Before == nil || events[1].Before.Percent != 20 || events[1].After == nil || events[1].After.Percent != 40 {
+		t.Fatalf("expected updated event with before and after, got %+v", events[1])
+	}
+	if events[2].Before == nil || events[2].Before.Percent != 40 || events[2].After != nil {
+		t.Fatalf("expected deleted event with before only, got %+v", events[2])
 	}
-}
 
-// TestRouterNewRouterProductionModeRequiresJWTSecret verifies the router new router production mode requires JWT secret scenario.
-func TestRouterNewRouterProductionModeRequiresJWTSecret(t *testing.T) {
-	t.Setenv("PRODUCTION_MODE", envBoolTrue)
-	t.Setenv("PLATO_CORS_ALLOWED_ORIGINS", testAppOrigin)
-	t.Setenv("PLATO_AUTH_JWT_HS256_SIGNING_KEY", "")
-	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "prod-data.json"))
+	var future []domain.AllocationEvent
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/audit/allocations?from=9000-01-01", nil, adminHeaders), &future)
+	if len(future) != 0 {
+		t.Fatalf("expected no events in a future window, got %d", len(future))
+	}
 
-	if _, err := NewRouterFromEnv(); err == nil {
-		t.Fatal("expected router creation to fail without production JWT secret")
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	if response := doJSONRequest(t, router, http.MethodGet, "/api/audit/allocations", nil, userHeaders); response.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for org_user, got %d", response.Code)
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, "/api/audit/allocations?from=2026-02-01&to=2026-01-01", nil, adminHeaders); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for inverted range, got %d", response.Code)
+	}
+	otherOrgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	var otherEvents []domain.AllocationEvent
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/audit/allocations", nil, map[string]string{"X-Role": "org_admin", "X-Org-ID": otherOrgID}), &otherEvents)
+	if len(otherEvents) != 0 {
+		t.Fatalf("expected another tenant to see no events, got %d", len(otherEvents))
 	}
 }
 
-// TestRouterNewRouterProductionModeCORSAllowlistAndAuth verifies the router new router production mode CORS allowlist and auth scenario.
-func TestRouterNewRouterProductionModeCORSAllowlistAndAuth(t *testing.T) {
-	t.Setenv("PRODUCTION_MODE", envBoolTrue)
-	t.Setenv("PLATO_AUTH_JWT_HS256_SIGNING_KEY", "test-secret")
-	t.Setenv("PLATO_CORS_ALLOWED_ORIGINS", testAppOrigin)
-	t.Setenv(dataFileEnvVar, filepath.Join(t.TempDir(), "prod-router-data.json"))
-
-	router, err := NewRouterFromEnv()
-	if err != nil {
-		t.Fatalf("create production router: %v", err)
-	}
+// TestHeadOnSingleResourceRoutes verifies the head on single resource routes scenario.
+func TestHeadOnSingleResourceRoutes(t *testing.T) {
+	router := newTestRouter(t)
+	state := setupMethodNotAllowedState(t, router)
 
-	allowlistedOriginResponse := doRawRequest(t, router, http.MethodGet, testOrganisationsPath, nil, map[string]string{
-		"Origin": testAppOrigin,
-	})
-	if allowlistedOriginResponse.Code != http.StatusUnauthorized {
-		t.Fatalf("expected unauthorized without bearer token, got %d", allowlistedOriginResponse.Code)
-	}
-	if got := allowlistedOriginResponse.Header().Get("Access-Control-Allow-Origin"); got != testAppOrigin {
-		t.Fatalf("expected allowlisted origin header, got %q", got)
+	for _, path := range []string{
+		routePersons + "/" + state.personID,
+		routeProjects + "/" + state.projectID,
+		routeGroups + "/" + state.groupID,
+		routeAllocations + "/" + state.allocationID,
+	} {
+		getResponse := doRawRequest(t, router, http.MethodGet, path, nil, state.adminHeaders)
+		headResponse := doRawRequest(t, router, http.MethodHead, path, nil, state.adminHeaders)
+		if headResponse.Code != http.StatusOK {
+			t.Fatalf("expected HEAD %s to return 200, got %d", path, headResponse.Code)
+		}
+		if headResponse.Body.Len() != 0 {
+			t.Fatalf("expected HEAD %s to omit body, got %q", path, headResponse.Body.String())
+		}
+		if got, want := headResponse.Header().Get(headerContentType), getResponse.Header().Get(headerContentType); got != want {
+			t.Fatalf("expected HEAD %s content type %q, got %q", path, want, got)
+		}
 	}
 
-	blockedOriginResponse := doRawRequest(t, router, http.MethodGet, testOrganisationsPath, nil, map[string]string{
-		"Origin": "https://blocked.example.com",
-	})
-	if got := blockedOriginResponse.Header().Get("Access-Control-Allow-Origin"); got != "" {
-		t.Fatalf("expected blocked origin to be omitted from CORS header, got %q", got)
+	for _, path := range []string{
+		routePersons + "/" + testMissingResourceID,
+		routeProjects + "/" + testMissingResourceID,
+		routeGroups + "/" + testMissingResourceID,
+		routeAllocations + "/" + testMissingResourceID,
+	} {
+		headResponse := doRawRequest(t, router, http.MethodHead, path, nil, state.adminHeaders)
+		if headResponse.Code != http.StatusNotFound {
+			t.Fatalf("expected HEAD %s to return 404, got %d", path, headResponse.Code)
+		}
+		if headResponse.Body.Len() != 0 {
+			t.Fatalf("expected HEAD %s to omit error body, got %q", path, headResponse.Body.String())
+		}
 	}
 }
 
@@ -636,7 +3328,13 @@ func decodeCreatedGroupForMethodNotAllowed(t *testing.T, router http.Handler, pe
 func decodeCreatedAllocationForMethodNotAllowed(t *testing.T, router http.Handler, personID, projectID string, headers map[string]string) domain.Allocation {
 	t.Helper()
 
-	createAllocation := doJSONRequest(t, router, http.MethodPost, routeAllocations, personAllocationPayload(personID, projectID, 20), headers)
+	return decodeCreatedAllocationForPayload(t, router, personAllocationPayload(personID, projectID, 20), headers)
+}
+
+func decodeCreatedAllocationForPayload(t *testing.T, router http.Handler, payload map[string]any, headers map[string]string) domain.Allocation {
+	t.Helper()
+
+	createAllocation := doJSONRequest(t, router, http.MethodPost, routeAllocations, payload, headers)
 	if createAllocation.Code != http.StatusCreated {
 		t.Fatalf("setup allocation failed: %d body=%s", createAllocation.Code, createAllocation.Body.String())
 	}
@@ -698,27 +3396,27 @@ func verifyMethodNotAllowedHits(t *testing.T, router http.Handler, state methodN
 		statusCode int
 		allow      string
 	}{
-		{http.MethodPatch, testOrganisationsPath, http.StatusMethodNotAllowed, "GET, POST"},
-		{http.MethodPost, "/api/organisations/" + state.orgID, http.StatusMethodNotAllowed, "GET, PUT, DELETE"},
-		{http.MethodPatch, "/api/organisations/" + state.orgID + "/holidays", http.StatusMethodNotAllowed, "GET, POST"},
-		{http.MethodPatch, routePersons, http.StatusMethodNotAllowed, "GET, POST"},
-		{http.MethodPost, "/api/persons/" + state.personID, http.StatusMethodNotAllowed, "GET, PUT, DELETE"},
-		{http.MethodPatch, "/api/persons/" + state.personID + "/unavailability", http.StatusMethodNotAllowed, "GET, POST"},
-		{http.MethodPatch, routeProjects, http.StatusMethodNotAllowed, "GET, POST"},
-		{http.MethodPatch, "/api/projects/" + state.projectID, http.StatusMethodNotAllowed, "GET, PUT, DELETE"},
-		{http.MethodPatch, routeGroups, http.StatusMethodNotAllowed, "GET, POST"},
-		{http.MethodPatch, "/api/groups/" + state.groupID, http.StatusMethodNotAllowed, "GET, PUT, DELETE"},
-		{http.MethodGet, "/api/groups/" + state.groupID + "/members", http.StatusMethodNotAllowed, "POST"},
-		{http.MethodPatch, "/api/groups/" + state.groupID + "/unavailability", http.StatusMethodNotAllowed, "GET, POST"},
-		{http.MethodPatch, routeAllocations, http.StatusMethodNotAllowed, "GET, POST"},
-		{http.MethodPatch, "/api/allocations/" + state.allocationID, http.StatusMethodNotAllowed, "GET, PUT, DELETE"},
-		{http.MethodGet, routeAvailabilityLoad, http.StatusMethodNotAllowed, "POST"},
-		{http.MethodGet, "/api/organisations/" + state.orgID + "/holidays/" + state.holidayID, http.StatusMethodNotAllowed, "DELETE"},
-		{http.MethodPatch, "/api/organisations/" + state.orgID + "/holidays/" + state.holidayID, http.StatusMethodNotAllowed, "DELETE"},
-		{http.MethodGet, "/api/persons/" + state.personID + "/unavailability/" + state.personUnavailabilityID, http.StatusMethodNotAllowed, "DELETE"},
-		{http.MethodPatch, "/api/persons/" + state.personID + "/unavailability/" + state.personUnavailabilityID, http.StatusMethodNotAllowed, "DELETE"},
-		{http.MethodGet, "/api/groups/" + state.groupID + "/unavailability/" + state.groupUnavailabilityID, http.StatusMethodNotAllowed, "DELETE"},
-		{http.MethodPatch, "/api/groups/" + state.groupID + "/unavailability/" + state.groupUnavailabilityID, http.StatusMethodNotAllowed, "DELETE"},
+		{http.MethodPatch, testOrganisationsPath, http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodPost, "/api/organisations/" + state.orgID, http.StatusMethodNotAllowed, "GET, PUT, DELETE, OPTIONS"},
+		{http.MethodPatch, "/api/organisations/" + state.orgID + "/holidays", http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodPatch, routePersons, http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodPost, "/api/persons/" + state.personID, http.StatusMethodNotAllowed, "GET, HEAD, PUT, DELETE, OPTIONS"},
+		{http.MethodPatch, "/api/persons/" + state.personID + "/unavailability", http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodPatch, routeProjects, http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodPatch, "/api/projects/" + state.projectID, http.StatusMethodNotAllowed, "GET, HEAD, PUT, DELETE, OPTIONS"},
+		{http.MethodPatch, routeGroups, http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodPatch, "/api/groups/" + state.groupID, http.StatusMethodNotAllowed, "GET, HEAD, PUT, DELETE, OPTIONS"},
+		{http.MethodGet, "/api/groups/" + state.groupID + "/members", http.StatusMethodNotAllowed, "POST, OPTIONS"},
+		{http.MethodPatch, "/api/groups/" + state.groupID + "/unavailability", http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodPatch, routeAllocations, http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodPatch, "/api/allocations/" + state.allocationID, http.StatusMethodNotAllowed, "GET, HEAD, PUT, DELETE, OPTIONS"},
+		{http.MethodGet, routeAvailabilityLoad, http.StatusMethodNotAllowed, "POST, OPTIONS"},
+		{http.MethodGet, "/api/organisations/" + state.orgID + "/holidays/" + state.holidayID, http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
+		{http.MethodPatch, "/api/organisations/" + state.orgID + "/holidays/" + state.holidayID, http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
+		{http.MethodGet, "/api/persons/" + state.personID + "/unavailability/" + state.personUnavailabilityID, http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
+		{http.MethodPatch, "/api/persons/" + state.personID + "/unavailability/" + state.personUnavailabilityID, http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
+		{http.MethodGet, "/api/groups/" + state.groupID + "/unavailability/" + state.groupUnavailabilityID, http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
+		{http.MethodPatch, "/api/groups/" + state.groupID + "/unavailability/" + state.groupUnavailabilityID, http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
 	}
 
 	for _, hit := range hits {
@@ -929,8 +3627,8 @@ func TestOrganisationAndReportExtraBranches(t *testing.T) {
 		code   int
 		allow  string
 	}{
-		{http.MethodGet, "/api/organisations/" + orgID + "/holidays/" + holiday.ID, http.StatusMethodNotAllowed, "DELETE"},
-		{http.MethodPatch, "/api/organisations/" + orgID + "/holidays/" + holiday.ID, http.StatusMethodNotAllowed, "DELETE"},
+		{http.MethodGet, "/api/organisations/" + orgID + "/holidays/" + holiday.ID, http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
+		{http.MethodPatch, "/api/organisations/" + orgID + "/holidays/" + holiday.ID, http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
 		{http.MethodDelete, "/api/organisations/" + orgID + "/holidays/missing", http.StatusNotFound, ""},
 		{http.MethodGet, "/api/organisations/" + orgID + "/unknown", http.StatusNotFound, ""},
 		{http.MethodGet, "/api/organisations/" + orgID + "/holidays/extra/path", http.StatusNotFound, ""},
@@ -996,6 +3694,239 @@ func TestDecodeJSONRequestBodyTooLarge(t *testing.T) {
 	}
 }
 
+// TestAllocationTemplatesEndpoints verifies the allocation templates endpoints scenario.
+func TestAllocationTemplatesEndpoints(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	firstID := createPerson(t, router, orgID, "First Rotation", 100)
+	secondID := createPerson(t, router, orgID, "Second Rotation", 100)
+	projectID := createProject(t, router, orgID, "Quarterly Maintenance")
+
+	createTemplate := doJSONRequest(t, router, http.MethodPost, "/api/allocation-templates", map[string]any{
+		"name": "Maintenance rotation",
+		"entries": []map[string]any{
+			{"target_type": "person", "target_id": firstID, "percent": 50},
+			{"target_type": "person", "target_id": secondID, "percent": 25},
+		},
+	}, adminHeaders)
+	if createTemplate.Code != http.StatusCreated {
+		t.Fatalf("create template: %d body=%s", createTemplate.Code, createTemplate.Body.String())
+	}
+	var template domain.AllocationTemplate
+	if err := json.Unmarshal(createTemplate.Body.Bytes(), &template); err != nil {
+		t.Fatalf("decode template: %v", err)
+	}
+	if len(template.Entries) != 2 || template.Version != 1 {
+		t.Fatalf("unexpected template %+v", template)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/allocation-templates", map[string]any{"name": "Empty"}, adminHeaders); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected a template without entries to be rejected, got %d body=%s", response.Code, response.Body.String())
+	}
+	var listed []domain.AllocationTemplate
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/allocation-templates", nil, userHeaders), &listed)
+	if len(listed) != 1 || listed[0].ID != template.ID {
+		t.Fatalf("expected the template, got %+v", listed)
+	}
+
+	applyPath := "/api/allocation-templates/" + template.ID + "/apply?project_id=" + projectID + "&start_date=2026-04-01&end_date=2026-06-30"
+	if response := doJSONRequest(t, router, http.MethodPost, applyPath, nil, userHeaders); response.Code != http.StatusForbidden {
+		t.Fatalf("expected org_user application to be forbidden, got %d", response.Code)
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, applyPath, nil, adminHeaders); response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET on apply to be rejected, got %d", response.Code)
+	}
+	outsideProject := "/api/allocation-templates/" + template.ID + "/apply?project_id=" + projectID + "&start_date=2025-12-01&end_date=2026-01-31"
+	if response := doJSONRequest(t, router, http.MethodPost, outsideProject, nil, adminHeaders); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected dates outside the project to be rejected, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	apply := doJSONRequest(t, router, http.MethodPost, applyPath, nil, adminHeaders)
+	if apply.Code != http.StatusCreated {
+		t.Fatalf("apply template: %d body=%s", apply.Code, apply.Body.String())
+	}
+	var created []domain.Allocation
+	if err := json.Unmarshal(apply.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode allocations: %v", err)
+	}
+	if len(created) != 2 || created[0].PersonID != firstID || created[1].Percent != 25 || created[1].EndDate != "2026-06-30" {
+		t.Fatalf("unexpected allocations %+v", created)
+	}
+
+	var allocations []domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/allocations", nil, userHeaders), &allocations)
+	if len(allocations) != 2 {
+		t.Fatalf("expected only the applied allocations to be stored, got %+v", allocations)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodDelete, "/api/allocation-templates/"+template.ID, nil, adminHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("delete template: %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, "/api/allocation-templates/"+template.ID, nil, userHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected a deleted template to be missing, got %d", response.Code)
+	}
+}
+
+// TestScenarioEndpoints verifies the scenario endpoints scenario.
+func TestScenarioEndpoints(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Scenario Person", 100)
+	projectID := createProject(t, router, orgID, "Scenario Project")
+	allocation := map[string]any{
+		"target_type": "person",
+		"target_id":   personID,
+		"project_id":  projectID,
+		"start_date":  "2026-04-01",
+		"end_date":    "2026-04-30",
+		"percent":     50,
+	}
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/allocations", allocation, adminHeaders); response.Code != http.StatusCreated {
+		t.Fatalf("create allocation: %d body=%s", response.Code, response.Body.String())
+	}
+
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/scenarios", map[string]any{"name": "Hiring"}, userHeaders); response.Code != http.StatusForbidden {
+		t.Fatalf("expected org_user scenario creation to be forbidden, got %d", response.Code)
+	}
+	createScenario := doJSONRequest(t, router, http.MethodPost, "/api/scenarios", map[string]any{"name": "Hiring"}, adminHeaders)
+	if createScenario.Code != http.StatusCreated {
+		t.Fatalf("create scenario: %d body=%s", createScenario.Code, createScenario.Body.String())
+	}
+	var scenario domain.Scenario
+	if err := json.Unmarshal(createScenario.Body.Bytes(), &scenario); err != nil {
+		t.Fatalf("decode scenario: %v", err)
+	}
+	if len(scenario.Allocations) != 1 {
+		t.Fatalf("expected the live allocation to be cloned, got %+v", scenario)
+	}
+
+	scenarioPath := "/api/scenarios/" + scenario.ID
+	createAllocation := doJSONRequest(t, router, http.MethodPost, scenarioPath+"/allocations", allocation, adminHeaders)
+	if createAllocation.Code != http.StatusCreated {
+		t.Fatalf("create scenario allocation: %d body=%s", createAllocation.Code, createAllocation.Body.String())
+	}
+	var added domain.Allocation
+	if err := json.Unmarshal(createAllocation.Body.Bytes(), &added); err != nil {
+		t.Fatalf("decode scenario allocation: %v", err)
+	}
+	allocation["percent"] = 25
+	if response := doJSONRequest(t, router, http.MethodPut, scenarioPath+"/allocations/"+added.ID, allocation, adminHeaders); response.Code != http.StatusOK {
+		t.Fatalf("update scenario allocation: %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, scenarioPath+"/allocations/missing", nil, adminHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected an unknown scenario allocation to be missing, got %d", response.Code)
+	}
+	var scenarioAllocations []domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, scenarioPath+"/allocations", nil, userHeaders), &scenarioAllocations)
+	if len(scenarioAllocations) != 2 {
+		t.Fatalf("expected two scenario allocations, got %+v", scenarioAllocations)
+	}
+
+	report := map[string]any{
+		"scope":       "person",
+		"ids":         []string{personID},
+		"from_date":   "2026-04-01",
+		"to_date":     "2026-04-30",
+		"granularity": "month",
+		"scenario_id": scenario.ID,
+	}
+	var result domain.ReportResult
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, "/api/reports/availability-load", report, userHeaders), &result)
+	if len(result.Buckets) != 1 || result.Buckets[0].LoadHours <= 0 {
+		t.Fatalf("expected a scenario report bucket, got %+v", result)
+	}
+
+	var summaries []domain.ScenarioSummary
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/scenarios", nil, userHeaders), &summaries)
+	if len(summaries) != 1 || summaries[0].AllocationCount != 2 {
+		t.Fatalf("expected one scenario summary, got %+v", summaries)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodGet, scenarioPath+"/apply", nil, adminHeaders); response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET on apply to be rejected, got %d", response.Code)
+	}
+	var applied domain.ScenarioChanges
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, scenarioPath+"/apply", nil, adminHeaders), &applied)
+	if len(applied.Created) != 1 || applied.Created[0].Percent != 25 {
+		t.Fatalf("expected the scenario allocation to be created, got %+v", applied)
+	}
+	var allocations []domain.Allocation
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/allocations", nil, userHeaders), &allocations)
+	if len(allocations) != 2 {
+		t.Fatalf("expected the applied allocation to be live, got %+v", allocations)
+	}
+	if response := doJSONRequest(t, router, http.MethodPost, scenarioPath+"/apply", nil, adminHeaders); response.Code != http.StatusConflict {
+		t.Fatalf("expected applying twice to conflict, got %d", response.Code)
+	}
+
+	if response := doJSONRequest(t, router, http.MethodDelete, scenarioPath, nil, adminHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("delete scenario: %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, scenarioPath, nil, userHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected a deleted scenario to be missing, got %d", response.Code)
+	}
+}
+
+// TestUndoOperationEndpoint verifies the undo endpoint scenario.
+func TestUndoOperationEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Undo Person", 100)
+	projectID := createProject(t, router, orgID, "Undo Project")
+
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/operations/undo", nil, adminHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected nothing to undo, got %d body=%s", response.Code, response.Body.String())
+	}
+	createAllocation := doJSONRequest(t, router, http.MethodPost, "/api/allocations", map[string]any{
+		"target_type": "person",
+		"target_id":   personID,
+		"project_id":  projectID,
+		"start_date":  "2026-04-01",
+		"end_date":    "2026-04-30",
+		"percent":     50,
+	}, adminHeaders)
+	if createAllocation.Code != http.StatusCreated {
+		t.Fatalf("create allocation: %d body=%s", createAllocation.Code, createAllocation.Body.String())
+	}
+	var allocation domain.Allocation
+	if err := json.Unmarshal(createAllocation.Body.Bytes(), &allocation); err != nil {
+		t.Fatalf("decode allocation: %v", err)
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, "/api/allocations/"+allocation.ID, nil, adminHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("delete allocation: %d body=%s", response.Code, response.Body.String())
+	}
+
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/operations/undo", nil, userHeaders); response.Code != http.StatusForbidden {
+		t.Fatalf("expected org_user undo to be forbidden, got %d", response.Code)
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, "/api/operations/undo", nil, adminHeaders); response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET on undo to be rejected, got %d", response.Code)
+	}
+	var undone domain.UndoResult
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, "/api/operations/undo", nil, adminHeaders), &undone)
+	if undone.Operation.Kind != domain.OperationAllocationDeleted || undone.Allocation == nil || undone.Allocation.ID != allocation.ID {
+		t.Fatalf("expected the delete to be undone, got %+v", undone)
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, "/api/allocations/"+allocation.ID, nil, userHeaders); response.Code != http.StatusOK {
+		t.Fatalf("expected the allocation to be back, got %d", response.Code)
+	}
+
+	var undoneCreate domain.UndoResult
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodPost, "/api/operations/undo", nil, adminHeaders), &undoneCreate)
+	if undoneCreate.Operation.Kind != domain.OperationAllocationCreated || undoneCreate.Allocation != nil {
+		t.Fatalf("expected the create to be undone, got %+v", undoneCreate)
+	}
+	if response := doJSONRequest(t, router, http.MethodGet, "/api/allocations/"+allocation.ID, nil, userHeaders); response.Code != http.StatusNotFound {
+		t.Fatalf("expected the allocation to be gone, got %d", response.Code)
+	}
+}
+
 func newTestRouter(t *testing.T) http.Handler {
 	t.Helper()
 	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "test-data.json"))
@@ -1110,3 +4041,130 @@ func doRawRequest(t *testing.T, handler http.Handler, method, path string, body
 	handler.ServeHTTP(response, request)
 	return response
 }
+
+// TestSearchEndpoint verifies the search endpoint scenario.
+func TestSearchEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	otherOrgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	userHeaders := map[string]string{"X-Role": "org_user", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Marie Curie", 100)
+	createPerson(t, router, otherOrgID, "Marie Other Tenant", 100)
+
+	if response := doJSONRequest(t, router, http.MethodGet, "/api/search", nil, userHeaders); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected a missing query to be rejected, got %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/search?q=marie", nil, userHeaders); response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected POST on search to be rejected, got %d", response.Code)
+	}
+
+	var results []domain.SearchResult
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/search?q=mar", nil, userHeaders), &results)
+	if len(results) != 1 || results[0].ID != personID || results[0].EntityType != domain.SearchEntityPerson {
+		t.Fatalf("expected only the person of the caller's organisation, got %+v", results)
+	}
+	if results[0].Snippet != "<mark>Mar</mark>ie Curie" {
+		t.Fatalf("expected a highlighted snippet, got %q", results[0].Snippet)
+	}
+
+	projectID := createProject(t, router, orgID, "Marathon")
+	var afterWrite []domain.SearchResult
+	decodeJSONResponse(t, doJSONRequest(t, router, http.MethodGet, "/api/search?q=mar", nil, userHeaders), &afterWrite)
+	if len(afterWrite) != 2 || afterWrite[1].ID != projectID {
+		t.Fatalf("expected the index to pick up the new project, got %+v", afterWrite)
+	}
+}
+
+// TestOrgPlannerRole verifies the org planner allocation-only write scenario.
+func TestOrgPlannerRole(t *testing.T) {
+	router := newTestRouter(t)
+	orgID := createOrganisation(t, router, map[string]string{"X-Role": "org_admin"})
+	adminHeaders := map[string]string{"X-Role": "org_admin", "X-Org-ID": orgID}
+	plannerHeaders := map[string]string{"X-Role": "org_planner", "X-Org-ID": orgID}
+	personID := createPerson(t, router, orgID, "Planner Person", 100)
+	projectID := createProject(t, router, orgID, "Planner Project")
+	createGroup := doJSONRequest(t, router, http.MethodPost, routeGroups, map[string]any{"name": "Planner Group", "member_ids": []string{personID}}, adminHeaders)
+	if createGroup.Code != http.StatusCreated {
+		t.Fatalf("create group: %d body=%s", createGroup.Code, createGroup.Body.String())
+	}
+	var group domain.Group
+	if err := json.Unmarshal(createGroup.Body.Bytes(), &group); err != nil {
+		t.Fatalf("decode group: %v", err)
+	}
+
+	for _, path := range []string{routePersons, routeProjects, routeGroups, routeAllocations, "/api/organisations/" + orgID, "/api/search?q=planner"} {
+		if response := doJSONRequest(t, router, http.MethodGet, path, nil, plannerHeaders); response.Code != http.StatusOK {
+			t.Fatalf("expected planner to read %s, got %d body=%s", path, response.Code, response.Body.String())
+		}
+	}
+
+	forbidden := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{method: http.MethodPost, path: "/api/organisations", body: map[string]any{"name": "Planner Org", "hours_per_day": 8, "hours_per_week": 40, "hours_per_year": 2080}},
+		{method: http.MethodPut, path: "/api/organisations/" + orgID, body: map[string]any{"name": "Renamed", "hours_per_day": 8, "hours_per_week": 40, "hours_per_year": 2080}},
+		{method: http.MethodPost, path: "/api/organisations/" + orgID + "/holidays", body: map[string]any{"date": "2026-01-01", "hours": 8}},
+		{method: http.MethodPost, path: routePersons, body: map[string]any{"name": "Another", "employment_pct": 100}},
+		{method: http.MethodDelete, path: "/api/persons/" + personID},
+		{method: http.MethodPost, path: routeProjects, body: projectPayload("Another Project")},
+		{method: http.MethodDelete, path: "/api/projects/" + projectID},
+		{method: http.MethodPost, path: routeGroups, body: map[string]any{"name": "Another Group", "member_ids": []string{personID}}},
+		{method: http.MethodDelete, path: "/api/groups/" + group.ID},
+		{method: http.MethodPost, path: "/api/api-keys", body: map[string]any{"name": "ci", "roles": []string{"org_user"}}},
+	}
+	for _, request := range forbidden {
+		if response := doJSONRequest(t, router, request.method, request.path, request.body, plannerHeaders); response.Code != http.StatusForbidden {
+			t.Fatalf("expected planner %s %s to be forbidden, got %d body=%s", request.method, request.path, response.Code, response.Body.String())
+		}
+	}
+
+	createAllocation := doJSONRequest(t, router, http.MethodPost, routeAllocations, personAllocationPayload(personID, projectID, 50), plannerHeaders)
+	if createAllocation.Code != http.StatusCreated {
+		t.Fatalf("expected planner to create an allocation, got %d body=%s", createAllocation.Code, createAllocation.Body.String())
+	}
+	var allocation domain.Allocation
+	if err := json.Unmarshal(createAllocation.Body.Bytes(), &allocation); err != nil {
+		t.Fatalf("decode allocation: %v", err)
+	}
+	if response := doJSONRequest(t, router, http.MethodPut, "/api/allocations/"+allocation.ID, personAllocationPayload(personID, projectID, 40), plannerHeaders); response.Code != http.StatusOK {
+		t.Fatalf("expected planner to update the allocation, got %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, "/api/allocations/"+allocation.ID, nil, plannerHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("expected planner to delete the allocation, got %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/operations/undo", nil, plannerHeaders); response.Code != http.StatusOK {
+		t.Fatalf("expected planner to undo the delete, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	createPersonUnavailable := doJSONRequest(t, router, http.MethodPost, "/api/persons/"+personID+"/unavailability", map[string]any{"date": "2026-01-02", "hours": 2}, plannerHeaders)
+	if createPersonUnavailable.Code != http.StatusCreated {
+		t.Fatalf("expected planner to create person unavailability, got %d body=%s", createPersonUnavailable.Code, createPersonUnavailable.Body.String())
+	}
+	var personUnavailable domain.PersonUnavailability
+	if err := json.Unmarshal(createPersonUnavailable.Body.Bytes(), &personUnavailable); err != nil {
+		t.Fatalf("decode person unavailability: %v", err)
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, "/api/persons/"+personID+"/unavailability/"+personUnavailable.ID, nil, plannerHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("expected planner to delete person unavailability, got %d body=%s", response.Code, response.Body.String())
+	}
+	createGroupUnavailable := doJSONRequest(t, router, http.MethodPost, "/api/groups/"+group.ID+"/unavailability", map[string]any{"date": "2026-01-03", "hours": 3}, plannerHeaders)
+	if createGroupUnavailable.Code != http.StatusCreated {
+		t.Fatalf("expected planner to create group unavailability, got %d body=%s", createGroupUnavailable.Code, createGroupUnavailable.Body.String())
+	}
+	var groupUnavailable domain.GroupUnavailability
+	if err := json.Unmarshal(createGroupUnavailable.Body.Bytes(), &groupUnavailable); err != nil {
+		t.Fatalf("decode group unavailability: %v", err)
+	}
+	if response := doJSONRequest(t, router, http.MethodDelete, "/api/groups/"+group.ID+"/unavailability/"+groupUnavailable.ID, nil, plannerHeaders); response.Code != http.StatusNoContent {
+		t.Fatalf("expected planner to delete group unavailability, got %d body=%s", response.Code, response.Body.String())
+	}
+
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/scenarios", map[string]any{"name": "Planner Scenario"}, plannerHeaders); response.Code != http.StatusCreated {
+		t.Fatalf("expected planner to create a scenario, got %d body=%s", response.Code, response.Body.String())
+	}
+	if response := doJSONRequest(t, router, http.MethodPost, "/api/api-keys", map[string]any{"name": "planner", "roles": []string{"org_planner"}}, adminHeaders); response.Code != http.StatusCreated {
+		t.Fatalf("expected an api key with the planner role, got %d body=%s", response.Code, response.Body.String())
+	}
+}
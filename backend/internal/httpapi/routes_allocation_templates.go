@@ -0,0 +1,108 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+const allocationTemplatesResource = "allocation-templates"
+
+func matchAllocationTemplatesRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
+	if isCollectionRoute(segments, allocationTemplatesResource) {
+		api.handleAllocationTemplates(w, r, authCtx)
+		return true
+	}
+	if isItemRoute(segments, allocationTemplatesResource) {
+		api.handleAllocationTemplateByID(w, r, authCtx, segments)
+		return true
+	}
+	return false
+}
+
+func (a *API) handleAllocationTemplates(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	switch r.Method {
+	case http.MethodGet:
+		templates, err := a.service.ListAllocationTemplates(r.Context(), authCtx)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, nonNilList(templates))
+	case http.MethodPost:
+		var input domain.AllocationTemplate
+		if err := decodeJSON(w, r, &input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		created, err := a.service.CreateAllocationTemplate(r.Context(), authCtx, input)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (a *API) handleAllocationTemplateByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) {
+	templateID, ok := parseResourceID(segments)
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	if len(segments) == 3 {
+		a.dispatchAllocationTemplateByIDMethod(w, r, authCtx, templateID)
+		return
+	}
+
+	if len(segments) == 4 && isSubresourceRoute(segments, "apply") {
+		a.applyAllocationTemplate(w, r, authCtx, templateID)
+		return
+	}
+
+	notFound(w)
+}
+
+func (a *API) dispatchAllocationTemplateByIDMethod(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, templateID string) {
+	switch r.Method {
+	case http.MethodGet:
+		template, err := a.service.GetAllocationTemplate(r.Context(), authCtx, templateID)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, template)
+	case http.MethodDelete:
+		if err := a.service.DeleteAllocationTemplate(r.Context(), authCtx, templateID); err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+	}
+}
+
+func (a *API) applyAllocationTemplate(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, templateID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	query := r.URL.Query()
+	created, err := a.service.ApplyAllocationTemplate(r.Context(), authCtx, templateID, domain.AllocationTemplateApplication{
+		ProjectID: strings.TrimSpace(query.Get("project_id")),
+		StartDate: strings.TrimSpace(query.Get("start_date")),
+		EndDate:   strings.TrimSpace(query.Get("end_date")),
+	})
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, nonNilList(created))
+}
@@ -1,6 +1,8 @@
 package httpapi
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 
 	"plato/backend/internal/domain"
@@ -10,12 +12,25 @@ import (
 func (a *API) handleAllocations(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
 	switch r.Method {
 	case http.MethodGet:
+		if query := r.URL.Query(); query.Has("active_on") {
+			a.listAllocationsActiveOn(w, r, authCtx, query.Get("active_on"))
+			return
+		}
+		query, paged, err := listQueryFor[domain.Allocation](r)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		if paged || len(query.Filters) > 0 {
+			a.listAllocationsPage(w, r, authCtx, query, paged)
+			return
+		}
 		allocations, err := a.service.ListAllocations(r.Context(), authCtx)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, allocations)
+		writeJSON(w, http.StatusOK, nonNilList(a.display.allocations(allocations)))
 	case http.MethodPost:
 		var input domain.Allocation
 		if err := decodeJSON(w, r, &input); err != nil {
@@ -24,44 +39,201 @@ func (a *API) handleAllocations(w http.ResponseWriter, r *http.Request, authCtx
 		}
 		created, err := a.service.CreateAllocation(r.Context(), authCtx, input)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusCreated, created)
+		writeJSON(w, http.StatusCreated, a.display.allocation(created))
 	default:
 		methodNotAllowed(w, http.MethodGet, http.MethodPost)
 	}
 }
 
+func (a *API) listAllocationsPage(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, query domain.ListQuery, paged bool) {
+	page, err := a.service.ListAllocationsPage(r.Context(), authCtx, query)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	page.Items = a.display.allocations(page.Items)
+	writeListPage(w, page, paged)
+}
+
+func (a *API) listAllocationsActiveOn(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, date string) {
+	allocations, err := a.service.ListAllocationsActiveOn(r.Context(), authCtx, date)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(a.display.activeAllocations(allocations)))
+}
+
 func (a *API) handleAllocationByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) {
 	allocationID := segments[2]
+	if len(segments) == 3 && allocationID == "import" {
+		a.importAllocations(w, r, authCtx)
+		return
+	}
+	if len(segments) == 3 && allocationID == "validate" {
+		a.validateAllocation(w, r, authCtx)
+		return
+	}
+	if len(segments) == 3 && allocationID == "conflicts" {
+		a.allocationConflicts(w, r, authCtx)
+		return
+	}
+	if len(segments) == 4 && isSubresourceRoute(segments, "end") {
+		a.endAllocation(w, r, authCtx, allocationID)
+		return
+	}
+	if len(segments) == 4 && isSubresourceRoute(segments, "history") {
+		a.allocationHistory(w, r, authCtx, allocationID)
+		return
+	}
+	if len(segments) != 3 {
+		notFound(w)
+		return
+	}
+	w = bodylessForHead(w, r)
 	switch r.Method {
-	case http.MethodGet:
+	case http.MethodGet, http.MethodHead:
 		allocation, err := a.service.GetAllocation(r.Context(), authCtx, allocationID)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, allocation)
+		setVersionETag(w, allocation.Version)
+		writeJSON(w, http.StatusOK, a.display.allocation(allocation))
 	case http.MethodPut:
 		var input domain.Allocation
 		if err := decodeJSON(w, r, &input); err != nil {
 			writeDecodeError(w, err)
 			return
 		}
+		var err error
+		if input.Version, err = ifMatchVersion(r, input.Version); err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
 		updated, err := a.service.UpdateAllocation(r.Context(), authCtx, allocationID, input)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, updated)
+		setVersionETag(w, updated.Version)
+		writeJSON(w, http.StatusOK, a.display.allocation(updated))
 	case http.MethodDelete:
 		if err := a.service.DeleteAllocation(r.Context(), authCtx, allocationID); err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
 		w.WriteHeader(http.StatusNoContent)
 	default:
-		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+		methodNotAllowed(w, http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (a *API) endAllocation(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, allocationID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var payload struct {
+		EndDate string `json:"end_date"`
+		Reason  string `json:"reason"`
+	}
+	if err := decodeJSON(w, r, &payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	ended, err := a.service.EndAllocation(r.Context(), authCtx, allocationID, payload.EndDate, payload.Reason)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.display.allocation(ended))
+}
+
+// allocationConflicts lists the people whose combined allocations exceed
+// their employment between the from_date and to_date query dates.
+func (a *API) allocationConflicts(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	w = bodylessForHead(w, r)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, http.MethodGet, http.MethodHead)
+		return
+	}
+
+	query := r.URL.Query()
+	conflicts, err := a.service.AllocationConflicts(r.Context(), authCtx, query.Get("from_date"), query.Get("to_date"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(conflicts))
+}
+
+func (a *API) allocationHistory(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, allocationID string) {
+	w = bodylessForHead(w, r)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, http.MethodGet, http.MethodHead)
+		return
+	}
+
+	history, err := a.service.GetAllocationHistory(r.Context(), authCtx, allocationID)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(history))
+}
+
+// importAllocations creates allocations from a CSV body that names persons,
+// groups, and projects. The response reports the outcome of every line.
+func (a *API) importAllocations(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJSONBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("request body too large (max %d bytes)", maxJSONBodyBytes))
+		return
+	}
+
+	result, err := a.service.ImportAllocationsByName(r.Context(), authCtx, raw)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// validateAllocation checks an allocation payload without saving it. The
+// optional check_limit flag also runs the daily allocation limit.
+func (a *API) validateAllocation(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	checkLimit, err := queryBool(r, "check_limit")
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+
+	var input domain.Allocation
+	if err := decodeJSON(w, r, &input); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	result, err := a.service.ValidateAllocation(r.Context(), authCtx, input, checkLimit)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
 	}
+	writeJSON(w, http.StatusOK, result)
 }
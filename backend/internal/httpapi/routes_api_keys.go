@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+func matchAPIKeysRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
+	if isCollectionRoute(segments, "api-keys") {
+		api.handleAPIKeys(w, r, authCtx)
+		return true
+	}
+	if len(segments) == 4 && isItemRoute(segments, "api-keys") && isSubresourceRoute(segments, "revoke") {
+		api.revokeAPIKey(w, r, authCtx, segments[2])
+		return true
+	}
+	return false
+}
+
+func (a *API) handleAPIKeys(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	switch r.Method {
+	case http.MethodGet:
+		keys, err := a.service.ListAPIKeys(r.Context(), authCtx)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, nonNilList(keys))
+	case http.MethodPost:
+		var input domain.APIKey
+		if err := decodeJSON(w, r, &input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		created, err := a.service.CreateAPIKey(r.Context(), authCtx, input)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (a *API) revokeAPIKey(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, keyID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	revoked, err := a.service.RevokeAPIKey(r.Context(), authCtx, keyID)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, revoked)
+}
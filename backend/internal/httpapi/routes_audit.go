@@ -0,0 +1,22 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"plato/backend/internal/ports"
+)
+
+func (a *API) handleAuditAllocations(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	events, err := a.service.ListAllocationEvents(r.Context(), authCtx, query.Get("from"), query.Get("to"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(events))
+}
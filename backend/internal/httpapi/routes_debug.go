@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"plato/backend/internal/ports"
+)
+
+// routeDescriptor names one route template and the methods it accepts.
+type routeDescriptor struct {
+	Path    string   `json:"path"`
+	Methods []string `json:"methods"`
+}
+
+// routeTable lists every route the router dispatches. Keep it in sync with
+// apiRouteMatchers, the methodNotAllowed lists in the route handlers, and
+// openAPIOperations.
+var routeTable = []routeDescriptor{
+	{Path: healthRoutePath, Methods: []string{http.MethodGet}},
+	{Path: metricsRoutePath, Methods: []string{http.MethodGet, http.MethodHead}},
+	{Path: "/api/_routes", Methods: []string{http.MethodGet}},
+	{Path: openAPIRoutePath, Methods: []string{http.MethodGet}},
+	{Path: apiDocsRoutePath, Methods: []string{http.MethodGet}},
+	{Path: "/api/organisations", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/organisations/{id}", Methods: []string{http.MethodGet, http.MethodPut, http.MethodDelete}},
+	{Path: "/api/organisations/{id}/holidays", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/organisations/{id}/holidays/import", Methods: []string{http.MethodPost}},
+	{Path: "/api/organisations/{id}/holidays/{holiday_id}", Methods: []string{http.MethodDelete}},
+	{Path: "/api/organisations/{id}/calendar", Methods: []string{http.MethodDelete}},
+	{Path: "/api/persons", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/persons/batch-get", Methods: []string{http.MethodPost}},
+	{Path: "/api/persons/{id}", Methods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}},
+	{Path: "/api/persons/{id}/unavailability", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/persons/{id}/unavailability/{entry_id}", Methods: []string{http.MethodDelete}},
+	{Path: "/api/persons/{id}/unavailability/recurring", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/persons/{id}/unavailability/recurring/{rule_id}", Methods: []string{http.MethodPut, http.MethodDelete}},
+	{Path: "/api/persons/{id}/peak-overallocation", Methods: []string{http.MethodGet, http.MethodHead}},
+	{Path: "/api/persons/{id}/capacity", Methods: []string{http.MethodGet, http.MethodHead}},
+	{Path: "/api/projects", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/projects/{id}", Methods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}},
+	{Path: "/api/projects/{id}/reconcile-allocations", Methods: []string{http.MethodPost}},
+	{Path: "/api/projects/{id}/restore", Methods: []string{http.MethodPost}},
+	{Path: "/api/groups", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/groups/{id}", Methods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}},
+	{Path: "/api/groups/{id}/members", Methods: []string{http.MethodPost}},
+	{Path: "/api/groups/{id}/members/{person_id}", Methods: []string{http.MethodDelete}},
+	{Path: "/api/groups/{id}/unavailability", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/groups/{id}/unavailability/{entry_id}", Methods: []string{http.MethodDelete}},
+	{Path: "/api/groups/{id}/unavailability/recurring", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/groups/{id}/unavailability/recurring/{rule_id}", Methods: []string{http.MethodPut, http.MethodDelete}},
+	{Path: "/api/groups/{id}/allocations", Methods: []string{http.MethodGet, http.MethodHead}},
+	{Path: "/api/groups/{id}/capacity", Methods: []string{http.MethodGet, http.MethodHead}},
+	{Path: "/api/allocations", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/allocations/import", Methods: []string{http.MethodPost}},
+	{Path: "/api/allocations/validate", Methods: []string{http.MethodPost}},
+	{Path: "/api/allocations/conflicts", Methods: []string{http.MethodGet, http.MethodHead}},
+	{Path: "/api/allocations/{id}", Methods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}},
+	{Path: "/api/allocations/{id}/end", Methods: []string{http.MethodPost}},
+	{Path: "/api/allocations/{id}/history", Methods: []string{http.MethodGet, http.MethodHead}},
+	{Path: "/api/allocation-templates", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/allocation-templates/{id}", Methods: []string{http.MethodGet, http.MethodDelete}},
+	{Path: "/api/allocation-templates/{id}/apply", Methods: []string{http.MethodPost}},
+	{Path: "/api/scenarios", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/scenarios/{id}", Methods: []string{http.MethodGet, http.MethodDelete}},
+	{Path: "/api/scenarios/{id}/allocations", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/scenarios/{id}/allocations/{allocation_id}", Methods: []string{http.MethodPut, http.MethodDelete}},
+	{Path: "/api/scenarios/{id}/apply", Methods: []string{http.MethodPost}},
+	{Path: "/api/reports/availability-load", Methods: []string{http.MethodPost}},
+	{Path: "/api/reports/what-if", Methods: []string{http.MethodPost}},
+	{Path: "/api/reports/diff", Methods: []string{http.MethodPost}},
+	{Path: "/api/reports/unallocated", Methods: []string{http.MethodGet}},
+	{Path: "/api/audit/allocations", Methods: []string{http.MethodGet}},
+	{Path: "/api/operations/undo", Methods: []string{http.MethodPost}},
+	{Path: "/api/search", Methods: []string{http.MethodGet}},
+	{Path: "/api/export", Methods: []string{http.MethodPost}},
+	{Path: "/api/import", Methods: []string{http.MethodPost}},
+	{Path: "/api/webhooks", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/webhooks/{id}", Methods: []string{http.MethodGet, http.MethodDelete}},
+	{Path: "/api/webhooks/{id}/deliveries", Methods: []string{http.MethodGet}},
+	{Path: "/api/api-keys", Methods: []string{http.MethodGet, http.MethodPost}},
+	{Path: "/api/api-keys/{id}/revoke", Methods: []string{http.MethodPost}},
+}
+
+// matchRouteTableRoute serves the route table in development mode only.
+// Production routers fall through to 404.
+func matchRouteTableRoute(api *API, w http.ResponseWriter, r *http.Request, _ ports.AuthContext, segments []string) bool {
+	if !api.exposeRouteTable || !isExactRoute(segments, "api", "_routes") {
+		return false
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return true
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"routes": routeTable})
+	return true
+}
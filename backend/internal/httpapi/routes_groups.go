@@ -10,12 +10,26 @@ import (
 func (a *API) handleGroups(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
 	switch r.Method {
 	case http.MethodGet:
+		query, paged, err := listQueryFor[domain.Group](r)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		if paged || len(query.Filters) > 0 {
+			page, pageErr := a.service.ListGroupsPage(r.Context(), authCtx, query)
+			if pageErr != nil {
+				a.writeServiceError(w, pageErr)
+				return
+			}
+			writeListPage(w, page, paged)
+			return
+		}
 		groups, err := a.service.ListGroups(r.Context(), authCtx)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, groups)
+		writeJSON(w, http.StatusOK, nonNilList(groups))
 	case http.MethodPost:
 		var input domain.Group
 		if err := decodeJSON(w, r, &input); err != nil {
@@ -24,7 +38,7 @@ func (a *API) handleGroups(w http.ResponseWriter, r *http.Request, authCtx ports
 		}
 		created, err := a.service.CreateGroup(r.Context(), authCtx, input)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
 		writeJSON(w, http.StatusCreated, created)
@@ -55,28 +69,86 @@ func (a *API) handleGroupByID(w http.ResponseWriter, r *http.Request, authCtx po
 		return
 	}
 
+	if len(segments) == 4 && isSubresourceRoute(segments, "allocations") {
+		a.listGroupAllocations(w, r, authCtx, groupID)
+		return
+	}
+
+	if len(segments) == 4 && isSubresourceRoute(segments, "capacity") {
+		a.groupCapacity(w, r, authCtx, groupID)
+		return
+	}
+
 	notFound(w)
 }
 
+// groupCapacity sums the capacity of a group's members per granularity
+// period between the from_date and to_date query dates.
+func (a *API) groupCapacity(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, groupID string) {
+	w = bodylessForHead(w, r)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, http.MethodGet, http.MethodHead)
+		return
+	}
+
+	release, ok := a.reports.tryAcquire()
+	if !ok {
+		writeReportsBusy(w)
+		return
+	}
+	defer release()
+
+	query := r.URL.Query()
+	capacity, err := a.service.GroupCapacity(r.Context(), authCtx, groupID, query.Get("from_date"), query.Get("to_date"), query.Get("granularity"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, capacity)
+}
+
+func (a *API) listGroupAllocations(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, groupID string) {
+	w = bodylessForHead(w, r)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, http.MethodGet, http.MethodHead)
+		return
+	}
+
+	resolveMembers, err := queryBool(r, "resolve_members")
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+
+	allocations, err := a.service.ListGroupAllocations(r.Context(), authCtx, groupID, resolveMembers)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(a.display.allocations(allocations)))
+}
+
 func (a *API) dispatchGroupByIDMethod(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, groupID string) {
+	w = bodylessForHead(w, r)
 	switch r.Method {
-	case http.MethodGet:
+	case http.MethodGet, http.MethodHead:
 		a.getGroupByID(w, r, authCtx, groupID)
 	case http.MethodPut:
 		a.updateGroupByID(w, r, authCtx, groupID)
 	case http.MethodDelete:
 		a.deleteGroupByID(w, r, authCtx, groupID)
 	default:
-		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+		methodNotAllowed(w, http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete)
 	}
 }
 
 func (a *API) getGroupByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, groupID string) {
 	group, err := a.service.GetGroup(r.Context(), authCtx, groupID)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
+	setVersionETag(w, group.Version)
 	writeJSON(w, http.StatusOK, group)
 }
 
@@ -86,18 +158,29 @@ func (a *API) updateGroupByID(w http.ResponseWriter, r *http.Request, authCtx po
 		writeDecodeError(w, err)
 		return
 	}
+	var err error
+	if input.Version, err = ifMatchVersion(r, input.Version); err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
 
 	updated, err := a.service.UpdateGroup(r.Context(), authCtx, groupID, input)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
+	setVersionETag(w, updated.Version)
 	writeJSON(w, http.StatusOK, updated)
 }
 
 func (a *API) deleteGroupByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, groupID string) {
-	if err := a.service.DeleteGroup(r.Context(), authCtx, groupID); err != nil {
-		writeServiceError(w, err)
+	cascade, err := queryBool(r, "cascade")
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	if err := a.service.DeleteGroup(r.Context(), authCtx, groupID, cascade); err != nil {
+		a.writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -130,7 +213,7 @@ func (a *API) addGroupMember(w http.ResponseWriter, r *http.Request, authCtx por
 
 	updated, err := a.service.AddGroupMember(r.Context(), authCtx, groupID, payload.PersonID)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, updated)
@@ -149,13 +232,17 @@ func (a *API) removeGroupMember(w http.ResponseWriter, r *http.Request, authCtx
 	}
 	updated, err := a.service.RemoveGroupMember(r.Context(), authCtx, groupID, personID)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, updated)
 }
 
 func (a *API) handleGroupUnavailabilityRoute(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, groupID string, segments []string) {
+	if isUnavailabilityRuleRoute(segments) {
+		a.handleUnavailabilityRuleRoute(w, r, authCtx, a.groupUnavailabilityRuleRoutes(groupID), segments)
+		return
+	}
 	switch len(segments) {
 	case 4:
 		a.dispatchGroupUnavailabilityMethod(w, r, authCtx, groupID)
@@ -184,7 +271,7 @@ func (a *API) dispatchGroupUnavailabilityMethod(w http.ResponseWriter, r *http.R
 func (a *API) listGroupUnavailability(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, groupID string) {
 	entries, err := a.service.ListGroupUnavailability(r.Context(), authCtx)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, filterGroupUnavailabilityByGroup(entries, groupID))
@@ -200,7 +287,7 @@ func (a *API) createGroupUnavailability(w http.ResponseWriter, r *http.Request,
 
 	created, err := a.service.CreateGroupUnavailability(r.Context(), authCtx, input)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusCreated, created)
@@ -213,7 +300,7 @@ func (a *API) deleteGroupUnavailabilityEntry(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	if err := a.service.DeleteGroupUnavailability(r.Context(), authCtx, entryID); err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,29 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"plato/backend/internal/ports"
+)
+
+func matchOperationsRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
+	if isExactRoute(segments, "api", "operations", "undo") {
+		api.handleUndoOperation(w, r, authCtx)
+		return true
+	}
+	return false
+}
+
+func (a *API) handleUndoOperation(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	undone, err := a.service.UndoLastOperation(r.Context(), authCtx)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, undone)
+}
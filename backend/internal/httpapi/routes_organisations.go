@@ -1,7 +1,10 @@
 package httpapi
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"plato/backend/internal/domain"
@@ -11,12 +14,12 @@ import (
 func (a *API) handleOrganisations(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
 	switch r.Method {
 	case http.MethodGet:
-		organisations, err := a.service.ListOrganisations(r.Context(), authCtx)
+		organisations, err := a.service.SearchOrganisations(r.Context(), authCtx, r.URL.Query().Get("q"))
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, organisations)
+		writeJSON(w, http.StatusOK, nonNilList(organisations))
 	case http.MethodPost:
 		var input domain.Organisation
 		if err := decodeJSON(w, r, &input); err != nil {
@@ -26,7 +29,7 @@ func (a *API) handleOrganisations(w http.ResponseWriter, r *http.Request, authCt
 
 		created, err := a.service.CreateOrganisation(r.Context(), authCtx, input)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
 		writeJSON(w, http.StatusCreated, created)
@@ -52,6 +55,11 @@ func (a *API) handleOrganisationByID(w http.ResponseWriter, r *http.Request, aut
 		return
 	}
 
+	if isSubresourceRoute(segments, "calendar") && len(segments) == 4 {
+		a.handleOrganisationCalendarRoute(w, r, authCtx, organisationID)
+		return
+	}
+
 	notFound(w)
 }
 
@@ -71,9 +79,10 @@ func (a *API) dispatchOrganisationByIDMethod(w http.ResponseWriter, r *http.Requ
 func (a *API) getOrganisationByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, organisationID string) {
 	organisation, err := a.service.GetOrganisation(r.Context(), authCtx, organisationID)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
+	setVersionETag(w, organisation.Version)
 	writeJSON(w, http.StatusOK, organisation)
 }
 
@@ -83,18 +92,24 @@ func (a *API) updateOrganisationByID(w http.ResponseWriter, r *http.Request, aut
 		writeDecodeError(w, err)
 		return
 	}
+	var err error
+	if input.Version, err = ifMatchVersion(r, input.Version); err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
 
 	updated, err := a.service.UpdateOrganisation(r.Context(), authCtx, organisationID, input)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
+	setVersionETag(w, updated.Version)
 	writeJSON(w, http.StatusOK, updated)
 }
 
 func (a *API) deleteOrganisationByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, organisationID string) {
 	if err := a.service.DeleteOrganisation(r.Context(), authCtx, organisationID); err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -102,7 +117,7 @@ func (a *API) deleteOrganisationByID(w http.ResponseWriter, r *http.Request, aut
 
 func (a *API) handleOrganisationHolidaysRoute(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, organisationID string, segments []string) {
 	if err := enforcePathTenant(authCtx, organisationID); err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 
@@ -110,6 +125,10 @@ func (a *API) handleOrganisationHolidaysRoute(w http.ResponseWriter, r *http.Req
 	case 4:
 		a.dispatchOrganisationHolidaysMethod(w, r, authCtx, organisationID)
 	case 5:
+		if segments[4] == holidayImportSegment {
+			a.importOrganisationHolidays(w, r, authCtx)
+			return
+		}
 		if r.Method != http.MethodDelete {
 			methodNotAllowed(w, http.MethodDelete)
 			return
@@ -132,12 +151,25 @@ func (a *API) dispatchOrganisationHolidaysMethod(w http.ResponseWriter, r *http.
 }
 
 func (a *API) listOrganisationHolidays(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if query := r.URL.Query(); query.Has("year") || query.Has("from") || query.Has("to") {
+		a.listOrganisationHolidaysInPeriod(w, r, authCtx, query.Get("year"), query.Get("from"), query.Get("to"))
+		return
+	}
 	holidays, err := a.service.ListOrgHolidays(r.Context(), authCtx)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(holidays))
+}
+
+func (a *API) listOrganisationHolidaysInPeriod(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, year, from, to string) {
+	holidays, err := a.service.ListOrgHolidaysInPeriod(r.Context(), authCtx, year, from, to)
+	if err != nil {
+		a.writeServiceError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, holidays)
+	writeJSON(w, http.StatusOK, nonNilList(holidays))
 }
 
 func (a *API) createOrganisationHoliday(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, organisationID string) {
@@ -150,12 +182,55 @@ func (a *API) createOrganisationHoliday(w http.ResponseWriter, r *http.Request,
 
 	created, err := a.service.CreateOrgHoliday(r.Context(), authCtx, input)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusCreated, created)
 }
 
+// holidayImportSegment names the bulk holiday import below an
+// organisation's holidays.
+const holidayImportSegment = "import"
+
+// importOrganisationHolidays creates the holidays of one year from the
+// national dataset named by the country and region query parameters, or from
+// an iCalendar file sent as the request body.
+func (a *API) importOrganisationHolidays(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	query := r.URL.Query()
+	input := domain.HolidayImportRequest{
+		Country: query.Get("country"),
+		Region:  query.Get("region"),
+	}
+	var err error
+	if input.Year, err = strconv.Atoi(strings.TrimSpace(query.Get("year"))); err != nil {
+		a.writeServiceError(w, fmt.Errorf("year must be a four digit year: %w", domain.ErrValidation))
+		return
+	}
+	if raw := strings.TrimSpace(query.Get("hours")); raw != "" {
+		if input.Hours, err = strconv.ParseFloat(raw, 64); err != nil {
+			a.writeServiceError(w, fmt.Errorf("hours must be a number: %w", domain.ErrValidation))
+			return
+		}
+	}
+	input.Calendar, err = io.ReadAll(http.MaxBytesReader(w, r.Body, maxJSONBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("request body too large (max %d bytes)", maxJSONBodyBytes))
+		return
+	}
+
+	result, err := a.service.ImportOrgHolidays(r.Context(), authCtx, input)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 func (a *API) deleteOrganisationHolidayByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) {
 	holidayID, ok := parseSubresourceID(segments)
 	if !ok {
@@ -163,12 +238,30 @@ func (a *API) deleteOrganisationHolidayByID(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	if err := a.service.DeleteOrgHoliday(r.Context(), authCtx, holidayID); err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (a *API) handleOrganisationCalendarRoute(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, organisationID string) {
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+	if err := enforcePathTenant(authCtx, organisationID); err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+
+	result, err := a.service.PurgeCalendarEntries(r.Context(), authCtx, r.URL.Query().Get("before"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 func enforcePathTenant(authCtx ports.AuthContext, organisationID string) error {
 	if strings.TrimSpace(authCtx.OrganisationID) == "" {
 		return nil
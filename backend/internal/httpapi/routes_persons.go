@@ -10,12 +10,26 @@ import (
 func (a *API) handlePersons(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
 	switch r.Method {
 	case http.MethodGet:
+		query, paged, err := listQueryFor[domain.Person](r)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		if paged || len(query.Filters) > 0 {
+			page, pageErr := a.service.ListPersonsPage(r.Context(), authCtx, query)
+			if pageErr != nil {
+				a.writeServiceError(w, pageErr)
+				return
+			}
+			writeListPage(w, page, paged)
+			return
+		}
 		persons, err := a.service.ListPersons(r.Context(), authCtx)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, persons)
+		writeJSON(w, http.StatusOK, nonNilList(persons))
 	case http.MethodPost:
 		var input domain.Person
 		if err := decodeJSON(w, r, &input); err != nil {
@@ -24,7 +38,7 @@ func (a *API) handlePersons(w http.ResponseWriter, r *http.Request, authCtx port
 		}
 		created, err := a.service.CreatePerson(r.Context(), authCtx, input)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
 		writeJSON(w, http.StatusCreated, created)
@@ -40,6 +54,10 @@ func (a *API) handlePersonByID(w http.ResponseWriter, r *http.Request, authCtx p
 		return
 	}
 
+	if len(segments) == 3 && personID == "batch-get" {
+		a.batchGetPersons(w, r, authCtx)
+		return
+	}
 	if len(segments) == 3 {
 		a.dispatchPersonByIDMethod(w, r, authCtx, personID)
 		return
@@ -49,29 +67,104 @@ func (a *API) handlePersonByID(w http.ResponseWriter, r *http.Request, authCtx p
 		a.handlePersonUnavailabilityRoute(w, r, authCtx, personID, segments)
 		return
 	}
+	if len(segments) == 4 && isSubresourceRoute(segments, "peak-overallocation") {
+		a.personPeakOverallocation(w, r, authCtx, personID)
+		return
+	}
+	if len(segments) == 4 && isSubresourceRoute(segments, "capacity") {
+		a.personCapacity(w, r, authCtx, personID)
+		return
+	}
 
 	notFound(w)
 }
 
+// personPeakOverallocation reports the worst overallocation window of one
+// person between the from and to query dates.
+func (a *API) personPeakOverallocation(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, personID string) {
+	w = bodylessForHead(w, r)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, http.MethodGet, http.MethodHead)
+		return
+	}
+
+	query := r.URL.Query()
+	peak, err := a.service.PersonPeakOverallocation(r.Context(), authCtx, personID, query.Get("from"), query.Get("to"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, peak)
+}
+
+// personCapacity breaks down the capacity of one person day by day between
+// the from_date and to_date query dates.
+func (a *API) personCapacity(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, personID string) {
+	w = bodylessForHead(w, r)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, http.MethodGet, http.MethodHead)
+		return
+	}
+
+	release, ok := a.reports.tryAcquire()
+	if !ok {
+		writeReportsBusy(w)
+		return
+	}
+	defer release()
+
+	query := r.URL.Query()
+	capacity, err := a.service.PersonCapacity(r.Context(), authCtx, personID, query.Get("from_date"), query.Get("to_date"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, capacity)
+}
+
+func (a *API) batchGetPersons(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var payload struct {
+		IDs []string `json:"ids"`
+	}
+	if err := decodeJSON(w, r, &payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	batch, err := a.service.GetPersonsByIDs(r.Context(), authCtx, payload.IDs)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, batch)
+}
+
 func (a *API) dispatchPersonByIDMethod(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, personID string) {
+	w = bodylessForHead(w, r)
 	switch r.Method {
-	case http.MethodGet:
+	case http.MethodGet, http.MethodHead:
 		a.getPersonByID(w, r, authCtx, personID)
 	case http.MethodPut:
 		a.updatePersonByID(w, r, authCtx, personID)
 	case http.MethodDelete:
 		a.deletePersonByID(w, r, authCtx, personID)
 	default:
-		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+		methodNotAllowed(w, http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete)
 	}
 }
 
 func (a *API) getPersonByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, personID string) {
 	person, err := a.service.GetPerson(r.Context(), authCtx, personID)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
+	setVersionETag(w, person.Version)
 	writeJSON(w, http.StatusOK, person)
 }
 
@@ -81,24 +174,39 @@ func (a *API) updatePersonByID(w http.ResponseWriter, r *http.Request, authCtx p
 		writeDecodeError(w, err)
 		return
 	}
+	var err error
+	if input.Version, err = ifMatchVersion(r, input.Version); err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
 
 	updated, err := a.service.UpdatePerson(r.Context(), authCtx, personID, input)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
+	setVersionETag(w, updated.Version)
 	writeJSON(w, http.StatusOK, updated)
 }
 
 func (a *API) deletePersonByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, personID string) {
-	if err := a.service.DeletePerson(r.Context(), authCtx, personID); err != nil {
-		writeServiceError(w, err)
+	cascade, err := queryBool(r, "cascade")
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	if err := a.service.DeletePerson(r.Context(), authCtx, personID, cascade); err != nil {
+		a.writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (a *API) handlePersonUnavailabilityRoute(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, personID string, segments []string) {
+	if isUnavailabilityRuleRoute(segments) {
+		a.handleUnavailabilityRuleRoute(w, r, authCtx, a.personUnavailabilityRuleRoutes(personID), segments)
+		return
+	}
 	switch len(segments) {
 	case 4:
 		a.dispatchPersonUnavailabilityMethod(w, r, authCtx, personID)
@@ -127,10 +235,10 @@ func (a *API) dispatchPersonUnavailabilityMethod(w http.ResponseWriter, r *http.
 func (a *API) listPersonUnavailability(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, personID string) {
 	entries, err := a.service.ListPersonUnavailabilityByPerson(r.Context(), authCtx, personID)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, entries)
+	writeJSON(w, http.StatusOK, nonNilList(entries))
 }
 
 func (a *API) createPersonUnavailability(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, personID string) {
@@ -143,7 +251,7 @@ func (a *API) createPersonUnavailability(w http.ResponseWriter, r *http.Request,
 
 	created, err := a.service.CreatePersonUnavailability(r.Context(), authCtx, input)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusCreated, created)
@@ -156,7 +264,7 @@ func (a *API) deletePersonUnavailabilityEntry(w http.ResponseWriter, r *http.Req
 		return
 	}
 	if err := a.service.DeletePersonUnavailabilityByPerson(r.Context(), authCtx, personID, entryID); err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
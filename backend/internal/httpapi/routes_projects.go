@@ -10,12 +10,31 @@ import (
 func (a *API) handleProjects(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
 	switch r.Method {
 	case http.MethodGet:
-		projects, err := a.service.ListProjects(r.Context(), authCtx)
+		includeArchived, err := queryBool(r, "include_archived")
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
-		writeJSON(w, http.StatusOK, projects)
+		query, paged, err := listQueryFor[domain.Project](r, "include_archived")
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		if paged || len(query.Filters) > 0 {
+			page, pageErr := a.service.ListProjectsPage(r.Context(), authCtx, includeArchived, query)
+			if pageErr != nil {
+				a.writeServiceError(w, pageErr)
+				return
+			}
+			writeListPage(w, page, paged)
+			return
+		}
+		projects, err := a.service.ListProjects(r.Context(), authCtx, includeArchived)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, nonNilList(projects))
 	case http.MethodPost:
 		var input domain.Project
 		if err := decodeJSON(w, r, &input); err != nil {
@@ -24,7 +43,7 @@ func (a *API) handleProjects(w http.ResponseWriter, r *http.Request, authCtx por
 		}
 		created, err := a.service.CreateProject(r.Context(), authCtx, input)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
 		writeJSON(w, http.StatusCreated, created)
@@ -35,13 +54,27 @@ func (a *API) handleProjects(w http.ResponseWriter, r *http.Request, authCtx por
 
 func (a *API) handleProjectByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) {
 	projectID := segments[2]
+	if len(segments) == 4 && isSubresourceRoute(segments, "reconcile-allocations") {
+		a.reconcileProjectAllocations(w, r, authCtx, projectID)
+		return
+	}
+	if len(segments) == 4 && isSubresourceRoute(segments, "restore") {
+		a.restoreProject(w, r, authCtx, projectID)
+		return
+	}
+	if len(segments) != 3 {
+		notFound(w)
+		return
+	}
+	w = bodylessForHead(w, r)
 	switch r.Method {
-	case http.MethodGet:
+	case http.MethodGet, http.MethodHead:
 		project, err := a.service.GetProject(r.Context(), authCtx, projectID)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
+		setVersionETag(w, project.Version)
 		writeJSON(w, http.StatusOK, project)
 	case http.MethodPut:
 		var input domain.Project
@@ -49,19 +82,63 @@ func (a *API) handleProjectByID(w http.ResponseWriter, r *http.Request, authCtx
 			writeDecodeError(w, err)
 			return
 		}
+		var err error
+		if input.Version, err = ifMatchVersion(r, input.Version); err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
 		updated, err := a.service.UpdateProject(r.Context(), authCtx, projectID, input)
 		if err != nil {
-			writeServiceError(w, err)
+			a.writeServiceError(w, err)
 			return
 		}
+		setVersionETag(w, updated.Version)
 		writeJSON(w, http.StatusOK, updated)
 	case http.MethodDelete:
-		if err := a.service.DeleteProject(r.Context(), authCtx, projectID); err != nil {
-			writeServiceError(w, err)
+		purge, err := queryBool(r, "purge")
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		cascade, err := queryBool(r, "cascade")
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		if err := a.service.DeleteProject(r.Context(), authCtx, projectID, purge, cascade); err != nil {
+			a.writeServiceError(w, err)
 			return
 		}
 		w.WriteHeader(http.StatusNoContent)
 	default:
-		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+		methodNotAllowed(w, http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (a *API) reconcileProjectAllocations(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, projectID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	result, err := a.service.ReconcileProjectAllocations(r.Context(), authCtx, projectID, r.URL.Query().Get("mode"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (a *API) restoreProject(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, projectID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	restored, err := a.service.RestoreProject(r.Context(), authCtx, projectID)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
 	}
+	writeJSON(w, http.StatusOK, restored)
 }
@@ -19,11 +19,93 @@ func (a *API) handleReportAvailabilityLoad(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	buckets, err := a.service.ReportAvailabilityAndLoad(r.Context(), authCtx, request)
+	release, ok := a.reports.tryAcquire()
+	if !ok {
+		writeReportsBusy(w)
+		return
+	}
+	defer release()
+
+	report, err := a.service.GenerateReport(r.Context(), authCtx, request)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+
+	body := map[string]any{"buckets": nonNilList(a.display.reportBuckets(report.Buckets, request.Unit))}
+	if request.TolerateMissing {
+		body["skipped_ids"] = nonNilList(report.SkippedIDs)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (a *API) handleReportWhatIf(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var request domain.WhatIfReportRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	release, ok := a.reports.tryAcquire()
+	if !ok {
+		writeReportsBusy(w)
+		return
+	}
+	defer release()
+
+	buckets, err := a.service.ReportWhatIf(r.Context(), authCtx, request)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"buckets": nonNilList(a.display.reportBuckets(buckets, request.Unit))})
+}
+
+func (a *API) handleReportDiff(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var request domain.ReportDiffRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	release, ok := a.reports.tryAcquire()
+	if !ok {
+		writeReportsBusy(w)
+		return
+	}
+	defer release()
+
+	buckets, err := a.service.ReportDiff(r.Context(), authCtx, request)
 	if err != nil {
-		writeServiceError(w, err)
+		a.writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"buckets": nonNilList(a.display.reportDiffBuckets(buckets, request.Baseline.Unit))})
+}
+
+func (a *API) handleReportUnallocated(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{"buckets": buckets})
+	query := r.URL.Query()
+	persons, err := a.service.ReportUnallocatedPersons(r.Context(), authCtx, query.Get("as_of"), query.Get("from"), query.Get("to"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(persons))
 }
@@ -0,0 +1,175 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+const scenariosResource = "scenarios"
+
+func matchScenariosRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
+	if isCollectionRoute(segments, scenariosResource) {
+		api.handleScenarios(w, r, authCtx)
+		return true
+	}
+	if isItemRoute(segments, scenariosResource) {
+		api.handleScenarioByID(w, r, authCtx, segments)
+		return true
+	}
+	return false
+}
+
+func (a *API) handleScenarios(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	switch r.Method {
+	case http.MethodGet:
+		scenarios, err := a.service.ListScenarios(r.Context(), authCtx)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, nonNilList(scenarios))
+	case http.MethodPost:
+		var input domain.Scenario
+		if err := decodeJSON(w, r, &input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		created, err := a.service.CreateScenario(r.Context(), authCtx, input)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (a *API) handleScenarioByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) {
+	scenarioID, ok := parseResourceID(segments)
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	if len(segments) == 3 {
+		a.dispatchScenarioByIDMethod(w, r, authCtx, scenarioID)
+		return
+	}
+
+	if len(segments) == 4 && isSubresourceRoute(segments, "apply") {
+		a.applyScenario(w, r, authCtx, scenarioID)
+		return
+	}
+
+	if len(segments) == 4 && isSubresourceRoute(segments, "allocations") {
+		a.handleScenarioAllocations(w, r, authCtx, scenarioID)
+		return
+	}
+
+	if len(segments) == 5 && isSubresourceRoute(segments, "allocations") {
+		a.handleScenarioAllocationByID(w, r, authCtx, scenarioID, segments[4])
+		return
+	}
+
+	notFound(w)
+}
+
+func (a *API) dispatchScenarioByIDMethod(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, scenarioID string) {
+	switch r.Method {
+	case http.MethodGet:
+		scenario, err := a.service.GetScenario(r.Context(), authCtx, scenarioID)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, scenario)
+	case http.MethodDelete:
+		if err := a.service.DeleteScenario(r.Context(), authCtx, scenarioID); err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+	}
+}
+
+func (a *API) handleScenarioAllocations(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, scenarioID string) {
+	switch r.Method {
+	case http.MethodGet:
+		scenario, err := a.service.GetScenario(r.Context(), authCtx, scenarioID)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, nonNilList(scenario.Allocations))
+	case http.MethodPost:
+		var input domain.Allocation
+		if err := decodeJSON(w, r, &input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		created, err := a.service.CreateScenarioAllocation(r.Context(), authCtx, scenarioID, input)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (a *API) handleScenarioAllocationByID(
+	w http.ResponseWriter,
+	r *http.Request,
+	authCtx ports.AuthContext,
+	scenarioID string,
+	allocationID string,
+) {
+	switch r.Method {
+	case http.MethodPut:
+		var input domain.Allocation
+		if err := decodeJSON(w, r, &input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		var err error
+		if input.Version, err = ifMatchVersion(r, input.Version); err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		updated, err := a.service.UpdateScenarioAllocation(r.Context(), authCtx, scenarioID, allocationID, input)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		setVersionETag(w, updated.Version)
+		writeJSON(w, http.StatusOK, updated)
+	case http.MethodDelete:
+		if err := a.service.DeleteScenarioAllocation(r.Context(), authCtx, scenarioID, allocationID); err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (a *API) applyScenario(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, scenarioID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	applied, err := a.service.ApplyScenario(r.Context(), authCtx, scenarioID)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, applied)
+}
@@ -0,0 +1,29 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"plato/backend/internal/ports"
+)
+
+func matchSearchRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
+	if isExactRoute(segments, "api", "search") {
+		api.handleSearch(w, r, authCtx)
+		return true
+	}
+	return false
+}
+
+func (a *API) handleSearch(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	results, err := a.service.Search(r.Context(), authCtx, r.URL.Query().Get("q"))
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(results))
+}
@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+const contentTypeZip = "application/zip"
+
+// snapshotFormat reads the format query flag. A missing flag selects JSON.
+func snapshotFormat(r *http.Request) string {
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		return domain.SnapshotFormatJSON
+	}
+	return format
+}
+
+// handleExport streams a snapshot of the caller's organisation as JSON, or as
+// a zip archive of CSV files when format is csv.
+func (a *API) handleExport(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	format := snapshotFormat(r)
+	body, err := a.service.ExportTenant(r.Context(), authCtx, format)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+
+	contentType, extension := contentTypeJSON, "json"
+	if format == domain.SnapshotFormatCSV {
+		contentType, extension = contentTypeZip, "zip"
+	}
+	w.Header().Set(headerContentType, contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "plato-export."+extension))
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(body); err != nil {
+		log.Printf("write export failed: err=%v", err)
+	}
+}
+
+// handleImport validates a tenant snapshot and loads it as a new organisation.
+// With dry_run set it only reports what would be imported.
+func (a *API) handleImport(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	dryRun, err := queryBool(r, "dry_run")
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("request body too large (max %d bytes)", maxImportBodyBytes))
+		return
+	}
+
+	result, err := a.service.ImportTenant(r.Context(), authCtx, raw, snapshotFormat(r), dryRun)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	switch {
+	case result.Imported:
+		writeJSON(w, http.StatusCreated, result)
+	case !result.Valid && !dryRun:
+		writeJSON(w, a.validationStatus, result)
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,121 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// recurringSegment names the rule collection below a person's or group's
+// unavailability, as in /api/persons/{id}/unavailability/recurring.
+const recurringSegment = "recurring"
+
+// unavailabilityRuleRoutes binds the rule handlers to the service methods of
+// one person or group.
+type unavailabilityRuleRoutes struct {
+	list   func(ctx context.Context, auth ports.AuthContext) ([]domain.UnavailabilityRule, error)
+	create func(ctx context.Context, auth ports.AuthContext, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error)
+	update func(ctx context.Context, auth ports.AuthContext, ruleID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error)
+	remove func(ctx context.Context, auth ports.AuthContext, ruleID string) error
+}
+
+func (a *API) personUnavailabilityRuleRoutes(personID string) unavailabilityRuleRoutes {
+	return unavailabilityRuleRoutes{
+		list: func(ctx context.Context, auth ports.AuthContext) ([]domain.UnavailabilityRule, error) {
+			return a.service.ListPersonUnavailabilityRules(ctx, auth, personID)
+		},
+		create: func(ctx context.Context, auth ports.AuthContext, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+			return a.service.CreatePersonUnavailabilityRule(ctx, auth, personID, input)
+		},
+		update: func(ctx context.Context, auth ports.AuthContext, ruleID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+			return a.service.UpdatePersonUnavailabilityRule(ctx, auth, personID, ruleID, input)
+		},
+		remove: func(ctx context.Context, auth ports.AuthContext, ruleID string) error {
+			return a.service.DeletePersonUnavailabilityRule(ctx, auth, personID, ruleID)
+		},
+	}
+}
+
+func (a *API) groupUnavailabilityRuleRoutes(groupID string) unavailabilityRuleRoutes {
+	return unavailabilityRuleRoutes{
+		list: func(ctx context.Context, auth ports.AuthContext) ([]domain.UnavailabilityRule, error) {
+			return a.service.ListGroupUnavailabilityRules(ctx, auth, groupID)
+		},
+		create: func(ctx context.Context, auth ports.AuthContext, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+			return a.service.CreateGroupUnavailabilityRule(ctx, auth, groupID, input)
+		},
+		update: func(ctx context.Context, auth ports.AuthContext, ruleID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+			return a.service.UpdateGroupUnavailabilityRule(ctx, auth, groupID, ruleID, input)
+		},
+		remove: func(ctx context.Context, auth ports.AuthContext, ruleID string) error {
+			return a.service.DeleteGroupUnavailabilityRule(ctx, auth, groupID, ruleID)
+		},
+	}
+}
+
+// isUnavailabilityRuleRoute reports whether segments address the recurring
+// rules of a person or group.
+func isUnavailabilityRuleRoute(segments []string) bool {
+	return len(segments) >= 5 && segments[4] == recurringSegment
+}
+
+func (a *API) handleUnavailabilityRuleRoute(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, routes unavailabilityRuleRoutes, segments []string) {
+	switch len(segments) {
+	case 5:
+		switch r.Method {
+		case http.MethodGet:
+			rules, err := routes.list(r.Context(), authCtx)
+			if err != nil {
+				a.writeServiceError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, nonNilList(rules))
+		case http.MethodPost:
+			var input domain.UnavailabilityRule
+			if err := decodeJSON(w, r, &input); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			created, err := routes.create(r.Context(), authCtx, input)
+			if err != nil {
+				a.writeServiceError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, created)
+		default:
+			methodNotAllowed(w, http.MethodGet, http.MethodPost)
+		}
+	case 6:
+		ruleID := segments[5]
+		if ruleID == "" {
+			notFound(w)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var input domain.UnavailabilityRule
+			if err := decodeJSON(w, r, &input); err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			updated, err := routes.update(r.Context(), authCtx, ruleID, input)
+			if err != nil {
+				a.writeServiceError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, updated)
+		case http.MethodDelete:
+			if err := routes.remove(r.Context(), authCtx, ruleID); err != nil {
+				a.writeServiceError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w, http.MethodPut, http.MethodDelete)
+		}
+	default:
+		notFound(w)
+	}
+}
@@ -0,0 +1,100 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+func matchWebhooksRoute(api *API, w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) bool {
+	if isCollectionRoute(segments, "webhooks") {
+		api.handleWebhooks(w, r, authCtx)
+		return true
+	}
+	if isItemRoute(segments, "webhooks") {
+		api.handleWebhookByID(w, r, authCtx, segments)
+		return true
+	}
+	return false
+}
+
+func (a *API) handleWebhooks(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext) {
+	switch r.Method {
+	case http.MethodGet:
+		webhooks, err := a.service.ListWebhooks(r.Context(), authCtx)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, nonNilList(webhooks))
+	case http.MethodPost:
+		var input domain.Webhook
+		if err := decodeJSON(w, r, &input); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		created, err := a.service.CreateWebhook(r.Context(), authCtx, input)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (a *API) handleWebhookByID(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, segments []string) {
+	webhookID, ok := parseResourceID(segments)
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	if len(segments) == 3 {
+		a.dispatchWebhookByIDMethod(w, r, authCtx, webhookID)
+		return
+	}
+
+	if len(segments) == 4 && isSubresourceRoute(segments, "deliveries") {
+		a.listWebhookDeliveries(w, r, authCtx, webhookID)
+		return
+	}
+
+	notFound(w)
+}
+
+func (a *API) dispatchWebhookByIDMethod(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, webhookID string) {
+	switch r.Method {
+	case http.MethodGet:
+		webhook, err := a.service.GetWebhook(r.Context(), authCtx, webhookID)
+		if err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, webhook)
+	case http.MethodDelete:
+		if err := a.service.DeleteWebhook(r.Context(), authCtx, webhookID); err != nil {
+			a.writeServiceError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+	}
+}
+
+func (a *API) listWebhookDeliveries(w http.ResponseWriter, r *http.Request, authCtx ports.AuthContext, webhookID string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	deliveries, err := a.service.ListWebhookDeliveries(r.Context(), authCtx, webhookID)
+	if err != nil {
+		a.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNilList(deliveries))
+}
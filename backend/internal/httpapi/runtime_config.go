@@ -5,12 +5,45 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
+
+	"plato/backend/internal/adapters/auth"
 )
 
 const (
 	envDevMode            = "DEV_MODE"
 	envProductionMode     = "PRODUCTION_MODE"
 	envCORSAllowedOrigins = "PLATO_CORS_ALLOWED_ORIGINS"
+	envValidationStatus   = "PLATO_VALIDATION_STATUS_422"
+	envSeedDemo           = "PLATO_SEED_DEMO"
+	envMaxPersonsPerOrg   = "PLATO_MAX_PERSONS_PER_ORG"
+	envMaxProjectsPerOrg  = "PLATO_MAX_PROJECTS_PER_ORG"
+	envHSTSMaxAge         = "PLATO_HSTS_MAX_AGE_SECONDS"
+	envStrictFields       = "PLATO_STRICT_FIELDS"
+	envPersistDebounce    = "PLATO_PERSIST_DEBOUNCE"
+	envPersistMaxDelay    = "PLATO_PERSIST_MAX_DELAY"
+	envRepositoryCacheTTL = "PLATO_REPOSITORY_CACHE_TTL"
+	envUniqueOrgNames     = "PLATO_UNIQUE_ORG_NAMES"
+	envWebhookPrivate     = "PLATO_WEBHOOK_ALLOW_PRIVATE"
+	envTelemetryFile      = "PLATO_TELEMETRY_FILE"
+	envTelemetryMaxBytes  = "PLATO_TELEMETRY_MAX_BYTES"
+	envMetricsEnabled     = "PLATO_METRICS_ENABLED"
+	envRequestLog         = "PLATO_REQUEST_LOG"
+	envFTEDecimals        = "PLATO_FTE_DECIMALS"
+	envPercentDecimals    = "PLATO_PERCENT_DECIMALS"
+	envMaxReports         = "PLATO_MAX_CONCURRENT_REPORTS"
+	envAuthProvider       = "PLATO_AUTH_PROVIDER"
+	envOIDCIssuer         = "PLATO_OIDC_ISSUER"
+	envOIDCAudience       = "PLATO_OIDC_AUDIENCE"
+	envOIDCOrgClaim       = "PLATO_OIDC_ORG_CLAIM"
+	envOIDCRolesClaim     = "PLATO_OIDC_ROLES_CLAIM"
+)
+
+// Auth providers selectable with PLATO_AUTH_PROVIDER.
+const (
+	AuthProviderDev  = "dev"
+	AuthProviderJWT  = "jwt"
+	AuthProviderOIDC = "oidc"
 )
 
 // RuntimeMode identifies the backend runtime mode.
@@ -23,11 +56,65 @@ const (
 	RuntimeModeProduction RuntimeMode = "production"
 )
 
-// RuntimeConfig captures runtime mode and CORS settings.
+// RuntimeConfig captures runtime mode, CORS, and error mapping settings.
 type RuntimeConfig struct {
 	Mode               RuntimeMode
 	CORSAllowedOrigins []string
 	AllowAnyCORSOrigin bool
+	// UnprocessableValidation maps semantic validation failures to 422 instead of 400.
+	UnprocessableValidation bool
+	// SeedDemo creates a demo tenant on startup when the store is empty.
+	// It is only honoured in development mode.
+	SeedDemo bool
+	// MaxPersonsPerOrganisation and MaxProjectsPerOrganisation cap tenant
+	// sizes. Zero keeps them unlimited.
+	MaxPersonsPerOrganisation  int
+	MaxProjectsPerOrganisation int
+	// HSTSMaxAgeSeconds enables Strict-Transport-Security in production mode
+	// when positive. Set it only when the backend is served over TLS.
+	HSTSMaxAgeSeconds int
+	// StrictFieldSelection answers 400 when the fields query parameter names
+	// an unknown field instead of ignoring it.
+	StrictFieldSelection bool
+	// PersistDebounce coalesces repository writes until no mutation arrived
+	// for this long. PersistMaxDelay bounds how long a change may stay
+	// unwritten. Zero debounce keeps every write synchronous.
+	PersistDebounce time.Duration
+	PersistMaxDelay time.Duration
+	// RepositoryCacheTTL keeps organisations and full per-organisation lists
+	// in memory for this long. Writes drop the cached kinds they change.
+	// Zero turns the cache off.
+	RepositoryCacheTTL time.Duration
+	// UniqueOrganisationNames rejects organisation names that another
+	// organisation already uses, ignoring case.
+	UniqueOrganisationNames bool
+	// AllowPrivateWebhooks lets webhooks target loopback, link-local, and
+	// private addresses.
+	AllowPrivateWebhooks bool
+	// TelemetryFile appends telemetry events as NDJSON lines to this path
+	// when set. TelemetryMaxBytes rotates the file once it would grow past
+	// this size. Zero never rotates.
+	TelemetryFile     string
+	TelemetryMaxBytes int64
+	// MetricsEnabled serves Prometheus metrics on /metrics without
+	// authentication. Keep the path reachable only by the scraper.
+	MetricsEnabled bool
+	// RequestLog writes one JSON line per request to stderr with the
+	// request ID, method, path, status, duration, organisation, and user.
+	RequestLog bool
+	// FTEPrecision and PercentPrecision round FTE report values and
+	// allocation percentages in responses. Stored and computed values stay
+	// exact. The zero value keeps full precision.
+	FTEPrecision     DisplayPrecision
+	PercentPrecision DisplayPrecision
+	// MaxConcurrentReports caps how many reports are generated at once.
+	// Requests over the cap get 503 with Retry-After. Zero keeps it unlimited.
+	MaxConcurrentReports int
+	// AuthProvider selects how requests authenticate: dev headers, HS256
+	// jwt tokens, or oidc tokens verified against OIDC.Issuer. Empty picks
+	// dev in development mode and jwt in production mode.
+	AuthProvider string
+	OIDC         auth.OIDCConfig
 }
 
 // IsDevelopment reports whether the runtime mode is development.
@@ -48,13 +135,146 @@ func DefaultListenAddr(mode RuntimeMode) string {
 	return ":8070"
 }
 
-// LoadRuntimeConfigFromEnv reads runtime mode and CORS settings from environment variables.
+// LoadRuntimeConfigFromEnv reads runtime mode, CORS, and error mapping settings from environment variables.
 func LoadRuntimeConfigFromEnv() (RuntimeConfig, error) {
 	mode, err := runtimeModeFromEnv()
 	if err != nil {
 		return RuntimeConfig{}, err
 	}
 
+	unprocessableValidation, _, err := parseOptionalBoolEnv(envValidationStatus)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+
+	strictFields, _, err := parseOptionalBoolEnv(envStrictFields)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+
+	uniqueOrgNames, _, err := parseOptionalBoolEnv(envUniqueOrgNames)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+
+	allowPrivateWebhooks, _, err := parseOptionalBoolEnv(envWebhookPrivate)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+
+	metricsEnabled, _, err := parseOptionalBoolEnv(envMetricsEnabled)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+
+	requestLog, _, err := parseOptionalBoolEnv(envRequestLog)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+
+	seedDemo, _, err := parseOptionalBoolEnv(envSeedDemo)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	if seedDemo && mode.IsProduction() {
+		return RuntimeConfig{}, fmt.Errorf("%s cannot be enabled in production mode", envSeedDemo)
+	}
+
+	config, err := corsRuntimeConfigFromEnv(mode)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.UnprocessableValidation = unprocessableValidation
+	config.SeedDemo = seedDemo
+	config.StrictFieldSelection = strictFields
+	config.UniqueOrganisationNames = uniqueOrgNames
+	config.AllowPrivateWebhooks = allowPrivateWebhooks
+	config.MetricsEnabled = metricsEnabled
+	config.RequestLog = requestLog
+
+	config.MaxPersonsPerOrganisation, err = parseOptionalLimitEnv(envMaxPersonsPerOrg)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.MaxProjectsPerOrganisation, err = parseOptionalLimitEnv(envMaxProjectsPerOrg)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.HSTSMaxAgeSeconds, err = parseOptionalLimitEnv(envHSTSMaxAge)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.MaxConcurrentReports, err = parseOptionalLimitEnv(envMaxReports)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.PersistDebounce, err = parseOptionalDurationEnv(envPersistDebounce)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.PersistMaxDelay, err = parseOptionalDurationEnv(envPersistMaxDelay)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	if config.PersistMaxDelay > 0 && config.PersistDebounce == 0 {
+		return RuntimeConfig{}, fmt.Errorf("%s requires %s", envPersistMaxDelay, envPersistDebounce)
+	}
+	config.RepositoryCacheTTL, err = parseOptionalDurationEnv(envRepositoryCacheTTL)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.TelemetryFile = strings.TrimSpace(os.Getenv(envTelemetryFile))
+	telemetryMaxBytes, err := parseOptionalLimitEnv(envTelemetryMaxBytes)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	if telemetryMaxBytes > 0 && config.TelemetryFile == "" {
+		return RuntimeConfig{}, fmt.Errorf("%s requires %s", envTelemetryMaxBytes, envTelemetryFile)
+	}
+	config.TelemetryMaxBytes = int64(telemetryMaxBytes)
+	config.FTEPrecision, err = parseOptionalDecimalsEnv(envFTEDecimals)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.PercentPrecision, err = parseOptionalDecimalsEnv(envPercentDecimals)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	config.AuthProvider, config.OIDC, err = authRuntimeConfigFromEnv(mode)
+	if err != nil {
+		return RuntimeConfig{}, err
+	}
+	return config, nil
+}
+
+func authRuntimeConfigFromEnv(mode RuntimeMode) (string, auth.OIDCConfig, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv(envAuthProvider)))
+	switch provider {
+	case "", AuthProviderJWT, AuthProviderOIDC:
+	case AuthProviderDev:
+		if mode.IsProduction() {
+			return "", auth.OIDCConfig{}, fmt.Errorf("%s=%s cannot be used in production mode", envAuthProvider, AuthProviderDev)
+		}
+	default:
+		return "", auth.OIDCConfig{}, fmt.Errorf("%s must be %s, %s, or %s", envAuthProvider, AuthProviderDev, AuthProviderJWT, AuthProviderOIDC)
+	}
+
+	oidc := auth.OIDCConfig{
+		Issuer:            strings.TrimSpace(os.Getenv(envOIDCIssuer)),
+		Audience:          strings.TrimSpace(os.Getenv(envOIDCAudience)),
+		OrganisationClaim: strings.TrimSpace(os.Getenv(envOIDCOrgClaim)),
+		RolesClaim:        strings.TrimSpace(os.Getenv(envOIDCRolesClaim)),
+	}
+	if provider == AuthProviderOIDC && oidc.Issuer == "" {
+		return "", auth.OIDCConfig{}, fmt.Errorf("%s=%s requires %s", envAuthProvider, AuthProviderOIDC, envOIDCIssuer)
+	}
+	if provider != AuthProviderOIDC && oidc != (auth.OIDCConfig{}) {
+		return "", auth.OIDCConfig{}, fmt.Errorf("%s requires %s=%s", envOIDCIssuer, envAuthProvider, AuthProviderOIDC)
+	}
+	return provider, oidc, nil
+}
+
+func corsRuntimeConfigFromEnv(mode RuntimeMode) (RuntimeConfig, error) {
 	allowedOrigins := parseCSV(os.Getenv(envCORSAllowedOrigins))
 	if mode.IsProduction() {
 		for _, origin := range allowedOrigins {
@@ -129,6 +349,55 @@ func parseOptionalBoolEnv(key string) (value bool, set bool, err error) {
 	return parsedValue, true, nil
 }
 
+// parseOptionalLimitEnv reads a non-negative count where unset or zero means unlimited.
+func parseOptionalLimitEnv(key string) (int, error) {
+	rawValue := strings.TrimSpace(os.Getenv(key))
+	if rawValue == "" {
+		return 0, nil
+	}
+	parsedValue, err := strconv.Atoi(rawValue)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %w", key, err)
+	}
+	if parsedValue < 0 {
+		return 0, fmt.Errorf("%s must not be negative", key)
+	}
+	return parsedValue, nil
+}
+
+// parseOptionalDurationEnv reads a non-negative Go duration where unset means zero.
+func parseOptionalDurationEnv(key string) (time.Duration, error) {
+	rawValue := strings.TrimSpace(os.Getenv(key))
+	if rawValue == "" {
+		return 0, nil
+	}
+	parsedValue, err := time.ParseDuration(rawValue)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration: %w", key, err)
+	}
+	if parsedValue < 0 {
+		return 0, fmt.Errorf("%s must not be negative", key)
+	}
+	return parsedValue, nil
+}
+
+// parseOptionalDecimalsEnv reads a display precision in decimal places where
+// unset keeps values unrounded.
+func parseOptionalDecimalsEnv(key string) (DisplayPrecision, error) {
+	rawValue := strings.TrimSpace(os.Getenv(key))
+	if rawValue == "" {
+		return DisplayPrecision{}, nil
+	}
+	parsedValue, err := strconv.Atoi(rawValue)
+	if err != nil {
+		return DisplayPrecision{}, fmt.Errorf("%s must be an integer: %w", key, err)
+	}
+	if parsedValue < 0 || parsedValue > maxDisplayDecimals {
+		return DisplayPrecision{}, fmt.Errorf("%s must be between 0 and %d", key, maxDisplayDecimals)
+	}
+	return DisplayPrecision{Enabled: true, Decimals: parsedValue}, nil
+}
+
 func parseCSV(rawValue string) []string {
 	parts := strings.Split(rawValue, ",")
 	values := make([]string, 0, len(parts))
@@ -3,6 +3,9 @@ package httpapi
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"plato/backend/internal/adapters/auth"
 )
 
 const errLoadRuntimeConfigFmt = "load runtime config: %v"
@@ -93,6 +96,463 @@ func TestLoadRuntimeConfigFromEnvRejectsInvalidBooleanValues(t *testing.T) {
 	}
 }
 
+// TestLoadRuntimeConfigFromEnvParsesValidationStatusFlag verifies the load runtime config from env parses validation status flag scenario.
+func TestLoadRuntimeConfigFromEnvParsesValidationStatusFlag(t *testing.T) {
+	t.Setenv(envDevMode, "")
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+
+	t.Setenv(envValidationStatus, "")
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf("load runtime config: %v", err)
+	}
+	if config.UnprocessableValidation {
+		t.Fatal("expected 422 validation status to be disabled by default")
+	}
+
+	t.Setenv(envValidationStatus, envBoolTrue)
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf("load runtime config: %v", err)
+	}
+	if !config.UnprocessableValidation {
+		t.Fatal("expected 422 validation status to be enabled")
+	}
+
+	t.Setenv(envValidationStatus, "maybe")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected boolean parse error for validation status flag")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvSeedDemoFlag verifies the load runtime config from env seed demo flag scenario.
+func TestLoadRuntimeConfigFromEnvSeedDemoFlag(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envSeedDemo, envBoolTrue)
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if !config.SeedDemo {
+		t.Fatal("expected demo seeding to be enabled in development mode")
+	}
+
+	t.Setenv(envDevMode, "")
+	t.Setenv(envProductionMode, envBoolTrue)
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected demo seeding to be rejected in production mode")
+	}
+
+	t.Setenv(envSeedDemo, "sometimes")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected boolean parse error for demo seed flag")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesTenantQuotas verifies the load runtime config from env parses tenant quotas scenario.
+func TestLoadRuntimeConfigFromEnvParsesTenantQuotas(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envMaxPersonsPerOrg, "")
+	t.Setenv(envMaxProjectsPerOrg, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.MaxPersonsPerOrganisation != 0 || config.MaxProjectsPerOrganisation != 0 {
+		t.Fatalf("expected unlimited quotas by default, got %+v", config)
+	}
+
+	t.Setenv(envMaxPersonsPerOrg, " 25 ")
+	t.Setenv(envMaxProjectsPerOrg, "10")
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.MaxPersonsPerOrganisation != 25 || config.MaxProjectsPerOrganisation != 10 {
+		t.Fatalf("unexpected quotas: %+v", config)
+	}
+
+	t.Setenv(envMaxProjectsPerOrg, "-1")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected negative project quota to be rejected")
+	}
+	t.Setenv(envMaxProjectsPerOrg, "")
+	t.Setenv(envMaxPersonsPerOrg, "many")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected non-numeric person quota to be rejected")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesHSTSMaxAge verifies the load runtime config from env parses HSTS max age scenario.
+func TestLoadRuntimeConfigFromEnvParsesHSTSMaxAge(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envHSTSMaxAge, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.HSTSMaxAgeSeconds != 0 {
+		t.Fatalf("expected HSTS to be disabled by default, got %d", config.HSTSMaxAgeSeconds)
+	}
+
+	t.Setenv(envHSTSMaxAge, "86400")
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.HSTSMaxAgeSeconds != 86400 {
+		t.Fatalf("expected HSTS max age 86400, got %d", config.HSTSMaxAgeSeconds)
+	}
+
+	t.Setenv(envHSTSMaxAge, "-5")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected negative HSTS max age to be rejected")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesStrictFields verifies the load runtime config from env parses strict fields scenario.
+func TestLoadRuntimeConfigFromEnvParsesStrictFields(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envStrictFields, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.StrictFieldSelection {
+		t.Fatal("expected unknown fields to be ignored by default")
+	}
+
+	t.Setenv(envStrictFields, envBoolTrue)
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if !config.StrictFieldSelection {
+		t.Fatal("expected strict field selection to be enabled")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesUniqueOrgNames verifies the load runtime config from env parses unique org names scenario.
+func TestLoadRuntimeConfigFromEnvParsesUniqueOrgNames(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envUniqueOrgNames, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.UniqueOrganisationNames {
+		t.Fatal("expected duplicate organisation names to be allowed by default")
+	}
+
+	t.Setenv(envUniqueOrgNames, envBoolTrue)
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if !config.UniqueOrganisationNames {
+		t.Fatal("expected unique organisation names to be enabled")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesWebhookPrivate verifies the load runtime config from env parses webhook private scenario.
+func TestLoadRuntimeConfigFromEnvParsesWebhookPrivate(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envWebhookPrivate, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.AllowPrivateWebhooks {
+		t.Fatal("expected private webhook targets to be refused by default")
+	}
+
+	t.Setenv(envWebhookPrivate, envBoolTrue)
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if !config.AllowPrivateWebhooks {
+		t.Fatal("expected private webhook targets to be allowed")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesMetricsEnabled verifies the load runtime config from env parses metrics enabled scenario.
+func TestLoadRuntimeConfigFromEnvParsesMetricsEnabled(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envMetricsEnabled, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.MetricsEnabled {
+		t.Fatal("expected metrics to be disabled by default")
+	}
+
+	t.Setenv(envMetricsEnabled, envBoolTrue)
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if !config.MetricsEnabled {
+		t.Fatal("expected metrics to be enabled")
+	}
+
+	t.Setenv(envMetricsEnabled, "sometimes")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected invalid metrics flag to fail")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesRequestLog verifies the load runtime config from env parses request log scenario.
+func TestLoadRuntimeConfigFromEnvParsesRequestLog(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envRequestLog, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.RequestLog {
+		t.Fatal("expected request logging to be disabled by default")
+	}
+
+	t.Setenv(envRequestLog, envBoolTrue)
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if !config.RequestLog {
+		t.Fatal("expected request logging to be enabled")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesPersistDebounce verifies the load runtime config from env parses persist debounce scenario.
+func TestLoadRuntimeConfigFromEnvParsesPersistDebounce(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envPersistDebounce, "")
+	t.Setenv(envPersistMaxDelay, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.PersistDebounce != 0 || config.PersistMaxDelay != 0 {
+		t.Fatalf("expected synchronous saves by default, got %+v", config)
+	}
+
+	t.Setenv(envPersistDebounce, "250ms")
+	t.Setenv(envPersistMaxDelay, "2s")
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.PersistDebounce != 250*time.Millisecond || config.PersistMaxDelay != 2*time.Second {
+		t.Fatalf("expected 250ms debounce and 2s max delay, got %s and %s", config.PersistDebounce, config.PersistMaxDelay)
+	}
+
+	t.Setenv(envPersistDebounce, "soon")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected invalid debounce duration to be rejected")
+	}
+
+	t.Setenv(envPersistDebounce, "-1s")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected negative debounce duration to be rejected")
+	}
+
+	t.Setenv(envPersistDebounce, "")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected max delay without debounce to be rejected")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesRepositoryCacheTTL verifies the load runtime config from env parses repository cache TTL scenario.
+func TestLoadRuntimeConfigFromEnvParsesRepositoryCacheTTL(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envRepositoryCacheTTL, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.RepositoryCacheTTL != 0 {
+		t.Fatalf("expected the repository cache to be off by default, got %s", config.RepositoryCacheTTL)
+	}
+
+	t.Setenv(envRepositoryCacheTTL, "30s")
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.RepositoryCacheTTL != 30*time.Second {
+		t.Fatalf("expected a 30s cache TTL, got %s", config.RepositoryCacheTTL)
+	}
+
+	t.Setenv(envRepositoryCacheTTL, "-1s")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected negative cache TTL to be rejected")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesTelemetryFile verifies the load runtime config from env parses telemetry file scenario.
+func TestLoadRuntimeConfigFromEnvParsesTelemetryFile(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envTelemetryFile, "")
+	t.Setenv(envTelemetryMaxBytes, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.TelemetryFile != "" || config.TelemetryMaxBytes != 0 {
+		t.Fatalf("expected telemetry file to be off by default, got %+v", config)
+	}
+
+	t.Setenv(envTelemetryFile, " /var/log/plato/events.ndjson ")
+	t.Setenv(envTelemetryMaxBytes, "1048576")
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.TelemetryFile != "/var/log/plato/events.ndjson" || config.TelemetryMaxBytes != 1048576 {
+		t.Fatalf("expected telemetry file with 1 MiB rotation, got %q and %d", config.TelemetryFile, config.TelemetryMaxBytes)
+	}
+
+	t.Setenv(envTelemetryMaxBytes, "-1")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected negative telemetry max bytes to be rejected")
+	}
+
+	t.Setenv(envTelemetryFile, "")
+	t.Setenv(envTelemetryMaxBytes, "1024")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected telemetry max bytes without a file to be rejected")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesDisplayPrecision verifies the load runtime config from env parses display precision scenario.
+func TestLoadRuntimeConfigFromEnvParsesDisplayPrecision(t *testing.T) {
+	t.Setenv(envDevMode, envBoolTrue)
+	t.Setenv(envProductionMode, "")
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envFTEDecimals, "")
+	t.Setenv(envPercentDecimals, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.FTEPrecision.Enabled || config.PercentPrecision.Enabled {
+		t.Fatalf("expected display rounding to be off by default, got %+v", config)
+	}
+
+	t.Setenv(envFTEDecimals, "1")
+	t.Setenv(envPercentDecimals, "0")
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.FTEPrecision != (DisplayPrecision{Enabled: true, Decimals: 1}) {
+		t.Fatalf("expected one FTE decimal, got %+v", config.FTEPrecision)
+	}
+	if config.PercentPrecision != (DisplayPrecision{Enabled: true, Decimals: 0}) {
+		t.Fatalf("expected whole percentages, got %+v", config.PercentPrecision)
+	}
+
+	t.Setenv(envFTEDecimals, "7")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected FTE decimals above the maximum to be rejected")
+	}
+
+	t.Setenv(envFTEDecimals, "")
+	t.Setenv(envPercentDecimals, "two")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected non-numeric percent decimals to be rejected")
+	}
+}
+
+// TestLoadRuntimeConfigFromEnvParsesAuthProvider verifies the load runtime config from env parses auth provider scenario.
+func TestLoadRuntimeConfigFromEnvParsesAuthProvider(t *testing.T) {
+	t.Setenv(envDevMode, "")
+	t.Setenv(envProductionMode, envBoolTrue)
+	t.Setenv(envCORSAllowedOrigins, "")
+	t.Setenv(envAuthProvider, "")
+	t.Setenv(envOIDCIssuer, "")
+	t.Setenv(envOIDCAudience, "")
+	t.Setenv(envOIDCOrgClaim, "")
+	t.Setenv(envOIDCRolesClaim, "")
+
+	config, err := LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	if config.AuthProvider != "" || config.OIDC != (auth.OIDCConfig{}) {
+		t.Fatalf("expected the mode default auth provider, got %+v", config)
+	}
+
+	t.Setenv(envAuthProvider, " OIDC ")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected oidc without an issuer to be rejected")
+	}
+	t.Setenv(envOIDCIssuer, " https://keycloak.local/realms/plato ")
+	t.Setenv(envOIDCAudience, "plato")
+	t.Setenv(envOIDCRolesClaim, "realm_access.roles")
+	config, err = LoadRuntimeConfigFromEnv()
+	if err != nil {
+		t.Fatalf(errLoadRuntimeConfigFmt, err)
+	}
+	expected := auth.OIDCConfig{Issuer: "https://keycloak.local/realms/plato", Audience: "plato", RolesClaim: "realm_access.roles"}
+	if config.AuthProvider != AuthProviderOIDC || config.OIDC != expected {
+		t.Fatalf("expected oidc settings, got %q and %+v", config.AuthProvider, config.OIDC)
+	}
+
+	t.Setenv(envAuthProvider, AuthProviderJWT)
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected oidc settings without the oidc provider to be rejected")
+	}
+	t.Setenv(envOIDCIssuer, "")
+	t.Setenv(envOIDCAudience, "")
+	t.Setenv(envOIDCRolesClaim, "")
+	t.Setenv(envAuthProvider, AuthProviderDev)
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected dev auth to be rejected in production mode")
+	}
+	t.Setenv(envAuthProvider, "saml")
+	if _, err = LoadRuntimeConfigFromEnv(); err == nil {
+		t.Fatal("expected an unknown auth provider to be rejected")
+	}
+}
+
 // TestLoadRuntimeConfigFromEnvRejectsConflictingModeBooleans verifies the load runtime config from env rejects conflicting mode booleans scenario.
 func TestLoadRuntimeConfigFromEnvRejectsConflictingModeBooleans(t *testing.T) {
 	t.Setenv(envDevMode, envBoolTrue)
@@ -35,10 +35,60 @@ type Telemetry interface {
 	Record(name string, attributes map[string]string)
 }
 
+// EventPublisher hands stored changes to webhook delivery. Publish must not
+// block the caller.
+type EventPublisher interface {
+	Publish(event domain.Event)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context that carries the ID correlating one HTTP
+// request with the telemetry events and logs it causes.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID, or an
+// empty string when there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+type allocationAuditKey struct{}
+
+// WithAllocationAudit returns a context that names the actor and time of the
+// allocation events stored for writes made under it. Repositories store an
+// AllocationEvent for every allocation they create, update, restore, import,
+// or delete, including those removed together with a person, group, or
+// project, in the same write as the change. Writes without an audit are
+// recorded without an actor. Deleting an organisation removes its events.
+func WithAllocationAudit(ctx context.Context, audit domain.AllocationAudit) context.Context {
+	return context.WithValue(ctx, allocationAuditKey{}, audit)
+}
+
+// AllocationAuditFromContext returns the audit stored by
+// WithAllocationAudit and whether there is one.
+func AllocationAuditFromContext(ctx context.Context) (domain.AllocationAudit, bool) {
+	audit, ok := ctx.Value(allocationAuditKey{}).(domain.AllocationAudit)
+	return audit, ok
+}
+
 // ImportExport defines import and export operations.
 type ImportExport interface {
-	Import(ctx context.Context, raw []byte) error
-	Export(ctx context.Context) ([]byte, error)
+	// EncodeTenantSnapshot renders a snapshot in one of the domain snapshot
+	// formats.
+	EncodeTenantSnapshot(snapshot domain.TenantSnapshot, format string) ([]byte, error)
+	// DecodeTenantSnapshot parses a snapshot written by EncodeTenantSnapshot.
+	DecodeTenantSnapshot(raw []byte, format string) (domain.TenantSnapshot, error)
+	DecodeAllocationImport(raw []byte) ([]domain.AllocationImportRow, error)
+	// DecodeHolidayCalendar reads the all-day events of an iCalendar file
+	// that fall in year.
+	DecodeHolidayCalendar(raw []byte, year int) ([]domain.HolidayImportEntry, error)
+	// NationalHolidays returns the public holidays of a country, and of one
+	// of its regions when region is set, in year.
+	NationalHolidays(country, region string, year int) ([]domain.HolidayImportEntry, error)
 }
 
 // Repository defines the persistence operations used by the service layer.
@@ -48,33 +98,99 @@ type Repository interface {
 	CreateOrganisation(ctx context.Context, organisation domain.Organisation) (domain.Organisation, error)
 	UpdateOrganisation(ctx context.Context, organisation domain.Organisation) (domain.Organisation, error)
 	DeleteOrganisation(ctx context.Context, id string) error
+	// StateVersion returns a counter that grows with every successful write.
+	StateVersion(ctx context.Context) uint64
 
 	ListPersons(ctx context.Context, organisationID string) ([]domain.Person, error)
+	// ListPersonsPage returns one filtered and sorted page of persons.
+	ListPersonsPage(ctx context.Context, organisationID string, query domain.ListQuery) (domain.ListPage[domain.Person], error)
 	GetPerson(ctx context.Context, organisationID, id string) (domain.Person, error)
 	CreatePerson(ctx context.Context, person domain.Person) (domain.Person, error)
+	// CreatePersonWithLimit stores a person unless the organisation already
+	// holds maxPersons persons, checked in the same write. It returns a
+	// domain.QuotaError at the limit. Zero or less means no limit.
+	CreatePersonWithLimit(ctx context.Context, person domain.Person, maxPersons int) (domain.Person, error)
 	UpdatePerson(ctx context.Context, person domain.Person) (domain.Person, error)
-	DeletePerson(ctx context.Context, organisationID, id string) error
+	// UpdatePersonEndingAllocations stores a person and, in the same write,
+	// ends the person's own allocations still running on cutoff the day
+	// before with endReason and deletes those starting on or after it. The
+	// allocations are picked inside the write, so none created meanwhile is
+	// missed. It returns the ended allocations and the deleted IDs. Group
+	// allocations are left alone, and nothing is stored when any change fails.
+	UpdatePersonEndingAllocations(
+		ctx context.Context,
+		person domain.Person,
+		cutoff string,
+		endReason string,
+	) (domain.Person, []domain.Allocation, []string, error)
+	// DeletePerson removes a person with its group memberships and targeted
+	// holidays. Without cascade it fails with a domain.DependentsError while
+	// allocations, unavailability entries, or rules still target the person,
+	// and with cascade it deletes them in the same write.
+	DeletePerson(ctx context.Context, organisationID, id string, cascade bool) error
 
 	ListProjects(ctx context.Context, organisationID string) ([]domain.Project, error)
+	ListProjectsPage(ctx context.Context, organisationID string, query domain.ListQuery) (domain.ListPage[domain.Project], error)
 	GetProject(ctx context.Context, organisationID, id string) (domain.Project, error)
 	CreateProject(ctx context.Context, project domain.Project) (domain.Project, error)
+	// CreateProjectWithLimit stores a project unless the organisation already
+	// holds maxProjects projects, checked in the same write. It returns a
+	// domain.QuotaError at the limit. Zero or less means no limit.
+	CreateProjectWithLimit(ctx context.Context, project domain.Project, maxProjects int) (domain.Project, error)
 	UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error)
-	DeleteProject(ctx context.Context, organisationID, id string) error
+	// DeleteProject removes a project. Without cascade it fails with a
+	// domain.DependentsError while allocations are booked on it, and with
+	// cascade it deletes them in the same write.
+	DeleteProject(ctx context.Context, organisationID, id string, cascade bool) error
 
 	ListGroups(ctx context.Context, organisationID string) ([]domain.Group, error)
+	ListGroupsPage(ctx context.Context, organisationID string, query domain.ListQuery) (domain.ListPage[domain.Group], error)
 	GetGroup(ctx context.Context, organisationID, id string) (domain.Group, error)
 	CreateGroup(ctx context.Context, group domain.Group) (domain.Group, error)
 	UpdateGroup(ctx context.Context, group domain.Group) (domain.Group, error)
-	DeleteGroup(ctx context.Context, organisationID, id string) error
+	// DeleteGroup removes a group and its sub-group links. Without cascade it
+	// fails with a domain.DependentsError while allocations, unavailability
+	// entries, or rules still target the group, and with cascade it deletes
+	// them in the same write.
+	DeleteGroup(ctx context.Context, organisationID, id string, cascade bool) error
 
 	ListAllocations(ctx context.Context, organisationID string) ([]domain.Allocation, error)
+	ListAllocationsPage(ctx context.Context, organisationID string, query domain.ListQuery) (domain.ListPage[domain.Allocation], error)
 	GetAllocation(ctx context.Context, organisationID, id string) (domain.Allocation, error)
 	CreateAllocation(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error)
+	// CreateAllocations stores all allocations in one write or none of them.
+	CreateAllocations(ctx context.Context, organisationID string, allocations []domain.Allocation) ([]domain.Allocation, error)
 	UpdateAllocation(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error)
+	UpdateAllocations(ctx context.Context, organisationID string, allocations []domain.Allocation) ([]domain.Allocation, error)
 	DeleteAllocation(ctx context.Context, organisationID, id string) error
+	// RestoreAllocation stores a deleted allocation again under its old ID
+	// with the next version. It returns ErrConflict when the ID is in use.
+	RestoreAllocation(ctx context.Context, allocation domain.Allocation) (domain.Allocation, error)
+	// ListAllocationEvents returns the organisation's allocation events,
+	// oldest first.
+	ListAllocationEvents(ctx context.Context, organisationID string) ([]domain.AllocationEvent, error)
+
+	ListAPIKeys(ctx context.Context, organisationID string) ([]domain.APIKey, error)
+	// GetAPIKeyByHash looks a key up across organisations.
+	GetAPIKeyByHash(ctx context.Context, hash string) (domain.APIKey, error)
+	CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, organisationID, id string) (domain.APIKey, error)
+
+	ListWebhooks(ctx context.Context, organisationID string) ([]domain.Webhook, error)
+	GetWebhook(ctx context.Context, organisationID, id string) (domain.Webhook, error)
+	CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	// DeleteWebhook removes a webhook together with its delivery log.
+	DeleteWebhook(ctx context.Context, organisationID, id string) error
+	// AppendWebhookDelivery stores the outcome of one delivery. Implementations
+	// may drop old outcomes to bound the log.
+	AppendWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	ListWebhookDeliveries(ctx context.Context, organisationID, webhookID string) ([]domain.WebhookDelivery, error)
 
 	ListOrgHolidays(ctx context.Context, organisationID string) ([]domain.OrgHoliday, error)
 	CreateOrgHoliday(ctx context.Context, entry domain.OrgHoliday) (domain.OrgHoliday, error)
+	// CreateOrgHolidays stores several holidays of one organisation in a
+	// single write.
+	CreateOrgHolidays(ctx context.Context, organisationID string, entries []domain.OrgHoliday) ([]domain.OrgHoliday, error)
 	DeleteOrgHoliday(ctx context.Context, organisationID, id string) error
 
 	ListGroupUnavailability(ctx context.Context, organisationID string) ([]domain.GroupUnavailability, error)
@@ -88,4 +204,35 @@ type Repository interface {
 	CreatePersonUnavailabilityWithDailyLimit(ctx context.Context, entry domain.PersonUnavailability, maxHours float64) (domain.PersonUnavailability, error)
 	DeletePersonUnavailability(ctx context.Context, organisationID, id string) error
 	DeletePersonUnavailabilityByPerson(ctx context.Context, organisationID, personID, id string) error
+
+	ListUnavailabilityRules(ctx context.Context, organisationID string) ([]domain.UnavailabilityRule, error)
+	GetUnavailabilityRule(ctx context.Context, organisationID, id string) (domain.UnavailabilityRule, error)
+	CreateUnavailabilityRule(ctx context.Context, rule domain.UnavailabilityRule) (domain.UnavailabilityRule, error)
+	// UpdateUnavailabilityRule keeps the stored person or group target.
+	UpdateUnavailabilityRule(ctx context.Context, rule domain.UnavailabilityRule) (domain.UnavailabilityRule, error)
+	DeleteUnavailabilityRule(ctx context.Context, organisationID, id string) error
+
+	ListAllocationTemplates(ctx context.Context, organisationID string) ([]domain.AllocationTemplate, error)
+	GetAllocationTemplate(ctx context.Context, organisationID, id string) (domain.AllocationTemplate, error)
+	CreateAllocationTemplate(ctx context.Context, template domain.AllocationTemplate) (domain.AllocationTemplate, error)
+	DeleteAllocationTemplate(ctx context.Context, organisationID, id string) error
+
+	ListScenarios(ctx context.Context, organisationID string) ([]domain.Scenario, error)
+	GetScenario(ctx context.Context, organisationID, id string) (domain.Scenario, error)
+	CreateScenario(ctx context.Context, scenario domain.Scenario) (domain.Scenario, error)
+	// UpdateScenario keeps the stored base versions and rejects applied
+	// scenarios with ErrConflict.
+	UpdateScenario(ctx context.Context, scenario domain.Scenario) (domain.Scenario, error)
+	DeleteScenario(ctx context.Context, organisationID, id string) error
+	// ApplyScenario writes the scenario's changes to the live allocations and
+	// marks it applied in one write. It returns ErrConflict when the scenario
+	// or any live allocation it changes moved on since it was read or cloned.
+	ApplyScenario(ctx context.Context, scenario domain.Scenario) (domain.ScenarioChanges, error)
+
+	PurgeCalendarEntriesBefore(ctx context.Context, organisationID, cutoffDate string) (domain.CalendarPurgeResult, error)
+
+	// ImportTenant stores a snapshot as a new organisation in one write. Every
+	// record gets a new ID and references between records are rewritten to
+	// match. Nothing is stored when any reference is unknown.
+	ImportTenant(ctx context.Context, snapshot domain.TenantSnapshot) (domain.TenantSnapshot, error)
 }
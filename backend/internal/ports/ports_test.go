@@ -1,6 +1,9 @@
 package ports
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 // TestAuthContextHasRole verifies the auth context has role scenario.
 func TestAuthContextHasRole(t *testing.T) {
@@ -12,3 +15,14 @@ func TestAuthContextHasRole(t *testing.T) {
 		t.Fatal("did not expect org_admin role")
 	}
 }
+
+// TestRequestIDContext verifies the request ID context scenario.
+func TestRequestIDContext(t *testing.T) {
+	if got := RequestIDFromContext(context.Background()); got != "" {
+		t.Fatalf("expected no request ID, got %q", got)
+	}
+	ctx := WithRequestID(context.Background(), "req-1")
+	if got := RequestIDFromContext(ctx); got != "req-1" {
+		t.Fatalf("expected req-1, got %q", got)
+	}
+}
@@ -1,8 +1,16 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net"
+	"net/netip"
+	"time"
 
+	"plato/backend/internal/domain"
 	"plato/backend/internal/ports"
 )
 
@@ -11,6 +19,23 @@ type Service struct {
 	repo      ports.Repository
 	telemetry ports.Telemetry
 	importer  ports.ImportExport
+	now       func() time.Time
+	quotas    Quotas
+	publisher ports.EventPublisher
+	history   *operationHistory
+	search    *searchIndexCache
+
+	uniqueOrganisationNames bool
+	allowPrivateWebhooks    bool
+	// lookupIP resolves webhook hosts before they are registered.
+	lookupIP func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+// Quotas caps how many records one organisation may hold.
+// Zero means unlimited.
+type Quotas struct {
+	MaxPersonsPerOrganisation  int
+	MaxProjectsPerOrganisation int
 }
 
 // New returns a Service from the required repository and adapter dependencies.
@@ -24,5 +49,86 @@ func New(repo ports.Repository, telemetry ports.Telemetry, importer ports.Import
 	if importer == nil {
 		return nil, errors.New("new service: import/export is nil")
 	}
-	return &Service{repo: repo, telemetry: telemetry, importer: importer}, nil
+	return &Service{repo: repo, telemetry: resilientTelemetry{next: telemetry, logf: log.Printf}, importer: importer, now: time.Now, history: newOperationHistory(), search: newSearchIndexCache(), lookupIP: lookupNetIP}, nil
+}
+
+func lookupNetIP(ctx context.Context, host string) ([]netip.Addr, error) {
+	return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+}
+
+// resilientTelemetry keeps telemetry failures from failing business
+// operations. A panicking adapter is logged and the operation continues.
+type resilientTelemetry struct {
+	next ports.Telemetry
+	logf func(format string, args ...any)
+}
+
+// Record forwards the event and recovers from adapter panics.
+func (t resilientTelemetry) Record(name string, attributes map[string]string) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			t.logf("telemetry record %q failed: %v", name, recovered)
+		}
+	}()
+	t.next.Record(name, attributes)
+}
+
+// record sends a telemetry event and tags it with the request ID from ctx
+// so events can be matched to the request log.
+func (s *Service) record(ctx context.Context, name string, attributes map[string]string) {
+	if requestID := ports.RequestIDFromContext(ctx); requestID != "" {
+		tagged := make(map[string]string, len(attributes)+1)
+		maps.Copy(tagged, attributes)
+		tagged["request_id"] = requestID
+		attributes = tagged
+	}
+	s.telemetry.Record(name, attributes)
+}
+
+// recordChange records a stored change and publishes it to webhooks when an
+// event publisher is set.
+func (s *Service) recordChange(ctx context.Context, organisationID, name string, attributes map[string]string) {
+	s.record(ctx, name, attributes)
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(domain.Event{
+		Name:           name,
+		OrganisationID: organisationID,
+		OccurredAt:     s.now().UTC(),
+		RequestID:      ports.RequestIDFromContext(ctx),
+		Data:           maps.Clone(attributes),
+	})
+}
+
+// SetEventPublisher sets where stored changes are published for webhook
+// delivery. Call it before the service handles requests.
+func (s *Service) SetEventPublisher(publisher ports.EventPublisher) {
+	s.publisher = publisher
+}
+
+// SetQuotas replaces the per-organisation record limits.
+func (s *Service) SetQuotas(quotas Quotas) {
+	s.quotas = quotas
+}
+
+// SetUniqueOrganisationNames makes organisation creates and updates reject a
+// name that another organisation already uses.
+func (s *Service) SetUniqueOrganisationNames(unique bool) {
+	s.uniqueOrganisationNames = unique
+}
+
+// SetAllowPrivateWebhooks lets webhooks point at loopback, link-local, and
+// private addresses, which are refused by default.
+func (s *Service) SetAllowPrivateWebhooks(allow bool) {
+	s.allowPrivateWebhooks = allow
+}
+
+// requireCurrentVersion rejects an update made against an older copy of a
+// record. Callers that send no version skip the check.
+func requireCurrentVersion(resource string, expected, stored int64) error {
+	if expected != 0 && expected != stored {
+		return fmt.Errorf("%s changed since version %d, current version is %d: %w", resource, expected, stored, domain.ErrConflict)
+	}
+	return nil
 }
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"plato/backend/internal/domain"
@@ -39,6 +41,43 @@ func enforceTenant(auth ports.AuthContext, targetOrganisationID string) error {
 	return nil
 }
 
+// requireWritableOrganisation rejects mutations while the organisation is in
+// read-only maintenance mode. Reads never call it.
+func (s *Service) requireWritableOrganisation(ctx context.Context, organisationID string) error {
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+	if organisation.ReadOnly {
+		return domain.ErrReadOnly
+	}
+	return s.requireWithinWriteWindow(organisation)
+}
+
+// requireWithinWriteWindow rejects changes outside the organisation's write
+// window, judged by the service clock in the organisation's time zone.
+func (s *Service) requireWithinWriteWindow(organisation domain.Organisation) error {
+	if organisation.WriteWindow == nil {
+		return nil
+	}
+	location, err := organisation.Location()
+	if err != nil {
+		return err
+	}
+	window := *organisation.WriteWindow
+	if window.Allows(s.now().In(location)) {
+		return nil
+	}
+	days := "every day"
+	if len(window.Weekdays) > 0 {
+		days = strings.Join(window.Weekdays, ", ")
+	}
+	return fmt.Errorf(
+		"changes are only accepted on %s between %s and %s %s: %w",
+		days, window.Start, window.End, location.String(), domain.ErrOutsideWriteWindow,
+	)
+}
+
 // IsValidationError reports whether err matches the validation sentinel.
 func IsValidationError(err error) bool {
 	return errors.Is(err, domain.ErrValidation)
@@ -53,3 +92,8 @@ func IsForbiddenError(err error) bool {
 func IsNotFoundError(err error) bool {
 	return errors.Is(err, domain.ErrNotFound)
 }
+
+// IsReadOnlyError reports whether err matches the read-only sentinel.
+func IsReadOnlyError(err error) bool {
+	return errors.Is(err, domain.ErrReadOnly)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// ListAllocationTemplates returns the allocation templates of the caller's
+// organisation.
+func (s *Service) ListAllocationTemplates(ctx context.Context, auth ports.AuthContext) ([]domain.AllocationTemplate, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListAllocationTemplates(ctx, organisationID)
+}
+
+// GetAllocationTemplate returns one allocation template of the caller's
+// organisation.
+func (s *Service) GetAllocationTemplate(ctx context.Context, auth ports.AuthContext, templateID string) (domain.AllocationTemplate, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+	return s.repo.GetAllocationTemplate(ctx, organisationID, templateID)
+}
+
+// CreateAllocationTemplate validates and stores an allocation template. Every
+// entry must name a person or group of the caller's organisation.
+func (s *Service) CreateAllocationTemplate(ctx context.Context, auth ports.AuthContext, input domain.AllocationTemplate) (domain.AllocationTemplate, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+	input = input.Normalized()
+	if err = input.Validate(); err != nil {
+		return domain.AllocationTemplate{}, errors.Join(domain.ErrValidation, fmt.Errorf(
+			"allocation template needs a name and one to %d entries, each with a target_type of %s or %s, a target_id, and a positive percent",
+			domain.MaxAllocationTemplateEntries,
+			domain.AllocationTargetPerson,
+			domain.AllocationTargetGroup,
+		))
+	}
+	for index, entry := range input.Entries {
+		if err = s.requireAllocationTemplateTarget(ctx, organisationID, entry); err != nil {
+			return domain.AllocationTemplate{}, fmt.Errorf("template entry %d: %w", index+1, err)
+		}
+	}
+
+	created, err := s.repo.CreateAllocationTemplate(ctx, domain.AllocationTemplate{
+		OrganisationID: organisationID,
+		Name:           input.Name,
+		Entries:        input.Entries,
+	})
+	if err != nil {
+		return domain.AllocationTemplate{}, err
+	}
+
+	s.recordChange(ctx, organisationID, "allocation_template.created", map[string]string{"template_id": created.ID})
+	return created, nil
+}
+
+// DeleteAllocationTemplate deletes an allocation template. Allocations created
+// from it stay.
+func (s *Service) DeleteAllocationTemplate(ctx context.Context, auth ports.AuthContext, templateID string) error {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
+	if err = s.repo.DeleteAllocationTemplate(ctx, organisationID, templateID); err != nil {
+		return err
+	}
+
+	s.recordChange(ctx, organisationID, "allocation_template.deleted", map[string]string{"template_id": templateID})
+	return nil
+}
+
+// ApplyAllocationTemplate creates one allocation per template entry on the
+// given project and dates. Empty dates default to the project's dates. Each
+// allocation passes the same checks as CreateAllocation, and the daily limit,
+// employment, and project effort checks also count the allocations created
+// before it in the same call. Nothing is stored unless every entry passes.
+func (s *Service) ApplyAllocationTemplate(
+	ctx context.Context,
+	auth ports.AuthContext,
+	templateID string,
+	input domain.AllocationTemplateApplication,
+) ([]domain.Allocation, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return nil, err
+	}
+	template, err := s.repo.GetAllocationTemplate(ctx, organisationID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &pendingAllocationsRepository{Repository: s.repo}
+	batch := *s
+	batch.repo = pending
+	allocations := make([]domain.Allocation, 0, len(template.Entries))
+	warnings := make([][]string, 0, len(template.Entries))
+	for index, entry := range template.Entries {
+		allocation, prepareErr := batch.prepareAllocation(ctx, organisationID, domain.Allocation{
+			TargetType: entry.TargetType,
+			TargetID:   entry.TargetID,
+			ProjectID:  input.ProjectID,
+			StartDate:  input.StartDate,
+			EndDate:    input.EndDate,
+			Percent:    entry.Percent,
+		}, "", true)
+		if prepareErr != nil {
+			return nil, fmt.Errorf("template entry %d: %w", index+1, prepareErr)
+		}
+		warnings = append(warnings, allocation.Warnings)
+		allocation.Warnings = nil
+		allocations = append(allocations, allocation)
+		pending.add(allocation)
+	}
+
+	created, err := s.repo.CreateAllocations(s.withAllocationAudit(ctx, auth), organisationID, allocations)
+	if err != nil {
+		return nil, err
+	}
+	for index := range created {
+		s.recordChange(ctx, organisationID, "allocation.created", map[string]string{"allocation_id": created[index].ID})
+		created[index].Warnings = warnings[index]
+	}
+
+	s.recordChange(ctx, organisationID, "allocation_template.applied", map[string]string{
+		"template_id": template.ID,
+		"project_id":  input.ProjectID,
+		"created":     strconv.Itoa(len(created)),
+	})
+	return created, nil
+}
+
+// requireAllocationTemplateTarget checks that a template entry names a stored
+// person or group.
+func (s *Service) requireAllocationTemplateTarget(ctx context.Context, organisationID string, entry domain.AllocationTemplateEntry) error {
+	var err error
+	if entry.TargetType == domain.AllocationTargetGroup {
+		_, err = s.repo.GetGroup(ctx, organisationID, entry.TargetID)
+	} else {
+		_, err = s.repo.GetPerson(ctx, organisationID, entry.TargetID)
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		return errors.Join(domain.ErrValidation, fmt.Errorf("target_id does not reference a %s in this organisation", entry.TargetType))
+	}
+	return err
+}
+
+// pendingAllocationsRepository lists allocations that passed their checks but
+// are not stored yet next to the stored ones, so a batch of new allocations
+// is checked against itself as well.
+type pendingAllocationsRepository struct {
+	ports.Repository
+	pending []domain.Allocation
+}
+
+// add keeps an allocation as pending. It gets a placeholder ID because the
+// limit checks skip the allocation with the ID being updated, which is empty
+// for new allocations.
+func (r *pendingAllocationsRepository) add(allocation domain.Allocation) {
+	allocation.ID = "pending_" + strconv.Itoa(len(r.pending)+1)
+	r.pending = append(r.pending, allocation)
+}
+
+// ListAllocations returns the stored allocations followed by the pending ones.
+func (r *pendingAllocationsRepository) ListAllocations(ctx context.Context, organisationID string) ([]domain.Allocation, error) {
+	stored, err := r.Repository.ListAllocations(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	return append(stored, r.pending...), nil
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,7 +18,7 @@ const allocationLimitTolerance = 1e-9
 
 // ListAllocations returns the allocations visible to the caller within their organisation.
 func (s *Service) ListAllocations(ctx context.Context, auth ports.AuthContext) ([]domain.Allocation, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -25,9 +28,22 @@ func (s *Service) ListAllocations(ctx context.Context, auth ports.AuthContext) (
 	return s.repo.ListAllocations(ctx, organisationID)
 }
 
+// ListAllocationsPage returns one filtered and sorted page of the allocations visible to
+// the caller within their organisation.
+func (s *Service) ListAllocationsPage(ctx context.Context, auth ports.AuthContext, query domain.ListQuery) (domain.ListPage[domain.Allocation], error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.ListPage[domain.Allocation]{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.ListPage[domain.Allocation]{}, err
+	}
+	return s.repo.ListAllocationsPage(ctx, organisationID, query)
+}
+
 // GetAllocation returns one allocation from the caller's organisation.
 func (s *Service) GetAllocation(ctx context.Context, auth ports.AuthContext, allocationID string) (domain.Allocation, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return domain.Allocation{}, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -37,34 +53,211 @@ func (s *Service) GetAllocation(ctx context.Context, auth ports.AuthContext, all
 	return s.repo.GetAllocation(ctx, organisationID, allocationID)
 }
 
+// ListAllocationsActiveOn returns the caller's organisation allocations whose
+// date range includes date, with target and project names resolved. Expired
+// holds are left out.
+func (s *Service) ListAllocationsActiveOn(ctx context.Context, auth ports.AuthContext, date string) ([]domain.ActiveAllocation, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	day, _, err := parseDateRange(date, date)
+	if err != nil || strings.TrimSpace(date) == "" {
+		return nil, fmt.Errorf("active_on must be a date in YYYY-MM-DD format: %w", domain.ErrValidation)
+	}
+
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	targetNames, projectNames, err := s.allocationDisplayNames(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ActiveAllocation, 0)
+	for _, allocation := range allocations {
+		start, end, rangeErr := parseDateRange(allocation.StartDate, allocation.EndDate)
+		if rangeErr != nil {
+			return nil, rangeErr
+		}
+		if _, _, overlaps := overlapDateRanges(start, end, day, day); !overlaps {
+			continue
+		}
+		targetType, targetID := normalizedAllocationTarget(allocation)
+		result = append(result, domain.ActiveAllocation{
+			Allocation:  allocation,
+			TargetName:  targetNames[targetType][targetID],
+			ProjectName: projectNames[allocation.ProjectID],
+		})
+	}
+	return result, nil
+}
+
+// ActiveAllocationCounts returns, per organisation id, how many allocations
+// cover today. Expired holds are left out. It takes no caller because it
+// feeds operational metrics, so only expose its result to operators.
+func (s *Service) ActiveAllocationCounts(ctx context.Context) (map[string]int, error) {
+	organisations, err := s.repo.ListOrganisations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	today := s.now().UTC().Format(domain.DateLayout)
+	counts := make(map[string]int, len(organisations))
+	for _, organisation := range organisations {
+		allocations, listErr := s.listActiveAllocations(ctx, organisation.ID)
+		if listErr != nil {
+			return nil, listErr
+		}
+		count := 0
+		for _, allocation := range allocations {
+			if allocation.StartDate <= today && today <= allocation.EndDate {
+				count++
+			}
+		}
+		counts[organisation.ID] = count
+	}
+	return counts, nil
+}
+
+// allocationDisplayNames maps allocation target ids to names, keyed by target
+// type, and project ids to project names.
+func (s *Service) allocationDisplayNames(
+	ctx context.Context,
+	organisationID string,
+) (targetNames map[string]map[string]string, projectNames map[string]string, err error) {
+	targetNames = map[string]map[string]string{
+		domain.AllocationTargetPerson: {},
+		domain.AllocationTargetGroup:  {},
+	}
+	persons, err := s.repo.ListPersons(ctx, organisationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, person := range persons {
+		targetNames[domain.AllocationTargetPerson][person.ID] = person.Name
+	}
+	groups, err := s.repo.ListGroups(ctx, organisationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, group := range groups {
+		targetNames[domain.AllocationTargetGroup][group.ID] = group.Name
+	}
+	projects, err := s.repo.ListProjects(ctx, organisationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	projectNames = make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+	return targetNames, projectNames, nil
+}
+
 // CreateAllocation validates and creates an allocation in the caller's organisation.
 func (s *Service) CreateAllocation(ctx context.Context, auth ports.AuthContext, input domain.Allocation) (domain.Allocation, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
 		return domain.Allocation{}, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Allocation{}, err
+	}
+	allocation, err := s.prepareAllocation(ctx, organisationID, input, "", true)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	warnings := allocation.Warnings
+	allocation.Warnings = nil
+
+	created, err := s.repo.CreateAllocation(s.withAllocationAudit(ctx, auth), allocation)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+
+	s.recordChange(ctx, organisationID, "allocation.created", map[string]string{"allocation_id": created.ID})
+	s.rememberAllocationOperation(auth, domain.OperationAllocationCreated, nil, &created)
+	created.Warnings = warnings
+	return created, nil
+}
+
+// prepareAllocation normalizes and checks a new allocation the way
+// CreateAllocation stores it. With checkLimits it also runs the daily limit,
+// employment, and project effort checks against the organisation's
+// allocations. A non-empty allocationID leaves that allocation out of those
+// checks because input replaces it. The result carries the same-project
+// overlap warnings.
+func (s *Service) prepareAllocation(
+	ctx context.Context,
+	organisationID string,
+	input domain.Allocation,
+	allocationID string,
+	checkLimits bool,
+) (domain.Allocation, error) {
 	input = normalizeAllocationInput(input)
+	input, err := s.defaultAllocationDatesFromProject(ctx, organisationID, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
 	err = validateAllocation(input)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	err = s.validateHoldExpiry(input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	input, err = s.snapAllocationDates(ctx, organisationID, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	input, err = s.applyAllocationPercentStep(ctx, organisationID, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
 	project, err := s.repo.GetProject(ctx, organisationID, input.ProjectID)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	err = requireActiveProject(project)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	err = s.validateAllocationReferences(ctx, organisationID, project, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
 	err = validateAllocationWithinProjectRange(input, project)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	err = s.validateAllocationEmploymentWindow(ctx, organisationID, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
 
 	targetPersonIDs, err := s.resolveAllocationTargetPersons(ctx, organisationID, input.TargetType, input.TargetID)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
-	err = s.validateAllocationLimit(ctx, organisationID, input, targetPersonIDs, "")
+	if checkLimits {
+		err = s.validateAllocationLimit(ctx, organisationID, input, targetPersonIDs, allocationID)
+		if err != nil {
+			return domain.Allocation{}, err
+		}
+		err = s.validateProjectEffortCapacity(ctx, organisationID, project, input, len(targetPersonIDs), allocationID)
+		if err != nil {
+			return domain.Allocation{}, err
+		}
+	}
+	warnings, err := s.sameProjectOverlapWarnings(ctx, organisationID, input, allocationID)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
@@ -77,47 +270,201 @@ func (s *Service) CreateAllocation(ctx context.Context, auth ports.AuthContext,
 		StartDate:      input.StartDate,
 		EndDate:        input.EndDate,
 		Percent:        input.Percent,
+		HoldExpiresAt:  input.HoldExpiresAt,
+		Warnings:       warnings,
 	}
 	if input.TargetType == domain.AllocationTargetPerson {
 		allocation.PersonID = input.TargetID
 	}
+	return allocation, nil
+}
 
-	created, err := s.repo.CreateAllocation(ctx, allocation)
+// maxAllocationImportRows caps how many lines one allocation import may hold.
+const maxAllocationImportRows = 1000
+
+// ImportAllocationsByName creates allocations from an import file that names
+// targets and projects instead of referencing IDs. Names resolve within the
+// caller's organisation, ignoring case. Each line is created on its own, so a
+// line with an unknown or ambiguous name, or one that fails validation, is
+// reported as failed without stopping the others.
+func (s *Service) ImportAllocationsByName(ctx context.Context, auth ports.AuthContext, raw []byte) (domain.AllocationImportResult, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return domain.AllocationImportResult{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
-		return domain.Allocation{}, err
+		return domain.AllocationImportResult{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.AllocationImportResult{}, err
+	}
+	rows, err := s.importer.DecodeAllocationImport(raw)
+	if err != nil {
+		return domain.AllocationImportResult{}, err
+	}
+	if len(rows) > maxAllocationImportRows {
+		return domain.AllocationImportResult{}, fmt.Errorf("allocation import must not contain more than %d rows: %w", maxAllocationImportRows, domain.ErrValidation)
 	}
 
-	s.telemetry.Record("allocation.created", map[string]string{"allocation_id": created.ID})
-	return created, nil
+	targetNames, projectNames, err := s.allocationDisplayNames(ctx, organisationID)
+	if err != nil {
+		return domain.AllocationImportResult{}, err
+	}
+	targetIDsByName := map[string]map[string][]string{
+		domain.AllocationTargetPerson: idsByName(targetNames[domain.AllocationTargetPerson]),
+		domain.AllocationTargetGroup:  idsByName(targetNames[domain.AllocationTargetGroup]),
+	}
+	projectIDsByName := idsByName(projectNames)
+
+	result := domain.AllocationImportResult{Rows: make([]domain.AllocationImportRowResult, 0, len(rows))}
+	for _, row := range rows {
+		rowResult := domain.AllocationImportRowResult{Line: row.Line}
+		allocationID, rowErr := s.importAllocationRow(ctx, auth, row, targetIDsByName, projectIDsByName)
+		switch {
+		case rowErr == nil:
+			rowResult.AllocationID = allocationID
+			result.Created++
+		case errors.Is(rowErr, domain.ErrValidation) || errors.Is(rowErr, domain.ErrNotFound):
+			rowResult.Error = rowErr.Error()
+			result.Failed++
+		default:
+			return domain.AllocationImportResult{}, rowErr
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+
+	s.recordChange(ctx, organisationID, "allocation.imported", map[string]string{
+		"created": strconv.Itoa(result.Created),
+		"failed":  strconv.Itoa(result.Failed),
+	})
+	return result, nil
+}
+
+func (s *Service) importAllocationRow(
+	ctx context.Context,
+	auth ports.AuthContext,
+	row domain.AllocationImportRow,
+	targetIDsByName map[string]map[string][]string,
+	projectIDsByName map[string][]string,
+) (string, error) {
+	if row.Error != "" {
+		return "", fmt.Errorf("%s: %w", row.Error, domain.ErrValidation)
+	}
+	targetType := strings.ToLower(strings.TrimSpace(row.TargetType))
+	if targetType == "" {
+		targetType = domain.AllocationTargetPerson
+	}
+	targetIDs, ok := targetIDsByName[targetType]
+	if !ok {
+		return "", fmt.Errorf("target_type %q must be %s or %s: %w", row.TargetType, domain.AllocationTargetPerson, domain.AllocationTargetGroup, domain.ErrValidation)
+	}
+	targetID, err := resolveImportName(targetType, row.TargetName, targetIDs)
+	if err != nil {
+		return "", err
+	}
+	projectID, err := resolveImportName("project", row.ProjectName, projectIDsByName)
+	if err != nil {
+		return "", err
+	}
+
+	created, err := s.CreateAllocation(ctx, auth, domain.Allocation{
+		TargetType: targetType,
+		TargetID:   targetID,
+		ProjectID:  projectID,
+		StartDate:  row.StartDate,
+		EndDate:    row.EndDate,
+		Percent:    row.Percent,
+	})
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func resolveImportName(kind, name string, idsByName map[string][]string) (string, error) {
+	ids := idsByName[importNameKey(name)]
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%s %q not found: %w", kind, strings.TrimSpace(name), domain.ErrValidation)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%s name %q is ambiguous, %d match: %w", kind, strings.TrimSpace(name), len(ids), domain.ErrValidation)
+	}
+}
+
+func idsByName(namesByID map[string]string) map[string][]string {
+	result := make(map[string][]string, len(namesByID))
+	for id, name := range namesByID {
+		key := importNameKey(name)
+		result[key] = append(result[key], id)
+	}
+	return result
+}
+
+func importNameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
 }
 
 // UpdateAllocation validates and updates an allocation in the caller's organisation.
 func (s *Service) UpdateAllocation(ctx context.Context, auth ports.AuthContext, allocationID string, input domain.Allocation) (domain.Allocation, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
 		return domain.Allocation{}, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Allocation{}, err
+	}
 	input = normalizeAllocationInput(input)
 	err = validateAllocation(input)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	err = s.validateHoldExpiry(input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	input, err = s.snapAllocationDates(ctx, organisationID, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	input, err = s.applyAllocationPercentStep(ctx, organisationID, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
 
 	allocation, err := s.repo.GetAllocation(ctx, organisationID, allocationID)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	if err = requireCurrentVersion("allocation", input.Version, allocation.Version); err != nil {
+		return domain.Allocation{}, err
+	}
 	project, err := s.repo.GetProject(ctx, organisationID, input.ProjectID)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	if input.ProjectID != allocation.ProjectID {
+		err = requireActiveProject(project)
+		if err != nil {
+			return domain.Allocation{}, err
+		}
+	}
+	err = s.validateAllocationReferences(ctx, organisationID, project, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
 	err = validateAllocationWithinProjectRange(input, project)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	err = s.validateAllocationEmploymentWindow(ctx, organisationID, input)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
 
 	targetPersonIDs, err := s.resolveAllocationTargetPersons(ctx, organisationID, input.TargetType, input.TargetID)
 	if err != nil {
@@ -127,47 +474,285 @@ func (s *Service) UpdateAllocation(ctx context.Context, auth ports.AuthContext,
 	if err != nil {
 		return domain.Allocation{}, err
 	}
+	err = s.validateProjectEffortCapacity(ctx, organisationID, project, input, len(targetPersonIDs), allocationID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	warnings, err := s.sameProjectOverlapWarnings(ctx, organisationID, input, allocationID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
 
+	before := allocation
 	allocation.TargetType = input.TargetType
 	allocation.TargetID = input.TargetID
 	allocation.ProjectID = input.ProjectID
 	allocation.StartDate = input.StartDate
 	allocation.EndDate = input.EndDate
 	allocation.Percent = input.Percent
+	// Omitting hold_expires_at confirms a tentative hold.
+	allocation.HoldExpiresAt = input.HoldExpiresAt
 	if input.TargetType == domain.AllocationTargetPerson {
 		allocation.PersonID = input.TargetID
 	} else {
 		allocation.PersonID = ""
 	}
 
-	updated, err := s.repo.UpdateAllocation(ctx, allocation)
+	updated, err := s.repo.UpdateAllocation(s.withAllocationAudit(ctx, auth), allocation)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+
+	s.recordChange(ctx, organisationID, "allocation.updated", map[string]string{"allocation_id": updated.ID})
+	s.rememberAllocationOperation(auth, domain.OperationAllocationUpdated, &before, &updated)
+	updated.Warnings = warnings
+	return updated, nil
+}
+
+// EndAllocation ends an allocation early as of endDate and records the reason.
+// The allocation is kept so reports before the end date stay intact.
+func (s *Service) EndAllocation(ctx context.Context, auth ports.AuthContext, allocationID, endDate, reason string) (domain.Allocation, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return domain.Allocation{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Allocation{}, err
+	}
+	endDate, err = domain.ValidateDate(strings.TrimSpace(endDate))
+	if err != nil {
+		return domain.Allocation{}, fmt.Errorf("end_date must be a date in YYYY-MM-DD format: %w", domain.ErrValidation)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return domain.Allocation{}, fmt.Errorf("end reason is required: %w", domain.ErrValidation)
+	}
+
+	allocation, err := s.repo.GetAllocation(ctx, organisationID, allocationID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	if endDate < allocation.StartDate || endDate > allocation.EndDate {
+		return domain.Allocation{}, fmt.Errorf(
+			"end date must fall within allocation range %s to %s: %w",
+			allocation.StartDate,
+			allocation.EndDate,
+			domain.ErrValidation,
+		)
+	}
+
+	before := allocation
+	allocation.EndDate = endDate
+	allocation.EndReason = reason
+	updated, err := s.repo.UpdateAllocation(s.withAllocationEndAudit(ctx, auth), allocation)
 	if err != nil {
 		return domain.Allocation{}, err
 	}
 
-	s.telemetry.Record("allocation.updated", map[string]string{"allocation_id": updated.ID})
+	s.recordChange(ctx, organisationID, "allocation.ended", map[string]string{"allocation_id": updated.ID})
+	s.rememberAllocationOperation(auth, domain.OperationAllocationEnded, &before, &updated)
 	return updated, nil
 }
 
 // DeleteAllocation deletes an allocation from the caller's organisation.
 func (s *Service) DeleteAllocation(ctx context.Context, auth ports.AuthContext, allocationID string) error {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
 		return err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
 
-	err = s.repo.DeleteAllocation(ctx, organisationID, allocationID)
+	allocation, err := s.repo.GetAllocation(ctx, organisationID, allocationID)
+	if err != nil {
+		return err
+	}
+	err = s.repo.DeleteAllocation(s.withAllocationAudit(ctx, auth), organisationID, allocationID)
 	if err != nil {
 		return err
 	}
 
-	s.telemetry.Record("allocation.deleted", map[string]string{"allocation_id": allocationID})
+	s.recordChange(ctx, organisationID, "allocation.deleted", map[string]string{"allocation_id": allocationID})
+	s.rememberAllocationOperation(auth, domain.OperationAllocationDeleted, &allocation, nil)
 	return nil
 }
 
+// ValidateAllocation checks an allocation payload for the caller's
+// organisation without saving it. Payload, reference, and project range
+// problems are reported per field. checkLimit also runs the daily allocation
+// limit against existing allocations. Only failures unrelated to the payload
+// are returned as errors.
+func (s *Service) ValidateAllocation(
+	ctx context.Context,
+	auth ports.AuthContext,
+	input domain.Allocation,
+	checkLimit bool,
+) (domain.AllocationValidation, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return domain.AllocationValidation{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.AllocationValidation{}, err
+	}
+
+	input = normalizeAllocationInput(input)
+	defaulted, err := s.defaultAllocationDatesFromProject(ctx, organisationID, input)
+	switch {
+	case err == nil:
+		input = defaulted
+	case errors.Is(err, domain.ErrNotFound):
+		return allocationValidationResult(append(
+			allocationFieldErrors(input),
+			unknownAllocationProjectError(),
+		)), nil
+	case !errors.Is(err, domain.ErrValidation):
+		return domain.AllocationValidation{}, err
+	}
+	if fieldErrors := allocationFieldErrors(input); len(fieldErrors) > 0 {
+		return allocationValidationResult(fieldErrors), nil
+	}
+
+	project, err := s.repo.GetProject(ctx, organisationID, input.ProjectID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return allocationValidationResult([]domain.AllocationFieldError{unknownAllocationProjectError()}), nil
+	}
+	if err != nil {
+		return domain.AllocationValidation{}, err
+	}
+	fieldErrors := projectRangeFieldErrors(input, project)
+	if project.Archived {
+		fieldErrors = append(fieldErrors, domain.AllocationFieldError{
+			Field:   "project_id",
+			Message: "project_id references an archived project",
+		})
+	}
+	targetPersonIDs, err := s.resolveAllocationTargetPersons(ctx, organisationID, input.TargetType, input.TargetID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		fieldErrors = append(fieldErrors, domain.AllocationFieldError{
+			Field:   "target_id",
+			Message: fmt.Sprintf("target_id does not reference a %s in this organisation", input.TargetType),
+		})
+	case errors.Is(err, domain.ErrValidation):
+		fieldErrors = append(fieldErrors, domain.AllocationFieldError{
+			Field:   "target_id",
+			Message: "target_id references a group without members",
+		})
+	case err != nil:
+		return domain.AllocationValidation{}, err
+	}
+	if len(fieldErrors) > 0 || !checkLimit {
+		return allocationValidationResult(fieldErrors), nil
+	}
+
+	err = s.validateAllocationLimit(ctx, organisationID, input, targetPersonIDs, "")
+	if errors.Is(err, domain.ErrValidation) {
+		fieldErrors = append(fieldErrors, domain.AllocationFieldError{
+			Field:   "percent",
+			Message: strings.TrimSuffix(err.Error(), ": "+domain.ErrValidation.Error()),
+		})
+	} else if err != nil {
+		return domain.AllocationValidation{}, err
+	}
+	return allocationValidationResult(fieldErrors), nil
+}
+
+func allocationValidationResult(fieldErrors []domain.AllocationFieldError) domain.AllocationValidation {
+	if fieldErrors == nil {
+		fieldErrors = []domain.AllocationFieldError{}
+	}
+	return domain.AllocationValidation{Valid: len(fieldErrors) == 0, Errors: fieldErrors}
+}
+
+func unknownAllocationProjectError() domain.AllocationFieldError {
+	return domain.AllocationFieldError{
+		Field:   "project_id",
+		Message: "project_id does not reference a project in this organisation",
+	}
+}
+
+// projectRangeFieldErrors reports which allocation dates fall outside the
+// project window. Both ranges must already be valid.
+func projectRangeFieldErrors(allocation domain.Allocation, project domain.Project) []domain.AllocationFieldError {
+	if validateAllocationWithinProjectRange(allocation, project) == nil {
+		return nil
+	}
+	projectStart, projectEnd, err := parseDateRange(project.StartDate, project.EndDate)
+	if err != nil {
+		return nil
+	}
+	allocationStart, allocationEnd, err := parseDateRange(allocation.StartDate, allocation.EndDate)
+	if err != nil {
+		return nil
+	}
+
+	var fieldErrors []domain.AllocationFieldError
+	if allocationStart.Before(projectStart) {
+		fieldErrors = append(fieldErrors, domain.AllocationFieldError{
+			Field:   "start_date",
+			Message: "start_date must not be before the project start " + project.StartDate,
+		})
+	}
+	if allocationEnd.After(projectEnd) {
+		fieldErrors = append(fieldErrors, domain.AllocationFieldError{
+			Field:   "end_date",
+			Message: "end_date must not be after the project end " + project.EndDate,
+		})
+	}
+	return fieldErrors
+}
+
+// applyAllocationPercentStep rejects or snaps off-step percents according to
+// the organisation's allocation percent step.
+func (s *Service) applyAllocationPercentStep(ctx context.Context, organisationID string, input domain.Allocation) (domain.Allocation, error) {
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	snapped, onStep := domain.SnapPercentToStep(input.Percent, organisation.AllocationPercentStep)
+	if !onStep && organisation.AllocationPercentStepMode != domain.PercentStepSnap {
+		return domain.Allocation{}, fmt.Errorf(
+			"percent %s is not a multiple of the allocation step %s: %w",
+			strconv.FormatFloat(input.Percent, 'f', -1, 64),
+			strconv.FormatFloat(organisation.AllocationPercentStep, 'f', -1, 64),
+			domain.ErrValidation,
+		)
+	}
+	input.Percent = snapped
+	return input, nil
+}
+
+// snapAllocationDates applies the organisation's allocation date snap option.
+// Snapped dates stop at the project's start and end, so a project that
+// starts or ends mid-week or mid-month can still be planned in whole periods.
+func (s *Service) snapAllocationDates(ctx context.Context, organisationID string, input domain.Allocation) (domain.Allocation, error) {
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	snap := organisation.SnapAllocationDatesTo
+	if snap == "" || snap == domain.SnapNone {
+		return input, nil
+	}
+	project, err := s.repo.GetProject(ctx, organisationID, input.ProjectID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	input.StartDate, input.EndDate, err = domain.SnapAllocationDates(input.StartDate, input.EndDate, snap, project.StartDate, project.EndDate)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	return input, nil
+}
+
 func (s *Service) validateAllocationLimit(
 	ctx context.Context,
 	organisationID string,
@@ -180,7 +765,7 @@ func (s *Service) validateAllocationLimit(
 		return domain.ErrValidation
 	}
 
-	allocations, err := s.repo.ListAllocations(ctx, organisationID)
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
 	if err != nil {
 		return err
 	}
@@ -192,6 +777,10 @@ func (s *Service) validateAllocationLimit(
 	if err != nil {
 		return err
 	}
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return err
+	}
 
 	for _, personID := range candidatePersonIDs {
 		personValidationErr := s.validatePersonAllocationLimit(
@@ -205,6 +794,7 @@ func (s *Service) validateAllocationLimit(
 			allocations,
 			groupsByID,
 			maxPercentPerDay,
+			organisation.RejectOverEmployment,
 		)
 		if personValidationErr != nil {
 			return personValidationErr
@@ -225,8 +815,10 @@ func (s *Service) validatePersonAllocationLimit(
 	allocations []domain.Allocation,
 	groupsByID map[string]domain.Group,
 	maxPercentPerDay float64,
+	rejectOverEmployment bool,
 ) error {
-	if _, err := s.repo.GetPerson(ctx, organisationID, personID); err != nil {
+	person, err := s.repo.GetPerson(ctx, organisationID, personID)
+	if err != nil {
 		return err
 	}
 
@@ -243,7 +835,7 @@ func (s *Service) validatePersonAllocationLimit(
 	eventDates := sortedEventDates(events)
 	for _, eventDate := range eventDates {
 		if eventDate.After(candidateEnd) {
-			return nil
+			break
 		}
 		total += events[eventDate]
 		if exceedsAllocationLimit(total, maxPercentPerDay) {
@@ -251,9 +843,107 @@ func (s *Service) validatePersonAllocationLimit(
 		}
 	}
 
+	if rejectOverEmployment {
+		return validateEmploymentCapacity(person, candidate.Percent, events, candidateStart, candidateEnd)
+	}
+	return nil
+}
+
+// validateEmploymentCapacity walks the candidate window in stretches of
+// constant load and rejects the first day where the load exceeds the
+// person's employment percentage.
+func validateEmploymentCapacity(
+	person domain.Person,
+	candidatePercent float64,
+	events map[time.Time]float64,
+	candidateStart time.Time,
+	candidateEnd time.Time,
+) error {
+	eventDates := sortedEventDates(events)
+	total := candidatePercent
+	next := 0
+	for stretchStart := candidateStart; !stretchStart.After(candidateEnd); {
+		for next < len(eventDates) && !eventDates[next].After(stretchStart) {
+			total += events[eventDates[next]]
+			next++
+		}
+		stretchEnd := candidateEnd
+		if next < len(eventDates) && eventDates[next].AddDate(0, 0, -1).Before(stretchEnd) {
+			stretchEnd = eventDates[next].AddDate(0, 0, -1)
+		}
+		if err := validateEmploymentCapacityBetween(person, total, stretchStart, stretchEnd); err != nil {
+			return err
+		}
+		if next >= len(eventDates) {
+			return nil
+		}
+		stretchStart = eventDates[next]
+	}
+	return nil
+}
+
+// validateEmploymentCapacityBetween checks a constant load against the
+// employment percentage at the start of the range and at every employment
+// change inside it.
+func validateEmploymentCapacityBetween(person domain.Person, total float64, rangeStart, rangeEnd time.Time) error {
+	checkDates := []time.Time{rangeStart}
+	for _, change := range person.EmploymentChanges {
+		effective, err := time.Parse(domain.MonthLayout, change.EffectiveMonth)
+		if err != nil {
+			return domain.ErrValidation
+		}
+		if effective.After(rangeStart) && !effective.After(rangeEnd) {
+			checkDates = append(checkDates, effective)
+		}
+	}
+	sort.Slice(checkDates, func(i, j int) bool { return checkDates[i].Before(checkDates[j]) })
+
+	for _, checkDate := range checkDates {
+		date := checkDate.Format(domain.DateLayout)
+		employmentPct, err := domain.EmploymentPctOnDate(person, date)
+		if err != nil {
+			return err
+		}
+		if total > employmentPct+allocationLimitTolerance {
+			return fmt.Errorf(
+				"allocation puts person %s at %g%% on %s, %g%% over their %g%% employment: %w",
+				person.ID,
+				total,
+				date,
+				math.Round((total-employmentPct)*100)/100,
+				employmentPct,
+				domain.ErrValidation,
+			)
+		}
+	}
 	return nil
 }
 
+func personExceedsAllocationLimit(
+	allocations []domain.Allocation,
+	personID string,
+	groupsByID map[string]domain.Group,
+	maxPercentPerDay float64,
+) (bool, error) {
+	rangeStart, rangeEnd, err := parseDateRange("", "")
+	if err != nil {
+		return false, err
+	}
+	events, err := buildAllocationEvents(allocations, "", personID, groupsByID, rangeStart, rangeEnd)
+	if err != nil {
+		return false, err
+	}
+
+	var total float64
+	for _, eventDate := range sortedEventDates(events) {
+		total += events[eventDate]
+		if exceedsAllocationLimit(total, maxPercentPerDay) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (s *Service) listGroupsByID(ctx context.Context, organisationID string) (map[string]domain.Group, error) {
 	groups, err := s.repo.ListGroups(ctx, organisationID)
 	if err != nil {
@@ -308,6 +998,49 @@ func buildAllocationEvents(
 	return events, nil
 }
 
+// sameProjectOverlapWarnings describes active allocations that book the same
+// target on the same project over overlapping dates. Unlike the allocation
+// limit these double bookings never block the write.
+func (s *Service) sameProjectOverlapWarnings(
+	ctx context.Context,
+	organisationID string,
+	input domain.Allocation,
+	allocationID string,
+) ([]string, error) {
+	candidateStart, candidateEnd, err := parseDateRange(input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, domain.ErrValidation
+	}
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, existing := range allocations {
+		existing = normalizeAllocationInput(existing)
+		if existing.ID == allocationID || existing.ProjectID != input.ProjectID ||
+			existing.TargetType != input.TargetType || existing.TargetID != input.TargetID {
+			continue
+		}
+		existingStart, existingEnd, rangeErr := parseDateRange(existing.StartDate, existing.EndDate)
+		if rangeErr != nil {
+			return nil, domain.ErrValidation
+		}
+		overlapStart, overlapEnd, overlaps := overlapDateRanges(candidateStart, candidateEnd, existingStart, existingEnd)
+		if !overlaps {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"overlaps allocation %s on the same project from %s to %s",
+			existing.ID,
+			overlapStart.Format(domain.DateLayout),
+			overlapEnd.Format(domain.DateLayout),
+		))
+	}
+	return warnings, nil
+}
+
 func overlapDateRanges(
 	rangeStartA time.Time,
 	rangeEndA time.Time,
@@ -347,6 +1080,26 @@ func allocationLimitExceededError() error {
 	return fmt.Errorf("allocation exceeds 24 hours/day theoretical limit: %w", domain.ErrValidation)
 }
 
+// listActiveAllocations returns allocations that still reserve capacity, so
+// expired tentative holds are skipped lazily at read time.
+func (s *Service) listActiveAllocations(ctx context.Context, organisationID string) ([]domain.Allocation, error) {
+	allocations, err := s.repo.ListAllocations(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ActiveAllocations(allocations, s.now()), nil
+}
+
+func (s *Service) validateHoldExpiry(input domain.Allocation) error {
+	if input.HoldExpiresAt == nil {
+		return nil
+	}
+	if input.HoldExpired(s.now()) {
+		return fmt.Errorf("hold_expires_at must be in the future: %w", domain.ErrValidation)
+	}
+	return nil
+}
+
 func normalizeAllocationInput(input domain.Allocation) domain.Allocation {
 	input.TargetType = strings.TrimSpace(input.TargetType)
 	input.TargetID = strings.TrimSpace(input.TargetID)
@@ -360,6 +1113,76 @@ func normalizeAllocationInput(input domain.Allocation) domain.Allocation {
 	return input
 }
 
+// defaultAllocationDatesFromProject fills a missing start or end date with
+// the referenced project's start or end, so omitting both spans the whole
+// project. Explicit dates are kept. An open-ended project has no end to
+// inherit, so the end date stays required there. Without a project ID the
+// input is returned unchanged and fails validation later.
+func (s *Service) defaultAllocationDatesFromProject(ctx context.Context, organisationID string, input domain.Allocation) (domain.Allocation, error) {
+	input.StartDate = strings.TrimSpace(input.StartDate)
+	input.EndDate = strings.TrimSpace(input.EndDate)
+	if (input.StartDate != "" && input.EndDate != "") || strings.TrimSpace(input.ProjectID) == "" {
+		return input, nil
+	}
+
+	project, err := s.repo.GetProject(ctx, organisationID, input.ProjectID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	if input.StartDate == "" {
+		input.StartDate = project.StartDate
+	}
+	if input.EndDate == "" {
+		input.EndDate = strings.TrimSpace(project.EndDate)
+	}
+	if input.EndDate == "" {
+		return domain.Allocation{}, fmt.Errorf("end_date is required because project %s has no end date: %w", project.ID, domain.ErrValidation)
+	}
+	return input, nil
+}
+
+// requireActiveProject rejects new allocations against an archived project.
+// Allocations it already has are kept for reporting.
+func requireActiveProject(project domain.Project) error {
+	if project.Archived {
+		return fmt.Errorf("project %s is archived: %w", project.ID, domain.ErrValidation)
+	}
+	return nil
+}
+
+// validateAllocationReferences checks that the allocation's project and
+// target both belong to organisationID. Repository lookups are already
+// scoped by tenant, so this guards against a reference slipping through from
+// another organisation. Foreign records are reported as not found so their
+// existence is not revealed.
+func (s *Service) validateAllocationReferences(ctx context.Context, organisationID string, project domain.Project, input domain.Allocation) error {
+	if project.OrganisationID != organisationID {
+		return fmt.Errorf("project %s: %w", input.ProjectID, domain.ErrNotFound)
+	}
+
+	var targetOrganisationID string
+	switch input.TargetType {
+	case domain.AllocationTargetPerson:
+		person, err := s.repo.GetPerson(ctx, organisationID, input.TargetID)
+		if err != nil {
+			return err
+		}
+		targetOrganisationID = person.OrganisationID
+	case domain.AllocationTargetGroup:
+		group, err := s.repo.GetGroup(ctx, organisationID, input.TargetID)
+		if err != nil {
+			return err
+		}
+		targetOrganisationID = group.OrganisationID
+	default:
+		return domain.ErrValidation
+	}
+	if targetOrganisationID != organisationID {
+		return fmt.Errorf("%s %s: %w", input.TargetType, input.TargetID, domain.ErrNotFound)
+	}
+	return nil
+}
+
 func (s *Service) resolveAllocationTargetPersons(
 	ctx context.Context,
 	organisationID string,
@@ -384,14 +1207,18 @@ func (s *Service) resolvePersonAllocationTarget(ctx context.Context, organisatio
 }
 
 func (s *Service) resolveGroupAllocationTarget(ctx context.Context, organisationID string, groupID string) ([]string, error) {
-	group, err := s.repo.GetGroup(ctx, organisationID, groupID)
+	groupsByID, err := s.listGroupsByID(ctx, organisationID)
 	if err != nil {
 		return nil, err
 	}
-	if len(group.MemberIDs) == 0 {
+	memberIDs, err := domain.GroupPersonIDs(groupID, groupsByID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberIDs) == 0 {
 		return nil, domain.ErrValidation
 	}
-	return uniqueStringIDs(group.MemberIDs), nil
+	return memberIDs, nil
 }
 
 func allocationTargetsPerson(allocation domain.Allocation, personID string, groupsByID map[string]domain.Group) bool {
@@ -400,11 +1227,11 @@ func allocationTargetsPerson(allocation domain.Allocation, personID string, grou
 	case domain.AllocationTargetPerson:
 		return targetID == personID
 	case domain.AllocationTargetGroup:
-		group, ok := groupsByID[targetID]
-		if !ok {
+		memberIDs, err := domain.GroupPersonIDs(targetID, groupsByID)
+		if err != nil {
 			return false
 		}
-		for _, memberID := range group.MemberIDs {
+		for _, memberID := range memberIDs {
 			if memberID == personID {
 				return true
 			}
@@ -434,11 +1261,129 @@ func validateAllocationWithinProjectRange(allocation domain.Allocation, project
 		return domain.ErrValidation
 	}
 	if allocationStart.Before(projectStart) || allocationEnd.After(projectEnd) {
+		return domain.ProjectRangeError{ProjectStart: project.StartDate, ProjectEnd: project.EndDate}
+	}
+	return nil
+}
+
+// validateAllocationEmploymentWindow rejects person allocations that fall
+// entirely in months with 0% employment when the organisation requires it.
+func (s *Service) validateAllocationEmploymentWindow(ctx context.Context, organisationID string, allocation domain.Allocation) error {
+	if allocation.TargetType != domain.AllocationTargetPerson {
+		return nil
+	}
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+	if !organisation.RequireEmploymentForAllocations {
+		return nil
+	}
+	person, err := s.repo.GetPerson(ctx, organisationID, allocation.TargetID)
+	if err != nil {
+		return err
+	}
+
+	start, end, err := parseDateRange(allocation.StartDate, allocation.EndDate)
+	if err != nil {
 		return domain.ErrValidation
 	}
+	for month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(end); month = month.AddDate(0, 1, 0) {
+		employmentPct, pctErr := domain.EmploymentPctOnDate(person, month.Format(domain.DateLayout))
+		if pctErr != nil {
+			return pctErr
+		}
+		if employmentPct > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"person is not employed between %s and %s: %w",
+		allocation.StartDate,
+		allocation.EndDate,
+		domain.ErrValidation,
+	)
+}
+
+// validateProjectEffortCapacity rejects allocations that would commit more
+// hours to a project than its estimated effort when the organisation requires it.
+// Committed hours use the report scale of hours per day times percent for
+// every day and target person of an allocation.
+func (s *Service) validateProjectEffortCapacity(
+	ctx context.Context,
+	organisationID string,
+	project domain.Project,
+	candidate domain.Allocation,
+	candidateTargets int,
+	allocationID string,
+) error {
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+	if !organisation.RejectAllocationsOverProjectEffort || project.EstimatedEffortHours <= 0 {
+		return nil
+	}
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+	groupsByID, err := s.listGroupsByID(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+
+	committed, err := committedAllocationHours(candidate, candidateTargets, organisation)
+	if err != nil {
+		return err
+	}
+	for _, allocation := range allocations {
+		if allocation.ID == allocationID || allocation.ProjectID != project.ID {
+			continue
+		}
+		hours, hoursErr := committedAllocationHours(allocation, allocationTargetCount(allocation, groupsByID), organisation)
+		if hoursErr != nil {
+			return hoursErr
+		}
+		committed += hours
+	}
+
+	overage := committed - project.EstimatedEffortHours
+	if overage > allocationLimitTolerance {
+		return fmt.Errorf(
+			"allocation exceeds project estimated effort of %.2f hours by %.2f hours: %w",
+			project.EstimatedEffortHours,
+			overage,
+			domain.ErrValidation,
+		)
+	}
 	return nil
 }
 
+func committedAllocationHours(allocation domain.Allocation, targets int, organisation domain.Organisation) (float64, error) {
+	start, end, err := parseDateRange(allocation.StartDate, allocation.EndDate)
+	if err != nil {
+		return 0, domain.ErrValidation
+	}
+	var fullTimeHours float64
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		fullTimeHours += organisation.HoursPerDayOn(day.Format(domain.DateLayout))
+	}
+	return fullTimeHours * allocation.Percent / 100 * float64(targets), nil
+}
+
+func allocationTargetCount(allocation domain.Allocation, groupsByID map[string]domain.Group) int {
+	targetType, targetID := normalizedAllocationTarget(allocation)
+	if targetType == domain.AllocationTargetGroup {
+		memberIDs, err := domain.GroupPersonIDs(targetID, groupsByID)
+		if err != nil {
+			return 0
+		}
+		return len(memberIDs)
+	}
+	return 1
+}
+
 func uniqueStringIDs(values []string) []string {
 	seen := map[string]bool{}
 	result := make([]string, 0, len(values))
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+const (
+	// apiKeyPrefix starts every API key so leaked keys are easy to scan for.
+	apiKeyPrefix = "plato_"
+	// apiKeySecretBytes is the number of random bytes in an API key.
+	apiKeySecretBytes = 32
+	// apiKeyDisplayLength is how much of the key is kept to tell keys apart.
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+	// apiKeyActorPrefix marks audit actors that authenticated with a key.
+	apiKeyActorPrefix = "api_key:"
+)
+
+var errAPIKeyInvalid = errors.New("api key is unknown or revoked")
+
+// ListAPIKeys returns the API keys of the caller's organisation without
+// their hashes.
+func (s *Service) ListAPIKeys(ctx context.Context, auth ports.AuthContext) ([]domain.APIKey, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.ListAPIKeys(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i].Hash = ""
+	}
+	return keys, nil
+}
+
+// CreateAPIKey issues an API key bound to the caller's organisation with the
+// requested roles. The returned key carries the plain key, which is not
+// shown again.
+func (s *Service) CreateAPIKey(ctx context.Context, auth ports.AuthContext, input domain.APIKey) (domain.APIKey, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.APIKey{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.APIKey{}, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return domain.APIKey{}, errors.Join(domain.ErrValidation, errors.New("api key name is required"))
+	}
+	roles, err := normalizeAPIKeyRoles(input.Roles)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return domain.APIKey{}, fmt.Errorf("generate api key: %w", err)
+	}
+	plain := apiKeyPrefix + hex.EncodeToString(secret)
+
+	created, err := s.repo.CreateAPIKey(ctx, domain.APIKey{
+		OrganisationID: organisationID,
+		Name:           name,
+		Roles:          roles,
+		Prefix:         plain[:apiKeyDisplayLength],
+		Hash:           hashAPIKey(plain),
+	})
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	s.record(ctx, "api_key.created", map[string]string{"api_key_id": created.ID})
+	created.Hash = ""
+	created.Key = plain
+	return created, nil
+}
+
+// RevokeAPIKey stops an API key of the caller's organisation from
+// authenticating. The key stays listed with its revocation time.
+func (s *Service) RevokeAPIKey(ctx context.Context, auth ports.AuthContext, keyID string) (domain.APIKey, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.APIKey{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	revoked, err := s.repo.RevokeAPIKey(ctx, organisationID, keyID)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	s.record(ctx, "api_key.revoked", map[string]string{"api_key_id": keyID})
+	revoked.Hash = ""
+	return revoked, nil
+}
+
+// ResolveAPIKey returns the auth context a plain API key grants. Unknown and
+// revoked keys fail.
+func (s *Service) ResolveAPIKey(ctx context.Context, plain string) (ports.AuthContext, error) {
+	key, err := s.repo.GetAPIKeyByHash(ctx, hashAPIKey(plain))
+	if errors.Is(err, domain.ErrNotFound) {
+		return ports.AuthContext{}, errAPIKeyInvalid
+	}
+	if err != nil {
+		return ports.AuthContext{}, err
+	}
+	if key.RevokedAt != nil {
+		return ports.AuthContext{}, errAPIKeyInvalid
+	}
+
+	return ports.AuthContext{
+		UserID:         apiKeyActorPrefix + key.ID,
+		OrganisationID: key.OrganisationID,
+		Roles:          key.Roles,
+	}, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of a plain key. Keys carry 256 random
+// bits, so a fast unsalted hash is enough to keep stored hashes useless.
+func hashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeAPIKeyRoles trims and deduplicates roles and rejects unknown ones.
+func normalizeAPIKeyRoles(roles []string) ([]string, error) {
+	normalized := make([]string, 0, len(roles))
+	for _, rawRole := range roles {
+		role := strings.TrimSpace(rawRole)
+		if role != domain.RoleOrgAdmin && role != domain.RoleOrgPlanner && role != domain.RoleOrgUser {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("unknown api key role %q", role))
+		}
+		if !slices.Contains(normalized, role) {
+			normalized = append(normalized, role)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, errors.Join(domain.ErrValidation, errors.New("api key roles must not be empty"))
+	}
+	return normalized, nil
+}
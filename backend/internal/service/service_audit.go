@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// ListAllocationEvents returns the caller's organisation allocation events
+// dated within fromDate and toDate, oldest first. Dates are inclusive, read
+// in the organisation's time zone, and either bound may be left empty.
+func (s *Service) ListAllocationEvents(ctx context.Context, auth ports.AuthContext, fromDate, toDate string) ([]domain.AllocationEvent, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := parseDateRange(fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("from and to must be dates in YYYY-MM-DD format with from not after to: %w", domain.ErrValidation)
+	}
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := organisation.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.ListAllocationEvents(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	fromKey := from.Format(domain.DateLayout)
+	toKey := to.Format(domain.DateLayout)
+	result := make([]domain.AllocationEvent, 0, len(events))
+	for _, event := range events {
+		day := event.At.In(location).Format(domain.DateLayout)
+		if day < fromKey || day > toKey {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+// GetAllocationHistory returns the recorded changes of one allocation in the
+// caller's organisation, oldest first. The creation itself is not a change
+// and is left out.
+func (s *Service) GetAllocationHistory(ctx context.Context, auth ports.AuthContext, allocationID string) ([]domain.AllocationHistoryEntry, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.repo.GetAllocation(ctx, organisationID, allocationID); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.ListAllocationEvents(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]domain.AllocationHistoryEntry, 0)
+	for _, event := range events {
+		if event.AllocationID != allocationID || event.Before == nil || event.After == nil {
+			continue
+		}
+		history = append(history, domain.AllocationHistoryEntry{
+			Action:  event.Action,
+			ActorID: event.ActorID,
+			At:      event.At,
+			Changes: domain.AllocationFieldChanges(*event.Before, *event.After),
+		})
+	}
+	return history, nil
+}
+
+// withAllocationAudit names the caller as the actor of the allocation events
+// the repository stores for writes made under the returned context.
+func (s *Service) withAllocationAudit(ctx context.Context, auth ports.AuthContext) context.Context {
+	return ports.WithAllocationAudit(ctx, domain.AllocationAudit{ActorID: auth.UserID, At: s.now().UTC()})
+}
+
+// withAllocationEndAudit is withAllocationAudit for writes that end
+// allocations early, whose updates are recorded as AllocationEventEnded.
+func (s *Service) withAllocationEndAudit(ctx context.Context, auth ports.AuthContext) context.Context {
+	return ports.WithAllocationAudit(ctx, domain.AllocationAudit{
+		ActorID:      auth.UserID,
+		At:           s.now().UTC(),
+		UpdateAction: domain.AllocationEventEnded,
+	})
+}
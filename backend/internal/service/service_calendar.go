@@ -1,8 +1,15 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"plato/backend/internal/domain"
 	"plato/backend/internal/ports"
@@ -10,7 +17,7 @@ import (
 
 // ListOrgHolidays returns organisation holidays visible to the caller.
 func (s *Service) ListOrgHolidays(ctx context.Context, auth ports.AuthContext) ([]domain.OrgHoliday, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -20,6 +27,71 @@ func (s *Service) ListOrgHolidays(ctx context.Context, auth ports.AuthContext) (
 	return s.repo.ListOrgHolidays(ctx, organisationID)
 }
 
+// ListOrgHolidaysInPeriod returns organisation holidays dated within the
+// requested year and the optional from and to bounds. Every bound is
+// optional, and a year combined with from or to narrows to their overlap.
+func (s *Service) ListOrgHolidaysInPeriod(ctx context.Context, auth ports.AuthContext, year, from, to string) ([]domain.OrgHoliday, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := holidayPeriod(year, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.repo.ListOrgHolidays(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]domain.OrgHoliday, 0, len(holidays))
+	for _, holiday := range holidays {
+		if (start != "" && holiday.Date < start) || (end != "" && holiday.Date > end) {
+			continue
+		}
+		result = append(result, holiday)
+	}
+	return result, nil
+}
+
+// holidayPeriod resolves the holiday list filter into inclusive date
+// bounds. An empty bound leaves that side of the period open.
+func holidayPeriod(year, from, to string) (start string, end string, err error) {
+	if year = strings.TrimSpace(year); year != "" {
+		parsedYear, parseErr := strconv.Atoi(year)
+		if parseErr != nil || len(year) != 4 || parsedYear < 1 {
+			return "", "", errors.Join(domain.ErrValidation, errors.New("year must be a four digit year"))
+		}
+		start = year + "-01-01"
+		end = year + "-12-31"
+	}
+	if from = strings.TrimSpace(from); from != "" {
+		from, err = domain.ValidateDate(from)
+		if err != nil {
+			return "", "", errors.Join(domain.ErrValidation, errors.New("from must be a date in YYYY-MM-DD format"))
+		}
+		if from > start {
+			start = from
+		}
+	}
+	if to = strings.TrimSpace(to); to != "" {
+		to, err = domain.ValidateDate(to)
+		if err != nil {
+			return "", "", errors.Join(domain.ErrValidation, errors.New("to must be a date in YYYY-MM-DD format"))
+		}
+		if end == "" || to < end {
+			end = to
+		}
+	}
+	if start != "" && end != "" && end < start {
+		return "", "", errors.Join(domain.ErrValidation, errors.New("holiday period end must not be before its start"))
+	}
+	return start, end, nil
+}
+
 // CreateOrgHoliday validates and creates an organisation holiday entry.
 func (s *Service) CreateOrgHoliday(ctx context.Context, auth ports.AuthContext, input domain.OrgHoliday) (domain.OrgHoliday, error) {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
@@ -29,11 +101,18 @@ func (s *Service) CreateOrgHoliday(ctx context.Context, auth ports.AuthContext,
 	if err != nil {
 		return domain.OrgHoliday{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.OrgHoliday{}, err
+	}
 	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
 	if err != nil {
 		return domain.OrgHoliday{}, err
 	}
-	err = validateDateHours(input.Date, input.Hours, organisation.HoursPerDay)
+	err = validateDateHours(input.Date, input.Hours, organisation.HoursPerDayOn(input.Date))
+	if err != nil {
+		return domain.OrgHoliday{}, err
+	}
+	personIDs, err := s.resolveHolidayTargets(ctx, organisationID, input.PersonIDs)
 	if err != nil {
 		return domain.OrgHoliday{}, err
 	}
@@ -41,7 +120,9 @@ func (s *Service) CreateOrgHoliday(ctx context.Context, auth ports.AuthContext,
 	entry := domain.OrgHoliday{
 		OrganisationID: organisationID,
 		Date:           input.Date,
+		Name:           strings.TrimSpace(input.Name),
 		Hours:          input.Hours,
+		PersonIDs:      personIDs,
 	}
 
 	created, err := s.repo.CreateOrgHoliday(ctx, entry)
@@ -49,10 +130,157 @@ func (s *Service) CreateOrgHoliday(ctx context.Context, auth ports.AuthContext,
 		return domain.OrgHoliday{}, err
 	}
 
-	s.telemetry.Record("holiday.created", map[string]string{"holiday_id": created.ID})
+	s.recordChange(ctx, organisationID, "holiday.created", map[string]string{"holiday_id": created.ID})
 	return created, nil
 }
 
+// ImportOrgHolidays creates organisation-wide holidays for one year in a
+// single write, either from an iCalendar file or from the national holiday
+// dataset of a country and optional region. Dates that already carry an
+// organisation-wide holiday, or that appear twice in the import, are skipped.
+func (s *Service) ImportOrgHolidays(ctx context.Context, auth ports.AuthContext, input domain.HolidayImportRequest) (domain.HolidayImportResult, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.HolidayImportResult{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.HolidayImportResult{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.HolidayImportResult{}, err
+	}
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return domain.HolidayImportResult{}, err
+	}
+	if input.Year < 1000 || input.Year > 9999 {
+		return domain.HolidayImportResult{}, errors.Join(domain.ErrValidation, errors.New("year must be a four digit year"))
+	}
+	if math.IsNaN(input.Hours) || math.IsInf(input.Hours, 0) || input.Hours < 0 ||
+		input.Hours > organisation.MaxHoursPerDay()+dailyHoursTolerance {
+		return domain.HolidayImportResult{}, holidayImportHoursError()
+	}
+
+	entries, err := s.holidayImportEntries(input)
+	if err != nil {
+		return domain.HolidayImportResult{}, err
+	}
+	existing, err := s.repo.ListOrgHolidays(ctx, organisationID)
+	if err != nil {
+		return domain.HolidayImportResult{}, err
+	}
+	covered := make(map[string]struct{}, len(existing))
+	for _, holiday := range existing {
+		if len(holiday.PersonIDs) == 0 {
+			covered[holiday.Date] = struct{}{}
+		}
+	}
+
+	result := domain.HolidayImportResult{Skipped: make([]domain.HolidayImportEntry, 0)}
+	pending := make([]domain.OrgHoliday, 0, len(entries))
+	for _, entry := range entries {
+		if _, duplicate := covered[entry.Date]; duplicate {
+			result.Skipped = append(result.Skipped, entry)
+			continue
+		}
+		covered[entry.Date] = struct{}{}
+		dayHours := organisation.HoursPerDayOn(entry.Date)
+		hours := input.Hours
+		if hours == 0 {
+			hours = dayHours
+		}
+		if hours > dayHours+dailyHoursTolerance {
+			return domain.HolidayImportResult{}, holidayImportHoursError()
+		}
+		pending = append(pending, domain.OrgHoliday{
+			OrganisationID: organisationID,
+			Date:           entry.Date,
+			Name:           entry.Name,
+			Hours:          hours,
+		})
+	}
+
+	result.Created = make([]domain.OrgHoliday, 0)
+	if len(pending) > 0 {
+		if result.Created, err = s.repo.CreateOrgHolidays(ctx, organisationID, pending); err != nil {
+			return domain.HolidayImportResult{}, err
+		}
+	}
+
+	s.recordChange(ctx, organisationID, "holiday.imported", map[string]string{
+		"year":    strconv.Itoa(input.Year),
+		"created": strconv.Itoa(len(result.Created)),
+		"skipped": strconv.Itoa(len(result.Skipped)),
+	})
+	return result, nil
+}
+
+func holidayImportHoursError() error {
+	return errors.Join(domain.ErrValidation, errors.New("hours must be positive and must not exceed the organisation's hours per day"))
+}
+
+// holidayImportEntries reads the import source and orders the entries by
+// date.
+func (s *Service) holidayImportEntries(input domain.HolidayImportRequest) ([]domain.HolidayImportEntry, error) {
+	country := strings.TrimSpace(input.Country)
+	hasCalendar := len(bytes.TrimSpace(input.Calendar)) > 0
+	var (
+		entries []domain.HolidayImportEntry
+		err     error
+	)
+	switch {
+	case country != "" && hasCalendar:
+		return nil, errors.Join(domain.ErrValidation, errors.New("import holidays from either a country or a calendar file, not both"))
+	case country != "":
+		entries, err = s.importer.NationalHolidays(country, input.Region, input.Year)
+	case hasCalendar:
+		if strings.TrimSpace(input.Region) != "" {
+			return nil, errors.Join(domain.ErrValidation, errors.New("region needs a country"))
+		}
+		entries, err = s.importer.DecodeHolidayCalendar(input.Calendar, input.Year)
+	default:
+		return nil, errors.Join(domain.ErrValidation, errors.New("holiday import needs a country or a calendar file"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date < entries[j].Date
+	})
+	return entries, nil
+}
+
+// resolveHolidayTargets trims and deduplicates targeted person IDs.
+// An empty result keeps the holiday organisation-wide.
+func (s *Service) resolveHolidayTargets(ctx context.Context, organisationID string, personIDs []string) ([]string, error) {
+	if len(personIDs) == 0 {
+		return nil, nil
+	}
+
+	resolved := make([]string, 0, len(personIDs))
+	seen := make(map[string]struct{}, len(personIDs))
+	for _, rawPersonID := range personIDs {
+		personID := strings.TrimSpace(rawPersonID)
+		if personID == "" {
+			return nil, errors.Join(domain.ErrValidation, errors.New("holiday target person id must not be empty"))
+		}
+		if _, duplicate := seen[personID]; duplicate {
+			continue
+		}
+		_, err := s.repo.GetPerson(ctx, organisationID, personID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("unknown holiday target person %q", personID))
+		}
+		if err != nil {
+			return nil, err
+		}
+		seen[personID] = struct{}{}
+		resolved = append(resolved, personID)
+	}
+
+	return resolved, nil
+}
+
 // DeleteOrgHoliday deletes an organisation holiday entry.
 func (s *Service) DeleteOrgHoliday(ctx context.Context, auth ports.AuthContext, holidayID string) error {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
@@ -62,19 +290,22 @@ func (s *Service) DeleteOrgHoliday(ctx context.Context, auth ports.AuthContext,
 	if err != nil {
 		return err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
 
 	err = s.repo.DeleteOrgHoliday(ctx, organisationID, holidayID)
 	if err != nil {
 		return err
 	}
 
-	s.telemetry.Record("holiday.deleted", map[string]string{"holiday_id": holidayID})
+	s.recordChange(ctx, organisationID, "holiday.deleted", map[string]string{"holiday_id": holidayID})
 	return nil
 }
 
 // ListGroupUnavailability returns group unavailability entries visible to the caller.
 func (s *Service) ListGroupUnavailability(ctx context.Context, auth ports.AuthContext) ([]domain.GroupUnavailability, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -86,13 +317,16 @@ func (s *Service) ListGroupUnavailability(ctx context.Context, auth ports.AuthCo
 
 // CreateGroupUnavailability validates and creates a group unavailability entry.
 func (s *Service) CreateGroupUnavailability(ctx context.Context, auth ports.AuthContext, input domain.GroupUnavailability) (domain.GroupUnavailability, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
 		return domain.GroupUnavailability{}, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return domain.GroupUnavailability{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.GroupUnavailability{}, err
+	}
 	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
 	if err != nil {
 		return domain.GroupUnavailability{}, err
@@ -101,7 +335,7 @@ func (s *Service) CreateGroupUnavailability(ctx context.Context, auth ports.Auth
 	if err != nil {
 		return domain.GroupUnavailability{}, err
 	}
-	err = validateDateHours(input.Date, input.Hours, organisation.HoursPerDay)
+	err = validateDateHours(input.Date, input.Hours, organisation.HoursPerDayOn(input.Date))
 	if err != nil {
 		return domain.GroupUnavailability{}, err
 	}
@@ -118,32 +352,35 @@ func (s *Service) CreateGroupUnavailability(ctx context.Context, auth ports.Auth
 		return domain.GroupUnavailability{}, err
 	}
 
-	s.telemetry.Record("group_unavailability.created", map[string]string{"entry_id": created.ID})
+	s.recordChange(ctx, organisationID, "group_unavailability.created", map[string]string{"entry_id": created.ID})
 	return created, nil
 }
 
 // DeleteGroupUnavailability deletes a group unavailability entry.
 func (s *Service) DeleteGroupUnavailability(ctx context.Context, auth ports.AuthContext, entryID string) error {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
 		return err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
 
 	err = s.repo.DeleteGroupUnavailability(ctx, organisationID, entryID)
 	if err != nil {
 		return err
 	}
 
-	s.telemetry.Record("group_unavailability.deleted", map[string]string{"entry_id": entryID})
+	s.recordChange(ctx, organisationID, "group_unavailability.deleted", map[string]string{"entry_id": entryID})
 	return nil
 }
 
 // ListPersonUnavailability returns person unavailability entries visible to the caller.
 func (s *Service) ListPersonUnavailability(ctx context.Context, auth ports.AuthContext) ([]domain.PersonUnavailability, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -155,7 +392,7 @@ func (s *Service) ListPersonUnavailability(ctx context.Context, auth ports.AuthC
 
 // ListPersonUnavailabilityByPerson returns unavailability entries for one person.
 func (s *Service) ListPersonUnavailabilityByPerson(ctx context.Context, auth ports.AuthContext, personID string) ([]domain.PersonUnavailability, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -167,13 +404,16 @@ func (s *Service) ListPersonUnavailabilityByPerson(ctx context.Context, auth por
 
 // CreatePersonUnavailability validates and creates a person unavailability entry.
 func (s *Service) CreatePersonUnavailability(ctx context.Context, auth ports.AuthContext, input domain.PersonUnavailability) (domain.PersonUnavailability, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
 		return domain.PersonUnavailability{}, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return domain.PersonUnavailability{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.PersonUnavailability{}, err
+	}
 	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
 	if err != nil {
 		return domain.PersonUnavailability{}, err
@@ -183,11 +423,10 @@ func (s *Service) CreatePersonUnavailability(ctx context.Context, auth ports.Aut
 		return domain.PersonUnavailability{}, err
 	}
 
-	employmentPct, err := domain.EmploymentPctOnDate(person, input.Date)
+	personDailyHours, err := personDailyHoursOn(organisation, person, input.Date)
 	if err != nil {
-		return domain.PersonUnavailability{}, fmt.Errorf("person employment on date: %w", err)
+		return domain.PersonUnavailability{}, err
 	}
-	personDailyHours := organisation.HoursPerDay * employmentPct / 100
 	err = validateDateHours(input.Date, input.Hours, personDailyHours)
 	if err != nil {
 		return domain.PersonUnavailability{}, err
@@ -205,44 +444,98 @@ func (s *Service) CreatePersonUnavailability(ctx context.Context, auth ports.Aut
 		return domain.PersonUnavailability{}, err
 	}
 
-	s.telemetry.Record("person_unavailability.created", map[string]string{"entry_id": created.ID})
+	s.recordChange(ctx, organisationID, "person_unavailability.created", map[string]string{"entry_id": created.ID})
 	return created, nil
 }
 
+// personDailyHoursOn returns the hours a person works on date, scaled by
+// their employment and zero on days off in their work schedule.
+func personDailyHoursOn(organisation domain.Organisation, person domain.Person, date string) (float64, error) {
+	employmentPct, err := domain.EmploymentPctOnDate(person, date)
+	if err != nil {
+		return 0, fmt.Errorf("person employment on date: %w", err)
+	}
+	dailyHours := organisation.HoursPerDayOn(date) * employmentPct / 100
+	if schedule, ok := organisation.WorkSchedule(person.WorkSchedule); ok {
+		day, parseErr := time.Parse(domain.DateLayout, date)
+		if parseErr == nil && !schedule.WorksOn(day) {
+			dailyHours = 0
+		}
+	}
+	return dailyHours, nil
+}
+
 // DeletePersonUnavailability deletes a person unavailability entry.
 func (s *Service) DeletePersonUnavailability(ctx context.Context, auth ports.AuthContext, entryID string) error {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
 		return err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
 
 	err = s.repo.DeletePersonUnavailability(ctx, organisationID, entryID)
 	if err != nil {
 		return err
 	}
 
-	s.telemetry.Record("person_unavailability.deleted", map[string]string{"entry_id": entryID})
+	s.recordChange(ctx, organisationID, "person_unavailability.deleted", map[string]string{"entry_id": entryID})
 	return nil
 }
 
 // DeletePersonUnavailabilityByPerson deletes one person's unavailability entry.
 func (s *Service) DeletePersonUnavailabilityByPerson(ctx context.Context, auth ports.AuthContext, personID, entryID string) error {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
 		return err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
 
 	err = s.repo.DeletePersonUnavailabilityByPerson(ctx, organisationID, personID, entryID)
 	if err != nil {
 		return err
 	}
 
-	s.telemetry.Record("person_unavailability.deleted", map[string]string{"entry_id": entryID})
+	s.recordChange(ctx, organisationID, "person_unavailability.deleted", map[string]string{"entry_id": entryID})
 	return nil
 }
+
+// PurgeCalendarEntries removes holidays and unavailability dated before the cutoff.
+func (s *Service) PurgeCalendarEntries(ctx context.Context, auth ports.AuthContext, before string) (domain.CalendarPurgeResult, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.CalendarPurgeResult{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.CalendarPurgeResult{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.CalendarPurgeResult{}, err
+	}
+	cutoff, err := domain.ValidateDate(strings.TrimSpace(before))
+	if err != nil {
+		return domain.CalendarPurgeResult{}, errors.Join(domain.ErrValidation, errors.New("before must be a date in YYYY-MM-DD format"))
+	}
+
+	result, err := s.repo.PurgeCalendarEntriesBefore(ctx, organisationID, cutoff)
+	if err != nil {
+		return domain.CalendarPurgeResult{}, err
+	}
+
+	s.record(ctx, "calendar.purged", map[string]string{
+		"before":                cutoff,
+		"org_holidays":          strconv.Itoa(result.OrgHolidays),
+		"group_unavailability":  strconv.Itoa(result.GroupUnavailability),
+		"person_unavailability": strconv.Itoa(result.PersonUnavailability),
+	})
+	return result, nil
+}
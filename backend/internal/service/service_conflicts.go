@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// AllocationConflicts lists every stretch between from and to where a
+// person's combined direct and group allocation load exceeds their
+// employment percentage. Conflicts are ordered by person and then by date
+// and rated minor, moderate, or severe with the organisation's overload bands.
+func (s *Service) AllocationConflicts(ctx context.Context, auth ports.AuthContext, from, to string) ([]domain.AllocationConflict, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+		return nil, errors.Join(domain.ErrValidation, errors.New("from_date and to_date are required"))
+	}
+	rangeStart, rangeEnd, err := parseDateRange(from, to)
+	if err != nil {
+		return nil, errors.Join(domain.ErrValidation, errors.New("dates must use YYYY-MM-DD format and end on or after the start"))
+	}
+
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	persons, err := s.repo.ListPersons(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	groupsByID, err := s.listGroupsByID(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make([]domain.AllocationConflict, 0)
+	for _, person := range persons {
+		personConflicts, conflictErr := personAllocationConflicts(person, allocations, groupsByID, organisation.OverloadBands(), rangeStart, rangeEnd)
+		if conflictErr != nil {
+			return nil, conflictErr
+		}
+		conflicts = append(conflicts, personConflicts...)
+	}
+
+	s.record(ctx, "report.allocation_conflicts_generated", map[string]string{"conflicts": strconv.Itoa(len(conflicts))})
+	return conflicts, nil
+}
+
+// personAllocationConflicts returns the overallocated stretches of one
+// person. Neighbouring stretches with the same load, capacity, and
+// allocations are reported as one conflict.
+func personAllocationConflicts(
+	person domain.Person,
+	allocations []domain.Allocation,
+	groupsByID map[string]domain.Group,
+	bands domain.OverloadBands,
+	rangeStart time.Time,
+	rangeEnd time.Time,
+) ([]domain.AllocationConflict, error) {
+	events, err := buildAllocationEvents(allocations, "", person.ID, groupsByID, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	stretches, err := loadStretches(person, events, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []domain.AllocationConflict
+	var previous loadStretch
+	for _, stretch := range stretches {
+		if stretch.loadPct-stretch.capacityPct <= allocationLimitTolerance {
+			continue
+		}
+		allocationIDs := overlappingAllocationIDs(allocations, person.ID, groupsByID, stretch.start, stretch.end)
+		if last := len(conflicts) - 1; last >= 0 && sameLoadStretch(previous, stretch) &&
+			previous.end.AddDate(0, 0, 1).Equal(stretch.start) && slices.Equal(conflicts[last].AllocationIDs, allocationIDs) {
+			conflicts[last].EndDate = stretch.end.Format(domain.DateLayout)
+			previous = stretch
+			continue
+		}
+		conflicts = append(conflicts, domain.AllocationConflict{
+			PersonID:      person.ID,
+			PersonName:    person.Name,
+			StartDate:     stretch.start.Format(domain.DateLayout),
+			EndDate:       stretch.end.Format(domain.DateLayout),
+			LoadPct:       math.Round(stretch.loadPct*100) / 100,
+			CapacityPct:   stretch.capacityPct,
+			ExcessPct:     math.Round((stretch.loadPct-stretch.capacityPct)*100) / 100,
+			Severity:      conflictSeverity(bands, stretch),
+			AllocationIDs: allocationIDs,
+		})
+		previous = stretch
+	}
+	return conflicts, nil
+}
+
+// overlappingAllocationIDs lists, in ID order, the allocations that reach
+// the person on any day from start through end.
+func overlappingAllocationIDs(
+	allocations []domain.Allocation,
+	personID string,
+	groupsByID map[string]domain.Group,
+	start time.Time,
+	end time.Time,
+) []string {
+	ids := make([]string, 0)
+	for _, allocation := range allocations {
+		if !allocationTargetsPerson(allocation, personID, groupsByID) {
+			continue
+		}
+		allocationStart, allocationEnd, err := parseDateRange(allocation.StartDate, allocation.EndDate)
+		if err != nil {
+			continue
+		}
+		if _, _, overlaps := overlapDateRanges(start, end, allocationStart, allocationEnd); overlaps {
+			ids = append(ids, allocation.ID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// conflictSeverity rates an overallocated stretch. A load the bands still
+// count as within capacity is minor, because it exceeded the capacity.
+func conflictSeverity(bands domain.OverloadBands, stretch loadStretch) string {
+	if severity := bands.Classify(stretch.capacityPct, stretch.loadPct); severity != "" {
+		return severity
+	}
+	return domain.OverloadMinor
+}
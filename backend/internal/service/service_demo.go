@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// DemoOrganisationName names the organisation created by SeedDemoTenant.
+const DemoOrganisationName = "Demo Organisation"
+
+type demoGroup struct {
+	name    string
+	members []string
+}
+
+type demoAllocation struct {
+	targetType string
+	target     string
+	project    string
+	percent    float64
+}
+
+// SeedDemoTenant creates a demo organisation with sample people, groups,
+// projects, allocations, and holidays when the store has no organisations.
+// It reports whether seeding ran. All records go through the regular service
+// methods so the usual validation applies. When any record fails, the
+// organisation is deleted again so a later start seeds it from scratch.
+func (s *Service) SeedDemoTenant(ctx context.Context) (bool, error) {
+	organisations, err := s.repo.ListOrganisations(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(organisations) > 0 {
+		return false, nil
+	}
+
+	organisation, err := s.CreateOrganisation(ctx, demoAuth(""), domain.Organisation{
+		Name:         DemoOrganisationName,
+		HoursPerDay:  8,
+		HoursPerWeek: 40,
+		HoursPerYear: 2080,
+	})
+	if err != nil {
+		return false, fmt.Errorf("create demo organisation: %w", err)
+	}
+	if err = s.seedDemoRecords(ctx, demoAuth(organisation.ID)); err != nil {
+		if deleteErr := s.repo.DeleteOrganisation(ctx, organisation.ID); deleteErr != nil {
+			return false, errors.Join(err, fmt.Errorf("remove partial demo organisation: %w", deleteErr))
+		}
+		return false, err
+	}
+
+	s.record(ctx, "demo.seeded", map[string]string{"organisation_id": organisation.ID})
+	return true, nil
+}
+
+func demoAuth(organisationID string) ports.AuthContext {
+	return ports.AuthContext{UserID: "demo-seed", OrganisationID: organisationID, Roles: []string{domain.RoleOrgAdmin}}
+}
+
+func (s *Service) seedDemoRecords(ctx context.Context, auth ports.AuthContext) error {
+	year := strconv.Itoa(s.now().Year())
+
+	personIDs, err := s.seedDemoPersons(ctx, auth)
+	if err != nil {
+		return err
+	}
+	groupIDs, err := s.seedDemoGroups(ctx, auth, personIDs)
+	if err != nil {
+		return err
+	}
+	projects, err := s.seedDemoProjects(ctx, auth, year)
+	if err != nil {
+		return err
+	}
+	targetIDs := map[string]map[string]string{
+		domain.AllocationTargetPerson: personIDs,
+		domain.AllocationTargetGroup:  groupIDs,
+	}
+	if err = s.seedDemoAllocations(ctx, auth, targetIDs, projects); err != nil {
+		return err
+	}
+
+	for _, date := range []string{year + "-01-01", year + "-12-25", year + "-12-26"} {
+		if _, err = s.CreateOrgHoliday(ctx, auth, domain.OrgHoliday{Date: date, Hours: 8}); err != nil {
+			return fmt.Errorf("create demo holiday %s: %w", date, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) seedDemoPersons(ctx context.Context, auth ports.AuthContext) (map[string]string, error) {
+	personIDs := map[string]string{}
+	for _, person := range []domain.Person{
+		{Name: "Ada Lovelace", EmploymentPct: 100},
+		{Name: "Grace Hopper", EmploymentPct: 80},
+		{Name: "Alan Turing", EmploymentPct: 100},
+		{Name: "Katherine Johnson", EmploymentPct: 60},
+	} {
+		created, err := s.CreatePerson(ctx, auth, person)
+		if err != nil {
+			return nil, fmt.Errorf("create demo person %s: %w", person.Name, err)
+		}
+		personIDs[person.Name] = created.ID
+	}
+	return personIDs, nil
+}
+
+func (s *Service) seedDemoGroups(ctx context.Context, auth ports.AuthContext, personIDs map[string]string) (map[string]string, error) {
+	groupIDs := map[string]string{}
+	for _, group := range []demoGroup{
+		{name: "Platform", members: []string{"Ada Lovelace", "Alan Turing"}},
+		{name: "Research", members: []string{"Grace Hopper", "Katherine Johnson"}},
+	} {
+		memberIDs := make([]string, 0, len(group.members))
+		for _, member := range group.members {
+			memberIDs = append(memberIDs, personIDs[member])
+		}
+		created, err := s.CreateGroup(ctx, auth, domain.Group{Name: group.name, MemberIDs: memberIDs})
+		if err != nil {
+			return nil, fmt.Errorf("create demo group %s: %w", group.name, err)
+		}
+		groupIDs[group.name] = created.ID
+	}
+	return groupIDs, nil
+}
+
+func (s *Service) seedDemoProjects(ctx context.Context, auth ports.AuthContext, year string) (map[string]domain.Project, error) {
+	projects := map[string]domain.Project{}
+	for _, project := range []domain.Project{
+		{Name: "Apollo", StartDate: year + "-01-01", EndDate: year + "-12-31", EstimatedEffortHours: 3000},
+		{Name: "Hermes", StartDate: year + "-03-01", EndDate: year + "-09-30", EstimatedEffortHours: 1200},
+	} {
+		created, err := s.CreateProject(ctx, auth, project)
+		if err != nil {
+			return nil, fmt.Errorf("create demo project %s: %w", project.Name, err)
+		}
+		projects[project.Name] = created
+	}
+	return projects, nil
+}
+
+func (s *Service) seedDemoAllocations(
+	ctx context.Context,
+	auth ports.AuthContext,
+	targetIDs map[string]map[string]string,
+	projects map[string]domain.Project,
+) error {
+	for _, allocation := range []demoAllocation{
+		{targetType: domain.AllocationTargetGroup, target: "Platform", project: "Apollo", percent: 50},
+		{targetType: domain.AllocationTargetPerson, target: "Grace Hopper", project: "Hermes", percent: 40},
+		{targetType: domain.AllocationTargetPerson, target: "Katherine Johnson", project: "Apollo", percent: 30},
+	} {
+		project := projects[allocation.project]
+		_, err := s.CreateAllocation(ctx, auth, domain.Allocation{
+			TargetType: allocation.targetType,
+			TargetID:   targetIDs[allocation.targetType][allocation.target],
+			ProjectID:  project.ID,
+			StartDate:  project.StartDate,
+			EndDate:    project.EndDate,
+			Percent:    allocation.percent,
+		})
+		if err != nil {
+			return fmt.Errorf("create demo allocation for %s: %w", allocation.target, err)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"plato/backend/internal/domain"
@@ -10,7 +12,7 @@ import (
 
 // ListGroups returns the groups visible to the caller within their organisation.
 func (s *Service) ListGroups(ctx context.Context, auth ports.AuthContext) ([]domain.Group, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -20,9 +22,22 @@ func (s *Service) ListGroups(ctx context.Context, auth ports.AuthContext) ([]dom
 	return s.repo.ListGroups(ctx, organisationID)
 }
 
+// ListGroupsPage returns one filtered and sorted page of the groups visible to
+// the caller within their organisation.
+func (s *Service) ListGroupsPage(ctx context.Context, auth ports.AuthContext, query domain.ListQuery) (domain.ListPage[domain.Group], error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.ListPage[domain.Group]{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.ListPage[domain.Group]{}, err
+	}
+	return s.repo.ListGroupsPage(ctx, organisationID, query)
+}
+
 // GetGroup returns one group from the caller's organisation.
 func (s *Service) GetGroup(ctx context.Context, auth ports.AuthContext, groupID string) (domain.Group, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return domain.Group{}, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -32,6 +47,57 @@ func (s *Service) GetGroup(ctx context.Context, auth ports.AuthContext, groupID
 	return s.repo.GetGroup(ctx, organisationID, groupID)
 }
 
+// ListGroupAllocations returns the active allocations that target the group
+// directly. With resolveMembers set it also returns every active allocation
+// that reaches one of the group's members, whether it targets the person or
+// another group they belong to.
+func (s *Service) ListGroupAllocations(ctx context.Context, auth ports.AuthContext, groupID string, resolveMembers bool) ([]domain.Allocation, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.repo.GetGroup(ctx, organisationID, groupID); err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	var memberIDs []string
+	var groupsByID map[string]domain.Group
+	if resolveMembers {
+		memberIDs, err = s.resolveAllocationTargetPersons(ctx, organisationID, domain.AllocationTargetGroup, groupID)
+		// A group without members resolves to a validation error, which
+		// here only means there are no member commitments to add.
+		if err != nil && !errors.Is(err, domain.ErrValidation) {
+			return nil, err
+		}
+		if groupsByID, err = s.listGroupsByID(ctx, organisationID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]domain.Allocation, 0)
+	for _, allocation := range allocations {
+		targetType, targetID := normalizedAllocationTarget(allocation)
+		if targetType == domain.AllocationTargetGroup && targetID == groupID {
+			result = append(result, allocation)
+			continue
+		}
+		for _, memberID := range memberIDs {
+			if allocationTargetsPerson(allocation, memberID, groupsByID) {
+				result = append(result, allocation)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
 // CreateGroup validates and creates a group in the caller's organisation.
 func (s *Service) CreateGroup(ctx context.Context, auth ports.AuthContext, input domain.Group) (domain.Group, error) {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
@@ -41,6 +107,9 @@ func (s *Service) CreateGroup(ctx context.Context, auth ports.AuthContext, input
 	if err != nil {
 		return domain.Group{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Group{}, err
+	}
 	err = validateGroup(input)
 	if err != nil {
 		return domain.Group{}, err
@@ -49,11 +118,20 @@ func (s *Service) CreateGroup(ctx context.Context, auth ports.AuthContext, input
 	if err != nil {
 		return domain.Group{}, err
 	}
+	groupsByID, err := s.listGroupsByID(ctx, organisationID)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	err = validateSubGroups(input.SubGroupIDs, "", groupsByID)
+	if err != nil {
+		return domain.Group{}, err
+	}
 
 	group := domain.Group{
 		OrganisationID: organisationID,
 		Name:           strings.TrimSpace(input.Name),
 		MemberIDs:      input.MemberIDs,
+		SubGroupIDs:    input.SubGroupIDs,
 	}
 
 	created, err := s.repo.CreateGroup(ctx, group)
@@ -61,7 +139,7 @@ func (s *Service) CreateGroup(ctx context.Context, auth ports.AuthContext, input
 		return domain.Group{}, err
 	}
 
-	s.telemetry.Record("group.created", map[string]string{"group_id": created.ID})
+	s.recordChange(ctx, organisationID, "group.created", map[string]string{"group_id": created.ID})
 	return created, nil
 }
 
@@ -74,6 +152,9 @@ func (s *Service) UpdateGroup(ctx context.Context, auth ports.AuthContext, group
 	if err != nil {
 		return domain.Group{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Group{}, err
+	}
 	err = validateGroup(input)
 	if err != nil {
 		return domain.Group{}, err
@@ -83,24 +164,53 @@ func (s *Service) UpdateGroup(ctx context.Context, auth ports.AuthContext, group
 		return domain.Group{}, err
 	}
 
-	group, err := s.repo.GetGroup(ctx, organisationID, groupID)
+	groupsByID, err := s.listGroupsByID(ctx, organisationID)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	group, ok := groupsByID[groupID]
+	if !ok {
+		return domain.Group{}, domain.ErrNotFound
+	}
+	if err = requireCurrentVersion("group", input.Version, group.Version); err != nil {
+		return domain.Group{}, err
+	}
+	err = validateSubGroups(input.SubGroupIDs, groupID, groupsByID)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	previousMemberIDs, err := domain.GroupPersonIDs(groupID, groupsByID)
 	if err != nil {
 		return domain.Group{}, err
 	}
 	group.Name = strings.TrimSpace(input.Name)
 	group.MemberIDs = input.MemberIDs
+	group.SubGroupIDs = input.SubGroupIDs
+	groupsByID[groupID] = group
+	nextMemberIDs, err := domain.GroupPersonIDs(groupID, groupsByID)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	addedMemberIDs := addedGroupMembers(previousMemberIDs, nextMemberIDs)
+	err = s.validateMembershipAllocationLimit(ctx, organisationID, group, addedMemberIDs)
+	if err != nil {
+		return domain.Group{}, err
+	}
 
 	updated, err := s.repo.UpdateGroup(ctx, group)
 	if err != nil {
 		return domain.Group{}, err
 	}
 
-	s.telemetry.Record("group.updated", map[string]string{"group_id": updated.ID})
+	s.recordChange(ctx, organisationID, "group.updated", map[string]string{"group_id": updated.ID})
 	return updated, nil
 }
 
-// DeleteGroup deletes a group from the caller's organisation.
-func (s *Service) DeleteGroup(ctx context.Context, auth ports.AuthContext, groupID string) error {
+// DeleteGroup deletes a group from the caller's organisation. It fails with a
+// DependentsError while allocations or unavailability entries still target
+// the group, unless cascade is set to delete them too. Sub-group links in
+// other groups are always cleaned up.
+func (s *Service) DeleteGroup(ctx context.Context, auth ports.AuthContext, groupID string, cascade bool) error {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
 		return err
 	}
@@ -108,13 +218,16 @@ func (s *Service) DeleteGroup(ctx context.Context, auth ports.AuthContext, group
 	if err != nil {
 		return err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
 
-	err = s.repo.DeleteGroup(ctx, organisationID, groupID)
+	err = s.repo.DeleteGroup(s.withAllocationAudit(ctx, auth), organisationID, groupID, cascade)
 	if err != nil {
 		return err
 	}
 
-	s.telemetry.Record("group.deleted", map[string]string{"group_id": groupID})
+	s.recordChange(ctx, organisationID, "group.deleted", map[string]string{"group_id": groupID})
 	return nil
 }
 
@@ -127,6 +240,9 @@ func (s *Service) AddGroupMember(ctx context.Context, auth ports.AuthContext, gr
 	if err != nil {
 		return domain.Group{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Group{}, err
+	}
 	_, err = s.repo.GetPerson(ctx, organisationID, personID)
 	if err != nil {
 		return domain.Group{}, err
@@ -142,6 +258,10 @@ func (s *Service) AddGroupMember(ctx context.Context, auth ports.AuthContext, gr
 		}
 	}
 	group.MemberIDs = append(group.MemberIDs, personID)
+	err = s.validateMembershipAllocationLimit(ctx, organisationID, group, []string{personID})
+	if err != nil {
+		return domain.Group{}, err
+	}
 	return s.repo.UpdateGroup(ctx, group)
 }
 
@@ -154,6 +274,9 @@ func (s *Service) RemoveGroupMember(ctx context.Context, auth ports.AuthContext,
 	if err != nil {
 		return domain.Group{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Group{}, err
+	}
 
 	group, err := s.repo.GetGroup(ctx, organisationID, groupID)
 	if err != nil {
@@ -177,3 +300,72 @@ func (s *Service) ensureMembersBelongToOrg(ctx context.Context, organisationID s
 	}
 	return nil
 }
+
+// validateMembershipAllocationLimit checks that newly added members stay within
+// the daily allocation limit once the group's allocations apply to them.
+// The check only runs when the organisation opts in.
+func (s *Service) validateMembershipAllocationLimit(ctx context.Context, organisationID string, group domain.Group, addedMemberIDs []string) error {
+	if len(addedMemberIDs) == 0 {
+		return nil
+	}
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+	if !organisation.EnforceMembershipAllocationLimit {
+		return nil
+	}
+
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+	groupsByID, err := s.listGroupsByID(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+	groupsByID[group.ID] = group
+	maxPercentPerDay, err := s.maxAllocationPercentPerDay(ctx, organisationID)
+	if err != nil {
+		return err
+	}
+
+	for _, personID := range uniqueStringIDs(addedMemberIDs) {
+		exceeded, limitErr := personExceedsAllocationLimit(allocations, personID, groupsByID, maxPercentPerDay)
+		if limitErr != nil {
+			return limitErr
+		}
+		if exceeded {
+			return fmt.Errorf("adding person %s to group exceeds 24 hours/day theoretical limit: %w", personID, domain.ErrValidation)
+		}
+	}
+	return nil
+}
+
+// validateSubGroups rejects self references and subgroups outside the
+// organisation. Cycles surface when the updated group is flattened.
+func validateSubGroups(subGroupIDs []string, groupID string, groupsByID map[string]domain.Group) error {
+	for _, subGroupID := range subGroupIDs {
+		if subGroupID == groupID {
+			return fmt.Errorf("group %s cannot contain itself: %w", groupID, domain.ErrValidation)
+		}
+		if _, ok := groupsByID[subGroupID]; !ok {
+			return fmt.Errorf("subgroup %s does not exist: %w", subGroupID, domain.ErrValidation)
+		}
+	}
+	return nil
+}
+
+func addedGroupMembers(currentMemberIDs, nextMemberIDs []string) []string {
+	current := make(map[string]bool, len(currentMemberIDs))
+	for _, memberID := range currentMemberIDs {
+		current[memberID] = true
+	}
+	added := make([]string, 0)
+	for _, memberID := range nextMemberIDs {
+		if !current[memberID] {
+			added = append(added, memberID)
+		}
+	}
+	return added
+}
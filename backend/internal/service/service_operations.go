@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// maxOperationHistory caps how many recent operations are kept per
+// organisation. The oldest one is dropped when a new one arrives.
+const maxOperationHistory = 50
+
+// UndoLastOperation reverses the caller's most recent allocation create,
+// update, end, or delete that is still in the history. A created allocation
+// is deleted, an updated or ended one gets its previous values back, and a
+// deleted one is stored again under its old ID. Reverted and recreated
+// allocations pass the same checks as a create. The undo fails with a
+// conflict when the allocation changed since the operation, and an operation
+// that cannot be undone is dropped from the history.
+func (s *Service) UndoLastOperation(ctx context.Context, auth ports.AuthContext) (domain.UndoResult, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return domain.UndoResult{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.UndoResult{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.UndoResult{}, err
+	}
+	entry, ok := s.history.popLatest(organisationID, auth.UserID)
+	if !ok {
+		return domain.UndoResult{}, fmt.Errorf("no operation to undo: %w", domain.ErrNotFound)
+	}
+
+	result, err := s.undoOperation(ctx, auth, organisationID, entry)
+	if err != nil {
+		if !errors.Is(err, domain.ErrConflict) && !errors.Is(err, domain.ErrValidation) && !errors.Is(err, domain.ErrNotFound) {
+			s.history.push(organisationID, entry)
+		}
+		return domain.UndoResult{}, err
+	}
+
+	if result.Allocation != nil {
+		s.history.rebase(organisationID, *result.Allocation)
+	}
+	s.record(ctx, "operation.undone", map[string]string{
+		"kind":          entry.operation.Kind,
+		"allocation_id": entry.operation.AllocationID,
+	})
+	return result, nil
+}
+
+func (s *Service) undoOperation(ctx context.Context, auth ports.AuthContext, organisationID string, entry operationEntry) (domain.UndoResult, error) {
+	result := domain.UndoResult{Operation: entry.operation}
+	allocationID := entry.operation.AllocationID
+
+	if entry.operation.Kind == domain.OperationAllocationDeleted {
+		if _, err := s.prepareAllocation(ctx, organisationID, *entry.before, "", true); err != nil {
+			return domain.UndoResult{}, err
+		}
+		restored, err := s.repo.RestoreAllocation(s.withAllocationAudit(ctx, auth), *entry.before)
+		if err != nil {
+			return domain.UndoResult{}, err
+		}
+		s.recordChange(ctx, organisationID, "allocation.created", map[string]string{"allocation_id": restored.ID})
+		result.Allocation = &restored
+		return result, nil
+	}
+
+	current, err := s.repo.GetAllocation(ctx, organisationID, allocationID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.UndoResult{}, fmt.Errorf("allocation %s no longer exists: %w", allocationID, domain.ErrConflict)
+	}
+	if err != nil {
+		return domain.UndoResult{}, err
+	}
+	if err = requireCurrentVersion("allocation", entry.after.Version, current.Version); err != nil {
+		return domain.UndoResult{}, err
+	}
+
+	if entry.operation.Kind == domain.OperationAllocationCreated {
+		if err = s.repo.DeleteAllocation(s.withAllocationAudit(ctx, auth), organisationID, allocationID); err != nil {
+			return domain.UndoResult{}, err
+		}
+		s.recordChange(ctx, organisationID, "allocation.deleted", map[string]string{"allocation_id": allocationID})
+		return result, nil
+	}
+
+	if _, err = s.prepareAllocation(ctx, organisationID, *entry.before, allocationID, true); err != nil {
+		return domain.UndoResult{}, err
+	}
+	reverted := *entry.before
+	reverted.Version = current.Version
+	updated, err := s.repo.UpdateAllocation(s.withAllocationAudit(ctx, auth), reverted)
+	if err != nil {
+		return domain.UndoResult{}, err
+	}
+	s.recordChange(ctx, organisationID, "allocation.updated", map[string]string{"allocation_id": allocationID})
+	result.Allocation = &updated
+	return result, nil
+}
+
+// rememberAllocationOperation adds an allocation change to the undo history.
+// before is nil for a create and after is nil for a delete.
+func (s *Service) rememberAllocationOperation(auth ports.AuthContext, kind string, before, after *domain.Allocation) {
+	entry := operationEntry{
+		operation: domain.Operation{Kind: kind, ActorID: auth.UserID, OccurredAt: s.now().UTC()},
+	}
+	if before != nil {
+		stored := *before
+		stored.Warnings = nil
+		entry.before = &stored
+		entry.operation.AllocationID = stored.ID
+	}
+	if after != nil {
+		stored := *after
+		stored.Warnings = nil
+		entry.after = &stored
+		entry.operation.AllocationID = stored.ID
+	}
+	s.history.push(auth.OrganisationID, entry)
+}
+
+// operationHistory keeps the recent operations of each organisation in the
+// order they happened. It lives in memory, so a restart clears it.
+type operationHistory struct {
+	mu      sync.Mutex
+	entries map[string][]operationEntry
+}
+
+// operationEntry holds an operation with the allocation before and after it.
+type operationEntry struct {
+	operation domain.Operation
+	before    *domain.Allocation
+	after     *domain.Allocation
+}
+
+func newOperationHistory() *operationHistory {
+	return &operationHistory{entries: map[string][]operationEntry{}}
+}
+
+func (h *operationHistory) push(organisationID string, entry operationEntry) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := append(h.entries[organisationID], entry)
+	if len(entries) > maxOperationHistory {
+		entries = entries[len(entries)-maxOperationHistory:]
+	}
+	h.entries[organisationID] = entries
+}
+
+// rebase points the newest remaining operation on an allocation at the
+// allocation an undo just stored. The undo brought back the state that
+// operation left, only under a newer version, so it can be undone next.
+func (h *operationHistory) rebase(organisationID string, allocation domain.Allocation) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := h.entries[organisationID]
+	for index := len(entries) - 1; index >= 0; index-- {
+		if entries[index].operation.AllocationID != allocation.ID {
+			continue
+		}
+		if entries[index].after != nil {
+			entries[index].after = &allocation
+		}
+		return
+	}
+}
+
+// popLatest removes and returns the newest operation of one actor.
+func (h *operationHistory) popLatest(organisationID, actorID string) (operationEntry, bool) {
+	if h == nil {
+		return operationEntry{}, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := h.entries[organisationID]
+	for index := len(entries) - 1; index >= 0; index-- {
+		if entries[index].operation.ActorID != actorID {
+			continue
+		}
+		entry := entries[index]
+		h.entries[organisationID] = append(entries[:index:index], entries[index+1:]...)
+		return entry, true
+	}
+	return operationEntry{}, false
+}
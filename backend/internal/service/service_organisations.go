@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"plato/backend/internal/domain"
 	"plato/backend/internal/ports"
@@ -10,7 +13,7 @@ import (
 
 // ListOrganisations returns the organisations visible to the caller.
 func (s *Service) ListOrganisations(ctx context.Context, auth ports.AuthContext) ([]domain.Organisation, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 
@@ -32,9 +35,40 @@ func (s *Service) ListOrganisations(ctx context.Context, auth ports.AuthContext)
 	return []domain.Organisation{}, nil
 }
 
+// maxOrganisationSearchLength caps the organisation name search query in characters.
+const maxOrganisationSearchLength = 100
+
+// SearchOrganisations returns the organisations visible to the caller whose
+// name contains query, ignoring case. Callers without a tenant search every
+// organisation, while tenant callers only ever see their own. An empty query
+// matches every visible organisation.
+func (s *Service) SearchOrganisations(ctx context.Context, auth ports.AuthContext, query string) ([]domain.Organisation, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) > maxOrganisationSearchLength {
+		return nil, fmt.Errorf("q must be at most %d characters: %w", maxOrganisationSearchLength, domain.ErrValidation)
+	}
+
+	organisations, err := s.ListOrganisations(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+	if query == "" {
+		return organisations, nil
+	}
+
+	needle := strings.ToLower(query)
+	matches := make([]domain.Organisation, 0, len(organisations))
+	for _, organisation := range organisations {
+		if strings.Contains(strings.ToLower(organisation.Name), needle) {
+			matches = append(matches, organisation)
+		}
+	}
+	return matches, nil
+}
+
 // GetOrganisation returns one organisation after tenant checks pass.
 func (s *Service) GetOrganisation(ctx context.Context, auth ports.AuthContext, organisationID string) (domain.Organisation, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return domain.Organisation{}, err
 	}
 	if err := enforceTenant(auth, organisationID); err != nil {
@@ -57,18 +91,36 @@ func (s *Service) CreateOrganisation(ctx context.Context, auth ports.AuthContext
 	if err := validateOrganisation(input); err != nil {
 		return domain.Organisation{}, err
 	}
+	if err := s.requireUniqueOrganisationName(ctx, input.Name, ""); err != nil {
+		return domain.Organisation{}, err
+	}
 
 	created, err := s.repo.CreateOrganisation(ctx, domain.Organisation{
 		Name:         strings.TrimSpace(input.Name),
 		HoursPerDay:  input.HoursPerDay,
 		HoursPerWeek: input.HoursPerWeek,
 		HoursPerYear: input.HoursPerYear,
+
+		EnforceMembershipAllocationLimit:   input.EnforceMembershipAllocationLimit,
+		SnapAllocationDatesTo:              strings.TrimSpace(input.SnapAllocationDatesTo),
+		RequireEmploymentForAllocations:    input.RequireEmploymentForAllocations,
+		RejectAllocationsOverProjectEffort: input.RejectAllocationsOverProjectEffort,
+		RejectOverEmployment:               input.RejectOverEmployment,
+		EndAllocationsOnZeroEmployment:     input.EndAllocationsOnZeroEmployment,
+		AllocationPercentStep:              input.AllocationPercentStep,
+		AllocationPercentStepMode:          strings.TrimSpace(input.AllocationPercentStepMode),
+		WriteWindow:                        normalizedWriteWindow(input.WriteWindow),
+		WorkSchedules:                      normalizedWorkSchedules(input.WorkSchedules),
+		HoursBaselines:                     normalizedHoursBaselines(input.HoursBaselines),
+		Timezone:                           strings.TrimSpace(input.Timezone),
+		OverloadModeratePct:                input.OverloadModeratePct,
+		OverloadSeverePct:                  input.OverloadSeverePct,
 	})
 	if err != nil {
 		return domain.Organisation{}, err
 	}
 
-	s.telemetry.Record("organisation.created", map[string]string{"organisation_id": created.ID})
+	s.record(ctx, "organisation.created", map[string]string{"organisation_id": created.ID})
 	return created, nil
 }
 
@@ -83,26 +135,128 @@ func (s *Service) UpdateOrganisation(ctx context.Context, auth ports.AuthContext
 	if err := validateOrganisation(input); err != nil {
 		return domain.Organisation{}, err
 	}
+	if err := s.requireUniqueOrganisationName(ctx, input.Name, organisationID); err != nil {
+		return domain.Organisation{}, err
+	}
 
 	current, err := s.repo.GetOrganisation(ctx, organisationID)
 	if err != nil {
 		return domain.Organisation{}, err
 	}
+	if err = requireCurrentVersion("organisation", input.Version, current.Version); err != nil {
+		return domain.Organisation{}, err
+	}
+	// A read-only organisation only accepts the update that lifts the flag.
+	if current.ReadOnly && input.ReadOnly {
+		return domain.Organisation{}, domain.ErrReadOnly
+	}
 
 	current.Name = strings.TrimSpace(input.Name)
 	current.HoursPerDay = input.HoursPerDay
 	current.HoursPerWeek = input.HoursPerWeek
 	current.HoursPerYear = input.HoursPerYear
+	current.EnforceMembershipAllocationLimit = input.EnforceMembershipAllocationLimit
+	current.SnapAllocationDatesTo = strings.TrimSpace(input.SnapAllocationDatesTo)
+	current.RequireEmploymentForAllocations = input.RequireEmploymentForAllocations
+	current.RejectAllocationsOverProjectEffort = input.RejectAllocationsOverProjectEffort
+	current.RejectOverEmployment = input.RejectOverEmployment
+	current.EndAllocationsOnZeroEmployment = input.EndAllocationsOnZeroEmployment
+	current.AllocationPercentStep = input.AllocationPercentStep
+	current.AllocationPercentStepMode = strings.TrimSpace(input.AllocationPercentStepMode)
+	current.Timezone = strings.TrimSpace(input.Timezone)
+	current.OverloadModeratePct = input.OverloadModeratePct
+	current.OverloadSeverePct = input.OverloadSeverePct
+	current.ReadOnly = input.ReadOnly
+	current.WriteWindow = normalizedWriteWindow(input.WriteWindow)
+	current.WorkSchedules = normalizedWorkSchedules(input.WorkSchedules)
+	current.HoursBaselines = normalizedHoursBaselines(input.HoursBaselines)
+	if err = s.requireAssignedWorkSchedules(ctx, current); err != nil {
+		return domain.Organisation{}, err
+	}
 
 	updated, err := s.repo.UpdateOrganisation(ctx, current)
 	if err != nil {
 		return domain.Organisation{}, err
 	}
 
-	s.telemetry.Record("organisation.updated", map[string]string{"organisation_id": updated.ID})
+	s.recordChange(ctx, organisationID, "organisation.updated", map[string]string{"organisation_id": updated.ID})
 	return updated, nil
 }
 
+// requireUniqueOrganisationName rejects a name already used by an
+// organisation other than excludeID when unique names are enabled. Names
+// match ignoring case and surrounding spaces, across all tenants.
+func (s *Service) requireUniqueOrganisationName(ctx context.Context, name, excludeID string) error {
+	if !s.uniqueOrganisationNames {
+		return nil
+	}
+
+	organisations, err := s.repo.ListOrganisations(ctx)
+	if err != nil {
+		return err
+	}
+	name = strings.TrimSpace(name)
+	for _, organisation := range organisations {
+		if organisation.ID != excludeID && strings.EqualFold(strings.TrimSpace(organisation.Name), name) {
+			return fmt.Errorf("organisation name %q is already in use: %w", name, domain.ErrValidation)
+		}
+	}
+	return nil
+}
+
+func normalizedWriteWindow(window *domain.WriteWindow) *domain.WriteWindow {
+	if window == nil {
+		return nil
+	}
+	normalized := window.Normalized()
+	return &normalized
+}
+
+func normalizedWorkSchedules(schedules []domain.WorkSchedule) []domain.WorkSchedule {
+	if len(schedules) == 0 {
+		return nil
+	}
+	normalized := make([]domain.WorkSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		normalized = append(normalized, schedule.Normalized())
+	}
+	return normalized
+}
+
+// normalizedHoursBaselines returns the baselines normalized and ordered by
+// start date.
+func normalizedHoursBaselines(baselines []domain.HoursBaseline) []domain.HoursBaseline {
+	if len(baselines) == 0 {
+		return nil
+	}
+	normalized := make([]domain.HoursBaseline, 0, len(baselines))
+	for _, baseline := range baselines {
+		normalized = append(normalized, baseline.Normalized())
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return normalized[i].StartDate < normalized[j].StartDate
+	})
+	return normalized
+}
+
+// requireAssignedWorkSchedules rejects an organisation update that removes or
+// renames a work schedule still assigned to a person.
+func (s *Service) requireAssignedWorkSchedules(ctx context.Context, organisation domain.Organisation) error {
+	persons, err := s.repo.ListPersons(ctx, organisation.ID)
+	if err != nil {
+		return err
+	}
+	for _, person := range persons {
+		if person.WorkSchedule == "" {
+			continue
+		}
+		if _, ok := organisation.WorkSchedule(person.WorkSchedule); !ok {
+			return fmt.Errorf("work schedule %q is still assigned to person %s: %w", person.WorkSchedule, person.ID, domain.ErrValidation)
+		}
+	}
+	return nil
+}
+
 // DeleteOrganisation deletes an organisation after tenant checks pass.
 func (s *Service) DeleteOrganisation(ctx context.Context, auth ports.AuthContext, organisationID string) error {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
@@ -111,11 +265,14 @@ func (s *Service) DeleteOrganisation(ctx context.Context, auth ports.AuthContext
 	if err := enforceTenant(auth, organisationID); err != nil {
 		return err
 	}
+	if err := s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
 
 	if err := s.repo.DeleteOrganisation(ctx, organisationID); err != nil {
 		return err
 	}
 
-	s.telemetry.Record("organisation.deleted", map[string]string{"organisation_id": organisationID})
+	s.record(ctx, "organisation.deleted", map[string]string{"organisation_id": organisationID})
 	return nil
 }
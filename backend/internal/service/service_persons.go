@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"plato/backend/internal/domain"
 	"plato/backend/internal/ports"
@@ -12,7 +14,7 @@ import (
 
 // ListPersons returns the people visible to the caller within their organisation.
 func (s *Service) ListPersons(ctx context.Context, auth ports.AuthContext) ([]domain.Person, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -22,9 +24,22 @@ func (s *Service) ListPersons(ctx context.Context, auth ports.AuthContext) ([]do
 	return s.repo.ListPersons(ctx, organisationID)
 }
 
+// ListPersonsPage returns one filtered and sorted page of the people visible to
+// the caller within their organisation.
+func (s *Service) ListPersonsPage(ctx context.Context, auth ports.AuthContext, query domain.ListQuery) (domain.ListPage[domain.Person], error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.ListPage[domain.Person]{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.ListPage[domain.Person]{}, err
+	}
+	return s.repo.ListPersonsPage(ctx, organisationID, query)
+}
+
 // GetPerson returns one person from the caller's organisation.
 func (s *Service) GetPerson(ctx context.Context, auth ports.AuthContext, personID string) (domain.Person, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return domain.Person{}, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -34,6 +49,49 @@ func (s *Service) GetPerson(ctx context.Context, auth ports.AuthContext, personI
 	return s.repo.GetPerson(ctx, organisationID, personID)
 }
 
+// maxBatchPersonIDs caps how many IDs one batch lookup may request.
+const maxBatchPersonIDs = 200
+
+// GetPersonsByIDs returns the requested people from the caller's organisation.
+// IDs that do not exist in the organisation are reported as missing.
+func (s *Service) GetPersonsByIDs(ctx context.Context, auth ports.AuthContext, personIDs []string) (domain.PersonBatch, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.PersonBatch{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.PersonBatch{}, err
+	}
+
+	trimmedIDs := make([]string, 0, len(personIDs))
+	for _, personID := range personIDs {
+		if trimmed := strings.TrimSpace(personID); trimmed != "" {
+			trimmedIDs = append(trimmedIDs, trimmed)
+		}
+	}
+	uniqueIDs := uniqueStringIDs(trimmedIDs)
+	if len(uniqueIDs) == 0 {
+		return domain.PersonBatch{}, errors.Join(domain.ErrValidation, errors.New("ids must contain at least one person id"))
+	}
+	if len(uniqueIDs) > maxBatchPersonIDs {
+		return domain.PersonBatch{}, errors.Join(domain.ErrValidation, fmt.Errorf("ids must not contain more than %d person ids", maxBatchPersonIDs))
+	}
+
+	batch := domain.PersonBatch{Persons: []domain.Person{}, MissingIDs: []string{}}
+	for _, personID := range uniqueIDs {
+		person, getErr := s.repo.GetPerson(ctx, organisationID, personID)
+		if errors.Is(getErr, domain.ErrNotFound) {
+			batch.MissingIDs = append(batch.MissingIDs, personID)
+			continue
+		}
+		if getErr != nil {
+			return domain.PersonBatch{}, getErr
+		}
+		batch.Persons = append(batch.Persons, person)
+	}
+	return batch, nil
+}
+
 // CreatePerson validates and creates a person in the caller's organisation.
 func (s *Service) CreatePerson(ctx context.Context, auth ports.AuthContext, input domain.Person) (domain.Person, error) {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
@@ -43,30 +101,52 @@ func (s *Service) CreatePerson(ctx context.Context, auth ports.AuthContext, inpu
 	if err != nil {
 		return domain.Person{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Person{}, err
+	}
 	err = validatePerson(input)
 	if err != nil {
 		return domain.Person{}, err
 	}
-	if _, getOrgErr := s.repo.GetOrganisation(ctx, organisationID); getOrgErr != nil {
-		return domain.Person{}, getOrgErr
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return domain.Person{}, err
+	}
+	workSchedule, err := resolveWorkSchedule(organisation, input.WorkSchedule)
+	if err != nil {
+		return domain.Person{}, err
 	}
-
 	person := domain.Person{
 		OrganisationID:               organisationID,
 		Name:                         strings.TrimSpace(input.Name),
 		EmploymentPct:                input.EmploymentPct,
 		EmploymentEffectiveFromMonth: "",
+		WorkSchedule:                 workSchedule,
 	}
 
-	created, err := s.repo.CreatePerson(ctx, person)
+	created, err := s.repo.CreatePersonWithLimit(ctx, person, s.quotas.MaxPersonsPerOrganisation)
 	if err != nil {
 		return domain.Person{}, err
 	}
 
-	s.telemetry.Record("person.created", map[string]string{"person_id": created.ID})
+	s.recordChange(ctx, organisationID, "person.created", map[string]string{"person_id": created.ID})
 	return created, nil
 }
 
+// resolveWorkSchedule returns the stored name of the organisation's work
+// schedule matching name. An empty name assigns no schedule.
+func resolveWorkSchedule(organisation domain.Organisation, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	schedule, ok := organisation.WorkSchedule(name)
+	if !ok {
+		return "", fmt.Errorf("work schedule %q is not defined for the organisation: %w", name, domain.ErrValidation)
+	}
+	return schedule.Name, nil
+}
+
 // UpdatePerson validates and updates a person in the caller's organisation.
 func (s *Service) UpdatePerson(ctx context.Context, auth ports.AuthContext, personID string, input domain.Person) (domain.Person, error) {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
@@ -76,6 +156,9 @@ func (s *Service) UpdatePerson(ctx context.Context, auth ports.AuthContext, pers
 	if err != nil {
 		return domain.Person{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Person{}, err
+	}
 	err = validatePerson(input)
 	if err != nil {
 		return domain.Person{}, err
@@ -85,6 +168,17 @@ func (s *Service) UpdatePerson(ctx context.Context, auth ports.AuthContext, pers
 	if err != nil {
 		return domain.Person{}, err
 	}
+	if err = requireCurrentVersion("person", input.Version, person.Version); err != nil {
+		return domain.Person{}, err
+	}
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return domain.Person{}, err
+	}
+	person.WorkSchedule, err = resolveWorkSchedule(organisation, input.WorkSchedule)
+	if err != nil {
+		return domain.Person{}, err
+	}
 	person.Name = strings.TrimSpace(input.Name)
 	effectiveFromMonth := strings.TrimSpace(input.EmploymentEffectiveFromMonth)
 	if effectiveFromMonth == "" {
@@ -102,17 +196,81 @@ func (s *Service) UpdatePerson(ctx context.Context, auth ports.AuthContext, pers
 		return domain.Person{}, err
 	}
 
+	if organisation.EndAllocationsOnZeroEmployment && effectiveFromMonth != "" && employmentEndsFrom(person, effectiveFromMonth) {
+		return s.updatePersonEndingAllocations(ctx, auth, person, effectiveFromMonth)
+	}
+
 	updated, err := s.repo.UpdatePerson(ctx, person)
 	if err != nil {
 		return domain.Person{}, err
 	}
 
-	s.telemetry.Record("person.updated", map[string]string{"person_id": updated.ID})
+	s.recordChange(ctx, organisationID, "person.updated", map[string]string{"person_id": updated.ID})
 	return updated, nil
 }
 
-// DeletePerson deletes a person from the caller's organisation.
-func (s *Service) DeletePerson(ctx context.Context, auth ports.AuthContext, personID string) error {
+// employmentEndReason is the end reason stored on allocations ended because
+// the person's employment dropped to 0%.
+const employmentEndReason = "employment ended"
+
+// employmentEndsFrom reports whether the person's employment is 0% from month
+// onward with no later change bringing it back.
+func employmentEndsFrom(person domain.Person, month string) bool {
+	normalizedMonth, err := domain.ValidateMonth(month)
+	if err != nil {
+		return false
+	}
+	ends := false
+	for _, change := range person.EmploymentChanges {
+		switch {
+		case change.EffectiveMonth == normalizedMonth:
+			ends = change.EmploymentPct == 0
+		case change.EffectiveMonth > normalizedMonth && change.EmploymentPct > 0:
+			return false
+		}
+	}
+	return ends
+}
+
+// updatePersonEndingAllocations stores the person together with their direct
+// allocations ended on the last day before month. Allocations that start in
+// or after month are deleted. Group allocations are left alone because they
+// cover other members too.
+func (s *Service) updatePersonEndingAllocations(ctx context.Context, auth ports.AuthContext, person domain.Person, month string) (domain.Person, error) {
+	normalizedMonth, err := domain.ValidateMonth(month)
+	if err != nil {
+		return domain.Person{}, domain.ErrValidation
+	}
+	monthStart, err := time.Parse(domain.DateLayout, normalizedMonth+"-01")
+	if err != nil {
+		return domain.Person{}, domain.ErrValidation
+	}
+	cutoff := monthStart.Format(domain.DateLayout)
+
+	updated, ended, deletedIDs, err := s.repo.UpdatePersonEndingAllocations(
+		s.withAllocationEndAudit(ctx, auth),
+		person,
+		cutoff,
+		employmentEndReason,
+	)
+	if err != nil {
+		return domain.Person{}, err
+	}
+
+	s.recordChange(ctx, updated.OrganisationID, "person.updated", map[string]string{"person_id": updated.ID})
+	s.recordChange(ctx, updated.OrganisationID, "person.allocations_ended", map[string]string{
+		"person_id": updated.ID,
+		"ended":     strconv.Itoa(len(ended)),
+		"deleted":   strconv.Itoa(len(deletedIDs)),
+	})
+	return updated, nil
+}
+
+// DeletePerson deletes a person from the caller's organisation. It fails with
+// a DependentsError while allocations or unavailability entries still target
+// the person, unless cascade is set to delete them too. Group memberships and
+// targeted holidays are always cleaned up.
+func (s *Service) DeletePerson(ctx context.Context, auth ports.AuthContext, personID string, cascade bool) error {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
 		return err
 	}
@@ -120,12 +278,15 @@ func (s *Service) DeletePerson(ctx context.Context, auth ports.AuthContext, pers
 	if err != nil {
 		return err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
 
-	err = s.repo.DeletePerson(ctx, organisationID, personID)
+	err = s.repo.DeletePerson(s.withAllocationAudit(ctx, auth), organisationID, personID, cascade)
 	if err != nil {
 		return err
 	}
 
-	s.telemetry.Record("person.deleted", map[string]string{"person_id": personID})
+	s.recordChange(ctx, organisationID, "person.deleted", map[string]string{"person_id": personID})
 	return nil
 }
@@ -2,27 +2,64 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
 	"strings"
 
 	"plato/backend/internal/domain"
 	"plato/backend/internal/ports"
 )
 
-// ListProjects returns the projects visible to the caller within their organisation.
-func (s *Service) ListProjects(ctx context.Context, auth ports.AuthContext) ([]domain.Project, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+// ListProjects returns the projects visible to the caller within their
+// organisation. Archived projects are left out unless includeArchived is set.
+func (s *Service) ListProjects(ctx context.Context, auth ports.AuthContext, includeArchived bool) ([]domain.Project, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return nil, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
 		return nil, err
 	}
-	return s.repo.ListProjects(ctx, organisationID)
+	projects, err := s.repo.ListProjects(ctx, organisationID)
+	if err != nil || includeArchived {
+		return projects, err
+	}
+	result := make([]domain.Project, 0, len(projects))
+	for _, project := range projects {
+		if !project.Archived {
+			result = append(result, project)
+		}
+	}
+	return result, nil
+}
+
+// ListProjectsPage returns one filtered and sorted page of the caller's
+// projects. Archived projects are left out unless includeArchived is set or
+// the query filters on archived itself.
+func (s *Service) ListProjectsPage(ctx context.Context, auth ports.AuthContext, includeArchived bool, query domain.ListQuery) (domain.ListPage[domain.Project], error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.ListPage[domain.Project]{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.ListPage[domain.Project]{}, err
+	}
+	if _, filtered := query.Filters["archived"]; !includeArchived && !filtered {
+		filters := maps.Clone(query.Filters)
+		if filters == nil {
+			filters = map[string][]string{}
+		}
+		filters["archived"] = []string{"false"}
+		query.Filters = filters
+	}
+	return s.repo.ListProjectsPage(ctx, organisationID, query)
 }
 
 // GetProject returns one project from the caller's organisation.
 func (s *Service) GetProject(ctx context.Context, auth ports.AuthContext, projectID string) (domain.Project, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
 		return domain.Project{}, err
 	}
 	organisationID, err := requiredOrganisationID(auth)
@@ -41,25 +78,28 @@ func (s *Service) CreateProject(ctx context.Context, auth ports.AuthContext, inp
 	if err != nil {
 		return domain.Project{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Project{}, err
+	}
 	err = validateProject(input)
 	if err != nil {
 		return domain.Project{}, err
 	}
-
 	project := domain.Project{
 		OrganisationID:       organisationID,
 		Name:                 strings.TrimSpace(input.Name),
 		StartDate:            input.StartDate,
 		EndDate:              input.EndDate,
 		EstimatedEffortHours: input.EstimatedEffortHours,
+		Milestones:           normalizeProjectMilestones(input.Milestones),
 	}
 
-	created, err := s.repo.CreateProject(ctx, project)
+	created, err := s.repo.CreateProjectWithLimit(ctx, project, s.quotas.MaxProjectsPerOrganisation)
 	if err != nil {
 		return domain.Project{}, err
 	}
 
-	s.telemetry.Record("project.created", map[string]string{"project_id": created.ID})
+	s.recordChange(ctx, organisationID, "project.created", map[string]string{"project_id": created.ID})
 	return created, nil
 }
 
@@ -72,6 +112,9 @@ func (s *Service) UpdateProject(ctx context.Context, auth ports.AuthContext, pro
 	if err != nil {
 		return domain.Project{}, err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Project{}, err
+	}
 	err = validateProject(input)
 	if err != nil {
 		return domain.Project{}, err
@@ -81,22 +124,29 @@ func (s *Service) UpdateProject(ctx context.Context, auth ports.AuthContext, pro
 	if err != nil {
 		return domain.Project{}, err
 	}
+	if err = requireCurrentVersion("project", input.Version, project.Version); err != nil {
+		return domain.Project{}, err
+	}
 	project.Name = strings.TrimSpace(input.Name)
 	project.StartDate = input.StartDate
 	project.EndDate = input.EndDate
 	project.EstimatedEffortHours = input.EstimatedEffortHours
+	project.Milestones = normalizeProjectMilestones(input.Milestones)
 
 	updated, err := s.repo.UpdateProject(ctx, project)
 	if err != nil {
 		return domain.Project{}, err
 	}
 
-	s.telemetry.Record("project.updated", map[string]string{"project_id": updated.ID})
+	s.recordChange(ctx, organisationID, "project.updated", map[string]string{"project_id": updated.ID})
 	return updated, nil
 }
 
-// DeleteProject deletes a project from the caller's organisation.
-func (s *Service) DeleteProject(ctx context.Context, auth ports.AuthContext, projectID string) error {
+// DeleteProject archives a project in the caller's organisation so its
+// allocations stay in reports. Purge removes the project for good instead,
+// and fails with a DependentsError while allocations are still booked on it
+// unless cascade is set to delete them too.
+func (s *Service) DeleteProject(ctx context.Context, auth ports.AuthContext, projectID string, purge, cascade bool) error {
 	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
 		return err
 	}
@@ -104,12 +154,171 @@ func (s *Service) DeleteProject(ctx context.Context, auth ports.AuthContext, pro
 	if err != nil {
 		return err
 	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
+
+	if purge {
+		err = s.repo.DeleteProject(s.withAllocationAudit(ctx, auth), organisationID, projectID, cascade)
+		if err != nil {
+			return err
+		}
+		s.recordChange(ctx, organisationID, "project.deleted", map[string]string{"project_id": projectID})
+		return nil
+	}
 
-	err = s.repo.DeleteProject(ctx, organisationID, projectID)
+	project, err := s.repo.GetProject(ctx, organisationID, projectID)
 	if err != nil {
 		return err
 	}
+	if project.Archived {
+		return nil
+	}
+	archivedAt := s.now().UTC()
+	project.Archived = true
+	project.ArchivedAt = &archivedAt
+	if _, err = s.repo.UpdateProject(ctx, project); err != nil {
+		return err
+	}
 
-	s.telemetry.Record("project.deleted", map[string]string{"project_id": projectID})
+	s.recordChange(ctx, organisationID, "project.archived", map[string]string{"project_id": projectID})
 	return nil
 }
+
+// RestoreProject brings an archived project in the caller's organisation back
+// into listings and allocation. Restoring an active project changes nothing.
+func (s *Service) RestoreProject(ctx context.Context, auth ports.AuthContext, projectID string) (domain.Project, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.Project{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Project{}, err
+	}
+
+	project, err := s.repo.GetProject(ctx, organisationID, projectID)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if !project.Archived {
+		return project, nil
+	}
+	project.Archived = false
+	project.ArchivedAt = nil
+	restored, err := s.repo.UpdateProject(ctx, project)
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	s.recordChange(ctx, organisationID, "project.restored", map[string]string{"project_id": projectID})
+	return restored, nil
+}
+
+// ReconcileProjectAllocations finds allocations of a project that fall outside
+// its current date range. Report mode only lists them. Clip mode trims every
+// allocation that still overlaps the range and stores all changes in one
+// write, while allocations entirely outside the range stay listed for manual
+// handling.
+func (s *Service) ReconcileProjectAllocations(ctx context.Context, auth ports.AuthContext, projectID, mode string) (domain.AllocationReconciliation, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.AllocationReconciliation{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.AllocationReconciliation{}, err
+	}
+	mode = strings.TrimSpace(mode)
+	if mode == "" {
+		mode = domain.ReconcileReport
+	}
+	if mode != domain.ReconcileReport && mode != domain.ReconcileClip {
+		return domain.AllocationReconciliation{}, fmt.Errorf("mode must be report or clip: %w", domain.ErrValidation)
+	}
+	if mode == domain.ReconcileClip {
+		if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+			return domain.AllocationReconciliation{}, err
+		}
+	}
+
+	project, err := s.repo.GetProject(ctx, organisationID, projectID)
+	if err != nil {
+		return domain.AllocationReconciliation{}, err
+	}
+	allocations, err := s.repo.ListAllocations(ctx, organisationID)
+	if err != nil {
+		return domain.AllocationReconciliation{}, err
+	}
+
+	result := domain.AllocationReconciliation{
+		ProjectID:  projectID,
+		Mode:       mode,
+		Clipped:    []domain.Allocation{},
+		OutOfRange: []domain.Allocation{},
+	}
+	for _, allocation := range allocations {
+		if allocation.ProjectID != projectID {
+			continue
+		}
+		rangeErr := validateAllocationWithinProjectRange(allocation, project)
+		if rangeErr == nil {
+			continue
+		}
+		var projectRangeErr domain.ProjectRangeError
+		if !errors.As(rangeErr, &projectRangeErr) {
+			return domain.AllocationReconciliation{}, rangeErr
+		}
+		if mode == domain.ReconcileClip {
+			clipped, ok, clipErr := clipAllocationToProject(allocation, project)
+			if clipErr != nil {
+				return domain.AllocationReconciliation{}, clipErr
+			}
+			if ok {
+				result.Clipped = append(result.Clipped, clipped)
+				continue
+			}
+		}
+		result.OutOfRange = append(result.OutOfRange, allocation)
+	}
+
+	if len(result.Clipped) > 0 {
+		result.Clipped, err = s.repo.UpdateAllocations(s.withAllocationAudit(ctx, auth), organisationID, result.Clipped)
+		if err != nil {
+			return domain.AllocationReconciliation{}, err
+		}
+	}
+
+	s.recordChange(ctx, organisationID, "project.allocations_reconciled", map[string]string{
+		"project_id":   projectID,
+		"mode":         mode,
+		"clipped":      strconv.Itoa(len(result.Clipped)),
+		"out_of_range": strconv.Itoa(len(result.OutOfRange)),
+	})
+	return result, nil
+}
+
+// clipAllocationToProject trims the allocation to the project range. It
+// reports false when the allocation does not overlap the range at all.
+func clipAllocationToProject(allocation domain.Allocation, project domain.Project) (domain.Allocation, bool, error) {
+	projectStart, projectEnd, err := parseDateRange(project.StartDate, project.EndDate)
+	if err != nil {
+		return domain.Allocation{}, false, domain.ErrValidation
+	}
+	allocationStart, allocationEnd, err := parseDateRange(allocation.StartDate, allocation.EndDate)
+	if err != nil {
+		return domain.Allocation{}, false, domain.ErrValidation
+	}
+	overlapStart, overlapEnd, overlaps := overlapDateRanges(allocationStart, allocationEnd, projectStart, projectEnd)
+	if !overlaps {
+		return domain.Allocation{}, false, nil
+	}
+	if allocationStart.Before(projectStart) {
+		allocation.StartDate = overlapStart.Format(domain.DateLayout)
+	}
+	if allocationEnd.After(projectEnd) {
+		allocation.EndDate = overlapEnd.Format(domain.DateLayout)
+	}
+	return allocation, true, nil
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"plato/backend/internal/domain"
 	"plato/backend/internal/ports"
@@ -11,31 +16,516 @@ import (
 
 // ReportAvailabilityAndLoad generates availability and load buckets for a report request.
 func (s *Service) ReportAvailabilityAndLoad(ctx context.Context, auth ports.AuthContext, request domain.ReportRequest) ([]domain.ReportBucket, error) {
-	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgUser); err != nil {
+	result, err := s.GenerateReport(ctx, auth, request)
+	if err != nil {
 		return nil, err
 	}
+	return result.Buckets, nil
+}
+
+// GenerateReport generates availability and load buckets for a report request.
+// With TolerateMissing set, requested IDs outside the organisation are listed
+// in SkippedIDs and the report covers the remaining ones.
+func (s *Service) GenerateReport(ctx context.Context, auth ports.AuthContext, request domain.ReportRequest) (domain.ReportResult, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.ReportResult{}, err
+	}
 	organisationID, err := requiredOrganisationID(auth)
 	if err != nil {
-		return nil, err
+		return domain.ReportResult{}, err
+	}
+	request, err = s.resolveReportDefaults(ctx, organisationID, request)
+	if err != nil {
+		return domain.ReportResult{}, err
 	}
 	if validationErr := validateReportRequest(request); validationErr != nil {
-		return nil, validationErr
+		return domain.ReportResult{}, validationErr
 	}
 
 	calculationInput, err := s.loadReportCalculationInput(ctx, organisationID, request)
+	if err != nil {
+		return domain.ReportResult{}, err
+	}
+	report := domain.ReportResult{
+		Buckets:    []domain.ReportBucket{},
+		SkippedIDs: skippedScopeIDs(request.IDs, calculationInput.Request.IDs),
+	}
+	if len(request.IDs) > 0 && len(calculationInput.Request.IDs) == 0 {
+		// Every requested ID was skipped. An empty ID list would widen the
+		// report to the whole scope, so return no buckets instead.
+		return report, nil
+	}
+
+	report.Buckets, err = domain.CalculateAvailabilityLoad(calculationInput)
+	if err != nil {
+		return domain.ReportResult{}, err
+	}
+
+	s.record(ctx, "report.generated", map[string]string{"scope": request.Scope})
+	return report, nil
+}
+
+// maxWhatIfAllocations caps how many proposed allocations one what-if report may model.
+const maxWhatIfAllocations = 100
+
+// ReportWhatIf generates availability and load buckets as if the proposed
+// allocations existed alongside the stored ones. Nothing is persisted and the
+// allocation limits are not enforced, so overloads show up in the buckets.
+func (s *Service) ReportWhatIf(ctx context.Context, auth ports.AuthContext, request domain.WhatIfReportRequest) ([]domain.ReportBucket, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.resolveReportDefaults(ctx, organisationID, request.ReportRequest)
+	if err != nil {
+		return nil, err
+	}
+	// What-if responses carry no skipped ID list, so IDs always resolve strictly.
+	report.TolerateMissing = false
+	if validationErr := validateReportRequest(report); validationErr != nil {
+		return nil, validationErr
+	}
+	if len(request.ProposedAllocations) == 0 {
+		return nil, errors.Join(domain.ErrValidation, errors.New("proposed_allocations must contain at least one allocation"))
+	}
+	if len(request.ProposedAllocations) > maxWhatIfAllocations {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("proposed_allocations must not contain more than %d allocations", maxWhatIfAllocations))
+	}
+
+	calculationInput, err := s.loadReportCalculationInput(ctx, organisationID, report)
+	if err != nil {
+		return nil, err
+	}
+	proposed, err := s.proposedAllocations(ctx, organisationID, request.ProposedAllocations)
 	if err != nil {
 		return nil, err
 	}
+	calculationInput.Allocations = append(calculationInput.Allocations, proposed...)
 
 	result, err := domain.CalculateAvailabilityLoad(calculationInput)
 	if err != nil {
 		return nil, err
 	}
 
-	s.telemetry.Record("report.generated", map[string]string{"scope": request.Scope})
+	s.record(ctx, "report.what_if_generated", map[string]string{"scope": report.Scope})
 	return result, nil
 }
 
+// ReportDiff runs the baseline and comparison reports and returns the change
+// in load and availability for every period start found in either run. The
+// comparison runs as a what-if report when it proposes allocations.
+func (s *Service) ReportDiff(ctx context.Context, auth ports.AuthContext, request domain.ReportDiffRequest) ([]domain.ReportDiffBucket, error) {
+	if reportUnit(request.Baseline) != reportUnit(request.Comparison.ReportRequest) {
+		return nil, errors.Join(domain.ErrValidation, errors.New("baseline and comparison must use the same unit"))
+	}
+	baseline, err := s.ReportAvailabilityAndLoad(ctx, auth, request.Baseline)
+	if err != nil {
+		return nil, err
+	}
+	var comparison []domain.ReportBucket
+	if len(request.Comparison.ProposedAllocations) > 0 {
+		comparison, err = s.ReportWhatIf(ctx, auth, request.Comparison)
+	} else {
+		comparison, err = s.ReportAvailabilityAndLoad(ctx, auth, request.Comparison.ReportRequest)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffReportBuckets(baseline, comparison)
+	s.record(ctx, "report.diff_generated", map[string]string{
+		"baseline_scope":   request.Baseline.Scope,
+		"comparison_scope": request.Comparison.Scope,
+	})
+	return diff, nil
+}
+
+func diffReportBuckets(baseline, comparison []domain.ReportBucket) []domain.ReportDiffBucket {
+	byPeriod := make(map[string]*domain.ReportDiffBucket, len(baseline)+len(comparison))
+	periods := make([]string, 0, len(baseline)+len(comparison))
+	bucketFor := func(periodStart string) *domain.ReportDiffBucket {
+		bucket, ok := byPeriod[periodStart]
+		if !ok {
+			bucket = &domain.ReportDiffBucket{PeriodStart: periodStart}
+			byPeriod[periodStart] = bucket
+			periods = append(periods, periodStart)
+		}
+		return bucket
+	}
+	for _, bucket := range baseline {
+		diff := bucketFor(bucket.PeriodStart)
+		diff.BaselineLoadHours = bucket.LoadHours
+		diff.BaselineAvailabilityHours = bucket.AvailabilityHours
+	}
+	for _, bucket := range comparison {
+		diff := bucketFor(bucket.PeriodStart)
+		diff.ComparisonLoadHours = bucket.LoadHours
+		diff.ComparisonAvailabilityHours = bucket.AvailabilityHours
+	}
+
+	sort.Strings(periods)
+	result := make([]domain.ReportDiffBucket, 0, len(periods))
+	for _, periodStart := range periods {
+		diff := byPeriod[periodStart]
+		diff.LoadHoursDelta = diff.ComparisonLoadHours - diff.BaselineLoadHours
+		diff.AvailabilityHoursDelta = diff.ComparisonAvailabilityHours - diff.BaselineAvailabilityHours
+		result = append(result, *diff)
+	}
+	return result
+}
+
+// ReportUnallocatedPersons lists the people with no allocation load, direct
+// or through a group, on the as_of date or on any day from from through to.
+func (s *Service) ReportUnallocatedPersons(ctx context.Context, auth ports.AuthContext, asOf, from, to string) ([]domain.Person, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	rangeStart, rangeEnd, err := unallocatedReportRange(asOf, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	persons, err := s.repo.ListPersons(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	groupsByID, err := s.listGroupsByID(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	unallocated := make([]domain.Person, 0)
+	for _, person := range persons {
+		events, eventsErr := buildAllocationEvents(allocations, "", person.ID, groupsByID, rangeStart, rangeEnd)
+		if eventsErr != nil {
+			return nil, eventsErr
+		}
+		if !hasAllocationLoad(events) {
+			unallocated = append(unallocated, person)
+		}
+	}
+
+	s.record(ctx, "report.unallocated_generated", map[string]string{"persons": strconv.Itoa(len(unallocated))})
+	return unallocated, nil
+}
+
+// unallocatedReportRange accepts either a single as_of date or a from and to
+// range, never both.
+func unallocatedReportRange(asOf, from, to string) (time.Time, time.Time, error) {
+	asOf = strings.TrimSpace(asOf)
+	from = strings.TrimSpace(from)
+	to = strings.TrimSpace(to)
+	if asOf != "" {
+		if from != "" || to != "" {
+			return time.Time{}, time.Time{}, errors.Join(domain.ErrValidation, errors.New("as_of cannot be combined with from and to"))
+		}
+		from, to = asOf, asOf
+	}
+	if from == "" || to == "" {
+		return time.Time{}, time.Time{}, errors.Join(domain.ErrValidation, errors.New("as_of or both from and to are required"))
+	}
+	start, end, err := parseDateRange(from, to)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Join(domain.ErrValidation, errors.New("dates must use YYYY-MM-DD format and end on or after the start"))
+	}
+	return start, end, nil
+}
+
+func hasAllocationLoad(events map[time.Time]float64) bool {
+	var total float64
+	for _, eventDate := range sortedEventDates(events) {
+		total += events[eventDate]
+		if total > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonPeakOverallocation returns the window between from and to where the
+// person's combined direct and group load exceeds their employment percentage
+// by the largest margin. Ties go to the earliest window.
+func (s *Service) PersonPeakOverallocation(ctx context.Context, auth ports.AuthContext, personID, from, to string) (domain.PeakOverallocation, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.PeakOverallocation{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.PeakOverallocation{}, err
+	}
+	if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+		return domain.PeakOverallocation{}, errors.Join(domain.ErrValidation, errors.New("from and to are required"))
+	}
+	rangeStart, rangeEnd, err := parseDateRange(from, to)
+	if err != nil {
+		return domain.PeakOverallocation{}, errors.Join(domain.ErrValidation, errors.New("dates must use YYYY-MM-DD format and end on or after the start"))
+	}
+
+	person, err := s.repo.GetPerson(ctx, organisationID, personID)
+	if err != nil {
+		return domain.PeakOverallocation{}, err
+	}
+	allocations, err := s.listActiveAllocations(ctx, organisationID)
+	if err != nil {
+		return domain.PeakOverallocation{}, err
+	}
+	groupsByID, err := s.listGroupsByID(ctx, organisationID)
+	if err != nil {
+		return domain.PeakOverallocation{}, err
+	}
+	events, err := buildAllocationEvents(allocations, "", person.ID, groupsByID, rangeStart, rangeEnd)
+	if err != nil {
+		return domain.PeakOverallocation{}, err
+	}
+	stretches, err := loadStretches(person, events, rangeStart, rangeEnd)
+	if err != nil {
+		return domain.PeakOverallocation{}, err
+	}
+
+	peak := domain.PeakOverallocation{PersonID: person.ID}
+	peakIndex := -1
+	for index, stretch := range stretches {
+		excess := stretch.loadPct - stretch.capacityPct
+		if excess > allocationLimitTolerance && (peakIndex < 0 || excess > peak.ExcessPct+allocationLimitTolerance) {
+			peakIndex = index
+			peak.ExcessPct = excess
+		}
+	}
+	if peakIndex >= 0 {
+		start := stretches[peakIndex]
+		end := start
+		// Event dates where loads cancel out split a stretch without changing it.
+		for _, next := range stretches[peakIndex+1:] {
+			if !sameLoadStretch(next, start) {
+				break
+			}
+			end = next
+		}
+		peak.Overallocated = true
+		peak.StartDate = start.start.Format(domain.DateLayout)
+		peak.EndDate = end.end.Format(domain.DateLayout)
+		peak.LoadPct = math.Round(start.loadPct*100) / 100
+		peak.CapacityPct = start.capacityPct
+		peak.ExcessPct = math.Round(peak.ExcessPct*100) / 100
+	}
+
+	s.record(ctx, "report.peak_overallocation_generated", map[string]string{"person_id": person.ID})
+	return peak, nil
+}
+
+// maxCapacityDays caps how many days one person or group capacity
+// breakdown may cover.
+const maxCapacityDays = 366
+
+// PersonCapacity returns the day by day capacity breakdown of one person
+// between fromDate and toDate, both inclusive.
+func (s *Service) PersonCapacity(ctx context.Context, auth ports.AuthContext, personID, fromDate, toDate string) (domain.PersonCapacity, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.PersonCapacity{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.PersonCapacity{}, err
+	}
+	rangeStart, rangeEnd, err := parseCapacityRange(fromDate, toDate)
+	if err != nil {
+		return domain.PersonCapacity{}, err
+	}
+	if _, err = s.repo.GetPerson(ctx, organisationID, personID); err != nil {
+		return domain.PersonCapacity{}, err
+	}
+
+	calculationInput, err := s.loadReportCalculationInput(ctx, organisationID, domain.ReportRequest{
+		Scope:       domain.ScopePerson,
+		IDs:         []string{personID},
+		FromDate:    rangeStart.Format(domain.DateLayout),
+		ToDate:      rangeEnd.Format(domain.DateLayout),
+		Granularity: domain.GranularityDay,
+	})
+	if err != nil {
+		return domain.PersonCapacity{}, err
+	}
+	capacity, err := domain.CalculatePersonCapacity(calculationInput, personID)
+	if err != nil {
+		return domain.PersonCapacity{}, err
+	}
+
+	s.record(ctx, "report.person_capacity_generated", map[string]string{"person_id": personID})
+	return capacity, nil
+}
+
+// GroupCapacity sums the capacity of a group's members per granularity
+// period between fromDate and toDate, both inclusive. Granularity defaults
+// to week.
+func (s *Service) GroupCapacity(ctx context.Context, auth ports.AuthContext, groupID, fromDate, toDate, granularity string) (domain.GroupCapacity, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.GroupCapacity{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.GroupCapacity{}, err
+	}
+	rangeStart, rangeEnd, err := parseCapacityRange(fromDate, toDate)
+	if err != nil {
+		return domain.GroupCapacity{}, err
+	}
+	if strings.TrimSpace(granularity) == "" {
+		granularity = domain.GranularityWeek
+	}
+	if err = domain.ValidateGranularity(granularity); err != nil {
+		return domain.GroupCapacity{}, errors.Join(err, errors.New("granularity must be day, week, month, quarter, or year"))
+	}
+	if _, err = s.repo.GetGroup(ctx, organisationID, groupID); err != nil {
+		return domain.GroupCapacity{}, err
+	}
+
+	calculationInput, err := s.loadReportCalculationInput(ctx, organisationID, domain.ReportRequest{
+		Scope:       domain.ScopeGroup,
+		IDs:         []string{groupID},
+		FromDate:    rangeStart.Format(domain.DateLayout),
+		ToDate:      rangeEnd.Format(domain.DateLayout),
+		Granularity: granularity,
+	})
+	if err != nil {
+		return domain.GroupCapacity{}, err
+	}
+	capacity, err := domain.CalculateGroupCapacity(calculationInput, groupID)
+	if err != nil {
+		return domain.GroupCapacity{}, err
+	}
+
+	s.record(ctx, "report.group_capacity_generated", map[string]string{"group_id": groupID})
+	return capacity, nil
+}
+
+// parseCapacityRange parses the required from_date and to_date of a capacity
+// breakdown and enforces maxCapacityDays.
+func parseCapacityRange(fromDate, toDate string) (time.Time, time.Time, error) {
+	if strings.TrimSpace(fromDate) == "" || strings.TrimSpace(toDate) == "" {
+		return time.Time{}, time.Time{}, errors.Join(domain.ErrValidation, errors.New("from_date and to_date are required"))
+	}
+	rangeStart, rangeEnd, err := parseDateRange(fromDate, toDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Join(domain.ErrValidation, errors.New("dates must use YYYY-MM-DD format and end on or after the start"))
+	}
+	if rangeEnd.Sub(rangeStart) >= maxCapacityDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.Join(domain.ErrValidation, fmt.Errorf("date range must not exceed %d days", maxCapacityDays))
+	}
+	return rangeStart, rangeEnd, nil
+}
+
+// loadStretch is a run of days with a constant allocation load and a
+// constant employment percentage.
+type loadStretch struct {
+	start       time.Time
+	end         time.Time
+	loadPct     float64
+	capacityPct float64
+}
+
+func sameLoadStretch(a, b loadStretch) bool {
+	return math.Abs(a.loadPct-b.loadPct) <= allocationLimitTolerance && a.capacityPct == b.capacityPct
+}
+
+// loadStretches splits the range at every allocation event and employment
+// change so each stretch can be compared against capacity once.
+func loadStretches(person domain.Person, events map[time.Time]float64, rangeStart, rangeEnd time.Time) ([]loadStretch, error) {
+	boundarySet := map[time.Time]bool{rangeStart: true}
+	for eventDate := range events {
+		if eventDate.After(rangeStart) && !eventDate.After(rangeEnd) {
+			boundarySet[eventDate] = true
+		}
+	}
+	for _, change := range person.EmploymentChanges {
+		effective, err := time.Parse(domain.MonthLayout, change.EffectiveMonth)
+		if err != nil {
+			return nil, domain.ErrValidation
+		}
+		if effective.After(rangeStart) && !effective.After(rangeEnd) {
+			boundarySet[effective] = true
+		}
+	}
+	boundaries := make([]time.Time, 0, len(boundarySet))
+	for boundary := range boundarySet {
+		boundaries = append(boundaries, boundary)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+
+	eventDates := sortedEventDates(events)
+	stretches := make([]loadStretch, 0, len(boundaries))
+	var load float64
+	next := 0
+	for index, boundary := range boundaries {
+		for next < len(eventDates) && !eventDates[next].After(boundary) {
+			load += events[eventDates[next]]
+			next++
+		}
+		end := rangeEnd
+		if index+1 < len(boundaries) {
+			end = boundaries[index+1].AddDate(0, 0, -1)
+		}
+		capacity, err := domain.EmploymentPctOnDate(person, boundary.Format(domain.DateLayout))
+		if err != nil {
+			return nil, err
+		}
+		stretches = append(stretches, loadStretch{start: boundary, end: end, loadPct: load, capacityPct: capacity})
+	}
+	return stretches, nil
+}
+
+// proposedAllocations prepares what-if allocations through prepareAllocation
+// without the limit checks and gives each a transient id.
+func (s *Service) proposedAllocations(ctx context.Context, organisationID string, inputs []domain.Allocation) ([]domain.Allocation, error) {
+	proposed := make([]domain.Allocation, 0, len(inputs))
+	for index, input := range inputs {
+		allocation, err := s.prepareAllocation(ctx, organisationID, input, "", false)
+		if err != nil {
+			return nil, fmt.Errorf("proposed allocation %d: %w", index+1, err)
+		}
+		allocation.ID = fmt.Sprintf("what_if_%d", index+1)
+		allocation.Warnings = nil
+		proposed = append(proposed, allocation)
+	}
+	return proposed, nil
+}
+
+// resolveReportDefaults fills an omitted date range with the current month in
+// the organisation's time zone.
+func (s *Service) resolveReportDefaults(ctx context.Context, organisationID string, request domain.ReportRequest) (domain.ReportRequest, error) {
+	if strings.TrimSpace(request.FromDate) != "" || strings.TrimSpace(request.ToDate) != "" {
+		return request, nil
+	}
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return domain.ReportRequest{}, err
+	}
+	location, err := organisation.Location()
+	if err != nil {
+		return domain.ReportRequest{}, err
+	}
+	request.FromDate, request.ToDate = domain.CurrentMonthRange(s.now(), location)
+	return request, nil
+}
+
+// reportUnit returns the effective unit of a report request.
+func reportUnit(request domain.ReportRequest) string {
+	if request.Unit == "" {
+		return domain.ReportUnitHours
+	}
+	return request.Unit
+}
+
 func validateReportRequest(request domain.ReportRequest) error {
 	if err := domain.ValidateScope(request.Scope); err != nil {
 		return err
@@ -43,6 +533,12 @@ func validateReportRequest(request domain.ReportRequest) error {
 	if err := domain.ValidateGranularity(request.Granularity); err != nil {
 		return err
 	}
+	if err := validateReportScopeIDs(request); err != nil {
+		return err
+	}
+	if err := domain.ValidateReportUnit(request.Unit); err != nil {
+		return errors.Join(err, fmt.Errorf("unit must be %s or %s", domain.ReportUnitHours, domain.ReportUnitFTE))
+	}
 	fromDate, err := domain.ValidateDate(request.FromDate)
 	if err != nil {
 		return errors.Join(domain.ErrValidation, fmt.Errorf("from date: %w", err))
@@ -74,9 +570,9 @@ func (s *Service) loadReportCalculationInput(ctx context.Context, organisationID
 	if err != nil {
 		return domain.CalculationInput{}, fmt.Errorf("list groups for organisation %s: %w", organisationID, err)
 	}
-	allocations, err := s.repo.ListAllocations(ctx, organisationID)
+	allocations, err := s.reportAllocations(ctx, organisationID, request.ScenarioID)
 	if err != nil {
-		return domain.CalculationInput{}, fmt.Errorf("list allocations for organisation %s: %w", organisationID, err)
+		return domain.CalculationInput{}, err
 	}
 	orgHolidays, err := s.repo.ListOrgHolidays(ctx, organisationID)
 	if err != nil {
@@ -90,6 +586,16 @@ func (s *Service) loadReportCalculationInput(ctx context.Context, organisationID
 	if err != nil {
 		return domain.CalculationInput{}, fmt.Errorf("list person unavailability for organisation %s: %w", organisationID, err)
 	}
+	unavailabilityRules, err := s.repo.ListUnavailabilityRules(ctx, organisationID)
+	if err != nil {
+		return domain.CalculationInput{}, fmt.Errorf("list unavailability rules for organisation %s: %w", organisationID, err)
+	}
+	if request.TolerateMissing {
+		request.IDs, err = knownScopeIDs(request, persons, groups, projects)
+		if err != nil {
+			return domain.CalculationInput{}, err
+		}
+	}
 	if scopeErr := validateScopeIDs(request, persons, groups, projects); scopeErr != nil {
 		return domain.CalculationInput{}, scopeErr
 	}
@@ -103,17 +609,103 @@ func (s *Service) loadReportCalculationInput(ctx context.Context, organisationID
 		OrgHolidays:          orgHolidays,
 		GroupUnavailability:  groupUnavailability,
 		PersonUnavailability: personUnavailability,
+		UnavailabilityRules:  unavailabilityRules,
 		Request:              request,
 	}, nil
 }
 
+// reportAllocations returns the allocations a report counts: the live ones,
+// or those of the given scenario. Expired holds are left out of both.
+func (s *Service) reportAllocations(ctx context.Context, organisationID, scenarioID string) ([]domain.Allocation, error) {
+	scenarioID = strings.TrimSpace(scenarioID)
+	if scenarioID == "" {
+		allocations, err := s.listActiveAllocations(ctx, organisationID)
+		if err != nil {
+			return nil, fmt.Errorf("list allocations for organisation %s: %w", organisationID, err)
+		}
+		return allocations, nil
+	}
+	scenario, err := s.repo.GetScenario(ctx, organisationID, scenarioID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, errors.Join(domain.ErrValidation, fmt.Errorf("scenario_id %q does not reference a scenario in this organisation", scenarioID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get scenario %s: %w", scenarioID, err)
+	}
+	return domain.ActiveAllocations(scenario.Allocations, s.now()), nil
+}
+
+// validateReportScopeIDs checks that the IDs fit the scope. Organisation
+// scope covers everyone and takes no IDs, while the other scopes report on
+// the IDs they are given and need at least one.
+func validateReportScopeIDs(request domain.ReportRequest) error {
+	if request.Scope == domain.ScopeOrganisation {
+		if len(request.IDs) > 0 {
+			return errors.Join(domain.ErrValidation, errors.New("ids must be empty for organisation scope"))
+		}
+		return nil
+	}
+	if len(request.IDs) == 0 {
+		return errors.Join(domain.ErrValidation, fmt.Errorf("ids must contain at least one %s for %s scope", request.Scope, request.Scope))
+	}
+	return nil
+}
+
 func validateScopeIDs(request domain.ReportRequest, persons []domain.Person, groups []domain.Group, projects []domain.Project) error {
 	if len(request.IDs) == 0 {
 		return nil
 	}
 
+	lookup, err := scopeIDLookup(request.Scope, persons, groups, projects)
+	if err != nil || lookup == nil {
+		return err
+	}
+
+	for _, id := range request.IDs {
+		if !lookup[id] {
+			return domain.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+// knownScopeIDs keeps the requested IDs that exist in the organisation for the
+// report scope. Organisation scope takes no IDs, so they are kept as given.
+func knownScopeIDs(request domain.ReportRequest, persons []domain.Person, groups []domain.Group, projects []domain.Project) ([]string, error) {
+	lookup, err := scopeIDLookup(request.Scope, persons, groups, projects)
+	if err != nil || lookup == nil {
+		return request.IDs, err
+	}
+
+	known := make([]string, 0, len(request.IDs))
+	for _, id := range request.IDs {
+		if lookup[id] {
+			known = append(known, id)
+		}
+	}
+	return known, nil
+}
+
+func skippedScopeIDs(requested, kept []string) []string {
+	keptSet := make(map[string]bool, len(kept))
+	for _, id := range kept {
+		keptSet[id] = true
+	}
+	skipped := make([]string, 0)
+	for _, id := range requested {
+		if !keptSet[id] {
+			skipped = append(skipped, id)
+		}
+	}
+	return uniqueStringIDs(skipped)
+}
+
+// scopeIDLookup returns the set of valid IDs for a report scope. It returns a
+// nil set for organisation scope, which does not filter by ID.
+func scopeIDLookup(scope string, persons []domain.Person, groups []domain.Group, projects []domain.Project) (map[string]bool, error) {
 	lookup := map[string]bool{}
-	switch request.Scope {
+	switch scope {
 	case domain.ScopePerson:
 		for _, person := range persons {
 			lookup[person.ID] = true
@@ -127,16 +719,9 @@ func validateScopeIDs(request domain.ReportRequest, persons []domain.Person, gro
 			lookup[project.ID] = true
 		}
 	case domain.ScopeOrganisation:
-		return nil
+		return nil, nil
 	default:
-		return domain.ErrValidation
+		return nil, domain.ErrValidation
 	}
-
-	for _, id := range request.IDs {
-		if !lookup[id] {
-			return domain.ErrNotFound
-		}
-	}
-
-	return nil
+	return lookup, nil
 }
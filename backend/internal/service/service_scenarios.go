@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// ListScenarios returns the scenarios of the caller's organisation without
+// their allocations.
+func (s *Service) ListScenarios(ctx context.Context, auth ports.AuthContext) ([]domain.ScenarioSummary, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	scenarios, err := s.repo.ListScenarios(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]domain.ScenarioSummary, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		summaries = append(summaries, scenario.Summary())
+	}
+	return summaries, nil
+}
+
+// GetScenario returns one scenario of the caller's organisation with its
+// allocations.
+func (s *Service) GetScenario(ctx context.Context, auth ports.AuthContext, scenarioID string) (domain.Scenario, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return domain.Scenario{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.Scenario{}, err
+	}
+	return s.repo.GetScenario(ctx, organisationID, scenarioID)
+}
+
+// CreateScenario stores a named scenario holding a copy of the caller's
+// organisation allocations as they are now.
+func (s *Service) CreateScenario(ctx context.Context, auth ports.AuthContext, input domain.Scenario) (domain.Scenario, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return domain.Scenario{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.Scenario{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Scenario{}, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if err = domain.ValidateName(name); err != nil {
+		return domain.Scenario{}, errors.Join(domain.ErrValidation, errors.New("scenario name is required"))
+	}
+
+	allocations, err := s.repo.ListAllocations(ctx, organisationID)
+	if err != nil {
+		return domain.Scenario{}, err
+	}
+	baseVersions := make(map[string]int64, len(allocations))
+	for index := range allocations {
+		allocations[index].Warnings = nil
+		baseVersions[allocations[index].ID] = allocations[index].Version
+	}
+
+	created, err := s.repo.CreateScenario(ctx, domain.Scenario{
+		OrganisationID: organisationID,
+		Name:           name,
+		Allocations:    allocations,
+		BaseVersions:   baseVersions,
+	})
+	if err != nil {
+		return domain.Scenario{}, err
+	}
+
+	s.recordChange(ctx, organisationID, "scenario.created", map[string]string{"scenario_id": created.ID})
+	return created, nil
+}
+
+// DeleteScenario deletes a scenario. Live allocations are not touched.
+func (s *Service) DeleteScenario(ctx context.Context, auth ports.AuthContext, scenarioID string) error {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
+	if err = s.repo.DeleteScenario(ctx, organisationID, scenarioID); err != nil {
+		return err
+	}
+
+	s.recordChange(ctx, organisationID, "scenario.deleted", map[string]string{"scenario_id": scenarioID})
+	return nil
+}
+
+// CreateScenarioAllocation adds an allocation to a scenario. It passes the
+// same checks as CreateAllocation, counted against the scenario's
+// allocations instead of the live ones.
+func (s *Service) CreateScenarioAllocation(ctx context.Context, auth ports.AuthContext, scenarioID string, input domain.Allocation) (domain.Allocation, error) {
+	scenario, err := s.editableScenario(ctx, auth, scenarioID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	allocation, err := s.scenarioBatch(scenario).prepareAllocation(ctx, scenario.OrganisationID, input, "", true)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	warnings := allocation.Warnings
+	now := s.now().UTC()
+	allocation.Warnings = nil
+	allocation.ID = nextScenarioAllocationID(scenario)
+	allocation.CreatedAt = now
+	allocation.UpdatedAt = now
+	allocation.Version = 1
+	scenario.Allocations = append(scenario.Allocations, allocation)
+
+	if _, err = s.repo.UpdateScenario(ctx, scenario); err != nil {
+		return domain.Allocation{}, err
+	}
+
+	s.recordChange(ctx, scenario.OrganisationID, "scenario.updated", map[string]string{
+		"scenario_id":   scenario.ID,
+		"allocation_id": allocation.ID,
+	})
+	allocation.Warnings = warnings
+	return allocation, nil
+}
+
+// UpdateScenarioAllocation replaces an allocation of a scenario. It passes
+// the same checks as CreateScenarioAllocation with the replaced allocation
+// left out.
+func (s *Service) UpdateScenarioAllocation(
+	ctx context.Context,
+	auth ports.AuthContext,
+	scenarioID string,
+	allocationID string,
+	input domain.Allocation,
+) (domain.Allocation, error) {
+	scenario, err := s.editableScenario(ctx, auth, scenarioID)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	index := scenarioAllocationIndex(scenario, allocationID)
+	if index < 0 {
+		return domain.Allocation{}, domain.ErrNotFound
+	}
+	current := scenario.Allocations[index]
+	if err = requireCurrentVersion("allocation", input.Version, current.Version); err != nil {
+		return domain.Allocation{}, err
+	}
+	allocation, err := s.scenarioBatch(scenario).prepareAllocation(ctx, scenario.OrganisationID, input, allocationID, true)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+	warnings := allocation.Warnings
+	allocation.Warnings = nil
+	allocation.ID = current.ID
+	allocation.CreatedAt = current.CreatedAt
+	allocation.UpdatedAt = s.now().UTC()
+	allocation.Version = current.Version + 1
+	scenario.Allocations[index] = allocation
+
+	if _, err = s.repo.UpdateScenario(ctx, scenario); err != nil {
+		return domain.Allocation{}, err
+	}
+
+	s.recordChange(ctx, scenario.OrganisationID, "scenario.updated", map[string]string{
+		"scenario_id":   scenario.ID,
+		"allocation_id": allocation.ID,
+	})
+	allocation.Warnings = warnings
+	return allocation, nil
+}
+
+// DeleteScenarioAllocation removes an allocation from a scenario.
+func (s *Service) DeleteScenarioAllocation(ctx context.Context, auth ports.AuthContext, scenarioID, allocationID string) error {
+	scenario, err := s.editableScenario(ctx, auth, scenarioID)
+	if err != nil {
+		return err
+	}
+	index := scenarioAllocationIndex(scenario, allocationID)
+	if index < 0 {
+		return domain.ErrNotFound
+	}
+	scenario.Allocations = append(scenario.Allocations[:index], scenario.Allocations[index+1:]...)
+
+	if _, err = s.repo.UpdateScenario(ctx, scenario); err != nil {
+		return err
+	}
+
+	s.recordChange(ctx, scenario.OrganisationID, "scenario.updated", map[string]string{
+		"scenario_id":   scenario.ID,
+		"allocation_id": allocationID,
+	})
+	return nil
+}
+
+// ApplyScenario writes the changes a scenario made to the live allocations
+// and marks it applied. Created and updated allocations are checked again
+// against the live allocations as they will be after the merge, so changes
+// made outside the scenario since it was created still count. Applying fails
+// with a conflict when a live allocation the scenario updates or deletes
+// changed since the scenario was created. Nothing is stored unless every
+// change passes.
+func (s *Service) ApplyScenario(ctx context.Context, auth ports.AuthContext, scenarioID string) (domain.ScenarioChanges, error) {
+	scenario, err := s.editableScenario(ctx, auth, scenarioID)
+	if err != nil {
+		return domain.ScenarioChanges{}, err
+	}
+	organisationID := scenario.OrganisationID
+	live, err := s.repo.ListAllocations(ctx, organisationID)
+	if err != nil {
+		return domain.ScenarioChanges{}, err
+	}
+	changes := scenario.Changes()
+	replaced := make(map[string]bool, len(changes.Updated)+len(changes.DeletedIDs))
+	for _, allocation := range changes.Updated {
+		replaced[allocation.ID] = true
+	}
+	for _, id := range changes.DeletedIDs {
+		replaced[id] = true
+	}
+	merged := make([]domain.Allocation, 0, len(live)+len(changes.Created))
+	for _, allocation := range live {
+		if !replaced[allocation.ID] {
+			merged = append(merged, allocation)
+		}
+	}
+	merged = append(merged, changes.Updated...)
+	merged = append(merged, changes.Created...)
+
+	batch := *s
+	batch.repo = &scenarioAllocationsRepository{Repository: s.repo, allocations: merged}
+	for _, allocation := range append(append([]domain.Allocation{}, changes.Updated...), changes.Created...) {
+		if _, err = batch.prepareAllocation(ctx, organisationID, allocation, allocation.ID, true); err != nil {
+			return domain.ScenarioChanges{}, fmt.Errorf("scenario allocation %s: %w", allocation.ID, err)
+		}
+	}
+
+	applied, err := s.repo.ApplyScenario(s.withAllocationAudit(ctx, auth), scenario)
+	if err != nil {
+		return domain.ScenarioChanges{}, err
+	}
+	for index := range applied.Created {
+		s.recordChange(ctx, organisationID, "allocation.created", map[string]string{"allocation_id": applied.Created[index].ID})
+	}
+	for index := range applied.Updated {
+		s.recordChange(ctx, organisationID, "allocation.updated", map[string]string{"allocation_id": applied.Updated[index].ID})
+	}
+	for _, id := range applied.DeletedIDs {
+		s.recordChange(ctx, organisationID, "allocation.deleted", map[string]string{"allocation_id": id})
+	}
+
+	s.recordChange(ctx, organisationID, "scenario.applied", map[string]string{
+		"scenario_id": scenario.ID,
+		"created":     strconv.Itoa(len(applied.Created)),
+		"updated":     strconv.Itoa(len(applied.Updated)),
+		"deleted":     strconv.Itoa(len(applied.DeletedIDs)),
+	})
+	return applied, nil
+}
+
+// editableScenario checks that the caller may change scenarios and returns
+// the scenario when it has not been applied yet.
+func (s *Service) editableScenario(ctx context.Context, auth ports.AuthContext, scenarioID string) (domain.Scenario, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return domain.Scenario{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.Scenario{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Scenario{}, err
+	}
+	scenario, err := s.repo.GetScenario(ctx, organisationID, scenarioID)
+	if err != nil {
+		return domain.Scenario{}, err
+	}
+	if scenario.AppliedAt != nil {
+		return domain.Scenario{}, fmt.Errorf("scenario %s has already been applied: %w", scenario.ID, domain.ErrConflict)
+	}
+	return scenario, nil
+}
+
+// scenarioBatch returns a copy of the service whose allocation checks count
+// the scenario's allocations instead of the live ones.
+func (s *Service) scenarioBatch(scenario domain.Scenario) *Service {
+	batch := *s
+	batch.repo = &scenarioAllocationsRepository{Repository: s.repo, allocations: scenario.Allocations}
+	return &batch
+}
+
+func scenarioAllocationIndex(scenario domain.Scenario, allocationID string) int {
+	for index, allocation := range scenario.Allocations {
+		if allocation.ID == allocationID {
+			return index
+		}
+	}
+	return -1
+}
+
+// nextScenarioAllocationID returns an ID one past the highest scenario
+// allocation ID in use.
+func nextScenarioAllocationID(scenario domain.Scenario) string {
+	highest := 0
+	for _, allocation := range scenario.Allocations {
+		suffix, found := strings.CutPrefix(allocation.ID, domain.ScenarioAllocationIDPrefix)
+		if !found {
+			continue
+		}
+		if number, err := strconv.Atoi(suffix); err == nil && number > highest {
+			highest = number
+		}
+	}
+	return domain.ScenarioAllocationIDPrefix + strconv.Itoa(highest+1)
+}
+
+// scenarioAllocationsRepository serves a fixed set of allocations in place of
+// the stored ones, so the allocation checks run against a scenario.
+type scenarioAllocationsRepository struct {
+	ports.Repository
+	allocations []domain.Allocation
+}
+
+// ListAllocations returns a copy of the scenario's allocations.
+func (r *scenarioAllocationsRepository) ListAllocations(_ context.Context, _ string) ([]domain.Allocation, error) {
+	return append([]domain.Allocation{}, r.allocations...), nil
+}
+
+// GetAllocation returns one of the scenario's allocations.
+func (r *scenarioAllocationsRepository) GetAllocation(_ context.Context, _, id string) (domain.Allocation, error) {
+	index := scenarioAllocationIndex(domain.Scenario{Allocations: r.allocations}, id)
+	if index < 0 {
+		return domain.Allocation{}, domain.ErrNotFound
+	}
+	return r.allocations[index], nil
+}
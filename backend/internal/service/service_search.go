@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// maxSearchResults caps how many records one search returns.
+const maxSearchResults = 50
+
+// Search returns the persons, projects, and groups of the caller's
+// organisation whose names hold a word starting with each word of query.
+// The index behind it is built on first use and again after any write.
+func (s *Service) Search(ctx context.Context, auth ports.AuthContext, query string) ([]domain.SearchResult, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.Join(domain.ErrValidation, errors.New("search query is required"))
+	}
+
+	index, err := s.searchIndex(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	return index.Search(query, maxSearchResults), nil
+}
+
+// searchIndex returns the organisation's index, rebuilding it when the
+// repository changed since it was built.
+func (s *Service) searchIndex(ctx context.Context, organisationID string) (domain.SearchIndex, error) {
+	version := s.repo.StateVersion(ctx)
+	if index, ok := s.search.get(organisationID, version); ok {
+		return index, nil
+	}
+
+	persons, err := s.repo.ListPersons(ctx, organisationID)
+	if err != nil {
+		return domain.SearchIndex{}, err
+	}
+	projects, err := s.repo.ListProjects(ctx, organisationID)
+	if err != nil {
+		return domain.SearchIndex{}, err
+	}
+	groups, err := s.repo.ListGroups(ctx, organisationID)
+	if err != nil {
+		return domain.SearchIndex{}, err
+	}
+	index := domain.NewSearchIndex(persons, projects, groups)
+	s.search.put(organisationID, version, index)
+	return index, nil
+}
+
+// searchIndexCache keeps the last index built for each organisation together
+// with the repository state version it was built from.
+type searchIndexCache struct {
+	mu      sync.Mutex
+	entries map[string]searchIndexEntry
+}
+
+type searchIndexEntry struct {
+	version uint64
+	index   domain.SearchIndex
+}
+
+func newSearchIndexCache() *searchIndexCache {
+	return &searchIndexCache{entries: map[string]searchIndexEntry{}}
+}
+
+func (c *searchIndexCache) get(organisationID string, version uint64) (domain.SearchIndex, bool) {
+	if c == nil {
+		return domain.SearchIndex{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[organisationID]
+	if !ok || entry.version != version {
+		return domain.SearchIndex{}, false
+	}
+	return entry.index, true
+}
+
+func (c *searchIndexCache) put(organisationID string, version uint64, index domain.SearchIndex) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[organisationID] = searchIndexEntry{version: version, index: index}
+}
@@ -3,9 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
+	"net/netip"
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -241,7 +246,7 @@ func createServiceResourceFlowProjects(ctx context.Context, t *testing.T, state
 		t.Fatalf("unexpected project2 update: %+v", state.project2)
 	}
 
-	projectList, err := state.svc.ListProjects(ctx, state.user)
+	projectList, err := state.svc.ListProjects(ctx, state.user, false)
 	if err != nil {
 		t.Fatalf("list projects as user: %v", err)
 	}
@@ -515,23 +520,23 @@ func validateServiceResourceFlowCleanup(ctx context.Context, t *testing.T, state
 	if err != nil {
 		t.Fatalf("delete group allocation: %v", err)
 	}
-	err = state.svc.DeleteGroup(ctx, state.admin, state.group.ID)
+	err = state.svc.DeleteGroup(ctx, state.admin, state.group.ID, false)
 	if err != nil {
 		t.Fatalf("delete group: %v", err)
 	}
-	err = state.svc.DeleteProject(ctx, state.admin, state.project2.ID)
+	err = state.svc.DeleteProject(ctx, state.admin, state.project2.ID, false, false)
 	if err != nil {
 		t.Fatalf("delete project2: %v", err)
 	}
-	err = state.svc.DeleteProject(ctx, state.admin, state.project1.ID)
+	err = state.svc.DeleteProject(ctx, state.admin, state.project1.ID, false, false)
 	if err != nil {
 		t.Fatalf("delete project1: %v", err)
 	}
-	err = state.svc.DeletePerson(ctx, state.admin, state.person2.ID)
+	err = state.svc.DeletePerson(ctx, state.admin, state.person2.ID, false)
 	if err != nil {
 		t.Fatalf("delete person2: %v", err)
 	}
-	err = state.svc.DeletePerson(ctx, state.admin, state.person1.ID)
+	err = state.svc.DeletePerson(ctx, state.admin, state.person1.ID, false)
 	if err != nil {
 		t.Fatalf("delete person1: %v", err)
 	}
@@ -615,7 +620,7 @@ func assertServiceValidationTenantListGuards(ctx context.Context, t *testing.T,
 	if _, err := svc.ListPersons(ctx, ports.AuthContext{Roles: []string{domain.RoleOrgAdmin}}); !errors.Is(err, domain.ErrForbidden) {
 		t.Fatalf("expected forbidden when tenant missing for list persons, got %v", err)
 	}
-	if _, err := svc.ListProjects(ctx, ports.AuthContext{Roles: []string{domain.RoleOrgUser}}); !errors.Is(err, domain.ErrForbidden) {
+	if _, err := svc.ListProjects(ctx, ports.AuthContext{Roles: []string{domain.RoleOrgUser}}, false); !errors.Is(err, domain.ErrForbidden) {
 		t.Fatalf("expected forbidden when tenant missing for list projects, got %v", err)
 	}
 	if _, err := svc.ListGroups(ctx, ports.AuthContext{Roles: []string{domain.RoleOrgUser}}); !errors.Is(err, domain.ErrForbidden) {
@@ -730,7 +735,7 @@ func validateServiceRemainingErrorBranchesForbiddenReads(t *testing.T, state *se
 	if _, err := state.svc.ListPersons(ctx, ports.AuthContext{OrganisationID: state.organisation.ID, Roles: []string{}}); !errors.Is(err, domain.ErrForbidden) {
 		t.Fatalf("expected forbidden list persons without role, got %v", err)
 	}
-	if _, err := state.svc.ListProjects(ctx, ports.AuthContext{OrganisationID: state.organisation.ID, Roles: []string{}}); !errors.Is(err, domain.ErrForbidden) {
+	if _, err := state.svc.ListProjects(ctx, ports.AuthContext{OrganisationID: state.organisation.ID, Roles: []string{}}, false); !errors.Is(err, domain.ErrForbidden) {
 		t.Fatalf("expected forbidden list projects without role, got %v", err)
 	}
 	if _, err := state.svc.ListGroups(ctx, ports.AuthContext{OrganisationID: state.organisation.ID, Roles: []string{}}); !errors.Is(err, domain.ErrForbidden) {
@@ -822,21 +827,21 @@ func assertServiceRemainingDeleteMissingEntities(ctx context.Context, t *testing
 		{
 			name: "delete missing person",
 			run: func() error {
-				return state.svc.DeletePerson(ctx, state.admin, testMissingID)
+				return state.svc.DeletePerson(ctx, state.admin, testMissingID, false)
 			},
 			want: domain.ErrNotFound,
 		},
 		{
 			name: "delete missing project",
 			run: func() error {
-				return state.svc.DeleteProject(ctx, state.admin, testMissingID)
+				return state.svc.DeleteProject(ctx, state.admin, testMissingID, false, false)
 			},
 			want: domain.ErrNotFound,
 		},
 		{
 			name: "delete missing group",
 			run: func() error {
-				return state.svc.DeleteGroup(ctx, state.admin, testMissingID)
+				return state.svc.DeleteGroup(ctx, state.admin, testMissingID, false)
 			},
 			want: domain.ErrNotFound,
 		},
@@ -1029,6 +1034,2474 @@ func TestServicePersonUnavailabilityEmploymentDailyCap(t *testing.T) {
 	}
 }
 
+// TestServiceSearchOrganisations verifies the service search organisations scenario.
+func TestServiceSearchOrganisations(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	acme := createOrganisationForService(ctx, t, svc, globalAdmin, "Acme Consulting")
+	createOrganisationForService(ctx, t, svc, globalAdmin, "Globex")
+	acmeLabs := createOrganisationForService(ctx, t, svc, globalAdmin, "ACME Labs")
+
+	organisationIDs := func(auth ports.AuthContext, query string) []string {
+		t.Helper()
+		organisations, err := svc.SearchOrganisations(ctx, auth, query)
+		if err != nil {
+			t.Fatalf("search organisations for %q: %v", query, err)
+		}
+		ids := make([]string, 0, len(organisations))
+		for _, organisation := range organisations {
+			ids = append(ids, organisation.ID)
+		}
+		return ids
+	}
+
+	if ids := organisationIDs(globalAdmin, ""); len(ids) != 3 {
+		t.Fatalf("expected an empty query to list every organisation, got %v", ids)
+	}
+	if ids := organisationIDs(globalAdmin, "  acme "); !reflect.DeepEqual(ids, []string{acmeLabs.ID, acme.ID}) {
+		t.Fatalf("expected case-insensitive matches across organisations, got %v", ids)
+	}
+	if ids := organisationIDs(globalAdmin, "initech"); len(ids) != 0 {
+		t.Fatalf("expected no matches, got %v", ids)
+	}
+
+	tenantAdmin := ports.AuthContext{UserID: "tenant-admin", OrganisationID: acme.ID, Roles: []string{domain.RoleOrgAdmin}}
+	if ids := organisationIDs(tenantAdmin, "acme"); !reflect.DeepEqual(ids, []string{acme.ID}) {
+		t.Fatalf("expected a tenant admin to only find their own organisation, got %v", ids)
+	}
+	if ids := organisationIDs(tenantAdmin, "labs"); len(ids) != 0 {
+		t.Fatalf("expected a tenant admin not to find another organisation, got %v", ids)
+	}
+
+	if _, err := svc.SearchOrganisations(ctx, globalAdmin, strings.Repeat("a", maxOrganisationSearchLength+1)); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected an overlong query to fail validation, got %v", err)
+	}
+}
+
+// TestServiceUniqueOrganisationNames verifies the service unique organisation names scenario.
+func TestServiceUniqueOrganisationNames(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	acme := createOrganisationForService(ctx, t, svc, globalAdmin, "Acme")
+	duplicate := domain.Organisation{Name: " acme ", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}
+	if _, err := svc.CreateOrganisation(ctx, globalAdmin, duplicate); err != nil {
+		t.Fatalf("expected duplicate names to be allowed by default, got %v", err)
+	}
+
+	svc.SetUniqueOrganisationNames(true)
+	if _, err := svc.CreateOrganisation(ctx, globalAdmin, duplicate); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a duplicate name to fail validation, got %v", err)
+	}
+	globex := createOrganisationForService(ctx, t, svc, globalAdmin, "Globex")
+	rename := domain.Organisation{Name: "ACME", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}
+	if _, err := svc.UpdateOrganisation(ctx, globalAdmin, globex.ID, rename); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a rename onto a taken name to fail validation, got %v", err)
+	}
+	if _, err := svc.UpdateOrganisation(ctx, globalAdmin, acme.ID, rename); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected the earlier duplicate to still block the name, got %v", err)
+	}
+	rename.Name = "Globex"
+	if _, err := svc.UpdateOrganisation(ctx, globalAdmin, globex.ID, rename); err != nil {
+		t.Fatalf("expected an organisation to keep its own name, got %v", err)
+	}
+}
+
+// TestServiceFractionalEmploymentPct verifies the service fractional employment pct scenario.
+func TestServiceFractionalEmploymentPct(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Fractional")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	if _, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Too Much", EmploymentPct: 100.5}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected 100.5%% employment to fail validation, got %v", err)
+	}
+	if _, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Too Precise", EmploymentPct: 37.125}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected three decimal places to fail validation, got %v", err)
+	}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Three Eighths", EmploymentPct: 37.5})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	if _, err = svc.CreatePersonUnavailability(ctx, admin, domain.PersonUnavailability{PersonID: person.ID, Date: "2026-03-02", Hours: 1.5}); err != nil {
+		t.Fatalf("expected unavailability within the 3 hour cap, got %v", err)
+	}
+	if _, err = svc.CreatePersonUnavailability(ctx, admin, domain.PersonUnavailability{PersonID: person.ID, Date: "2026-03-02", Hours: 1.5}); err != nil {
+		t.Fatalf("expected unavailability reaching the 3 hour cap, got %v", err)
+	}
+	if _, err = svc.CreatePersonUnavailability(ctx, admin, domain.PersonUnavailability{PersonID: person.ID, Date: "2026-03-03", Hours: 3.5}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected unavailability above the 3 hour cap to fail, got %v", err)
+	}
+
+	if _, err = svc.UpdatePerson(ctx, admin, person.ID, domain.Person{Name: person.Name, EmploymentPct: 100.5, EmploymentEffectiveFromMonth: "2026-06"}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected out of range employment change to fail validation, got %v", err)
+	}
+	if _, err = svc.UpdatePerson(ctx, admin, person.ID, domain.Person{Name: person.Name, EmploymentPct: 62.5, EmploymentEffectiveFromMonth: "2026-06"}); err != nil {
+		t.Fatalf("expected fractional employment change to pass, got %v", err)
+	}
+	if _, err = svc.CreatePersonUnavailability(ctx, admin, domain.PersonUnavailability{PersonID: person.ID, Date: "2026-06-01", Hours: 5}); err != nil {
+		t.Fatalf("expected unavailability within the 5 hour cap after the change, got %v", err)
+	}
+	if _, err = svc.CreatePersonUnavailability(ctx, admin, domain.PersonUnavailability{PersonID: person.ID, Date: "2026-06-02", Hours: 5.5}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected unavailability above the 5 hour cap to fail, got %v", err)
+	}
+}
+
+// TestServiceOrgHolidayPersonTargets verifies the service org holiday person targets scenario.
+func TestServiceOrgHolidayPersonTargets(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Regional Holidays")
+	otherOrganisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Other Region")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	otherAdmin := ports.AuthContext{UserID: "admin2", OrganisationID: otherOrganisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Regional Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	foreignPerson, err := svc.CreatePerson(ctx, otherAdmin, domain.Person{Name: "Foreign Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+
+	holiday, err := svc.CreateOrgHoliday(ctx, admin, domain.OrgHoliday{
+		Date:      testDate20260101,
+		Hours:     4,
+		PersonIDs: []string{" " + person.ID + " ", person.ID},
+	})
+	if err != nil {
+		t.Fatalf("create targeted holiday: %v", err)
+	}
+	if !reflect.DeepEqual(holiday.PersonIDs, []string{person.ID}) {
+		t.Fatalf("expected normalized holiday targets, got %v", holiday.PersonIDs)
+	}
+
+	for _, personIDs := range [][]string{{testMissingID}, {foreignPerson.ID}, {""}} {
+		_, err = svc.CreateOrgHoliday(ctx, admin, domain.OrgHoliday{Date: testDate20260101, Hours: 4, PersonIDs: personIDs})
+		if !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected validation error for holiday targets %v, got %v", personIDs, err)
+		}
+	}
+}
+
+// TestServiceImportOrgHolidays verifies the service import org holidays scenario.
+func TestServiceImportOrgHolidays(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Holiday Import")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Holiday Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	if _, err = svc.CreateOrgHoliday(ctx, admin, domain.OrgHoliday{Date: "2026-12-25", Name: " Christmas ", Hours: 8}); err != nil {
+		t.Fatalf("create holiday: %v", err)
+	}
+	if _, err = svc.CreateOrgHoliday(ctx, admin, domain.OrgHoliday{Date: "2026-10-03", Hours: 8, PersonIDs: []string{person.ID}}); err != nil {
+		t.Fatalf("create targeted holiday: %v", err)
+	}
+
+	result, err := svc.ImportOrgHolidays(ctx, admin, domain.HolidayImportRequest{Year: 2026, Country: "DE", Region: "BY"})
+	if err != nil {
+		t.Fatalf("import holidays: %v", err)
+	}
+	if len(result.Created) != 11 || len(result.Skipped) != 1 || result.Skipped[0].Date != "2026-12-25" {
+		t.Fatalf("expected 11 created and Christmas skipped, got %d created and %+v skipped", len(result.Created), result.Skipped)
+	}
+	if first := result.Created[0]; first.Date != "2026-01-01" || first.Name != "New Year's Day" || first.Hours != 8 || first.ID == "" {
+		t.Fatalf("unexpected first imported holiday %+v", first)
+	}
+
+	again, err := svc.ImportOrgHolidays(ctx, admin, domain.HolidayImportRequest{Year: 2026, Country: "DE", Region: "BY"})
+	if err != nil || len(again.Created) != 0 || len(again.Skipped) != 12 {
+		t.Fatalf("expected a repeated import to skip every date, got %+v err=%v", again, err)
+	}
+
+	calendar := []byte("BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20270101\nSUMMARY:New Year\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20270101\nSUMMARY:Duplicate\nEND:VEVENT\nEND:VCALENDAR\n")
+	fromCalendar, err := svc.ImportOrgHolidays(ctx, admin, domain.HolidayImportRequest{Year: 2027, Hours: 4, Calendar: calendar})
+	if err != nil || len(fromCalendar.Created) != 1 || fromCalendar.Created[0].Hours != 4 || len(fromCalendar.Skipped) != 1 {
+		t.Fatalf("expected one calendar holiday and the duplicate skipped, got %+v err=%v", fromCalendar, err)
+	}
+
+	if _, err = svc.ImportOrgHolidays(ctx, user, domain.HolidayImportRequest{Year: 2026, Country: "DE"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user import to be forbidden, got %v", err)
+	}
+	for _, invalid := range []domain.HolidayImportRequest{
+		{Year: 26, Country: "DE"},
+		{Year: 2026},
+		{Year: 2026, Country: "DE", Calendar: calendar},
+		{Year: 2026, Region: "BY", Calendar: calendar},
+		{Year: 2026, Country: "DE", Hours: 9},
+		{Year: 2026, Country: "DE", Hours: -1},
+		{Year: 2026, Country: "XX"},
+	} {
+		if _, err = svc.ImportOrgHolidays(ctx, admin, invalid); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected validation error for %+v, got %v", invalid, err)
+		}
+	}
+}
+
+// TestServiceGroupMembershipAllocationLimit verifies the service group membership allocation limit scenario.
+func TestServiceGroupMembershipAllocationLimit(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Membership Limit")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Membership Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	member, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Member", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	busy, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Busy", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	group, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Heavy Group", MemberIDs: []string{member.ID}})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testGroupAllocationInput(group.ID, project.ID, 150)); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(busy.ID, project.ID, 200)); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	organisation.EnforceMembershipAllocationLimit = true
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("enable membership allocation limit: %v", err)
+	}
+
+	_, err = svc.AddGroupMember(ctx, admin, group.ID, busy.ID)
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected add member breach to fail validation, got %v", err)
+	}
+	_, err = svc.UpdateGroup(ctx, admin, group.ID, domain.Group{Name: "Heavy Group", MemberIDs: []string{member.ID, busy.ID}})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected group update breach to fail validation, got %v", err)
+	}
+	if _, err = svc.UpdateGroup(ctx, admin, group.ID, domain.Group{Name: "Renamed Group", MemberIDs: []string{member.ID}}); err != nil {
+		t.Fatalf("expected update without new members to pass, got %v", err)
+	}
+
+	organisation.EnforceMembershipAllocationLimit = false
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("disable membership allocation limit: %v", err)
+	}
+	updated, err := svc.AddGroupMember(ctx, admin, group.ID, busy.ID)
+	if err != nil {
+		t.Fatalf("expected permissive default to allow member, got %v", err)
+	}
+	if len(updated.MemberIDs) != 2 {
+		t.Fatalf("expected two members, got %v", updated.MemberIDs)
+	}
+}
+
+// TestServiceReportCurrentMonthUsesOrganisationTimezone verifies the service report current month uses organisation timezone scenario.
+func TestServiceReportCurrentMonthUsesOrganisationTimezone(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	svc.now = func() time.Time { return time.Date(2026, time.January, 31, 12, 0, 0, 0, time.UTC) }
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+
+	if _, err := svc.CreateOrganisation(ctx, globalAdmin, domain.Organisation{
+		Name: "Org Bad Zone", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080, Timezone: "Nowhere/Special",
+	}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected invalid time zone to fail validation, got %v", err)
+	}
+
+	currentMonth := func(timezone string) string {
+		t.Helper()
+		organisation, err := svc.CreateOrganisation(ctx, globalAdmin, domain.Organisation{
+			Name: "Org " + timezone, HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080, Timezone: timezone,
+		})
+		if err != nil {
+			t.Fatalf("create organisation in %q: %v", timezone, err)
+		}
+		auth := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+		buckets, err := svc.ReportAvailabilityAndLoad(ctx, auth, domain.ReportRequest{
+			Scope:       domain.ScopeOrganisation,
+			Granularity: domain.GranularityMonth,
+		})
+		if err != nil || len(buckets) != 1 {
+			t.Fatalf("current month report in %q: buckets=%+v err=%v", timezone, buckets, err)
+		}
+		return buckets[0].PeriodStart
+	}
+
+	if got := currentMonth(""); got != testDate20260101 {
+		t.Fatalf("expected UTC tenant to resolve January, got %s", got)
+	}
+	if got := currentMonth("Pacific/Kiritimati"); got != "2026-02-01" {
+		t.Fatalf("expected UTC+14 tenant to resolve February, got %s", got)
+	}
+	if got := currentMonth("Pacific/Pago_Pago"); got != testDate20260101 {
+		t.Fatalf("expected UTC-11 tenant to resolve January, got %s", got)
+	}
+}
+
+// TestServiceOrganisationOverloadBands verifies the service organisation overload bands scenario.
+func TestServiceOrganisationOverloadBands(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	admin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	input := domain.Organisation{Name: "Org Bands", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080, OverloadModeratePct: 10, OverloadSeverePct: 40}
+
+	created, err := svc.CreateOrganisation(ctx, admin, input)
+	if err != nil {
+		t.Fatalf("create organisation with bands: %v", err)
+	}
+	if created.OverloadModeratePct != 10 || created.OverloadSeverePct != 40 {
+		t.Fatalf("expected bands to be stored, got %+v", created)
+	}
+
+	for _, bands := range [][2]float64{{50, 40}, {-5, 40}, {150, 0}} {
+		input.OverloadModeratePct, input.OverloadSeverePct = bands[0], bands[1]
+		if _, err = svc.UpdateOrganisation(ctx, admin, created.ID, input); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected bands %v to be rejected, got %v", bands, err)
+		}
+	}
+
+	input.OverloadModeratePct, input.OverloadSeverePct = 0, 0
+	updated, err := svc.UpdateOrganisation(ctx, admin, created.ID, input)
+	if err != nil {
+		t.Fatalf("reset bands: %v", err)
+	}
+	if updated.OverloadModeratePct != 0 || updated.OverloadSeverePct != 0 {
+		t.Fatalf("expected bands to reset to defaults, got %+v", updated)
+	}
+}
+
+// TestServiceOrganisationHoursBaselines verifies the service organisation hours baselines scenario.
+func TestServiceOrganisationHoursBaselines(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	admin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	input := domain.Organisation{
+		Name:         "Org Baselines",
+		HoursPerDay:  8,
+		HoursPerWeek: 40,
+		HoursPerYear: 2080,
+		HoursBaselines: []domain.HoursBaseline{
+			{StartDate: "2027-01-01", HoursPerDay: 7.5, HoursPerWeek: 37.5, HoursPerYear: 1950},
+			{StartDate: " 2026-01-01 ", EndDate: "2026-12-31", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+		},
+	}
+
+	created, err := svc.CreateOrganisation(ctx, admin, input)
+	if err != nil {
+		t.Fatalf("create organisation with baselines: %v", err)
+	}
+	if len(created.HoursBaselines) != 2 || created.HoursBaselines[0].StartDate != "2026-01-01" || created.HoursBaselines[1].StartDate != "2027-01-01" {
+		t.Fatalf("expected baselines to be normalized and ordered by start date, got %+v", created.HoursBaselines)
+	}
+
+	orgAdmin := ports.AuthContext{UserID: "admin", OrganisationID: created.ID, Roles: []string{domain.RoleOrgAdmin}}
+	if _, err = svc.CreateOrgHoliday(ctx, orgAdmin, domain.OrgHoliday{Date: "2027-05-03", Hours: 8}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a holiday longer than the 2027 working day to be rejected, got %v", err)
+	}
+	if _, err = svc.CreateOrgHoliday(ctx, orgAdmin, domain.OrgHoliday{Date: "2026-05-04", Hours: 8}); err != nil {
+		t.Fatalf("create holiday within the 2026 working day: %v", err)
+	}
+
+	invalid := [][]domain.HoursBaseline{
+		{
+			{StartDate: "2026-01-01", EndDate: "2026-12-31", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+			{StartDate: "2026-12-31", HoursPerDay: 7.5, HoursPerWeek: 37.5, HoursPerYear: 1950},
+		},
+		{
+			{StartDate: "2026-01-01", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080},
+			{StartDate: "2028-01-01", EndDate: "2028-12-31", HoursPerDay: 7.5, HoursPerWeek: 37.5, HoursPerYear: 1950},
+		},
+		{{StartDate: "2026-12-31", EndDate: "2026-01-01", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080}},
+		{{StartDate: "2026-01-01", HoursPerDay: -1, HoursPerWeek: 40, HoursPerYear: 2080}},
+	}
+	for _, baselines := range invalid {
+		input.HoursBaselines = baselines
+		input.Version = created.Version
+		if _, err = svc.UpdateOrganisation(ctx, admin, created.ID, input); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected baselines %+v to be rejected, got %v", baselines, err)
+		}
+	}
+
+	input.HoursBaselines = nil
+	input.Version = created.Version
+	updated, err := svc.UpdateOrganisation(ctx, admin, created.ID, input)
+	if err != nil {
+		t.Fatalf("clear baselines: %v", err)
+	}
+	if len(updated.HoursBaselines) != 0 {
+		t.Fatalf("expected baselines to be cleared, got %+v", updated.HoursBaselines)
+	}
+}
+
+// TestServiceGetPersonsByIDsValidation verifies the service get persons by IDs validation scenario.
+func TestServiceGetPersonsByIDsValidation(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Batch")
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	if _, err := svc.GetPersonsByIDs(ctx, ports.AuthContext{OrganisationID: organisation.ID}, []string{"p"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected missing role to be forbidden, got %v", err)
+	}
+	tooMany := make([]string, maxBatchPersonIDs+1)
+	for idx := range tooMany {
+		tooMany[idx] = "person_" + strconv.Itoa(idx)
+	}
+	if _, err := svc.GetPersonsByIDs(ctx, user, tooMany); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected oversized batch to fail validation, got %v", err)
+	}
+	batch, err := svc.GetPersonsByIDs(ctx, user, []string{testMissingID})
+	if err != nil {
+		t.Fatalf("batch lookup: %v", err)
+	}
+	if len(batch.Persons) != 0 || len(batch.MissingIDs) != 1 {
+		t.Fatalf("expected one missing ID, got %+v", batch)
+	}
+}
+
+// TestServiceTenantQuotas verifies the service tenant quotas scenario.
+func TestServiceTenantQuotas(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Quota")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	for idx := 0; idx < 3; idx++ {
+		if _, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Unlimited " + strconv.Itoa(idx), EmploymentPct: 100}); err != nil {
+			t.Fatalf("expected unlimited persons by default: %v", err)
+		}
+	}
+
+	svc.SetQuotas(Quotas{MaxPersonsPerOrganisation: 4, MaxProjectsPerOrganisation: 2})
+	if _, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Fourth", EmploymentPct: 100}); err != nil {
+		t.Fatalf("expected person up to the limit to succeed: %v", err)
+	}
+	_, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Fifth", EmploymentPct: 100})
+	if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "limit of 4 persons") {
+		t.Fatalf("expected person quota error, got %v", err)
+	}
+
+	for idx := 0; idx < 2; idx++ {
+		if _, err = svc.CreateProject(ctx, admin, testProjectInput("Quota Project "+strconv.Itoa(idx))); err != nil {
+			t.Fatalf("expected project up to the limit to succeed: %v", err)
+		}
+	}
+	_, err = svc.CreateProject(ctx, admin, testProjectInput("Quota Project Overflow"))
+	if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "limit of 2 projects") {
+		t.Fatalf("expected project quota error, got %v", err)
+	}
+
+	otherOrganisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Quota Other")
+	otherAdmin := ports.AuthContext{UserID: "admin2", OrganisationID: otherOrganisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	if _, err = svc.CreateProject(ctx, otherAdmin, testProjectInput("Other Tenant Project")); err != nil {
+		t.Fatalf("expected quotas to be counted per organisation: %v", err)
+	}
+}
+
+type failingTelemetry struct{}
+
+func (failingTelemetry) Record(name string, _ map[string]string) {
+	panic("metrics backend unavailable for " + name)
+}
+
+// TestServiceToleratesFailingTelemetry verifies the service tolerates failing telemetry scenario.
+func TestServiceToleratesFailingTelemetry(t *testing.T) {
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "service-data.json"))
+	if err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	svc, err := New(repo, failingTelemetry{}, impexp.NewNoopImportExport())
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+	var logged []string
+	svc.telemetry = resilientTelemetry{next: failingTelemetry{}, logf: func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}}
+
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Telemetry Down")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Resilient", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf("expected create person to succeed despite telemetry failure: %v", err)
+	}
+	if person.ID == "" {
+		t.Fatal("expected created person to be persisted")
+	}
+	if len(logged) != 2 || !strings.Contains(logged[1], `"person.created"`) || !strings.Contains(logged[1], "metrics backend unavailable") {
+		t.Fatalf("expected telemetry failures to be logged, got %v", logged)
+	}
+}
+
+type capturingPublisher struct {
+	events []domain.Event
+}
+
+func (p *capturingPublisher) Publish(event domain.Event) {
+	p.events = append(p.events, event)
+}
+
+// TestServiceRejectsPrivateWebhookTargets verifies the webhook address scenario.
+func TestServiceRejectsPrivateWebhookTargets(t *testing.T) {
+	svc := newTestService(t)
+	svc.lookupIP = func(_ context.Context, host string) ([]netip.Addr, error) {
+		switch host {
+		case "hooks.example.com":
+			return []netip.Addr{netip.MustParseAddr("203.0.113.10")}, nil
+		case "mixed.example.com":
+			return []netip.Addr{netip.MustParseAddr("203.0.113.10"), netip.MustParseAddr("192.168.1.10")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Webhook Targets")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	events := []string{domain.WebhookEventAll}
+
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.1.2.3/hook",
+		"http://[::ffff:192.168.0.1]/hook",
+		"http://0.0.0.0/hook",
+		"https://mixed.example.com/hook",
+		"https://unknown.example.com/hook",
+	} {
+		if _, err := svc.CreateWebhook(ctx, admin, domain.Webhook{URL: target, Events: events}); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected %s to be rejected, got %v", target, err)
+		}
+	}
+	if _, err := svc.CreateWebhook(ctx, admin, domain.Webhook{URL: "https://hooks.example.com/hook", Events: events}); err != nil {
+		t.Fatalf("create public webhook: %v", err)
+	}
+
+	svc.SetAllowPrivateWebhooks(true)
+	if _, err := svc.CreateWebhook(ctx, admin, domain.Webhook{URL: "http://127.0.0.1:8080/hook", Events: events}); err != nil {
+		t.Fatalf("expected private webhooks to be allowed when enabled: %v", err)
+	}
+}
+
+// TestServicePublishesChangeEvents verifies the service webhook event scenario.
+func TestServicePublishesChangeEvents(t *testing.T) {
+	svc := newTestService(t)
+	svc.lookupIP = func(context.Context, string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr("203.0.113.10")}, nil
+	}
+	publisher := &capturingPublisher{}
+	svc.SetEventPublisher(publisher)
+
+	ctx := ports.WithRequestID(context.Background(), "req-1")
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Webhooks")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	if _, err := svc.CreateWebhook(ctx, admin, domain.Webhook{URL: "https://example.com/hook", Events: []string{"person.created", " person.created"}}); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	webhooks, err := svc.ListWebhooks(ctx, admin)
+	if err != nil || len(webhooks) != 1 || webhooks[0].Secret != "" || !reflect.DeepEqual(webhooks[0].Events, []string{"person.created"}) {
+		t.Fatalf("expected one deduplicated webhook without secret, got %+v err=%v", webhooks, err)
+	}
+	if _, err = svc.CreateWebhook(ctx, admin, domain.Webhook{URL: "https://example.com/hook"}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected empty events to be rejected, got %v", err)
+	}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Published", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected only the person change to be published, got %+v", publisher.events)
+	}
+	event := publisher.events[0]
+	if event.Name != "person.created" || event.OrganisationID != organisation.ID || event.RequestID != "req-1" || event.Data["person_id"] != person.ID {
+		t.Fatalf("unexpected published event %+v", event)
+	}
+}
+
+// TestServiceAllocationEmploymentWindow verifies the service allocation employment window scenario.
+func TestServiceAllocationEmploymentWindow(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Employment Window")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Window Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Starter", EmploymentPct: 0})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	for _, change := range []domain.Person{
+		{Name: "Starter", EmploymentPct: 100, EmploymentEffectiveFromMonth: "2026-04"},
+		{Name: "Starter", EmploymentPct: 0, EmploymentEffectiveFromMonth: "2026-10"},
+	} {
+		if _, err = svc.UpdatePerson(ctx, admin, person.ID, change); err != nil {
+			t.Fatalf("setup employment change: %v", err)
+		}
+	}
+
+	beforeStart := testPersonAllocationInputForRange(person.ID, project.ID, 50, testDate20260101, "2026-03-31")
+	if _, err = svc.CreateAllocation(ctx, admin, beforeStart); err != nil {
+		t.Fatalf("expected allocation before start to pass while the setting is off: %v", err)
+	}
+
+	organisation.RequireEmploymentForAllocations = true
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("enable employment window setting: %v", err)
+	}
+
+	_, err = svc.CreateAllocation(ctx, admin, beforeStart)
+	if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "between 2026-01-01 and 2026-03-31") {
+		t.Fatalf("expected allocation before start to be rejected with its dates, got %v", err)
+	}
+	afterLeaving := testPersonAllocationInputForRange(person.ID, project.ID, 50, "2026-10-01", "2026-12-31")
+	if _, err = svc.CreateAllocation(ctx, admin, afterLeaving); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected allocation after leaving to be rejected, got %v", err)
+	}
+	overlapping := testPersonAllocationInputForRange(person.ID, project.ID, 50, "2026-03-01", "2026-04-30")
+	if _, err = svc.CreateAllocation(ctx, admin, overlapping); err != nil {
+		t.Fatalf("expected allocation overlapping employment to pass: %v", err)
+	}
+}
+
+// TestServiceAllocationProjectEffortCapacity verifies the service allocation project effort capacity scenario.
+func TestServiceAllocationProjectEffortCapacity(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Effort Capacity")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Capped Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Planner", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+
+	overflow := testPersonAllocationInputForRange(person.ID, project.ID, 100, "2026-05-05", "2026-05-06")
+	// 100 days at 8 hours commits 800 of the 1000 estimated hours.
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 100, testDate20260101, "2026-04-10")); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	organisation.RejectAllocationsOverProjectEffort = true
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("enable project effort capacity setting: %v", err)
+	}
+
+	// 24 more days commit 992 hours, just under the estimate.
+	justUnder, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 100, "2026-04-11", "2026-05-04"))
+	if err != nil {
+		t.Fatalf("expected allocation just under the project effort to pass: %v", err)
+	}
+	_, err = svc.CreateAllocation(ctx, admin, overflow)
+	if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "by 8.00 hours") {
+		t.Fatalf("expected allocation just over the project effort to report the overage, got %v", err)
+	}
+
+	// Updating an allocation does not count its previous commitment twice.
+	if _, err = svc.UpdateAllocation(ctx, admin, justUnder.ID, testPersonAllocationInputForRange(person.ID, project.ID, 100, "2026-04-11", "2026-05-05")); err != nil {
+		t.Fatalf("expected update up to the project effort to pass: %v", err)
+	}
+
+	organisation.RejectAllocationsOverProjectEffort = false
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("disable project effort capacity setting: %v", err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, overflow); err != nil {
+		t.Fatalf("expected overflow to pass when the setting is off: %v", err)
+	}
+}
+
+// TestServiceReportWhatIf verifies the service report what-if scenario.
+func TestServiceReportWhatIf(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org What If")
+	admin := ports.AuthContext{UserID: "admin", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Planner", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("What If Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 50)); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	report := domain.ReportRequest{Scope: domain.ScopePerson, IDs: []string{person.ID}, FromDate: testDate20260101, ToDate: testDate20260101, Granularity: domain.GranularityDay}
+	baseline, err := svc.ReportAvailabilityAndLoad(ctx, user, report)
+	if err != nil || len(baseline) != 1 {
+		t.Fatalf("baseline report: buckets=%+v err=%v", baseline, err)
+	}
+	whatIf, err := svc.ReportWhatIf(ctx, user, domain.WhatIfReportRequest{
+		ReportRequest: report,
+		ProposedAllocations: []domain.Allocation{
+			testPersonAllocationInput(person.ID, project.ID, 200),
+			testPersonAllocationInputForRange(person.ID, project.ID, 100, "2026-02-01", "2026-02-28"),
+		},
+	})
+	if err != nil || len(whatIf) != 1 {
+		t.Fatalf("what-if report: buckets=%+v err=%v", whatIf, err)
+	}
+	if baseline[0].LoadHours != 4 || whatIf[0].LoadHours != 20 {
+		t.Fatalf("expected what-if to add 16 hours over the baseline 4, got baseline=%v what-if=%v", baseline[0].LoadHours, whatIf[0].LoadHours)
+	}
+
+	allocations, err := svc.ListAllocations(ctx, user)
+	if err != nil || len(allocations) != 1 {
+		t.Fatalf("expected what-if to persist nothing, got %+v err=%v", allocations, err)
+	}
+
+	if _, err = svc.ReportWhatIf(ctx, user, domain.WhatIfReportRequest{ReportRequest: report}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected empty proposal to fail validation, got %v", err)
+	}
+	outsideProject := testPersonAllocationInputForRange(person.ID, project.ID, 10, "2025-12-01", "2026-01-31")
+	if _, err = svc.ReportWhatIf(ctx, user, domain.WhatIfReportRequest{ReportRequest: report, ProposedAllocations: []domain.Allocation{outsideProject}}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected proposal outside the project to fail validation, got %v", err)
+	}
+	unknownTarget := testPersonAllocationInput(testMissingID, project.ID, 10)
+	if _, err = svc.ReportWhatIf(ctx, user, domain.WhatIfReportRequest{ReportRequest: report, ProposedAllocations: []domain.Allocation{unknownTarget}}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected unknown proposal target to be not found, got %v", err)
+	}
+
+	undated := testPersonAllocationInputForRange(person.ID, project.ID, 50, "", "")
+	whatIf, err = svc.ReportWhatIf(ctx, user, domain.WhatIfReportRequest{ReportRequest: report, ProposedAllocations: []domain.Allocation{undated}})
+	if err != nil || len(whatIf) != 1 || whatIf[0].LoadHours != 8 {
+		t.Fatalf("expected an undated proposal to cover the project window, got buckets=%+v err=%v", whatIf, err)
+	}
+	if err = svc.DeleteProject(ctx, admin, project.ID, false, false); err != nil {
+		t.Fatalf("archive project: %v", err)
+	}
+	archived := testPersonAllocationInput(person.ID, project.ID, 10)
+	if _, err = svc.ReportWhatIf(ctx, user, domain.WhatIfReportRequest{ReportRequest: report, ProposedAllocations: []domain.Allocation{archived}}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected proposal on an archived project to fail validation, got %v", err)
+	}
+}
+
+// TestServiceReportDiff verifies the service report diff scenario.
+func TestServiceReportDiff(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Report Diff")
+	admin := ports.AuthContext{UserID: "admin", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Planner", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Diff Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 50)); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	report := domain.ReportRequest{Scope: domain.ScopePerson, IDs: []string{person.ID}, FromDate: "2026-01-05", ToDate: "2026-01-18", Granularity: domain.GranularityWeek}
+	added := testPersonAllocationInputForRange(person.ID, project.ID, 25, "2026-01-12", "2026-01-18")
+	diff, err := svc.ReportDiff(ctx, user, domain.ReportDiffRequest{
+		Baseline:   report,
+		Comparison: domain.WhatIfReportRequest{ReportRequest: report, ProposedAllocations: []domain.Allocation{added}},
+	})
+	if err != nil || len(diff) != 2 {
+		t.Fatalf("report diff: buckets=%+v err=%v", diff, err)
+	}
+	// The added 25% allocation covers only the second week: 7 days of 8 hours.
+	expectedDeltas := map[string]float64{"2026-01-05": 0, "2026-01-12": 14}
+	for _, bucket := range diff {
+		expected, ok := expectedDeltas[bucket.PeriodStart]
+		if !ok || bucket.LoadHoursDelta != expected || bucket.ComparisonLoadHours-bucket.BaselineLoadHours != expected {
+			t.Fatalf("unexpected diff bucket %+v", bucket)
+		}
+		if bucket.BaselineLoadHours != 28 || bucket.AvailabilityHoursDelta != 0 {
+			t.Fatalf("expected unchanged baseline load and availability, got %+v", bucket)
+		}
+	}
+
+	shifted := report
+	shifted.FromDate = "2026-01-12"
+	shifted.ToDate = "2026-01-25"
+	diff, err = svc.ReportDiff(ctx, user, domain.ReportDiffRequest{Baseline: report, Comparison: domain.WhatIfReportRequest{ReportRequest: shifted}})
+	if err != nil || len(diff) != 3 {
+		t.Fatalf("expected union of three weeks, got buckets=%+v err=%v", diff, err)
+	}
+	if diff[0].PeriodStart != "2026-01-05" || diff[0].LoadHoursDelta != -28 || diff[2].PeriodStart != "2026-01-19" || diff[2].LoadHoursDelta != 28 {
+		t.Fatalf("expected unmatched periods to diff against zero, got %+v", diff)
+	}
+
+	if _, err = svc.ReportDiff(ctx, user, domain.ReportDiffRequest{Baseline: report}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected missing comparison scope to fail validation, got %v", err)
+	}
+}
+
+// TestServiceNestedGroups verifies the service nested groups scenario.
+func TestServiceNestedGroups(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Nested Groups")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Nested Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	personIDs := make([]string, 0, 3)
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		person, createErr := svc.CreatePerson(ctx, admin, domain.Person{Name: name, EmploymentPct: 100})
+		if createErr != nil {
+			t.Fatalf(errSetupPersonFmt, createErr)
+		}
+		personIDs = append(personIDs, person.ID)
+	}
+
+	// division > department > {team one, team two}, with Carol directly in the department.
+	teamOne, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Team One", MemberIDs: []string{personIDs[0]}})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+	teamTwo, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Team Two", MemberIDs: []string{personIDs[1]}})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+	department, err := svc.CreateGroup(ctx, admin, domain.Group{
+		Name:        "Department",
+		MemberIDs:   []string{personIDs[2]},
+		SubGroupIDs: []string{teamOne.ID, teamTwo.ID},
+	})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+	division, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Division", SubGroupIDs: []string{department.ID}})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+
+	targets, err := svc.resolveAllocationTargetPersons(ctx, organisation.ID, domain.AllocationTargetGroup, division.ID)
+	if err != nil {
+		t.Fatalf("resolve nested group: %v", err)
+	}
+	sort.Strings(targets)
+	expected := append([]string{}, personIDs...)
+	sort.Strings(expected)
+	if !reflect.DeepEqual(targets, expected) {
+		t.Fatalf("expected division to flatten to %v, got %v", expected, targets)
+	}
+
+	if _, err = svc.CreateAllocation(ctx, admin, testGroupAllocationInput(division.ID, project.ID, 50)); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+	buckets, err := svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+		Scope:       domain.ScopeGroup,
+		IDs:         []string{division.ID},
+		FromDate:    testDate20260101,
+		ToDate:      testDate20260101,
+		Granularity: domain.GranularityDay,
+	})
+	if err != nil {
+		t.Fatalf("report nested group: %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].AvailabilityHours != 24 || buckets[0].LoadHours != 12 {
+		t.Fatalf("expected the division allocation to load all three transitive members, got %+v", buckets)
+	}
+
+	_, err = svc.UpdateGroup(ctx, admin, teamOne.ID, domain.Group{Name: "Team One", MemberIDs: []string{personIDs[0]}, SubGroupIDs: []string{division.ID}})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected cyclic nesting to fail validation, got %v", err)
+	}
+	_, err = svc.UpdateGroup(ctx, admin, teamOne.ID, domain.Group{Name: "Team One", SubGroupIDs: []string{teamOne.ID}})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected self nesting to fail validation, got %v", err)
+	}
+	_, err = svc.CreateGroup(ctx, admin, domain.Group{Name: "Orphan", SubGroupIDs: []string{testMissingID}})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected missing subgroup to fail validation, got %v", err)
+	}
+
+	if err = svc.DeleteGroup(ctx, admin, teamTwo.ID, false); err != nil {
+		t.Fatalf("delete subgroup: %v", err)
+	}
+	department, err = svc.GetGroup(ctx, admin, department.ID)
+	if err != nil || !reflect.DeepEqual(department.SubGroupIDs, []string{teamOne.ID}) {
+		t.Fatalf("expected deleted subgroup to be dropped from its parent, got %+v err=%v", department.SubGroupIDs, err)
+	}
+}
+
+// TestServiceRejectOverEmployment verifies the service reject over employment scenario.
+func TestServiceRejectOverEmployment(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Over Employment")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Employment Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Part Timer", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	_, err = svc.UpdatePerson(ctx, admin, person.ID, domain.Person{Name: "Part Timer", EmploymentPct: 50, EmploymentEffectiveFromMonth: "2026-07"})
+	if err != nil {
+		t.Fatalf("reduce employment from July: %v", err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 60, testDate20260101, "2026-06-30")); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	overlapping := testPersonAllocationInputForRange(person.ID, project.ID, 50, "2026-03-15", "2026-03-20")
+	if err = svc.validateAllocationLimit(ctx, organisation.ID, overlapping, []string{person.ID}, ""); err != nil {
+		t.Fatalf("expected over-employment to pass while the toggle is off, got %v", err)
+	}
+
+	organisation.RejectOverEmployment = true
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("enable over-employment rejection: %v", err)
+	}
+
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 40, "2026-03-01", "2026-03-31")); err != nil {
+		t.Fatalf("expected exactly 100%% to pass, got %v", err)
+	}
+	_, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 10, "2026-03-10", "2026-04-10"))
+	if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "2026-03-10") || !strings.Contains(err.Error(), "10% over") {
+		t.Fatalf("expected rejection naming 2026-03-10 and a 10%% overage, got %v", err)
+	}
+
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 50, "2026-07-01", "2026-07-31")); err != nil {
+		t.Fatalf("expected load equal to reduced employment to pass, got %v", err)
+	}
+	_, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 20, "2026-06-20", "2026-07-05"))
+	if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "2026-07-01") || !strings.Contains(err.Error(), "50% employment") {
+		t.Fatalf("expected rejection when employment drops on 2026-07-01, got %v", err)
+	}
+}
+
+// TestServiceOpenEndedProject verifies the service open ended project scenario.
+func TestServiceOpenEndedProject(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Open Project")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	input := testProjectInput("Ongoing Platform")
+	input.EndDate = ""
+	project, err := svc.CreateProject(ctx, admin, input)
+	if err != nil {
+		t.Fatalf("expected open-ended project to be accepted, got %v", err)
+	}
+	if project.EndDate != "" {
+		t.Fatalf("expected end date to stay open, got %q", project.EndDate)
+	}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Platform Engineer", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 20, "2026-02-01", "2035-12-31")); err != nil {
+		t.Fatalf("expected long allocation within the open project to validate, got %v", err)
+	}
+	_, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 20, "2025-12-01", "2026-03-31"))
+	var rangeErr domain.ProjectRangeError
+	if !errors.As(err, &rangeErr) || !strings.Contains(err.Error(), "open-ended project range starting 2026-01-01") {
+		t.Fatalf("expected allocation before the project start to be rejected, got %v", err)
+	}
+
+	input.StartDate = ""
+	if _, err = svc.CreateProject(ctx, admin, input); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected project without start date to be rejected, got %v", err)
+	}
+}
+
+// TestServiceImportAllocationsByName verifies the service import allocations by name scenario.
+func TestServiceImportAllocationsByName(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Import")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	if _, err := svc.CreateProject(ctx, admin, testProjectInput("Apollo")); err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	ada, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Ada Lovelace", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	for range 2 {
+		if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "Sam Smith", EmploymentPct: 100}); err != nil {
+			t.Fatalf(errSetupPersonFmt, err)
+		}
+	}
+
+	raw := []byte("target_name,project_name,start_date,end_date,percent\n" +
+		"ada lovelace,Apollo,2026-01-01,2026-03-31,40\n" +
+		"Grace Hopper,Apollo,2026-01-01,2026-03-31,40\n" +
+		"Sam Smith,Apollo,2026-01-01,2026-03-31,40\n")
+	if _, err = svc.ImportAllocationsByName(ctx, user, raw); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user import to be forbidden, got %v", err)
+	}
+
+	result, err := svc.ImportAllocationsByName(ctx, admin, raw)
+	if err != nil {
+		t.Fatalf("import allocations: %v", err)
+	}
+	if result.Created != 1 || result.Failed != 2 || len(result.Rows) != 3 {
+		t.Fatalf("expected one created and two failed rows, got %+v", result)
+	}
+	created, err := svc.GetAllocation(ctx, admin, result.Rows[0].AllocationID)
+	if err != nil || created.TargetID != ada.ID || created.Percent != 40 {
+		t.Fatalf("expected resolved allocation for %s, got %+v err=%v", ada.ID, created, err)
+	}
+	if result.Rows[1].Line != 3 || !strings.Contains(result.Rows[1].Error, `person "Grace Hopper" not found`) {
+		t.Fatalf("expected missing name on line 3, got %+v", result.Rows[1])
+	}
+	if result.Rows[2].Line != 4 || !strings.Contains(result.Rows[2].Error, `person name "Sam Smith" is ambiguous, 2 match`) {
+		t.Fatalf("expected ambiguous name on line 4, got %+v", result.Rows[2])
+	}
+
+	if _, err = svc.ImportAllocationsByName(ctx, admin, []byte("name,project_name\n")); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected unknown column to be rejected, got %v", err)
+	}
+}
+
+// TestServiceRejectsStaleUpdates verifies the service rejects stale updates scenario.
+func TestServiceRejectsStaleUpdates(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Versions")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Versioned", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	if person.Version != 1 {
+		t.Fatalf("expected a new person to start at version 1, got %d", person.Version)
+	}
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Versioned Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+
+	first := person
+	first.Name = "First Edit"
+	updated, err := svc.UpdatePerson(ctx, admin, person.ID, first)
+	if err != nil {
+		t.Fatalf("update person: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Fatalf("expected the update to move to version 2, got %d", updated.Version)
+	}
+	second := person
+	second.Name = "Second Edit"
+	if _, err = svc.UpdatePerson(ctx, admin, person.ID, second); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a stale person update to conflict, got %v", err)
+	}
+	stored, err := svc.GetPerson(ctx, admin, person.ID)
+	if err != nil || stored.Name != "First Edit" {
+		t.Fatalf("expected the first edit to survive, got %+v err=%v", stored, err)
+	}
+	second.Version = 0
+	if _, err = svc.UpdatePerson(ctx, admin, person.ID, second); err != nil {
+		t.Fatalf("expected an update without a version to skip the check, got %v", err)
+	}
+
+	staleOrganisation := organisation
+	staleOrganisation.Version = organisation.Version + 1
+	if _, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, staleOrganisation); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a stale organisation update to conflict, got %v", err)
+	}
+	staleProject := project
+	staleProject.Version = project.Version + 1
+	if _, err = svc.UpdateProject(ctx, admin, project.ID, staleProject); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a stale project update to conflict, got %v", err)
+	}
+	group, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Versioned Group", MemberIDs: []string{person.ID}})
+	if err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+	group.Version++
+	if _, err = svc.UpdateGroup(ctx, admin, group.ID, group); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a stale group update to conflict, got %v", err)
+	}
+	allocation, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 40))
+	if err != nil {
+		t.Fatalf("setup allocation: %v", err)
+	}
+	allocation.Version++
+	if _, err = svc.UpdateAllocation(ctx, admin, allocation.ID, allocation); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a stale allocation update to conflict, got %v", err)
+	}
+}
+
+// TestServiceTenantExportImport verifies the service tenant export import scenario.
+func TestServiceTenantExportImport(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Export")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Exported", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Exported Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	group, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Exported Team", MemberIDs: []string{person.ID}})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 50)); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+	if _, err = svc.CreateOrgHoliday(ctx, admin, domain.OrgHoliday{Date: "2026-12-25", Hours: 8}); err != nil {
+		t.Fatalf("setup holiday: %v", err)
+	}
+	if _, err = svc.CreateGroupUnavailability(ctx, admin, domain.GroupUnavailability{GroupID: group.ID, Date: "2026-05-04", Hours: 4}); err != nil {
+		t.Fatalf("setup group unavailability: %v", err)
+	}
+
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+	if _, err = svc.ExportTenant(ctx, user, domain.SnapshotFormatJSON); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected forbidden export for org user, got %v", err)
+	}
+	if _, err = svc.ExportTenant(ctx, admin, "xml"); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error for unknown format, got %v", err)
+	}
+
+	for _, format := range []string{domain.SnapshotFormatJSON, domain.SnapshotFormatCSV} {
+		exported, exportErr := svc.ExportTenant(ctx, admin, format)
+		if exportErr != nil {
+			t.Fatalf("export %s: %v", format, exportErr)
+		}
+
+		dryRun, importErr := svc.ImportTenant(ctx, globalAdmin, exported, format, true)
+		if importErr != nil {
+			t.Fatalf("dry run %s: %v", format, importErr)
+		}
+		if !dryRun.Valid || dryRun.Imported || len(dryRun.Issues) != 0 {
+			t.Fatalf("expected a clean dry run for %s, got %+v", format, dryRun)
+		}
+		if dryRun.Counts["persons"] != 1 || dryRun.Counts["allocations"] != 1 || dryRun.Counts["group_unavailability"] != 1 {
+			t.Fatalf("unexpected dry run counts for %s: %+v", format, dryRun.Counts)
+		}
+
+		result, importErr := svc.ImportTenant(ctx, globalAdmin, exported, format, false)
+		if importErr != nil {
+			t.Fatalf("import %s: %v", format, importErr)
+		}
+		if !result.Imported || result.OrganisationID == "" || result.OrganisationID == organisation.ID {
+			t.Fatalf("expected %s import into a new organisation, got %+v", format, result)
+		}
+
+		importedAdmin := ports.AuthContext{UserID: "admin2", OrganisationID: result.OrganisationID, Roles: []string{domain.RoleOrgAdmin}}
+		groups, listErr := svc.ListGroups(ctx, importedAdmin)
+		if listErr != nil {
+			t.Fatalf("list imported groups: %v", listErr)
+		}
+		persons, listErr := svc.ListPersons(ctx, importedAdmin)
+		if listErr != nil {
+			t.Fatalf("list imported persons: %v", listErr)
+		}
+		if len(groups) != 1 || len(persons) != 1 || len(groups[0].MemberIDs) != 1 || groups[0].MemberIDs[0] != persons[0].ID {
+			t.Fatalf("expected imported group members to point at imported persons, got %+v and %+v", groups, persons)
+		}
+		if persons[0].ID == person.ID {
+			t.Fatalf("expected imported person to get a new ID")
+		}
+	}
+}
+
+// TestServiceTenantImportReportsIssues verifies the service tenant import reports issues scenario.
+func TestServiceTenantImportReportsIssues(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	admin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	raw := []byte(`{
+		"format_version": 1,
+		"organisation": {"name": "Broken", "hours_per_day": 8, "hours_per_week": 40, "hours_per_year": 2080},
+		"persons": [{"id": "p1", "name": "Ada", "employment_pct": 100}, {"id": "p1", "name": "Bob", "employment_pct": 100}],
+		"groups": [{"id": "g1", "name": "Loop", "member_ids": [], "sub_group_ids": ["g2"]}, {"id": "g2", "name": "Back", "member_ids": [], "sub_group_ids": ["g1"]}],
+		"allocations": [{"target_type": "person", "target_id": "p9", "project_id": "missing", "start_date": "2026-01-01", "end_date": "2026-02-01", "percent": 50}]
+	}`)
+
+	for _, dryRun := range []bool{true, false} {
+		result, err := svc.ImportTenant(ctx, admin, raw, domain.SnapshotFormatJSON, dryRun)
+		if err != nil {
+			t.Fatalf("import with dry run %t: %v", dryRun, err)
+		}
+		if result.Valid || result.Imported {
+			t.Fatalf("expected an invalid snapshot, got %+v", result)
+		}
+		records := map[string]bool{}
+		for _, issue := range result.Issues {
+			records[issue.Record] = true
+		}
+		for _, record := range []string{"persons[1]", "groups[0]", "groups[1]", "allocations[0]"} {
+			if !records[record] {
+				t.Fatalf("expected an issue for %s, got %+v", record, result.Issues)
+			}
+		}
+	}
+
+	organisations, err := svc.ListOrganisations(ctx, admin)
+	if err != nil {
+		t.Fatalf("list organisations: %v", err)
+	}
+	if len(organisations) != 0 {
+		t.Fatalf("expected an invalid import to store nothing, got %+v", organisations)
+	}
+	if _, err = svc.ImportTenant(ctx, admin, []byte("{"), domain.SnapshotFormatJSON, false); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error for malformed snapshot, got %v", err)
+	}
+}
+
+// TestServiceReadOnlyOrganisation verifies the service read-only organisation scenario.
+func TestServiceReadOnlyOrganisation(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Read Only")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Frozen", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+
+	frozen := organisation
+	frozen.ReadOnly = true
+	if _, err = svc.UpdateOrganisation(ctx, user, organisation.ID, frozen); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user toggle to be forbidden, got %v", err)
+	}
+	if frozen, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, frozen); err != nil {
+		t.Fatalf("set read-only: %v", err)
+	}
+
+	if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "Blocked", EmploymentPct: 100}); !errors.Is(err, domain.ErrReadOnly) {
+		t.Fatalf("expected create to be rejected as read-only, got %v", err)
+	}
+	if err = svc.DeletePerson(ctx, admin, person.ID, false); !errors.Is(err, domain.ErrReadOnly) {
+		t.Fatalf("expected delete to be rejected as read-only, got %v", err)
+	}
+	frozen.Name = "Renamed While Frozen"
+	if _, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, frozen); !errors.Is(err, domain.ErrReadOnly) {
+		t.Fatalf("expected update keeping read-only to be rejected, got %v", err)
+	}
+	persons, err := svc.ListPersons(ctx, user)
+	if err != nil || len(persons) != 1 {
+		t.Fatalf("expected list to succeed while read-only, got %+v err=%v", persons, err)
+	}
+	report := domain.ReportRequest{Scope: domain.ScopePerson, IDs: []string{person.ID}, FromDate: testDate20260101, ToDate: testDate20260101, Granularity: domain.GranularityDay}
+	if _, err = svc.ReportAvailabilityAndLoad(ctx, user, report); err != nil {
+		t.Fatalf("expected report to succeed while read-only, got %v", err)
+	}
+
+	organisation.Version = frozen.Version
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("clear read-only: %v", err)
+	}
+	if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "Restored", EmploymentPct: 100}); err != nil {
+		t.Fatalf("expected create to succeed after clearing read-only, got %v", err)
+	}
+}
+
+// TestServiceOrganisationWriteWindow verifies the service organisation write window scenario.
+func TestServiceOrganisationWriteWindow(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	// Saturday 2026-03-07, 10:00 in Zurich.
+	clock := time.Date(2026, time.March, 7, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return clock }
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	invalid := domain.Organisation{Name: "Org Invalid Window", HoursPerDay: 8, HoursPerWeek: 40, HoursPerYear: 2080, WriteWindow: &domain.WriteWindow{Weekdays: []string{"funday"}, Start: "09:00", End: "17:00"}}
+	if _, err := svc.CreateOrganisation(ctx, globalAdmin, invalid); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected an unknown weekday to fail validation, got %v", err)
+	}
+	invalid.WriteWindow = &domain.WriteWindow{Start: "17:00", End: "09:00"}
+	if _, err := svc.CreateOrganisation(ctx, globalAdmin, invalid); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a window ending before it starts to fail validation, got %v", err)
+	}
+
+	organisation, err := svc.CreateOrganisation(ctx, globalAdmin, domain.Organisation{
+		Name:         "Org Business Hours",
+		HoursPerDay:  8,
+		HoursPerWeek: 40,
+		HoursPerYear: 2080,
+		Timezone:     "Europe/Zurich",
+		WriteWindow:  &domain.WriteWindow{Weekdays: []string{" Monday", "tuesday", "wednesday", "thursday", "friday"}, Start: "09:00", End: "17:00"},
+	})
+	if err != nil {
+		t.Fatalf("create organisation: %v", err)
+	}
+	if organisation.WriteWindow == nil || organisation.WriteWindow.Weekdays[0] != "monday" {
+		t.Fatalf("expected the write window to be stored normalized, got %+v", organisation.WriteWindow)
+	}
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "Weekend", EmploymentPct: 100}); !errors.Is(err, domain.ErrOutsideWriteWindow) {
+		t.Fatalf("expected a weekend create to be rejected, got %v", err)
+	}
+	if _, err = svc.ListPersons(ctx, admin); err != nil {
+		t.Fatalf("expected reads outside the window to pass, got %v", err)
+	}
+
+	// Monday 2026-03-09, 08:30 in Zurich.
+	clock = time.Date(2026, time.March, 9, 7, 30, 0, 0, time.UTC)
+	if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "Early", EmploymentPct: 100}); !errors.Is(err, domain.ErrOutsideWriteWindow) {
+		t.Fatalf("expected a create before opening time to be rejected, got %v", err)
+	}
+
+	// Monday 2026-03-09, 09:00 in Zurich.
+	clock = time.Date(2026, time.March, 9, 8, 0, 0, 0, time.UTC)
+	if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "On Time", EmploymentPct: 100}); err != nil {
+		t.Fatalf("expected a create inside the window to pass, got %v", err)
+	}
+
+	// Monday 2026-03-09, 17:00 in Zurich.
+	clock = time.Date(2026, time.March, 9, 16, 0, 0, 0, time.UTC)
+	if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "Late", EmploymentPct: 100}); !errors.Is(err, domain.ErrOutsideWriteWindow) {
+		t.Fatalf("expected a create at closing time to be rejected, got %v", err)
+	}
+
+	organisation.WriteWindow = nil
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("expected organisation settings to stay editable outside the window, got %v", err)
+	}
+	if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "Unrestricted", EmploymentPct: 100}); err != nil {
+		t.Fatalf("expected a create after removing the window to pass, got %v", err)
+	}
+}
+
+// TestServiceAllocationPercentStep verifies the service allocation percent step scenario.
+func TestServiceAllocationPercentStep(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Percent Step")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Stepper", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Step Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+
+	invalid := organisation
+	invalid.AllocationPercentStep = 30
+	if _, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, invalid); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a step that does not divide 100 to fail validation, got %v", err)
+	}
+
+	organisation.AllocationPercentStep = 5
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("set reject step: %v", err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 42)); !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), "not a multiple of the allocation step 5") {
+		t.Fatalf("expected off-step percent to be rejected, got %v", err)
+	}
+	onStep, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 45))
+	if err != nil || onStep.Percent != 45 {
+		t.Fatalf("expected on-step percent to be kept, got %+v err=%v", onStep, err)
+	}
+
+	organisation.AllocationPercentStepMode = domain.PercentStepSnap
+	if organisation, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("set snap step: %v", err)
+	}
+	snapped, err := svc.UpdateAllocation(ctx, admin, onStep.ID, testPersonAllocationInput(person.ID, project.ID, 43))
+	if err != nil || snapped.Percent != 45 {
+		t.Fatalf("expected 43 to snap to 45 on update, got %+v err=%v", snapped, err)
+	}
+	if err = svc.DeleteAllocation(ctx, admin, onStep.ID); err != nil {
+		t.Fatalf("delete allocation: %v", err)
+	}
+	created, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 42))
+	if err != nil || created.Percent != 40 {
+		t.Fatalf("expected 42 to snap to 40 on create, got %+v err=%v", created, err)
+	}
+}
+
+// TestServiceAllocationRejectsCrossTenantReferences verifies the service allocation rejects cross tenant references scenario.
+func TestServiceAllocationRejectsCrossTenantReferences(t *testing.T) {
+	ctx := context.Background()
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "service-data.json"))
+	if err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	leaky := newTenantLeakingRepository(repo)
+	svc, err := New(leaky, telemetry.NewNoopTelemetry(), impexp.NewCSVImportExport())
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	orgA := createOrganisationForService(ctx, t, svc, globalAdmin, "Tenant A")
+	orgB := createOrganisationForService(ctx, t, svc, globalAdmin, "Tenant B")
+	adminA := ports.AuthContext{UserID: "admin-a", OrganisationID: orgA.ID, Roles: []string{domain.RoleOrgAdmin}}
+	adminB := ports.AuthContext{UserID: "admin-b", OrganisationID: orgB.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	personA, err := svc.CreatePerson(ctx, adminA, domain.Person{Name: "Person A", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	projectA, err := svc.CreateProject(ctx, adminA, testProjectInput("Project A"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	personB, err := svc.CreatePerson(ctx, adminB, domain.Person{Name: "Person B", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	projectB, err := svc.CreateProject(ctx, adminB, testProjectInput("Project B"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+
+	if _, err = svc.CreateAllocation(ctx, adminA, testPersonAllocationInput(personA.ID, projectB.ID, 20)); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a foreign project to be rejected on create even when the repository leaks it, got %v", err)
+	}
+	leaky.leak = false
+	if _, err = svc.CreateAllocation(ctx, adminA, testPersonAllocationInput(personA.ID, projectB.ID, 20)); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a foreign project to be rejected on create, got %v", err)
+	}
+	if _, err = svc.CreateAllocation(ctx, adminA, testPersonAllocationInput(personB.ID, projectA.ID, 20)); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a foreign person to be rejected on create, got %v", err)
+	}
+
+	allocation, err := svc.CreateAllocation(ctx, adminA, testPersonAllocationInput(personA.ID, projectA.ID, 20))
+	if err != nil {
+		t.Fatalf("create same-tenant allocation: %v", err)
+	}
+	leaky.leak = true
+	if _, err = svc.UpdateAllocation(ctx, adminA, allocation.ID, testPersonAllocationInput(personA.ID, projectB.ID, 20)); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a foreign project to be rejected on update, got %v", err)
+	}
+	stored, err := svc.GetAllocation(ctx, adminA, allocation.ID)
+	if err != nil {
+		t.Fatalf("get allocation: %v", err)
+	}
+	if stored.ProjectID != projectA.ID {
+		t.Fatalf("expected the allocation to keep its project, got %s", stored.ProjectID)
+	}
+}
+
+// tenantLeakingRepository simulates a repository that returns projects of
+// any organisation while leak is set.
+type tenantLeakingRepository struct {
+	ports.Repository
+	leak bool
+}
+
+func newTenantLeakingRepository(repo ports.Repository) *tenantLeakingRepository {
+	return &tenantLeakingRepository{Repository: repo, leak: true}
+}
+
+func (r *tenantLeakingRepository) GetProject(ctx context.Context, organisationID, id string) (domain.Project, error) {
+	if !r.leak {
+		return r.Repository.GetProject(ctx, organisationID, id)
+	}
+	organisations, err := r.ListOrganisations(ctx)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	for _, organisation := range organisations {
+		if project, getErr := r.Repository.GetProject(ctx, organisation.ID, id); getErr == nil {
+			return project, nil
+		}
+	}
+	return domain.Project{}, domain.ErrNotFound
+}
+
+// TestServiceGroupReportBlendsMemberEmployment verifies the service group report blends member employment scenario.
+func TestServiceGroupReportBlendsMemberEmployment(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Blended")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	fullTime, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Full Time", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	partTime, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Part Time", EmploymentPct: 50})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	if _, err = svc.UpdatePerson(ctx, admin, partTime.ID, domain.Person{Name: partTime.Name, EmploymentPct: 75, EmploymentEffectiveFromMonth: "2026-03"}); err != nil {
+		t.Fatalf("schedule employment change: %v", err)
+	}
+	group, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Blended", MemberIDs: []string{fullTime.ID, partTime.ID}})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+
+	availability := func(scope string, ids []string, granularity string) map[string]float64 {
+		t.Helper()
+		buckets, reportErr := svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+			Scope:       scope,
+			IDs:         ids,
+			FromDate:    "2026-02-23",
+			ToDate:      "2026-03-08",
+			Granularity: granularity,
+		})
+		if reportErr != nil {
+			t.Fatalf("report %s %v: %v", scope, ids, reportErr)
+		}
+		result := map[string]float64{}
+		for _, bucket := range buckets {
+			result[bucket.PeriodStart] = bucket.AvailabilityHours
+		}
+		return result
+	}
+
+	expected := []struct {
+		granularity string
+		periodStart string
+		hours       float64
+	}{
+		{granularity: domain.GranularityDay, periodStart: "2026-02-28", hours: 12},
+		{granularity: domain.GranularityDay, periodStart: "2026-03-01", hours: 14},
+		// The week of 2026-02-23 holds six days at 50% and one at 75%.
+		{granularity: domain.GranularityWeek, periodStart: "2026-02-23", hours: 86},
+		{granularity: domain.GranularityWeek, periodStart: "2026-03-02", hours: 98},
+	}
+	for _, tc := range expected {
+		groupHours := availability(domain.ScopeGroup, []string{group.ID}, tc.granularity)
+		if got := groupHours[tc.periodStart]; got != tc.hours {
+			t.Fatalf("expected %v group hours for %s %s, got %v", tc.hours, tc.granularity, tc.periodStart, got)
+		}
+	}
+
+	for _, granularity := range []string{domain.GranularityDay, domain.GranularityWeek, domain.GranularityMonth} {
+		groupHours := availability(domain.ScopeGroup, []string{group.ID}, granularity)
+		fullTimeHours := availability(domain.ScopePerson, []string{fullTime.ID}, granularity)
+		partTimeHours := availability(domain.ScopePerson, []string{partTime.ID}, granularity)
+		for periodStart, hours := range groupHours {
+			if sum := fullTimeHours[periodStart] + partTimeHours[periodStart]; math.Abs(sum-hours) > 1e-9 {
+				t.Fatalf("expected %s group hours for %s to equal the member sum %v, got %v", granularity, periodStart, sum, hours)
+			}
+		}
+	}
+}
+
+// TestServiceReportFTEUnit verifies the service report FTE unit scenario.
+func TestServiceReportFTEUnit(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org FTE")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	fullTime, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Full Time", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	partTime, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Part Time", EmploymentPct: 50})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("FTE Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(fullTime.ID, project.ID, 100)); err != nil {
+		t.Fatalf("create full-time allocation: %v", err)
+	}
+
+	report := func(ids []string, granularity, unit string) []domain.ReportBucket {
+		t.Helper()
+		buckets, reportErr := svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+			Scope:       domain.ScopePerson,
+			IDs:         ids,
+			FromDate:    "2026-03-04",
+			ToDate:      "2026-03-17",
+			Granularity: granularity,
+			Unit:        unit,
+		})
+		if reportErr != nil {
+			t.Fatalf("report %s %s: %v", granularity, unit, reportErr)
+		}
+		return buckets
+	}
+
+	dayBuckets := report([]string{fullTime.ID}, domain.GranularityDay, domain.ReportUnitFTE)
+	if got := dayBuckets[0]; got.LoadHours != 1 || got.AvailabilityHours != 1 || got.FreeHours != 0 {
+		t.Fatalf("expected a fully allocated full-time day to be 1 FTE, got %+v", got)
+	}
+	if got := report([]string{fullTime.ID}, domain.GranularityDay, "")[0].LoadHours; got != 8 {
+		t.Fatalf("expected hours by default, got %v", got)
+	}
+
+	// The range starts mid-week and ends mid-month, so partial buckets must
+	// still scale by the days they cover.
+	for _, granularity := range []string{domain.GranularityWeek, domain.GranularityMonth, domain.GranularityYear} {
+		for _, bucket := range report([]string{fullTime.ID, partTime.ID}, granularity, domain.ReportUnitFTE) {
+			if bucket.LoadHours != 1 || bucket.AvailabilityHours != 1.5 {
+				t.Fatalf("expected 1 FTE load and 1.5 FTE availability for %s %s, got %+v", granularity, bucket.PeriodStart, bucket)
+			}
+		}
+	}
+
+	_, err = svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+		Scope:       domain.ScopePerson,
+		FromDate:    "2026-03-04",
+		ToDate:      "2026-03-04",
+		Granularity: domain.GranularityDay,
+		Unit:        "days",
+	})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected an unknown unit to fail validation, got %v", err)
+	}
+}
+
+// TestServiceReportRejectsIDsIncompatibleWithScope verifies the service report rejects IDs incompatible with scope scenario.
+func TestServiceReportRejectsIDsIncompatibleWithScope(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Scope IDs")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Scoped", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+
+	request := func(scope string, ids []string) domain.ReportRequest {
+		return domain.ReportRequest{Scope: scope, IDs: ids, FromDate: "2026-03-02", ToDate: "2026-03-02", Granularity: domain.GranularityDay}
+	}
+	rejected := []struct {
+		name    string
+		request domain.ReportRequest
+		message string
+	}{
+		{name: "organisation with ids", request: request(domain.ScopeOrganisation, []string{person.ID}), message: "ids must be empty for organisation scope"},
+		{name: "person without ids", request: request(domain.ScopePerson, nil), message: "ids must contain at least one person"},
+		{name: "group without ids", request: request(domain.ScopeGroup, []string{}), message: "ids must contain at least one group"},
+		{name: "project without ids", request: request(domain.ScopeProject, nil), message: "ids must contain at least one project"},
+	}
+	for _, tc := range rejected {
+		_, err := svc.ReportAvailabilityAndLoad(ctx, admin, tc.request)
+		if !errors.Is(err, domain.ErrValidation) || !strings.Contains(err.Error(), tc.message) {
+			t.Fatalf("%s: expected validation error mentioning %q, got %v", tc.name, tc.message, err)
+		}
+	}
+
+	if _, err = svc.ReportAvailabilityAndLoad(ctx, admin, request(domain.ScopeOrganisation, nil)); err != nil {
+		t.Fatalf("expected organisation scope without ids to succeed, got %v", err)
+	}
+	if _, err = svc.ReportAvailabilityAndLoad(ctx, admin, request(domain.ScopePerson, []string{person.ID})); err != nil {
+		t.Fatalf("expected person scope with ids to succeed, got %v", err)
+	}
+}
+
+// TestServiceCreateAllocationDefaultsDatesFromProject verifies the service create allocation defaults dates from project scenario.
+func TestServiceCreateAllocationDefaultsDatesFromProject(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Default Window")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Windowed", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	projectInput := testProjectInput("Windowed Project")
+	projectInput.StartDate = "2026-03-01"
+	projectInput.EndDate = "2026-06-30"
+	project, err := svc.CreateProject(ctx, admin, projectInput)
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+
+	undated := domain.Allocation{TargetType: domain.AllocationTargetPerson, TargetID: person.ID, ProjectID: project.ID, Percent: 20}
+	inherited, err := svc.CreateAllocation(ctx, admin, undated)
+	if err != nil {
+		t.Fatalf("create undated allocation: %v", err)
+	}
+	if inherited.StartDate != "2026-03-01" || inherited.EndDate != "2026-06-30" {
+		t.Fatalf("expected the project window, got %s to %s", inherited.StartDate, inherited.EndDate)
+	}
+
+	narrower := undated
+	narrower.StartDate = "2026-04-01"
+	narrower.EndDate = "2026-04-30"
+	explicit, err := svc.CreateAllocation(ctx, admin, narrower)
+	if err != nil {
+		t.Fatalf("create dated allocation: %v", err)
+	}
+	if explicit.StartDate != "2026-04-01" || explicit.EndDate != "2026-04-30" {
+		t.Fatalf("expected explicit dates to win, got %s to %s", explicit.StartDate, explicit.EndDate)
+	}
+
+	endOnly := undated
+	endOnly.EndDate = "2026-05-15"
+	mixed, err := svc.CreateAllocation(ctx, admin, endOnly)
+	if err != nil {
+		t.Fatalf("create end-only allocation: %v", err)
+	}
+	if mixed.StartDate != "2026-03-01" || mixed.EndDate != "2026-05-15" {
+		t.Fatalf("expected only the missing start to be inherited, got %s to %s", mixed.StartDate, mixed.EndDate)
+	}
+
+	outside := undated
+	outside.EndDate = "2026-07-31"
+	if _, err = svc.CreateAllocation(ctx, admin, outside); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected an explicit end past the project to fail validation, got %v", err)
+	}
+
+	openInput := testProjectInput("Open Project")
+	openInput.EndDate = ""
+	openProject, err := svc.CreateProject(ctx, admin, openInput)
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	openEnded := undated
+	openEnded.ProjectID = openProject.ID
+	if _, err = svc.CreateAllocation(ctx, admin, openEnded); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected an open-ended project to still require an end date, got %v", err)
+	}
+}
+
+// TestServiceWorkScheduleReducesAvailability verifies the service work schedule reduces availability scenario.
+func TestServiceWorkScheduleReducesAvailability(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	fourDayWeek := domain.WorkSchedule{Name: "Four-day week", WorkingDays: []bool{true, true, true, true, false, false, false}}
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation, err := svc.CreateOrganisation(ctx, globalAdmin, domain.Organisation{
+		Name:          "Org Schedules",
+		HoursPerDay:   8,
+		HoursPerWeek:  40,
+		HoursPerYear:  2080,
+		WorkSchedules: []domain.WorkSchedule{fourDayWeek},
+	})
+	if err != nil {
+		t.Fatalf("create organisation: %v", err)
+	}
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	standard, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Standard", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	reduced, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Reduced", EmploymentPct: 100, WorkSchedule: "four-day WEEK"})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	if reduced.WorkSchedule != fourDayWeek.Name {
+		t.Fatalf("expected the stored schedule name, got %q", reduced.WorkSchedule)
+	}
+
+	weekAvailability := func(personID string) float64 {
+		t.Helper()
+		buckets, reportErr := svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+			Scope:       domain.ScopePerson,
+			IDs:         []string{personID},
+			FromDate:    "2026-03-02",
+			ToDate:      "2026-03-08",
+			Granularity: domain.GranularityWeek,
+		})
+		if reportErr != nil {
+			t.Fatalf("report %s: %v", personID, reportErr)
+		}
+		return buckets[0].AvailabilityHours
+	}
+	if got := weekAvailability(standard.ID); got != 56 {
+		t.Fatalf("expected 56 hours for the standard week, got %v", got)
+	}
+	if got := weekAvailability(reduced.ID); got != 32 {
+		t.Fatalf("expected 32 hours for the four-day week, got %v", got)
+	}
+
+	if _, err = svc.CreatePersonUnavailability(ctx, admin, domain.PersonUnavailability{PersonID: reduced.ID, Date: "2026-03-06", Hours: 1}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected unavailability on a non-working day to fail validation, got %v", err)
+	}
+	if _, err = svc.CreatePerson(ctx, admin, domain.Person{Name: "Unknown", EmploymentPct: 100, WorkSchedule: "Night shift"}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected an unknown schedule to fail validation, got %v", err)
+	}
+
+	withoutSchedules := organisation
+	withoutSchedules.WorkSchedules = nil
+	if _, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, withoutSchedules); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected removing an assigned schedule to fail validation, got %v", err)
+	}
+	duplicated := organisation
+	duplicated.WorkSchedules = []domain.WorkSchedule{fourDayWeek, {Name: "FOUR-DAY WEEK", WorkingDays: fourDayWeek.WorkingDays}}
+	if _, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, duplicated); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected duplicate schedule names to fail validation, got %v", err)
+	}
+
+	if _, err = svc.UpdatePerson(ctx, admin, reduced.ID, domain.Person{Name: reduced.Name, EmploymentPct: 100}); err != nil {
+		t.Fatalf("clear schedule: %v", err)
+	}
+	if got := weekAvailability(reduced.ID); got != 56 {
+		t.Fatalf("expected a cleared schedule to restore every day, got %v", got)
+	}
+	if _, err = svc.UpdateOrganisation(ctx, admin, organisation.ID, withoutSchedules); err != nil {
+		t.Fatalf("expected an unassigned schedule to be removable, got %v", err)
+	}
+}
+
+// TestServiceUnavailabilityRules verifies the service unavailability rules scenario.
+func TestServiceUnavailabilityRules(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Rules")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Rule Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	other, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Other Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	group, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Rule Group", MemberIDs: []string{person.ID}})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+
+	rule, err := svc.CreatePersonUnavailabilityRule(ctx, admin, person.ID, domain.UnavailabilityRule{
+		Weekdays:  []string{"Friday"},
+		Hours:     4,
+		StartDate: "2026-03-02",
+	})
+	if err != nil {
+		t.Fatalf("create person rule: %v", err)
+	}
+	if rule.PersonID != person.ID || rule.GroupID != "" || rule.Weekdays[0] != "friday" {
+		t.Fatalf("expected a normalized rule for the person, got %+v", rule)
+	}
+	if _, err = svc.CreateGroupUnavailabilityRule(ctx, admin, group.ID, domain.UnavailabilityRule{
+		Weekdays:  []string{"monday"},
+		Hours:     2,
+		StartDate: "2026-03-02",
+		UntilDate: "2026-03-02",
+	}); err != nil {
+		t.Fatalf("create group rule: %v", err)
+	}
+
+	if _, err = svc.CreatePersonUnavailabilityRule(ctx, user, person.ID, domain.UnavailabilityRule{Weekdays: []string{"friday"}, Hours: 1, StartDate: "2026-03-02"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user rule creation to be forbidden, got %v", err)
+	}
+	if _, err = svc.CreatePersonUnavailabilityRule(ctx, admin, person.ID, domain.UnavailabilityRule{Weekdays: []string{"friday"}, Hours: 9, StartDate: "2026-03-02"}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected hours above the working day to fail validation, got %v", err)
+	}
+	if _, err = svc.CreatePersonUnavailabilityRule(ctx, admin, testMissingID, domain.UnavailabilityRule{Weekdays: []string{"friday"}, Hours: 1, StartDate: "2026-03-02"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a missing person to fail with not found, got %v", err)
+	}
+	if _, err = svc.UpdatePersonUnavailabilityRule(ctx, admin, other.ID, rule.ID, rule); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected another person's rule to be reported missing, got %v", err)
+	}
+
+	rules, err := svc.ListPersonUnavailabilityRules(ctx, user, person.ID)
+	if err != nil || len(rules) != 1 || rules[0].ID != rule.ID {
+		t.Fatalf("expected the person's rule only, got %+v err=%v", rules, err)
+	}
+
+	capacity, err := svc.PersonCapacity(ctx, user, person.ID, "2026-03-02", "2026-03-13")
+	if err != nil {
+		t.Fatalf("person capacity: %v", err)
+	}
+	if len(capacity.Days) != 12 {
+		t.Fatalf("expected 12 capacity days, got %d", len(capacity.Days))
+	}
+	expectedUnavailable := map[string]float64{"2026-03-02": 2, "2026-03-06": 4, "2026-03-09": 0, "2026-03-13": 4}
+	for _, day := range capacity.Days {
+		if want, ok := expectedUnavailable[day.Date]; ok && day.PersonUnavailableHours+day.GroupUnavailableHours != want {
+			t.Fatalf("expected %v unavailable hours on %s, got %+v", want, day.Date, day)
+		}
+	}
+
+	rule.Weekdays = []string{"thursday"}
+	updated, err := svc.UpdatePersonUnavailabilityRule(ctx, admin, person.ID, rule.ID, rule)
+	if err != nil {
+		t.Fatalf("update rule: %v", err)
+	}
+	if updated.Version != rule.Version+1 || updated.Weekdays[0] != "thursday" {
+		t.Fatalf("expected the updated rule with a new version, got %+v", updated)
+	}
+	if _, err = svc.UpdatePersonUnavailabilityRule(ctx, admin, person.ID, rule.ID, rule); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a stale rule update to conflict, got %v", err)
+	}
+
+	if err = svc.DeletePerson(ctx, admin, person.ID, false); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a person with rules to report dependents, got %v", err)
+	}
+	if err = svc.DeletePersonUnavailabilityRule(ctx, admin, person.ID, rule.ID); err != nil {
+		t.Fatalf("delete rule: %v", err)
+	}
+	if rules, err = svc.ListPersonUnavailabilityRules(ctx, admin, person.ID); err != nil || len(rules) != 0 {
+		t.Fatalf("expected no person rules after delete, got %+v err=%v", rules, err)
+	}
+}
+
+// TestServiceEndAllocation verifies the service end allocation scenario.
+func TestServiceEndAllocation(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org End")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Cut Short"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Reassigned", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	allocation, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 50))
+	if err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	if _, err = svc.EndAllocation(ctx, user, allocation.ID, "2026-03-15", "reassigned"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user to be forbidden, got %v", err)
+	}
+	invalidCases := []struct {
+		name    string
+		endDate string
+		reason  string
+	}{
+		{name: "malformed date", endDate: "15.03.2026", reason: "reassigned"},
+		{name: "before start", endDate: "2025-12-31", reason: "reassigned"},
+		{name: "after end", endDate: "2027-01-01", reason: "reassigned"},
+		{name: "missing reason", endDate: "2026-03-15", reason: " "},
+	}
+	for _, testCase := range invalidCases {
+		if _, err = svc.EndAllocation(ctx, admin, allocation.ID, testCase.endDate, testCase.reason); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("%s: expected validation error, got %v", testCase.name, err)
+		}
+	}
+	if _, err = svc.EndAllocation(ctx, admin, testMissingID, "2026-03-15", "reassigned"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected missing allocation to be not found, got %v", err)
+	}
+
+	ended, err := svc.EndAllocation(ctx, admin, allocation.ID, "2026-03-15", " project cut short ")
+	if err != nil {
+		t.Fatalf("end allocation: %v", err)
+	}
+	if ended.EndDate != "2026-03-15" || ended.EndReason != "project cut short" || ended.StartDate != allocation.StartDate {
+		t.Fatalf("unexpected ended allocation: %+v", ended)
+	}
+
+	buckets, err := svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+		Scope:       domain.ScopePerson,
+		IDs:         []string{person.ID},
+		FromDate:    "2026-03-14",
+		ToDate:      "2026-03-16",
+		Granularity: domain.GranularityDay,
+	})
+	if err != nil {
+		t.Fatalf("report after ending allocation: %v", err)
+	}
+	if len(buckets) != 3 || buckets[0].LoadHours != 4 || buckets[1].LoadHours != 4 || buckets[2].LoadHours != 0 {
+		t.Fatalf("expected load to stop after the end date, got %+v", buckets)
+	}
+}
+
+// TestServiceAllocationHoldExpiry verifies the service allocation hold expiry scenario.
+func TestServiceAllocationHoldExpiry(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	clock := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return clock }
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Holds")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Hold Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Held Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+
+	expired := clock.Add(-time.Minute)
+	pastHold := testPersonAllocationInput(person.ID, project.ID, 200)
+	pastHold.HoldExpiresAt = &expired
+	if _, err = svc.CreateAllocation(ctx, admin, pastHold); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected expired hold to fail validation, got %v", err)
+	}
+
+	expiry := clock.Add(24 * time.Hour)
+	holdInput := testPersonAllocationInput(person.ID, project.ID, 200)
+	holdInput.HoldExpiresAt = &expiry
+	hold, err := svc.CreateAllocation(ctx, admin, holdInput)
+	if err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+	if hold.HoldExpiresAt == nil || !hold.HoldExpiresAt.Equal(expiry) {
+		t.Fatalf("expected hold expiry to be stored, got %+v", hold.HoldExpiresAt)
+	}
+
+	reportLoad := func() float64 {
+		t.Helper()
+		buckets, reportErr := svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+			Scope:       domain.ScopePerson,
+			IDs:         []string{person.ID},
+			FromDate:    "2026-03-10",
+			ToDate:      "2026-03-10",
+			Granularity: domain.GranularityDay,
+		})
+		if reportErr != nil || len(buckets) != 1 {
+			t.Fatalf("report load: buckets=%+v err=%v", buckets, reportErr)
+		}
+		return buckets[0].LoadHours
+	}
+	if load := reportLoad(); load != 16 {
+		t.Fatalf("expected active hold to count as load, got %v", load)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 150)); err == nil {
+		t.Fatal("expected active hold to reserve capacity")
+	}
+
+	clock = expiry
+	if load := reportLoad(); load != 0 {
+		t.Fatalf("expected expired hold to be excluded from load, got %v", load)
+	}
+	freed, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 150))
+	if err != nil {
+		t.Fatalf("expected expired hold to free capacity: %v", err)
+	}
+	if err = svc.DeleteAllocation(ctx, admin, freed.ID); err != nil {
+		t.Fatalf("delete allocation: %v", err)
+	}
+
+	clock = expiry.Add(-time.Hour)
+	confirmed, err := svc.UpdateAllocation(ctx, admin, hold.ID, testPersonAllocationInput(person.ID, project.ID, 200))
+	if err != nil {
+		t.Fatalf("confirm allocation: %v", err)
+	}
+	if confirmed.HoldExpiresAt != nil {
+		t.Fatalf("expected confirmation to clear the hold expiry, got %v", confirmed.HoldExpiresAt)
+	}
+	clock = expiry.Add(time.Hour)
+	if load := reportLoad(); load != 16 {
+		t.Fatalf("expected confirmed allocation to keep counting, got %v", load)
+	}
+}
+
+// TestServiceProjectArchiveAndRestore verifies the service project archive and restore scenario.
+func TestServiceProjectArchiveAndRestore(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Archive")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Archivist", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, domain.Project{Name: "Finished", StartDate: testDate20260101, EndDate: "2026-12-31", EstimatedEffortHours: 1000})
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 50)); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	if err = svc.DeleteProject(ctx, admin, project.ID, false, false); err != nil {
+		t.Fatalf("archive project: %v", err)
+	}
+	archived, err := svc.GetProject(ctx, admin, project.ID)
+	if err != nil {
+		t.Fatalf("get archived project: %v", err)
+	}
+	if !archived.Archived || archived.ArchivedAt == nil {
+		t.Fatalf("expected archived project with timestamp, got %+v", archived)
+	}
+
+	listed, err := svc.ListProjects(ctx, admin, false)
+	if err != nil {
+		t.Fatalf("list projects: %v", err)
+	}
+	if len(listed) != 0 {
+		t.Fatalf("expected archived project to be hidden, got %+v", listed)
+	}
+	listed, err = svc.ListProjects(ctx, admin, true)
+	if err != nil {
+		t.Fatalf("list projects with archived: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != project.ID {
+		t.Fatalf("expected archived project when included, got %+v", listed)
+	}
+
+	buckets, err := svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+		Scope:       domain.ScopeProject,
+		IDs:         []string{project.ID},
+		FromDate:    "2026-03-02",
+		ToDate:      "2026-03-02",
+		Granularity: domain.GranularityDay,
+	})
+	if err != nil {
+		t.Fatalf("report archived project: %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].LoadHours != 4 {
+		t.Fatalf("expected archived project load to stay in reports, got %+v", buckets)
+	}
+
+	_, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 10))
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected allocation against archived project to fail validation, got %v", err)
+	}
+
+	restored, err := svc.RestoreProject(ctx, admin, project.ID)
+	if err != nil {
+		t.Fatalf("restore project: %v", err)
+	}
+	if restored.Archived || restored.ArchivedAt != nil {
+		t.Fatalf("expected restored project to be active, got %+v", restored)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 10)); err != nil {
+		t.Fatalf("create allocation after restore: %v", err)
+	}
+
+	var dependentsErr domain.DependentsError
+	if err = svc.DeleteProject(ctx, admin, project.ID, true, false); !errors.As(err, &dependentsErr) || !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected purge with allocations to need cascade, got %v", err)
+	}
+	if len(dependentsErr.Dependents.AllocationIDs) == 0 {
+		t.Fatalf("expected the project allocations to be listed as dependents, got %+v", dependentsErr.Dependents)
+	}
+	if err = svc.DeleteProject(ctx, admin, project.ID, true, true); err != nil {
+		t.Fatalf("purge project: %v", err)
+	}
+	if _, err = svc.GetProject(ctx, admin, project.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected purged project to be gone, got %v", err)
+	}
+	allocations, err := svc.ListAllocations(ctx, admin)
+	if err != nil {
+		t.Fatalf("list allocations: %v", err)
+	}
+	if len(allocations) != 0 {
+		t.Fatalf("expected purge to remove project allocations, got %+v", allocations)
+	}
+}
+
+// TestServiceZeroEmploymentEndsAllocations verifies the service zero employment ends allocations scenario.
+func TestServiceZeroEmploymentEndsAllocations(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation, err := svc.CreateOrganisation(ctx, globalAdmin, domain.Organisation{
+		Name:                           "Org Leavers",
+		HoursPerDay:                    8,
+		HoursPerWeek:                   40,
+		HoursPerYear:                   2080,
+		EndAllocationsOnZeroEmployment: true,
+	})
+	if err != nil {
+		t.Fatalf("create organisation: %v", err)
+	}
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	leaver, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Leaver", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, domain.Project{Name: "Ongoing", StartDate: testDate20260101, EndDate: "2026-12-31", EstimatedEffortHours: 5000})
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	running, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInput(leaver.ID, project.ID, 50))
+	if err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+	if _, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(leaver.ID, project.ID, 25, "2026-08-01", "2026-09-30")); err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	if _, err = svc.UpdatePerson(ctx, admin, leaver.ID, domain.Person{Name: "Leaver", EmploymentPct: 0, EmploymentEffectiveFromMonth: "2026-06"}); err != nil {
+		t.Fatalf("update person: %v", err)
+	}
+
+	allocations, err := svc.ListAllocations(ctx, admin)
+	if err != nil {
+		t.Fatalf("list allocations: %v", err)
+	}
+	if len(allocations) != 1 || allocations[0].ID != running.ID {
+		t.Fatalf("expected only the running allocation to remain, got %+v", allocations)
+	}
+	if allocations[0].EndDate != "2026-05-31" || allocations[0].EndReason != employmentEndReason {
+		t.Fatalf("expected allocation ended before the month, got %+v", allocations[0])
+	}
+
+	loadOn := func(date string) float64 {
+		t.Helper()
+		buckets, reportErr := svc.ReportAvailabilityAndLoad(ctx, admin, domain.ReportRequest{
+			Scope:       domain.ScopePerson,
+			IDs:         []string{leaver.ID},
+			FromDate:    date,
+			ToDate:      date,
+			Granularity: domain.GranularityDay,
+		})
+		if reportErr != nil {
+			t.Fatalf("report %s: %v", date, reportErr)
+		}
+		if len(buckets) != 1 {
+			t.Fatalf("expected one bucket for %s, got %+v", date, buckets)
+		}
+		return buckets[0].LoadHours
+	}
+	if load := loadOn("2026-05-29"); load != 4 {
+		t.Fatalf("expected load before leaving, got %v", load)
+	}
+	if load := loadOn("2026-06-01"); load != 0 {
+		t.Fatalf("expected no load after leaving, got %v", load)
+	}
+
+	events, err := svc.ListAllocationEvents(ctx, admin, "", "")
+	if err != nil {
+		t.Fatalf("list allocation events: %v", err)
+	}
+	if last := events[len(events)-1]; last.Action != domain.AllocationEventDeleted {
+		t.Fatalf("expected the removed allocation to be audited, got %+v", last)
+	}
+}
+
+// TestServiceZeroEmploymentKeepsAllocationsByDefault verifies the service zero employment keeps allocations by default scenario.
+func TestServiceZeroEmploymentKeepsAllocationsByDefault(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Sabbatical")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Stayer", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, domain.Project{Name: "Ongoing", StartDate: testDate20260101, EndDate: "2026-12-31", EstimatedEffortHours: 5000})
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	allocation, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInput(person.ID, project.ID, 50))
+	if err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+
+	if _, err = svc.UpdatePerson(ctx, admin, person.ID, domain.Person{Name: "Stayer", EmploymentPct: 0, EmploymentEffectiveFromMonth: "2026-06"}); err != nil {
+		t.Fatalf("update person: %v", err)
+	}
+	stored, err := svc.GetAllocation(ctx, admin, allocation.ID)
+	if err != nil {
+		t.Fatalf("get allocation: %v", err)
+	}
+	if stored.EndDate != allocation.EndDate {
+		t.Fatalf("expected allocation to keep its end date without the policy, got %+v", stored)
+	}
+
+	if !employmentEndsFrom(domain.Person{EmploymentChanges: []domain.EmploymentChange{{EffectiveMonth: "2026-06", EmploymentPct: 0}}}, "2026-06") {
+		t.Fatal("expected a lasting 0% change to end employment")
+	}
+	if employmentEndsFrom(domain.Person{EmploymentChanges: []domain.EmploymentChange{
+		{EffectiveMonth: "2026-06", EmploymentPct: 0},
+		{EffectiveMonth: "2026-09", EmploymentPct: 80},
+	}}, "2026-06") {
+		t.Fatal("expected a later return to work to keep employment")
+	}
+}
+
+// TestServiceProjectMilestoneValidation verifies the service project milestone validation scenario.
+func TestServiceProjectMilestoneValidation(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Milestones")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	invalid := map[string][]domain.ProjectMilestone{
+		"missing name":    {{Date: "2026-03-01", TargetEffortHours: 10}},
+		"invalid date":    {{Name: "Beta", Date: "2026-13-01", TargetEffortHours: 10}},
+		"before start":    {{Name: "Beta", Date: "2025-12-31", TargetEffortHours: 10}},
+		"after end":       {{Name: "Beta", Date: "2027-01-01", TargetEffortHours: 10}},
+		"negative effort": {{Name: "Beta", Date: "2026-03-01", TargetEffortHours: -1}},
+		"not a number":    {{Name: "Beta", Date: "2026-03-01", TargetEffortHours: math.NaN()}},
+		"infinite effort": {{Name: "Beta", Date: "2026-03-01", TargetEffortHours: math.Inf(1)}},
+		"one bad of many": {{Name: "Alpha", Date: "2026-02-01", TargetEffortHours: 5}, {Name: "Beta", Date: "2028-01-01"}},
+	}
+	for name, milestones := range invalid {
+		input := testProjectInput("Milestone Project")
+		input.Milestones = milestones
+		if _, err := svc.CreateProject(ctx, admin, input); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+
+	input := testProjectInput("Milestone Project")
+	input.Milestones = []domain.ProjectMilestone{
+		{Name: " Beta ", Date: "2026-06-01", TargetEffortHours: 400},
+		{Name: "Alpha", Date: "2026-03-01", TargetEffortHours: 0},
+	}
+	created, err := svc.CreateProject(ctx, admin, input)
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	if len(created.Milestones) != 2 || created.Milestones[0].Name != "Alpha" || created.Milestones[1].Name != "Beta" {
+		t.Fatalf("expected trimmed milestones sorted by date, got %+v", created.Milestones)
+	}
+
+	input.Milestones = nil
+	updated, err := svc.UpdateProject(ctx, admin, created.ID, input)
+	if err != nil {
+		t.Fatalf("update project: %v", err)
+	}
+	if updated.Milestones != nil {
+		t.Fatalf("expected milestones to be cleared, got %+v", updated.Milestones)
+	}
+}
+
+// TestServiceAllocationDateSnapping verifies the service allocation date snapping scenario.
+func TestServiceAllocationDateSnapping(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Week Planning")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	organisation.SnapAllocationDatesTo = "fortnight"
+	if _, err := svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected invalid snap option to fail validation, got %v", err)
+	}
+	organisation.SnapAllocationDatesTo = domain.SnapWeek
+	if _, err := svc.UpdateOrganisation(ctx, admin, organisation.ID, organisation); err != nil {
+		t.Fatalf("enable week snapping: %v", err)
+	}
+
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Snap Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Weekly Planner", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+
+	created, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 50, "2026-03-04", "2026-03-19"))
+	if err != nil {
+		t.Fatalf(errSetupAllocationFmt, err)
+	}
+	if created.StartDate != "2026-03-02" || created.EndDate != "2026-03-22" {
+		t.Fatalf("expected Monday to Sunday range, got %s..%s", created.StartDate, created.EndDate)
+	}
+
+	updated, err := svc.UpdateAllocation(ctx, admin, created.ID, testPersonAllocationInputForRange(person.ID, project.ID, 50, "2026-04-01", "2026-04-01"))
+	if err != nil {
+		t.Fatalf("update allocation: %v", err)
+	}
+	if updated.StartDate != "2026-03-30" || updated.EndDate != "2026-04-05" {
+		t.Fatalf("expected snapped update range, got %s..%s", updated.StartDate, updated.EndDate)
+	}
+
+	// 2026-01-01 is a Thursday, so the snapped start stops at the project start.
+	clamped, err := svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, project.ID, 10, testDate20260101, "2026-01-02"))
+	if err != nil {
+		t.Fatalf("create allocation at project start: %v", err)
+	}
+	if clamped.StartDate != testDate20260101 || clamped.EndDate != "2026-01-04" {
+		t.Fatalf("expected range clamped to project start, got %s..%s", clamped.StartDate, clamped.EndDate)
+	}
+
+	// 2026-03-18 is a Wednesday, so the snapped end stops at the project end.
+	shortProject := testProjectInput("Short Snap Project")
+	shortProject.EndDate = "2026-03-18"
+	short, err := svc.CreateProject(ctx, admin, shortProject)
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	clamped, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, short.ID, 10, "2026-03-10", "2026-03-17"))
+	if err != nil {
+		t.Fatalf("create allocation at project end: %v", err)
+	}
+	if clamped.StartDate != "2026-03-09" || clamped.EndDate != "2026-03-18" {
+		t.Fatalf("expected range clamped to project end, got %s..%s", clamped.StartDate, clamped.EndDate)
+	}
+
+	_, err = svc.CreateAllocation(ctx, admin, testPersonAllocationInputForRange(person.ID, short.ID, 10, "2026-03-10", "2026-03-20"))
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected range past project end to fail validation, got %v", err)
+	}
+}
+
 // TestServicePersonEmploymentChangesByMonth verifies the service person employment changes by month scenario.
 func TestServicePersonEmploymentChangesByMonth(t *testing.T) {
 	svc := newTestService(t)
@@ -1170,17 +3643,17 @@ func TestServiceForbiddenMutationsForOrgUser(t *testing.T) {
 	expectForbiddenError(t, err)
 	_, err = svc.UpdatePerson(ctx, user, person.ID, domain.Person{Name: "x", EmploymentPct: 100})
 	expectForbiddenError(t, err)
-	expectForbiddenError(t, svc.DeletePerson(ctx, user, person.ID))
+	expectForbiddenError(t, svc.DeletePerson(ctx, user, person.ID, false))
 	_, err = svc.CreateProject(ctx, user, testProjectInput("x"))
 	expectForbiddenError(t, err)
 	_, err = svc.UpdateProject(ctx, user, project.ID, testProjectInput("x"))
 	expectForbiddenError(t, err)
-	expectForbiddenError(t, svc.DeleteProject(ctx, user, project.ID))
+	expectForbiddenError(t, svc.DeleteProject(ctx, user, project.ID, false, false))
 	_, err = svc.CreateGroup(ctx, user, domain.Group{Name: "x"})
 	expectForbiddenError(t, err)
 	_, err = svc.UpdateGroup(ctx, user, group.ID, domain.Group{Name: "x"})
 	expectForbiddenError(t, err)
-	expectForbiddenError(t, svc.DeleteGroup(ctx, user, group.ID))
+	expectForbiddenError(t, svc.DeleteGroup(ctx, user, group.ID, false))
 	_, err = svc.AddGroupMember(ctx, user, group.ID, person.ID)
 	expectForbiddenError(t, err)
 	_, err = svc.RemoveGroupMember(ctx, user, group.ID, person.ID)
@@ -1782,6 +4255,42 @@ func testProjectInput(name string) domain.Project {
 	}
 }
 
+// TestAllocationConflictSeverity verifies the allocation conflict severity thresholds scenario.
+func TestAllocationConflictSeverity(t *testing.T) {
+	person := domain.Person{ID: "person_1", Name: "Busy", EmploymentPct: 100}
+	start := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 4)
+	defaultBands := domain.Organisation{}.OverloadBands()
+	customBands := domain.OverloadBands{ModeratePct: 10, SeverePct: 50}
+
+	for _, tc := range []struct {
+		name     string
+		bands    domain.OverloadBands
+		percent  float64
+		severity string
+	}{
+		{name: "below moderate", bands: defaultBands, percent: 124, severity: domain.OverloadMinor},
+		{name: "at moderate", bands: defaultBands, percent: 125, severity: domain.OverloadModerate},
+		{name: "below severe", bands: defaultBands, percent: 199, severity: domain.OverloadModerate},
+		{name: "at severe", bands: defaultBands, percent: 200, severity: domain.OverloadSevere},
+		{name: "custom below moderate", bands: customBands, percent: 109, severity: domain.OverloadMinor},
+		{name: "custom at moderate", bands: customBands, percent: 110, severity: domain.OverloadModerate},
+		{name: "custom at severe", bands: customBands, percent: 150, severity: domain.OverloadSevere},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			allocation := testPersonAllocationInputForRange(person.ID, "project_1", tc.percent, "2026-03-02", "2026-03-06")
+			allocation.ID = "allocation_1"
+			conflicts, err := personAllocationConflicts(person, []domain.Allocation{allocation}, map[string]domain.Group{}, tc.bands, start, end)
+			if err != nil {
+				t.Fatalf("find conflicts: %v", err)
+			}
+			if len(conflicts) != 1 || conflicts[0].Severity != tc.severity {
+				t.Fatalf("expected one %s conflict, got %+v", tc.severity, conflicts)
+			}
+		})
+	}
+}
+
 func testPersonAllocationInput(personID, projectID string, percent float64) domain.Allocation {
 	return domain.Allocation{
 		TargetType: domain.AllocationTargetPerson,
@@ -1828,6 +4337,409 @@ func formatEventMapByDate(events map[time.Time]float64) map[string]float64 {
 	return formatted
 }
 
+// TestServiceAllocationTemplates verifies the service allocation templates scenario.
+func TestServiceAllocationTemplates(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation, err := svc.CreateOrganisation(ctx, globalAdmin, domain.Organisation{
+		Name:                 "Org Templates",
+		HoursPerDay:          8,
+		HoursPerWeek:         40,
+		HoursPerYear:         2080,
+		RejectOverEmployment: true,
+	})
+	if err != nil {
+		t.Fatalf("create organisation: %v", err)
+	}
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Rotation Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	group, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Rotation Group", MemberIDs: []string{person.ID}})
+	if err != nil {
+		t.Fatalf(errSetupGroupFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, domain.Project{Name: "Maintenance", StartDate: testDate20260101, EndDate: "2026-12-31", EstimatedEffortHours: 5000})
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+
+	if _, err = svc.CreateAllocationTemplate(ctx, user, domain.AllocationTemplate{Name: "Rotation"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user template creation to be forbidden, got %v", err)
+	}
+	invalid := []domain.AllocationTemplate{
+		{Name: " ", Entries: []domain.AllocationTemplateEntry{{TargetID: person.ID, Percent: 50}}},
+		{Name: "Empty"},
+		{Name: "Zero", Entries: []domain.AllocationTemplateEntry{{TargetID: person.ID, Percent: 0}}},
+		{Name: "Team", Entries: []domain.AllocationTemplateEntry{{TargetType: "team", TargetID: person.ID, Percent: 50}}},
+		{Name: "Missing", Entries: []domain.AllocationTemplateEntry{{TargetID: testMissingID, Percent: 50}}},
+	}
+	for _, candidate := range invalid {
+		if _, err = svc.CreateAllocationTemplate(ctx, admin, candidate); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("expected template %q to fail validation, got %v", candidate.Name, err)
+		}
+	}
+
+	overbooked, err := svc.CreateAllocationTemplate(ctx, admin, domain.AllocationTemplate{
+		Name: "Overbooked",
+		Entries: []domain.AllocationTemplateEntry{
+			{TargetID: person.ID, Percent: 60},
+			{TargetType: domain.AllocationTargetGroup, TargetID: group.ID, Percent: 60},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create overbooked template: %v", err)
+	}
+	application := domain.AllocationTemplateApplication{ProjectID: project.ID, StartDate: "2026-04-01", EndDate: "2026-06-30"}
+	if _, err = svc.ApplyAllocationTemplate(ctx, admin, overbooked.ID, application); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected entries that overbook the person together to fail validation, got %v", err)
+	}
+	allocations, err := svc.ListAllocations(ctx, admin)
+	if err != nil || len(allocations) != 0 {
+		t.Fatalf("expected a failed application to store nothing, got %+v err=%v", allocations, err)
+	}
+
+	rotation, err := svc.CreateAllocationTemplate(ctx, admin, domain.AllocationTemplate{
+		Name: " Rotation ",
+		Entries: []domain.AllocationTemplateEntry{
+			{TargetType: " Person ", TargetID: person.ID, Percent: 40},
+			{TargetType: domain.AllocationTargetGroup, TargetID: group.ID, Percent: 20},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if rotation.Name != "Rotation" || rotation.Entries[0].TargetType != domain.AllocationTargetPerson {
+		t.Fatalf("expected a normalized template, got %+v", rotation)
+	}
+	templates, err := svc.ListAllocationTemplates(ctx, user)
+	if err != nil || len(templates) != 2 {
+		t.Fatalf("expected both templates, got %+v err=%v", templates, err)
+	}
+
+	if _, err = svc.ApplyAllocationTemplate(ctx, user, rotation.ID, application); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user application to be forbidden, got %v", err)
+	}
+	if _, err = svc.ApplyAllocationTemplate(ctx, admin, testMissingID, application); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a missing template to fail with not found, got %v", err)
+	}
+	created, err := svc.ApplyAllocationTemplate(ctx, admin, rotation.ID, application)
+	if err != nil {
+		t.Fatalf("apply template: %v", err)
+	}
+	if len(created) != 2 || created[0].TargetID != person.ID || created[1].TargetID != group.ID || created[1].StartDate != "2026-04-01" {
+		t.Fatalf("expected one allocation per entry, got %+v", created)
+	}
+	events, err := svc.ListAllocationEvents(ctx, admin, "", "")
+	if err != nil || len(events) != 2 {
+		t.Fatalf("expected a created event per allocation, got %+v err=%v", events, err)
+	}
+
+	defaulted, err := svc.ApplyAllocationTemplate(ctx, admin, rotation.ID, domain.AllocationTemplateApplication{ProjectID: project.ID, StartDate: "2026-07-01"})
+	if err != nil {
+		t.Fatalf("apply template with a default end date: %v", err)
+	}
+	if defaulted[0].EndDate != "2026-12-31" {
+		t.Fatalf("expected the end date to default to the project end, got %+v", defaulted[0])
+	}
+
+	if err = svc.DeleteAllocationTemplate(ctx, admin, rotation.ID); err != nil {
+		t.Fatalf("delete template: %v", err)
+	}
+	if _, err = svc.GetAllocationTemplate(ctx, user, rotation.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a deleted template to be missing, got %v", err)
+	}
+	allocations, err = svc.ListAllocations(ctx, admin)
+	if err != nil || len(allocations) != 4 {
+		t.Fatalf("expected allocations to stay after the template is deleted, got %d err=%v", len(allocations), err)
+	}
+}
+
+// TestServiceScenarios verifies the scenario sandbox, report, and apply scenario.
+func TestServiceScenarios(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation, err := svc.CreateOrganisation(ctx, globalAdmin, domain.Organisation{
+		Name:                 "Org Scenarios",
+		HoursPerDay:          8,
+		HoursPerWeek:         40,
+		HoursPerYear:         2080,
+		RejectOverEmployment: true,
+	})
+	if err != nil {
+		t.Fatalf("create organisation: %v", err)
+	}
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Scenario Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, domain.Project{Name: "Platform", StartDate: testDate20260101, EndDate: "2026-12-31", EstimatedEffortHours: 5000})
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	newAllocation := func(percent float64) domain.Allocation {
+		return domain.Allocation{
+			TargetType: domain.AllocationTargetPerson,
+			TargetID:   person.ID,
+			ProjectID:  project.ID,
+			StartDate:  "2026-04-01",
+			EndDate:    "2026-06-30",
+			Percent:    percent,
+		}
+	}
+	kept, err := svc.CreateAllocation(ctx, admin, newAllocation(50))
+	if err != nil {
+		t.Fatalf("create kept allocation: %v", err)
+	}
+	removed, err := svc.CreateAllocation(ctx, admin, newAllocation(20))
+	if err != nil {
+		t.Fatalf("create removed allocation: %v", err)
+	}
+
+	if _, err = svc.CreateScenario(ctx, user, domain.Scenario{Name: "Hiring"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user scenario creation to be forbidden, got %v", err)
+	}
+	if _, err = svc.CreateScenario(ctx, admin, domain.Scenario{Name: " "}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a blank scenario name to fail validation, got %v", err)
+	}
+	stale, err := svc.CreateScenario(ctx, admin, domain.Scenario{Name: "Stale"})
+	if err != nil {
+		t.Fatalf("create stale scenario: %v", err)
+	}
+	scenario, err := svc.CreateScenario(ctx, admin, domain.Scenario{Name: " Reshuffle "})
+	if err != nil {
+		t.Fatalf("create scenario: %v", err)
+	}
+	if scenario.Name != "Reshuffle" || len(scenario.Allocations) != 2 || scenario.BaseVersions[kept.ID] != kept.Version {
+		t.Fatalf("expected a scenario cloned from the live allocations, got %+v", scenario)
+	}
+
+	if _, err = svc.CreateScenarioAllocation(ctx, admin, scenario.ID, newAllocation(40)); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected the scenario's own allocations to count toward employment, got %v", err)
+	}
+	if err = svc.DeleteScenarioAllocation(ctx, admin, scenario.ID, removed.ID); err != nil {
+		t.Fatalf("delete scenario allocation: %v", err)
+	}
+	added, err := svc.CreateScenarioAllocation(ctx, admin, scenario.ID, newAllocation(40))
+	if err != nil {
+		t.Fatalf("create scenario allocation: %v", err)
+	}
+	if !strings.HasPrefix(added.ID, domain.ScenarioAllocationIDPrefix) || added.Version != 1 {
+		t.Fatalf("expected a scenario allocation ID and version 1, got %+v", added)
+	}
+	changed := newAllocation(60)
+	changed.Version = kept.Version + 1
+	if _, err = svc.UpdateScenarioAllocation(ctx, admin, scenario.ID, kept.ID, changed); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a stale scenario allocation version to conflict, got %v", err)
+	}
+	changed.Version = kept.Version
+	if _, err = svc.UpdateScenarioAllocation(ctx, admin, scenario.ID, kept.ID, changed); err != nil {
+		t.Fatalf("update scenario allocation: %v", err)
+	}
+
+	live, err := svc.ListAllocations(ctx, admin)
+	if err != nil || len(live) != 2 {
+		t.Fatalf("expected scenario edits to leave live allocations alone, got %+v err=%v", live, err)
+	}
+	report := domain.ReportRequest{
+		Scope:       domain.ScopePerson,
+		IDs:         []string{person.ID},
+		FromDate:    "2026-04-01",
+		ToDate:      "2026-04-30",
+		Granularity: domain.GranularityMonth,
+	}
+	liveReport, err := svc.ReportAvailabilityAndLoad(ctx, user, report)
+	if err != nil {
+		t.Fatalf("live report: %v", err)
+	}
+	report.ScenarioID = scenario.ID
+	scenarioReport, err := svc.ReportAvailabilityAndLoad(ctx, user, report)
+	if err != nil {
+		t.Fatalf("scenario report: %v", err)
+	}
+	if len(liveReport) != 1 || len(scenarioReport) != 1 ||
+		math.Abs(scenarioReport[0].LoadHours-liveReport[0].LoadHours*100/70) > 1e-6 {
+		t.Fatalf("expected the scenario report to count 100%% instead of 70%%, got live %+v scenario %+v", liveReport, scenarioReport)
+	}
+	report.ScenarioID = testMissingID
+	if _, err = svc.ReportAvailabilityAndLoad(ctx, user, report); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected an unknown scenario_id to fail validation, got %v", err)
+	}
+
+	if _, err = svc.ApplyScenario(ctx, user, scenario.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user apply to be forbidden, got %v", err)
+	}
+	applied, err := svc.ApplyScenario(ctx, admin, scenario.ID)
+	if err != nil {
+		t.Fatalf("apply scenario: %v", err)
+	}
+	if len(applied.Created) != 1 || len(applied.Updated) != 1 || len(applied.DeletedIDs) != 1 || applied.DeletedIDs[0] != removed.ID {
+		t.Fatalf("expected one created, updated, and deleted allocation, got %+v", applied)
+	}
+	if strings.HasPrefix(applied.Created[0].ID, domain.ScenarioAllocationIDPrefix) || applied.Updated[0].Version != kept.Version+1 {
+		t.Fatalf("expected stored IDs and versions, got %+v", applied)
+	}
+	live, err = svc.ListAllocations(ctx, admin)
+	if err != nil || len(live) != 2 {
+		t.Fatalf("expected two live allocations after applying, got %+v err=%v", live, err)
+	}
+	updated, err := svc.GetAllocation(ctx, admin, kept.ID)
+	if err != nil || updated.Percent != 60 {
+		t.Fatalf("expected the applied update in live data, got %+v err=%v", updated, err)
+	}
+	if _, err = svc.GetAllocation(ctx, admin, removed.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected the applied delete in live data, got %v", err)
+	}
+
+	if _, err = svc.ApplyScenario(ctx, admin, scenario.ID); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected applying twice to conflict, got %v", err)
+	}
+	if _, err = svc.CreateScenarioAllocation(ctx, admin, scenario.ID, newAllocation(5)); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected edits to an applied scenario to conflict, got %v", err)
+	}
+	if err = svc.DeleteScenarioAllocation(ctx, admin, stale.ID, kept.ID); err != nil {
+		t.Fatalf("delete stale scenario allocation: %v", err)
+	}
+	if _, err = svc.ApplyScenario(ctx, admin, stale.ID); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a scenario cloned before a live change to conflict, got %v", err)
+	}
+	if _, err = svc.GetAllocation(ctx, admin, kept.ID); err != nil {
+		t.Fatalf("expected a conflicting apply to store nothing, got %v", err)
+	}
+
+	summaries, err := svc.ListScenarios(ctx, user)
+	if err != nil || len(summaries) != 2 || summaries[1].AppliedAt == nil || summaries[1].AllocationCount != 2 {
+		t.Fatalf("expected two scenario summaries with the applied one marked, got %+v err=%v", summaries, err)
+	}
+	if err = svc.DeleteScenario(ctx, admin, stale.ID); err != nil {
+		t.Fatalf("delete scenario: %v", err)
+	}
+	if _, err = svc.GetScenario(ctx, user, stale.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a deleted scenario to be gone, got %v", err)
+	}
+}
+
+// TestServiceUndoLastOperation verifies the undo history scenario.
+func TestServiceUndoLastOperation(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Undo")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	otherAdmin := ports.AuthContext{UserID: "admin2", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+	user := ports.AuthContext{UserID: "user1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgUser}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Undo Person", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	project, err := svc.CreateProject(ctx, admin, domain.Project{Name: "Undo Project", StartDate: testDate20260101, EndDate: "2026-12-31", EstimatedEffortHours: 5000})
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	newAllocation := func(percent float64, version int64) domain.Allocation {
+		return domain.Allocation{
+			TargetType: domain.AllocationTargetPerson,
+			TargetID:   person.ID,
+			ProjectID:  project.ID,
+			StartDate:  "2026-04-01",
+			EndDate:    "2026-06-30",
+			Percent:    percent,
+			Version:    version,
+		}
+	}
+
+	if _, err = svc.UndoLastOperation(ctx, user); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected org_user undo to be forbidden, got %v", err)
+	}
+	if _, err = svc.UndoLastOperation(ctx, admin); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected nothing to undo, got %v", err)
+	}
+
+	first, err := svc.CreateAllocation(ctx, admin, newAllocation(50, 0))
+	if err != nil {
+		t.Fatalf("create allocation: %v", err)
+	}
+	if _, err = svc.UpdateAllocation(ctx, admin, first.ID, newAllocation(60, first.Version)); err != nil {
+		t.Fatalf("update allocation: %v", err)
+	}
+	second, err := svc.CreateAllocation(ctx, otherAdmin, newAllocation(10, 0))
+	if err != nil {
+		t.Fatalf("create second allocation: %v", err)
+	}
+
+	undone, err := svc.UndoLastOperation(ctx, admin)
+	if err != nil {
+		t.Fatalf("undo update: %v", err)
+	}
+	if undone.Operation.Kind != domain.OperationAllocationUpdated || undone.Allocation == nil ||
+		undone.Allocation.Percent != 50 || undone.Allocation.Version != 3 {
+		t.Fatalf("expected the update to be reverted, got %+v", undone)
+	}
+	undone, err = svc.UndoLastOperation(ctx, admin)
+	if err != nil {
+		t.Fatalf("undo create: %v", err)
+	}
+	if undone.Operation.Kind != domain.OperationAllocationCreated || undone.Allocation != nil {
+		t.Fatalf("expected the create to be undone, got %+v", undone)
+	}
+	if _, err = svc.GetAllocation(ctx, admin, first.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected the created allocation to be deleted, got %v", err)
+	}
+	if _, err = svc.UndoLastOperation(ctx, admin); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected another user's operations to stay, got %v", err)
+	}
+
+	if err = svc.DeleteAllocation(ctx, otherAdmin, second.ID); err != nil {
+		t.Fatalf("delete allocation: %v", err)
+	}
+	undone, err = svc.UndoLastOperation(ctx, otherAdmin)
+	if err != nil {
+		t.Fatalf("undo delete: %v", err)
+	}
+	if undone.Allocation == nil || undone.Allocation.ID != second.ID || undone.Allocation.Version != second.Version+1 {
+		t.Fatalf("expected the allocation to be recreated under its old ID, got %+v", undone)
+	}
+	restored, err := svc.GetAllocation(ctx, admin, second.ID)
+	if err != nil || restored.Percent != 10 {
+		t.Fatalf("expected the recreated allocation to be stored, got %+v err=%v", restored, err)
+	}
+
+	ended, err := svc.EndAllocation(ctx, otherAdmin, second.ID, "2026-05-15", "Moved on")
+	if err != nil {
+		t.Fatalf("end allocation: %v", err)
+	}
+	if _, err = svc.UpdateAllocation(ctx, admin, second.ID, domain.Allocation{
+		TargetType: domain.AllocationTargetPerson,
+		TargetID:   person.ID,
+		ProjectID:  project.ID,
+		StartDate:  "2026-04-01",
+		EndDate:    "2026-05-15",
+		Percent:    30,
+		Version:    ended.Version,
+	}); err != nil {
+		t.Fatalf("update ended allocation: %v", err)
+	}
+	if _, err = svc.UndoLastOperation(ctx, otherAdmin); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected undoing an end changed since to conflict, got %v", err)
+	}
+	current, err := svc.GetAllocation(ctx, admin, second.ID)
+	if err != nil || current.Percent != 30 || current.EndDate != "2026-05-15" {
+		t.Fatalf("expected a conflicting undo to change nothing, got %+v err=%v", current, err)
+	}
+}
+
 func createOrganisationForService(ctx context.Context, t *testing.T, svc *Service, auth ports.AuthContext, name string) domain.Organisation {
 	t.Helper()
 	organisation, err := svc.CreateOrganisation(ctx, auth, domain.Organisation{
@@ -1848,9 +4760,116 @@ func newTestService(t *testing.T) *Service {
 	if err != nil {
 		t.Fatalf("create repository: %v", err)
 	}
-	svc, err := New(repo, telemetry.NewNoopTelemetry(), impexp.NewNoopImportExport())
+	svc, err := New(repo, telemetry.NewNoopTelemetry(), impexp.NewCSVImportExport())
 	if err != nil {
 		t.Fatalf("create service: %v", err)
 	}
 	return svc
 }
+
+// holidayFailingRepository fails every holiday write so demo seeding stops
+// after most of its records are stored.
+type holidayFailingRepository struct {
+	ports.Repository
+}
+
+func (r *holidayFailingRepository) CreateOrgHoliday(context.Context, domain.OrgHoliday) (domain.OrgHoliday, error) {
+	return domain.OrgHoliday{}, errors.New("disk full")
+}
+
+// TestServiceSeedDemoTenantRemovesPartialOrganisation verifies the service seed demo tenant removes partial organisation scenario.
+func TestServiceSeedDemoTenantRemovesPartialOrganisation(t *testing.T) {
+	ctx := context.Background()
+	repo, err := persistence.NewFileRepository(filepath.Join(t.TempDir(), "service-data.json"))
+	if err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+	failing, err := New(&holidayFailingRepository{Repository: repo}, telemetry.NewNoopTelemetry(), impexp.NewCSVImportExport())
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	if seeded, seedErr := failing.SeedDemoTenant(ctx); seedErr == nil || seeded {
+		t.Fatalf("expected failing seed to report an error, got seeded=%v err=%v", seeded, seedErr)
+	}
+	organisations, err := repo.ListOrganisations(ctx)
+	if err != nil {
+		t.Fatalf("list organisations: %v", err)
+	}
+	if len(organisations) != 0 {
+		t.Fatalf("expected partial demo organisation to be removed, got %+v", organisations)
+	}
+
+	svc, err := New(repo, telemetry.NewNoopTelemetry(), impexp.NewCSVImportExport())
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+	if seeded, seedErr := svc.SeedDemoTenant(ctx); seedErr != nil || !seeded {
+		t.Fatalf("expected a later start to seed again, got seeded=%v err=%v", seeded, seedErr)
+	}
+}
+
+// TestServiceAuditsCascadingDeletes verifies the service audits cascading deletes scenario.
+func TestServiceAuditsCascadingDeletes(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	globalAdmin := ports.AuthContext{UserID: "admin", Roles: []string{domain.RoleOrgAdmin}}
+	organisation := createOrganisationForService(ctx, t, svc, globalAdmin, "Org Cascade Audit")
+	admin := ports.AuthContext{UserID: "admin1", OrganisationID: organisation.ID, Roles: []string{domain.RoleOrgAdmin}}
+
+	person, err := svc.CreatePerson(ctx, admin, domain.Person{Name: "Cascaded", EmploymentPct: 100})
+	if err != nil {
+		t.Fatalf(errSetupPersonFmt, err)
+	}
+	group, err := svc.CreateGroup(ctx, admin, domain.Group{Name: "Cascaded Group", MemberIDs: []string{person.ID}})
+	if err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	project, err := svc.CreateProject(ctx, admin, testProjectInput("Cascaded Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	other, err := svc.CreateProject(ctx, admin, testProjectInput("Other Project"))
+	if err != nil {
+		t.Fatalf(errSetupProjectFmt, err)
+	}
+	removed := map[string]bool{}
+	for _, input := range []domain.Allocation{
+		testPersonAllocationInput(person.ID, project.ID, 10),
+		{TargetType: domain.AllocationTargetGroup, TargetID: group.ID, ProjectID: other.ID, StartDate: testDate20260101, EndDate: "2026-12-31", Percent: 10},
+		testPersonAllocationInput(person.ID, other.ID, 10),
+	} {
+		created, createErr := svc.CreateAllocation(ctx, admin, input)
+		if createErr != nil {
+			t.Fatalf(errSetupAllocationFmt, createErr)
+		}
+		removed[created.ID] = true
+	}
+
+	if err = svc.DeleteProject(ctx, admin, project.ID, true, true); err != nil {
+		t.Fatalf("purge project: %v", err)
+	}
+	if err = svc.DeleteGroup(ctx, admin, group.ID, true); err != nil {
+		t.Fatalf("delete group: %v", err)
+	}
+	if err = svc.DeletePerson(ctx, admin, person.ID, true); err != nil {
+		t.Fatalf("delete person: %v", err)
+	}
+
+	events, err := svc.ListAllocationEvents(ctx, admin, "", "")
+	if err != nil {
+		t.Fatalf("list allocation events: %v", err)
+	}
+	for _, event := range events {
+		if event.Action != domain.AllocationEventDeleted {
+			continue
+		}
+		if event.ActorID != admin.UserID || event.Before == nil {
+			t.Fatalf("expected the cascading delete to name the caller and keep the allocation, got %+v", event)
+		}
+		delete(removed, event.AllocationID)
+	}
+	if len(removed) != 0 {
+		t.Fatalf("expected a deleted event for every cascaded allocation, missing %v", removed)
+	}
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// ExportTenant returns every record of the caller's organisation as a tenant
+// snapshot encoded in format.
+func (s *Service) ExportTenant(ctx context.Context, auth ports.AuthContext, format string) ([]byte, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.tenantSnapshot(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := s.importer.EncodeTenantSnapshot(snapshot, strings.ToLower(strings.TrimSpace(format)))
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, "tenant.exported", map[string]string{"organisation_id": organisationID, "format": format})
+	return encoded, nil
+}
+
+func (s *Service) tenantSnapshot(ctx context.Context, organisationID string) (domain.TenantSnapshot, error) {
+	snapshot := domain.TenantSnapshot{FormatVersion: domain.TenantSnapshotVersion}
+	var err error
+	if snapshot.Organisation, err = s.repo.GetOrganisation(ctx, organisationID); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	if snapshot.Persons, err = s.repo.ListPersons(ctx, organisationID); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	if snapshot.Projects, err = s.repo.ListProjects(ctx, organisationID); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	if snapshot.Groups, err = s.repo.ListGroups(ctx, organisationID); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	if snapshot.Allocations, err = s.repo.ListAllocations(ctx, organisationID); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	if snapshot.Holidays, err = s.repo.ListOrgHolidays(ctx, organisationID); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	if snapshot.GroupUnavailability, err = s.repo.ListGroupUnavailability(ctx, organisationID); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	if snapshot.PersonUnavailability, err = s.repo.ListPersonUnavailability(ctx, organisationID); err != nil {
+		return domain.TenantSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// ImportTenant validates a tenant snapshot and stores it as a new
+// organisation. Every problem found is listed in the result and nothing is
+// stored unless the whole snapshot is valid. A dry run only validates.
+func (s *Service) ImportTenant(ctx context.Context, auth ports.AuthContext, raw []byte, format string, dryRun bool) (domain.TenantImportResult, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.TenantImportResult{}, err
+	}
+	snapshot, err := s.importer.DecodeTenantSnapshot(raw, strings.ToLower(strings.TrimSpace(format)))
+	if err != nil {
+		return domain.TenantImportResult{}, err
+	}
+
+	result := domain.TenantImportResult{
+		DryRun: dryRun,
+		Counts: tenantSnapshotCounts(snapshot),
+		Issues: validateTenantSnapshot(snapshot),
+	}
+	err = s.requireUniqueOrganisationName(ctx, snapshot.Organisation.Name, "")
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		result.Issues = append(result.Issues, domain.TenantImportIssue{Record: "organisation", Message: err.Error()})
+	case err != nil:
+		return domain.TenantImportResult{}, err
+	}
+	result.Valid = len(result.Issues) == 0
+	if !result.Valid || dryRun {
+		return result, nil
+	}
+
+	imported, err := s.repo.ImportTenant(s.withAllocationAudit(ctx, auth), normalizedTenantSnapshot(snapshot))
+	if err != nil {
+		return domain.TenantImportResult{}, err
+	}
+	result.Imported = true
+	result.OrganisationID = imported.Organisation.ID
+
+	s.record(ctx, "tenant.imported", map[string]string{
+		"organisation_id": imported.Organisation.ID,
+		"persons":         strconv.Itoa(len(imported.Persons)),
+		"allocations":     strconv.Itoa(len(imported.Allocations)),
+	})
+	return result, nil
+}
+
+func tenantSnapshotCounts(snapshot domain.TenantSnapshot) map[string]int {
+	return map[string]int{
+		"persons":               len(snapshot.Persons),
+		"projects":              len(snapshot.Projects),
+		"groups":                len(snapshot.Groups),
+		"allocations":           len(snapshot.Allocations),
+		"holidays":              len(snapshot.Holidays),
+		"group_unavailability":  len(snapshot.GroupUnavailability),
+		"person_unavailability": len(snapshot.PersonUnavailability),
+	}
+}
+
+// normalizedTenantSnapshot trims names the same way the create endpoints do
+// and resolves legacy allocation targets.
+func normalizedTenantSnapshot(snapshot domain.TenantSnapshot) domain.TenantSnapshot {
+	snapshot.Organisation.Name = strings.TrimSpace(snapshot.Organisation.Name)
+	snapshot.Organisation.WriteWindow = normalizedWriteWindow(snapshot.Organisation.WriteWindow)
+	snapshot.Organisation.WorkSchedules = normalizedWorkSchedules(snapshot.Organisation.WorkSchedules)
+
+	persons := make([]domain.Person, len(snapshot.Persons))
+	for index, person := range snapshot.Persons {
+		person.Name = strings.TrimSpace(person.Name)
+		person.WorkSchedule, _ = resolveWorkSchedule(snapshot.Organisation, person.WorkSchedule)
+		persons[index] = person
+	}
+	snapshot.Persons = persons
+
+	projects := make([]domain.Project, len(snapshot.Projects))
+	for index, project := range snapshot.Projects {
+		project.Name = strings.TrimSpace(project.Name)
+		projects[index] = project
+	}
+	snapshot.Projects = projects
+
+	groups := make([]domain.Group, len(snapshot.Groups))
+	for index, group := range snapshot.Groups {
+		group.Name = strings.TrimSpace(group.Name)
+		groups[index] = group
+	}
+	snapshot.Groups = groups
+
+	allocations := make([]domain.Allocation, len(snapshot.Allocations))
+	for index, allocation := range snapshot.Allocations {
+		allocation.TargetType, allocation.TargetID = normalizedAllocationTarget(allocation)
+		allocations[index] = allocation
+	}
+	snapshot.Allocations = allocations
+	return snapshot
+}
+
+// tenantSnapshotIssues collects the problems found in one snapshot.
+type tenantSnapshotIssues []domain.TenantImportIssue
+
+func (issues *tenantSnapshotIssues) add(record string, err error) {
+	*issues = append(*issues, domain.TenantImportIssue{Record: record, Message: err.Error()})
+}
+
+func snapshotRecord(kind string, index int) string {
+	return fmt.Sprintf("%s[%d]", kind, index)
+}
+
+// validateTenantSnapshot checks every record with the rules of the create
+// endpoints and checks that references point at records of the snapshot.
+func validateTenantSnapshot(snapshot domain.TenantSnapshot) []domain.TenantImportIssue {
+	issues := tenantSnapshotIssues{}
+	if snapshot.FormatVersion != domain.TenantSnapshotVersion {
+		issues.add("format_version", fmt.Errorf("format version %d is not supported, expected %d", snapshot.FormatVersion, domain.TenantSnapshotVersion))
+	}
+	organisation := snapshot.Organisation
+	if err := validateOrganisation(organisation); err != nil {
+		issues.add("organisation", err)
+	}
+
+	personsByID := make(map[string]domain.Person, len(snapshot.Persons))
+	for index, person := range snapshot.Persons {
+		record := snapshotRecord("persons", index)
+		if err := validateSnapshotID(person.ID, personsByID); err != nil {
+			issues.add(record, err)
+		}
+		if err := validatePerson(person); err != nil {
+			issues.add(record, err)
+		}
+		if _, err := resolveWorkSchedule(organisation, person.WorkSchedule); err != nil {
+			issues.add(record, err)
+		}
+		personsByID[person.ID] = person
+	}
+
+	projectsByID := make(map[string]domain.Project, len(snapshot.Projects))
+	for index, project := range snapshot.Projects {
+		record := snapshotRecord("projects", index)
+		if err := validateSnapshotID(project.ID, projectsByID); err != nil {
+			issues.add(record, err)
+		}
+		if err := validateProject(project); err != nil {
+			issues.add(record, err)
+		}
+		projectsByID[project.ID] = project
+	}
+
+	groupsByID := make(map[string]domain.Group, len(snapshot.Groups))
+	for index, group := range snapshot.Groups {
+		if err := validateSnapshotID(group.ID, groupsByID); err != nil {
+			issues.add(snapshotRecord("groups", index), err)
+		}
+		groupsByID[group.ID] = group
+	}
+	for index, group := range snapshot.Groups {
+		record := snapshotRecord("groups", index)
+		if err := validateGroup(group); err != nil {
+			issues.add(record, err)
+		}
+		if err := validateSnapshotReferences("person", group.MemberIDs, personsByID); err != nil {
+			issues.add(record, err)
+		}
+		if err := validateSubGroups(group.SubGroupIDs, group.ID, groupsByID); err != nil {
+			issues.add(record, err)
+		} else if _, err = domain.GroupPersonIDs(group.ID, groupsByID); err != nil {
+			issues.add(record, err)
+		}
+	}
+
+	for index, allocation := range snapshot.Allocations {
+		record := snapshotRecord("allocations", index)
+		allocation.TargetType, allocation.TargetID = normalizedAllocationTarget(allocation)
+		for _, fieldError := range allocationFieldErrors(allocation) {
+			issues.add(record, errors.New(fieldError.Message))
+		}
+		if allocation.TargetType == domain.AllocationTargetGroup {
+			if err := validateSnapshotReference("group", allocation.TargetID, groupsByID); err != nil {
+				issues.add(record, err)
+			}
+		} else if err := validateSnapshotReference("person", allocation.TargetID, personsByID); err != nil {
+			issues.add(record, err)
+		}
+		if err := validateSnapshotReference("project", allocation.ProjectID, projectsByID); err != nil {
+			issues.add(record, err)
+		}
+	}
+
+	for index, holiday := range snapshot.Holidays {
+		record := snapshotRecord("holidays", index)
+		if err := validateDateHours(holiday.Date, holiday.Hours, organisation.HoursPerDayOn(holiday.Date)); err != nil {
+			issues.add(record, err)
+		}
+		if err := validateSnapshotReferences("person", holiday.PersonIDs, personsByID); err != nil {
+			issues.add(record, err)
+		}
+	}
+	for index, entry := range snapshot.GroupUnavailability {
+		record := snapshotRecord("group_unavailability", index)
+		if err := validateDateHours(entry.Date, entry.Hours, organisation.HoursPerDayOn(entry.Date)); err != nil {
+			issues.add(record, err)
+		}
+		if err := validateSnapshotReference("group", entry.GroupID, groupsByID); err != nil {
+			issues.add(record, err)
+		}
+	}
+	for index, entry := range snapshot.PersonUnavailability {
+		record := snapshotRecord("person_unavailability", index)
+		person, ok := personsByID[entry.PersonID]
+		if !ok {
+			issues.add(record, unknownSnapshotReference("person", entry.PersonID))
+			continue
+		}
+		dailyHours, err := personDailyHoursOn(organisation, person, entry.Date)
+		if err == nil {
+			err = validateDateHours(entry.Date, entry.Hours, dailyHours)
+		}
+		if err != nil {
+			issues.add(record, err)
+		}
+	}
+	return issues
+}
+
+func validateSnapshotID[T any](id string, seen map[string]T) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("id is required: %w", domain.ErrValidation)
+	}
+	if _, ok := seen[id]; ok {
+		return fmt.Errorf("id %q is used more than once: %w", id, domain.ErrValidation)
+	}
+	return nil
+}
+
+func validateSnapshotReference[T any](kind, id string, known map[string]T) error {
+	if _, ok := known[id]; !ok {
+		return unknownSnapshotReference(kind, id)
+	}
+	return nil
+}
+
+func validateSnapshotReferences[T any](kind string, ids []string, known map[string]T) error {
+	for _, id := range ids {
+		if err := validateSnapshotReference(kind, id, known); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unknownSnapshotReference(kind, id string) error {
+	return fmt.Errorf("%s %q is not part of the snapshot: %w", kind, id, domain.ErrValidation)
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// unavailabilityRuleTarget names the person or the group a rule belongs to.
+// Exactly one of the IDs is set.
+type unavailabilityRuleTarget struct {
+	personID string
+	groupID  string
+}
+
+func (t unavailabilityRuleTarget) owns(rule domain.UnavailabilityRule) bool {
+	return rule.PersonID == t.personID && rule.GroupID == t.groupID
+}
+
+func (t unavailabilityRuleTarget) attributes(ruleID string) map[string]string {
+	attributes := map[string]string{"rule_id": ruleID}
+	if t.personID != "" {
+		attributes["person_id"] = t.personID
+	} else {
+		attributes["group_id"] = t.groupID
+	}
+	return attributes
+}
+
+// ListPersonUnavailabilityRules returns the recurring unavailability rules of
+// one person.
+func (s *Service) ListPersonUnavailabilityRules(ctx context.Context, auth ports.AuthContext, personID string) ([]domain.UnavailabilityRule, error) {
+	return s.listUnavailabilityRules(ctx, auth, unavailabilityRuleTarget{personID: personID})
+}
+
+// CreatePersonUnavailabilityRule validates and stores a recurring
+// unavailability rule for one person.
+func (s *Service) CreatePersonUnavailabilityRule(ctx context.Context, auth ports.AuthContext, personID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	return s.createUnavailabilityRule(ctx, auth, unavailabilityRuleTarget{personID: personID}, input)
+}
+
+// UpdatePersonUnavailabilityRule validates and replaces a recurring
+// unavailability rule of one person.
+func (s *Service) UpdatePersonUnavailabilityRule(ctx context.Context, auth ports.AuthContext, personID, ruleID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	return s.updateUnavailabilityRule(ctx, auth, unavailabilityRuleTarget{personID: personID}, ruleID, input)
+}
+
+// DeletePersonUnavailabilityRule deletes a recurring unavailability rule of
+// one person.
+func (s *Service) DeletePersonUnavailabilityRule(ctx context.Context, auth ports.AuthContext, personID, ruleID string) error {
+	return s.deleteUnavailabilityRule(ctx, auth, unavailabilityRuleTarget{personID: personID}, ruleID)
+}
+
+// ListGroupUnavailabilityRules returns the recurring unavailability rules of
+// one group.
+func (s *Service) ListGroupUnavailabilityRules(ctx context.Context, auth ports.AuthContext, groupID string) ([]domain.UnavailabilityRule, error) {
+	return s.listUnavailabilityRules(ctx, auth, unavailabilityRuleTarget{groupID: groupID})
+}
+
+// CreateGroupUnavailabilityRule validates and stores a recurring
+// unavailability rule for one group.
+func (s *Service) CreateGroupUnavailabilityRule(ctx context.Context, auth ports.AuthContext, groupID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	return s.createUnavailabilityRule(ctx, auth, unavailabilityRuleTarget{groupID: groupID}, input)
+}
+
+// UpdateGroupUnavailabilityRule validates and replaces a recurring
+// unavailability rule of one group.
+func (s *Service) UpdateGroupUnavailabilityRule(ctx context.Context, auth ports.AuthContext, groupID, ruleID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	return s.updateUnavailabilityRule(ctx, auth, unavailabilityRuleTarget{groupID: groupID}, ruleID, input)
+}
+
+// DeleteGroupUnavailabilityRule deletes a recurring unavailability rule of
+// one group.
+func (s *Service) DeleteGroupUnavailabilityRule(ctx context.Context, auth ports.AuthContext, groupID, ruleID string) error {
+	return s.deleteUnavailabilityRule(ctx, auth, unavailabilityRuleTarget{groupID: groupID}, ruleID)
+}
+
+func (s *Service) listUnavailabilityRules(ctx context.Context, auth ports.AuthContext, target unavailabilityRuleTarget) ([]domain.UnavailabilityRule, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner, domain.RoleOrgUser); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.requireUnavailabilityRuleTarget(ctx, organisationID, target); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.repo.ListUnavailabilityRules(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]domain.UnavailabilityRule, 0, len(rules))
+	for _, rule := range rules {
+		if target.owns(rule) {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) createUnavailabilityRule(ctx context.Context, auth ports.AuthContext, target unavailabilityRuleTarget, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	if err = s.requireUnavailabilityRuleTarget(ctx, organisationID, target); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	rule, err := s.validatedUnavailabilityRule(ctx, organisationID, input)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	rule.OrganisationID = organisationID
+	rule.PersonID = target.personID
+	rule.GroupID = target.groupID
+
+	created, err := s.repo.CreateUnavailabilityRule(ctx, rule)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+
+	s.recordChange(ctx, organisationID, "unavailability_rule.created", target.attributes(created.ID))
+	return created, nil
+}
+
+func (s *Service) updateUnavailabilityRule(ctx context.Context, auth ports.AuthContext, target unavailabilityRuleTarget, ruleID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	if _, err = s.ownedUnavailabilityRule(ctx, organisationID, target, ruleID); err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	rule, err := s.validatedUnavailabilityRule(ctx, organisationID, input)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	rule.ID = ruleID
+	rule.OrganisationID = organisationID
+
+	updated, err := s.repo.UpdateUnavailabilityRule(ctx, rule)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+
+	s.recordChange(ctx, organisationID, "unavailability_rule.updated", target.attributes(ruleID))
+	return updated, nil
+}
+
+func (s *Service) deleteUnavailabilityRule(ctx context.Context, auth ports.AuthContext, target unavailabilityRuleTarget, ruleID string) error {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin, domain.RoleOrgPlanner); err != nil {
+		return err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
+	if _, err = s.ownedUnavailabilityRule(ctx, organisationID, target, ruleID); err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteUnavailabilityRule(ctx, organisationID, ruleID); err != nil {
+		return err
+	}
+
+	s.recordChange(ctx, organisationID, "unavailability_rule.deleted", target.attributes(ruleID))
+	return nil
+}
+
+// requireUnavailabilityRuleTarget fails with ErrNotFound when the person or
+// group is not in the organisation.
+func (s *Service) requireUnavailabilityRuleTarget(ctx context.Context, organisationID string, target unavailabilityRuleTarget) error {
+	if target.personID != "" {
+		_, err := s.repo.GetPerson(ctx, organisationID, target.personID)
+		return err
+	}
+	_, err := s.repo.GetGroup(ctx, organisationID, target.groupID)
+	return err
+}
+
+// ownedUnavailabilityRule returns a rule of the target. Rules of other
+// people or groups are reported as missing.
+func (s *Service) ownedUnavailabilityRule(ctx context.Context, organisationID string, target unavailabilityRuleTarget, ruleID string) (domain.UnavailabilityRule, error) {
+	rule, err := s.repo.GetUnavailabilityRule(ctx, organisationID, ruleID)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	if !target.owns(rule) {
+		return domain.UnavailabilityRule{}, domain.ErrNotFound
+	}
+	return rule, nil
+}
+
+// validatedUnavailabilityRule normalizes the rule fields a caller may set and
+// caps the hours at the organisation's working day.
+func (s *Service) validatedUnavailabilityRule(ctx context.Context, organisationID string, input domain.UnavailabilityRule) (domain.UnavailabilityRule, error) {
+	organisation, err := s.repo.GetOrganisation(ctx, organisationID)
+	if err != nil {
+		return domain.UnavailabilityRule{}, err
+	}
+	rule := domain.UnavailabilityRule{
+		Weekdays:  input.Weekdays,
+		Hours:     input.Hours,
+		StartDate: input.StartDate,
+		UntilDate: input.UntilDate,
+		Version:   input.Version,
+	}.Normalized()
+	if err = rule.Validate(); err != nil {
+		return domain.UnavailabilityRule{}, errors.Join(err, errors.New("rule needs weekday names, positive hours, and a start_date on or before until_date"))
+	}
+	if rule.Hours > organisation.MaxHoursPerDay()+dailyHoursTolerance {
+		return domain.UnavailabilityRule{}, errors.Join(domain.ErrValidation, errors.New("rule hours must not exceed the organisation's hours per day"))
+	}
+	return rule, nil
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"sort"
 	"strings"
@@ -16,15 +18,96 @@ func validateOrganisation(organisation domain.Organisation) error {
 	if organisation.HoursPerDay <= 0 || organisation.HoursPerWeek <= 0 || organisation.HoursPerYear <= 0 {
 		return domain.ErrValidation
 	}
+	if err := domain.ValidateAllocationDateSnap(strings.TrimSpace(organisation.SnapAllocationDatesTo)); err != nil {
+		return errors.Join(domain.ErrValidation, fmt.Errorf("snap_allocation_dates_to must be one of %s, %s, or %s", domain.SnapNone, domain.SnapWeek, domain.SnapMonth))
+	}
+	if err := domain.ValidateAllocationPercentStep(organisation.AllocationPercentStep); err != nil {
+		return errors.Join(domain.ErrValidation, errors.New("allocation_percent_step must be between 0 and 100 and divide 100 into whole steps"))
+	}
+	if err := domain.ValidateAllocationPercentStepMode(strings.TrimSpace(organisation.AllocationPercentStepMode)); err != nil {
+		return errors.Join(domain.ErrValidation, fmt.Errorf("allocation_percent_step_mode must be %s or %s", domain.PercentStepReject, domain.PercentStepSnap))
+	}
+	if organisation.WriteWindow != nil {
+		if err := organisation.WriteWindow.Normalized().Validate(); err != nil {
+			return errors.Join(domain.ErrValidation, errors.New("write_window needs known weekday names and HH:MM start and end times with start before end"))
+		}
+	}
+	if err := validateWorkSchedules(organisation.WorkSchedules); err != nil {
+		return err
+	}
+	if err := validateHoursBaselines(organisation.HoursBaselines); err != nil {
+		return err
+	}
+	if _, err := domain.LoadTimezone(organisation.Timezone); err != nil {
+		return err
+	}
+	if organisation.OverloadModeratePct < 0 || organisation.OverloadSeverePct < 0 {
+		return errors.Join(domain.ErrValidation, errors.New("overload bands must not be negative"))
+	}
+	if err := organisation.OverloadBands().Validate(); err != nil {
+		return errors.Join(domain.ErrValidation, errors.New("overload_moderate_pct must be below overload_severe_pct"))
+	}
+	return nil
+}
+
+func validateWorkSchedules(schedules []domain.WorkSchedule) error {
+	seen := make(map[string]bool, len(schedules))
+	for _, schedule := range schedules {
+		schedule = schedule.Normalized()
+		if err := schedule.Validate(); err != nil {
+			return errors.Join(domain.ErrValidation, fmt.Errorf(
+				"work schedule %q needs a name, one to four weeks of working_days with at least one working day, and a Monday cycle_start",
+				schedule.Name,
+			))
+		}
+		key := strings.ToLower(schedule.Name)
+		if seen[key] {
+			return errors.Join(domain.ErrValidation, fmt.Errorf("work schedule name %q is used more than once", schedule.Name))
+		}
+		seen[key] = true
+	}
 	return nil
 }
 
+func validateHoursBaselines(baselines []domain.HoursBaseline) error {
+	normalized := normalizedHoursBaselines(baselines)
+	for index, baseline := range normalized {
+		if err := baseline.Validate(); err != nil {
+			return errors.Join(domain.ErrValidation, fmt.Errorf(
+				"hours baseline starting %q needs a start_date, an end_date on or after it, and positive hours",
+				baseline.StartDate,
+			))
+		}
+		for _, other := range normalized[:index] {
+			if domain.HoursBaselinesOverlap(baseline, other) {
+				return errors.Join(domain.ErrValidation, fmt.Errorf(
+					"hours baselines starting %s and %s overlap, their date ranges must not share a day",
+					other.StartDate,
+					baseline.StartDate,
+				))
+			}
+		}
+	}
+	return nil
+}
+
+func employmentPctError() error {
+	return errors.Join(
+		domain.ErrValidation,
+		fmt.Errorf("employment_pct must be between 0 and 100 with at most %d decimal places", domain.EmploymentPctDecimals),
+	)
+}
+
+// dailyHoursTolerance absorbs floating point error in daily hour caps derived
+// from fractional employment percentages.
+const dailyHoursTolerance = 1e-9
+
 func validatePerson(person domain.Person) error {
 	if err := domain.ValidateName(person.Name); err != nil {
 		return domain.ErrValidation
 	}
-	if err := domain.ValidatePercent(person.EmploymentPct); err != nil {
-		return domain.ErrValidation
+	if err := domain.ValidateEmploymentPct(person.EmploymentPct); err != nil {
+		return employmentPctError()
 	}
 	if strings.TrimSpace(person.EmploymentEffectiveFromMonth) != "" {
 		if _, err := domain.ValidateMonth(strings.TrimSpace(person.EmploymentEffectiveFromMonth)); err != nil {
@@ -35,8 +118,8 @@ func validatePerson(person domain.Person) error {
 		if _, err := domain.ValidateMonth(change.EffectiveMonth); err != nil {
 			return domain.ErrValidation
 		}
-		if err := domain.ValidatePercent(change.EmploymentPct); err != nil {
-			return domain.ErrValidation
+		if err := domain.ValidateEmploymentPct(change.EmploymentPct); err != nil {
+			return employmentPctError()
 		}
 	}
 	return nil
@@ -80,15 +163,55 @@ func validateProject(project domain.Project) error {
 	if project.EstimatedEffortHours <= 0 {
 		return domain.ErrValidation
 	}
-	if strings.TrimSpace(project.StartDate) == "" || strings.TrimSpace(project.EndDate) == "" {
+	if strings.TrimSpace(project.StartDate) == "" {
 		return domain.ErrValidation
 	}
-	if _, _, err := parseDateRange(project.StartDate, project.EndDate); err != nil {
+	// An empty end date leaves the project open-ended. parseDateRange reads
+	// it as a far-future bound, so allocations may end on any later date.
+	projectStart, projectEnd, err := parseDateRange(project.StartDate, project.EndDate)
+	if err != nil {
 		return domain.ErrValidation
 	}
+	return validateProjectMilestones(project.Milestones, projectStart, projectEnd)
+}
+
+func validateProjectMilestones(milestones []domain.ProjectMilestone, projectStart, projectEnd time.Time) error {
+	for _, milestone := range milestones {
+		if err := domain.ValidateName(milestone.Name); err != nil {
+			return errors.Join(domain.ErrValidation, errors.New("milestone name is required"))
+		}
+		date, err := time.Parse(domain.DateLayout, strings.TrimSpace(milestone.Date))
+		if err != nil {
+			return errors.Join(domain.ErrValidation, fmt.Errorf("milestone %q date must use YYYY-MM-DD", milestone.Name))
+		}
+		if date.Before(projectStart) || date.After(projectEnd) {
+			return errors.Join(domain.ErrValidation, fmt.Errorf("milestone %q date must be within the project range", milestone.Name))
+		}
+		if math.IsNaN(milestone.TargetEffortHours) || math.IsInf(milestone.TargetEffortHours, 0) || milestone.TargetEffortHours < 0 {
+			return errors.Join(domain.ErrValidation, fmt.Errorf("milestone %q target_effort_hours must be non-negative", milestone.Name))
+		}
+	}
 	return nil
 }
 
+func normalizeProjectMilestones(milestones []domain.ProjectMilestone) []domain.ProjectMilestone {
+	if len(milestones) == 0 {
+		return nil
+	}
+	normalized := make([]domain.ProjectMilestone, 0, len(milestones))
+	for _, milestone := range milestones {
+		normalized = append(normalized, domain.ProjectMilestone{
+			Name:              strings.TrimSpace(milestone.Name),
+			Date:              strings.TrimSpace(milestone.Date),
+			TargetEffortHours: milestone.TargetEffortHours,
+		})
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return normalized[i].Date < normalized[j].Date
+	})
+	return normalized
+}
+
 func validateGroup(group domain.Group) error {
 	if err := domain.ValidateName(group.Name); err != nil {
 		return domain.ErrValidation
@@ -97,25 +220,52 @@ func validateGroup(group domain.Group) error {
 }
 
 func validateAllocation(allocation domain.Allocation) error {
-	if err := domain.ValidateAllocationTargetType(allocation.TargetType); err != nil {
+	if len(allocationFieldErrors(allocation)) > 0 {
 		return domain.ErrValidation
 	}
+	return nil
+}
+
+// allocationFieldErrors lists the payload problems of an allocation that can
+// be found without looking up its project or target.
+func allocationFieldErrors(allocation domain.Allocation) []domain.AllocationFieldError {
+	fieldErrors := []domain.AllocationFieldError{}
+	add := func(field, message string) {
+		fieldErrors = append(fieldErrors, domain.AllocationFieldError{Field: field, Message: message})
+	}
+
+	if err := domain.ValidateAllocationTargetType(allocation.TargetType); err != nil {
+		add("target_type", fmt.Sprintf("target_type must be %s or %s", domain.AllocationTargetPerson, domain.AllocationTargetGroup))
+	}
 	if strings.TrimSpace(allocation.TargetID) == "" {
-		return domain.ErrValidation
+		add("target_id", "target_id is required")
 	}
 	if strings.TrimSpace(allocation.ProjectID) == "" {
-		return domain.ErrValidation
-	}
-	if strings.TrimSpace(allocation.StartDate) == "" || strings.TrimSpace(allocation.EndDate) == "" {
-		return domain.ErrValidation
+		add("project_id", "project_id is required")
 	}
-	if _, _, err := parseDateRange(allocation.StartDate, allocation.EndDate); err != nil {
-		return domain.ErrValidation
+	startDate, startOK := allocationDateField(allocation.StartDate, "start_date", add)
+	endDate, endOK := allocationDateField(allocation.EndDate, "end_date", add)
+	if startOK && endOK && endDate < startDate {
+		add("end_date", "end_date must not be before start_date")
 	}
 	if math.IsNaN(allocation.Percent) || math.IsInf(allocation.Percent, 0) || allocation.Percent < 0 {
-		return domain.ErrValidation
+		add("percent", "percent must be a number of at least 0")
 	}
-	return nil
+	return fieldErrors
+}
+
+func allocationDateField(value, field string, add func(field, message string)) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		add(field, field+" is required")
+		return "", false
+	}
+	normalized, err := domain.ValidateDate(value)
+	if err != nil {
+		add(field, field+" must be a date in YYYY-MM-DD format")
+		return "", false
+	}
+	return normalized, true
 }
 
 func validateDateHours(date string, hours float64, maxHours float64) error {
@@ -125,7 +275,7 @@ func validateDateHours(date string, hours float64, maxHours float64) error {
 	if _, err := domain.ValidateDate(date); err != nil {
 		return domain.ErrValidation
 	}
-	if hours < 0 || hours > maxHours {
+	if hours < 0 || hours > maxHours+dailyHoursTolerance {
 		return domain.ErrValidation
 	}
 	return nil
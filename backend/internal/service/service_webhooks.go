@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"plato/backend/internal/domain"
+	"plato/backend/internal/ports"
+)
+
+// webhookSecretBytes is the number of random bytes in a webhook secret.
+const webhookSecretBytes = 32
+
+// ListWebhooks returns the webhooks of the caller's organisation without
+// their secrets.
+func (s *Service) ListWebhooks(ctx context.Context, auth ports.AuthContext) ([]domain.Webhook, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.repo.ListWebhooks(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns one webhook of the caller's organisation without its
+// secret.
+func (s *Service) GetWebhook(ctx context.Context, auth ports.AuthContext, webhookID string) (domain.Webhook, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.Webhook{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	webhook, err := s.repo.GetWebhook(ctx, organisationID, webhookID)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// CreateWebhook validates and registers a webhook in the caller's
+// organisation. The returned webhook carries the generated signing secret,
+// which is not shown again.
+func (s *Service) CreateWebhook(ctx context.Context, auth ports.AuthContext, input domain.Webhook) (domain.Webhook, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return domain.Webhook{}, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return domain.Webhook{}, err
+	}
+	webhookURL := strings.TrimSpace(input.URL)
+	if err = s.validateWebhookURL(ctx, webhookURL); err != nil {
+		return domain.Webhook{}, err
+	}
+	events, err := normalizeWebhookEvents(input.Events)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	secret := make([]byte, webhookSecretBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return domain.Webhook{}, fmt.Errorf("generate webhook secret: %w", err)
+	}
+
+	created, err := s.repo.CreateWebhook(ctx, domain.Webhook{
+		OrganisationID: organisationID,
+		URL:            webhookURL,
+		Events:         events,
+		Secret:         "whsec_" + hex.EncodeToString(secret),
+	})
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	s.record(ctx, "webhook.created", map[string]string{"webhook_id": created.ID})
+	return created, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log from the caller's
+// organisation.
+func (s *Service) DeleteWebhook(ctx context.Context, auth ports.AuthContext, webhookID string) error {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return err
+	}
+	if err = s.requireWritableOrganisation(ctx, organisationID); err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteWebhook(ctx, organisationID, webhookID); err != nil {
+		return err
+	}
+
+	s.record(ctx, "webhook.deleted", map[string]string{"webhook_id": webhookID})
+	return nil
+}
+
+// ListWebhookDeliveries returns the logged delivery outcomes of one webhook
+// in the caller's organisation, oldest first.
+func (s *Service) ListWebhookDeliveries(ctx context.Context, auth ports.AuthContext, webhookID string) ([]domain.WebhookDelivery, error) {
+	if err := requireAnyRole(auth, domain.RoleOrgAdmin); err != nil {
+		return nil, err
+	}
+	organisationID, err := requiredOrganisationID(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListWebhookDeliveries(ctx, organisationID, webhookID)
+}
+
+// validateWebhookURL accepts absolute http and https URLs. Unless private
+// webhooks are allowed, the host must resolve to public addresses only.
+func (s *Service) validateWebhookURL(ctx context.Context, value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.Join(domain.ErrValidation, errors.New("webhook url must be an absolute http or https URL"))
+	}
+	if s.allowPrivateWebhooks {
+		return nil
+	}
+
+	host := parsed.Hostname()
+	addrs := make([]netip.Addr, 0, 1)
+	if addr, parseErr := netip.ParseAddr(host); parseErr == nil {
+		addrs = append(addrs, addr)
+	} else if addrs, err = s.lookupIP(ctx, host); err != nil || len(addrs) == 0 {
+		return errors.Join(domain.ErrValidation, fmt.Errorf("webhook host %q cannot be resolved", host))
+	}
+	for _, addr := range addrs {
+		if !domain.WebhookAddressAllowed(addr) {
+			return errors.Join(domain.ErrValidation, fmt.Errorf("webhook host %q resolves to the non-public address %s", host, addr))
+		}
+	}
+	return nil
+}
+
+// normalizeWebhookEvents trims and deduplicates event filters and rejects
+// names outside domain.WebhookEvents.
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	normalized := make([]string, 0, len(events))
+	for _, rawEvent := range events {
+		event := strings.TrimSpace(rawEvent)
+		if event != domain.WebhookEventAll && !slices.Contains(domain.WebhookEvents, event) {
+			return nil, errors.Join(domain.ErrValidation, fmt.Errorf("unknown webhook event %q", event))
+		}
+		if !slices.Contains(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, errors.Join(domain.ErrValidation, errors.New("webhook events must not be empty"))
+	}
+	return normalized, nil
+}
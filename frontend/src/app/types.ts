@@ -103,7 +103,7 @@ export type ReportTableRow = {
   detailCount: number
 }
 
-export type ReportGranularity = "day" | "week" | "month" | "year"
+export type ReportGranularity = "day" | "week" | "month" | "quarter" | "year"
 
 export type WorkingTimeUnit = "day" | "week" | "month" | "year"
 export type AvailabilityScope = "organisation" | "person" | "group"
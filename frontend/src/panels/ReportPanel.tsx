@@ -105,6 +105,7 @@ export function ReportPanel(props: ReportPanelProps) {
             <option value="day">day</option>
             <option value="week">week</option>
             <option value="month">month</option>
+            <option value="quarter">quarter</option>
             <option value="year">year</option>
           </select>
         </label>
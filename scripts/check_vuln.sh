@@ -15,7 +15,16 @@ GOVULN_INPUT="${PLATO_VULN_GOVULNCHECK_INPUT:-}"
 BINARY_GOVULN_INPUT="${PLATO_VULN_GOVULNCHECK_BINARY_INPUT:-}"
 NVD_SNAPSHOT="${PLATO_VULN_NVD_SNAPSHOT:-}"
 NVD_API_BASE_URL="${PLATO_VULN_NVD_API_BASE_URL:-}"
+NVD_REQUEST_INTERVAL="${PLATO_VULN_NVD_REQUEST_INTERVAL:-}"
+SEVERITY_CACHE="${PLATO_VULN_SEVERITY_CACHE:-}"
+SEVERITY_CACHE_TTL="${PLATO_VULN_SEVERITY_CACHE_TTL:-}"
+SEVERITY_CACHE_ERROR_TTL="${PLATO_VULN_SEVERITY_CACHE_ERROR_TTL:-}"
 GHSA_API_BASE_URL="${PLATO_VULN_GHSA_API_BASE_URL:-}"
+OSV_API_BASE_URL="${PLATO_VULN_OSV_API_BASE_URL:-}"
+OSV_LOOKUP="${PLATO_VULN_OSV_LOOKUP:-0}"
+STRICT_OVERRIDE_MATCH="${PLATO_VULN_STRICT_OVERRIDE_MATCH:-0}"
+OVERRIDE_EXPIRY_WARNING="${PLATO_VULN_OVERRIDE_EXPIRY_WARNING:-}"
+SEVERITY_BANDS="${PLATO_VULN_SEVERITY_BANDS:-}"
 GHSA_TOKEN_FILE="${PLATO_VULN_GHSA_TOKEN_FILE:-${GHSA_TOKEN_FILE:-}}"
 NVD_API_KEY_FILE="${PLATO_VULN_NVD_API_KEY_FILE:-${NVD_API_KEY_FILE:-}}"
 REPORT_DIR="${PLATO_VULN_REPORT_DIR:-}"
@@ -47,6 +56,10 @@ if [ -n "$NVD_SNAPSHOT" ]; then
   NVD_SNAPSHOT="$(to_abs_path "$NVD_SNAPSHOT")"
 fi
 
+if [ -n "$SEVERITY_CACHE" ]; then
+  SEVERITY_CACHE="$(to_abs_path "$SEVERITY_CACHE")"
+fi
+
 if [ -n "$GHSA_TOKEN_FILE" ]; then
   GHSA_TOKEN_FILE="$(to_abs_path "$GHSA_TOKEN_FILE")"
 fi
@@ -171,10 +184,46 @@ run_policy() {
     vulnpolicy_args+=( -nvd-api-base-url "$NVD_API_BASE_URL" )
   fi
 
+  if [ -n "$NVD_REQUEST_INTERVAL" ]; then
+    vulnpolicy_args+=( -nvd-request-interval "$NVD_REQUEST_INTERVAL" )
+  fi
+
+  if [ -n "$SEVERITY_CACHE" ]; then
+    vulnpolicy_args+=( -severity-cache "$SEVERITY_CACHE" )
+  fi
+
+  if [ -n "$SEVERITY_CACHE_TTL" ]; then
+    vulnpolicy_args+=( -severity-cache-ttl "$SEVERITY_CACHE_TTL" )
+  fi
+
+  if [ -n "$SEVERITY_CACHE_ERROR_TTL" ]; then
+    vulnpolicy_args+=( -severity-cache-error-ttl "$SEVERITY_CACHE_ERROR_TTL" )
+  fi
+
   if [ -n "$GHSA_API_BASE_URL" ]; then
     vulnpolicy_args+=( -ghsa-api-base-url "$GHSA_API_BASE_URL" )
   fi
 
+  if [ "$OSV_LOOKUP" = "1" ]; then
+    vulnpolicy_args+=( -osv-lookup )
+  fi
+
+  if [ "$STRICT_OVERRIDE_MATCH" = "1" ]; then
+    vulnpolicy_args+=( -strict-override-match )
+  fi
+
+  if [ -n "$OVERRIDE_EXPIRY_WARNING" ]; then
+    vulnpolicy_args+=( -override-expiry-warning "$OVERRIDE_EXPIRY_WARNING" )
+  fi
+
+  if [ -n "$OSV_API_BASE_URL" ]; then
+    vulnpolicy_args+=( -osv-api-base-url "$OSV_API_BASE_URL" )
+  fi
+
+  if [ -n "$SEVERITY_BANDS" ]; then
+    vulnpolicy_args+=( -severity-bands "$SEVERITY_BANDS" )
+  fi
+
   if [ -n "$NVD_SNAPSHOT" ]; then
     vulnpolicy_args+=( -severity-snapshot "$NVD_SNAPSHOT" )
   fi